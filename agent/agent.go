@@ -7,8 +7,8 @@ package agent
 import (
 	"context"
 	"fmt"
-	"math"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,6 +17,7 @@ import (
 
 	"github.com/choria-io/ccm/hiera"
 	"github.com/choria-io/ccm/internal/backoff"
+	"github.com/choria-io/ccm/internal/runloop"
 	"github.com/choria-io/ccm/manager"
 	"github.com/choria-io/ccm/model"
 )
@@ -26,6 +27,8 @@ const MinInterval = 30 * time.Second
 const DefaultMaxDataRefreshTries = 10
 const DefaultCacheDir = "/etc/choria/ccm/source"
 const MinFactUpdateInterval = 2 * time.Minute
+const DefaultTrustWindow = time.Hour
+const DefaultFactCacheTTL = 5 * time.Minute
 
 type Agent struct {
 	mgr               model.Manager
@@ -38,17 +41,19 @@ type Agent struct {
 	previousFactsTime time.Time
 	previousData      map[string]any
 	applyTrigger      chan *worker
+	applyLoop         *runloop.Loop
+	healthCheckLoop   *runloop.Loop
+	servicesActive    bool
 
 	ctx    context.Context
 	cancel context.CancelFunc
 
 	wwg sync.WaitGroup
 
-	mu sync.Mutex
+	mu     sync.Mutex
+	loopMu sync.Mutex
 }
 
-// TODO: watch kv and only re-fetch data if it changes, but resolve each time for facts updates
-
 // New creates a new agent
 func New(cfg *Config, opts ...Option) (*Agent, error) {
 	logger, err := cfg.NewLogger()
@@ -63,6 +68,67 @@ func New(cfg *Config, opts ...Option) (*Agent, error) {
 	if cfg.Registration != "" {
 		mgrOpts = append(mgrOpts, manager.WithRegistrationDestination(cfg.Registration))
 	}
+	if cfg.runDeadlineDuration > 0 {
+		mgrOpts = append(mgrOpts, manager.WithRunDeadline(cfg.runDeadlineDuration))
+	}
+	if cfg.Concurrency > 0 {
+		mgrOpts = append(mgrOpts, manager.WithConcurrency(cfg.Concurrency))
+	}
+	if cfg.PauseMarker != "" {
+		mgrOpts = append(mgrOpts, manager.WithPauseMarker(cfg.PauseMarker))
+	}
+	if len(cfg.ProtectedPaths) > 0 {
+		mgrOpts = append(mgrOpts, manager.WithProtectedPaths(cfg.ProtectedPaths...))
+	}
+	if cfg.DownloadCacheDir != "" {
+		mgrOpts = append(mgrOpts, manager.WithDownloadCache(cfg.DownloadCacheDir, cfg.downloadCacheSize))
+	}
+	if cfg.EventFile != "" {
+		mgrOpts = append(mgrOpts, manager.WithEventFile(cfg.EventFile))
+	}
+	if cfg.RefreshStateDir != "" {
+		mgrOpts = append(mgrOpts, manager.WithRefreshStateDirectory(cfg.RefreshStateDir))
+	}
+	if cfg.ConvergedStateDir != "" {
+		mgrOpts = append(mgrOpts, manager.WithConvergedStateDirectory(cfg.ConvergedStateDir, cfg.trustWindowDuration))
+	}
+	if cfg.FactCache != "" {
+		mgrOpts = append(mgrOpts, manager.WithFactCache(cfg.FactCache, cfg.factCacheTTLDuration))
+	}
+	if cfg.DebugDumpDir != "" {
+		mgrOpts = append(mgrOpts, manager.WithDebugDump(cfg.DebugDumpDir, cfg.debugDumpSize))
+	}
+	if cfg.ReportWebhook != "" {
+		mgrOpts = append(mgrOpts, manager.WithReportWebhook(cfg.ReportWebhook, cfg.ReportWebhookHeaders))
+	}
+	if cfg.EventSubjectPrefix != "" {
+		mgrOpts = append(mgrOpts, manager.WithEventSubjectPrefix(cfg.EventSubjectPrefix))
+	}
+	if cfg.ServiceRegistry != nil {
+		mgrOpts = append(mgrOpts, manager.WithServiceRegistry(cfg.ServiceRegistry.Bucket, cfg.ServiceRegistry.ttlDuration))
+	}
+	if cfg.jetStreamTimeoutDuration > 0 {
+		mgrOpts = append(mgrOpts, manager.WithJetStreamTimeout(cfg.jetStreamTimeoutDuration))
+	}
+	if cfg.JetStreamFailureThreshold != 0 || cfg.jetStreamFailureCooldownDuration > 0 {
+		threshold := cfg.JetStreamFailureThreshold
+		switch {
+		case threshold < 0:
+			threshold = 0
+		case threshold == 0:
+			threshold = manager.DefaultJetStreamFailureThreshold
+		}
+
+		cooldown := cfg.jetStreamFailureCooldownDuration
+		if cooldown == 0 {
+			cooldown = manager.DefaultJetStreamCooldown
+		}
+
+		mgrOpts = append(mgrOpts, manager.WithJetStreamCircuitBreaker(threshold, cooldown))
+	}
+	for provider, config := range cfg.ProviderConfig {
+		mgrOpts = append(mgrOpts, manager.WithProviderConfig(provider, config))
+	}
 
 	mgr, err := manager.NewManager(logger, logger, mgrOpts...)
 	if err != nil {
@@ -104,6 +170,7 @@ func (a *Agent) Run(ctx context.Context, wg *sync.WaitGroup) error {
 
 	if a.cfg.MonitorPort > 0 {
 		metrics.RegisterMetrics()
+		manager.RegisterMetrics(prometheus.DefaultRegisterer)
 		metrics.ListenAndServe(a.cfg.MonitorPort, a.log)
 	}
 
@@ -143,19 +210,21 @@ func (a *Agent) Run(ctx context.Context, wg *sync.WaitGroup) error {
 		go w.start(a.ctx, &a.wwg)
 	}
 
-	// we have a single ticker outside all the workers to simplify scheduling
+	// we have a single loop outside all the workers to simplify scheduling
 	// facts refreshes, conflicts between applies etc. The worker maintains object caches
-	// and triggers apply based on this ticker.
+	// and triggers apply based on this loop.
 	//
 	// workers do trigger applies though after object store updates, these will schedule
 	// in between the generally scheduled apply cycle
-	applyTicker := time.NewTicker(a.cfg.intervalDuration)
-
-	healthCheckTicker := time.NewTicker(math.MaxInt64)
-	if a.cfg.healthCheckIntervalDuration > 0 {
-		healthCheckTicker.Reset(a.cfg.healthCheckIntervalDuration)
+	err = a.startLoops()
+	if err != nil {
+		a.cancel()
+		a.wwg.Wait()
+		return err
 	}
 
+	a.startDataWatch()
+
 	for {
 		select {
 		case w := <-a.applyTrigger:
@@ -164,12 +233,6 @@ func (a *Agent) Run(ctx context.Context, wg *sync.WaitGroup) error {
 			w.apply(false, true) // we force it to run even if it was recently ran as this channel indicates a priority run is needed
 			a.mu.Unlock()
 
-		case <-healthCheckTicker.C:
-			a.runHealthChecks()
-
-		case <-applyTicker.C:
-			a.runManifests()
-
 		case <-a.ctx.Done():
 			a.cancel()
 			a.wwg.Wait()
@@ -181,14 +244,114 @@ func (a *Agent) Run(ctx context.Context, wg *sync.WaitGroup) error {
 	}
 }
 
+// startLoops starts the scheduled apply and health check loops, the loops skip runs while a previous run is still going
+func (a *Agent) startLoops() error {
+	opts := runloop.Options{
+		Interval: a.cfg.intervalDuration,
+		Splay:    a.cfg.splayDuration,
+		Jitter:   a.cfg.jitterDuration,
+	}
+
+	applyLoop, err := runloop.New("apply", func(context.Context) { a.runManifests() }, opts, a.log)
+	if err != nil {
+		return err
+	}
+
+	var healthCheckLoop *runloop.Loop
+	if a.cfg.healthCheckIntervalDuration > 0 {
+		opts.Interval = a.cfg.healthCheckIntervalDuration
+		healthCheckLoop, err = runloop.New("health_check", func(context.Context) { a.runHealthChecks() }, opts, a.log)
+		if err != nil {
+			return err
+		}
+	}
+
+	a.loopMu.Lock()
+	a.applyLoop = applyLoop
+	a.healthCheckLoop = healthCheckLoop
+	a.loopMu.Unlock()
+
+	for _, loop := range []*runloop.Loop{applyLoop, healthCheckLoop} {
+		if loop == nil {
+			continue
+		}
+
+		a.wwg.Add(1)
+		go func() {
+			defer a.wwg.Done()
+			loop.Run(a.ctx)
+		}()
+	}
+
+	return nil
+}
+
+// startDataWatch re-applies all manifests soon after kv:// data sources are updated rather than waiting for the
+// next scheduled run, the triggered applies are forced and the following scheduled run skips recently applied
+// manifests so a change is not applied twice
+func (a *Agent) startDataWatch() {
+	var sources []string
+	for _, source := range a.cfg.DataSources() {
+		if strings.HasPrefix(source, "kv://") {
+			sources = append(sources, source)
+		}
+	}
+
+	if len(sources) == 0 {
+		return
+	}
+
+	a.wwg.Add(1)
+	go func() {
+		defer a.wwg.Done()
+
+		backoff.Default.For(a.ctx, func(try int) error {
+			err := a.mgr.Watch(a.ctx, sources, manager.DefaultWatchDebounce, func() {
+				a.log.Warn("External data updated, triggering apply")
+				for _, w := range a.workers {
+					w.triggerApply()
+				}
+			})
+			if err != nil {
+				a.log.Error("Could not watch external data for updates", "try", try, "error", err)
+			}
+
+			return err
+		})
+	}()
+}
+
+// NextRuns reports when the next scheduled apply and health check runs are due, times are zero when not scheduled
+func (a *Agent) NextRuns() (apply time.Time, healthCheck time.Time) {
+	a.loopMu.Lock()
+	applyLoop := a.applyLoop
+	healthCheckLoop := a.healthCheckLoop
+	a.loopMu.Unlock()
+
+	if applyLoop != nil {
+		apply = applyLoop.NextRun()
+	}
+	if healthCheckLoop != nil {
+		healthCheck = healthCheckLoop.NextRun()
+	}
+
+	return apply, healthCheck
+}
+
 func (a *Agent) Stop() error {
 	a.mu.Lock()
-	defer a.mu.Unlock()
-
-	a.cancel()
+	cancel := a.cancel
+	a.mu.Unlock()
 
+	// scheduled runs in progress need the lock to complete so we wait without holding it
+	cancel()
 	a.wwg.Wait()
 
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.deregisterServices()
+
 	a.mgr.Close()
 	a.started = false
 	a.ctx = nil
@@ -197,6 +360,57 @@ func (a *Agent) Stop() error {
 	return nil
 }
 
+// keepServicesAlive registers the configured services after the first healthy run and renews them after later
+// healthy runs, services of a node with critical health checks expire once their TTL passed. Lock must be held.
+func (a *Agent) keepServicesAlive(healthy bool) {
+	if a.cfg.ServiceRegistry == nil {
+		return
+	}
+
+	if !healthy {
+		a.log.Warn("Not renewing registered services due to critical health checks")
+		return
+	}
+
+	if !a.servicesActive {
+		for _, service := range a.cfg.ServiceRegistry.Services {
+			// failed registrations are retried by later renewals
+			err := a.mgr.RegisterService(a.ctx, service)
+			if err != nil {
+				a.log.Error("Could not register service", "service", service.Service, "error", err)
+			}
+		}
+		a.servicesActive = true
+
+		return
+	}
+
+	err := a.mgr.RenewServices(a.ctx)
+	if err != nil {
+		a.log.Error("Could not renew registered services", "error", err)
+	}
+}
+
+// deregisterServices removes the registered services on shutdown rather than waiting for them to expire, lock
+// must be held
+func (a *Agent) deregisterServices() {
+	if a.cfg.ServiceRegistry == nil || !a.servicesActive {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, service := range a.cfg.ServiceRegistry.Services {
+		err := a.mgr.DeregisterService(ctx, service)
+		if err != nil {
+			a.log.Error("Could not deregister service", "service", service.Service, "error", err)
+		}
+	}
+
+	a.servicesActive = false
+}
+
 func (a *Agent) updateData() {
 	a.getFacts(a.ctx)
 	a.getData(a.ctx)
@@ -210,10 +424,16 @@ func (a *Agent) runManifests() {
 
 	a.updateData()
 
+	healthy := true
 	for _, w := range a.workers {
-		w.apply(false, false)
+		report := w.apply(false, false)
+		if report != nil && report.HealthCheckCriticalCount > 0 {
+			healthy = false
+		}
 	}
 
+	a.keepServicesAlive(healthy)
+
 	a.log.Info("Completed scheduled run")
 }
 
@@ -243,6 +463,8 @@ func (a *Agent) runHealthChecks() {
 
 	wg.Wait()
 
+	a.keepServicesAlive(len(triggers) == 0)
+
 	// avoid applies and checks interleaving
 	for _, t := range triggers {
 		t()
@@ -276,7 +498,7 @@ func (a *Agent) getFacts(ctx context.Context) {
 		}
 
 		log.Info("Refreshing facts")
-		f, err := a.mgr.SystemFacts(ctx)
+		f, err := a.mgr.GatherFacts(ctx)
 		if err != nil {
 			log.Error("Could not get system facts", "error", err)
 			metrics.AgentFactsResolveFailureCount.WithLabelValues().Inc()
@@ -295,10 +517,12 @@ func (a *Agent) getFacts(ctx context.Context) {
 }
 
 func (a *Agent) getData(ctx context.Context) {
-	if a.cfg.ExternalDataUrl == "" {
+	sources := a.cfg.DataSources()
+	if len(sources) == 0 {
 		a.log.Debug("Skipping external data resolution, no url configured")
 		return
 	}
+	sourcesLabel := strings.Join(sources, ",")
 
 	timer := prometheus.NewTimer(metrics.AgentDataResolveTime.WithLabelValues())
 	defer timer.ObserveDuration()
@@ -319,14 +543,14 @@ func (a *Agent) getData(ctx context.Context) {
 		f, err := a.mgr.Facts(ctx)
 		if err != nil {
 			log.Error("Could not get facts to resolve data", "error", err)
-			metrics.AgentDataResolveFailureCount.WithLabelValues(a.cfg.ExternalDataUrl).Inc()
+			metrics.AgentDataResolveFailureCount.WithLabelValues(sourcesLabel).Inc()
 			return err
 		}
 
-		result, err := hiera.ResolveUrl(ctx, a.cfg.ExternalDataUrl, a.mgr, f, hiera.DefaultOptions, a.log)
+		result, err := hiera.ResolveUrls(ctx, sources, a.mgr, f, hiera.DefaultOptions, a.log)
 		if err != nil {
 			log.Error("Could not resolve external data", "error", err)
-			metrics.AgentDataResolveFailureCount.WithLabelValues(a.cfg.ExternalDataUrl).Inc()
+			metrics.AgentDataResolveFailureCount.WithLabelValues(sourcesLabel).Inc()
 			return err
 		}
 
@@ -0,0 +1,69 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/choria-io/ccm/manager"
+	"github.com/choria-io/ccm/model/modelmocks"
+)
+
+// Tests are run via TestConfig in config_test.go
+
+var _ = Describe("getFacts", func() {
+	var (
+		mockLog *modelmocks.MockLogger
+		cache   string
+	)
+
+	BeforeEach(func() {
+		mockLog = modelmocks.NewMockLogger(gomock.NewController(GinkgoT()))
+		mockLog.EXPECT().With(gomock.Any()).AnyTimes().Return(mockLog)
+		mockLog.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLog.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+		cache = filepath.Join(GinkgoT().TempDir(), "facts.json")
+	})
+
+	It("Should reuse cached facts within the ttl", func(ctx context.Context) {
+		mgr, err := manager.NewManager(mockLog, mockLog, manager.WithFactCache(cache, time.Hour))
+		Expect(err).ToNot(HaveOccurred())
+
+		w := &worker{log: mockLog, mgr: mgr}
+		a := &Agent{mgr: mgr, log: mockLog, refreshTries: DefaultMaxDataRefreshTries, workers: map[string]*worker{"test": w}}
+
+		a.getFacts(ctx)
+		Expect(a.previousFacts).To(HaveKey("host"))
+		Expect(w.facts).To(HaveKey("host"))
+
+		// setting the gathered facts on the managers should not discard the cache
+		mgr.SetFacts(w.facts)
+
+		// replace the cached facts while keeping the time they were gathered, a refresh that gathers facts again
+		// would not find these
+		var cached map[string]any
+		jb, err := os.ReadFile(cache)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(json.Unmarshal(jb, &cached)).To(Succeed())
+		cached["facts"] = map[string]any{"cached": true}
+		jb, err = json.Marshal(cached)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.WriteFile(cache, jb, 0600)).To(Succeed())
+
+		a.previousFactsTime = time.Now().Add(-2 * MinFactUpdateInterval)
+		a.getFacts(ctx)
+		Expect(a.previousFacts).To(Equal(map[string]any{"cached": true}))
+		Expect(w.facts).To(Equal(map[string]any{"cached": true}))
+	})
+})
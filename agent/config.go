@@ -17,6 +17,7 @@ import (
 	"github.com/choria-io/ccm/manager"
 	"github.com/choria-io/ccm/model"
 	"github.com/choria-io/fisk"
+	"github.com/choria-io/fisk/units"
 )
 
 // Config holds the agent configuration
@@ -32,6 +33,31 @@ type Config struct {
 	HealthCheckInterval         string `yaml:"health_check_interval"`
 	healthCheckIntervalDuration time.Duration
 
+	// Splay is the maximum random delay before the first scheduled apply and health
+	// check run (e.g. "1m"), it avoids many agents running at the same time after a restart
+	Splay         string `yaml:"splay"`
+	splayDuration time.Duration
+
+	// Jitter is the maximum random delay added to every scheduled interval (e.g. "30s")
+	Jitter         string `yaml:"jitter"`
+	jitterDuration time.Duration
+
+	// RunDeadline is the maximum time a single manifest apply may take (e.g. "10m"), once passed the
+	// running resource is canceled and remaining resources are skipped. Unlimited when unset.
+	RunDeadline         string `yaml:"run_deadline"`
+	runDeadlineDuration time.Duration
+
+	// Concurrency is the maximum number of resources applied at the same time, resources still wait for
+	// the resources they require or subscribe to. Defaults to 1.
+	Concurrency int `yaml:"concurrency"`
+
+	// PauseMarker is a file path or kv://Bucket/Key that pauses management while present, scheduled and triggered
+	// applies then only run health checks until the marker is removed
+	PauseMarker string `yaml:"pause_marker"`
+
+	// ProtectedPaths are paths resources may never remove in addition to the default protected paths
+	ProtectedPaths []string `yaml:"protected_paths"`
+
 	// Manifests is the list of manifest sources to apply. Each source creates a
 	// separate worker that manages its own apply cycle. Sources can be file paths
 	// or object store URLs (obj://bucket/key).
@@ -41,10 +67,69 @@ type Config struct {
 	// resolution. The resolved data is merged into the manifest data context.
 	ExternalDataUrl string `yaml:"external_data_url"`
 
+	// ExternalDataUrls are additional hiera sources resolved after ExternalDataUrl,
+	// the results are deep merged in order with later sources overriding earlier ones
+	ExternalDataUrls []string `yaml:"external_data_urls"`
+
 	// CacheDir is the directory used to cache manifest sources fetched from
 	// remote locations. Defaults to DefaultCacheDir.
 	CacheDir string `yaml:"cache_dir"`
 
+	// DownloadCacheDir is an optional directory used to cache downloaded artifacts
+	// like archives, entries are shared between resources and keyed by URL and checksum
+	DownloadCacheDir string `yaml:"download_cache_dir"`
+
+	// DownloadCacheSize is the maximum size of the download cache (e.g. "1GiB"),
+	// least recently used entries are evicted when exceeded. Unlimited when unset.
+	DownloadCacheSize string `yaml:"download_cache_size"`
+	downloadCacheSize int64
+
+	// EventFile is an optional file every resource event is appended to as a JSON line for log shipping
+	EventFile string `yaml:"event_file"`
+
+	// RefreshStateDir is an optional directory where refreshes triggered by subscriptions are persisted
+	// until processed, refreshes interrupted by a failed or canceled run then happen on the next run
+	RefreshStateDir string `yaml:"refresh_state_dir"`
+
+	// ConvergedStateDir is an optional directory where resources found in their desired state are recorded, later
+	// runs skip checking resources whose properties and data are unchanged until the trust window passed
+	ConvergedStateDir string `yaml:"converged_state_dir"`
+
+	// TrustWindow is how long resources recorded in ConvergedStateDir are trusted (e.g. "6h"), defaults to DefaultTrustWindow
+	TrustWindow         string `yaml:"trust_window"`
+	trustWindowDuration time.Duration
+
+	// FactCache is an optional JSON file gathered facts are stored in, facts are reused from it until
+	// FactCacheTTL passed, also across restarts of the agent
+	FactCache string `yaml:"fact_cache"`
+
+	// FactCacheTTL is how long facts stored in FactCache are used (e.g. "10m"), defaults to DefaultFactCacheTTL
+	FactCacheTTL         string `yaml:"fact_cache_ttl"`
+	factCacheTTLDuration time.Duration
+
+	// DebugDumpDir is an optional directory where a debug dump holding the facts, data, resources and events of a
+	// run is written whenever a resource failed, sensitive values are redacted
+	DebugDumpDir string `yaml:"debug_dump_dir"`
+
+	// DebugDumpSize is the maximum size of a debug dump (e.g. "5MiB"), defaults to 10MiB
+	DebugDumpSize string `yaml:"debug_dump_size"`
+	debugDumpSize int64
+
+	// ReportWebhook is an optional URL a JSON report holding the summary and events of every run is posted to,
+	// delivery failures are logged and never fail the run
+	ReportWebhook string `yaml:"report_webhook"`
+
+	// ReportWebhookHeaders are headers added to every report webhook request, for example for authentication
+	ReportWebhookHeaders map[string]string `yaml:"report_webhook_headers"`
+
+	// EventSubjectPrefix is an optional NATS subject prefix every resource event and the summary of every run
+	// is published to using nats_context, publishing failures are logged and never fail the run
+	EventSubjectPrefix string `yaml:"event_subject_prefix"`
+
+	// ProviderConfig is configuration for providers keyed by provider name, for example the http archive provider
+	// timeout, properties set on resources take precedence
+	ProviderConfig map[string]map[string]any `yaml:"provider_config"`
+
 	// MonitorPort is the port to listen on for accessing Prometheus stats
 	MonitorPort int `yaml:"monitor_port"`
 
@@ -55,8 +140,40 @@ type Config struct {
 	// NatsContext is the NATS context to use for remote KV and Object store access
 	NatsContext string `yaml:"nats_context"`
 
+	// JetStreamTimeout is the maximum time a single JetStream call like a hiera KV lookup or object store
+	// manifest fetch may take (e.g. "10s"), defaults to manager.DefaultJetStreamTimeout
+	JetStreamTimeout         string `yaml:"jetstream_timeout"`
+	jetStreamTimeoutDuration time.Duration
+
+	// JetStreamFailureThreshold is the number of consecutive failed JetStream calls after which calls fail fast
+	// for JetStreamFailureCooldown, defaults to manager.DefaultJetStreamFailureThreshold, a negative value disables it
+	JetStreamFailureThreshold int `yaml:"jetstream_failure_threshold"`
+
+	// JetStreamFailureCooldown is how long JetStream calls fail fast once JetStreamFailureThreshold is reached
+	// (e.g. "1m"), defaults to manager.DefaultJetStreamCooldown
+	JetStreamFailureCooldown         string `yaml:"jetstream_failure_cooldown"`
+	jetStreamFailureCooldownDuration time.Duration
+
 	// Registration is the registration destination to support
 	Registration model.RegistrationDestination `yaml:"registration"`
+
+	// ServiceRegistry keeps services registered in a NATS KV bucket while the node passes its health checks
+	ServiceRegistry *ServiceRegistryConfig `yaml:"service_registry"`
+}
+
+// ServiceRegistryConfig configures the services the agent keeps registered, services are renewed after every
+// scheduled run without critical health checks and expire once the node stops renewing them
+type ServiceRegistryConfig struct {
+	// Bucket is the KV bucket services are registered in, it is created when it does not exist
+	Bucket string `yaml:"bucket"`
+
+	// TTL is how long services stay registered without being renewed (e.g. "3m"), it must be longer than the
+	// health check interval, or the interval when health checks are not scheduled
+	TTL         string `yaml:"ttl"`
+	ttlDuration time.Duration
+
+	// Services are the services to register
+	Services []*model.RegistrationEntry `yaml:"services"`
 }
 
 func ParseConfig(c []byte) (*Config, error) {
@@ -87,6 +204,80 @@ func ParseConfig(c []byte) (*Config, error) {
 		}
 	}
 
+	if cfg.Splay != "" {
+		cfg.splayDuration, err = fisk.ParseDuration(cfg.Splay)
+		if err != nil {
+			return nil, fmt.Errorf("invalid splay: %w", err)
+		}
+	}
+
+	if cfg.Jitter != "" {
+		cfg.jitterDuration, err = fisk.ParseDuration(cfg.Jitter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid jitter: %w", err)
+		}
+	}
+
+	if cfg.RunDeadline != "" {
+		cfg.runDeadlineDuration, err = fisk.ParseDuration(cfg.RunDeadline)
+		if err != nil {
+			return nil, fmt.Errorf("invalid run_deadline: %w", err)
+		}
+	}
+
+	cfg.trustWindowDuration = DefaultTrustWindow
+	if cfg.TrustWindow != "" {
+		cfg.trustWindowDuration, err = fisk.ParseDuration(cfg.TrustWindow)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trust_window: %w", err)
+		}
+	}
+
+	cfg.factCacheTTLDuration = DefaultFactCacheTTL
+	if cfg.FactCacheTTL != "" {
+		cfg.factCacheTTLDuration, err = fisk.ParseDuration(cfg.FactCacheTTL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fact_cache_ttl: %w", err)
+		}
+	}
+
+	if cfg.JetStreamTimeout != "" {
+		cfg.jetStreamTimeoutDuration, err = fisk.ParseDuration(cfg.JetStreamTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid jetstream_timeout: %w", err)
+		}
+	}
+
+	if cfg.JetStreamFailureCooldown != "" {
+		cfg.jetStreamFailureCooldownDuration, err = fisk.ParseDuration(cfg.JetStreamFailureCooldown)
+		if err != nil {
+			return nil, fmt.Errorf("invalid jetstream_failure_cooldown: %w", err)
+		}
+	}
+
+	if cfg.DownloadCacheSize != "" {
+		size, err := units.ParseBase2Bytes(cfg.DownloadCacheSize)
+		if err != nil {
+			return nil, fmt.Errorf("invalid download_cache_size: %w", err)
+		}
+		cfg.downloadCacheSize = int64(size)
+	}
+
+	if cfg.DebugDumpSize != "" {
+		size, err := units.ParseBase2Bytes(cfg.DebugDumpSize)
+		if err != nil {
+			return nil, fmt.Errorf("invalid debug_dump_size: %w", err)
+		}
+		cfg.debugDumpSize = int64(size)
+	}
+
+	if cfg.ServiceRegistry != nil && cfg.ServiceRegistry.TTL != "" {
+		cfg.ServiceRegistry.ttlDuration, err = fisk.ParseDuration(cfg.ServiceRegistry.TTL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid service_registry ttl: %w", err)
+		}
+	}
+
 	err = cfg.Validate()
 	if err != nil {
 		return nil, err
@@ -104,6 +295,45 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("interval must be at least %v", MinInterval)
 	}
 
+	if c.splayDuration < 0 {
+		return fmt.Errorf("splay cannot be negative")
+	}
+
+	if c.jitterDuration < 0 {
+		return fmt.Errorf("jitter cannot be negative")
+	}
+
+	if c.runDeadlineDuration < 0 {
+		return fmt.Errorf("run_deadline cannot be negative")
+	}
+
+	if c.Concurrency < 0 {
+		return fmt.Errorf("concurrency cannot be negative")
+	}
+
+	if c.jetStreamTimeoutDuration < 0 {
+		return fmt.Errorf("jetstream_timeout cannot be negative")
+	}
+
+	if c.jetStreamFailureCooldownDuration < 0 {
+		return fmt.Errorf("jetstream_failure_cooldown cannot be negative")
+	}
+
+	if c.ConvergedStateDir != "" && c.trustWindowDuration <= 0 {
+		return fmt.Errorf("trust_window must be positive")
+	}
+
+	if c.FactCache != "" && c.factCacheTTLDuration <= 0 {
+		return fmt.Errorf("fact_cache_ttl must be positive")
+	}
+
+	for _, path := range c.ProtectedPaths {
+		err := iu.ValidateCanonicalPath(fmt.Sprintf("protected_paths entry %q", path), path)
+		if err != nil {
+			return err
+		}
+	}
+
 	if c.CacheDir == "" {
 		return fmt.Errorf("cache_dir must be set")
 	}
@@ -119,9 +349,68 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("log_level must be one of: debug, info, warn, error")
 	}
 
+	if c.ServiceRegistry != nil {
+		err := c.validateServiceRegistry()
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// validateServiceRegistry ensures services are renewed more often than they expire
+func (c *Config) validateServiceRegistry() error {
+	reg := c.ServiceRegistry
+
+	if reg.Bucket == "" {
+		return fmt.Errorf("service_registry bucket must be set")
+	}
+
+	if len(reg.Services) == 0 {
+		return fmt.Errorf("service_registry services must be set")
+	}
+
+	renewInterval := c.intervalDuration
+	if c.healthCheckIntervalDuration > 0 {
+		renewInterval = c.healthCheckIntervalDuration
+	}
+
+	if reg.ttlDuration <= renewInterval {
+		return fmt.Errorf("service_registry ttl must be longer than %v", renewInterval)
+	}
+
+	for i, service := range reg.Services {
+		if service == nil {
+			return fmt.Errorf("service_registry service %d is empty", i)
+		}
+
+		err := service.Validate()
+		if err != nil {
+			return fmt.Errorf("service_registry service %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// DataSources is the ordered list of external hiera data sources
+func (c *Config) DataSources() []string {
+	var sources []string
+
+	if c.ExternalDataUrl != "" {
+		sources = append(sources, c.ExternalDataUrl)
+	}
+
+	for _, source := range c.ExternalDataUrls {
+		if source != "" {
+			sources = append(sources, source)
+		}
+	}
+
+	return sources
+}
+
 func (c *Config) NewLogger() (model.Logger, error) {
 	var level slog.Level
 
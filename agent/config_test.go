@@ -76,6 +76,107 @@ nats_context: custom-context
 			Expect(cfg.NatsContext).To(Equal("custom-context"))
 		})
 
+		It("Should combine external data sources in order", func() {
+			cfg, err := ParseConfig([]byte("interval: 5m\nexternal_data_url: kv://data/common\nexternal_data_urls:\n  - https://example.net/site.yaml\n  - /etc/ccm/node.yaml\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.DataSources()).To(Equal([]string{"kv://data/common", "https://example.net/site.yaml", "/etc/ccm/node.yaml"}))
+
+			cfg, err = ParseConfig([]byte("interval: 5m\nexternal_data_urls:\n  - /etc/ccm/node.yaml\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.DataSources()).To(Equal([]string{"/etc/ccm/node.yaml"}))
+
+			cfg, err = ParseConfig([]byte("interval: 5m\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.DataSources()).To(BeEmpty())
+		})
+
+		It("Should parse splay and jitter", func() {
+			cfg, err := ParseConfig([]byte("interval: 5m\nsplay: 2m\njitter: 30s\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.splayDuration).To(Equal(2 * time.Minute))
+			Expect(cfg.jitterDuration).To(Equal(30 * time.Second))
+
+			_, err = ParseConfig([]byte("interval: 5m\nsplay: soon\n"))
+			Expect(err).To(MatchError(ContainSubstring("invalid splay")))
+
+			_, err = ParseConfig([]byte("interval: 5m\njitter: soon\n"))
+			Expect(err).To(MatchError(ContainSubstring("invalid jitter")))
+		})
+
+		It("Should parse download cache settings", func() {
+			cfg, err := ParseConfig([]byte("interval: 5m\ndownload_cache_dir: /var/cache/ccm\ndownload_cache_size: 1GiB\n"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.DownloadCacheDir).To(Equal("/var/cache/ccm"))
+			Expect(cfg.downloadCacheSize).To(Equal(int64(1024 * 1024 * 1024)))
+
+			_, err = ParseConfig([]byte("interval: 5m\ndownload_cache_size: lots\n"))
+			Expect(err).To(MatchError(ContainSubstring("invalid download_cache_size")))
+		})
+
+		It("Should parse the fact cache settings", func() {
+			cfg, err := ParseConfig([]byte("interval: 5m\n"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.factCacheTTLDuration).To(Equal(DefaultFactCacheTTL))
+
+			cfg, err = ParseConfig([]byte("interval: 5m\nfact_cache: /var/lib/ccm/facts.json\nfact_cache_ttl: 10m\n"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.FactCache).To(Equal("/var/lib/ccm/facts.json"))
+			Expect(cfg.factCacheTTLDuration).To(Equal(10 * time.Minute))
+
+			_, err = ParseConfig([]byte("interval: 5m\nfact_cache_ttl: soon\n"))
+			Expect(err).To(MatchError(ContainSubstring("invalid fact_cache_ttl")))
+
+			_, err = ParseConfig([]byte("interval: 5m\nfact_cache: /var/lib/ccm/facts.json\nfact_cache_ttl: 0s\n"))
+			Expect(err).To(MatchError(ContainSubstring("fact_cache_ttl must be positive")))
+		})
+
+		It("Should validate the protected paths", func() {
+			cfg, err := ParseConfig([]byte("interval: 5m\nprotected_paths:\n  - /srv/data\n"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.ProtectedPaths).To(Equal([]string{"/srv/data"}))
+
+			_, err = ParseConfig([]byte("interval: 5m\nprotected_paths:\n  - srv/data\n"))
+			Expect(err).To(MatchError(`protected_paths entry "srv/data" must be an absolute path`))
+
+			_, err = ParseConfig([]byte("interval: 5m\nprotected_paths:\n  - /etc//shadow\n"))
+			Expect(err).To(MatchError(`protected_paths entry "/etc//shadow" must be a canonical path`))
+		})
+
+		It("Should parse the run deadline", func() {
+			cfg, err := ParseConfig([]byte("interval: 5m\nrun_deadline: 10m\n"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.runDeadlineDuration).To(Equal(10 * time.Minute))
+
+			_, err = ParseConfig([]byte("interval: 5m\nrun_deadline: soon\n"))
+			Expect(err).To(MatchError(ContainSubstring("invalid run_deadline")))
+
+			_, err = ParseConfig([]byte("interval: 5m\nrun_deadline: -1m\n"))
+			Expect(err).To(MatchError(ContainSubstring("run_deadline cannot be negative")))
+		})
+
+		It("Should parse the concurrency", func() {
+			cfg, err := ParseConfig([]byte("interval: 5m\nconcurrency: 4\n"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.Concurrency).To(Equal(4))
+
+			_, err = ParseConfig([]byte("interval: 5m\nconcurrency: -1\n"))
+			Expect(err).To(MatchError(ContainSubstring("concurrency cannot be negative")))
+		})
+
+		It("Should parse the JetStream settings", func() {
+			cfg, err := ParseConfig([]byte("interval: 5m\njetstream_timeout: 10s\njetstream_failure_threshold: 3\njetstream_failure_cooldown: 2m\n"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.jetStreamTimeoutDuration).To(Equal(10 * time.Second))
+			Expect(cfg.JetStreamFailureThreshold).To(Equal(3))
+			Expect(cfg.jetStreamFailureCooldownDuration).To(Equal(2 * time.Minute))
+
+			_, err = ParseConfig([]byte("interval: 5m\njetstream_timeout: soon\n"))
+			Expect(err).To(MatchError(ContainSubstring("invalid jetstream_timeout")))
+
+			_, err = ParseConfig([]byte("interval: 5m\njetstream_failure_cooldown: -1m\n"))
+			Expect(err).To(MatchError(ContainSubstring("jetstream_failure_cooldown cannot be negative")))
+		})
+
 		It("Should return error for invalid YAML", func() {
 			yamlData := `invalid: yaml: data:`
 
@@ -143,6 +244,51 @@ nats_context: custom-context
 			Expect(err.Error()).To(ContainSubstring("invalid registration destination"))
 			Expect(cfg).To(BeNil())
 		})
+
+		It("Should parse the service registry", func() {
+			yamlData := `
+health_check_interval: 1m
+service_registry:
+  bucket: SERVICES
+  ttl: 3m
+  services:
+    - cluster: prod
+      service: web
+      protocol: http
+      address: 192.168.1.1
+      port: 8080
+      priority: 1
+`
+
+			cfg, err := ParseConfig([]byte(yamlData))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.ServiceRegistry.Bucket).To(Equal("SERVICES"))
+			Expect(cfg.ServiceRegistry.ttlDuration).To(Equal(3 * time.Minute))
+			Expect(cfg.ServiceRegistry.Services).To(HaveLen(1))
+			Expect(cfg.ServiceRegistry.Services[0].Service).To(Equal("web"))
+		})
+
+		It("Should validate the service registry", func() {
+			service := "\n  services:\n    - {cluster: prod, service: web, protocol: http, address: 192.168.1.1, port: 8080, priority: 1}\n"
+
+			_, err := ParseConfig([]byte("service_registry:\n  ttl: 10m" + service))
+			Expect(err).To(MatchError("service_registry bucket must be set"))
+
+			_, err = ParseConfig([]byte("service_registry:\n  bucket: SERVICES\n  ttl: 10m\n"))
+			Expect(err).To(MatchError("service_registry services must be set"))
+
+			_, err = ParseConfig([]byte("service_registry:\n  bucket: SERVICES\n  ttl: soon" + service))
+			Expect(err).To(MatchError(ContainSubstring("invalid service_registry ttl")))
+
+			_, err = ParseConfig([]byte("service_registry:\n  bucket: SERVICES\n  ttl: 5m" + service))
+			Expect(err).To(MatchError("service_registry ttl must be longer than 5m0s"))
+
+			_, err = ParseConfig([]byte("health_check_interval: 1m\nservice_registry:\n  bucket: SERVICES\n  ttl: 1m" + service))
+			Expect(err).To(MatchError("service_registry ttl must be longer than 1m0s"))
+
+			_, err = ParseConfig([]byte("service_registry:\n  bucket: SERVICES\n  ttl: 10m\n  services:\n    - {cluster: prod, service: web, protocol: http, address: invalid, priority: 1}\n"))
+			Expect(err).To(MatchError(model.ErrRegistrationInvalid))
+		})
 	})
 
 	Describe("Validate", func() {
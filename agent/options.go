@@ -43,6 +43,14 @@ func WithExternalDataUrl(path string) Option {
 	}
 }
 
+// WithExternalDataUrls sets additional hiera sources merged over the external data url in order
+func WithExternalDataUrls(urls ...string) Option {
+	return func(a *Agent) error {
+		a.cfg.ExternalDataUrls = urls
+		return nil
+	}
+}
+
 func WithHealthCheckOnlyInterval(i time.Duration) Option {
 	return func(a *Agent) error {
 		a.cfg.healthCheckIntervalDuration = i
@@ -168,6 +168,16 @@ func (w *worker) apply(hcOnly bool, force bool) *model.SessionSummary {
 		return nil
 	}
 
+	if !hcOnly {
+		w.updateDriftMetrics(report)
+
+		paused := 0.0
+		if report.Paused {
+			paused = 1
+		}
+		metrics.AgentManagementPaused.WithLabelValues(w.source).Set(paused)
+	}
+
 	switch {
 	case report.ChangedResources > 0:
 		log.Warn(report.String())
@@ -180,6 +190,21 @@ func (w *worker) apply(hcOnly bool, force bool) *model.SessionSummary {
 	return report
 }
 
+// updateDriftMetrics publishes the drift ratios of a run, the mode label distinguishes
+// the would drift of noop runs from the corrective drift of enforcing runs
+func (w *worker) updateDriftMetrics(report *model.SessionSummary) {
+	mode := "enforce"
+	if report.Noop {
+		mode = "noop"
+	}
+
+	metrics.ManifestDriftRatio.WithLabelValues(w.source, mode).Set(report.Drift())
+
+	for typeName, drift := range report.DriftByType {
+		metrics.ResourceTypeDriftRatio.WithLabelValues(w.source, typeName, mode).Set(drift.Ratio)
+	}
+}
+
 func (w *worker) setFacts(facts map[string]any) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
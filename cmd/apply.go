@@ -8,26 +8,55 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/goccy/go-yaml"
 
 	iu "github.com/choria-io/ccm/internal/util"
+	"github.com/choria-io/ccm/manager"
 	"github.com/choria-io/ccm/resources/apply"
 	"github.com/choria-io/fisk"
+	"github.com/choria-io/fisk/units"
 )
 
 type applyCommand struct {
 	manifest           string
 	renderOnly         bool
+	graph              string
+	export             string
 	report             bool
+	reportFormat       string
 	hieraFile          string
 	readEnv            bool
 	noop               bool
 	monitorOnly        bool
+	detailedExitCode   bool
+	skipUnmanageable   bool
+	deadline           time.Duration
+	concurrency        int
+	pauseMarker        string
+	protectedPaths     []string
+	downloadCache      string
+	downloadCacheSize  units.Base2Bytes
+	eventFile          string
+	recordFile         string
+	replayFile         string
+	refreshState       string
+	convergedState     string
+	trustWindow        time.Duration
+	debugDump          string
+	debugDumpSize      units.Base2Bytes
+	reportWebhook      string
+	reportHeaders      map[string]string
+	eventPrefix        string
+	providerConfig     string
 	natsContext        string
 	registrationStream string
 	facts              map[string]string
 	factsFile          string
+	externalFacts      string
+	factCache          string
+	factCacheTTL       time.Duration
 }
 
 func registerApplyCommand(ccm *fisk.Application) {
@@ -48,12 +77,38 @@ server urls and authentication parameters.
 	applyCmd.Arg("manifest", "Path to manifest to apply").PlaceHolder("URL").Required().StringVar(&cmd.manifest)
 	applyCmd.Flag("fact", "Set additional facts to merge with the system facts").StringMapVar(&cmd.facts)
 	applyCmd.Flag("facts", "File holding additional facts to merge with the system facts").PlaceHolder("FILE").ExistingFileVar(&cmd.factsFile)
+	applyCmd.Flag("external-facts", "Directory holding facts files and scripts whose output are facts").Envar("CCM_EXTERNAL_FACTS").PlaceHolder("DIR").ExistingDirVar(&cmd.externalFacts)
+	applyCmd.Flag("fact-cache", "File to cache gathered facts in, later runs reuse the facts until --fact-cache-ttl passed").Envar("CCM_FACT_CACHE").PlaceHolder("FILE").StringVar(&cmd.factCache)
+	applyCmd.Flag("fact-cache-ttl", "How long cached facts are used before gathering facts again").Default("5m").DurationVar(&cmd.factCacheTTL)
 	applyCmd.Flag("hiera", "Hiera data file to use as overriding data source").Envar("CCM_HIERA_DATA").StringVar(&cmd.hieraFile)
 	applyCmd.Flag("read-env", "Read extra variables from .env file").Default("true").BoolVar(&cmd.readEnv)
 	applyCmd.Flag("noop", "Do not make changes, only show what would be done").UnNegatableBoolVar(&cmd.noop)
 	applyCmd.Flag("monitor-only", "Only perform monitoring").UnNegatableBoolVar(&cmd.monitorOnly)
+	applyCmd.Flag("skip-unmanageable", "Skip resources that no provider can manage on this node rather than failing").UnNegatableBoolVar(&cmd.skipUnmanageable)
+	applyCmd.Flag("deadline", "Maximum time the entire manifest apply may take, remaining resources are skipped once passed").PlaceHolder("DURATION").DurationVar(&cmd.deadline)
+	applyCmd.Flag("concurrency", "Maximum number of resources to apply at the same time").Default("1").IntVar(&cmd.concurrency)
+	applyCmd.Flag("pause-marker", "Only run health checks while this file or kv://Bucket/Key exists").Envar("CCM_PAUSE_MARKER").PlaceHolder("MARKER").StringVar(&cmd.pauseMarker)
+	applyCmd.Flag("protect", "Additional paths that resources may never remove").PlaceHolder("PATH").StringsVar(&cmd.protectedPaths)
+	applyCmd.Flag("download-cache", "Directory to cache downloaded artifacts in").Envar("CCM_DOWNLOAD_CACHE").PlaceHolder("DIR").StringVar(&cmd.downloadCache)
+	applyCmd.Flag("download-cache-size", "Maximum size of the download cache").PlaceHolder("SIZE").BytesVar(&cmd.downloadCacheSize)
+	applyCmd.Flag("events", "Append every resource event to FILE as JSON lines, - for STDOUT").PlaceHolder("FILE").StringVar(&cmd.eventFile)
+	applyCmd.Flag("record", "Record every command providers run and its output to FILE for later replay").PlaceHolder("FILE").StringVar(&cmd.recordFile)
+	applyCmd.Flag("replay", "Replay commands recorded using --record instead of running them").PlaceHolder("FILE").ExistingFileVar(&cmd.replayFile)
+	applyCmd.Flag("refresh-state", "Directory to persist pending refreshes in so interrupted refreshes happen on the next run").Envar("CCM_REFRESH_STATE").PlaceHolder("DIR").StringVar(&cmd.refreshState)
+	applyCmd.Flag("converged-state", "Directory to record converged resources in, resources with unchanged inputs are not checked again until the trust window passed").Envar("CCM_CONVERGED_STATE").PlaceHolder("DIR").StringVar(&cmd.convergedState)
+	applyCmd.Flag("trust-window", "How long resources recorded in the converged state are trusted before being checked again").Default("1h").DurationVar(&cmd.trustWindow)
+	applyCmd.Flag("debug-dump", "Directory to write a debug dump of facts, data, resources and events to when the apply fails").Envar("CCM_DEBUG_DUMP").PlaceHolder("DIR").StringVar(&cmd.debugDump)
+	applyCmd.Flag("debug-dump-size", "Maximum size of a debug dump").PlaceHolder("SIZE").BytesVar(&cmd.debugDumpSize)
+	applyCmd.Flag("report-webhook", "URL to POST a JSON report of the summary and events to once the apply completed").Envar("CCM_REPORT_WEBHOOK").PlaceHolder("URL").StringVar(&cmd.reportWebhook)
+	applyCmd.Flag("report-webhook-header", "Header to add to report webhook requests").PlaceHolder("HEADER=VALUE").StringMapVar(&cmd.reportHeaders)
+	applyCmd.Flag("event-subject-prefix", "Publish every resource event and the summary to NATS subjects below PREFIX").Envar("CCM_EVENT_SUBJECT_PREFIX").PlaceHolder("PREFIX").StringVar(&cmd.eventPrefix)
+	applyCmd.Flag("provider-config", "YAML file holding configuration for providers keyed by provider name").PlaceHolder("FILE").ExistingFileVar(&cmd.providerConfig)
 	applyCmd.Flag("render", "Do not apply, only render the resolved manifest").UnNegatableBoolVar(&cmd.renderOnly)
+	applyCmd.Flag("export", "Do not apply, only show the resources that would be managed with their resolved properties").PlaceHolder("FORMAT").EnumVar(&cmd.export, "yaml", "json")
+	applyCmd.Flag("graph", "Do not apply, only show the resource dependency graph").PlaceHolder("FORMAT").EnumVar(&cmd.graph, "json", "dot")
 	applyCmd.Flag("report", "Generate a report").Default("true").BoolVar(&cmd.report)
+	applyCmd.Flag("report-format", "The format to produce the report in").Default("text").EnumVar(&cmd.reportFormat, "text", "json")
+	applyCmd.Flag("detailed-exitcode", "Exit with 0 when no changes were made, 2 when changes were made and 1 on failures").UnNegatableBoolVar(&cmd.detailedExitCode)
 	applyCmd.Flag("context", "NATS Context to connect with").Envar("NATS_CONTEXT").Default("CCM").StringVar(&cmd.natsContext)
 	applyCmd.Flag("registration", "The NATS Stream holding registration data").Default("REGISTRATION").Short('R').StringVar(&cmd.registrationStream)
 }
@@ -80,7 +135,77 @@ func (c *applyCommand) applyAction(_ *fisk.ParseContext) error {
 		finalFacts = iu.DeepMergeMap(finalFacts, facts)
 	}
 
-	mgr, userLogger, err := newManager("", "", c.natsContext, c.readEnv, c.noop, c.registrationStream, finalFacts)
+	var mgrOpts []manager.Option
+	if c.externalFacts != "" {
+		mgrOpts = append(mgrOpts, manager.WithExternalFactsDir(c.externalFacts))
+	}
+	if c.factCache != "" {
+		mgrOpts = append(mgrOpts, manager.WithFactCache(c.factCache, c.factCacheTTL))
+	}
+	if c.skipUnmanageable {
+		mgrOpts = append(mgrOpts, manager.WithSkipIfUnmanageable())
+	}
+	if c.deadline > 0 {
+		mgrOpts = append(mgrOpts, manager.WithRunDeadline(c.deadline))
+	}
+	if c.concurrency != 1 {
+		mgrOpts = append(mgrOpts, manager.WithConcurrency(c.concurrency))
+	}
+	if c.pauseMarker != "" {
+		mgrOpts = append(mgrOpts, manager.WithPauseMarker(c.pauseMarker))
+	}
+	if len(c.protectedPaths) > 0 {
+		mgrOpts = append(mgrOpts, manager.WithProtectedPaths(c.protectedPaths...))
+	}
+	if c.downloadCache != "" {
+		mgrOpts = append(mgrOpts, manager.WithDownloadCache(c.downloadCache, int64(c.downloadCacheSize)))
+	}
+	if c.eventFile != "" {
+		mgrOpts = append(mgrOpts, manager.WithEventFile(c.eventFile))
+	}
+	if c.recordFile != "" && c.replayFile != "" {
+		return fmt.Errorf("--record and --replay cannot be used together")
+	}
+	if c.recordFile != "" {
+		mgrOpts = append(mgrOpts, manager.WithCommandRecording(c.recordFile))
+	}
+	if c.replayFile != "" {
+		mgrOpts = append(mgrOpts, manager.WithCommandReplay(c.replayFile))
+	}
+	if c.refreshState != "" {
+		mgrOpts = append(mgrOpts, manager.WithRefreshStateDirectory(c.refreshState))
+	}
+	if c.convergedState != "" {
+		mgrOpts = append(mgrOpts, manager.WithConvergedStateDirectory(c.convergedState, c.trustWindow))
+	}
+	if c.debugDump != "" {
+		mgrOpts = append(mgrOpts, manager.WithDebugDump(c.debugDump, int64(c.debugDumpSize)))
+	}
+	if c.reportWebhook != "" {
+		mgrOpts = append(mgrOpts, manager.WithReportWebhook(c.reportWebhook, c.reportHeaders))
+	}
+
+	if c.eventPrefix != "" {
+		mgrOpts = append(mgrOpts, manager.WithEventSubjectPrefix(c.eventPrefix))
+	}
+	if c.providerConfig != "" {
+		pc, err := os.ReadFile(c.providerConfig)
+		if err != nil {
+			return err
+		}
+
+		var configs map[string]map[string]any
+		err = yaml.Unmarshal(pc, &configs)
+		if err != nil {
+			return fmt.Errorf("invalid provider configuration: %w", err)
+		}
+
+		for provider, config := range configs {
+			mgrOpts = append(mgrOpts, manager.WithProviderConfig(provider, config))
+		}
+	}
+
+	mgr, userLogger, err := newManager("", "", c.natsContext, c.readEnv, c.noop, c.registrationStream, finalFacts, mgrOpts...)
 	if err != nil {
 		return err
 	}
@@ -110,6 +235,50 @@ func (c *applyCommand) applyAction(_ *fisk.ParseContext) error {
 		return nil
 	}
 
+	if c.export != "" {
+		resources, err := mgr.EffectiveResources(ctx, manifest)
+		if err != nil {
+			return err
+		}
+
+		switch c.export {
+		case "json":
+			j, err := json.MarshalIndent(resources, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(j))
+		default:
+			y, err := yaml.Marshal(resources)
+			if err != nil {
+				return err
+			}
+			fmt.Print(string(y))
+		}
+
+		return nil
+	}
+
+	if c.graph != "" {
+		graph, err := mgr.ResourceGraph(ctx, manifest)
+		if err != nil {
+			return err
+		}
+
+		switch c.graph {
+		case "dot":
+			fmt.Print(graph.DOT())
+		default:
+			j, err := json.MarshalIndent(graph, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(j))
+		}
+
+		return nil
+	}
+
 	if manifest.PreMessage() != "" {
 		fmt.Println()
 		fmt.Println(manifest.PreMessage())
@@ -129,10 +298,22 @@ func (c *applyCommand) applyAction(_ *fisk.ParseContext) error {
 		fmt.Println(manifest.PostMessage())
 	}
 
-	if c.report {
+	switch {
+	case c.report && c.reportFormat == "json":
+		j, err := json.MarshalIndent(summary, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(j))
+
+	case c.report:
 		fmt.Println()
 		summary.RenderText(os.Stdout)
 	}
 
+	if c.detailedExitCode {
+		exitCode = summary.ResultCode()
+	}
+
 	return nil
 }
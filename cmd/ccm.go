@@ -28,6 +28,10 @@ var (
 	debug   bool
 	info    bool
 	version = "development"
+
+	// exitCode is the code the process exits with once the command completed, commands set it rather than
+	// calling os.Exit() so their deferred cleanups run
+	exitCode int
 )
 
 func main() {
@@ -44,8 +48,10 @@ func main() {
 	registerFactsCommand(app)
 	registerHieraCommand(app)
 	registerRegistrationCommand(app)
+	registerSchemaCommand(app)
 	registerSessionCommand(app)
 	registerStatusCommand(app)
+	registerValidateCommand(app)
 
 	ctx, _ = signal.NotifyContext(context.Background(), os.Interrupt)
 
@@ -55,6 +61,8 @@ func main() {
 	}
 
 	app.MustParseWithUsage(os.Args[1:])
+
+	os.Exit(exitCode)
 }
 
 func extendCli(app *fisk.Application) error {
@@ -25,13 +25,16 @@ type ensureCommand struct {
 	healthCheckTries   int
 	healthCheckSleep   time.Duration
 
-	conditionIf     string
-	conditionUnless string
+	conditionIf      string
+	conditionUnless  string
+	skipUnmanageable bool
+	applyTimeout     string
 
-	alias    string
-	noop     bool
-	provider string
-	requires []string
+	alias       string
+	noop        bool
+	monitorOnly bool
+	provider    string
+	requires    []string
 
 	out model.Logger
 }
@@ -41,17 +44,26 @@ func registerEnsureCommand(ccm *fisk.Application) {
 
 	ens := ccm.Command("ensure", "Manage individual resources")
 	ens.Flag("noop", "Do not make any changes to the system").UnNegatableBoolVar(&cmd.noop)
+	ens.Flag("monitor-only", "Only report the current state and run health checks, never make changes").UnNegatableBoolVar(&cmd.monitorOnly)
 	ens.Flag("session", "Session store to use").Envar("CCM_SESSION_STORE").PlaceHolder("DIR").IsSetByUser(&cmd.sessionIsSet).StringVar(&cmd.session)
 	ens.Flag("hiera", "Hiera data file to use as data source").Default(".hiera").Envar("CCM_HIERA_DATA").StringVar(&cmd.hieraFile)
 	ens.Flag("read-env", "Read extra variables from .env file").Default("true").BoolVar(&cmd.readEnv)
 	ens.Flag("context", "NATS Context to connect with").Envar("NATS_CONTEXT").Default("CCM").StringVar(&cmd.natsContext)
 
 	registerEnsureArchiveCommand(ens, cmd)
+	registerEnsureCronCommand(ens, cmd)
 	registerEnsureExecCommand(ens, cmd)
 	registerEnsureFileCommand(ens, cmd)
+	registerEnsureGitCommand(ens, cmd)
+	registerEnsureGroupCommand(ens, cmd)
+	registerEnsureHostCommand(ens, cmd)
+	registerEnsureJsonEditCommand(ens, cmd)
 	registerEnsurePackageCommand(ens, cmd)
 	registerEnsureScaffoldCommand(ens, cmd)
 	registerEnsureServiceCommand(ens, cmd)
+	registerEnsureSSHKeyCommand(ens, cmd)
+	registerEnsureSudoersCommand(ens, cmd)
+	registerEnsureYumRepoCommand(ens, cmd)
 	registerEnsureApiCommand(ens, cmd)
 }
 
@@ -63,6 +75,8 @@ func (cmd *ensureCommand) addCommonFlags(app *fisk.CmdClause) {
 	app.Flag("check-sleep", "Time to sleep between health check tries").Default("1s").DurationVar(&cmd.healthCheckSleep)
 	app.Flag("if", "Manage resource if it matches this condition").PlaceHolder("CONDITION").StringVar(&cmd.conditionIf)
 	app.Flag("unless", "Manage resource unless it matches this condition").PlaceHolder("CONDITION").StringVar(&cmd.conditionUnless)
+	app.Flag("skip-unmanageable", "Skip the resource rather than failing when no provider can manage it").UnNegatableBoolVar(&cmd.skipUnmanageable)
+	app.Flag("apply-timeout", "Maximum time applying the resource may take").PlaceHolder("DURATION").StringVar(&cmd.applyTimeout)
 	app.Flag("provider", "Resource provider").PlaceHolder("NAME").StringVar(&cmd.provider)
 	app.Flag("require", "Require success on an earlier resource").PlaceHolder("type#name").StringsVar(&cmd.requires)
 }
@@ -72,6 +86,10 @@ func (cmd *ensureCommand) manager() (model.Manager, error) {
 		return nil, fmt.Errorf("session store should not be empty")
 	}
 
+	if cmd.noop && cmd.monitorOnly {
+		return nil, fmt.Errorf("cannot set monitor only and noop mode at the same time")
+	}
+
 	if cmd.session == "" && len(cmd.requires) > 0 {
 		return nil, fmt.Errorf("session store should be set when using requires")
 	}
@@ -100,13 +118,14 @@ func (cmd *ensureCommand) healthCheckProperties() []model.CommonHealthCheck {
 }
 
 func (cmd *ensureCommand) control() *model.CommonResourceControl {
-	if cmd.conditionIf == "" && cmd.conditionUnless == "" {
+	if cmd.conditionIf == "" && cmd.conditionUnless == "" && !cmd.skipUnmanageable {
 		return nil
 	}
 
 	return &model.CommonResourceControl{
-		ManageIf:     cmd.conditionIf,
-		ManageUnless: cmd.conditionUnless,
+		ManageIf:           cmd.conditionIf,
+		ManageUnless:       cmd.conditionUnless,
+		SkipIfUnmanageable: cmd.skipUnmanageable,
 	}
 }
 
@@ -122,13 +141,19 @@ func (cmd *ensureCommand) commonEnsureResource(properties model.ResourceProperti
 	cp.Control = cmd.control()
 	cp.Require = cmd.requires
 	cp.Alias = cmd.alias
+	cp.ApplyTimeout = cmd.applyTimeout
 
 	svc, err := resources.NewResourceFromProperties(ctx, mgr, properties)
 	if err != nil {
 		return err
 	}
 
-	status, err := svc.Apply(ctx)
+	var status *model.TransactionEvent
+	if cmd.monitorOnly {
+		status, err = svc.Healthcheck(ctx)
+	} else {
+		status, err = svc.Apply(ctx)
+	}
 	if err != nil {
 		return err
 	}
@@ -22,6 +22,9 @@ type ensureArchiveCommand struct {
 	username string
 	password string
 	checksum string
+	retries  int
+	delay    string
+	resume   bool
 	extract  string
 	cleanup  bool
 	owner    string
@@ -40,6 +43,9 @@ func registerEnsureArchiveCommand(ccm *fisk.CmdClause, parent *ensureCommand) {
 	archive.Flag("username", "HTTP username to use for authentication").PlaceHolder("USER").StringVar(&cmd.username)
 	archive.Flag("password", "HTTP password to use for authentication").PlaceHolder("PASS").Envar("HTTP_PASSWORD").StringVar(&cmd.password)
 	archive.Flag("checksum", "Hex encoded sha256 checksum of the archive").PlaceHolder("SHA256SUM").StringVar(&cmd.checksum)
+	archive.Flag("retries", "Number of times failed downloads are retried").PlaceHolder("COUNT").IntVar(&cmd.retries)
+	archive.Flag("retry-delay", "Delay before the first download retry").PlaceHolder("DURATION").StringVar(&cmd.delay)
+	archive.Flag("resume", "Continue interrupted downloads when the server supports it").UnNegatableBoolVar(&cmd.resume)
 	archive.Flag("extract", "Parent directory to extract to").PlaceHolder("DIR").ExistingDirVar(&cmd.extract)
 	archive.Flag("creates", "Skip extraction if this file exists").PlaceHolder("FILE").StringVar(&cmd.creates)
 	archive.Flag("cleanup", "Removes the archive after extraction").UnNegatableBoolVar(&cmd.cleanup)
@@ -60,6 +66,9 @@ func (c *ensureArchiveCommand) archiveAction(_ *fisk.ParseContext) error {
 		Username:      c.username,
 		Password:      c.password,
 		Checksum:      c.checksum,
+		Retries:       c.retries,
+		RetryDelay:    c.delay,
+		Resume:        c.resume,
 		ExtractParent: c.extract,
 		Creates:       c.creates,
 		Cleanup:       c.cleanup,
@@ -0,0 +1,62 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/fisk"
+)
+
+type ensureCronCommand struct {
+	name        string
+	command     string
+	ensure      string
+	user        string
+	minute      string
+	hour        string
+	monthDay    string
+	month       string
+	weekday     string
+	environment []string
+	parent      *ensureCommand
+}
+
+func registerEnsureCronCommand(ccm *fisk.CmdClause, parent *ensureCommand) {
+	cmd := &ensureCronCommand{parent: parent}
+
+	cron := ccm.Command("cron", "Scheduled job management").Action(cmd.cronAction)
+	cron.Arg("name", "Job name, used to derive the file name").Required().StringVar(&cmd.name)
+	cron.Arg("command", "The command to run").StringVar(&cmd.command)
+	cron.Flag("ensure", "Ensure value").Default(model.EnsurePresent).EnumVar(&cmd.ensure, model.EnsurePresent, model.EnsureAbsent)
+	cron.Flag("user", "User to run the command as").Default(model.CronDefaultUser).StringVar(&cmd.user)
+	cron.Flag("minute", "Minute schedule field").Default("*").StringVar(&cmd.minute)
+	cron.Flag("hour", "Hour schedule field").Default("*").StringVar(&cmd.hour)
+	cron.Flag("monthday", "Day of month schedule field").Default("*").StringVar(&cmd.monthDay)
+	cron.Flag("month", "Month schedule field").Default("*").StringVar(&cmd.month)
+	cron.Flag("weekday", "Day of week schedule field").Default("*").StringVar(&cmd.weekday)
+	cron.Flag("env", "Environment variable to set for the job").PlaceHolder("NAME=VALUE").StringsVar(&cmd.environment)
+
+	parent.addCommonFlags(cron)
+}
+
+func (c *ensureCronCommand) cronAction(_ *fisk.ParseContext) error {
+	properties := model.CronResourceProperties{
+		CommonResourceProperties: model.CommonResourceProperties{
+			Name:     c.name,
+			Ensure:   c.ensure,
+			Provider: c.parent.provider,
+		},
+		Command:     c.command,
+		User:        c.user,
+		Minute:      c.minute,
+		Hour:        c.hour,
+		MonthDay:    c.monthDay,
+		Month:       c.month,
+		Weekday:     c.weekday,
+		Environment: c.environment,
+	}
+
+	return c.parent.commonEnsureResource(&properties)
+}
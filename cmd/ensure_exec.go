@@ -17,6 +17,7 @@ type ensureExecCommand struct {
 	cwd         string
 	environment []string
 	path        string
+	user        string
 	subscribe   []string
 	refreshOnly bool
 	logoutput   bool
@@ -36,6 +37,7 @@ func registerEnsureExecCommand(ccm *fisk.CmdClause, parent *ensureCommand) {
 	exec.Flag("cwd", "Working directory for command execution").PlaceHolder("DIR").StringVar(&cmd.cwd)
 	exec.Flag("environment", "Environment variables in KEY=VALUE format").Short('e').PlaceHolder("KEY=VALUE").StringsVar(&cmd.environment)
 	exec.Flag("path", "Search path for executables (colon-separated)").PlaceHolder("PATH").StringVar(&cmd.path)
+	exec.Flag("user", "User to run the command as").PlaceHolder("USER").StringVar(&cmd.user)
 	exec.Flag("refresh-only", "Only run when notified by a subscribed resource").UnNegatableBoolVar(&cmd.refreshOnly)
 	exec.Flag("subscribe", "Subscribe to changes in other resources").PlaceHolder("type#name").Short('S').StringsVar(&cmd.subscribe)
 	exec.Flag("logoutput", "Log output of the command").UnNegatableBoolVar(&cmd.logoutput)
@@ -57,6 +59,7 @@ func (c *ensureExecCommand) execAction(_ *fisk.ParseContext) error {
 		Cwd:         c.cwd,
 		Environment: c.environment,
 		Path:        c.path,
+		User:        c.user,
 		Creates:     c.creates,
 		RefreshOnly: c.refreshOnly,
 		Subscribe:   c.subscribe,
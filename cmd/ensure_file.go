@@ -21,9 +21,19 @@ type ensureFileCommand struct {
 	contentsFile  string
 	contents      string
 	contentsIsSet bool
+	encoding      string
+	normalization string
 	source        string
+	sources       []string
 	owner         string
 	mode          string
+	recurse       bool
+	purge         bool
+	manageParents bool
+	parentMode    string
+	acl           []string
+	validate      string
+	showDiff      bool
 	parent        *ensureCommand
 }
 
@@ -37,7 +47,17 @@ func registerEnsureFileCommand(ccm *fisk.CmdClause, parent *ensureCommand) {
 	file.Flag("mode", "File mode (octal)").Default("0644").StringVar(&cmd.mode)
 	file.Flag("content", "Contents of the file, will be template parsed").PlaceHolder("STRING").IsSetByUser(&cmd.contentsIsSet).StringVar(&cmd.contents)
 	file.Flag("content-file", "File containing the contents of the file, will be template parsed").PlaceHolder("FILE").ExistingFileVar(&cmd.contentsFile)
-	file.Flag("source", "File to copy in place verbatim").PlaceHolder("FILE").ExistingFileVar(&cmd.source)
+	file.Flag("content-encoding", "Encoding of the contents, base64 contents are decoded before storing").Default(model.FileContentEncodingPlain).EnumVar(&cmd.encoding, model.FileContentEncodingPlain, model.FileContentEncodingBase64)
+	file.Flag("content-normalization", "Normalize line endings or trailing whitespace before comparing and storing contents").Default(model.FileContentNormalizationNone).EnumVar(&cmd.normalization, model.FileContentNormalizationNone, model.FileContentNormalizationLF, model.FileContentNormalizationTrimTrailing)
+	file.Flag("source", "File to copy in place verbatim, or directory to copy with --recurse").PlaceHolder("PATH").ExistingFileOrDirVar(&cmd.source)
+	file.Flag("sources", "Local files or URLs tried in order, the first that resolves is used with the content as fallback").PlaceHolder("SOURCE").StringsVar(&cmd.sources)
+	file.Flag("recurse", "Copy the source directory tree into the directory").UnNegatableBoolVar(&cmd.recurse)
+	file.Flag("purge", "Remove entries not present in the source directory, requires --recurse").UnNegatableBoolVar(&cmd.purge)
+	file.Flag("manage-parents", "Create missing parent directories owned by the file owner").UnNegatableBoolVar(&cmd.manageParents)
+	file.Flag("parent-mode", "Mode of created parent directories (octal)").PlaceHolder("MODE").StringVar(&cmd.parentMode)
+	file.Flag("acl", "POSIX ACL entries to manage in [default:]user|group:name:permissions format").PlaceHolder("ENTRY").StringsVar(&cmd.acl)
+	file.Flag("validate", "Command validating new content before it is written, %{path} is replaced by a temporary file holding the content").PlaceHolder("COMMAND").StringVar(&cmd.validate)
+	file.Flag("show-diff", "Show a diff of the content change in noop mode, includes the current content of the file").UnNegatableBoolVar(&cmd.showDiff)
 	file.Flag("registration", "The NATS Stream holding registration data").Default("REGISTRATION").Short('R').StringVar(&cmd.parent.registrationStream)
 
 	parent.addCommonFlags(file)
@@ -50,6 +70,9 @@ func (c *ensureFileCommand) fileAction(_ *fisk.ParseContext) error {
 	if c.source != "" && (c.contentsIsSet || c.contentsFile != "") {
 		return fmt.Errorf("cannot specify both source and contents or contents-file")
 	}
+	if c.source != "" && len(c.sources) > 0 {
+		return fmt.Errorf("cannot specify both source and sources")
+	}
 
 	var owner string
 	var group string
@@ -83,9 +106,16 @@ func (c *ensureFileCommand) fileAction(_ *fisk.ParseContext) error {
 			Ensure:   c.ensure,
 			Provider: c.parent.provider,
 		},
-		Owner: owner,
-		Group: group,
-		Mode:  c.mode,
+		Owner:           owner,
+		Group:           group,
+		Mode:            c.mode,
+		Recurse:         c.recurse,
+		Purge:           c.purge,
+		ManageParents:   c.manageParents,
+		ParentMode:      c.parentMode,
+		Acl:             c.acl,
+		ValidateCommand: c.validate,
+		ShowDiff:        c.showDiff,
 	}
 
 	switch {
@@ -105,5 +135,15 @@ func (c *ensureFileCommand) fileAction(_ *fisk.ParseContext) error {
 		properties.Source = c.source
 	}
 
+	properties.Sources = c.sources
+
+	if properties.Contents != nil && c.encoding != model.FileContentEncodingPlain {
+		properties.ContentEncoding = c.encoding
+	}
+
+	if c.normalization != model.FileContentNormalizationNone {
+		properties.ContentNormalization = c.normalization
+	}
+
 	return c.parent.commonEnsureResource(&properties)
 }
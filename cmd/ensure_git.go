@@ -0,0 +1,56 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/fisk"
+)
+
+type ensureGitCommand struct {
+	name     string
+	ensure   string
+	source   string
+	revision string
+	depth    int
+	sshKey   string
+	username string
+	password string
+	parent   *ensureCommand
+}
+
+func registerEnsureGitCommand(ccm *fisk.CmdClause, parent *ensureCommand) {
+	cmd := &ensureGitCommand{parent: parent}
+
+	git := ccm.Command("git", "Git checkout management").Action(cmd.gitAction)
+	git.Arg("path", "Directory to check the repository out into").Required().StringVar(&cmd.name)
+	git.Arg("source", "URL of the repository to clone").StringVar(&cmd.source)
+	git.Flag("ensure", "Ensure value").Default(model.EnsurePresent).EnumVar(&cmd.ensure, model.EnsurePresent, model.GitEnsureLatest, model.EnsureAbsent)
+	git.Flag("revision", "Branch, tag or commit to check out").PlaceHolder("REV").StringVar(&cmd.revision)
+	git.Flag("depth", "Create a shallow clone with the given number of commits").IntVar(&cmd.depth)
+	git.Flag("ssh-key", "Private key to use for ssh sources").PlaceHolder("FILE").StringVar(&cmd.sshKey)
+	git.Flag("username", "HTTP username to use for authentication").PlaceHolder("USER").StringVar(&cmd.username)
+	git.Flag("password", "HTTP password to use for authentication").PlaceHolder("PASS").Envar("HTTP_PASSWORD").StringVar(&cmd.password)
+
+	parent.addCommonFlags(git)
+}
+
+func (c *ensureGitCommand) gitAction(_ *fisk.ParseContext) error {
+	properties := model.GitResourceProperties{
+		CommonResourceProperties: model.CommonResourceProperties{
+			Name:     c.name,
+			Ensure:   c.ensure,
+			Provider: c.parent.provider,
+		},
+		Source:   c.source,
+		Revision: c.revision,
+		Depth:    c.depth,
+		SSHKey:   c.sshKey,
+		Username: c.username,
+		Password: c.password,
+	}
+
+	return c.parent.commonEnsureResource(&properties)
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/fisk"
+)
+
+type ensureGroupCommand struct {
+	name    string
+	ensure  string
+	gid     int
+	system  bool
+	members []string
+	parent  *ensureCommand
+}
+
+func registerEnsureGroupCommand(ccm *fisk.CmdClause, parent *ensureCommand) {
+	cmd := &ensureGroupCommand{parent: parent}
+
+	group := ccm.Command("group", "Group management").Action(cmd.groupAction)
+	group.Arg("name", "Group name to manage").Required().StringVar(&cmd.name)
+	group.Flag("ensure", "Ensure value").Default(model.EnsurePresent).EnumVar(&cmd.ensure, model.EnsurePresent, model.EnsureAbsent)
+	group.Flag("gid", "Group id, chosen by the system when not set").IntVar(&cmd.gid)
+	group.Flag("system", "Create a system group").UnNegatableBoolVar(&cmd.system)
+	group.Flag("member", "Member of the group, membership is not managed when not set").PlaceHolder("USER").StringsVar(&cmd.members)
+
+	parent.addCommonFlags(group)
+}
+
+func (c *ensureGroupCommand) groupAction(_ *fisk.ParseContext) error {
+	properties := model.GroupResourceProperties{
+		CommonResourceProperties: model.CommonResourceProperties{
+			Name:     c.name,
+			Ensure:   c.ensure,
+			Provider: c.parent.provider,
+		},
+		GID:     c.gid,
+		System:  c.system,
+		Members: c.members,
+	}
+
+	return c.parent.commonEnsureResource(&properties)
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/fisk"
+)
+
+type ensureHostCommand struct {
+	name     string
+	ensure   string
+	ip       string
+	hostname string
+	aliases  []string
+	parent   *ensureCommand
+}
+
+func registerEnsureHostCommand(ccm *fisk.CmdClause, parent *ensureCommand) {
+	cmd := &ensureHostCommand{parent: parent}
+
+	host := ccm.Command("host", "Hosts file entry management").Action(cmd.hostAction)
+	host.Arg("name", "Name of the entry, used as hostname when no hostname is set").Required().StringVar(&cmd.name)
+	host.Flag("ensure", "Ensure value").Default(model.EnsurePresent).EnumVar(&cmd.ensure, model.EnsurePresent, model.EnsureAbsent)
+	host.Flag("ip", "Address the hostname resolves to").StringVar(&cmd.ip)
+	host.Flag("hostname", "Canonical hostname of the entry").StringVar(&cmd.hostname)
+	host.Flag("host-alias", "Additional name for the address").PlaceHolder("ALIAS").StringsVar(&cmd.aliases)
+
+	parent.addCommonFlags(host)
+}
+
+func (c *ensureHostCommand) hostAction(_ *fisk.ParseContext) error {
+	properties := model.HostResourceProperties{
+		CommonResourceProperties: model.CommonResourceProperties{
+			Name:     c.name,
+			Ensure:   c.ensure,
+			Provider: c.parent.provider,
+		},
+		IP:       c.ip,
+		Hostname: c.hostname,
+		Aliases:  c.aliases,
+	}
+
+	return c.parent.commonEnsureResource(&properties)
+}
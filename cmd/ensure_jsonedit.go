@@ -0,0 +1,56 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/fisk"
+	"github.com/goccy/go-yaml"
+)
+
+type ensureJsonEditCommand struct {
+	name   string
+	path   string
+	value  string
+	ensure string
+	format string
+	parent *ensureCommand
+}
+
+func registerEnsureJsonEditCommand(ccm *fisk.CmdClause, parent *ensureCommand) {
+	cmd := &ensureJsonEditCommand{parent: parent}
+
+	edit := ccm.Command("jsonedit", "JSON and YAML document value management").Action(cmd.jsonEditAction)
+	edit.Arg("file", "The JSON or YAML file to edit").Required().StringVar(&cmd.name)
+	edit.Arg("path", "Dot separated path to the value to manage").Required().StringVar(&cmd.path)
+	edit.Arg("value", "The value to set, parsed as YAML").StringVar(&cmd.value)
+	edit.Flag("ensure", "Ensure value").Default(model.EnsurePresent).EnumVar(&cmd.ensure, model.EnsurePresent, model.EnsureAbsent)
+	edit.Flag("format", "The document format").EnumVar(&cmd.format, model.JsonEditFormatJSON, model.JsonEditFormatYAML)
+
+	parent.addCommonFlags(edit)
+}
+
+func (c *ensureJsonEditCommand) jsonEditAction(_ *fisk.ParseContext) error {
+	properties := model.JsonEditResourceProperties{
+		CommonResourceProperties: model.CommonResourceProperties{
+			Name:     c.name,
+			Ensure:   c.ensure,
+			Provider: c.parent.provider,
+		},
+		Path:   c.path,
+		Format: c.format,
+	}
+
+	if c.value != "" {
+		err := yaml.Unmarshal([]byte(c.value), &properties.Value)
+		if err != nil {
+			return fmt.Errorf("invalid value: %w", err)
+		}
+	}
+
+	return c.parent.commonEnsureResource(&properties)
+}
@@ -10,9 +10,12 @@ import (
 )
 
 type ensurePackageCommand struct {
-	name   string
-	ensure string
-	parent *ensureCommand
+	name       string
+	ensure     string
+	autoremove bool
+	cleanCache bool
+	manager    string
+	parent     *ensureCommand
 }
 
 func registerEnsurePackageCommand(ccm *fisk.CmdClause, parent *ensureCommand) {
@@ -21,6 +24,9 @@ func registerEnsurePackageCommand(ccm *fisk.CmdClause, parent *ensureCommand) {
 	pkg := ccm.Command("package", "Package management").Alias("pkg").Action(cmd.packageAction)
 	pkg.Arg("name", "Package name to manage").Required().StringVar(&cmd.name)
 	pkg.Arg("ensure", "Ensure value").Default(model.EnsurePresent).StringVar(&cmd.ensure)
+	pkg.Flag("autoremove", "Remove unused dependencies after uninstalling the package").UnNegatableBoolVar(&cmd.autoremove)
+	pkg.Flag("clean-cache", "Clean the package manager cache after a change").UnNegatableBoolVar(&cmd.cleanCache)
+	pkg.Flag("manager", "Preferred package manager, falls back to the most suitable provider when it cannot be used").PlaceHolder("NAME").StringVar(&cmd.manager)
 	parent.addCommonFlags(pkg)
 }
 
@@ -31,6 +37,9 @@ func (c *ensurePackageCommand) packageAction(_ *fisk.ParseContext) error {
 			Ensure:   c.ensure,
 			Provider: c.parent.provider,
 		},
+		Autoremove: c.autoremove,
+		CleanCache: c.cleanCache,
+		Manager:    c.manager,
 	}
 
 	return c.parent.commonEnsureResource(&properties)
@@ -21,6 +21,11 @@ type ensureScaffoldCommand struct {
 	engine    string
 	purge     bool
 	post      map[string]string
+	owner     string
+	group     string
+	fileMode  string
+	include   []string
+	exclude   []string
 
 	parent *ensureCommand
 }
@@ -35,9 +40,14 @@ func registerEnsureScaffoldCommand(ccm *fisk.CmdClause, parent *ensureCommand) {
 	scaffold.Flag("skip-empty", "Do not create empty files").BoolVar(&cmd.skipEmpty)
 	scaffold.Flag("left-delimiter", "Left template delimiter").StringVar(&cmd.left)
 	scaffold.Flag("right-delimiter", "Right template delimiter").StringVar(&cmd.right)
-	scaffold.Flag("engine", "Template engine to use (go, jet)").Default("jet").EnumVar(&cmd.engine, string(model.ScaffoldEngineGo), string(model.ScaffoldEngineJet))
+	scaffold.Flag("engine", "Template engine to use (go, jet, raw)").Default("jet").EnumVar(&cmd.engine, string(model.ScaffoldEngineGo), string(model.ScaffoldEngineJet), string(model.ScaffoldEngineRaw))
 	scaffold.Flag("post", "Post processing steps").PlaceHolder("PATTERN=TOOL").StringMapVar(&cmd.post)
 	scaffold.Flag("purge", "Purge existing files").UnNegatableBoolVar(&cmd.purge)
+	scaffold.Flag("owner", "Owner of every written file").StringVar(&cmd.owner)
+	scaffold.Flag("group", "Group of every written file").StringVar(&cmd.group)
+	scaffold.Flag("file-mode", "Octal mode of every written file").StringVar(&cmd.fileMode)
+	scaffold.Flag("include", "Only manage files matching these globs").StringsVar(&cmd.include)
+	scaffold.Flag("exclude", "Never manage files matching these globs").StringsVar(&cmd.exclude)
 
 	parent.addCommonFlags(scaffold)
 }
@@ -49,6 +59,11 @@ func (c *ensureScaffoldCommand) scaffoldAction(_ *fisk.ParseContext) error {
 		RightDelimiter: c.right,
 		Source:         c.source,
 		Purge:          c.purge,
+		Owner:          c.owner,
+		Group:          c.group,
+		FileMode:       c.fileMode,
+		Include:        c.include,
+		Exclude:        c.exclude,
 		CommonResourceProperties: model.CommonResourceProperties{
 			Name:     c.name,
 			Ensure:   c.ensure,
@@ -65,6 +80,8 @@ func (c *ensureScaffoldCommand) scaffoldAction(_ *fisk.ParseContext) error {
 		properties.Engine = model.ScaffoldEngineJet
 	case "go":
 		properties.Engine = model.ScaffoldEngineGo
+	case "raw":
+		properties.Engine = model.ScaffoldEngineRaw
 	default:
 		return fmt.Errorf("unknown engine %q", c.engine)
 	}
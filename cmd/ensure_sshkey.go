@@ -0,0 +1,53 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/fisk"
+)
+
+type ensureSSHKeyCommand struct {
+	name    string
+	ensure  string
+	user    string
+	keyType string
+	key     string
+	comment string
+	options []string
+	parent  *ensureCommand
+}
+
+func registerEnsureSSHKeyCommand(ccm *fisk.CmdClause, parent *ensureCommand) {
+	cmd := &ensureSSHKeyCommand{parent: parent}
+
+	key := ccm.Command("ssh_authorized_key", "SSH authorized key management").Alias("sshkey").Action(cmd.sshKeyAction)
+	key.Arg("name", "Name of the key, used as comment when no comment is set").Required().StringVar(&cmd.name)
+	key.Flag("ensure", "Ensure value").Default(model.EnsurePresent).EnumVar(&cmd.ensure, model.EnsurePresent, model.EnsureAbsent)
+	key.Flag("user", "User whose authorized_keys file is managed").Required().StringVar(&cmd.user)
+	key.Flag("type", "Type of the key").PlaceHolder("TYPE").EnumVar(&cmd.keyType, model.SSHKeyTypes...)
+	key.Flag("key", "Base64 encoded public key").StringVar(&cmd.key)
+	key.Flag("comment", "Comment identifying the key").StringVar(&cmd.comment)
+	key.Flag("option", "Option restricting the key").PlaceHolder("OPTION").StringsVar(&cmd.options)
+
+	parent.addCommonFlags(key)
+}
+
+func (c *ensureSSHKeyCommand) sshKeyAction(_ *fisk.ParseContext) error {
+	properties := model.SSHAuthorizedKeyResourceProperties{
+		CommonResourceProperties: model.CommonResourceProperties{
+			Name:     c.name,
+			Ensure:   c.ensure,
+			Provider: c.parent.provider,
+		},
+		User:    c.user,
+		KeyType: c.keyType,
+		Key:     c.key,
+		Comment: c.comment,
+		Options: c.options,
+	}
+
+	return c.parent.commonEnsureResource(&properties)
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/fisk"
+)
+
+type ensureSudoersCommand struct {
+	name     string
+	ensure   string
+	content  string
+	user     string
+	hosts    []string
+	runAs    string
+	commands []string
+	noPasswd bool
+	parent   *ensureCommand
+}
+
+func registerEnsureSudoersCommand(ccm *fisk.CmdClause, parent *ensureCommand) {
+	cmd := &ensureSudoersCommand{parent: parent}
+
+	sudoers := ccm.Command("sudoers", "Sudoers file management").Action(cmd.sudoersAction)
+	sudoers.Arg("name", "File name in the sudoers.d directory").Required().StringVar(&cmd.name)
+	sudoers.Flag("ensure", "Ensure value").Default(model.EnsurePresent).EnumVar(&cmd.ensure, model.EnsurePresent, model.EnsureAbsent)
+	sudoers.Flag("content", "Literal content of the sudoers file").StringVar(&cmd.content)
+	sudoers.Flag("user", "User, %group or alias a rule applies to").StringVar(&cmd.user)
+	sudoers.Flag("host", "Host the rule applies to").StringsVar(&cmd.hosts)
+	sudoers.Flag("run-as", "User the commands may be run as").StringVar(&cmd.runAs)
+	sudoers.Flag("command", "Fully qualified command the user may run").StringsVar(&cmd.commands)
+	sudoers.Flag("nopasswd", "Allow the commands to run without a password").UnNegatableBoolVar(&cmd.noPasswd)
+
+	parent.addCommonFlags(sudoers)
+}
+
+func (c *ensureSudoersCommand) sudoersAction(_ *fisk.ParseContext) error {
+	properties := model.SudoersResourceProperties{
+		CommonResourceProperties: model.CommonResourceProperties{
+			Name:     c.name,
+			Ensure:   c.ensure,
+			Provider: c.parent.provider,
+		},
+		Content: c.content,
+	}
+
+	if c.user != "" {
+		properties.Rules = []model.SudoersRule{{
+			User:     c.user,
+			Hosts:    c.hosts,
+			RunAs:    c.runAs,
+			Commands: c.commands,
+			NoPasswd: c.noPasswd,
+		}}
+	}
+
+	return c.parent.commonEnsureResource(&properties)
+}
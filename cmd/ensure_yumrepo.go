@@ -0,0 +1,52 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/fisk"
+)
+
+type ensureYumRepoCommand struct {
+	name        string
+	ensure      string
+	baseURL     string
+	description string
+	gpgKey      string
+	disabled    bool
+	parent      *ensureCommand
+}
+
+func registerEnsureYumRepoCommand(ccm *fisk.CmdClause, parent *ensureCommand) {
+	cmd := &ensureYumRepoCommand{parent: parent}
+
+	repo := ccm.Command("yumrepo", "Yum and dnf repository management").Action(cmd.yumRepoAction)
+	repo.Arg("name", "Repository id, also used as file name").Required().StringVar(&cmd.name)
+	repo.Flag("ensure", "Ensure value").Default(model.EnsurePresent).EnumVar(&cmd.ensure, model.EnsurePresent, model.EnsureAbsent)
+	repo.Flag("baseurl", "URL of the repository").StringVar(&cmd.baseURL)
+	repo.Flag("description", "Human readable name of the repository").StringVar(&cmd.description)
+	repo.Flag("gpgkey", "URL of the key packages are signed with").StringVar(&cmd.gpgKey)
+	repo.Flag("disabled", "Write the repository disabled").UnNegatableBoolVar(&cmd.disabled)
+
+	parent.addCommonFlags(repo)
+}
+
+func (c *ensureYumRepoCommand) yumRepoAction(_ *fisk.ParseContext) error {
+	enabled := !c.disabled
+
+	properties := model.YumRepoResourceProperties{
+		CommonResourceProperties: model.CommonResourceProperties{
+			Name:     c.name,
+			Ensure:   c.ensure,
+			Provider: c.parent.provider,
+		},
+		BaseURL:     c.baseURL,
+		Description: c.description,
+		GPGKey:      c.gpgKey,
+		Enabled:     &enabled,
+	}
+
+	return c.parent.commonEnsureResource(&properties)
+}
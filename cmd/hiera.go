@@ -25,6 +25,8 @@ import (
 
 type hieraCommand struct {
 	input       string
+	layers      []string
+	showSources bool
 	factsInput  map[string]string
 	factsFile   string
 	envFacts    bool
@@ -33,6 +35,9 @@ type hieraCommand struct {
 	envPrefix   string
 	dataInput   map[string]string
 	dataFile    string
+	privateKey  string
+	token       string
+	strict      bool
 	query       string
 	natsContext string
 }
@@ -47,8 +52,13 @@ func registerHieraCommand(ccm *fisk.Application) {
 
 	parse := hiera.Command("parse", "Parses a YAML or JSON file and prints the result as JSON").Alias("resolve").Action(cmd.parseAction)
 	parse.Arg("input", "Input JSON or YAML file to resolve").Envar("HIERA_INPUT").Required().StringVar(&cmd.input)
+	parse.Flag("merge", "Additional data sources resolved and deep merged over the input in order").PlaceHolder("SOURCE").StringsVar(&cmd.layers)
+	parse.Flag("sources", "Show the sources that set each top level key rather than the data").UnNegatableBoolVar(&cmd.showSources)
 	parse.Flag("data", "Override data values (always strings)").Short('D').StringMapVar(&cmd.dataInput)
 	parse.Flag("data-file", "JSON or YAML file containing override data values").PlaceHolder("FILE").ExistingFileVar(&cmd.dataFile)
+	parse.Flag("strict", "Fail when a lookup in a data value finds no value and has no default").UnNegatableBoolVar(&cmd.strict)
+	parse.Flag("token", "Bearer token sent to HTTP(S) data sources").Envar("HIERA_TOKEN").PlaceHolder("TOKEN").StringVar(&cmd.token)
+	parse.Flag("private-key", "PEM encoded RSA private key used to decrypt ENC[PKCS7,...] values").PlaceHolder("FILE").ExistingFileVar(&cmd.privateKey)
 	parse.Arg("fact", "Facts about the node").StringMapVar(&cmd.factsInput)
 	parse.Flag("facts", "JSON or YAML file containing facts").ExistingFileVar(&cmd.factsFile)
 	parse.Flag("env-facts", "Provide facts from the process environment").Short('E').UnNegatableBoolVar(&cmd.envFacts)
@@ -107,6 +117,15 @@ func (cmd *hieraCommand) parseAction(_ *fisk.ParseContext) error {
 	}
 
 	hieraOpts := hiera.DefaultOptions
+	hieraOpts.BearerToken = cmd.token
+	hieraOpts.Strict = cmd.strict
+
+	if cmd.privateKey != "" {
+		hieraOpts.PrivateKeyPEM, err = os.ReadFile(cmd.privateKey)
+		if err != nil {
+			return fmt.Errorf("failed to read private key: %w", err)
+		}
+	}
 
 	if cmd.dataFile != "" {
 		raw, err := os.ReadFile(cmd.dataFile)
@@ -137,12 +156,15 @@ func (cmd *hieraCommand) parseAction(_ *fisk.ParseContext) error {
 		}
 	}
 
-	hieraResult, err := hiera.ResolveUrl(ctx, cmd.input, mgr, facts, hieraOpts, logger)
+	hieraResult, err := hiera.ResolveUrls(ctx, append([]string{cmd.input}, cmd.layers...), mgr, facts, hieraOpts, logger)
 	if err != nil {
 		return err
 	}
 
 	res = hieraResult.Data
+	if cmd.showSources {
+		res = hieraResult.Sources
+	}
 
 	if cmd.query != "" {
 		jout, err := json.MarshalIndent(res, "", "  ")
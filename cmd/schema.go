@@ -0,0 +1,53 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/resources/apply"
+	"github.com/choria-io/fisk"
+)
+
+type schemaCommand struct {
+	output       string
+	resourceType string
+}
+
+func registerSchemaCommand(ccm *fisk.Application) {
+	cmd := &schemaCommand{}
+
+	schema := ccm.Command("schema", "Produce the JSON Schema for manifests").Action(cmd.schemaAction)
+	schema.HelpLong(`Produces a JSON Schema describing manifests for use by editors and other
+tooling to validate and complete manifests.
+
+The schema is derived from the resource types supported by this version
+of CCM and should be regenerated after upgrading.`)
+	schema.Flag("output", "Write the schema to FILE rather than STDOUT").Short('o').PlaceHolder("FILE").StringVar(&cmd.output)
+	schema.Flag("type", "Produce the schema for the properties of a single resource type").PlaceHolder("TYPE").StringVar(&cmd.resourceType)
+}
+
+func (c *schemaCommand) schemaAction(_ *fisk.ParseContext) error {
+	var schema []byte
+	var err error
+
+	if c.resourceType != "" {
+		schema, err = model.Schema(c.resourceType)
+	} else {
+		schema, err = apply.ManifestSchema()
+	}
+	if err != nil {
+		return err
+	}
+
+	if c.output == "" {
+		fmt.Println(string(schema))
+		return nil
+	}
+
+	return os.WriteFile(c.output, append(schema, '\n'), 0644)
+}
@@ -56,14 +56,31 @@ func (c *sessionCmd) reportAction(_ *fisk.ParseContext) error {
 	if summary.TotalDuration > 0 {
 		fmt.Printf("             Run Time: %v\n", summary.TotalDuration.Round(time.Millisecond))
 	}
+	if summary.SlowestResource != "" {
+		fmt.Printf("     Slowest Resource: %s (%v)\n", summary.SlowestResource, summary.SlowestResourceDuration.Round(time.Millisecond))
+	}
 	fmt.Printf("      Total Resources: %d\n", summary.TotalResources)
 	fmt.Printf("     Unique Resources: %d\n", summary.UniqueResources)
 	fmt.Printf("     Stable Resources: %d\n", summary.StableResources)
 	fmt.Printf("    Changed Resources: %d\n", summary.ChangedResources)
+	if summary.CorrectiveResources > 0 {
+		fmt.Printf("   Corrective Changes: %d\n", summary.CorrectiveResources)
+	}
 	fmt.Printf("     Failed Resources: %d\n", summary.FailedResources)
 	fmt.Printf("    Skipped Resources: %d\n", summary.SkippedResources)
+	if summary.NotApplicableResources > 0 {
+		fmt.Printf("       Not Applicable: %d\n", summary.NotApplicableResources)
+	}
+	if summary.DeadlineExceededResources > 0 {
+		fmt.Printf("    Deadline Exceeded: %d\n", summary.DeadlineExceededResources)
+	}
 	fmt.Printf("  Refreshed Resources: %d\n", summary.RefreshedCount)
 	fmt.Printf("         Total Errors: %d\n", summary.TotalErrors)
+	if summary.Noop {
+		fmt.Printf("          Would Drift: %.1f%%\n", summary.WouldDriftRatio*100)
+	} else {
+		fmt.Printf("                Drift: %.1f%%\n", summary.DriftRatio*100)
+	}
 
 	if c.clearSession {
 		// only clear files in temp dir
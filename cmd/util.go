@@ -8,6 +8,7 @@ import (
 	"bufio"
 	"errors"
 	"log/slog"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -20,7 +21,7 @@ import (
 	"github.com/choria-io/ccm/model"
 )
 
-func newManager(session string, hieraSource string, natsContext string, readEnv bool, noop bool, regStream string, facts map[string]any) (model.Manager, model.Logger, error) {
+func newManager(session string, hieraSource string, natsContext string, readEnv bool, noop bool, regStream string, facts map[string]any, extra ...manager.Option) (model.Manager, model.Logger, error) {
 	var opts []manager.Option
 
 	if session != "" {
@@ -49,6 +50,8 @@ func newManager(session string, hieraSource string, natsContext string, readEnv
 		opts = append(opts, manager.WithNoop())
 	}
 
+	opts = append(opts, extra...)
+
 	mgr, err := manager.NewManager(logger, out, opts...)
 	if err != nil {
 		return nil, nil, err
@@ -65,6 +68,22 @@ func newManager(session string, hieraSource string, natsContext string, readEnv
 		}
 
 		logger.Debug("Loading overriding hiera data from external source", "source", hieraSource)
+
+		// local files are resolved lazily so only the keys used by the resource are resolved
+		if uri, err := url.Parse(hieraSource); err == nil && uri.Scheme == "" {
+			lazyData, err := hiera.LazyFile(hieraSource, facts, hiera.DefaultOptions, logger)
+			switch {
+			case err == nil:
+				mgr.SetDataResolver(lazyData)
+			case errors.Is(err, hiera.ErrFileNotFound):
+				logger.Debug("Hiera data file not found, skipping", "file", hieraSource)
+			default:
+				return nil, nil, err
+			}
+
+			return mgr, out, nil
+		}
+
 		hieraResult, err := hiera.ResolveUrl(ctx, hieraSource, mgr, facts, hiera.DefaultOptions, logger)
 		switch {
 		case err == nil:
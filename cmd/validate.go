@@ -0,0 +1,126 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/goccy/go-yaml"
+
+	iu "github.com/choria-io/ccm/internal/util"
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/resources"
+	"github.com/choria-io/fisk"
+)
+
+type validateCommand struct {
+	typeName   string
+	file       string
+	hieraFile  string
+	readEnv    bool
+	facts      map[string]string
+	jsonFormat bool
+}
+
+func registerValidateCommand(ccm *fisk.Application) {
+	cmd := &validateCommand{
+		facts: make(map[string]string),
+	}
+
+	validate := ccm.Command("validate", "Validates the properties of a single resource").Action(cmd.validateAction)
+	validate.HelpLong(`Validates a single resource using the same checks as loading a manifest
+without selecting providers or making any changes.
+
+The properties are read from FILE, or STDIN when FILE is - or not given,
+in the same format as a resource in a manifest:
+
+   name: /etc/motd
+   ensure: present
+   content: Managed by CCM
+   owner: root
+   group: root
+   mode: "0644"
+`)
+	validate.Arg("type", "The resource type to validate").Required().EnumVar(&cmd.typeName, model.ApplyTypeName, model.ArchiveTypeName, model.CronTypeName, model.ExecTypeName, model.FileTypeName, model.GitTypeName, model.GroupTypeName, model.HostTypeName, model.JsonEditTypeName, model.PackageTypeName, model.ScaffoldTypeName, model.ServiceTypeName, model.SSHAuthorizedKeyTypeName, model.SudoersTypeName, model.YumRepoTypeName)
+	validate.Arg("file", "File holding the resource properties").Default("-").StringVar(&cmd.file)
+	validate.Flag("fact", "Set additional facts to merge with the system facts").StringMapVar(&cmd.facts)
+	validate.Flag("hiera", "Hiera data file to use as data source").Default(".hiera").Envar("CCM_HIERA_DATA").StringVar(&cmd.hieraFile)
+	validate.Flag("read-env", "Read extra variables from .env file").Default("true").BoolVar(&cmd.readEnv)
+	validate.Flag("json", "Output errors in JSON format").UnNegatableBoolVar(&cmd.jsonFormat)
+}
+
+func (c *validateCommand) validateAction(_ *fisk.ParseContext) error {
+	var body []byte
+	var err error
+
+	if c.file == "-" {
+		body, err = io.ReadAll(os.Stdin)
+	} else {
+		body, err = os.ReadFile(c.file)
+	}
+	if err != nil {
+		return err
+	}
+
+	properties := map[string]any{}
+	err = yaml.Unmarshal(body, &properties)
+	if err != nil {
+		return fmt.Errorf("could not parse resource properties: %w", err)
+	}
+
+	mgr, _, err := newManager("", c.hieraFile, "", c.readEnv, true, "", iu.MapStringsToMapStringAny(c.facts))
+	if err != nil {
+		return err
+	}
+
+	env, err := mgr.TemplateEnvironment(ctx)
+	if err != nil {
+		return err
+	}
+
+	log, err := mgr.Logger("component", "validate")
+	if err != nil {
+		return err
+	}
+
+	errs, err := resources.ValidateResource(ctx, c.typeName, properties, env, log)
+	if err != nil {
+		return err
+	}
+
+	if c.jsonFormat {
+		if errs == nil {
+			errs = []*resources.ValidationError{}
+		}
+
+		j, err := json.MarshalIndent(errs, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(j))
+	} else {
+		for _, verr := range errs {
+			if verr.Name != "" {
+				fmt.Printf("%s#%s: %s\n", c.typeName, verr.Name, verr.Error())
+			} else {
+				fmt.Printf("%s: %s\n", c.typeName, verr.Error())
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("resource is not valid")
+	}
+
+	if !c.jsonFormat {
+		fmt.Printf("%s resource is valid\n", c.typeName)
+	}
+
+	return nil
+}
@@ -26,6 +26,8 @@ func Gather(ctx context.Context, opts model.FactsConfig, log model.Logger) (map[
 		"memory":    getMemoryFacts(ctx, &opts),
 	}
 
+	gatherPluginFacts(ctx, facts, log)
+
 	for _, p := range append(opts.ExtraFactSources, gatherFileFacts) {
 		f, err := p(ctx, opts, log)
 		if err != nil {
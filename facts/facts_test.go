@@ -9,7 +9,9 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
+	"time"
 
 	"github.com/goccy/go-yaml"
 	. "github.com/onsi/ginkgo/v2"
@@ -320,7 +322,7 @@ var _ = Describe("Facts", func() {
 
 	Describe("readFactsDir", func() {
 		It("should return nil for non-existent directory", func() {
-			result := readFactsDir("/nonexistent/facts.d", logger)
+			result := readFactsDir(ctx, "/nonexistent/facts.d", false, logger)
 			Expect(result).To(BeNil())
 		})
 
@@ -329,7 +331,7 @@ var _ = Describe("Facts", func() {
 			dir := filepath.Join(td, "facts.d")
 			Expect(os.Mkdir(dir, 0755)).To(Succeed())
 
-			result := readFactsDir(dir, logger)
+			result := readFactsDir(ctx, dir, false, logger)
 			Expect(result).To(BeEmpty())
 		})
 
@@ -340,7 +342,7 @@ var _ = Describe("Facts", func() {
 			Expect(os.WriteFile(filepath.Join(dir, "net.json"), []byte(`{"network":"lan"}`), 0644)).To(Succeed())
 			Expect(os.WriteFile(filepath.Join(dir, "app.yaml"), []byte("app: myapp\n"), 0644)).To(Succeed())
 
-			result := readFactsDir(dir, logger)
+			result := readFactsDir(ctx, dir, false, logger)
 			Expect(result).To(HaveKeyWithValue("app", "myapp"))
 			Expect(result).To(HaveKeyWithValue("network", "lan"))
 		})
@@ -352,7 +354,7 @@ var _ = Describe("Facts", func() {
 			Expect(os.WriteFile(filepath.Join(dir, "data.json"), []byte(`{"key":"value"}`), 0644)).To(Succeed())
 			Expect(os.WriteFile(filepath.Join(dir, "extra.yml"), []byte("extra: yes\n"), 0644)).To(Succeed())
 
-			result := readFactsDir(dir, logger)
+			result := readFactsDir(ctx, dir, false, logger)
 			Expect(result).To(HaveLen(1))
 			Expect(result).To(HaveKeyWithValue("key", "value"))
 		})
@@ -364,7 +366,7 @@ var _ = Describe("Facts", func() {
 			Expect(os.WriteFile(filepath.Join(dir, "2override.json"), []byte(`{"role":"override"}`), 0644)).To(Succeed())
 			Expect(os.WriteFile(filepath.Join(dir, "1base.json"), []byte(`{"role":"base","source":"base"}`), 0644)).To(Succeed())
 
-			result := readFactsDir(dir, logger)
+			result := readFactsDir(ctx, dir, false, logger)
 			// 1base.json < 2override.json, so 2override wins
 			Expect(result).To(HaveKeyWithValue("role", "override"))
 			Expect(result).To(HaveKeyWithValue("source", "base"))
@@ -377,7 +379,7 @@ var _ = Describe("Facts", func() {
 			Expect(os.WriteFile(filepath.Join(dir, "data.json"), []byte(`{"key":"value"}`), 0644)).To(Succeed())
 			Expect(os.WriteFile(filepath.Join(dir, "readme.txt"), []byte("not facts"), 0644)).To(Succeed())
 
-			result := readFactsDir(dir, logger)
+			result := readFactsDir(ctx, dir, false, logger)
 			Expect(result).To(HaveLen(1))
 			Expect(result).To(HaveKeyWithValue("key", "value"))
 		})
@@ -389,7 +391,7 @@ var _ = Describe("Facts", func() {
 			Expect(os.Mkdir(filepath.Join(dir, "subdir"), 0755)).To(Succeed())
 			Expect(os.WriteFile(filepath.Join(dir, "data.json"), []byte(`{"key":"value"}`), 0644)).To(Succeed())
 
-			result := readFactsDir(dir, logger)
+			result := readFactsDir(ctx, dir, false, logger)
 			Expect(result).To(HaveLen(1))
 			Expect(result).To(HaveKeyWithValue("key", "value"))
 		})
@@ -406,7 +408,7 @@ var _ = Describe("Facts", func() {
 
 			logger.EXPECT().Error(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
-			result := readFactsDir(dir, logger)
+			result := readFactsDir(ctx, dir, false, logger)
 			Expect(result).To(HaveLen(1))
 			Expect(result).To(HaveKeyWithValue("regular", "yes"))
 		})
@@ -422,7 +424,7 @@ var _ = Describe("Facts", func() {
 
 			logger.EXPECT().Error(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
-			result := readFactsDir(linkDir, logger)
+			result := readFactsDir(ctx, linkDir, false, logger)
 			Expect(result).To(BeNil())
 		})
 
@@ -444,6 +446,81 @@ var _ = Describe("Facts", func() {
 		})
 	})
 
+	Describe("External facts", func() {
+		var dir string
+
+		BeforeEach(func() {
+			if runtime.GOOS == "windows" {
+				Skip("requires a posix shell")
+			}
+
+			dir = GinkgoT().TempDir()
+			logger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+		})
+
+		writeScript := func(name string, body string) {
+			Expect(os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+body+"\n"), 0755)).To(Succeed())
+		}
+
+		It("Should read facts files and the output of scripts", func() {
+			Expect(os.WriteFile(filepath.Join(dir, "10-static.json"), []byte(`{"static":"yes","role":"db"}`), 0644)).To(Succeed())
+			writeScript("20-json.sh", `echo '{"role":"web"}'`)
+			writeScript("30-yaml.sh", `printf 'rack:\n  id: r12\n'`)
+			writeScript("40-kv.sh", `echo "location=dc1"; echo "# comment"; echo "tier = gold"`)
+
+			result, err := gatherFileFacts(ctx, model.FactsConfig{ExternalFactsDirectory: dir}, logger)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(map[string]any{
+				"static":   "yes",
+				"role":     "web",
+				"rack":     map[string]any{"id": "r12"},
+				"location": "dc1",
+				"tier":     "gold",
+			}))
+		})
+
+		It("Should log and skip failing scripts", func() {
+			writeScript("fail.sh", `echo broken >&2; exit 1`)
+			writeScript("garbage.sh", `echo "not facts"`)
+			writeScript("ok.sh", `echo ok=yes`)
+
+			result, err := gatherFileFacts(ctx, model.FactsConfig{ExternalFactsDirectory: dir}, logger)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(map[string]any{"ok": "yes"}))
+		})
+
+		It("Should stop scripts that run too long", func() {
+			DeferCleanup(func(timeout time.Duration) { ScriptTimeout = timeout }, ScriptTimeout)
+			ScriptTimeout = 100 * time.Millisecond
+
+			writeScript("slow.sh", `sleep 10`)
+
+			start := time.Now()
+			result, err := gatherFileFacts(ctx, model.FactsConfig{ExternalFactsDirectory: dir}, logger)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(BeEmpty())
+			Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+		})
+
+		It("Should skip world writable scripts", func() {
+			writeScript("open.sh", `echo open=yes`)
+			Expect(os.Chmod(filepath.Join(dir, "open.sh"), 0777)).To(Succeed())
+
+			result, err := gatherFileFacts(ctx, model.FactsConfig{ExternalFactsDirectory: dir}, logger)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(BeEmpty())
+		})
+
+		It("Should not run scripts in configuration facts directories", func() {
+			Expect(os.Mkdir(filepath.Join(dir, "facts.d"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "facts.d", "run.sh"), []byte("#!/bin/sh\necho ran=yes\n"), 0755)).To(Succeed())
+
+			result, err := gatherFileFacts(ctx, model.FactsConfig{SystemConfigDirectory: dir}, logger)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(BeEmpty())
+		})
+	})
+
 	Describe("getMemoryFacts", func() {
 		It("should return empty facts when NoMemoryFacts is set", func() {
 			opts := &model.FactsConfig{NoMemoryFacts: true}
@@ -33,13 +33,23 @@ func gatherFileFacts(ctx context.Context, opts model.FactsConfig, log model.Logg
 
 		facts = iu.DeepMergeMap(facts, readFactsFile(filepath.Join(dir, "facts.json"), json.Unmarshal, log))
 		facts = iu.DeepMergeMap(facts, readFactsFile(filepath.Join(dir, "facts.yaml"), yaml.Unmarshal, log))
-		facts = iu.DeepMergeMap(facts, readFactsDir(filepath.Join(dir, "facts.d"), log))
+		facts = iu.DeepMergeMap(facts, readFactsDir(ctx, filepath.Join(dir, "facts.d"), false, log))
+	}
+
+	if opts.ExternalFactsDirectory != "" {
+		if filepath.IsAbs(opts.ExternalFactsDirectory) {
+			facts = iu.DeepMergeMap(facts, readFactsDir(ctx, filepath.Clean(opts.ExternalFactsDirectory), true, log))
+		} else {
+			log.Error("Skipping external facts directory with relative path", "dir", opts.ExternalFactsDirectory)
+		}
 	}
 
 	return facts, nil
 }
 
-func readFactsDir(dir string, log model.Logger) map[string]any {
+// readFactsDir reads the facts files in dir in name order, when scripts is set *.sh scripts are run and their output
+// is read as facts
+func readFactsDir(ctx context.Context, dir string, scripts bool, log model.Logger) map[string]any {
 	if iu.IsSymlink(dir) {
 		log.Error("Skipping facts directory that is a symlink", "dir", dir)
 		return nil
@@ -74,6 +84,12 @@ func readFactsDir(dir string, log model.Logger) map[string]any {
 			facts = iu.DeepMergeMap(facts, readFactsFile(path, json.Unmarshal, log))
 		case ".yaml":
 			facts = iu.DeepMergeMap(facts, readFactsFile(path, yaml.Unmarshal, log))
+		case ".sh":
+			if !scripts {
+				log.Debug("Skipping facts script outside of the external facts directory", "file", path)
+				continue
+			}
+			facts = iu.DeepMergeMap(facts, runFactsScript(ctx, path, log))
 		default:
 			log.Debug("Skipping non-facts file", "file", path)
 		}
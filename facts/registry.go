@@ -0,0 +1,100 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package facts
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/choria-io/ccm/model"
+)
+
+var (
+	gatherers  = make(map[string]model.FactGatherer)
+	gathererMu sync.Mutex
+
+	// gathererNameRegex matches names that can be used as fact keys in expressions
+	gathererNameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+)
+
+// RegisterGatherer registers a fact gatherer, its facts are gathered with the system facts and stored under its name
+func RegisterGatherer(g model.FactGatherer) error {
+	name := g.Name()
+	if !gathererNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid fact gatherer name %q", name)
+	}
+
+	gathererMu.Lock()
+	defer gathererMu.Unlock()
+
+	_, ok := gatherers[name]
+	if ok {
+		return fmt.Errorf("fact gatherer %s already registered", name)
+	}
+
+	gatherers[name] = g
+
+	return nil
+}
+
+// MustRegisterGatherer registers a fact gatherer and panics if registration fails
+func MustRegisterGatherer(g model.FactGatherer) {
+	err := RegisterGatherer(g)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// ClearGatherers removes all registered fact gatherers
+func ClearGatherers() {
+	gathererMu.Lock()
+	defer gathererMu.Unlock()
+
+	gatherers = make(map[string]model.FactGatherer)
+}
+
+// registeredGatherers returns the registered fact gatherers sorted by name
+func registeredGatherers() []model.FactGatherer {
+	gathererMu.Lock()
+	defer gathererMu.Unlock()
+
+	var res []model.FactGatherer
+	for _, g := range gatherers {
+		res = append(res, g)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Name() < res[j].Name() })
+
+	return res
+}
+
+// gatherPluginFacts adds the facts of all registered gatherers to facts under their names, gatherers whose name is
+// already used by other facts are skipped
+func gatherPluginFacts(ctx context.Context, facts map[string]any, log model.Logger) {
+	for _, g := range registeredGatherers() {
+		name := g.Name()
+
+		_, ok := facts[name]
+		if ok {
+			log.Warn("Skipping fact gatherer that conflicts with existing facts", "gatherer", name)
+			continue
+		}
+
+		f, err := g.Gather(ctx)
+		if err != nil {
+			log.Error("Could not gather facts", "gatherer", name, "error", err)
+			continue
+		}
+
+		if f == nil {
+			f = map[string]any{}
+		}
+
+		facts[name] = f
+	}
+}
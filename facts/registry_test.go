@@ -0,0 +1,88 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package facts
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/model/modelmocks"
+)
+
+type fakeGatherer struct {
+	name  string
+	facts map[string]any
+	err   error
+}
+
+func (g *fakeGatherer) Name() string { return g.name }
+
+func (g *fakeGatherer) Gather(_ context.Context) (map[string]any, error) { return g.facts, g.err }
+
+var _ = Describe("Fact gatherers", func() {
+	var (
+		logger *modelmocks.MockLogger
+		opts   model.FactsConfig
+	)
+
+	BeforeEach(func() {
+		logger = modelmocks.NewMockLogger(gomock.NewController(GinkgoT()))
+		logger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+
+		opts = model.FactsConfig{NoCPUFacts: true, NoMemoryFacts: true, NoPartitionFacts: true, NoHostFacts: true, NoNetworkFacts: true}
+
+		ClearGatherers()
+		DeferCleanup(ClearGatherers)
+	})
+
+	Describe("RegisterGatherer", func() {
+		It("Should reject invalid and duplicate names", func() {
+			Expect(RegisterGatherer(&fakeGatherer{name: "cloud-meta"})).To(MatchError(`invalid fact gatherer name "cloud-meta"`))
+			Expect(RegisterGatherer(&fakeGatherer{name: ""})).To(MatchError(`invalid fact gatherer name ""`))
+
+			Expect(RegisterGatherer(&fakeGatherer{name: "cloud"})).To(Succeed())
+			Expect(RegisterGatherer(&fakeGatherer{name: "cloud"})).To(MatchError("fact gatherer cloud already registered"))
+			Expect(func() { MustRegisterGatherer(&fakeGatherer{name: "cloud"}) }).To(Panic())
+		})
+	})
+
+	Describe("Gather", func() {
+		It("Should store facts under the gatherer name", func(ctx context.Context) {
+			MustRegisterGatherer(&fakeGatherer{name: "cloud", facts: map[string]any{"region": "eu-west-1"}})
+			MustRegisterGatherer(&fakeGatherer{name: "inventory", facts: map[string]any{"rack": "r12"}})
+
+			result, err := Gather(ctx, opts, logger)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(HaveKeyWithValue("cloud", map[string]any{"region": "eu-west-1"}))
+			Expect(result).To(HaveKeyWithValue("inventory", map[string]any{"rack": "r12"}))
+			Expect(result).To(HaveKey("host"))
+		})
+
+		It("Should skip gatherers that conflict with existing facts", func(ctx context.Context) {
+			logger.EXPECT().Warn("Skipping fact gatherer that conflicts with existing facts", "gatherer", "host")
+			MustRegisterGatherer(&fakeGatherer{name: "host", facts: map[string]any{"name": "override"}})
+
+			result, err := Gather(ctx, opts, logger)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result["host"]).ToNot(HaveKey("name"))
+		})
+
+		It("Should log and skip failing gatherers", func(ctx context.Context) {
+			logger.EXPECT().Error("Could not gather facts", "gatherer", "cloud", "error", gomock.Any())
+			MustRegisterGatherer(&fakeGatherer{name: "cloud", err: errors.New("metadata service unavailable")})
+			MustRegisterGatherer(&fakeGatherer{name: "inventory", facts: map[string]any{"rack": "r12"}})
+
+			result, err := Gather(ctx, opts, logger)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).ToNot(HaveKey("cloud"))
+			Expect(result).To(HaveKey("inventory"))
+		})
+	})
+})
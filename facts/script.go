@@ -0,0 +1,113 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package facts
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/choria-io/ccm/internal/cmdrunner"
+	"github.com/choria-io/ccm/model"
+)
+
+// ScriptTimeout is the maximum time a single external facts script may run
+var ScriptTimeout = 10 * time.Second
+
+// runFactsScript runs the facts script at path and parses its output, failures are logged and produce no facts
+func runFactsScript(ctx context.Context, path string, log model.Logger) map[string]any {
+	stat, err := os.Stat(path)
+	if err != nil {
+		log.Error("Failed to read facts script", "file", path, "error", err)
+		return nil
+	}
+
+	if runtime.GOOS != "windows" && stat.Mode().Perm()&0o002 != 0 {
+		log.Error("Skipping facts script that is world writable", "file", path)
+		return nil
+	}
+
+	runner, err := cmdrunner.NewCommandRunner(log)
+	if err != nil {
+		log.Error("Failed to run facts script", "file", path, "error", err)
+		return nil
+	}
+
+	log.Debug("Running facts script", "file", path)
+
+	stdout, stderr, exitCode, err := runner.ExecuteWithOptions(ctx, model.ExtendedExecOptions{
+		Command: path,
+		Cwd:     filepath.Dir(path),
+		Timeout: ScriptTimeout,
+	})
+	switch {
+	case err != nil:
+		log.Error("Failed to run facts script", "file", path, "error", err)
+		return nil
+	case exitCode != 0:
+		log.Error("Facts script failed", "file", path, "exitcode", exitCode, "stderr", strings.TrimSpace(string(stderr)))
+		return nil
+	}
+
+	facts, err := parseScriptOutput(stdout)
+	if err != nil {
+		log.Error("Failed to parse facts script output", "file", path, "error", err)
+		return nil
+	}
+
+	return facts
+}
+
+// parseScriptOutput parses facts from JSON, YAML or key=value lines
+func parseScriptOutput(out []byte) (map[string]any, error) {
+	out = bytes.TrimSpace(out)
+	if len(out) == 0 {
+		return nil, nil
+	}
+
+	var facts map[string]any
+
+	if out[0] == '{' {
+		err := json.Unmarshal(out, &facts)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+
+		return facts, nil
+	}
+
+	err := yaml.Unmarshal(out, &facts)
+	if err == nil {
+		return facts, nil
+	}
+
+	facts = map[string]any{}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for i := 1; scanner.Scan(); i++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("line %d is not JSON, YAML or key=value", i)
+		}
+
+		facts[key] = strings.TrimSpace(value)
+	}
+
+	return facts, nil
+}
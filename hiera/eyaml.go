@@ -0,0 +1,255 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package hiera
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/choria-io/ccm/model"
+)
+
+const (
+	encryptedPrefix = "ENC[PKCS7,"
+	encryptedSuffix = "]"
+)
+
+var (
+	oidEnvelopedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 3}
+	oidRSAEncryption = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+	oidAES128CBC     = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC     = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC     = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidDESEDE3CBC    = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 7}
+
+	// ErrDecryptFailed indicates an encrypted value could not be decrypted with the configured key
+	ErrDecryptFailed = errors.New("could not decrypt value")
+)
+
+// pkcs7ContentInfo is the outer PKCS7 structure holding the enveloped data
+type pkcs7ContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue
+}
+
+// pkcs7EnvelopedData holds the content encryption key for every recipient and the encrypted content
+type pkcs7EnvelopedData struct {
+	Version              int
+	RecipientInfos       []pkcs7RecipientInfo `asn1:"set"`
+	EncryptedContentInfo pkcs7EncryptedContentInfo
+}
+
+// pkcs7RecipientInfo is the content encryption key encrypted to the key of one recipient
+type pkcs7RecipientInfo struct {
+	Version                int
+	IssuerAndSerialNumber  asn1.RawValue
+	KeyEncryptionAlgorithm pkcs7AlgorithmIdentifier
+	EncryptedKey           []byte
+}
+
+// pkcs7EncryptedContentInfo is the content encrypted with the content encryption key
+type pkcs7EncryptedContentInfo struct {
+	ContentType                asn1.ObjectIdentifier
+	ContentEncryptionAlgorithm pkcs7AlgorithmIdentifier
+	EncryptedContent           asn1.RawValue `asn1:"tag:0,optional"`
+}
+
+type pkcs7AlgorithmIdentifier struct {
+	Algorithm  asn1.ObjectIdentifier
+	Parameters asn1.RawValue `asn1:"optional"`
+}
+
+// IsEncryptedValue determines if value is an eyaml style ENC[PKCS7,...] encrypted value
+func IsEncryptedValue(value string) bool {
+	value = strings.TrimSpace(value)
+
+	return strings.HasPrefix(value, encryptedPrefix) && strings.HasSuffix(value, encryptedSuffix)
+}
+
+// ParsePrivateKey parses a PEM encoded PKCS1 or PKCS8 RSA private key
+func ParsePrivateKey(keyPEM []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in private key")
+	}
+
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse private key: %w", err)
+	}
+
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not an RSA key")
+	}
+
+	return key, nil
+}
+
+// DecryptValue decrypts an eyaml style ENC[PKCS7,...] value, the value holds base64 encoded PKCS7 enveloped
+// data as produced by hiera-eyaml
+func DecryptValue(value string, key *rsa.PrivateKey) (string, error) {
+	value = strings.TrimSpace(value)
+	if !IsEncryptedValue(value) {
+		return "", fmt.Errorf("%w: not an ENC[PKCS7,...] value", ErrDecryptFailed)
+	}
+
+	// block style yaml values can wrap the encoded data over several lines
+	encoded := strings.Join(strings.Fields(strings.TrimSuffix(strings.TrimPrefix(value, encryptedPrefix), encryptedSuffix)), "")
+
+	der, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid base64: %w", ErrDecryptFailed, err)
+	}
+
+	var ci pkcs7ContentInfo
+	_, err = asn1.Unmarshal(der, &ci)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid PKCS7 data: %w", ErrDecryptFailed, err)
+	}
+	if !ci.ContentType.Equal(oidEnvelopedData) {
+		return "", fmt.Errorf("%w: PKCS7 data is not enveloped data", ErrDecryptFailed)
+	}
+
+	var ed pkcs7EnvelopedData
+	_, err = asn1.Unmarshal(ci.Content.Bytes, &ed)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid PKCS7 enveloped data: %w", ErrDecryptFailed, err)
+	}
+
+	content, err := encryptedContentBytes(ed.EncryptedContentInfo.EncryptedContent)
+	if err != nil {
+		return "", err
+	}
+
+	// the recipients are identified by certificate, we only have the key so every recipient is tried
+	for _, recipient := range ed.RecipientInfos {
+		if !recipient.KeyEncryptionAlgorithm.Algorithm.Equal(oidRSAEncryption) {
+			continue
+		}
+
+		cek, err := rsa.DecryptPKCS1v15(nil, key, recipient.EncryptedKey)
+		if err != nil {
+			continue
+		}
+
+		plain, err := decryptContent(ed.EncryptedContentInfo.ContentEncryptionAlgorithm, cek, content)
+		if err != nil {
+			continue
+		}
+
+		return string(plain), nil
+	}
+
+	return "", fmt.Errorf("%w: no recipient matches the private key", ErrDecryptFailed)
+}
+
+// encryptedContentBytes extracts the encrypted content which BER encoders may split into several octet strings
+func encryptedContentBytes(raw asn1.RawValue) ([]byte, error) {
+	if !raw.IsCompound {
+		return raw.Bytes, nil
+	}
+
+	var content []byte
+	rest := raw.Bytes
+	for len(rest) > 0 {
+		var chunk []byte
+		var err error
+
+		rest, err = asn1.Unmarshal(rest, &chunk)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid encrypted content: %w", ErrDecryptFailed, err)
+		}
+
+		content = append(content, chunk...)
+	}
+
+	return content, nil
+}
+
+// decryptContent decrypts CBC encrypted content and removes its PKCS7 padding
+func decryptContent(alg pkcs7AlgorithmIdentifier, cek []byte, content []byte) ([]byte, error) {
+	var block cipher.Block
+	var err error
+
+	switch {
+	case alg.Algorithm.Equal(oidAES128CBC), alg.Algorithm.Equal(oidAES192CBC), alg.Algorithm.Equal(oidAES256CBC):
+		block, err = aes.NewCipher(cek)
+	case alg.Algorithm.Equal(oidDESEDE3CBC):
+		block, err = des.NewTripleDESCipher(cek)
+	default:
+		return nil, fmt.Errorf("unsupported content encryption algorithm %s", alg.Algorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var iv []byte
+	_, err = asn1.Unmarshal(alg.Parameters.FullBytes, &iv)
+	if err != nil {
+		return nil, fmt.Errorf("invalid initialization vector: %w", err)
+	}
+
+	if len(iv) != block.BlockSize() || len(content) == 0 || len(content)%block.BlockSize() != 0 {
+		return nil, fmt.Errorf("invalid encrypted content size")
+	}
+
+	plain := make([]byte, len(content))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plain, content)
+
+	pad := int(plain[len(plain)-1])
+	if pad == 0 || pad > block.BlockSize() || !bytes.Equal(plain[len(plain)-pad:], bytes.Repeat([]byte{byte(pad)}, pad)) {
+		return nil, fmt.Errorf("invalid padding")
+	}
+
+	return plain[:len(plain)-pad], nil
+}
+
+// decrypter creates the function used to decrypt encrypted values while expanding data, without a key
+// encrypted values are left untouched and a warning is logged
+func decrypter(opts Options, log model.Logger) (func(string) (string, bool, error), error) {
+	if len(opts.PrivateKeyPEM) == 0 {
+		return func(value string) (string, bool, error) {
+			if IsEncryptedValue(value) && log != nil {
+				log.Warn("Leaving encrypted value untouched as no private key is configured")
+			}
+
+			return value, false, nil
+		}, nil
+	}
+
+	key, err := ParsePrivateKey(opts.PrivateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(value string) (string, bool, error) {
+		if !IsEncryptedValue(value) {
+			return value, false, nil
+		}
+
+		plain, err := DecryptValue(value, key)
+		if err != nil {
+			return "", false, err
+		}
+
+		return plain, true, nil
+	}, nil
+}
@@ -0,0 +1,220 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package hiera
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/choria-io/ccm/model/modelmocks"
+)
+
+// encryptTestValue produces an ENC[PKCS7,...] value the way hiera-eyaml does, AES-256-CBC content encrypted with a
+// key transported using RSA PKCS1 v1.5
+func encryptTestValue(pub *rsa.PublicKey, plain string) string {
+	GinkgoHelper()
+
+	cek := make([]byte, 32)
+	iv := make([]byte, aes.BlockSize)
+	_, err := rand.Read(cek)
+	Expect(err).ToNot(HaveOccurred())
+	_, err = rand.Read(iv)
+	Expect(err).ToNot(HaveOccurred())
+
+	pad := aes.BlockSize - len(plain)%aes.BlockSize
+	padded := append([]byte(plain), bytes.Repeat([]byte{byte(pad)}, pad)...)
+
+	block, err := aes.NewCipher(cek)
+	Expect(err).ToNot(HaveOccurred())
+	content := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(content, padded)
+
+	encryptedKey, err := rsa.EncryptPKCS1v15(rand.Reader, pub, cek)
+	Expect(err).ToNot(HaveOccurred())
+
+	ivParam, err := asn1.Marshal(iv)
+	Expect(err).ToNot(HaveOccurred())
+
+	issuer, err := asn1.Marshal(struct {
+		Issuer asn1.RawValue
+		Serial int
+	}{Issuer: asn1.RawValue{Tag: asn1.TagSequence, IsCompound: true}, Serial: 1})
+	Expect(err).ToNot(HaveOccurred())
+
+	ed, err := asn1.Marshal(pkcs7EnvelopedData{
+		RecipientInfos: []pkcs7RecipientInfo{{
+			IssuerAndSerialNumber:  asn1.RawValue{FullBytes: issuer},
+			KeyEncryptionAlgorithm: pkcs7AlgorithmIdentifier{Algorithm: oidRSAEncryption, Parameters: asn1.RawValue{Tag: asn1.TagNull}},
+			EncryptedKey:           encryptedKey,
+		}},
+		EncryptedContentInfo: pkcs7EncryptedContentInfo{
+			ContentType:                asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1},
+			ContentEncryptionAlgorithm: pkcs7AlgorithmIdentifier{Algorithm: oidAES256CBC, Parameters: asn1.RawValue{FullBytes: ivParam}},
+			EncryptedContent:           asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, Bytes: content},
+		},
+	})
+	Expect(err).ToNot(HaveOccurred())
+
+	ci, err := asn1.Marshal(pkcs7ContentInfo{
+		ContentType: oidEnvelopedData,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: ed},
+	})
+	Expect(err).ToNot(HaveOccurred())
+
+	return "ENC[PKCS7," + base64.StdEncoding.EncodeToString(ci) + "]"
+}
+
+var _ = Describe("Encrypted values", func() {
+	var (
+		key    *rsa.PrivateKey
+		keyPEM []byte
+	)
+
+	BeforeEach(func() {
+		var err error
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).ToNot(HaveOccurred())
+
+		keyPEM = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	})
+
+	Describe("DecryptValue", func() {
+		It("Should round trip a secret", func() {
+			enc := encryptTestValue(&key.PublicKey, "s3cret password")
+			Expect(IsEncryptedValue(enc)).To(BeTrue())
+
+			parsed, err := ParsePrivateKey(keyPEM)
+			Expect(err).ToNot(HaveOccurred())
+
+			plain, err := DecryptValue(enc, parsed)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plain).To(Equal("s3cret password"))
+		})
+
+		It("Should support values wrapped over several lines", func() {
+			enc := encryptTestValue(&key.PublicKey, "secret")
+			wrapped := enc[:40] + "\n    " + enc[40:80] + "\n    " + enc[80:]
+
+			plain, err := DecryptValue(wrapped, key)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plain).To(Equal("secret"))
+		})
+
+		It("Should fail with the wrong key", func() {
+			other, err := rsa.GenerateKey(rand.Reader, 2048)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = DecryptValue(encryptTestValue(&key.PublicKey, "secret"), other)
+			Expect(err).To(MatchError(ErrDecryptFailed))
+		})
+
+		It("Should fail for invalid values", func() {
+			_, err := DecryptValue("ENC[PKCS7,not base64!]", key)
+			Expect(err).To(MatchError(ErrDecryptFailed))
+
+			_, err = DecryptValue("plain", key)
+			Expect(err).To(MatchError(ErrDecryptFailed))
+		})
+	})
+
+	Describe("ParsePrivateKey", func() {
+		It("Should parse PKCS8 keys", func() {
+			der, err := x509.MarshalPKCS8PrivateKey(key)
+			Expect(err).ToNot(HaveOccurred())
+
+			parsed, err := ParsePrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed.Equal(key)).To(BeTrue())
+		})
+
+		It("Should fail for invalid keys", func() {
+			_, err := ParsePrivateKey([]byte("not a key"))
+			Expect(err).To(MatchError("no PEM data found in private key"))
+		})
+	})
+
+	Describe("Resolve", func() {
+		document := func(key *rsa.PrivateKey) map[string]any {
+			return map[string]any{
+				"hierarchy": map[string]any{
+					"order": []any{"role:{{ lookup('facts.role') }}"},
+					"merge": "deep",
+				},
+				"data": map[string]any{
+					"user":     "app",
+					"password": encryptTestValue(&key.PublicKey, "base secret"),
+				},
+				"overrides": map[string]any{
+					"role:db": map[string]any{
+						"db": map[string]any{
+							"credentials": []any{encryptTestValue(&key.PublicKey, "{{ not a template }}")},
+						},
+					},
+				},
+			}
+		}
+
+		It("Should decrypt values anywhere in the merged data", func() {
+			result, err := Resolve(document(key), map[string]any{"role": "db"}, Options{PrivateKeyPEM: keyPEM}, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(map[string]any{
+				"user":     "app",
+				"password": "base secret",
+				"db": map[string]any{
+					"credentials": []any{"{{ not a template }}"},
+				},
+			}))
+		})
+
+		It("Should decrypt values in lazily resolved data", func() {
+			lazy, err := NewLazyData(document(key), map[string]any{"role": "db"}, Options{PrivateKeyPEM: keyPEM}, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			val, ok, err := lazy.Lookup("password")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(val).To(Equal("base secret"))
+		})
+
+		It("Should leave encrypted values untouched and warn without a key", func() {
+			ctrl := gomock.NewController(GinkgoT())
+			log := modelmocks.NewMockLogger(ctrl)
+			log.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+			log.EXPECT().Warn("Leaving encrypted value untouched as no private key is configured").Times(2)
+
+			doc := document(key)
+			password := doc["data"].(map[string]any)["password"]
+
+			result, err := Resolve(doc, map[string]any{"role": "db"}, DefaultOptions, log)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result["password"]).To(Equal(password))
+		})
+
+		It("Should fail when decryption fails", func() {
+			other, err := rsa.GenerateKey(rand.Reader, 2048)
+			Expect(err).ToNot(HaveOccurred())
+			otherPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(other)})
+
+			_, err = Resolve(document(key), map[string]any{"role": "db"}, Options{PrivateKeyPEM: otherPEM}, nil)
+			Expect(err).To(MatchError(ErrDecryptFailed))
+		})
+
+		It("Should fail for invalid keys", func() {
+			_, err := Resolve(document(key), nil, Options{PrivateKeyPEM: []byte("invalid")}, nil)
+			Expect(err).To(MatchError("no PEM data found in private key"))
+		})
+	})
+})
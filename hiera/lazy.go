@@ -0,0 +1,327 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package hiera
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/goccy/go-yaml"
+
+	iu "github.com/choria-io/ccm/internal/util"
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/templates"
+)
+
+// LazyData resolves a hiera document one top level data key at a time. The hierarchy is evaluated against the
+// facts once when created while keys are only merged and have their templates expanded on first use, resolved
+// keys are cached. Every key resolves to the same value Resolve would produce for the whole document.
+type LazyData struct {
+	base          map[string]any
+	dataKey       string
+	candidates    []map[string]any
+	candidateKeys []string
+	mergeMode     string
+	overrides     map[string]any
+	env           *templates.Env
+	cache         map[string]any
+	resolved      map[string]bool
+	mu            sync.Mutex
+}
+
+// NewLazyData prepares a parsed data document for lazy resolution, see Resolve for the document format
+func NewLazyData(root map[string]any, facts map[string]any, opts Options, log model.Logger) (*LazyData, error) {
+	if opts.DataKey == "" {
+		opts.DataKey = "data"
+	}
+
+	_, ok := root["hierarchy"]
+	if !ok {
+		root["hierarchy"] = DefaultHierarchy
+	}
+
+	normalizedRoot, ok := normalizeNumericValues(root).(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("root document must be a map")
+	}
+
+	root = normalizedRoot
+	var overrides map[string]any
+	if _, ok := root["overrides"]; ok {
+		overrides = root["overrides"].(map[string]any)
+	}
+
+	hierarchy, err := parseHierarchy(root)
+	if err != nil {
+		return nil, err
+	}
+
+	decrypt, err := decrypter(opts, log)
+	if err != nil {
+		return nil, err
+	}
+
+	lazy := &LazyData{
+		base:      map[string]any{},
+		dataKey:   opts.DataKey,
+		overrides: opts.DataOverrides,
+		cache:     map[string]any{},
+		resolved:  map[string]bool{},
+		env: &templates.Env{
+			Facts:             facts,
+			DefaultOnMissing:  !opts.Strict,
+			RestrictFunctions: true,
+			DecryptFunc:       decrypt,
+		},
+	}
+
+	// hierarchy entries referencing missing facts do not match, so they are always resolved leniently
+	orderEnv := &templates.Env{
+		Facts:             facts,
+		DefaultOnMissing:  true,
+		RestrictFunctions: true,
+	}
+
+	data, hasData := root[opts.DataKey].(map[string]any)
+	if hasData {
+		lazy.base = data
+	}
+
+	lazy.mergeMode = strings.ToLower(hierarchy.Merge)
+	if lazy.mergeMode == "" {
+		lazy.mergeMode = "first"
+	}
+
+	var matches []map[string]any
+	var matchKeys []string
+	for _, entry := range hierarchy.Order {
+		resolvedKey, matched, err := templates.ResolveTemplateStringMatch(entry, orderEnv)
+		if err != nil {
+			return nil, err
+		}
+
+		if !matched {
+			continue
+		}
+
+		if log != nil {
+			log.Debug("Evaluating override", "override", resolvedKey)
+		}
+
+		if resolvedKey == opts.DataKey && hasData {
+			continue
+		}
+		candidate, ok := overrides[resolvedKey].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		if lazy.mergeMode != "deep" && lazy.mergeMode != "first" {
+			return nil, fmt.Errorf("unsupported merge mode: %s", lazy.mergeMode)
+		}
+
+		matches = append(matches, candidate)
+		matchKeys = append(matchKeys, resolvedKey)
+	}
+
+	lazy.candidates = matches
+	lazy.candidateKeys = matchKeys
+	if lazy.mergeMode == "first" && len(matches) > 0 {
+		lazy.candidates = matches[:1]
+		lazy.candidateKeys = matchKeys[:1]
+	}
+
+	lazy.env.Hierarchy = lookupLayers(data, matches, lazy.mergeMode)
+
+	return lazy, nil
+}
+
+// LazyFile reads a YAML or JSON file and prepares it for lazy resolution. Keys with validation rules are
+// resolved and validated immediately so invalid data is rejected as early as with ResolveFile.
+func LazyFile(file string, facts map[string]any, opts Options, log model.Logger) (*LazyData, error) {
+	if opts.DataKey == "" {
+		opts.DataKey = "data"
+	}
+
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if !iu.FileExists(abs) {
+		return nil, fmt.Errorf("%w: %s", ErrFileNotFound, abs)
+	}
+
+	raw, err := os.ReadFile(abs)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []ValidationRule
+	root := map[string]any{}
+
+	if iu.IsJsonObject(raw) {
+		err = json.Unmarshal(raw, &root)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %w", err)
+		}
+	} else {
+		cm := yaml.CommentMap{}
+		err = yaml.UnmarshalWithOptions(raw, &root, yaml.CommentToMap(cm))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+		rules = ParseAnnotations(cm, opts.DataKey, log)
+	}
+
+	lazy, err := NewLazyData(root, facts, opts, log)
+	if err != nil {
+		return nil, err
+	}
+
+	err = lazy.validate(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	return lazy, nil
+}
+
+// validate resolves only the keys referenced by rules and validates them
+func (l *LazyData) validate(rules []ValidationRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	data := map[string]any{}
+	for _, rule := range rules {
+		key, _, _ := strings.Cut(rule.Key, ".")
+
+		val, ok, err := l.Lookup(key)
+		if err != nil {
+			return err
+		}
+		if ok {
+			data[key] = val
+		}
+	}
+
+	return ValidateData(data, rules)
+}
+
+// Keys returns the sorted top level keys the resolved data will have
+func (l *LazyData) Keys() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	keys := map[string]struct{}{}
+	for _, source := range append([]map[string]any{l.base, l.overrides}, l.candidates...) {
+		for k := range source {
+			keys[k] = struct{}{}
+		}
+	}
+
+	res := make([]string, 0, len(keys))
+	for k := range keys {
+		res = append(res, k)
+	}
+	slices.Sort(res)
+
+	return res
+}
+
+// Lookup resolves a single top level key, the boolean reports if the key is set in the data
+func (l *LazyData) Lookup(key string) (any, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.resolved[key] {
+		val, ok := l.cache[key]
+		return val, ok, nil
+	}
+
+	val, ok, err := l.resolveKey(key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	l.resolved[key] = true
+	if ok {
+		l.cache[key] = val
+	}
+
+	return val, ok, nil
+}
+
+// All resolves every key, the result is identical to resolving the document with Resolve and merging the data overrides
+func (l *LazyData) All() (map[string]any, error) {
+	res := map[string]any{}
+
+	for _, key := range l.Keys() {
+		val, ok, err := l.Lookup(key)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			res[key] = val
+		}
+	}
+
+	return res, nil
+}
+
+// Reset discards all cached keys so they are resolved again on next use
+func (l *LazyData) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.cache = map[string]any{}
+	l.resolved = map[string]bool{}
+}
+
+// resolveKey merges a key the same way Resolve merges whole data sections, limiting the work to just this key
+func (l *LazyData) resolveKey(key string) (any, bool, error) {
+	resolved := map[string]any{}
+
+	if val, ok := l.base[key]; ok {
+		expanded, err := expandMapValues(iu.CloneMap(map[string]any{key: val}), l.env, l.dataKey)
+		if err != nil {
+			return nil, false, err
+		}
+		resolved = expanded
+	}
+
+	for i, candidate := range l.candidates {
+		val, ok := candidate[key]
+		if !ok {
+			continue
+		}
+
+		expanded, err := expandMapValues(iu.CloneMap(map[string]any{key: val}), l.env, "overrides."+l.candidateKeys[i])
+		if err != nil {
+			return nil, false, err
+		}
+
+		switch l.mergeMode {
+		case "deep":
+			resolved = iu.DeepMergeMap(resolved, expanded)
+		case "first":
+			resolved = iu.ShallowMerge(resolved, expanded)
+		}
+	}
+
+	if val, ok := l.overrides[key]; ok {
+		resolved = iu.DeepMergeMap(resolved, map[string]any{key: val})
+	}
+
+	val, ok := resolved[key]
+
+	return val, ok, nil
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package hiera
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/goccy/go-yaml"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LazyData", func() {
+	document := func(merge string) map[string]any {
+		root := map[string]any{}
+		Expect(yaml.Unmarshal([]byte(`
+hierarchy:
+  order:
+    - env:{{ lookup('facts.env') }}
+    - role:{{ lookup('facts.role') }}
+    - host:{{ lookup('facts.hostname') }}
+  merge: `+merge+`
+data:
+  log_level: INFO
+  packages:
+    - ca-certificates
+  web:
+    listen_port: 80
+    tls: false
+  hostname: "{{ lookup('facts.hostname') }}"
+
+overrides:
+  env:prod:
+    log_level: WARN
+    web:
+      listen_port: 8080
+
+  role:web:
+    packages:
+      - nginx
+    web:
+      tls: true
+    role_only: true
+
+  host:web01:
+    log_level: TRACE
+`), &root)).To(Succeed())
+
+		return root
+	}
+
+	facts := map[string]any{"env": "prod", "role": "web", "hostname": "web01"}
+
+	for _, merge := range []string{"first", "deep"} {
+		It("Should resolve to the same data as Resolve with "+merge+" merge", func() {
+			opts := Options{DataKey: "data", DataOverrides: map[string]any{"web": map[string]any{"bind": "0.0.0.0"}}}
+
+			eager, err := Resolve(document(merge), facts, opts, nil)
+			Expect(err).ToNot(HaveOccurred())
+			eager = mergeOverrides(eager, opts)
+
+			lazy, err := NewLazyData(document(merge), facts, opts, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			for key, val := range eager {
+				res, ok, err := lazy.Lookup(key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(ok).To(BeTrue())
+				Expect(res).To(Equal(val), key)
+			}
+
+			all, err := lazy.All()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(all).To(Equal(eager))
+		})
+	}
+
+	for _, merge := range []string{"first", "deep"} {
+		It("Should resolve lookups across the hierarchy like Resolve with "+merge+" merge", func() {
+			doc := func() map[string]any {
+				root := document(merge)
+				data := root["data"].(map[string]any)
+				data["all_packages"] = "{{ lookup('data.packages', {merge: 'unique'}) }}"
+				data["effective_log_level"] = "{{ lookup('data.log_level') }}"
+				return root
+			}
+
+			eager, err := Resolve(doc(), facts, DefaultOptions, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(eager["all_packages"]).To(Equal([]any{"ca-certificates", "nginx"}))
+
+			lazy, err := NewLazyData(doc(), facts, DefaultOptions, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, key := range []string{"all_packages", "effective_log_level"} {
+				res, ok, err := lazy.Lookup(key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(ok).To(BeTrue())
+				Expect(res).To(Equal(eager[key]), key)
+			}
+		})
+	}
+
+	It("Should only resolve and cache requested keys", func() {
+		lazy, err := NewLazyData(document("deep"), facts, DefaultOptions, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		val, ok, err := lazy.Lookup("hostname")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(val).To(Equal("web01"))
+		Expect(lazy.cache).To(Equal(map[string]any{"hostname": "web01"}))
+
+		_, ok, err = lazy.Lookup("unknown")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ok).To(BeFalse())
+		Expect(lazy.resolved).To(HaveKey("unknown"))
+
+		lazy.Reset()
+		Expect(lazy.cache).To(BeEmpty())
+		Expect(lazy.resolved).To(BeEmpty())
+	})
+
+	It("Should list all keys", func() {
+		lazy, err := NewLazyData(document("deep"), facts, DefaultOptions, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lazy.Keys()).To(Equal([]string{"hostname", "log_level", "packages", "role_only", "web"}))
+
+		lazy, err = NewLazyData(document("first"), facts, DefaultOptions, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lazy.Keys()).To(Equal([]string{"hostname", "log_level", "packages", "web"}))
+	})
+
+	It("Should reject unsupported merge modes", func() {
+		_, err := NewLazyData(document("bogus"), facts, DefaultOptions, nil)
+		Expect(err).To(MatchError("unsupported merge mode: bogus"))
+	})
+
+	Describe("LazyFile", func() {
+		It("Should handle missing files", func() {
+			_, err := LazyFile(filepath.Join(GinkgoT().TempDir(), "missing.yaml"), facts, DefaultOptions, nil)
+			Expect(errors.Is(err, ErrFileNotFound)).To(BeTrue())
+		})
+
+		It("Should validate keys with rules", func() {
+			file := filepath.Join(GinkgoT().TempDir(), "data.yaml")
+			Expect(os.WriteFile(file, []byte(`
+data:
+  # @require
+  user: ""
+  group: app
+`), 0600)).To(Succeed())
+
+			_, err := LazyFile(file, facts, DefaultOptions, nil)
+			Expect(err).To(MatchError(ContainSubstring("user")))
+
+			Expect(os.WriteFile(file, []byte(`
+data:
+  # @require
+  user: app
+  group: app
+`), 0600)).To(Succeed())
+
+			lazy, err := LazyFile(file, facts, DefaultOptions, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(lazy.cache).To(Equal(map[string]any{"user": "app"}))
+		})
+	})
+})
@@ -7,11 +7,17 @@ package hiera
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"math"
+	"mime"
+	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
 	"time"
 
@@ -39,6 +45,20 @@ type Options struct {
 	// data before validation runs. This allows callers to supply overriding
 	// values that satisfy @require annotations or change resolved defaults.
 	DataOverrides map[string]any
+
+	// PrivateKeyPEM is a PEM encoded RSA private key used to decrypt ENC[PKCS7,...] values,
+	// without it encrypted values are left untouched
+	PrivateKeyPEM []byte
+
+	// BearerToken is sent as an Authorization header when fetching data from HTTP(S) sources
+	BearerToken string
+
+	// HttpTimeout is the maximum time fetching data from HTTP(S) sources may take, defaults to 1 minute
+	HttpTimeout time.Duration
+
+	// Strict fails resolution when a lookup() in a data value finds no value and has no default, by default
+	// such lookups resolve to an empty string. Hierarchy entries are not affected.
+	Strict bool
 }
 
 var DefaultOptions = Options{
@@ -50,6 +70,10 @@ var DefaultOptions = Options{
 type ResolveResult struct {
 	Data  map[string]any
 	Rules []ValidationRule
+
+	// Sources records, for each top level data key, the sources that set the key in
+	// merge order, later sources override earlier ones. Only set by ResolveUrls.
+	Sources map[string][]string
 }
 
 // applyOverrides merges DataOverrides from opts into the resolved data and
@@ -90,8 +114,14 @@ var (
 	}
 
 	ErrFileNotFound = fmt.Errorf("file not found")
+
+	// ErrSourceNotFound indicates a remote data source does not exist
+	ErrSourceNotFound = fmt.Errorf("data source not found")
 )
 
+// MissingOkPrefix marks a source passed to ResolveUrls that is skipped when it does not exist
+const MissingOkPrefix = "missing-ok:"
+
 // Resolve consumes a parsed data document and a map of facts to produce a final data map.
 // The data map is expected to contain a hierarchy section, a base data section, and any number of overlays.
 // Placeholders in the hierarchy order (e.g. env:%{env}) are replaced with values from the provided facts map.
@@ -121,19 +151,23 @@ func Resolve(root map[string]any, facts map[string]any, opts Options, log model.
 		return nil, err
 	}
 
-	env := &templates.Env{
+	decrypt, err := decrypter(opts, log)
+	if err != nil {
+		return nil, err
+	}
+
+	// hierarchy entries referencing missing facts do not match, so they are always resolved leniently
+	orderEnv := &templates.Env{
 		Facts:             facts,
 		DefaultOnMissing:  true,
 		RestrictFunctions: true,
 	}
 
-	base := map[string]any{}
-	data, hasData := root[opts.DataKey].(map[string]any)
-	if hasData {
-		base, err = templates.ExpandMapValues(iu.CloneMap(data), env)
-		if err != nil {
-			return nil, err
-		}
+	env := &templates.Env{
+		Facts:             facts,
+		DefaultOnMissing:  !opts.Strict,
+		RestrictFunctions: true,
+		DecryptFunc:       decrypt,
 	}
 
 	mergeMode := strings.ToLower(hierarchy.Merge)
@@ -141,8 +175,12 @@ func Resolve(root map[string]any, facts map[string]any, opts Options, log model.
 		mergeMode = "first"
 	}
 
+	data, hasData := root[opts.DataKey].(map[string]any)
+
+	var candidates []map[string]any
+	var candidateKeys []string
 	for _, entry := range hierarchy.Order {
-		resolvedKey, matched, err := templates.ResolveTemplateStringMatch(entry, env)
+		resolvedKey, matched, err := templates.ResolveTemplateStringMatch(entry, orderEnv)
 		if err != nil {
 			return nil, err
 		}
@@ -164,7 +202,22 @@ func Resolve(root map[string]any, facts map[string]any, opts Options, log model.
 			continue
 		}
 
-		candidate, err = templates.ExpandMapValues(iu.CloneMap(candidate), env)
+		candidates = append(candidates, candidate)
+		candidateKeys = append(candidateKeys, candidateKey)
+	}
+
+	env.Hierarchy = lookupLayers(data, candidates, mergeMode)
+
+	base := map[string]any{}
+	if hasData {
+		base, err = expandMapValues(iu.CloneMap(data), env, opts.DataKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i, candidate := range candidates {
+		candidate, err = expandMapValues(iu.CloneMap(candidate), env, "overrides."+candidateKeys[i])
 		if err != nil {
 			return nil, err
 		}
@@ -183,6 +236,74 @@ func Resolve(root map[string]any, facts map[string]any, opts Options, log model.
 	return base, nil
 }
 
+// expandMapValues expands placeholders in all values of a map, errors name the path of the failing value
+func expandMapValues(values map[string]any, env *templates.Env, path string) (map[string]any, error) {
+	for k, v := range values {
+		expanded, err := expandValue(v, env, path+"."+k)
+		if err != nil {
+			return nil, err
+		}
+		values[k] = expanded
+	}
+
+	return values, nil
+}
+
+// expandValue expands placeholders in a value like templates.ExpandValuesRecursively while tracking the path
+// of nested values so errors can name the failing value
+func expandValue(value any, env *templates.Env, path string) (any, error) {
+	switch typed := value.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(typed))
+		for k, v := range typed {
+			expanded, err := expandValue(v, env, path+"."+k)
+			if err != nil {
+				return nil, err
+			}
+			result[k] = expanded
+		}
+		return result, nil
+
+	case []any:
+		result := make([]any, len(typed))
+		for i, v := range typed {
+			expanded, err := expandValue(v, env, fmt.Sprintf("%s.%d", path, i))
+			if err != nil {
+				return nil, err
+			}
+			result[i] = expanded
+		}
+		return result, nil
+
+	default:
+		expanded, err := templates.ExpandValuesRecursively(typed, env)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return expanded, nil
+	}
+}
+
+// lookupLayers orders the base data and matching overrides lowest priority first so lookup() can merge a key
+// across all of them, in first mode earlier overrides have the higher priority
+func lookupLayers(data map[string]any, candidates []map[string]any, mergeMode string) []map[string]any {
+	var layers []map[string]any
+
+	if data != nil {
+		layers = append(layers, data)
+	}
+
+	if mergeMode == "first" {
+		for _, candidate := range slices.Backward(candidates) {
+			layers = append(layers, candidate)
+		}
+	} else {
+		layers = append(layers, candidates...)
+	}
+
+	return layers
+}
+
 // ResolveYaml consumes raw YAML bytes and a map of facts to produce a final data map.
 // The function decodes the YAML document and delegates processing to Resolve to perform merges and fact substitution.
 // Unlike other Resolve functions, ResolveYaml does NOT validate internally - it returns rules in the result
@@ -228,7 +349,7 @@ func ResolveJson(data []byte, facts map[string]any, opts Options, log model.Logg
 }
 
 // ResolveUrl parses a URL and resolves the data using the correct helper.
-// Supported URL schemes: file paths, kv://Bucket/Key, http://, and https://
+// Supported URL schemes: file paths, file:///path, kv://Bucket/Key, http://, and https://
 func ResolveUrl(ctx context.Context, source string, mgr model.Manager, facts map[string]any, opts Options, log model.Logger) (*ResolveResult, error) {
 	if source == "" {
 		return nil, fmt.Errorf("source is required")
@@ -248,6 +369,15 @@ func ResolveUrl(ctx context.Context, source string, mgr model.Manager, facts map
 	case "http", "https":
 		res, err = ResolveHttp(ctx, source, facts, opts, log)
 
+	case "file":
+		var file string
+		file, err = fileUrlPath(uri)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err = ResolveFile(ctx, file, facts, opts, log)
+
 	case "":
 		res, err = ResolveFile(ctx, source, facts, opts, log)
 
@@ -263,6 +393,81 @@ func ResolveUrl(ctx context.Context, source string, mgr model.Manager, facts map
 	return res, nil
 }
 
+// fileUrlPath extracts the local path from a file:// URL, only local files can be referenced
+func fileUrlPath(uri *url.URL) (string, error) {
+	if uri.Host != "" && uri.Host != "localhost" {
+		return "", fmt.Errorf("unsupported host %q in file URL, only local files are supported", uri.Host)
+	}
+
+	if uri.Path == "" {
+		return "", fmt.Errorf("file URL %s requires an absolute path", uri.Redacted())
+	}
+
+	file := uri.Path
+
+	// file:///C:/data.yaml holds a windows drive letter after the leading slash
+	if runtime.GOOS == "windows" && len(file) > 2 && file[0] == '/' && file[2] == ':' {
+		file = file[1:]
+	}
+
+	return filepath.FromSlash(file), nil
+}
+
+// ResolveUrls resolves each source using ResolveUrl and deep merges the results in order with later
+// sources overriding earlier ones, the hierarchy within each source is resolved before merging.
+// Resolving a single source is equivalent to ResolveUrl. Sources prefixed with MissingOkPrefix are
+// skipped when they do not exist, any other error fails resolution.
+func ResolveUrls(ctx context.Context, sources []string, mgr model.Manager, facts map[string]any, opts Options, log model.Logger) (*ResolveResult, error) {
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("at least one source is required")
+	}
+
+	result := &ResolveResult{
+		Data:    map[string]any{},
+		Sources: map[string][]string{},
+	}
+
+	for _, source := range sources {
+		source, missingOk := strings.CutPrefix(source, MissingOkPrefix)
+
+		res, err := ResolveUrl(ctx, source, mgr, facts, opts, log)
+		if err != nil {
+			if missingOk && IsMissingSource(err) {
+				log.Debug("Skipping missing hiera data source", "source", source, "error", err)
+				continue
+			}
+
+			return nil, fmt.Errorf("%s: %w", source, err)
+		}
+
+		if len(sources) == 1 {
+			result.Data = res.Data
+		} else {
+			result.Data = iu.DeepMergeMap(result.Data, res.Data)
+		}
+		result.Rules = append(result.Rules, res.Rules...)
+
+		for key := range res.Data {
+			result.Sources[key] = append(result.Sources[key], source)
+		}
+	}
+
+	if len(sources) > 1 {
+		log.Debug("Merged hiera data sources", "sources", sources, "keys", result.Sources)
+	}
+
+	return result, nil
+}
+
+// IsMissingSource determines if err indicates that a data source does not exist
+func IsMissingSource(err error) bool {
+	return errors.Is(err, ErrFileNotFound) ||
+		errors.Is(err, ErrSourceNotFound) ||
+		errors.Is(err, jetstream.ErrKeyNotFound) ||
+		errors.Is(err, jetstream.ErrKeyDeleted) ||
+		errors.Is(err, jetstream.ErrBucketNotFound)
+}
+
 // ResolveFile reads a YAML or JSON file and delegates to ResolveYaml or ResolveJson respectively.
 func ResolveFile(ctx context.Context, file string, facts map[string]any, opts Options, log model.Logger) (*ResolveResult, error) {
 	abs, err := filepath.Abs(file)
@@ -300,7 +505,8 @@ func ResolveFile(ctx context.Context, file string, facts map[string]any, opts Op
 }
 
 // ResolveHttp fetches hiera data from an HTTP(S) URL and resolves it.
-// Supports JSON and YAML formats. HTTP Basic Authentication is supported via URL credentials.
+// Supports JSON and YAML formats selected by content type or file extension. HTTP Basic Authentication is
+// supported via URL credentials and bearer tokens via Options.BearerToken.
 func ResolveHttp(ctx context.Context, rawUrl string, facts map[string]any, opts Options, log model.Logger) (*ResolveResult, error) {
 	if rawUrl == "" {
 		return nil, fmt.Errorf("URL is required for HTTP hiera data source")
@@ -316,30 +522,51 @@ func ResolveHttp(ctx context.Context, rawUrl string, facts map[string]any, opts
 
 	log.Debug("Getting hiera data from HTTP URL", "url", redactedUrl)
 
-	httpResult, err := iu.HttpGet(ctx, rawUrl, time.Minute)
+	hdrs := http.Header{}
+	if opts.BearerToken != "" {
+		hdrs.Set("Authorization", "Bearer "+opts.BearerToken)
+	}
+
+	timeout := opts.HttpTimeout
+	if timeout <= 0 {
+		timeout = time.Minute
+	}
+
+	resp, cancel, err := iu.HttpGetResponse(ctx, rawUrl, timeout, hdrs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch hiera data from %s: %w", redactedUrl, err)
 	}
+	defer resp.Body.Close()
+	defer cancel()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusGone:
+		return nil, fmt.Errorf("%w: HTTP request to %s failed with status %d: %s", ErrSourceNotFound, redactedUrl, resp.StatusCode, resp.Status)
+	default:
+		return nil, fmt.Errorf("HTTP request to %s failed with status %d: %s", redactedUrl, resp.StatusCode, resp.Status)
+	}
 
-	if httpResult.StatusCode != 200 {
-		return nil, fmt.Errorf("HTTP request to %s failed with status %d: %s", redactedUrl, httpResult.StatusCode, httpResult.Status)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch hiera data from %s: %w", redactedUrl, err)
 	}
 
-	if len(httpResult.Body) == 0 {
+	if len(body) == 0 {
 		return nil, fmt.Errorf("HTTP response from %s is empty", redactedUrl)
 	}
 
 	var rules []ValidationRule
 
 	root := map[string]any{}
-	if iu.IsJsonObject(httpResult.Body) {
-		err = json.Unmarshal(httpResult.Body, &root)
+	if isJsonHttpData(resp.Header.Get("Content-Type"), parsedUrl.Path, body) {
+		err = json.Unmarshal(body, &root)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse JSON from %s: %w", redactedUrl, err)
 		}
 	} else {
 		cm := yaml.CommentMap{}
-		err = yaml.UnmarshalWithOptions(httpResult.Body, &root, yaml.CommentToMap(cm))
+		err = yaml.UnmarshalWithOptions(body, &root, yaml.CommentToMap(cm))
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse YAML from %s: %w", redactedUrl, err)
 		}
@@ -359,6 +586,28 @@ func ResolveHttp(ctx context.Context, rawUrl string, facts map[string]any, opts
 	return &ResolveResult{Data: merged, Rules: rules}, nil
 }
 
+// isJsonHttpData determines if data fetched over HTTP is JSON, a JSON or YAML content type is used first followed by
+// the file extension in the URL, when neither is conclusive the body is inspected
+func isJsonHttpData(contentType string, path string, body []byte) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return true
+	case strings.Contains(mediaType, "yaml"):
+		return false
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return true
+	case ".yaml", ".yml":
+		return false
+	}
+
+	return iu.IsJsonObject(body)
+}
+
 // ResolveKeyValue consumes raw JSON bytes from a KV value and a map of facts to produce a final data map
 // The function decodes the JSON document and delegates processing to Resolve to perform merges and fact substitution.
 func ResolveKeyValue(ctx context.Context, mgr model.Manager, bucket string, key string, facts map[string]any, opts Options, log model.Logger) (*ResolveResult, error) {
@@ -369,19 +618,19 @@ func ResolveKeyValue(ctx context.Context, mgr model.Manager, bucket string, key
 		return nil, fmt.Errorf("key is required for kv hiera data source")
 	}
 
-	js, err := mgr.JetStream()
-	if err != nil {
-		return nil, err
-	}
-
 	log.Debug("Getting hiera data from JetStream KV", "key", key, "bucket", bucket)
 
-	kv, err := js.KeyValue(ctx, bucket)
-	if err != nil {
-		return nil, err
-	}
+	var entry jetstream.KeyValueEntry
+	err := mgr.JetStreamCall(ctx, func(ctx context.Context, js jetstream.JetStream) error {
+		kv, err := js.KeyValue(ctx, bucket)
+		if err != nil {
+			return err
+		}
+
+		entry, err = kv.Get(ctx, key)
 
-	entry, err := kv.Get(ctx, key)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
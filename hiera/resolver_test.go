@@ -11,7 +11,10 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/nats-io/nats.go/jetstream"
 	. "github.com/onsi/ginkgo/v2"
@@ -297,6 +300,125 @@ var _ = Describe("Resolve", func() {
 		}))
 	})
 
+	It("Should support lookup options in values", func() {
+		data := map[string]any{
+			"hierarchy": map[string]any{
+				"order": []any{"role:{{ lookup('facts.role') }}", "env:{{ lookup('facts.env') }}"},
+				"merge": "deep",
+			},
+			"data": map[string]any{
+				"packages":     []any{"zsh", "vim"},
+				"all_packages": "{{ lookup('data.packages', {merge: 'unique'}) }}",
+				"extra":        "{{ lookup('data.extra_packages', {default: [], merge: 'unique'}) }}",
+			},
+			"overrides": map[string]any{
+				"role:web": map[string]any{
+					"packages": []any{"vim", "nginx"},
+				},
+				"env:prod": map[string]any{
+					"packages": []any{"nginx", "audit"},
+				},
+			},
+		}
+
+		facts := map[string]any{"role": "web", "env": "prod"}
+
+		result, err := Resolve(data, facts, DefaultOptions, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(map[string]any{
+			"packages":     []any{"zsh", "vim", "vim", "nginx", "nginx", "audit"},
+			"all_packages": []any{"zsh", "vim", "nginx", "audit"},
+			"extra":        []any{},
+		}))
+	})
+
+	It("Should give earlier overrides priority in lookups using first merge mode", func() {
+		data := map[string]any{
+			"hierarchy": map[string]any{
+				"order": []any{"role:{{ lookup('facts.role') }}", "env:{{ lookup('facts.env') }}"},
+				"merge": "first",
+			},
+			"data": map[string]any{
+				"port":   80,
+				"listen": "{{ lookup('data.port') }}",
+			},
+			"overrides": map[string]any{
+				"role:web": map[string]any{
+					"port": 443,
+				},
+				"env:prod": map[string]any{
+					"port": 8080,
+				},
+			},
+		}
+
+		facts := map[string]any{"role": "web", "env": "prod"}
+
+		result, err := Resolve(data, facts, DefaultOptions, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(map[string]any{
+			"port":   443,
+			"listen": int64(443),
+		}))
+	})
+
+	Describe("Strict mode", func() {
+		document := func() map[string]any {
+			return map[string]any{
+				"hierarchy": map[string]any{
+					"order": []any{"role:{{ lookup('facts.role') }}", "env:{{ lookup('facts.env') }}"},
+					"merge": "deep",
+				},
+				"data": map[string]any{
+					"name": "app",
+				},
+				"overrides": map[string]any{
+					"role:web": map[string]any{
+						"servers": []any{"{{ lookup('facts.hostname') }}", "{{ lookup('facts.peer') }}"},
+					},
+				},
+			}
+		}
+
+		facts := map[string]any{"role": "web", "hostname": "web1"}
+
+		It("Should substitute an empty value for missing keys by default", func() {
+			result, err := Resolve(document(), facts, DefaultOptions, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(map[string]any{
+				"name":    "app",
+				"servers": []any{"web1", ""},
+			}))
+		})
+
+		It("Should fail naming the key and value for missing keys", func() {
+			_, err := Resolve(document(), facts, Options{Strict: true}, nil)
+			Expect(err).To(MatchError(ContainSubstring("overrides.role:web.servers.1: ")))
+			Expect(err).To(MatchError(ContainSubstring("missing key 'facts.peer' in environment")))
+		})
+
+		It("Should use defaults in strict mode", func() {
+			doc := document()
+			doc["overrides"].(map[string]any)["role:web"].(map[string]any)["servers"] = []any{"{{ lookup('facts.peer', 'none') }}"}
+
+			result, err := Resolve(doc, facts, Options{Strict: true}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result["servers"]).To(Equal([]any{"none"}))
+		})
+
+		It("Should fail for lazily resolved keys", func() {
+			lazy, err := NewLazyData(document(), facts, Options{Strict: true}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, err = lazy.Lookup("name")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, err = lazy.Lookup("servers")
+			Expect(err).To(MatchError(ContainSubstring("overrides.role:web.servers.1: ")))
+			Expect(err).To(MatchError(ContainSubstring("missing key 'facts.peer' in environment")))
+		})
+	})
+
 	It("processes an already parsed map without mutating input", func() {
 		data := map[string]any{
 			"hierarchy": map[string]any{
@@ -426,14 +548,14 @@ var _ = Describe("ResolveKeyValue", func() {
 
 	It("returns an error when JetStream fails", func() {
 		jsErr := errors.New("jetstream unavailable")
-		mockMgr.EXPECT().JetStream().Return(nil, jsErr)
+		mockMgr.EXPECT().JetStreamCall(gomock.Any(), gomock.Any()).Return(jsErr)
 
 		_, err := ResolveKeyValue(ctx, mockMgr, "bucket", "key", nil, DefaultOptions, mockLog)
 		Expect(err).To(MatchError(jsErr))
 	})
 
 	It("returns an error when bucket does not exist", func() {
-		mockMgr.EXPECT().JetStream().Return(mockJS, nil)
+		mockMgr.EXPECT().JetStreamCall(gomock.Any(), gomock.Any()).DoAndReturn(modelmocks.JetStreamCallWith(mockJS))
 		mockJS.EXPECT().KeyValue(ctx, "missing-bucket").Return(nil, jetstream.ErrBucketNotFound)
 
 		_, err := ResolveKeyValue(ctx, mockMgr, "missing-bucket", "key", nil, DefaultOptions, mockLog)
@@ -441,7 +563,7 @@ var _ = Describe("ResolveKeyValue", func() {
 	})
 
 	It("returns an error when key does not exist", func() {
-		mockMgr.EXPECT().JetStream().Return(mockJS, nil)
+		mockMgr.EXPECT().JetStreamCall(gomock.Any(), gomock.Any()).DoAndReturn(modelmocks.JetStreamCallWith(mockJS))
 		mockJS.EXPECT().KeyValue(ctx, "bucket").Return(mockKV, nil)
 		mockKV.EXPECT().Get(ctx, "missing-key").Return(nil, jetstream.ErrKeyNotFound)
 
@@ -453,7 +575,7 @@ var _ = Describe("ResolveKeyValue", func() {
 		mockEntry := modelmocks.NewMockKeyValueEntry(ctrl)
 		mockEntry.EXPECT().Operation().Return(jetstream.KeyValueDelete)
 
-		mockMgr.EXPECT().JetStream().Return(mockJS, nil)
+		mockMgr.EXPECT().JetStreamCall(gomock.Any(), gomock.Any()).DoAndReturn(modelmocks.JetStreamCallWith(mockJS))
 		mockJS.EXPECT().KeyValue(ctx, "bucket").Return(mockKV, nil)
 		mockKV.EXPECT().Get(ctx, "deleted-key").Return(mockEntry, nil)
 
@@ -466,7 +588,7 @@ var _ = Describe("ResolveKeyValue", func() {
 		mockEntry.EXPECT().Operation().Return(jetstream.KeyValuePut)
 		mockEntry.EXPECT().Value().Return([]byte{})
 
-		mockMgr.EXPECT().JetStream().Return(mockJS, nil)
+		mockMgr.EXPECT().JetStreamCall(gomock.Any(), gomock.Any()).DoAndReturn(modelmocks.JetStreamCallWith(mockJS))
 		mockJS.EXPECT().KeyValue(ctx, "bucket").Return(mockKV, nil)
 		mockKV.EXPECT().Get(ctx, "empty-key").Return(mockEntry, nil)
 
@@ -479,7 +601,7 @@ var _ = Describe("ResolveKeyValue", func() {
 		mockEntry.EXPECT().Operation().Return(jetstream.KeyValuePut)
 		mockEntry.EXPECT().Value().Return([]byte(`{invalid json`))
 
-		mockMgr.EXPECT().JetStream().Return(mockJS, nil)
+		mockMgr.EXPECT().JetStreamCall(gomock.Any(), gomock.Any()).DoAndReturn(modelmocks.JetStreamCallWith(mockJS))
 		mockJS.EXPECT().KeyValue(ctx, "bucket").Return(mockKV, nil)
 		mockKV.EXPECT().Get(ctx, "bad-json").Return(mockEntry, nil)
 
@@ -504,7 +626,7 @@ var _ = Describe("ResolveKeyValue", func() {
 		mockEntry.EXPECT().Operation().Return(jetstream.KeyValuePut)
 		mockEntry.EXPECT().Value().Return(jsonData)
 
-		mockMgr.EXPECT().JetStream().Return(mockJS, nil)
+		mockMgr.EXPECT().JetStreamCall(gomock.Any(), gomock.Any()).DoAndReturn(modelmocks.JetStreamCallWith(mockJS))
 		mockJS.EXPECT().KeyValue(ctx, "config").Return(mockKV, nil)
 		mockKV.EXPECT().Get(ctx, "app.settings").Return(mockEntry, nil)
 
@@ -531,7 +653,7 @@ data:
 		mockEntry.EXPECT().Operation().Return(jetstream.KeyValuePut)
 		mockEntry.EXPECT().Value().Return(yamlData)
 
-		mockMgr.EXPECT().JetStream().Return(mockJS, nil)
+		mockMgr.EXPECT().JetStreamCall(gomock.Any(), gomock.Any()).DoAndReturn(modelmocks.JetStreamCallWith(mockJS))
 		mockJS.EXPECT().KeyValue(ctx, "config").Return(mockKV, nil)
 		mockKV.EXPECT().Get(ctx, "app.yaml").Return(mockEntry, nil)
 
@@ -565,7 +687,7 @@ data:
 		mockEntry.EXPECT().Operation().Return(jetstream.KeyValuePut)
 		mockEntry.EXPECT().Value().Return(jsonData)
 
-		mockMgr.EXPECT().JetStream().Return(mockJS, nil)
+		mockMgr.EXPECT().JetStreamCall(gomock.Any(), gomock.Any()).DoAndReturn(modelmocks.JetStreamCallWith(mockJS))
 		mockJS.EXPECT().KeyValue(ctx, "config").Return(mockKV, nil)
 		mockKV.EXPECT().Get(ctx, "app.config").Return(mockEntry, nil)
 
@@ -580,7 +702,7 @@ data:
 
 	It("handles KeyValue access errors", func() {
 		accessErr := errors.New("connection timeout")
-		mockMgr.EXPECT().JetStream().Return(mockJS, nil)
+		mockMgr.EXPECT().JetStreamCall(gomock.Any(), gomock.Any()).DoAndReturn(modelmocks.JetStreamCallWith(mockJS))
 		mockJS.EXPECT().KeyValue(ctx, "bucket").Return(nil, accessErr)
 
 		_, err := ResolveKeyValue(ctx, mockMgr, "bucket", "key", nil, DefaultOptions, mockLog)
@@ -589,7 +711,7 @@ data:
 
 	It("handles Get errors", func() {
 		getErr := errors.New("read timeout")
-		mockMgr.EXPECT().JetStream().Return(mockJS, nil)
+		mockMgr.EXPECT().JetStreamCall(gomock.Any(), gomock.Any()).DoAndReturn(modelmocks.JetStreamCallWith(mockJS))
 		mockJS.EXPECT().KeyValue(ctx, "bucket").Return(mockKV, nil)
 		mockKV.EXPECT().Get(ctx, "key").Return(nil, getErr)
 
@@ -612,7 +734,7 @@ data:
 		mockEntry.EXPECT().Operation().Return(jetstream.KeyValuePut)
 		mockEntry.EXPECT().Value().Return(yamlData)
 
-		mockMgr.EXPECT().JetStream().Return(mockJS, nil)
+		mockMgr.EXPECT().JetStreamCall(gomock.Any(), gomock.Any()).DoAndReturn(modelmocks.JetStreamCallWith(mockJS))
 		mockJS.EXPECT().KeyValue(ctx, "bucket").Return(mockKV, nil)
 		mockKV.EXPECT().Get(ctx, "key").Return(mockEntry, nil)
 		mockLog.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
@@ -633,7 +755,7 @@ data:
 		mockEntry.EXPECT().Operation().Return(jetstream.KeyValuePut)
 		mockEntry.EXPECT().Value().Return(jsonData)
 
-		mockMgr.EXPECT().JetStream().Return(mockJS, nil)
+		mockMgr.EXPECT().JetStreamCall(gomock.Any(), gomock.Any()).DoAndReturn(modelmocks.JetStreamCallWith(mockJS))
 		mockJS.EXPECT().KeyValue(ctx, "bucket").Return(mockKV, nil)
 		mockKV.EXPECT().Get(ctx, "key").Return(mockEntry, nil)
 
@@ -657,7 +779,7 @@ data:
 		mockEntry.EXPECT().Operation().Return(jetstream.KeyValuePut)
 		mockEntry.EXPECT().Value().Return(yamlData)
 
-		mockMgr.EXPECT().JetStream().Return(mockJS, nil)
+		mockMgr.EXPECT().JetStreamCall(gomock.Any(), gomock.Any()).DoAndReturn(modelmocks.JetStreamCallWith(mockJS))
 		mockJS.EXPECT().KeyValue(ctx, "bucket").Return(mockKV, nil)
 		mockKV.EXPECT().Get(ctx, "key").Return(mockEntry, nil)
 		mockLog.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
@@ -723,7 +845,7 @@ var _ = Describe("ResolveUrl", func() {
 		mockEntry.EXPECT().Operation().Return(jetstream.KeyValuePut)
 		mockEntry.EXPECT().Value().Return(jsonData)
 
-		mockMgr.EXPECT().JetStream().Return(mockJS, nil)
+		mockMgr.EXPECT().JetStreamCall(gomock.Any(), gomock.Any()).DoAndReturn(modelmocks.JetStreamCallWith(mockJS))
 		mockJS.EXPECT().KeyValue(ctx, "mybucket").Return(mockKV, nil)
 		mockKV.EXPECT().Get(ctx, "mykey").Return(mockEntry, nil)
 
@@ -733,6 +855,195 @@ var _ = Describe("ResolveUrl", func() {
 			"setting": "value",
 		}))
 	})
+
+	It("resolves file:// URLs", func() {
+		dir := GinkgoT().TempDir()
+
+		yamlFile := filepath.Join(dir, "data.yaml")
+		Expect(os.WriteFile(yamlFile, []byte("hierarchy:\n  order:\n    - role:{{ lookup('facts.role') }}\ndata:\n  setting: yaml\noverrides:\n  role:web:\n    setting: web\n"), 0600)).To(Succeed())
+
+		jsonFile := filepath.Join(dir, "data.json")
+		Expect(os.WriteFile(jsonFile, []byte(`{"data": {"setting": "json"}}`), 0600)).To(Succeed())
+
+		fileUrl := func(path string) string {
+			path = filepath.ToSlash(path)
+			if !strings.HasPrefix(path, "/") {
+				path = "/" + path
+			}
+
+			return (&url.URL{Scheme: "file", Path: path}).String()
+		}
+
+		result, err := ResolveUrl(ctx, fileUrl(yamlFile), mockMgr, map[string]any{"role": "web"}, DefaultOptions, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Data).To(Equal(map[string]any{"setting": "web"}))
+
+		result, err = ResolveUrl(ctx, fileUrl(jsonFile), mockMgr, map[string]any{}, DefaultOptions, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Data).To(Equal(map[string]any{"setting": "json"}))
+
+		result, err = ResolveUrl(ctx, strings.Replace(fileUrl(jsonFile), "file://", "file://localhost", 1), mockMgr, map[string]any{}, DefaultOptions, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Data).To(Equal(map[string]any{"setting": "json"}))
+
+		_, err = ResolveUrl(ctx, fileUrl(filepath.Join(dir, "missing.yaml")), mockMgr, map[string]any{}, DefaultOptions, mockLog)
+		Expect(err).To(MatchError(ErrFileNotFound))
+	})
+
+	It("rejects file:// URLs that are not local", func() {
+		_, err := ResolveUrl(ctx, "file://example.net/data.yaml", mockMgr, nil, DefaultOptions, mockLog)
+		Expect(err).To(MatchError(`unsupported host "example.net" in file URL, only local files are supported`))
+
+		_, err = ResolveUrl(ctx, "file:data.yaml", mockMgr, nil, DefaultOptions, mockLog)
+		Expect(err).To(MatchError("file URL file:data.yaml requires an absolute path"))
+	})
+})
+
+var _ = Describe("ResolveUrls", func() {
+	var (
+		ctrl    *gomock.Controller
+		mockLog *modelmocks.MockLogger
+		ctx     context.Context
+		tempDir string
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockLog = modelmocks.NewMockLogger(ctrl)
+		ctx = context.Background()
+		tempDir = GinkgoT().TempDir()
+
+		mockLog.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	})
+
+	write := func(name string, content string) string {
+		path := filepath.Join(tempDir, name)
+		Expect(os.WriteFile(path, []byte(content), 0600)).To(Succeed())
+		return path
+	}
+
+	It("requires at least one source", func() {
+		_, err := ResolveUrls(ctx, nil, nil, nil, DefaultOptions, mockLog)
+		Expect(err).To(MatchError("at least one source is required"))
+	})
+
+	It("behaves like ResolveUrl for a single source", func() {
+		file := write("single.yaml", `
+hierarchy:
+  order:
+    - env:{{ lookup('facts.env') }}
+data:
+  log_level: info
+overrides:
+  env:prod:
+    log_level: warn
+`)
+		single, err := ResolveUrl(ctx, file, nil, map[string]any{"env": "prod"}, DefaultOptions, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+
+		res, err := ResolveUrls(ctx, []string{file}, nil, map[string]any{"env": "prod"}, DefaultOptions, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Data).To(Equal(single.Data))
+		Expect(res.Data).To(Equal(map[string]any{"log_level": "warn"}))
+		Expect(res.Sources).To(Equal(map[string][]string{"log_level": {file}}))
+	})
+
+	It("deep merges sources in order", func() {
+		global := write("global.yaml", `
+data:
+  log_level: info
+  packages:
+    - zsh
+  settings:
+    port: 80
+    tls: false
+`)
+		site := write("site.json", `{"data": {"settings": {"tls": true}, "site": "lon"}}`)
+		node := write("node.yaml", `
+hierarchy:
+  order:
+    - host:{{ lookup('facts.hostname') }}
+data:
+  packages:
+    - vim
+overrides:
+  host:web1:
+    log_level: debug
+`)
+
+		res, err := ResolveUrls(ctx, []string{global, site, node}, nil, map[string]any{"hostname": "web1"}, DefaultOptions, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Data).To(Equal(map[string]any{
+			"log_level": "debug",
+			"packages":  []any{"zsh", "vim"},
+			"settings":  map[string]any{"port": 80, "tls": true},
+			"site":      "lon",
+		}))
+		Expect(res.Sources).To(Equal(map[string][]string{
+			"log_level": {global, node},
+			"packages":  {global, node},
+			"settings":  {global, site},
+			"site":      {site},
+		}))
+	})
+
+	It("merges file and kv sources with the kv source winning", func() {
+		mockMgr := modelmocks.NewMockManager(ctrl)
+		mockJS := modelmocks.NewMockJetStream(ctrl)
+		mockKV := modelmocks.NewMockKeyValue(ctrl)
+		mockEntry := modelmocks.NewMockKeyValueEntry(ctrl)
+
+		global := write("global.yaml", "data:\n  log_level: info\n  port: 80\n")
+
+		mockEntry.EXPECT().Operation().Return(jetstream.KeyValuePut)
+		mockEntry.EXPECT().Value().Return([]byte(`{"data": {"log_level": "debug"}}`))
+		mockMgr.EXPECT().JetStreamCall(gomock.Any(), gomock.Any()).DoAndReturn(modelmocks.JetStreamCallWith(mockJS))
+		mockJS.EXPECT().KeyValue(ctx, "CCM").Return(mockKV, nil)
+		mockKV.EXPECT().Get(ctx, "prod").Return(mockEntry, nil)
+
+		res, err := ResolveUrls(ctx, []string{global, "kv://CCM/prod"}, mockMgr, nil, DefaultOptions, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Data).To(Equal(map[string]any{"log_level": "debug", "port": 80}))
+		Expect(res.Sources).To(Equal(map[string][]string{
+			"log_level": {global, "kv://CCM/prod"},
+			"port":      {global},
+		}))
+	})
+
+	It("skips missing sources marked missing-ok", func() {
+		mockMgr := modelmocks.NewMockManager(ctrl)
+		mockJS := modelmocks.NewMockJetStream(ctrl)
+		mockKV := modelmocks.NewMockKeyValue(ctrl)
+
+		global := write("global.yaml", "data:\n  a: 1\n")
+		missing := filepath.Join(tempDir, "missing.yaml")
+
+		mockMgr.EXPECT().JetStreamCall(gomock.Any(), gomock.Any()).DoAndReturn(modelmocks.JetStreamCallWith(mockJS))
+		mockJS.EXPECT().KeyValue(ctx, "CCM").Return(mockKV, nil)
+		mockKV.EXPECT().Get(ctx, "node").Return(nil, jetstream.ErrKeyNotFound)
+
+		res, err := ResolveUrls(ctx, []string{global, MissingOkPrefix + missing, MissingOkPrefix + "kv://CCM/node"}, mockMgr, nil, DefaultOptions, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Data).To(Equal(map[string]any{"a": 1}))
+		Expect(res.Sources).To(Equal(map[string][]string{"a": {global}}))
+	})
+
+	It("fails for missing-ok sources that exist but cannot be resolved", func() {
+		invalid := write("invalid.yaml", "invalid:\n  yaml:\n bad indentation")
+
+		_, err := ResolveUrls(ctx, []string{MissingOkPrefix + invalid}, nil, nil, DefaultOptions, mockLog)
+		Expect(err).To(MatchError(ContainSubstring("failed to parse YAML")))
+		Expect(err.Error()).To(HavePrefix(invalid + ": "))
+	})
+
+	It("fails when any source fails", func() {
+		global := write("global.yaml", "data:\n  a: 1\n")
+		missing := filepath.Join(tempDir, "missing.yaml")
+
+		_, err := ResolveUrls(ctx, []string{global, missing}, nil, nil, DefaultOptions, mockLog)
+		Expect(err).To(MatchError(ErrFileNotFound))
+		Expect(err.Error()).To(HavePrefix(missing + ": "))
+	})
 })
 
 var _ = Describe("ResolveFile", func() {
@@ -1048,6 +1359,89 @@ data:
 		Expect(err.Error()).To(ContainSubstring("status 404"))
 	})
 
+	It("reports missing data as a missing source", func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+
+		_, err := ResolveHttp(ctx, server.URL+"/notfound", map[string]any{}, DefaultOptions, mockLog)
+		Expect(err).To(MatchError(ErrSourceNotFound))
+		Expect(IsMissingSource(err)).To(BeTrue())
+	})
+
+	It("sends the bearer token from the options", func() {
+		var receivedAuth string
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			receivedAuth = r.Header.Get("Authorization")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"data": {"key": "value"}}`))
+		}))
+
+		opts := DefaultOptions
+		opts.BearerToken = "s3cret"
+
+		_, err := ResolveHttp(ctx, server.URL+"/config.json", map[string]any{}, opts, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(receivedAuth).To(Equal("Bearer s3cret"))
+	})
+
+	It("fails when the request takes longer than the timeout", func() {
+		done := make(chan struct{})
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-done:
+			case <-r.Context().Done():
+			}
+		}))
+		defer close(done)
+
+		opts := DefaultOptions
+		opts.HttpTimeout = 50 * time.Millisecond
+
+		_, err := ResolveHttp(ctx, server.URL+"/config.json", map[string]any{}, opts, mockLog)
+		Expect(err).To(MatchError(ContainSubstring("failed to fetch hiera data")))
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+	})
+
+	It("parses YAML based on the content type", func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/yaml; charset=utf-8")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{data: {key: value}}`))
+		}))
+
+		result, err := ResolveHttp(ctx, server.URL+"/config", map[string]any{}, DefaultOptions, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Data).To(Equal(map[string]any{"key": "value"}))
+	})
+
+	It("parses data based on the extension when the content type is not conclusive", func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{data: {key: value}}`))
+		}))
+
+		result, err := ResolveHttp(ctx, server.URL+"/config.yml", map[string]any{}, DefaultOptions, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Data).To(Equal(map[string]any{"key": "value"}))
+
+		_, err = ResolveHttp(ctx, server.URL+"/config.json", map[string]any{}, DefaultOptions, mockLog)
+		Expect(err).To(MatchError(ContainSubstring("failed to parse JSON")))
+	})
+
+	It("is used by ResolveUrl for http sources", func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"hierarchy": {"order": ["role:{{ lookup('facts.role') }}"]}, "data": {"key": "value"}, "overrides": {"role:web": {"key": "web"}}}`))
+		}))
+
+		result, err := ResolveUrl(ctx, server.URL+"/config", nil, map[string]any{"role": "web"}, DefaultOptions, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Data).To(Equal(map[string]any{"key": "web"}))
+	})
+
 	It("returns error for empty response body", func() {
 		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusOK)
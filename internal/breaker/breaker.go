@@ -0,0 +1,109 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package breaker implements a simple circuit breaker that stops calls to a failing dependency for a cooldown period
+package breaker
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrOpen indicates the breaker is open and calls are not attempted
+var ErrOpen = errors.New("circuit breaker is open")
+
+// Breaker counts consecutive failures and opens once a threshold is reached. While open every call fails fast
+// until the cooldown passed, a single trial call is then allowed, success closes the breaker while failure opens
+// it for another cooldown.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	trial     bool
+	now       func() time.Time
+	mu        sync.Mutex
+}
+
+// New creates a breaker that opens after threshold consecutive failures for cooldown, a threshold of 0 or less
+// disables the breaker
+func New(threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Allow reports if a call may be attempted, an error wrapping ErrOpen is returned while the breaker is open
+func (b *Breaker) Allow() error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.threshold <= 0 || b.failures < b.threshold {
+		return nil
+	}
+
+	now := b.now()
+	if now.Before(b.openUntil) {
+		return fmt.Errorf("%w after %d consecutive failures, retrying in %v", ErrOpen, b.failures, b.openUntil.Sub(now).Round(time.Second))
+	}
+
+	if b.trial {
+		return fmt.Errorf("%w after %d consecutive failures, a trial call is in progress", ErrOpen, b.failures)
+	}
+
+	b.trial = true
+
+	return nil
+}
+
+// Success records a successful call and closes the breaker
+func (b *Breaker) Success() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.trial = false
+	b.openUntil = time.Time{}
+}
+
+// Failure records a failed call, opening the breaker once the threshold is reached
+func (b *Breaker) Failure() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.trial = false
+
+	if b.threshold > 0 && b.failures >= b.threshold {
+		b.openUntil = b.now().Add(b.cooldown)
+	}
+}
+
+// IsOpen reports if calls are currently being rejected
+func (b *Breaker) IsOpen() bool {
+	if b == nil {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.threshold > 0 && b.failures >= b.threshold && (b.now().Before(b.openUntil) || b.trial)
+}
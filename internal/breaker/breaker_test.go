@@ -0,0 +1,101 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestBreaker(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Internal/Breaker")
+}
+
+var _ = Describe("Breaker", func() {
+	var (
+		b   *Breaker
+		now time.Time
+	)
+
+	BeforeEach(func() {
+		now = time.Now()
+		b = New(3, time.Minute)
+		b.now = func() time.Time { return now }
+	})
+
+	It("Should open after the threshold is reached", func() {
+		for range 2 {
+			Expect(b.Allow()).To(Succeed())
+			b.Failure()
+		}
+		Expect(b.IsOpen()).To(BeFalse())
+
+		Expect(b.Allow()).To(Succeed())
+		b.Failure()
+		Expect(b.IsOpen()).To(BeTrue())
+
+		err := b.Allow()
+		Expect(errors.Is(err, ErrOpen)).To(BeTrue())
+		Expect(err).To(MatchError("circuit breaker is open after 3 consecutive failures, retrying in 1m0s"))
+	})
+
+	It("Should reset the failure count on success", func() {
+		b.Failure()
+		b.Failure()
+		b.Success()
+		b.Failure()
+		b.Failure()
+
+		Expect(b.Allow()).To(Succeed())
+		Expect(b.IsOpen()).To(BeFalse())
+	})
+
+	It("Should allow a single trial call after the cooldown", func() {
+		for range 3 {
+			b.Failure()
+		}
+		Expect(b.Allow()).To(MatchError(ErrOpen))
+
+		now = now.Add(time.Minute)
+		Expect(b.Allow()).To(Succeed())
+		Expect(b.Allow()).To(MatchError(ContainSubstring("a trial call is in progress")))
+
+		b.Success()
+		Expect(b.IsOpen()).To(BeFalse())
+		Expect(b.Allow()).To(Succeed())
+	})
+
+	It("Should open again when the trial call fails", func() {
+		for range 3 {
+			b.Failure()
+		}
+
+		now = now.Add(time.Minute)
+		Expect(b.Allow()).To(Succeed())
+		b.Failure()
+
+		Expect(b.Allow()).To(MatchError(ContainSubstring("4 consecutive failures, retrying in 1m0s")))
+	})
+
+	It("Should support being disabled", func() {
+		b = New(0, time.Minute)
+		for range 10 {
+			b.Failure()
+		}
+		Expect(b.Allow()).To(Succeed())
+		Expect(b.IsOpen()).To(BeFalse())
+
+		var nb *Breaker
+		Expect(nb.Allow()).To(Succeed())
+		nb.Failure()
+		nb.Success()
+		Expect(nb.IsOpen()).To(BeFalse())
+	})
+})
@@ -0,0 +1,43 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmdrunner
+
+import (
+	"context"
+
+	"github.com/choria-io/ccm/model"
+)
+
+// LocaleRunner is a CommandRunner that runs commands under a specific locale and timezone using another runner,
+// commands that request their own locale or timezone keep it
+type LocaleRunner struct {
+	runner   model.CommandRunner
+	locale   string
+	timezone string
+}
+
+var _ model.CommandRunner = (*LocaleRunner)(nil)
+
+// NewLocaleRunner creates a runner that runs commands using runner under locale and timezone, empty values keep the defaults
+func NewLocaleRunner(runner model.CommandRunner, locale string, timezone string) *LocaleRunner {
+	return &LocaleRunner{runner: runner, locale: locale, timezone: timezone}
+}
+
+// Execute runs a command with the given arguments under the configured locale and timezone
+func (r *LocaleRunner) Execute(ctx context.Context, command string, args ...string) ([]byte, []byte, int, error) {
+	return r.ExecuteWithOptions(ctx, model.ExtendedExecOptions{Command: command, Args: args})
+}
+
+// ExecuteWithOptions runs a command under the configured locale and timezone
+func (r *LocaleRunner) ExecuteWithOptions(ctx context.Context, opts model.ExtendedExecOptions) ([]byte, []byte, int, error) {
+	if opts.Locale == "" {
+		opts.Locale = r.locale
+	}
+	if opts.Timezone == "" {
+		opts.Timezone = r.timezone
+	}
+
+	return r.runner.ExecuteWithOptions(ctx, opts)
+}
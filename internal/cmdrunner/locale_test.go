@@ -0,0 +1,29 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmdrunner
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	"go.uber.org/mock/gomock"
+
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/model/modelmocks"
+)
+
+var _ = Describe("LocaleRunner", func() {
+	It("Should set the locale and timezone unless requested by the command", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		runner := modelmocks.NewMockCommandRunner(ctrl)
+		locale := NewLocaleRunner(runner, "en_US.UTF-8", "UTC")
+
+		runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/bin/date", Locale: "en_US.UTF-8", Timezone: "UTC"})
+		locale.Execute(context.Background(), "/bin/date")
+
+		runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/bin/date", Locale: "C", Timezone: "UTC"})
+		locale.ExecuteWithOptions(context.Background(), model.ExtendedExecOptions{Command: "/bin/date", Locale: "C"})
+	})
+})
@@ -0,0 +1,294 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmdrunner
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/choria-io/ccm/model"
+)
+
+// ErrCommandNotRecorded indicates a replayed run executed a command the recording does not hold
+var ErrCommandNotRecorded = errors.New("command not found in recording")
+
+// RecordedCommand is a single command execution held in a recording, sensitive values are redacted
+type RecordedCommand struct {
+	Command     string   `json:"command"`
+	Args        []string `json:"args,omitempty"`
+	Cwd         string   `json:"cwd,omitempty"`
+	Environment []string `json:"environment,omitempty"`
+	Path        string   `json:"path,omitempty"`
+	User        string   `json:"user,omitempty"`
+	Stdout      string   `json:"stdout,omitempty"`
+	Stderr      string   `json:"stderr,omitempty"`
+	ExitCode    int      `json:"exit_code"`
+	Error       string   `json:"error,omitempty"`
+	NotFound    bool     `json:"not_found,omitempty"`
+}
+
+// matches reports if c was recorded for the same request as other
+func (c *RecordedCommand) matches(other *RecordedCommand) bool {
+	return c.Command == other.Command &&
+		slices.Equal(c.Args, other.Args) &&
+		c.Cwd == other.Cwd &&
+		slices.Equal(c.Environment, other.Environment) &&
+		c.Path == other.Path &&
+		c.User == other.User
+}
+
+// result returns the recorded outcome in the form CommandRunner returns it
+func (c *RecordedCommand) result() ([]byte, []byte, int, error) {
+	var err error
+	switch {
+	case c.NotFound:
+		err = fmt.Errorf("%w: %s", model.ErrExecutableNotFound, c.Command)
+	case c.Error != "":
+		err = errors.New(c.Error)
+	}
+
+	return []byte(c.Stdout), []byte(c.Stderr), c.ExitCode, err
+}
+
+// redactor replaces sensitive values in recorded commands
+type redactor struct {
+	values []string
+	mu     sync.Mutex
+}
+
+// Redact adds values that should never be stored in a recording
+func (r *redactor) Redact(values ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, v := range values {
+		if v != "" && !slices.Contains(r.values, v) {
+			r.values = append(r.values, v)
+		}
+	}
+
+	// longer values first so values containing others are fully redacted
+	slices.SortFunc(r.values, func(a, b string) int { return len(b) - len(a) })
+}
+
+func (r *redactor) redact(s string) string {
+	for _, v := range r.values {
+		s = strings.ReplaceAll(s, v, model.RedactedValue)
+	}
+
+	return s
+}
+
+// request builds the redacted recorded form of opts, environment values are always redacted as they often hold secrets
+func (r *redactor) request(opts model.ExtendedExecOptions) *RecordedCommand {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cmd := &RecordedCommand{
+		Command: r.redact(opts.Command),
+		Cwd:     r.redact(opts.Cwd),
+		Path:    r.redact(opts.Path),
+		User:    opts.User,
+	}
+
+	for _, arg := range opts.Args {
+		cmd.Args = append(cmd.Args, r.redact(arg))
+	}
+
+	for _, env := range opts.Environment {
+		name, _, _ := strings.Cut(env, "=")
+		cmd.Environment = append(cmd.Environment, name+"="+model.RedactedValue)
+	}
+
+	return cmd
+}
+
+// response redacts and stores the outcome of a command in cmd
+func (r *redactor) response(cmd *RecordedCommand, stdout []byte, stderr []byte, exitCode int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cmd.Stdout = r.redact(string(stdout))
+	cmd.Stderr = r.redact(string(stderr))
+	cmd.ExitCode = exitCode
+
+	if err != nil {
+		cmd.NotFound = errors.Is(err, model.ErrExecutableNotFound)
+		cmd.Error = r.redact(err.Error())
+	}
+}
+
+// Recorder is a CommandRunner that executes commands using another runner and records every execution
+// to a file as JSON lines, the recording can be replayed using a Replayer
+type Recorder struct {
+	runner model.CommandRunner
+	file   *os.File
+	redactor
+
+	mu sync.Mutex
+}
+
+var _ model.RedactingCommandRunner = (*Recorder)(nil)
+
+// NewRecorder creates a recorder that executes commands using runner and records them in file, any
+// existing file is truncated
+func NewRecorder(runner model.CommandRunner, file string) (*Recorder, error) {
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("could not open recording: %w", err)
+	}
+
+	return &Recorder{runner: runner, file: f}, nil
+}
+
+// Execute runs a command using the wrapped runner and records it
+func (r *Recorder) Execute(ctx context.Context, command string, args ...string) ([]byte, []byte, int, error) {
+	stdout, stderr, exitCode, err := r.runner.Execute(ctx, command, args...)
+	r.record(model.ExtendedExecOptions{Command: command, Args: args}, stdout, stderr, exitCode, err)
+
+	return stdout, stderr, exitCode, err
+}
+
+// ExecuteWithOptions runs a command using the wrapped runner and records it
+func (r *Recorder) ExecuteWithOptions(ctx context.Context, opts model.ExtendedExecOptions) ([]byte, []byte, int, error) {
+	stdout, stderr, exitCode, err := r.runner.ExecuteWithOptions(ctx, opts)
+	r.record(opts, stdout, stderr, exitCode, err)
+
+	return stdout, stderr, exitCode, err
+}
+
+func (r *Recorder) record(opts model.ExtendedExecOptions, stdout []byte, stderr []byte, exitCode int, err error) {
+	cmd := r.request(opts)
+	r.response(cmd, stdout, stderr, exitCode, err)
+
+	j, jerr := json.Marshal(cmd)
+	if jerr != nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return
+	}
+
+	r.file.Write(append(j, '\n'))
+}
+
+// Close closes the recording file
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+
+	err := r.file.Close()
+	r.file = nil
+
+	return err
+}
+
+// Replayer is a CommandRunner that never executes commands, it returns the outcomes held in a recording instead.
+// Each recorded execution is returned once, for the first request matching it, so a run making the same calls in
+// the same order as the recorded run sees identical results.
+type Replayer struct {
+	commands []*RecordedCommand
+	used     []bool
+	redactor
+
+	mu sync.Mutex
+}
+
+var _ model.RedactingCommandRunner = (*Replayer)(nil)
+
+// NewReplayer creates a replayer for the recording in file
+func NewReplayer(file string) (*Replayer, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("could not open recording: %w", err)
+	}
+	defer f.Close()
+
+	r := &Replayer{}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+
+		cmd := &RecordedCommand{}
+		err = json.Unmarshal(scanner.Bytes(), cmd)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recording on line %d: %w", line, err)
+		}
+
+		r.commands = append(r.commands, cmd)
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("could not read recording: %w", err)
+	}
+
+	r.used = make([]bool, len(r.commands))
+
+	return r, nil
+}
+
+// Execute returns the recorded outcome of the command
+func (r *Replayer) Execute(_ context.Context, command string, args ...string) ([]byte, []byte, int, error) {
+	return r.replay(model.ExtendedExecOptions{Command: command, Args: args})
+}
+
+// ExecuteWithOptions returns the recorded outcome of the command
+func (r *Replayer) ExecuteWithOptions(_ context.Context, opts model.ExtendedExecOptions) ([]byte, []byte, int, error) {
+	return r.replay(opts)
+}
+
+// Remaining is the number of recorded executions that were not replayed
+func (r *Replayer) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	remaining := 0
+	for _, used := range r.used {
+		if !used {
+			remaining++
+		}
+	}
+
+	return remaining
+}
+
+func (r *Replayer) replay(opts model.ExtendedExecOptions) ([]byte, []byte, int, error) {
+	req := r.request(opts)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, cmd := range r.commands {
+		if r.used[i] || !cmd.matches(req) {
+			continue
+		}
+
+		r.used[i] = true
+
+		return cmd.result()
+	}
+
+	return nil, nil, -1, fmt.Errorf("%w: %s", ErrCommandNotRecorded, strings.Join(append([]string{req.Command}, req.Args...), " "))
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmdrunner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/model/modelmocks"
+)
+
+var _ = Describe("Recording", func() {
+	var (
+		ctrl      *gomock.Controller
+		runner    *modelmocks.MockCommandRunner
+		recording string
+		ctx       context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		runner = modelmocks.NewMockCommandRunner(ctrl)
+		recording = filepath.Join(GinkgoT().TempDir(), "recording.jsonl")
+		ctx = context.Background()
+	})
+
+	record := func() {
+		recorder, err := NewRecorder(runner, recording)
+		Expect(err).ToNot(HaveOccurred())
+		recorder.Redact("s3cret")
+
+		runner.EXPECT().Execute(ctx, "/usr/bin/id", "-u").Return([]byte("0\n"), nil, 0, nil)
+		runner.EXPECT().Execute(ctx, "/usr/bin/id", "-u").Return([]byte("1000\n"), nil, 0, nil)
+		runner.EXPECT().Execute(ctx, "/usr/bin/missing").Return(nil, nil, -1, fmt.Errorf("%w: /usr/bin/missing", model.ErrExecutableNotFound))
+		runner.EXPECT().ExecuteWithOptions(ctx, gomock.Any()).Return([]byte("token s3cret"), []byte("warn"), 2, nil)
+
+		recorder.Execute(ctx, "/usr/bin/id", "-u")
+		recorder.Execute(ctx, "/usr/bin/id", "-u")
+		recorder.Execute(ctx, "/usr/bin/missing")
+		recorder.ExecuteWithOptions(ctx, model.ExtendedExecOptions{
+			Command:     "/usr/bin/login",
+			Args:        []string{"--password", "s3cret"},
+			Cwd:         "/tmp",
+			Environment: []string{"TOKEN=t0ken"},
+		})
+
+		Expect(recorder.Close()).To(Succeed())
+	}
+
+	It("Should redact sensitive values and the environment", func() {
+		record()
+
+		raw, err := os.ReadFile(recording)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(raw)).ToNot(ContainSubstring("s3cret"))
+		Expect(string(raw)).ToNot(ContainSubstring("t0ken"))
+		Expect(string(raw)).To(ContainSubstring(`"args":["--password","[REDACTED]"]`))
+		Expect(string(raw)).To(ContainSubstring(`"environment":["TOKEN=[REDACTED]"]`))
+		Expect(string(raw)).To(ContainSubstring(`"stdout":"token [REDACTED]"`))
+	})
+
+	It("Should replay recorded commands in order", func() {
+		record()
+
+		replayer, err := NewReplayer(recording)
+		Expect(err).ToNot(HaveOccurred())
+		replayer.Redact("other-s3cret")
+		Expect(replayer.Remaining()).To(Equal(4))
+
+		stdout, _, code, err := replayer.Execute(ctx, "/usr/bin/id", "-u")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(code).To(Equal(0))
+		Expect(string(stdout)).To(Equal("0\n"))
+
+		stdout, _, _, err = replayer.Execute(ctx, "/usr/bin/id", "-u")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(stdout)).To(Equal("1000\n"))
+
+		_, _, _, err = replayer.Execute(ctx, "/usr/bin/missing")
+		Expect(errors.Is(err, model.ErrExecutableNotFound)).To(BeTrue())
+
+		// a different secret during replay still matches as both are redacted
+		stdout, stderr, code, err := replayer.ExecuteWithOptions(ctx, model.ExtendedExecOptions{
+			Command:     "/usr/bin/login",
+			Args:        []string{"--password", "other-s3cret"},
+			Cwd:         "/tmp",
+			Environment: []string{"TOKEN=other"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(code).To(Equal(2))
+		Expect(string(stdout)).To(Equal("token [REDACTED]"))
+		Expect(string(stderr)).To(Equal("warn"))
+		Expect(replayer.Remaining()).To(Equal(0))
+
+		_, _, _, err = replayer.Execute(ctx, "/usr/bin/id", "-u")
+		Expect(err).To(MatchError(ErrCommandNotRecorded))
+		Expect(err).To(MatchError(ContainSubstring("/usr/bin/id -u")))
+	})
+
+	It("Should reject invalid recordings", func() {
+		Expect(os.WriteFile(recording, []byte("{\"command\":\"/bin/true\"}\n\nnot json\n"), 0600)).To(Succeed())
+
+		_, err := NewReplayer(recording)
+		Expect(err).To(MatchError(ContainSubstring("invalid recording on line 3")))
+	})
+})
@@ -8,11 +8,18 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"io/fs"
 	"os/exec"
+	"time"
 
 	"github.com/choria-io/ccm/model"
 )
 
+// DefaultLocale is the locale commands run under unless another is requested, providers parse command output
+// that would be translated under other locales
+const DefaultLocale = "C"
+
 // CommandRunner executes system commands and captures their output
 type CommandRunner struct {
 	logger model.Logger
@@ -34,6 +41,9 @@ func (c *CommandRunner) ExecuteWithOptions(ctx context.Context, opts model.Exten
 	if opts.Cwd != "" {
 		logOpts = append(logOpts, "cwd", opts.Cwd)
 	}
+	if opts.User != "" {
+		logOpts = append(logOpts, "user", opts.User)
+	}
 
 	c.logger.Debug("Running command", logOpts...)
 
@@ -46,14 +56,32 @@ func (c *CommandRunner) ExecuteWithOptions(ctx context.Context, opts model.Exten
 
 	cmd := exec.CommandContext(toCtx, opts.Command, opts.Args...)
 	if cancel != nil {
-		cmd.Cancel = func() error { cancel(); return nil }
+		// children of a killed command can keep its output open, stop waiting for them shortly after
+		cmd.WaitDelay = time.Second
+	}
+
+	locale := opts.Locale
+	if locale == "" {
+		locale = DefaultLocale
 	}
 
 	cmd.Env = []string{
 		"PATH=/usr/bin:/bin:/usr/sbin:/sbin:/usr/local/bin:/usr/local/sbin",
-		"LANG=C",
-		"LC_ALL=C",
+		"LANG=" + locale,
+		"LC_ALL=" + locale,
 	}
+	if opts.Timezone != "" {
+		cmd.Env = append(cmd.Env, "TZ="+opts.Timezone)
+	}
+
+	if opts.User != "" {
+		env, err := runAsUser(cmd, opts.User)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		cmd.Env = append(cmd.Env, env...)
+	}
+
 	cmd.Env = append(cmd.Env, opts.Environment...)
 
 	if opts.Cwd != "" {
@@ -75,6 +103,17 @@ func (c *CommandRunner) ExecuteWithOptions(ctx context.Context, opts model.Exten
 	err := cmd.Run()
 	exitCode := cmd.ProcessState.ExitCode()
 
+	if err != nil && cancel != nil && errors.Is(toCtx.Err(), context.DeadlineExceeded) {
+		return stdout.Bytes(), stderr.Bytes(), exitCode, toCtx.Err()
+	}
+
+	// the command could not be started because it does not exist, this can happen
+	// when a binary a provider relies on is removed after provider selection
+	var execErr *exec.Error
+	if errors.As(err, &execErr) && (errors.Is(err, exec.ErrNotFound) || errors.Is(err, fs.ErrNotExist)) {
+		return nil, nil, exitCode, fmt.Errorf("%w: %s", model.ErrExecutableNotFound, execErr.Name)
+	}
+
 	var exitErr *exec.ExitError
 	if errors.As(err, &exitErr) {
 		// we specifically dont want to error when exit codes are >0 but we do want to return the exit code instead
@@ -0,0 +1,76 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmdrunner
+
+import (
+	"context"
+	"os"
+	"os/user"
+	"runtime"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/model/modelmocks"
+)
+
+var _ = Describe("CommandRunner", func() {
+	var runner *CommandRunner
+
+	BeforeEach(func() {
+		if runtime.GOOS == "windows" {
+			Skip("requires a posix shell")
+		}
+
+		logger := modelmocks.NewMockLogger(gomock.NewController(GinkgoT()))
+		logger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+
+		var err error
+		runner, err = NewCommandRunner(logger)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Describe("Timeout", func() {
+		It("Should kill commands that run too long", func(ctx context.Context) {
+			start := time.Now()
+			_, _, _, err := runner.ExecuteWithOptions(ctx, model.ExtendedExecOptions{
+				Command: "/bin/sh",
+				Args:    []string{"-c", "sleep 10"},
+				Timeout: 100 * time.Millisecond,
+			})
+			Expect(err).To(MatchError(context.DeadlineExceeded))
+			Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+		})
+	})
+
+	Describe("User", func() {
+		It("Should fail for unknown users", func(ctx context.Context) {
+			_, _, _, err := runner.ExecuteWithOptions(ctx, model.ExtendedExecOptions{Command: "/bin/true", User: "ccm-no-such-user"})
+			Expect(err).To(MatchError(ContainSubstring("could not look up user ccm-no-such-user")))
+		})
+
+		It("Should run as the user with its environment", func(ctx context.Context) {
+			if os.Geteuid() != 0 {
+				Skip("changing credentials requires root")
+			}
+
+			current, err := user.Current()
+			Expect(err).ToNot(HaveOccurred())
+
+			stdout, _, code, err := runner.ExecuteWithOptions(ctx, model.ExtendedExecOptions{
+				Command:     "/bin/sh",
+				Args:        []string{"-c", "echo $(id -u) $USER $FOO"},
+				Environment: []string{"FOO=bar"},
+				User:        current.Username,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(code).To(Equal(0))
+			Expect(string(stdout)).To(Equal(current.Uid + " " + current.Username + " bar\n"))
+		})
+	})
+})
@@ -0,0 +1,50 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !windows
+
+package cmdrunner
+
+import (
+	"fmt"
+	"os/exec"
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+// runAsUser configures cmd to run as the named user and returns the environment describing that user
+func runAsUser(cmd *exec.Cmd, name string) ([]string, error) {
+	u, err := user.Lookup(name)
+	if err != nil {
+		return nil, fmt.Errorf("could not look up user %s: %w", name, err)
+	}
+
+	uid, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid uid %q for user %s: %w", u.Uid, name, err)
+	}
+
+	gid, err := strconv.ParseUint(u.Gid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gid %q for user %s: %w", u.Gid, name, err)
+	}
+
+	var groups []uint32
+	gids, err := u.GroupIds()
+	if err == nil {
+		for _, g := range gids {
+			id, err := strconv.ParseUint(g, 10, 32)
+			if err == nil {
+				groups = append(groups, uint32(id))
+			}
+		}
+	}
+
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Credential: &syscall.Credential{Uid: uint32(uid), Gid: uint32(gid), Groups: groups},
+	}
+
+	return []string{"HOME=" + u.HomeDir, "USER=" + u.Username, "LOGNAME=" + u.Username}, nil
+}
@@ -0,0 +1,17 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build windows
+
+package cmdrunner
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// runAsUser configures cmd to run as the named user, this is not supported on Windows
+func runAsUser(_ *exec.Cmd, name string) ([]string, error) {
+	return nil, fmt.Errorf("running commands as user %s is not supported on windows", name)
+}
@@ -0,0 +1,216 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package downloadcache implements a content addressed cache for downloaded artifacts.
+//
+// Entries are keyed by the source URL and the expected sha256 checksum and every
+// cache hit is verified against the checksum before being used. Entries are written
+// to a temporary file and renamed into place so concurrent writers, in this or other
+// processes, never expose partially written entries.
+package downloadcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/choria-io/ccm/model"
+)
+
+const (
+	entrySuffix   = ".entry"
+	partialPrefix = ".partial-"
+)
+
+// Cache is a content addressed download cache with least recently used eviction
+type Cache struct {
+	dir     string
+	maxSize int64
+	log     model.Logger
+	mu      sync.Mutex
+}
+
+var _ model.DownloadCache = (*Cache)(nil)
+
+// New creates a cache stored in dir, a maxSize of 0 disables eviction
+func New(dir string, maxSize int64, log model.Logger) (*Cache, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("cache directory is required")
+	}
+	if maxSize < 0 {
+		return nil, fmt.Errorf("cache size cannot be negative")
+	}
+
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, fmt.Errorf("could not create download cache directory: %w", err)
+	}
+
+	return &Cache{dir: dir, maxSize: maxSize, log: log}, nil
+}
+
+// Open opens the cached entry for url and checksum, the contents are verified
+// against checksum and entries that do not match are removed and treated as a miss
+func (c *Cache) Open(url string, checksum string) (io.ReadCloser, bool, error) {
+	if checksum == "" {
+		return nil, false, nil
+	}
+
+	path := c.entryPath(url, checksum)
+
+	f, err := os.Open(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return nil, false, nil
+	case err != nil:
+		return nil, false, err
+	}
+
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		f.Close()
+		return nil, false, err
+	}
+
+	sum := hex.EncodeToString(h.Sum(nil))
+	if sum != checksum {
+		f.Close()
+		c.log.Warn("Removing corrupt download cache entry", "url", url, "expected", checksum, "found", sum)
+		os.Remove(path)
+		return nil, false, nil
+	}
+
+	_, err = f.Seek(0, io.SeekStart)
+	if err != nil {
+		f.Close()
+		return nil, false, err
+	}
+
+	// mtime tracks usage for eviction
+	now := time.Now()
+	err = os.Chtimes(path, now, now)
+	if err != nil {
+		c.log.Debug("Could not update download cache entry access time", "path", path, "error", err)
+	}
+
+	c.log.Debug("Download cache hit", "url", url, "checksum", checksum)
+
+	return f, true, nil
+}
+
+// Store copies file into the cache for url and checksum, the file must match checksum
+func (c *Cache) Store(url string, checksum string, file string) error {
+	if checksum == "" {
+		return nil
+	}
+
+	src, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tf, err := os.CreateTemp(c.dir, partialPrefix+"*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tf.Name())
+
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tf, h), src)
+	if err != nil {
+		tf.Close()
+		return fmt.Errorf("could not write download cache entry: %w", err)
+	}
+
+	err = tf.Close()
+	if err != nil {
+		return err
+	}
+
+	sum := hex.EncodeToString(h.Sum(nil))
+	if sum != checksum {
+		return fmt.Errorf("checksum mismatch, expected %q got %q", checksum, sum)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err = os.Rename(tf.Name(), c.entryPath(url, checksum))
+	if err != nil {
+		return fmt.Errorf("could not store download cache entry: %w", err)
+	}
+
+	c.log.Debug("Stored download cache entry", "url", url, "checksum", checksum)
+
+	return c.evictUnlocked()
+}
+
+// evictUnlocked removes the least recently used entries until the cache fits in maxSize
+func (c *Cache) evictUnlocked() error {
+	if c.maxSize == 0 {
+		return nil
+	}
+
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+
+	var entries []os.FileInfo
+	var total int64
+
+	for _, de := range dirEntries {
+		if !de.Type().IsRegular() || !strings.HasSuffix(de.Name(), entrySuffix) {
+			continue
+		}
+
+		info, err := de.Info()
+		if err != nil {
+			continue
+		}
+
+		entries = append(entries, info)
+		total += info.Size()
+	}
+
+	if total <= c.maxSize {
+		return nil
+	}
+
+	slices.SortFunc(entries, func(a, b os.FileInfo) int {
+		return a.ModTime().Compare(b.ModTime())
+	})
+
+	for _, entry := range entries {
+		if total <= c.maxSize {
+			break
+		}
+
+		err = os.Remove(filepath.Join(c.dir, entry.Name()))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		c.log.Debug("Evicted download cache entry", "entry", entry.Name(), "size", entry.Size())
+		total -= entry.Size()
+	}
+
+	return nil
+}
+
+func (c *Cache) entryPath(url string, checksum string) string {
+	key := sha256.Sum256([]byte(url + "\n" + checksum))
+
+	return filepath.Join(c.dir, hex.EncodeToString(key[:])+entrySuffix)
+}
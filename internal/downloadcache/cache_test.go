@@ -0,0 +1,167 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package downloadcache
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	iu "github.com/choria-io/ccm/internal/util"
+	"github.com/choria-io/ccm/model/modelmocks"
+)
+
+func TestDownloadCache(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Internal/DownloadCache")
+}
+
+var _ = Describe("Cache", func() {
+	var (
+		mockctl *gomock.Controller
+		logger  *modelmocks.MockLogger
+		dir     string
+		srcDir  string
+	)
+
+	BeforeEach(func() {
+		mockctl = gomock.NewController(GinkgoT())
+		logger = modelmocks.NewMockLogger(mockctl)
+		logger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+		logger.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+
+		dir = filepath.Join(GinkgoT().TempDir(), "cache")
+		srcDir = GinkgoT().TempDir()
+	})
+
+	source := func(name string, content string) (string, string) {
+		path := filepath.Join(srcDir, name)
+		Expect(os.WriteFile(path, []byte(content), 0644)).To(Succeed())
+		sum, err := iu.Sha256HashFile(path)
+		Expect(err).ToNot(HaveOccurred())
+
+		return path, sum
+	}
+
+	read := func(r io.ReadCloser) string {
+		defer r.Close()
+		b, err := io.ReadAll(r)
+		Expect(err).ToNot(HaveOccurred())
+		return string(b)
+	}
+
+	Describe("New", func() {
+		It("Should validate arguments and create the directory", func() {
+			_, err := New("", 0, logger)
+			Expect(err).To(MatchError("cache directory is required"))
+
+			_, err = New(dir, -1, logger)
+			Expect(err).To(MatchError("cache size cannot be negative"))
+
+			_, err = New(dir, 0, logger)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(iu.IsDirectory(dir)).To(BeTrue())
+		})
+	})
+
+	Describe("Open and Store", func() {
+		It("Should store and retrieve entries keyed by url and checksum", func() {
+			cache, err := New(dir, 0, logger)
+			Expect(err).ToNot(HaveOccurred())
+
+			file, sum := source("a.tgz", "archive a")
+
+			_, found, err := cache.Open("https://example.net/a.tgz", sum)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeFalse())
+
+			Expect(cache.Store("https://example.net/a.tgz", sum, file)).To(Succeed())
+
+			r, found, err := cache.Open("https://example.net/a.tgz", sum)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(read(r)).To(Equal("archive a"))
+
+			_, found, err = cache.Open("https://example.net/other.tgz", sum)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		It("Should not cache without a checksum", func() {
+			cache, err := New(dir, 0, logger)
+			Expect(err).ToNot(HaveOccurred())
+
+			file, _ := source("a.tgz", "archive a")
+			Expect(cache.Store("https://example.net/a.tgz", "", file)).To(Succeed())
+
+			entries, err := os.ReadDir(dir)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(BeEmpty())
+
+			_, found, err := cache.Open("https://example.net/a.tgz", "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		It("Should refuse to store files that do not match the checksum", func() {
+			cache, err := New(dir, 0, logger)
+			Expect(err).ToNot(HaveOccurred())
+
+			file, _ := source("a.tgz", "archive a")
+			err = cache.Store("https://example.net/a.tgz", "abc", file)
+			Expect(err).To(MatchError(ContainSubstring("checksum mismatch")))
+
+			entries, err := os.ReadDir(dir)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(BeEmpty())
+		})
+
+		It("Should remove corrupt entries", func() {
+			cache, err := New(dir, 0, logger)
+			Expect(err).ToNot(HaveOccurred())
+
+			file, sum := source("a.tgz", "archive a")
+			Expect(cache.Store("https://example.net/a.tgz", sum, file)).To(Succeed())
+
+			path := cache.entryPath("https://example.net/a.tgz", sum)
+			Expect(os.WriteFile(path, []byte("truncated"), 0600)).To(Succeed())
+
+			_, found, err := cache.Open("https://example.net/a.tgz", sum)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeFalse())
+			Expect(iu.FileExists(path)).To(BeFalse())
+		})
+	})
+
+	Describe("Eviction", func() {
+		It("Should evict the least recently used entries", func() {
+			cache, err := New(dir, 20, logger)
+			Expect(err).ToNot(HaveOccurred())
+
+			a, aSum := source("a.tgz", "0123456789")
+			b, bSum := source("b.tgz", "abcdefghij")
+			c, cSum := source("c.tgz", "ABCDEFGHIJ")
+
+			Expect(cache.Store("a", aSum, a)).To(Succeed())
+			Expect(cache.Store("b", bSum, b)).To(Succeed())
+
+			// make a the least recently used entry regardless of file system timestamp resolution
+			old := time.Now().Add(-time.Hour)
+			Expect(os.Chtimes(cache.entryPath("a", aSum), old, old)).To(Succeed())
+
+			Expect(cache.Store("c", cSum, c)).To(Succeed())
+
+			Expect(iu.FileExists(cache.entryPath("a", aSum))).To(BeFalse())
+			Expect(iu.FileExists(cache.entryPath("b", bSum))).To(BeTrue())
+			Expect(iu.FileExists(cache.entryPath("c", cSum))).To(BeTrue())
+		})
+	})
+})
@@ -144,7 +144,7 @@ func Execute(ctx context.Context, mgr model.Manager, hc *model.CommonHealthCheck
 			sleep = time.Second
 		}
 
-		err = backoff.InterruptableSleep(ctx, hc.ParseTrySleep)
+		err = backoff.InterruptableSleep(ctx, sleep)
 		if err != nil {
 			return nil, err
 		}
@@ -7,6 +7,7 @@ package goss
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/goccy/go-yaml"
 	. "github.com/onsi/ginkgo/v2"
@@ -140,8 +141,9 @@ var _ = Describe("Execute", func() {
 				GossRules: yaml.RawMessage(`command:
   "false":
     exit-status: 0`),
-				Format: model.HealthCheckGossFormat,
-				Tries:  3,
+				Format:        model.HealthCheckGossFormat,
+				Tries:         3,
+				ParseTrySleep: time.Millisecond,
 			}
 
 			result, err := Execute(ctx, mgr, hc, logger, logger)
@@ -156,8 +158,9 @@ var _ = Describe("Execute", func() {
 				GossRules: yaml.RawMessage(`command:
   "true":
     exit-status: 0`),
-				Format: model.HealthCheckGossFormat,
-				Tries:  3,
+				Format:        model.HealthCheckGossFormat,
+				Tries:         3,
+				ParseTrySleep: time.Millisecond,
 			}
 
 			result, err := Execute(ctx, mgr, hc, logger, logger)
@@ -175,8 +178,9 @@ var _ = Describe("Execute", func() {
 				GossRules: yaml.RawMessage(`command:
   "true":
     exit-status: 0`),
-				Format: model.HealthCheckGossFormat,
-				Tries:  3,
+				Format:        model.HealthCheckGossFormat,
+				Tries:         3,
+				ParseTrySleep: time.Millisecond,
 			}
 
 			result, err := Execute(ctx, mgr, hc, logger, logger)
@@ -23,27 +23,21 @@ var (
 
 // parseNagiosExitCode converts a Nagios exit code to a HealthCheckResult with appropriate status
 func parseNagiosExitCode(exitCode int, output string) *model.HealthCheckResult {
-	result := &model.HealthCheckResult{
-		Output: strings.TrimSpace(output),
-	}
+	return parseExitCode(&model.CommonHealthCheck{}, exitCode, output)
+}
 
-	switch exitCode {
-	case 0:
-		result.Status = model.HealthCheckOK
-	case 1:
-		result.Status = model.HealthCheckWarning
-	case 2:
-		result.Status = model.HealthCheckCritical
-	default:
-		result.Status = model.HealthCheckUnknown
+// parseExitCode converts an exit code to a HealthCheckResult using the exit codes configured in hc
+func parseExitCode(hc *model.CommonHealthCheck, exitCode int, output string) *model.HealthCheckResult {
+	return &model.HealthCheckResult{
+		Status: hc.StatusForExitCode(exitCode),
+		Output: strings.TrimSpace(output),
 	}
-
-	return result
 }
 
 // Execute runs a health check command and returns an error if the check fails.
 // If Tries > 1, the check will be retried up to Tries times with ParseTrySleep
-// delay between attempts until the check passes (returns OK status).
+// delay between attempts until the check passes (returns OK status). Every
+// attempt is limited to ParsedTimeout, a timed out attempt fails the check.
 func Execute(ctx context.Context, mgr model.Manager, hc *model.CommonHealthCheck, userLogger model.Logger, log model.Logger) (*model.HealthCheckResult, error) {
 	if hc == nil || hc.Command == "" {
 		return nil, ErrCommandNotSpecified
@@ -69,7 +63,7 @@ func Execute(ctx context.Context, mgr model.Manager, hc *model.CommonHealthCheck
 	}
 
 	if userLogger != nil {
-		userLogger = userLogger.With("format", "goss")
+		userLogger = userLogger.With("format", "nagios")
 	}
 
 	var result *model.HealthCheckResult
@@ -82,26 +76,20 @@ func Execute(ctx context.Context, mgr model.Manager, hc *model.CommonHealthCheck
 
 		log.Info("Executing health check command", "command", cmd[0], "args", strings.Join(args, " "), "try", attempt)
 
-		execCtx := ctx
-		var cancel context.CancelFunc
-		if hc.ParsedTimeout > 0 {
-			execCtx, cancel = context.WithTimeout(ctx, hc.ParsedTimeout)
-		}
-
 		timer := prometheus.NewTimer(metrics.HealthCheckTime.WithLabelValues(hc.TypeName, hc.ResourceName, hc.Name))
-		out, _, exitCode, err := runner.Execute(execCtx, cmd[0], args...)
+		out, _, exitCode, err := runner.ExecuteWithOptions(ctx, model.ExtendedExecOptions{
+			Command: cmd[0],
+			Args:    args,
+			Timeout: hc.ParsedTimeout,
+		})
 		timer.ObserveDuration()
 
-		if cancel != nil {
-			cancel()
-		}
-
 		if err != nil {
 			// On execution error, return immediately without retry
 			return nil, err
 		}
 
-		result = parseNagiosExitCode(exitCode, string(out))
+		result = parseExitCode(hc, exitCode, string(out))
 		result.Tries = attempt
 
 		// If check passed, return immediately
@@ -123,7 +111,7 @@ func Execute(ctx context.Context, mgr model.Manager, hc *model.CommonHealthCheck
 			sleep = time.Second
 		}
 
-		err = backoff.InterruptableSleep(ctx, hc.ParseTrySleep)
+		err = backoff.InterruptableSleep(ctx, sleep)
 		if err != nil {
 			return nil, err
 		}
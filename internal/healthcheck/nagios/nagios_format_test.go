@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -82,7 +83,7 @@ var _ = Describe("Execute", func() {
 		func(ctx context.Context, exitCode int, output string, expectedStatus model.HealthCheckStatus) {
 			hc := &model.CommonHealthCheck{Command: "/usr/lib/nagios/plugins/check_disk"}
 
-			runner.EXPECT().Execute(gomock.Any(), "/usr/lib/nagios/plugins/check_disk").
+			runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/lib/nagios/plugins/check_disk"}).
 				Return([]byte(output), []byte{}, exitCode, nil)
 
 			result, err := Execute(ctx, mgr, hc, logger, logger)
@@ -100,21 +101,29 @@ var _ = Describe("Execute", func() {
 		Entry("unexpected exit code 127", 127, "Unexpected error", model.HealthCheckUnknown),
 	)
 
+	It("should classify exit codes using the configured exit codes", func(ctx context.Context) {
+		hc := &model.CommonHealthCheck{Command: "/usr/local/bin/check_legacy", WarnExitCodes: []int{1, 3}}
+
+		runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/local/bin/check_legacy"}).
+			Return([]byte("LEGACY degraded"), []byte{}, 3, nil)
+
+		result, err := Execute(ctx, mgr, hc, logger, logger)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Status).To(Equal(model.HealthCheckWarning))
+		Expect(result.Output).To(Equal("LEGACY degraded"))
+	})
+
 	DescribeTable("command parsing",
 		func(ctx context.Context, command string, expectedCmd string, expectedArgs []string) {
 			hc := &model.CommonHealthCheck{Command: command}
 
+			opts := model.ExtendedExecOptions{Command: expectedCmd}
 			if len(expectedArgs) > 0 {
-				args := make([]any, len(expectedArgs))
-				for i, a := range expectedArgs {
-					args[i] = a
-				}
-				runner.EXPECT().Execute(gomock.Any(), expectedCmd, args...).
-					Return([]byte("OK"), []byte{}, 0, nil)
-			} else {
-				runner.EXPECT().Execute(gomock.Any(), expectedCmd).
-					Return([]byte("OK"), []byte{}, 0, nil)
+				opts.Args = expectedArgs
 			}
+			runner.EXPECT().ExecuteWithOptions(gomock.Any(), opts).
+				Return([]byte("OK"), []byte{}, 0, nil)
 
 			result, err := Execute(ctx, mgr, hc, logger, logger)
 
@@ -145,7 +154,7 @@ var _ = Describe("Execute", func() {
 		hc := &model.CommonHealthCheck{Command: "/usr/lib/nagios/plugins/check_disk"}
 		expectedErr := fmt.Errorf("execution failed")
 
-		runner.EXPECT().Execute(gomock.Any(), "/usr/lib/nagios/plugins/check_disk").
+		runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/lib/nagios/plugins/check_disk"}).
 			Return(nil, nil, 0, expectedErr)
 
 		result, err := Execute(ctx, mgr, hc, logger, logger)
@@ -162,7 +171,7 @@ var _ = Describe("Execute", func() {
 					Tries:   tries,
 				}
 
-				runner.EXPECT().Execute(gomock.Any(), "/usr/lib/nagios/plugins/check_disk").
+				runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/lib/nagios/plugins/check_disk"}).
 					Return([]byte("DISK CRITICAL"), []byte{}, finalExitCode, nil).Times(expectedCalls)
 
 				result, err := Execute(ctx, mgr, hc, logger, logger)
@@ -178,14 +187,15 @@ var _ = Describe("Execute", func() {
 
 		It("should retry and succeed on second attempt", func(ctx context.Context) {
 			hc := &model.CommonHealthCheck{
-				Command: "/usr/lib/nagios/plugins/check_disk",
-				Tries:   3,
+				Command:       "/usr/lib/nagios/plugins/check_disk",
+				Tries:         3,
+				ParseTrySleep: time.Millisecond,
 			}
 
 			gomock.InOrder(
-				runner.EXPECT().Execute(gomock.Any(), "/usr/lib/nagios/plugins/check_disk").
+				runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/lib/nagios/plugins/check_disk"}).
 					Return([]byte("DISK CRITICAL"), []byte{}, 2, nil),
-				runner.EXPECT().Execute(gomock.Any(), "/usr/lib/nagios/plugins/check_disk").
+				runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/lib/nagios/plugins/check_disk"}).
 					Return([]byte("DISK OK"), []byte{}, 0, nil),
 			)
 
@@ -198,16 +208,17 @@ var _ = Describe("Execute", func() {
 
 		It("should retry and succeed on third attempt", func(ctx context.Context) {
 			hc := &model.CommonHealthCheck{
-				Command: "/usr/lib/nagios/plugins/check_disk",
-				Tries:   3,
+				Command:       "/usr/lib/nagios/plugins/check_disk",
+				Tries:         3,
+				ParseTrySleep: time.Millisecond,
 			}
 
 			gomock.InOrder(
-				runner.EXPECT().Execute(gomock.Any(), "/usr/lib/nagios/plugins/check_disk").
+				runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/lib/nagios/plugins/check_disk"}).
 					Return([]byte("DISK CRITICAL"), []byte{}, 2, nil),
-				runner.EXPECT().Execute(gomock.Any(), "/usr/lib/nagios/plugins/check_disk").
+				runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/lib/nagios/plugins/check_disk"}).
 					Return([]byte("DISK WARNING"), []byte{}, 1, nil),
-				runner.EXPECT().Execute(gomock.Any(), "/usr/lib/nagios/plugins/check_disk").
+				runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/lib/nagios/plugins/check_disk"}).
 					Return([]byte("DISK OK"), []byte{}, 0, nil),
 			)
 
@@ -219,16 +230,17 @@ var _ = Describe("Execute", func() {
 
 		It("should return last result after all retries exhausted", func(ctx context.Context) {
 			hc := &model.CommonHealthCheck{
-				Command: "/usr/lib/nagios/plugins/check_disk",
-				Tries:   3,
+				Command:       "/usr/lib/nagios/plugins/check_disk",
+				Tries:         3,
+				ParseTrySleep: time.Millisecond,
 			}
 
 			gomock.InOrder(
-				runner.EXPECT().Execute(gomock.Any(), "/usr/lib/nagios/plugins/check_disk").
+				runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/lib/nagios/plugins/check_disk"}).
 					Return([]byte("DISK CRITICAL - attempt 1"), []byte{}, 2, nil),
-				runner.EXPECT().Execute(gomock.Any(), "/usr/lib/nagios/plugins/check_disk").
+				runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/lib/nagios/plugins/check_disk"}).
 					Return([]byte("DISK CRITICAL - attempt 2"), []byte{}, 2, nil),
-				runner.EXPECT().Execute(gomock.Any(), "/usr/lib/nagios/plugins/check_disk").
+				runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/lib/nagios/plugins/check_disk"}).
 					Return([]byte("DISK WARNING - attempt 3"), []byte{}, 1, nil),
 			)
 
@@ -239,14 +251,41 @@ var _ = Describe("Execute", func() {
 			Expect(result.Output).To(Equal("DISK WARNING - attempt 3"))
 		})
 
+		It("should pass the timeout to every attempt and sleep between tries", func(ctx context.Context) {
+			hc := &model.CommonHealthCheck{
+				Command:       "/usr/lib/nagios/plugins/check_disk -w 20%",
+				Tries:         2,
+				ParsedTimeout: 5 * time.Second,
+				ParseTrySleep: 50 * time.Millisecond,
+			}
+			opts := model.ExtendedExecOptions{Command: "/usr/lib/nagios/plugins/check_disk", Args: []string{"-w", "20%"}, Timeout: 5 * time.Second}
+
+			gomock.InOrder(
+				runner.EXPECT().ExecuteWithOptions(gomock.Any(), opts).
+					Return([]byte("DISK WARNING"), []byte{}, 1, nil),
+				runner.EXPECT().ExecuteWithOptions(gomock.Any(), opts).
+					Return([]byte("DISK OK"), []byte{}, 0, nil),
+			)
+
+			start := time.Now()
+			result, err := Execute(ctx, mgr, hc, logger, logger)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Status).To(Equal(model.HealthCheckOK))
+			Expect(result.Tries).To(Equal(2))
+			Expect(time.Since(start)).To(BeNumerically(">=", 50*time.Millisecond))
+			Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+		})
+
 		It("should not retry on execution error", func(ctx context.Context) {
 			hc := &model.CommonHealthCheck{
-				Command: "/usr/lib/nagios/plugins/check_disk",
-				Tries:   3,
+				Command:       "/usr/lib/nagios/plugins/check_disk",
+				Tries:         3,
+				ParseTrySleep: time.Millisecond,
 			}
 			expectedErr := fmt.Errorf("execution failed")
 
-			runner.EXPECT().Execute(gomock.Any(), "/usr/lib/nagios/plugins/check_disk").
+			runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/lib/nagios/plugins/check_disk"}).
 				Return(nil, nil, 0, expectedErr).Times(1)
 
 			result, err := Execute(ctx, mgr, hc, logger, logger)
@@ -258,12 +297,13 @@ var _ = Describe("Execute", func() {
 		It("should respect context cancellation between retries", func(ctx context.Context) {
 			ctx, cancel := context.WithCancel(ctx)
 			hc := &model.CommonHealthCheck{
-				Command: "/usr/lib/nagios/plugins/check_disk",
-				Tries:   3,
+				Command:       "/usr/lib/nagios/plugins/check_disk",
+				Tries:         3,
+				ParseTrySleep: time.Millisecond,
 			}
 
-			runner.EXPECT().Execute(gomock.Any(), "/usr/lib/nagios/plugins/check_disk").
-				DoAndReturn(func(_ context.Context, _ string, _ ...string) ([]byte, []byte, int, error) {
+			runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/lib/nagios/plugins/check_disk"}).
+				DoAndReturn(func(_ context.Context, _ model.ExtendedExecOptions) ([]byte, []byte, int, error) {
 					cancel() // Cancel context after first attempt
 					return []byte("DISK CRITICAL"), []byte{}, 2, nil
 				}).Times(1)
@@ -279,8 +319,9 @@ var _ = Describe("Execute", func() {
 			cancel() // Cancel before execution
 
 			hc := &model.CommonHealthCheck{
-				Command: "/usr/lib/nagios/plugins/check_disk",
-				Tries:   3,
+				Command:       "/usr/lib/nagios/plugins/check_disk",
+				Tries:         3,
+				ParseTrySleep: time.Millisecond,
 			}
 
 			result, err := Execute(ctx, mgr, hc, logger, logger)
@@ -48,6 +48,12 @@ var (
 		Help: "How many resources were changed",
 	}, []string{"type", "name"})
 
+	// ResourceStateCorrective counts how many changes repaired drift on resources that existed before the run
+	ResourceStateCorrective = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: prometheus.BuildFQName(NameSpace, Subsystem, "resource_state_corrective_count"),
+		Help: "How many changes repaired drift on resources that existed before the run",
+	}, []string{"type", "name"})
+
 	// ResourceStateRefreshed counts how many resources were refreshed
 	ResourceStateRefreshed = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: prometheus.BuildFQName(NameSpace, Subsystem, "resource_state_refreshed_count"),
@@ -72,6 +78,18 @@ var (
 		Help: "How many resources were skipped",
 	}, []string{"type", "name"})
 
+	// ResourceStateNotApplicable counts how many resources were skipped as no provider could manage them
+	ResourceStateNotApplicable = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: prometheus.BuildFQName(NameSpace, Subsystem, "resource_state_not_applicable_count"),
+		Help: "How many resources were skipped as no provider could manage them",
+	}, []string{"type", "name"})
+
+	// ResourceStateDeadlineExceeded counts how many resources were canceled or skipped as the run deadline passed
+	ResourceStateDeadlineExceeded = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: prometheus.BuildFQName(NameSpace, Subsystem, "resource_state_deadline_exceeded_count"),
+		Help: "How many resources were canceled or skipped as the run deadline passed",
+	}, []string{"type", "name"})
+
 	// ResourceStateNoop counts how many resources were in noop mode
 	ResourceStateNoop = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: prometheus.BuildFQName(NameSpace, Subsystem, "resource_state_noop_count"),
@@ -90,6 +108,18 @@ var (
 		Help: "How many resources were in stable state",
 	}, []string{"type", "name"})
 
+	// ManifestDriftRatio is the share of resources that were changed or failed in the last run of a manifest
+	ManifestDriftRatio = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: prometheus.BuildFQName(NameSpace, Subsystem, "manifest_drift_ratio"),
+		Help: "The share of resources that were changed or failed in the last run of a manifest, mode is enforce or noop",
+	}, []string{"manifest", "mode"})
+
+	// ResourceTypeDriftRatio is the share of resources of a type that were changed or failed in the last run of a manifest
+	ResourceTypeDriftRatio = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: prometheus.BuildFQName(NameSpace, Subsystem, "resource_type_drift_ratio"),
+		Help: "The share of resources of a type that were changed or failed in the last run of a manifest, mode is enforce or noop",
+	}, []string{"manifest", "type", "mode"})
+
 	// FactGatherTime is a summary of the time taken to gather facts
 	FactGatherTime = prometheus.NewSummaryVec(prometheus.SummaryOpts{
 		Name: prometheus.BuildFQName(NameSpace, Subsystem, "facts_gather_duration_seconds"),
@@ -140,6 +170,11 @@ var (
 		Name: prometheus.BuildFQName(NameSpace, Subsystem, "agent_manifest_fetch_error_count"),
 		Help: "How many times fetching manifests failed in agents",
 	}, []string{"manifest"})
+
+	AgentManagementPaused = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: prometheus.BuildFQName(NameSpace, Subsystem, "agent_management_paused"),
+		Help: "1 when the last apply only ran health checks as management was paused",
+	}, []string{"manifest"})
 )
 
 func RegisterMetrics() {
@@ -148,13 +183,18 @@ func RegisterMetrics() {
 	prometheus.MustRegister(HealthCheckTime)
 	prometheus.MustRegister(HealthStatusCount)
 	prometheus.MustRegister(ResourceStateChanged)
+	prometheus.MustRegister(ResourceStateCorrective)
 	prometheus.MustRegister(ResourceStateRefreshed)
 	prometheus.MustRegister(ResourceStateFailed)
 	prometheus.MustRegister(ResourceStateError)
 	prometheus.MustRegister(ResourceStateSkipped)
+	prometheus.MustRegister(ResourceStateNotApplicable)
+	prometheus.MustRegister(ResourceStateDeadlineExceeded)
 	prometheus.MustRegister(ResourceStateNoop)
 	prometheus.MustRegister(ResourceStateTotal)
 	prometheus.MustRegister(ResourceStateStable)
+	prometheus.MustRegister(ManifestDriftRatio)
+	prometheus.MustRegister(ResourceTypeDriftRatio)
 	prometheus.MustRegister(FactGatherTime)
 	prometheus.MustRegister(AgentApplyTime)
 	prometheus.MustRegister(AgentDataResolveTime)
@@ -165,6 +205,7 @@ func RegisterMetrics() {
 	prometheus.MustRegister(AgentManifestFetchFailureCount)
 	prometheus.MustRegister(AgentHealthCheckTime)
 	prometheus.MustRegister(AgentHealthCheckRemediation)
+	prometheus.MustRegister(AgentManagementPaused)
 }
 
 func ListenAndServe(port int, log model.Logger) {
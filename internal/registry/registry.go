@@ -7,12 +7,22 @@ package registry
 import (
 	"fmt"
 	"maps"
+	"slices"
 	"sort"
 	"sync"
 
+	"github.com/choria-io/ccm/internal/cmdrunner"
 	"github.com/choria-io/ccm/model"
 )
 
+const (
+	// ProviderConfigLocale is the provider configuration setting holding the locale the provider runs commands under
+	ProviderConfigLocale = "locale"
+
+	// ProviderConfigTimezone is the provider configuration setting holding the timezone the provider runs commands under
+	ProviderConfigTimezone = "timezone"
+)
+
 type providerEntry struct {
 	factory model.ProviderFactory
 }
@@ -164,8 +174,9 @@ func Types() []string {
 	return res
 }
 
-// FindSuitableProvider searches all registered providers for a suitable provider capable of working on the node
-func FindSuitableProvider(typeName string, provider string, facts map[string]any, properties model.ResourceProperties, log model.Logger, runner model.CommandRunner) (model.Provider, error) {
+// FindSuitableProvider searches all registered providers for a suitable provider capable of working on the node,
+// the provider is configured using any configuration config holds for it, config may be nil
+func FindSuitableProvider(typeName string, provider string, facts map[string]any, properties model.ResourceProperties, log model.Logger, runner model.CommandRunner, config model.ProviderConfigSource) (model.Provider, error) {
 	var selected model.ProviderFactory
 
 	if provider == "" {
@@ -192,5 +203,90 @@ func FindSuitableProvider(typeName string, provider string, facts map[string]any
 		return nil, model.ErrNoSuitableProvider
 	}
 
-	return selected.New(log, runner)
+	redactSensitive(runner, properties)
+
+	return newProvider(selected, log, runner, config)
+}
+
+// FindAlternateProvider searches for the most suitable provider like FindSuitableProvider but skips any provider named in exclude,
+// this is used to fall back to another provider when the selected one cannot run
+func FindAlternateProvider(typeName string, exclude []string, facts map[string]any, properties model.ResourceProperties, log model.Logger, runner model.CommandRunner, config model.ProviderConfigSource) (model.Provider, error) {
+	provs, err := selectProviders(typeName, facts, properties, log)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", model.ErrProviderNotFound, err)
+	}
+
+	for _, prov := range provs {
+		if slices.Contains(exclude, prov.Name()) {
+			continue
+		}
+
+		redactSensitive(runner, properties)
+
+		return newProvider(prov, log, runner, config)
+	}
+
+	return nil, model.ErrNoSuitableProvider
+}
+
+// redactSensitive tells runners that store the commands they run about the sensitive values in properties
+func redactSensitive(runner model.CommandRunner, properties model.ResourceProperties) {
+	r, ok := runner.(model.RedactingCommandRunner)
+	if !ok || properties == nil {
+		return
+	}
+
+	r.Redact(model.SensitiveValues(properties)...)
+}
+
+// newProvider creates a provider using factory, passing the provider configuration to factories that support it
+func newProvider(factory model.ProviderFactory, log model.Logger, runner model.CommandRunner, config model.ProviderConfigSource) (model.Provider, error) {
+	if config == nil {
+		return factory.New(log, runner)
+	}
+
+	cfg := config.ProviderConfig(factory.Name())
+
+	runner, cfg, err := localeRunner(factory.Name(), runner, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	cf, ok := factory.(model.ConfigurableProviderFactory)
+	if !ok || len(cfg) == 0 {
+		return factory.New(log, runner)
+	}
+
+	return cf.NewWithConfig(log, runner, cfg)
+}
+
+// localeRunner handles the locale and timezone settings every provider supports, when set runner is wrapped to run
+// commands under them and the remaining provider specific configuration is returned
+func localeRunner(provider string, runner model.CommandRunner, cfg map[string]any) (model.CommandRunner, map[string]any, error) {
+	_, hasLocale := cfg[ProviderConfigLocale]
+	_, hasTimezone := cfg[ProviderConfigTimezone]
+	if !hasLocale && !hasTimezone {
+		return runner, cfg, nil
+	}
+
+	settings := make(map[string]string, 2)
+	for _, key := range []string{ProviderConfigLocale, ProviderConfigTimezone} {
+		val, ok := cfg[key]
+		if !ok {
+			continue
+		}
+
+		str, ok := val.(string)
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid %s provider configuration: %s must be a string", provider, key)
+		}
+
+		settings[key] = str
+	}
+
+	cfg = maps.Clone(cfg)
+	delete(cfg, ProviderConfigLocale)
+	delete(cfg, ProviderConfigTimezone)
+
+	return cmdrunner.NewLocaleRunner(runner, settings[ProviderConfigLocale], settings[ProviderConfigTimezone]), cfg, nil
 }
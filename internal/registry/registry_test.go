@@ -5,7 +5,9 @@
 package registry
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -21,6 +23,23 @@ func TestRegistry(t *testing.T) {
 	RunSpecs(t, "Internal/Registry")
 }
 
+// configurableFactory adds ConfigurableProviderFactory support to the mock factory
+type configurableFactory struct {
+	*modelmocks.MockProviderFactory
+
+	config map[string]any
+}
+
+func (f *configurableFactory) NewWithConfig(log model.Logger, runner model.CommandRunner, config map[string]any) (model.Provider, error) {
+	f.config = config
+	return f.New(log, runner)
+}
+
+// providerConfig is a static model.ProviderConfigSource
+type providerConfig map[string]map[string]any
+
+func (c providerConfig) ProviderConfig(provider string) map[string]any { return c[provider] }
+
 var _ = Describe("Registry", func() {
 	var (
 		mockctl  *gomock.Controller
@@ -306,7 +325,7 @@ var _ = Describe("Registry", func() {
 
 		Context("with auto-selection (empty provider name)", func() {
 			It("Should return ErrNoSuitableProvider when no providers registered", func() {
-				result, err := FindSuitableProvider("package", "", facts, nil, logger, runner)
+				result, err := FindSuitableProvider("package", "", facts, nil, logger, runner, nil)
 				Expect(err).To(Equal(model.ErrNoSuitableProvider))
 				Expect(result).To(BeNil())
 			})
@@ -315,7 +334,7 @@ var _ = Describe("Registry", func() {
 				factory1.EXPECT().IsManageable(facts, nil).Return(false, 0, nil)
 				registerProvider(factory1)
 
-				result, err := FindSuitableProvider("package", "", facts, nil, logger, runner)
+				result, err := FindSuitableProvider("package", "", facts, nil, logger, runner, nil)
 				Expect(err).To(Equal(model.ErrNoSuitableProvider))
 				Expect(result).To(BeNil())
 			})
@@ -325,7 +344,7 @@ var _ = Describe("Registry", func() {
 				factory1.EXPECT().New(logger, runner).Return(provider, nil)
 				registerProvider(factory1)
 
-				result, err := FindSuitableProvider("package", "", facts, nil, logger, runner)
+				result, err := FindSuitableProvider("package", "", facts, nil, logger, runner, nil)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(result).To(Equal(provider))
 			})
@@ -337,7 +356,7 @@ var _ = Describe("Registry", func() {
 				registerProvider(factory1)
 				registerProvider(factory2)
 
-				result, err := FindSuitableProvider("package", "", facts, nil, logger, runner)
+				result, err := FindSuitableProvider("package", "", facts, nil, logger, runner, nil)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(result).To(Equal(provider))
 			})
@@ -352,25 +371,89 @@ var _ = Describe("Registry", func() {
 				registerProvider(factory1)
 				registerProvider(factory2)
 
-				result, err := FindSuitableProvider("package", "", facts, nil, logger, runner)
+				result, err := FindSuitableProvider("package", "", facts, nil, logger, runner, nil)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(result).To(Equal(provider2)) // factory2 selected due to lower priority value
 			})
 
+			It("Should pass provider configuration to configurable factories", func() {
+				configurable := &configurableFactory{MockProviderFactory: factory1}
+				factory1.EXPECT().IsManageable(facts, nil).Return(true, 1, nil)
+				factory1.EXPECT().New(logger, runner).Return(provider, nil)
+				registerProvider(configurable)
+
+				result, err := FindSuitableProvider("package", "", facts, nil, logger, runner, providerConfig{"apt": {"proxy": "http://proxy:3128"}})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(provider))
+				Expect(configurable.config).To(Equal(map[string]any{"proxy": "http://proxy:3128"}))
+			})
+
+			It("Should create configurable factories without configuration using New", func() {
+				configurable := &configurableFactory{MockProviderFactory: factory1}
+				factory1.EXPECT().IsManageable(facts, nil).Return(true, 1, nil)
+				factory1.EXPECT().New(logger, runner).Return(provider, nil)
+				registerProvider(configurable)
+
+				_, err := FindSuitableProvider("package", "", facts, nil, logger, runner, providerConfig{"dnf": {"proxy": "http://proxy:3128"}})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(configurable.config).To(BeNil())
+			})
+
+			It("Should run commands under the configured locale and timezone", func() {
+				configurable := &configurableFactory{MockProviderFactory: factory1}
+				factory1.EXPECT().IsManageable(facts, nil).Return(true, 1, nil)
+				registerProvider(configurable)
+
+				var created model.CommandRunner
+				factory1.EXPECT().New(logger, gomock.Any()).DoAndReturn(func(_ model.Logger, r model.CommandRunner) (model.Provider, error) {
+					created = r
+					return provider, nil
+				})
+
+				_, err := FindSuitableProvider("package", "", facts, nil, logger, runner, providerConfig{"apt": {"locale": "en_US.UTF-8", "timezone": "UTC", "proxy": "http://proxy:3128"}})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(configurable.config).To(Equal(map[string]any{"proxy": "http://proxy:3128"}))
+
+				runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "apt-get", Args: []string{"update"}, Locale: "en_US.UTF-8", Timezone: "UTC"})
+				created.Execute(context.Background(), "apt-get", "update")
+			})
+
+			It("Should reject invalid locale settings", func() {
+				factory1.EXPECT().IsManageable(facts, nil).Return(true, 1, nil)
+				registerProvider(factory1)
+
+				_, err := FindSuitableProvider("package", "", facts, nil, logger, runner, providerConfig{"apt": {"locale": 1}})
+				Expect(err).To(MatchError("invalid apt provider configuration: locale must be a string"))
+			})
+
 			It("Should return error when provider New() fails", func() {
 				factory1.EXPECT().IsManageable(facts, nil).Return(true, 1, nil)
 				factory1.EXPECT().New(logger, runner).Return(nil, fmt.Errorf("failed to create provider"))
 				registerProvider(factory1)
 
-				result, err := FindSuitableProvider("package", "", facts, nil, logger, runner)
+				result, err := FindSuitableProvider("package", "", facts, nil, logger, runner, nil)
 				Expect(err).To(MatchError(ContainSubstring("failed to create provider")))
 				Expect(result).To(BeNil())
 			})
+
+			It("Should pass sensitive property values to redacting runners", func() {
+				redacting := modelmocks.NewMockRedactingCommandRunner(mockctl)
+				prop := &model.ArchiveResourceProperties{Password: "s3cret"}
+
+				factory1.EXPECT().IsManageable(facts, prop).Return(true, 1, nil)
+				factory1.EXPECT().New(logger, redacting).Return(provider, nil)
+				redacting.EXPECT().Redact("s3cret")
+				registerProvider(factory1)
+
+				result, err := FindSuitableProvider("package", "", facts, prop, logger, redacting, nil)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(provider))
+			})
 		})
 
 		Context("with explicit provider selection", func() {
 			It("Should return ErrNoSuitableProvider when type not found", func() {
-				result, err := FindSuitableProvider("nonexistent", "apt", facts, nil, logger, runner)
+				result, err := FindSuitableProvider("nonexistent", "apt", facts, nil, logger, runner, nil)
 				Expect(err).To(Equal(model.ErrNoSuitableProvider))
 				Expect(result).To(BeNil())
 			})
@@ -378,7 +461,7 @@ var _ = Describe("Registry", func() {
 			It("Should return ErrResourceInvalid when provider not found", func() {
 				registerProvider(factory1)
 
-				result, err := FindSuitableProvider("package", "nonexistent", facts, nil, logger, runner)
+				result, err := FindSuitableProvider("package", "nonexistent", facts, nil, logger, runner, nil)
 				Expect(err).To(MatchError(model.ErrResourceInvalid))
 				Expect(err).To(MatchError(ContainSubstring(model.ErrProviderNotFound.Error())))
 				Expect(result).To(BeNil())
@@ -388,7 +471,7 @@ var _ = Describe("Registry", func() {
 				factory1.EXPECT().IsManageable(facts, nil).Return(false, 0, nil)
 				registerProvider(factory1)
 
-				result, err := FindSuitableProvider("package", "apt", facts, nil, logger, runner)
+				result, err := FindSuitableProvider("package", "apt", facts, nil, logger, runner, nil)
 				Expect(err).To(MatchError(model.ErrResourceInvalid))
 				Expect(err).To(MatchError(ContainSubstring("not applicable")))
 				Expect(result).To(BeNil())
@@ -398,7 +481,7 @@ var _ = Describe("Registry", func() {
 				factory1.EXPECT().IsManageable(facts, nil).Return(false, 0, fmt.Errorf("check failed"))
 				registerProvider(factory1)
 
-				result, err := FindSuitableProvider("package", "apt", facts, nil, logger, runner)
+				result, err := FindSuitableProvider("package", "apt", facts, nil, logger, runner, nil)
 				Expect(err).To(MatchError(model.ErrResourceInvalid))
 				Expect(err).To(MatchError(ContainSubstring("check failed")))
 				Expect(result).To(BeNil())
@@ -409,7 +492,7 @@ var _ = Describe("Registry", func() {
 				factory1.EXPECT().New(logger, runner).Return(provider, nil)
 				registerProvider(factory1)
 
-				result, err := FindSuitableProvider("package", "apt", facts, nil, logger, runner)
+				result, err := FindSuitableProvider("package", "apt", facts, nil, logger, runner, nil)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(result).To(Equal(provider))
 			})
@@ -419,7 +502,7 @@ var _ = Describe("Registry", func() {
 				factory1.EXPECT().New(logger, runner).Return(nil, fmt.Errorf("initialization failed"))
 				registerProvider(factory1)
 
-				result, err := FindSuitableProvider("package", "apt", facts, nil, logger, runner)
+				result, err := FindSuitableProvider("package", "apt", facts, nil, logger, runner, nil)
 				Expect(err).To(MatchError(ContainSubstring("initialization failed")))
 				Expect(result).To(BeNil())
 			})
@@ -430,13 +513,117 @@ var _ = Describe("Registry", func() {
 				registerProvider(factory1)
 				registerProvider(factory2)
 
-				result, err := FindSuitableProvider("package", "yum", facts, nil, logger, runner)
+				result, err := FindSuitableProvider("package", "yum", facts, nil, logger, runner, nil)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(result).To(Equal(provider))
 			})
 		})
 	})
 
+	Describe("Property based routing", func() {
+		var (
+			facts  map[string]any
+			runner *modelmocks.MockCommandRunner
+			apt    *modelmocks.MockProvider
+			pip    *modelmocks.MockProvider
+		)
+
+		// routes python packages to pip, everything else to apt
+		isPythonPackage := func(prop model.ResourceProperties) bool {
+			pkg, ok := prop.(*model.PackageResourceProperties)
+			return ok && strings.HasPrefix(pkg.Name, "py:")
+		}
+
+		BeforeEach(func() {
+			facts = map[string]any{"os": "ubuntu"}
+			runner = modelmocks.NewMockCommandRunner(mockctl)
+			apt = modelmocks.NewMockProvider(mockctl)
+			pip = modelmocks.NewMockProvider(mockctl)
+
+			pipFactory := modelmocks.NewMockProviderFactory(mockctl)
+			pipFactory.EXPECT().TypeName().Return("package").AnyTimes()
+			pipFactory.EXPECT().Name().Return("pip").AnyTimes()
+			pipFactory.EXPECT().New(logger, runner).Return(pip, nil).AnyTimes()
+			pipFactory.EXPECT().IsManageable(facts, gomock.Any()).DoAndReturn(func(_ map[string]any, prop model.ResourceProperties) (bool, int, error) {
+				return isPythonPackage(prop), 1, nil
+			}).AnyTimes()
+
+			factory1.EXPECT().New(logger, runner).Return(apt, nil).AnyTimes()
+			factory1.EXPECT().IsManageable(facts, gomock.Any()).DoAndReturn(func(_ map[string]any, prop model.ResourceProperties) (bool, int, error) {
+				return !isPythonPackage(prop), 1, nil
+			}).AnyTimes()
+
+			registerProvider(factory1)
+			registerProvider(pipFactory)
+		})
+
+		DescribeTable("selects the provider based on the resource properties",
+			func(name string, expected string) {
+				props := &model.PackageResourceProperties{
+					CommonResourceProperties: model.CommonResourceProperties{Name: name, Ensure: model.EnsurePresent},
+				}
+
+				result, err := FindSuitableProvider("package", "", facts, props, logger, runner, nil)
+				Expect(err).ToNot(HaveOccurred())
+
+				switch expected {
+				case "pip":
+					Expect(result).To(BeIdenticalTo(pip))
+				default:
+					Expect(result).To(BeIdenticalTo(apt))
+				}
+			},
+			Entry("python package", "py:flask", "pip"),
+			Entry("system package", "nginx", "apt"),
+		)
+
+		It("Should reject a requested provider that cannot manage the properties", func() {
+			props := &model.PackageResourceProperties{
+				CommonResourceProperties: model.CommonResourceProperties{Name: "nginx", Ensure: model.EnsurePresent},
+			}
+
+			_, err := FindSuitableProvider("package", "pip", facts, props, logger, runner, nil)
+			Expect(err).To(MatchError(model.ErrProviderNotManageable))
+		})
+	})
+
+	Describe("FindAlternateProvider", func() {
+		var (
+			facts    map[string]any
+			runner   *modelmocks.MockCommandRunner
+			provider *modelmocks.MockProvider
+		)
+
+		BeforeEach(func() {
+			facts = map[string]any{"os": "ubuntu"}
+			runner = modelmocks.NewMockCommandRunner(mockctl)
+			provider = modelmocks.NewMockProvider(mockctl)
+		})
+
+		It("Should skip excluded providers", func() {
+			factory1.EXPECT().IsManageable(facts, nil).Return(true, 1, nil)
+			factory2.EXPECT().IsManageable(facts, nil).Return(true, 5, nil)
+			factory2.EXPECT().New(logger, runner).Return(provider, nil)
+			registerProvider(factory1)
+			registerProvider(factory2)
+
+			result, err := FindAlternateProvider("package", []string{"apt"}, facts, nil, logger, runner, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(provider))
+		})
+
+		It("Should return ErrNoSuitableProvider when all manageable providers are excluded", func() {
+			factory1.EXPECT().IsManageable(facts, nil).Return(true, 1, nil)
+			factory2.EXPECT().IsManageable(facts, nil).Return(false, 0, nil)
+			registerProvider(factory1)
+			registerProvider(factory2)
+
+			result, err := FindAlternateProvider("package", []string{"apt"}, facts, nil, logger, runner, nil)
+			Expect(err).To(MatchError(model.ErrNoSuitableProvider))
+			Expect(result).To(BeNil())
+		})
+	})
+
 	Describe("Thread safety", func() {
 		It("Should handle concurrent operations", func() {
 			// Set up IsManageable expectations since selectProviders may call it
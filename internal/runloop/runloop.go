@@ -0,0 +1,202 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package runloop runs a function on an interval with a random startup splay and per interval jitter.
+//
+// Each tick starts a run in the background, ticks that arrive while the previous run is still going
+// are skipped rather than queued so slow runs never pile up. Runs are scheduled on a fixed period from
+// the first run with the jitter applied per run, so neither run duration nor jitter drift the schedule.
+// Several loops can be used concurrently, for example one per managed component.
+package runloop
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/choria-io/ccm/model"
+)
+
+// Options configures a Loop
+type Options struct {
+	// Interval is the time between runs
+	Interval time.Duration
+	// Splay is the maximum random delay added before the first run to avoid many nodes running at the same time
+	Splay time.Duration
+	// Jitter is the maximum random delay added to every interval
+	Jitter time.Duration
+	// Immediate runs as soon as the splay passed rather than waiting a full interval for the first run
+	Immediate bool
+}
+
+// clock provides the current time and timers, replaced in tests
+type clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// Loop runs a function on an interval
+type Loop struct {
+	name    string
+	opts    Options
+	fn      func(context.Context)
+	log     model.Logger
+	clock   clock
+	running atomic.Bool
+	started atomic.Bool
+	wg      sync.WaitGroup
+
+	nextRun time.Time
+	lastRun time.Time
+	skipped int
+	mu      sync.Mutex
+}
+
+// New creates a loop that calls fn on every tick
+func New(name string, fn func(context.Context), opts Options, log model.Logger) (*Loop, error) {
+	if fn == nil {
+		return nil, fmt.Errorf("run function is required")
+	}
+	if opts.Interval <= 0 {
+		return nil, fmt.Errorf("interval must be greater than 0")
+	}
+	if opts.Splay < 0 {
+		return nil, fmt.Errorf("splay cannot be negative")
+	}
+	if opts.Jitter < 0 {
+		return nil, fmt.Errorf("jitter cannot be negative")
+	}
+
+	return &Loop{
+		name:  name,
+		opts:  opts,
+		fn:    fn,
+		log:   log.With("loop", name),
+		clock: realClock{},
+	}, nil
+}
+
+// Run schedules runs until ctx is canceled, it then waits for any in progress run to complete
+func (l *Loop) Run(ctx context.Context) error {
+	if !l.started.CompareAndSwap(false, true) {
+		return fmt.Errorf("loop %s already started", l.name)
+	}
+	defer l.started.Store(false)
+
+	// base is the unjittered time of the scheduled run, it advances by exactly the interval
+	base := l.clock.Now().Add(randomDuration(l.opts.Splay))
+	next := base
+	if !l.opts.Immediate {
+		base = base.Add(l.opts.Interval)
+		next = base.Add(randomDuration(l.opts.Jitter))
+	}
+
+	l.setNextRun(next)
+	l.log.Debug("Scheduled first run", "delay", next.Sub(l.clock.Now()).Round(time.Millisecond))
+
+	for {
+		select {
+		case <-l.clock.After(next.Sub(l.clock.Now())):
+			l.tick(ctx)
+
+			next, base = l.schedule(base)
+			l.setNextRun(next)
+
+		case <-ctx.Done():
+			l.setNextRun(time.Time{})
+			l.wg.Wait()
+
+			return nil
+		}
+	}
+}
+
+// NextRun is the time of the next scheduled run, zero when the loop is not running
+func (l *Loop) NextRun() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.nextRun
+}
+
+// LastRun is the time the most recent run started, zero when no run started yet
+func (l *Loop) LastRun() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.lastRun
+}
+
+// Skipped is the number of ticks skipped because the previous run was still in progress
+func (l *Loop) Skipped() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.skipped
+}
+
+// Running indicates if a run is in progress
+func (l *Loop) Running() bool {
+	return l.running.Load()
+}
+
+func (l *Loop) tick(ctx context.Context) {
+	if !l.running.CompareAndSwap(false, true) {
+		l.mu.Lock()
+		l.skipped++
+		l.mu.Unlock()
+
+		l.log.Warn("Skipping run, previous run is still in progress")
+		return
+	}
+
+	l.mu.Lock()
+	l.lastRun = time.Now()
+	l.mu.Unlock()
+
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		defer l.running.Store(false)
+
+		l.fn(ctx)
+	}()
+}
+
+// schedule calculates the next run after the one scheduled at base, runs missed while the process was
+// suspended or the system was overloaded are skipped rather than run back to back
+func (l *Loop) schedule(base time.Time) (next time.Time, nextBase time.Time) {
+	now := l.clock.Now()
+
+	base = base.Add(l.opts.Interval)
+	if !base.After(now) {
+		missed := now.Sub(base)/l.opts.Interval + 1
+		base = base.Add(missed * l.opts.Interval)
+		l.log.Warn("Skipping missed runs", "missed", int(missed))
+	}
+
+	return base.Add(randomDuration(l.opts.Jitter)), base
+}
+
+func (l *Loop) setNextRun(t time.Time) {
+	l.mu.Lock()
+	l.nextRun = t
+	l.mu.Unlock()
+}
+
+func randomDuration(limit time.Duration) time.Duration {
+	if limit <= 0 {
+		return 0
+	}
+
+	return rand.N(limit)
+}
@@ -0,0 +1,376 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package runloop
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/choria-io/ccm/model/modelmocks"
+)
+
+func TestRunLoop(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Internal/RunLoop")
+}
+
+// fakeClock only moves when advanced, firing the timers that became due
+type fakeClock struct {
+	now     time.Time
+	waiters []fakeWaiter
+	mu      sync.Mutex
+}
+
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+
+	c.waiters = append(c.waiters, fakeWaiter{at: c.now.Add(d), ch: ch})
+
+	return ch
+}
+
+func (c *fakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.waiters)
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+
+	var pending []fakeWaiter
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- c.now
+	}
+	c.waiters = pending
+}
+
+var _ = Describe("Loop", func() {
+	var (
+		mockctl *gomock.Controller
+		logger  *modelmocks.MockLogger
+		runs    atomic.Int32
+		noop    = func(context.Context) {}
+	)
+
+	BeforeEach(func() {
+		mockctl = gomock.NewController(GinkgoT())
+		logger = modelmocks.NewMockLogger(mockctl)
+		logger.EXPECT().With(gomock.Any()).Return(logger).AnyTimes()
+		logger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+		logger.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+		runs.Store(0)
+	})
+
+	start := func(ctx context.Context, l *Loop) chan struct{} {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			Expect(l.Run(ctx)).To(Succeed())
+		}()
+
+		return done
+	}
+
+	Describe("New", func() {
+		It("Should validate options", func() {
+			_, err := New("test", nil, Options{Interval: time.Second}, logger)
+			Expect(err).To(MatchError("run function is required"))
+
+			_, err = New("test", noop, Options{}, logger)
+			Expect(err).To(MatchError("interval must be greater than 0"))
+
+			_, err = New("test", noop, Options{Interval: time.Second, Splay: -1}, logger)
+			Expect(err).To(MatchError("splay cannot be negative"))
+
+			_, err = New("test", noop, Options{Interval: time.Second, Jitter: -1}, logger)
+			Expect(err).To(MatchError("jitter cannot be negative"))
+		})
+	})
+
+	Describe("Run", func() {
+		It("Should run on every interval", func(ctx context.Context) {
+			l, err := New("test", func(context.Context) { runs.Add(1) }, Options{Interval: 10 * time.Millisecond}, logger)
+			Expect(err).ToNot(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(ctx)
+			done := start(ctx, l)
+
+			Eventually(runs.Load).Should(BeNumerically(">=", 3))
+			Expect(l.LastRun()).ToNot(BeZero())
+
+			cancel()
+			Eventually(done).Should(BeClosed())
+			Expect(l.NextRun()).To(BeZero())
+		})
+
+		It("Should wait a full interval unless immediate", func(ctx context.Context) {
+			l, err := New("test", func(context.Context) { runs.Add(1) }, Options{Interval: time.Hour}, logger)
+			Expect(err).ToNot(HaveOccurred())
+
+			lctx, cancel := context.WithCancel(ctx)
+			done := start(lctx, l)
+
+			Eventually(l.NextRun).ShouldNot(BeZero())
+			Expect(l.NextRun()).To(BeTemporally("~", time.Now().Add(time.Hour), time.Second))
+			Consistently(runs.Load, 50*time.Millisecond).Should(BeZero())
+
+			cancel()
+			Eventually(done).Should(BeClosed())
+
+			l, err = New("test", func(context.Context) { runs.Add(1) }, Options{Interval: time.Hour, Immediate: true}, logger)
+			Expect(err).ToNot(HaveOccurred())
+
+			lctx, cancel = context.WithCancel(ctx)
+			done = start(lctx, l)
+
+			Eventually(runs.Load).Should(BeEquivalentTo(1))
+			Eventually(l.NextRun).Should(BeTemporally("~", time.Now().Add(time.Hour), time.Second))
+
+			cancel()
+			Eventually(done).Should(BeClosed())
+		})
+
+		It("Should delay the first run by at most the splay", func(ctx context.Context) {
+			l, err := New("test", noop, Options{Interval: time.Hour, Splay: time.Minute, Jitter: time.Minute}, logger)
+			Expect(err).ToNot(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(ctx)
+			now := time.Now()
+			done := start(ctx, l)
+
+			Eventually(l.NextRun).ShouldNot(BeZero())
+			Expect(l.NextRun()).To(BeTemporally(">=", now.Add(time.Hour)))
+			Expect(l.NextRun()).To(BeTemporally("<", now.Add(time.Hour+2*time.Minute+time.Second)))
+
+			cancel()
+			Eventually(done).Should(BeClosed())
+		})
+
+		It("Should skip ticks while a run is in progress", func(ctx context.Context) {
+			release := make(chan struct{})
+			l, err := New("test", func(context.Context) {
+				runs.Add(1)
+				<-release
+			}, Options{Interval: 5 * time.Millisecond, Immediate: true}, logger)
+			Expect(err).ToNot(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(ctx)
+			done := start(ctx, l)
+
+			Eventually(l.Skipped).Should(BeNumerically(">=", 2))
+			Expect(runs.Load()).To(BeEquivalentTo(1))
+			Expect(l.Running()).To(BeTrue())
+
+			close(release)
+			Eventually(runs.Load).Should(BeNumerically(">=", 2))
+
+			cancel()
+			Eventually(done).Should(BeClosed())
+		})
+
+		It("Should wait for the in progress run when canceled", func(ctx context.Context) {
+			var finished atomic.Bool
+			l, err := New("test", func(ctx context.Context) {
+				<-ctx.Done()
+				time.Sleep(20 * time.Millisecond)
+				finished.Store(true)
+			}, Options{Interval: time.Hour, Immediate: true}, logger)
+			Expect(err).ToNot(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(ctx)
+			done := start(ctx, l)
+
+			Eventually(l.Running).Should(BeTrue())
+			cancel()
+			Eventually(done).Should(BeClosed())
+			Expect(finished.Load()).To(BeTrue())
+		})
+
+		Describe("Schedule", func() {
+			var (
+				clk    *fakeClock
+				start0 time.Time
+				times  []time.Time
+				mu     sync.Mutex
+			)
+
+			BeforeEach(func() {
+				clk = newFakeClock()
+				start0 = clk.Now()
+				times = nil
+			})
+
+			record := func() {
+				mu.Lock()
+				times = append(times, clk.Now())
+				mu.Unlock()
+				runs.Add(1)
+			}
+
+			recorded := func() []time.Time {
+				mu.Lock()
+				defer mu.Unlock()
+
+				return append([]time.Time{}, times...)
+			}
+
+			// advance moves the clock once the loop is waiting for its next run
+			advance := func(d time.Duration) {
+				Eventually(clk.Waiters).Should(Equal(1))
+				clk.Advance(d)
+			}
+
+			It("Should run on a stable cadence", func(ctx context.Context) {
+				l, err := New("test", func(context.Context) { record() }, Options{Interval: time.Minute}, logger)
+				Expect(err).ToNot(HaveOccurred())
+				l.clock = clk
+
+				ctx, cancel := context.WithCancel(ctx)
+				done := start(ctx, l)
+
+				for i := 1; i <= 3; i++ {
+					advance(time.Minute)
+					Eventually(runs.Load).Should(BeEquivalentTo(i))
+				}
+
+				Expect(recorded()).To(Equal([]time.Time{start0.Add(time.Minute), start0.Add(2 * time.Minute), start0.Add(3 * time.Minute)}))
+				Eventually(l.NextRun).Should(Equal(start0.Add(4 * time.Minute)))
+
+				cancel()
+				Eventually(done).Should(BeClosed())
+			})
+
+			It("Should not drift with jitter", func(ctx context.Context) {
+				l, err := New("test", func(context.Context) { record() }, Options{Interval: time.Minute, Jitter: 10 * time.Second}, logger)
+				Expect(err).ToNot(HaveOccurred())
+				l.clock = clk
+
+				ctx, cancel := context.WithCancel(ctx)
+				done := start(ctx, l)
+
+				for i := 1; i <= 20; i++ {
+					Eventually(clk.Waiters).Should(Equal(1))
+					clk.Advance(l.NextRun().Sub(clk.Now()))
+					Eventually(runs.Load).Should(BeEquivalentTo(i))
+				}
+
+				for i, t := range recorded() {
+					slot := start0.Add(time.Duration(i+1) * time.Minute)
+					Expect(t).To(BeTemporally(">=", slot))
+					Expect(t).To(BeTemporally("<", slot.Add(10*time.Second)))
+				}
+
+				cancel()
+				Eventually(done).Should(BeClosed())
+			})
+
+			It("Should not run back to back after a long run", func(ctx context.Context) {
+				release := make(chan struct{})
+				l, err := New("test", func(context.Context) {
+					record()
+					if runs.Load() == 1 {
+						<-release
+					}
+				}, Options{Interval: time.Minute}, logger)
+				Expect(err).ToNot(HaveOccurred())
+				l.clock = clk
+
+				ctx, cancel := context.WithCancel(ctx)
+				done := start(ctx, l)
+
+				advance(time.Minute)
+				Eventually(l.Running).Should(BeTrue())
+
+				advance(time.Minute)
+				advance(time.Minute)
+				Eventually(l.Skipped).Should(Equal(2))
+
+				close(release)
+				Eventually(l.Running).Should(BeFalse())
+				Consistently(runs.Load, 20*time.Millisecond).Should(BeEquivalentTo(1))
+
+				advance(30 * time.Second)
+				Consistently(runs.Load, 20*time.Millisecond).Should(BeEquivalentTo(1))
+
+				advance(30 * time.Second)
+				Eventually(runs.Load).Should(BeEquivalentTo(2))
+				Expect(recorded()).To(Equal([]time.Time{start0.Add(time.Minute), start0.Add(4 * time.Minute)}))
+
+				cancel()
+				Eventually(done).Should(BeClosed())
+			})
+
+			It("Should skip runs missed while suspended", func(ctx context.Context) {
+				l, err := New("test", func(context.Context) { record() }, Options{Interval: time.Minute}, logger)
+				Expect(err).ToNot(HaveOccurred())
+				l.clock = clk
+
+				ctx, cancel := context.WithCancel(ctx)
+				done := start(ctx, l)
+
+				advance(3*time.Minute + 30*time.Second)
+				Eventually(runs.Load).Should(BeEquivalentTo(1))
+				Eventually(l.NextRun).Should(Equal(start0.Add(4 * time.Minute)))
+				Consistently(runs.Load, 20*time.Millisecond).Should(BeEquivalentTo(1))
+
+				cancel()
+				Eventually(done).Should(BeClosed())
+			})
+		})
+
+		It("Should not start twice", func(ctx context.Context) {
+			l, err := New("test", noop, Options{Interval: time.Hour}, logger)
+			Expect(err).ToNot(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(ctx)
+			done := start(ctx, l)
+			Eventually(l.NextRun).ShouldNot(BeZero())
+
+			Expect(l.Run(ctx)).To(MatchError("loop test already started"))
+
+			cancel()
+			Eventually(done).Should(BeClosed())
+		})
+	})
+})
@@ -5,13 +5,16 @@
 package session
 
 import (
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/segmentio/ksuid"
 
@@ -27,6 +30,26 @@ type DirectorySessionStore struct {
 	mu        sync.Mutex
 }
 
+// pendingRefreshDirectory is the directory within the session store holding pending refresh markers
+const pendingRefreshDirectory = "refresh"
+
+// convergedDirectory is the directory within the session store holding converged resource records
+const convergedDirectory = "converged"
+
+// convergedRecord records the inputs of a resource that was found in its desired state
+type convergedRecord struct {
+	Resource  string    `json:"resource"`
+	Digest    string    `json:"digest"`
+	TimeStamp time.Time `json:"timestamp"`
+}
+
+// pendingRefresh is a refresh that was triggered but not yet processed by the subscribing resource
+type pendingRefresh struct {
+	Resource  string    `json:"resource"`
+	Source    string    `json:"source"`
+	TimeStamp time.Time `json:"timestamp"`
+}
+
 // NewDirectorySessionStore creates a new directory of files based session store with the provided loggers
 func NewDirectorySessionStore(directory string, logger model.Logger, writer model.Logger) (*DirectorySessionStore, error) {
 	// Reject empty directory path early
@@ -202,6 +225,15 @@ func (s *DirectorySessionStore) allEventsUnlocked() ([]model.SessionEvent, error
 			}
 			event = &startEvent
 
+		case model.ManagementPausedEventProtocol:
+			var pausedEvent model.ManagementPausedEvent
+			err = json.Unmarshal(data, &pausedEvent)
+			if err != nil {
+				s.log.Error("Failed to parse management paused event", "filename", filename, "error", err)
+				continue
+			}
+			event = &pausedEvent
+
 		case model.TransactionEventProtocol:
 			var txEvent model.TransactionEvent
 			err = json.Unmarshal(data, &txEvent)
@@ -226,3 +258,119 @@ func (s *DirectorySessionStore) allEventsUnlocked() ([]model.SessionEvent, error
 
 	return events, nil
 }
+
+// AddPendingRefresh records that resourceId should be refreshed because source changed, the marker survives
+// new sessions and is only removed by ClearPendingRefresh or when the session is destroyed
+func (s *DirectorySessionStore) AddPendingRefresh(resourceId string, source string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := os.MkdirAll(filepath.Join(s.directory, pendingRefreshDirectory), 0755)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(pendingRefresh{Resource: resourceId, Source: source, TimeStamp: time.Now().UTC()}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	filename := s.pendingRefreshFile(resourceId)
+	s.log.Debug("Recording pending refresh", "resource", resourceId, "source", source, "filename", filename)
+
+	return os.WriteFile(filename, data, 0644)
+}
+
+// PendingRefresh returns the source of an unprocessed refresh for resourceId, empty when none is pending
+func (s *DirectorySessionStore) PendingRefresh(resourceId string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.pendingRefreshFile(resourceId))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	var pending pendingRefresh
+	err = json.Unmarshal(data, &pending)
+	if err != nil {
+		return "", fmt.Errorf("invalid pending refresh for %s: %w", resourceId, err)
+	}
+
+	return pending.Source, nil
+}
+
+// ClearPendingRefresh removes any pending refresh for resourceId
+func (s *DirectorySessionStore) ClearPendingRefresh(resourceId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := os.Remove(s.pendingRefreshFile(resourceId))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// pendingRefreshFile is the marker file for resourceId, the id is hex encoded as resource names are often paths
+func (s *DirectorySessionStore) pendingRefreshFile(resourceId string) string {
+	return filepath.Join(s.directory, pendingRefreshDirectory, hex.EncodeToString([]byte(resourceId))+".refresh")
+}
+
+// RecordConverged records that resourceId was in its desired state with inputs summarized by digest, records
+// survive new sessions and are only removed by ClearConverged or when the session is destroyed
+func (s *DirectorySessionStore) RecordConverged(resourceId string, digest string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := os.MkdirAll(filepath.Join(s.directory, convergedDirectory), 0755)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(convergedRecord{Resource: resourceId, Digest: digest, TimeStamp: time.Now().UTC()}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.convergedFile(resourceId), data, 0644)
+}
+
+// Converged returns the input digest and the time resourceId was recorded as converged, empty when not recorded
+func (s *DirectorySessionStore) Converged(resourceId string) (string, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.convergedFile(resourceId))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", time.Time{}, nil
+		}
+		return "", time.Time{}, err
+	}
+
+	var record convergedRecord
+	err = json.Unmarshal(data, &record)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("invalid converged record for %s: %w", resourceId, err)
+	}
+
+	return record.Digest, record.TimeStamp, nil
+}
+
+// ClearConverged removes all converged records
+func (s *DirectorySessionStore) ClearConverged() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return os.RemoveAll(filepath.Join(s.directory, convergedDirectory))
+}
+
+// convergedFile is the record file for resourceId, the id is hex encoded as resource names are often paths
+func (s *DirectorySessionStore) convergedFile(resourceId string) string {
+	return filepath.Join(s.directory, convergedDirectory, hex.EncodeToString([]byte(resourceId))+".converged")
+}
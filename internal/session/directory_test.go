@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -110,6 +111,28 @@ var _ = Describe("DirectorySessionStore", func() {
 			Expect(readEvent.Changed).To(BeTrue())
 		})
 
+		It("Should read back management paused events", func() {
+			Expect(store.RecordEvent(model.NewManagementPausedEvent("/etc/choria/ccm/pause"))).To(Succeed())
+			Expect(store.RecordEvent(model.NewTransactionEvent("package", "test", ""))).To(Succeed())
+
+			events, err := store.AllEvents()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(events).To(HaveLen(2))
+
+			// both events are created in the same second so their ksuid order is random
+			Expect(events).To(ContainElement(BeAssignableToTypeOf(&model.ManagementPausedEvent{})))
+
+			var paused *model.ManagementPausedEvent
+			for _, event := range events {
+				switch e := event.(type) {
+				case *model.ManagementPausedEvent:
+					paused = e
+				}
+			}
+			Expect(paused.Marker).To(Equal("/etc/choria/ccm/pause"))
+			Expect(model.BuildSessionSummary(events).Paused).To(BeTrue())
+		})
+
 		It("Should fail when directory doesn't exist", func() {
 			newDir := filepath.Join(tempDir, "nonexistent")
 			newStore, err := NewDirectorySessionStore(newDir, logger, writer)
@@ -366,4 +389,79 @@ var _ = Describe("DirectorySessionStore", func() {
 			Expect(events).To(HaveLen(1))
 		})
 	})
+
+	Describe("Pending refreshes", func() {
+		It("Should return no source when nothing is pending", func() {
+			source, err := store.PendingRefresh("service#httpd")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(source).To(BeEmpty())
+		})
+
+		It("Should record and clear pending refreshes", func() {
+			Expect(store.AddPendingRefresh("service#httpd", "package#httpd")).To(Succeed())
+			Expect(store.AddPendingRefresh("exec#/usr/bin/reload", "file#/etc/httpd/conf/httpd.conf")).To(Succeed())
+
+			source, err := store.PendingRefresh("service#httpd")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(source).To(Equal("package#httpd"))
+
+			Expect(store.ClearPendingRefresh("service#httpd")).To(Succeed())
+			source, err = store.PendingRefresh("service#httpd")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(source).To(BeEmpty())
+
+			source, err = store.PendingRefresh("exec#/usr/bin/reload")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(source).To(Equal("file#/etc/httpd/conf/httpd.conf"))
+		})
+
+		It("Should not fail when clearing a refresh that is not pending", func() {
+			Expect(store.ClearPendingRefresh("service#httpd")).To(Succeed())
+		})
+
+		It("Should survive new sessions", func() {
+			apply := modelmocks.NewMockApply(mockCtrl)
+			apply.EXPECT().Resources().Return(nil).AnyTimes()
+
+			Expect(store.StartSession(apply)).To(Succeed())
+			Expect(store.AddPendingRefresh("service#httpd", "package#httpd")).To(Succeed())
+
+			reopened, err := NewDirectorySessionStore(tempDir, logger, writer)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reopened.StartSession(apply)).To(Succeed())
+
+			source, err := reopened.PendingRefresh("service#httpd")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(source).To(Equal("package#httpd"))
+
+			events, err := reopened.AllEvents()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(events).To(HaveLen(2))
+		})
+	})
+
+	Describe("Converged records", func() {
+		It("Should record, read and clear converged resources", func() {
+			digest, since, err := store.Converged("file#/etc/motd")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(digest).To(BeEmpty())
+			Expect(since).To(BeZero())
+
+			Expect(store.RecordConverged("file#/etc/motd", "abc")).To(Succeed())
+			Expect(store.RecordConverged("package#zsh", "def")).To(Succeed())
+
+			digest, since, err = store.Converged("file#/etc/motd")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(digest).To(Equal("abc"))
+			Expect(since).To(BeTemporally("~", time.Now(), time.Minute))
+
+			Expect(store.ClearConverged()).To(Succeed())
+
+			for _, id := range []string{"file#/etc/motd", "package#zsh"} {
+				digest, _, err = store.Converged(id)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(digest).To(BeEmpty())
+			}
+		})
+	})
 })
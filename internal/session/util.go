@@ -27,8 +27,17 @@ func updateMetrics(event model.SessionEvent) {
 		metrics.ResourceStateNoop.WithLabelValues(e.ResourceType, name).Inc()
 	case e.Changed:
 		metrics.ResourceStateChanged.WithLabelValues(e.ResourceType, name).Inc()
+		if e.Corrective {
+			metrics.ResourceStateCorrective.WithLabelValues(e.ResourceType, name).Inc()
+		}
 	case e.Skipped:
 		metrics.ResourceStateSkipped.WithLabelValues(e.ResourceType, name).Inc()
+		if e.NotApplicable {
+			metrics.ResourceStateNotApplicable.WithLabelValues(e.ResourceType, name).Inc()
+		}
+		if e.DeadlineExceeded {
+			metrics.ResourceStateDeadlineExceeded.WithLabelValues(e.ResourceType, name).Inc()
+		}
 	case e.Refreshed:
 		metrics.ResourceStateRefreshed.WithLabelValues(e.ResourceType, name).Inc()
 	case e.Failed:
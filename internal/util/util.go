@@ -6,8 +6,10 @@ package util
 
 import (
 	"archive/tar"
+	"bytes"
 	"compress/gzip"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -18,10 +20,12 @@ import (
 	"os/user"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/tidwall/gjson"
 	"golang.org/x/term"
 )
 
@@ -53,6 +57,20 @@ func FileHasSuffix(filename string, suffixes ...string) bool {
 	return false
 }
 
+// ValidateCanonicalPath ensures path is absolute and canonical, rejecting relative paths, "." and ".." elements,
+// repeated separators and trailing separators, errors are reported against the property named by field
+func ValidateCanonicalPath(field string, path string) error {
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("%s must be an absolute path", field)
+	}
+
+	if filepath.Clean(path) != path {
+		return fmt.Errorf("%s must be a canonical path", field)
+	}
+
+	return nil
+}
+
 // IsDirectory determines if a path is a directory
 func IsDirectory(path string) bool {
 	stat, err := os.Stat(path)
@@ -340,6 +358,22 @@ func DeepMergeMap(target map[string]any, source map[string]any) map[string]any {
 	return result
 }
 
+// MergeDefaults merges values over defaults recursively. Nested maps are merged while any other
+// value in values, including slices, replaces the default entirely
+func MergeDefaults(defaults map[string]any, values map[string]any) map[string]any {
+	result := CloneMap(defaults)
+	for key, value := range values {
+		if existing, ok := result[key].(map[string]any); ok {
+			if incoming, ok := value.(map[string]any); ok {
+				result[key] = MergeDefaults(existing, incoming)
+				continue
+			}
+		}
+		result[key] = CloneValue(value)
+	}
+	return result
+}
+
 // CloneMap creates a shallow copy of the provided map with cloned values.
 func CloneMap(source map[string]any) map[string]any {
 	result := make(map[string]any, len(source))
@@ -395,6 +429,58 @@ func IsJsonObject(data []byte) bool {
 	return strings.HasPrefix(trimmed, "{") || strings.HasPrefix(string(trimmed), "[")
 }
 
+// JSONEqual reports whether a and b encode to the same JSON document. This allows
+// values decoded from YAML and JSON, with their differing numeric types, to be compared
+func JSONEqual(a any, b any) (bool, error) {
+	ja, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+
+	jb, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(ja, jb), nil
+}
+
+// FactString looks up a string fact using a GJSON path like host.info.platformFamily. Facts are encoded
+// to JSON first so typed values, such as host information structures, are searched like templates do
+func FactString(facts map[string]any, path string) (string, bool) {
+	if len(facts) == 0 {
+		return "", false
+	}
+
+	j, err := json.Marshal(facts)
+	if err != nil {
+		return "", false
+	}
+
+	res := gjson.GetBytes(j, path)
+	if res.Type != gjson.String || res.Str == "" {
+		return "", false
+	}
+
+	return res.Str, true
+}
+
+// PlatformMatches checks the host.info.platformFamily fact against families and, when the family is not
+// known, the host.info.platform fact against platforms. Missing facts never match
+func PlatformMatches(facts map[string]any, families []string, platforms []string) bool {
+	family, ok := FactString(facts, "host.info.platformFamily")
+	if ok {
+		return slices.Contains(families, strings.ToLower(family))
+	}
+
+	platform, ok := FactString(facts, "host.info.platform")
+	if ok {
+		return slices.Contains(platforms, strings.ToLower(platform))
+	}
+
+	return false
+}
+
 // UntarGz extracts a tar.gz file into a target directory
 func UntarGz(s io.Reader, td string) ([]string, error) {
 	uncompressed, err := gzip.NewReader(s)
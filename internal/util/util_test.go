@@ -19,6 +19,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/shirou/gopsutil/v4/host"
 )
 
 func TestPackageutil(t *testing.T) {
@@ -277,6 +278,86 @@ var _ = Describe("ShallowMerge", func() {
 	})
 })
 
+var _ = Describe("FactString", func() {
+	It("finds string facts in maps and typed values", func() {
+		facts := map[string]any{
+			"host":   map[string]any{"info": &host.InfoStat{Platform: "ubuntu", PlatformFamily: "debian"}},
+			"custom": map[string]any{"count": 1},
+		}
+
+		family, ok := FactString(facts, "host.info.platformFamily")
+		Expect(ok).To(BeTrue())
+		Expect(family).To(Equal("debian"))
+
+		_, ok = FactString(facts, "custom.count")
+		Expect(ok).To(BeFalse())
+
+		_, ok = FactString(facts, "host.info.missing")
+		Expect(ok).To(BeFalse())
+
+		_, ok = FactString(nil, "host.info.platform")
+		Expect(ok).To(BeFalse())
+	})
+})
+
+var _ = Describe("PlatformMatches", func() {
+	It("prefers the platform family and falls back to the platform", func() {
+		families := []string{"debian"}
+		platforms := []string{"ubuntu"}
+
+		Expect(PlatformMatches(map[string]any{"host": map[string]any{"info": map[string]any{"platformFamily": "Debian", "platform": "other"}}}, families, platforms)).To(BeTrue())
+		Expect(PlatformMatches(map[string]any{"host": map[string]any{"info": map[string]any{"platformFamily": "rhel", "platform": "ubuntu"}}}, families, platforms)).To(BeFalse())
+		Expect(PlatformMatches(map[string]any{"host": map[string]any{"info": map[string]any{"platform": "ubuntu"}}}, families, platforms)).To(BeTrue())
+		Expect(PlatformMatches(map[string]any{}, families, platforms)).To(BeFalse())
+	})
+})
+
+var _ = Describe("MergeDefaults", func() {
+	It("merges nested maps with values taking precedence", func() {
+		defaults := map[string]any{
+			"a":      1,
+			"nested": map[string]any{"x": 1, "y": 2},
+		}
+		values := map[string]any{
+			"b":      2,
+			"nested": map[string]any{"y": 99},
+		}
+
+		Expect(MergeDefaults(defaults, values)).To(Equal(map[string]any{
+			"a":      1,
+			"b":      2,
+			"nested": map[string]any{"x": 1, "y": 99},
+		}))
+	})
+
+	It("replaces slices and mismatched types", func() {
+		defaults := map[string]any{
+			"list":   []any{1, 2},
+			"nested": map[string]any{"x": 1},
+		}
+		values := map[string]any{
+			"list":   []any{3},
+			"nested": "scalar",
+		}
+
+		Expect(MergeDefaults(defaults, values)).To(Equal(map[string]any{
+			"list":   []any{3},
+			"nested": "scalar",
+		}))
+	})
+
+	It("does not mutate input maps", func() {
+		defaults := map[string]any{"nested": map[string]any{"x": 1}}
+		values := map[string]any{"nested": map[string]any{"y": 2}}
+
+		result := MergeDefaults(defaults, values)
+		result["nested"].(map[string]any)["x"] = 999
+
+		Expect(defaults["nested"]).To(Equal(map[string]any{"x": 1}))
+		Expect(values["nested"]).To(Equal(map[string]any{"y": 2}))
+	})
+})
+
 var _ = Describe("MapStringsToMapStringAny", func() {
 	It("converts map[string]string to map[string]any", func() {
 		input := map[string]string{
@@ -359,6 +440,28 @@ var _ = Describe("FileExists", func() {
 	})
 })
 
+var _ = Describe("ValidateCanonicalPath", func() {
+	DescribeTable("path validation",
+		func(path string, expected string) {
+			err := ValidateCanonicalPath("name", path)
+			if expected == "" {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(expected))
+			}
+		},
+		Entry("root", "/", ""),
+		Entry("canonical", "/a/b", ""),
+		Entry("empty", "", "name must be an absolute path"),
+		Entry("relative", "a/b", "name must be an absolute path"),
+		Entry("relative with dot", "./a", "name must be an absolute path"),
+		Entry("parent element", "/a/../b", "name must be a canonical path"),
+		Entry("current element", "/a/./b", "name must be a canonical path"),
+		Entry("repeated separator", "/a//b", "name must be a canonical path"),
+		Entry("trailing separator", "/a/b/", "name must be a canonical path"),
+	)
+})
+
 var _ = Describe("FileHasSuffix", func() {
 	DescribeTable("suffix matching",
 		func(filename string, suffixes []string, expected bool) {
@@ -0,0 +1,143 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go/micro"
+
+	"github.com/choria-io/ccm/model"
+)
+
+// ControlStatus is the response of the control API status endpoint
+type ControlStatus struct {
+	// Applying reports if an apply requested using the control API is running
+	Applying bool `json:"applying"`
+	// Paused reports if management is paused by the pause marker, errors checking the marker report paused
+	Paused bool `json:"paused"`
+	// Noop reports if the manager is in noop mode
+	Noop bool `json:"noop"`
+	// Summary is the summary of the last session, nil before any manifest was applied
+	Summary *model.SessionSummary `json:"summary,omitempty"`
+}
+
+// ServeControlAPI starts a NATS micro service with apply, status and summary endpoints. The apply endpoint runs
+// the last applied manifest again, applies requested using the API never overlap. The service stops when ctx is
+// canceled. Requires the context be set using WithNatsContext().
+func (m *CCM) ServeControlAPI(ctx context.Context, cfg micro.Config) (micro.Service, error) {
+	nc, err := m.NatsConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	svc, err := micro.AddService(nc, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not start control api: %w", err)
+	}
+
+	endpoints := map[string]micro.HandlerFunc{
+		"apply":   func(req micro.Request) { m.controlApply(ctx, req) },
+		"status":  func(req micro.Request) { m.controlStatus(ctx, req) },
+		"summary": m.controlSummary,
+	}
+
+	for _, name := range []string{"apply", "status", "summary"} {
+		err = svc.AddEndpoint(name, endpoints[name])
+		if err != nil {
+			svc.Stop()
+			return nil, fmt.Errorf("could not add control api endpoint %s: %w", name, err)
+		}
+	}
+
+	go func() {
+		<-ctx.Done()
+		svc.Stop()
+	}()
+
+	return svc, nil
+}
+
+// controlApply runs the last applied manifest and responds with its summary
+func (m *CCM) controlApply(ctx context.Context, req micro.Request) {
+	m.controlMu.Lock()
+	defer m.controlMu.Unlock()
+
+	m.mu.Lock()
+	manifest := m.lastApply
+	m.mu.Unlock()
+
+	if manifest == nil {
+		req.Error("400", "no manifest has been applied", nil)
+		return
+	}
+
+	m.log.Info("Applying manifest requested using the control api")
+
+	err := m.executeManifest(ctx, manifest)
+	if err != nil {
+		req.Error("500", err.Error(), nil)
+		return
+	}
+
+	m.controlSummary(req)
+}
+
+// executeManifest applies manifest reporting it as applying in the control api status, controlMu must be held
+// so applies never overlap
+func (m *CCM) executeManifest(ctx context.Context, manifest model.Apply) error {
+	m.applying.Store(true)
+	defer m.applying.Store(false)
+
+	_, err := manifest.Execute(ctx, m, false, m.userLogger)
+
+	return err
+}
+
+// controlStatus responds with the current management and monitoring state
+func (m *CCM) controlStatus(ctx context.Context, req micro.Request) {
+	status := &ControlStatus{
+		Applying: m.applying.Load(),
+		Noop:     m.NoopMode(),
+	}
+
+	paused, err := m.ManagementPaused(ctx)
+	status.Paused = paused || err != nil
+
+	m.mu.Lock()
+	hasSession := m.lastApply != nil
+	m.mu.Unlock()
+
+	if hasSession {
+		status.Summary, err = m.SessionSummary()
+		if err != nil {
+			req.Error("500", err.Error(), nil)
+			return
+		}
+	}
+
+	req.RespondJSON(status)
+}
+
+// controlSummary responds with the summary of the last session
+func (m *CCM) controlSummary(req micro.Request) {
+	m.mu.Lock()
+	hasSession := m.lastApply != nil
+	m.mu.Unlock()
+
+	if !hasSession {
+		req.Error("404", "no manifest has been applied", nil)
+		return
+	}
+
+	summary, err := m.SessionSummary()
+	if err != nil {
+		req.Error("500", err.Error(), nil)
+		return
+	}
+
+	req.RespondJSON(summary)
+}
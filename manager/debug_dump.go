@@ -0,0 +1,171 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package manager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/choria-io/ccm/model"
+)
+
+// DefaultDebugDumpMaxSize is the largest debug dump written when no size is configured
+const DefaultDebugDumpMaxSize = 10 * 1024 * 1024
+
+// debugDump is the content of a debug dump, sections that could not be gathered or that did not fit in the size
+// limit are listed in Errors and Omitted
+type debugDump struct {
+	Time      time.Time       `json:"time"`
+	Source    string          `json:"source"`
+	Noop      bool            `json:"noop"`
+	Error     string          `json:"error,omitempty"`
+	Facts     json.RawMessage `json:"facts,omitempty"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	Resources json.RawMessage `json:"resources,omitempty"`
+	Events    json.RawMessage `json:"events,omitempty"`
+	Omitted   []string        `json:"omitted,omitempty"`
+	Errors    []string        `json:"errors,omitempty"`
+}
+
+// WriteDebugDump writes the facts, data, effective resources and session events of a failed run to the debug dump
+// directory, values of sensitive properties are redacted wherever they appear. Sections are dropped, largest first,
+// until the dump fits the size limit. Returns the path to the dump, empty when debug dumps are not enabled.
+func (m *CCM) WriteDebugDump(ctx context.Context, apply model.Apply, runErr error) (string, error) {
+	m.mu.Lock()
+	dir := m.debugDumpDir
+	maxSize := m.debugDumpMaxSize
+	noop := m.noop
+	m.mu.Unlock()
+
+	if dir == "" {
+		return "", nil
+	}
+
+	if maxSize <= 0 {
+		maxSize = DefaultDebugDumpMaxSize
+	}
+
+	dump := &debugDump{
+		Time:   time.Now().UTC(),
+		Source: apply.Source(),
+		Noop:   noop,
+	}
+	if runErr != nil {
+		dump.Error = runErr.Error()
+	}
+
+	var sensitive []string
+	for _, r := range apply.Resources() {
+		for _, prop := range r {
+			if prop != nil {
+				sensitive = append(sensitive, model.SensitiveValues(prop)...)
+			}
+		}
+	}
+
+	sections := []struct {
+		name   string
+		target *json.RawMessage
+		gather func() (any, error)
+	}{
+		{"facts", &dump.Facts, func() (any, error) { return m.Facts(ctx) }},
+		{"data", &dump.Data, func() (any, error) { return m.Data(), nil }},
+		{"resources", &dump.Resources, func() (any, error) { return m.EffectiveResources(ctx, apply) }},
+		{"events", &dump.Events, func() (any, error) { return m.debugDumpEvents() }},
+	}
+
+	for _, section := range sections {
+		v, err := section.gather()
+		if err != nil {
+			dump.Errors = append(dump.Errors, fmt.Sprintf("%s: %v", section.name, err))
+			continue
+		}
+
+		j, err := json.Marshal(v)
+		if err != nil {
+			dump.Errors = append(dump.Errors, fmt.Sprintf("%s: %v", section.name, err))
+			continue
+		}
+
+		*section.target = redactValues(j, sensitive)
+	}
+
+	var out []byte
+	for {
+		var err error
+		out, err = json.MarshalIndent(dump, "", "  ")
+		if err != nil {
+			return "", err
+		}
+
+		if int64(len(out)) <= maxSize {
+			break
+		}
+
+		largest := -1
+		for i, section := range sections {
+			if len(*section.target) > 0 && (largest == -1 || len(*section.target) > len(*sections[largest].target)) {
+				largest = i
+			}
+		}
+		if largest == -1 {
+			return "", fmt.Errorf("debug dump exceeds the maximum size of %d bytes", maxSize)
+		}
+
+		*sections[largest].target = nil
+		dump.Omitted = append(dump.Omitted, sections[largest].name)
+	}
+
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("ccm-debug-%s.json", dump.Time.Format("20060102T150405.000000000Z")))
+	err = os.WriteFile(path, out, 0600)
+	if err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+func (m *CCM) debugDumpEvents() ([]model.SessionEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.session == nil {
+		return nil, fmt.Errorf("no session store available")
+	}
+
+	return m.session.AllEvents()
+}
+
+// redactValues replaces every occurrence of the sensitive values in the JSON document j by model.RedactedValue,
+// longer values are replaced first so values containing other values are fully redacted
+func redactValues(j []byte, sensitive []string) []byte {
+	redacted, _ := json.Marshal(model.RedactedValue)
+	redacted = bytes.Trim(redacted, `"`)
+
+	values := slices.Clone(sensitive)
+	slices.SortFunc(values, func(a, b string) int { return len(b) - len(a) })
+
+	for _, value := range slices.Compact(values) {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+
+		j = bytes.ReplaceAll(j, bytes.Trim(encoded, `"`), redacted)
+	}
+
+	return j
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/choria-io/ccm/model"
+)
+
+const (
+	// EventPublishBuffer is how many messages may wait to be published before further messages are dropped
+	EventPublishBuffer = 1000
+
+	// EventPublishFlushTimeout is the maximum time spent publishing waiting messages once a session completed
+	EventPublishFlushTimeout = 2 * time.Second
+)
+
+// eventMessage is a message waiting to be published, messages with flushed set only signal once all
+// earlier messages were published
+type eventMessage struct {
+	subject string
+	data    []byte
+	flushed chan struct{}
+}
+
+// eventPublisher publishes events to NATS subjects in the background so publishing never blocks an apply
+type eventPublisher struct {
+	prefix  string
+	connect func() (model.EventPublisher, error)
+	msgs    chan eventMessage
+	log     model.Logger
+	closed  bool
+
+	mu sync.Mutex
+}
+
+func newEventPublisher(prefix string, connect func() (model.EventPublisher, error), log model.Logger) *eventPublisher {
+	p := &eventPublisher{
+		prefix:  prefix,
+		connect: connect,
+		msgs:    make(chan eventMessage, EventPublishBuffer),
+		log:     log,
+	}
+
+	go p.run()
+
+	return p
+}
+
+// validateSubjectPrefix ensures prefix is a valid NATS subject without wildcards
+func validateSubjectPrefix(prefix string) error {
+	if prefix == "" {
+		return fmt.Errorf("event subject prefix is required")
+	}
+
+	for _, token := range strings.Split(prefix, ".") {
+		if token == "" || token == "*" || token == ">" || strings.ContainsAny(token, " \t\r\n") {
+			return fmt.Errorf("invalid event subject prefix %q", prefix)
+		}
+	}
+
+	return nil
+}
+
+// publishEvent queues a transaction event for publishing to <prefix>.resource.<type>
+func (p *eventPublisher) publishEvent(event *model.TransactionEvent) {
+	p.publish(fmt.Sprintf("%s.resource.%s", p.prefix, event.ResourceType), event)
+}
+
+// publishSummary queues a session summary for publishing to <prefix>.summary
+func (p *eventPublisher) publishSummary(summary *model.SessionSummary) {
+	p.publish(fmt.Sprintf("%s.summary", p.prefix), summary)
+}
+
+// publish queues v for publishing, messages are dropped when the buffer is full
+func (p *eventPublisher) publish(subject string, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		p.log.Warn("Could not encode event for publishing", "subject", subject, "error", err)
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return
+	}
+
+	select {
+	case p.msgs <- eventMessage{subject: subject, data: data}:
+	default:
+		p.log.Warn("Event publish buffer is full, dropping event", "subject", subject)
+	}
+}
+
+// flush waits up to timeout for all queued messages to be published
+func (p *eventPublisher) flush(timeout time.Duration) {
+	flushed := make(chan struct{})
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	select {
+	case p.msgs <- eventMessage{flushed: flushed}:
+	case <-timer.C:
+		p.mu.Unlock()
+		p.log.Warn("Timeout waiting for events to be published")
+		return
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-flushed:
+	case <-timer.C:
+		p.log.Warn("Timeout waiting for events to be published")
+	}
+}
+
+// close stops publishing, messages already queued are still published
+func (p *eventPublisher) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return
+	}
+
+	p.closed = true
+	close(p.msgs)
+}
+
+func (p *eventPublisher) run() {
+	var pub model.EventPublisher
+
+	for msg := range p.msgs {
+		if pub == nil {
+			var err error
+			pub, err = p.connect()
+			if err != nil {
+				p.log.Warn("Could not connect to publish events", "error", err)
+				pub = nil
+			}
+		}
+
+		if msg.flushed != nil {
+			// connections buffer published messages, flush them so they are sent before the process exits
+			if flusher, ok := pub.(interface{ FlushTimeout(time.Duration) error }); ok {
+				err := flusher.FlushTimeout(EventPublishFlushTimeout)
+				if err != nil {
+					p.log.Warn("Could not flush published events", "error", err)
+				}
+			}
+			close(msg.flushed)
+			continue
+		}
+
+		if pub == nil {
+			continue
+		}
+
+		err := pub.Publish(msg.subject, msg.data)
+		if err != nil {
+			p.log.Warn("Could not publish event", "subject", msg.subject, "error", err)
+		}
+	}
+}
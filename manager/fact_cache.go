@@ -0,0 +1,91 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package manager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// factCache stores gathered facts in a JSON file so later runs can reuse them until ttl passed
+type factCache struct {
+	path string
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+type cachedFacts struct {
+	Gathered time.Time      `json:"gathered"`
+	Facts    map[string]any `json:"facts"`
+}
+
+func newFactCache(path string, ttl time.Duration) *factCache {
+	return &factCache{path: path, ttl: ttl, now: time.Now}
+}
+
+// load returns the cached facts, reports false when there are no cached facts or they expired
+func (c *factCache) load() (map[string]any, bool, error) {
+	jb, err := os.ReadFile(c.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var cached cachedFacts
+	err = json.Unmarshal(jb, &cached)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid fact cache %s: %w", c.path, err)
+	}
+
+	if cached.Facts == nil || c.now().Sub(cached.Gathered) >= c.ttl {
+		return nil, false, nil
+	}
+
+	return cached.Facts, true, nil
+}
+
+// store writes facts to the cache, replacing the file so readers never see a partially written cache
+func (c *factCache) store(facts map[string]any) error {
+	jb, err := json.Marshal(cachedFacts{Gathered: c.now(), Facts: facts})
+	if err != nil {
+		return err
+	}
+
+	tf, err := os.CreateTemp(filepath.Dir(c.path), ".facts-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tf.Name())
+
+	_, err = tf.Write(jb)
+	if err != nil {
+		tf.Close()
+		return err
+	}
+
+	err = tf.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tf.Name(), c.path)
+}
+
+// invalidate removes the cache so the next run gathers facts
+func (c *factCache) invalidate() error {
+	err := os.Remove(c.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
@@ -7,9 +7,16 @@ package manager
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/choria-io/ccm/facts"
@@ -20,7 +27,9 @@ import (
 	"github.com/synadia-io/orbit.go/natscontext"
 
 	"github.com/choria-io/ccm/internal/backoff"
+	"github.com/choria-io/ccm/internal/breaker"
 	"github.com/choria-io/ccm/internal/cmdrunner"
+	"github.com/choria-io/ccm/internal/downloadcache"
 	iu "github.com/choria-io/ccm/internal/util"
 	"github.com/choria-io/ccm/model"
 	"github.com/choria-io/ccm/registration"
@@ -32,6 +41,17 @@ import (
 	"github.com/choria-io/ccm/templates"
 )
 
+const (
+	// DefaultJetStreamTimeout is the default maximum time a single JetStream call made using JetStreamCall may take
+	DefaultJetStreamTimeout = 30 * time.Second
+
+	// DefaultJetStreamFailureThreshold is the default number of consecutive failed JetStream calls that opens the circuit breaker
+	DefaultJetStreamFailureThreshold = 5
+
+	// DefaultJetStreamCooldown is the default time JetStream calls fail fast once the circuit breaker opened
+	DefaultJetStreamCooldown = time.Minute
+)
+
 // CCM is the main configuration and change management orchestrator
 type CCM struct {
 	session            model.SessionStore
@@ -43,16 +63,51 @@ type CCM struct {
 	js                 jetstream.JetStream
 	nc                 *nats.Conn
 	ncProvider         model.NatsConnProvider
-
-	noop        bool
-	workingDir  string
-	externData  map[string]any
-	data        map[string]any
-	facts       map[string]any
-	env         map[string]string
-	natsContext string
-
-	mu sync.Mutex
+	secretProvider     templates.SecretProvider
+	jsTimeout          time.Duration
+	jsBreaker          *breaker.Breaker
+
+	noop             bool
+	skipUnmanageable bool
+	runDeadline      time.Duration
+	concurrency      int
+	pauseMarker      string
+	protectedPaths   []string
+	cacheDir         string
+	cacheMaxSize     int64
+	downloadCache    model.DownloadCache
+	eventSink        io.Writer
+	eventFile        *os.File
+	recorder         *cmdrunner.Recorder
+	replayer         *cmdrunner.Replayer
+	refreshStore     model.PendingRefreshStore
+	subscribers      map[string][]string
+	convergedStore   model.ConvergedStateStore
+	trustWindow      time.Duration
+	convergedFailed  bool
+	metricsRecorded  bool
+	debugDumpDir     string
+	debugDumpMaxSize int64
+	reportWebhook    *reportWebhook
+	eventPublisher   *eventPublisher
+	ownsPublisher    bool
+	serviceRegistry  *serviceRegistry
+	lastApply        model.Apply
+	applying         atomic.Bool
+	providerConfig   map[string]map[string]any
+	workingDir       string
+	externData       map[string]any
+	data             map[string]any
+	dataResolver     model.DataResolver
+	dataResolved     map[string]bool
+	facts            map[string]any
+	externalFactsDir string
+	factCache        *factCache
+	env              map[string]string
+	natsContext      string
+
+	mu        sync.Mutex
+	controlMu sync.Mutex
 }
 
 // NewManager creates a new CCM instance with the provided loggers
@@ -66,6 +121,18 @@ func NewManager(log model.Logger, userLogger model.Logger, opts ...Option) (*CCM
 		}
 	}
 
+	if mgr.concurrency == 0 {
+		mgr.concurrency = DefaultConcurrency
+	}
+
+	if mgr.jsTimeout == 0 {
+		mgr.jsTimeout = DefaultJetStreamTimeout
+	}
+
+	if mgr.jsBreaker == nil {
+		mgr.jsBreaker = breaker.New(DefaultJetStreamFailureThreshold, DefaultJetStreamCooldown)
+	}
+
 	if mgr.session == nil {
 		sessionLog, err := mgr.Logger("session", "memory")
 		if err != nil {
@@ -78,6 +145,18 @@ func NewManager(log model.Logger, userLogger model.Logger, opts ...Option) (*CCM
 		}
 	}
 
+	if mgr.cacheDir != "" {
+		cacheLog, err := mgr.Logger("cache", "download")
+		if err != nil {
+			return nil, err
+		}
+
+		mgr.downloadCache, err = downloadcache.New(mgr.cacheDir, mgr.cacheMaxSize, cacheLog)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	mgr.regPublisher = registration.NewNoopPublisher()
 	if mgr.regPublisherDest != "" {
 		var err error
@@ -98,8 +177,27 @@ func (m *CCM) Close() error {
 		m.nc.Close()
 	}
 
+	if m.eventFile != nil {
+		m.eventFile.Close()
+		m.eventFile = nil
+		m.eventSink = nil
+	}
+
+	if m.eventPublisher != nil && m.ownsPublisher {
+		m.eventPublisher.close()
+	}
+	m.eventPublisher = nil
+
+	if m.recorder != nil {
+		m.recorder.Close()
+		m.recorder = nil
+	}
+	m.replayer = nil
+
 	m.js = nil
 	m.data = nil
+	m.dataResolver = nil
+	m.dataResolved = nil
 	m.facts = nil
 	m.env = nil
 	m.externData = nil
@@ -121,14 +219,44 @@ func (m *CCM) CopyFrom(source model.Manager) error {
 	defer src.mu.Unlock()
 
 	m.noop = src.noop
+	m.skipUnmanageable = src.skipUnmanageable
+	m.runDeadline = src.runDeadline
+	m.concurrency = src.concurrency
+	m.pauseMarker = src.pauseMarker
+	m.protectedPaths = slices.Clone(src.protectedPaths)
+	m.cacheDir = src.cacheDir
+	m.cacheMaxSize = src.cacheMaxSize
+	m.downloadCache = src.downloadCache
+	m.eventSink = src.eventSink
+	m.recorder = src.recorder
+	m.replayer = src.replayer
+	m.refreshStore = src.refreshStore
+	m.convergedStore = src.convergedStore
+	m.trustWindow = src.trustWindow
+	m.debugDumpDir = src.debugDumpDir
+	m.debugDumpMaxSize = src.debugDumpMaxSize
+	m.reportWebhook = src.reportWebhook
+	m.eventPublisher = src.eventPublisher
+	m.serviceRegistry = src.serviceRegistry
+	m.providerConfig = make(map[string]map[string]any, len(src.providerConfig))
+	for provider, config := range src.providerConfig {
+		m.providerConfig[provider] = iu.CloneMap(config)
+	}
 	m.workingDir = src.workingDir
 	m.data = iu.CloneMap(src.data)
+	m.dataResolver = src.dataResolver
+	m.dataResolved = maps.Clone(src.dataResolved)
 	m.facts = iu.CloneMap(src.facts)
+	m.externalFactsDir = src.externalFactsDir
+	m.factCache = src.factCache
 	m.env = iu.CloneMapStrings(src.env)
 	m.externData = iu.CloneMap(src.externData)
 	m.natsContext = src.natsContext
 	m.ncProvider = src.ncProvider
+	m.secretProvider = src.secretProvider
 	m.nc = src.nc
+	m.jsTimeout = src.jsTimeout
+	m.jsBreaker = src.jsBreaker
 	m.regPublisher = src.regPublisher
 	m.regPublisherDest = src.regPublisherDest
 	m.regPublisherStream = src.regPublisherStream
@@ -197,6 +325,70 @@ func (m *CCM) JetStream() (jetstream.JetStream, error) {
 	return m.js, nil
 }
 
+// JetStreamCall calls cb with a JetStream connection and a context limited to the JetStream timeout. Consecutive
+// failures caused by an unavailable or degraded NATS open a circuit breaker, while open calls fail fast with an error
+// wrapping breaker.ErrOpen rather than waiting for the timeout. A successful call after the cooldown closes it again.
+func (m *CCM) JetStreamCall(ctx context.Context, cb func(ctx context.Context, js jetstream.JetStream) error) error {
+	m.mu.Lock()
+	timeout := m.jsTimeout
+	jsBreaker := m.jsBreaker
+	m.mu.Unlock()
+
+	err := jsBreaker.Allow()
+	if err != nil {
+		return fmt.Errorf("jetstream unavailable: %w", err)
+	}
+
+	js, err := m.JetStream()
+	if err != nil {
+		jsBreaker.Failure()
+		return err
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	err = cb(ctx, js)
+	switch {
+	case err == nil:
+		jsBreaker.Success()
+	case isJetStreamFailure(err):
+		jsBreaker.Failure()
+		if jsBreaker.IsOpen() {
+			m.log.Error("JetStream calls are failing, circuit breaker opened", "error", err)
+		}
+	default:
+		// errors like a missing key show JetStream is working
+		jsBreaker.Success()
+	}
+
+	return err
+}
+
+// isJetStreamFailure reports if err indicates NATS or JetStream is unavailable or degraded
+func isJetStreamFailure(err error) bool {
+	for _, target := range []error{
+		context.DeadlineExceeded,
+		nats.ErrTimeout,
+		nats.ErrNoResponders,
+		nats.ErrConnectionClosed,
+		nats.ErrConnectionDraining,
+		nats.ErrDisconnected,
+		nats.ErrNoServers,
+		jetstream.ErrJetStreamNotEnabled,
+		jetstream.ErrJetStreamNotEnabledForAccount,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func DefaultNatsOptions(log model.Logger) []nats.Option {
 	return []nats.Option{
 		nats.Name("choria-ccm"),
@@ -272,17 +464,95 @@ func (m *CCM) WorkingDirectory() string {
 	return m.workingDir
 }
 
-// SetData sets the resolved Hiera data for the manager
+// SetData sets the resolved Hiera data for the manager, this is a full refresh that replaces any data resolver
 func (m *CCM) SetData(data map[string]any) map[string]any {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	copied := iu.DeepMergeMap(data, m.externData)
 	m.data = copied
+	m.dataResolver = nil
+	m.dataResolved = nil
 
 	return m.data
 }
 
+// SetDataValue sets a single top level data key, resources use this to pass values to resources applied after them.
+// Template environments created earlier are not affected.
+func (m *CCM) SetDataValue(key string, value any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	data := maps.Clone(m.data)
+	if data == nil {
+		data = map[string]any{}
+	}
+	data[key] = value
+	m.data = data
+
+	if m.dataResolved != nil {
+		m.dataResolved[key] = true
+	}
+}
+
+// SetDataResolver sets a resolver that supplies Hiera data on demand, only keys referenced by templates are
+// resolved and they are cached until the next call to SetData or SetDataResolver
+func (m *CCM) SetDataResolver(resolver model.DataResolver) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.dataResolver = resolver
+	m.dataResolved = map[string]bool{}
+	m.data = map[string]any{}
+}
+
+// resolveData resolves keys using the data resolver and caches them, without keys all data is resolved
+func (m *CCM) resolveData(keys ...string) (map[string]any, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(keys) == 0 {
+		if m.dataResolver != nil {
+			keys = m.dataResolver.Keys()
+		}
+		for key := range m.externData {
+			keys = append(keys, key)
+		}
+		for key := range m.data {
+			keys = append(keys, key)
+		}
+	}
+
+	res := make(map[string]any, len(keys))
+
+	for _, key := range keys {
+		if m.dataResolver != nil && !m.dataResolved[key] {
+			val, ok, err := m.dataResolver.Lookup(key)
+			if err != nil {
+				return nil, fmt.Errorf("could not resolve data key %q: %w", key, err)
+			}
+
+			ext, hasExt := m.externData[key]
+			switch {
+			case ok && hasExt:
+				m.data[key] = iu.DeepMergeMap(map[string]any{key: val}, map[string]any{key: ext})[key]
+			case ok:
+				m.data[key] = val
+			case hasExt:
+				m.data[key] = iu.CloneValue(ext)
+			}
+
+			m.dataResolved[key] = true
+		}
+
+		if val, ok := m.data[key]; ok {
+			res[key] = val
+		}
+	}
+
+	return res, nil
+}
+
 // SetEnviron sets the environment data for the manager
 func (m *CCM) SetEnviron(env map[string]string) {
 	m.mu.Lock()
@@ -299,14 +569,12 @@ func (m *CCM) Environment() map[string]string {
 	return m.env
 }
 
-// Data returns the resolved Hiera data
+// Data returns the resolved Hiera data, when a data resolver is set all keys are resolved
 func (m *CCM) Data() map[string]any {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	ret := make(map[string]any)
-	for k, v := range m.data {
-		ret[k] = v
+	ret, err := m.resolveData()
+	if err != nil {
+		m.log.Error("Could not resolve data", "error", err)
+		return map[string]any{}
 	}
 
 	return ret
@@ -418,9 +686,39 @@ func (m *CCM) StartSession(apply model.Apply) (model.SessionStore, error) {
 		return nil, fmt.Errorf("no session store available")
 	}
 
+	if m.refreshStore != nil {
+		m.subscribers = subscribersOf(apply.Resources())
+	}
+
+	m.convergedFailed = false
+	m.metricsRecorded = false
+	m.lastApply = apply
+
 	return m.session, m.session.StartSession(apply)
 }
 
+// subscribersOf maps resource references, by name and alias, to the ids of the resources subscribed to them
+func subscribersOf(resources []map[string]model.ResourceProperties) map[string][]string {
+	subscribers := make(map[string][]string)
+
+	for _, r := range resources {
+		for _, prop := range r {
+			sp, ok := prop.(model.SubscribingResourceProperties)
+			if !ok {
+				continue
+			}
+
+			cp := prop.CommonProperties()
+			id := fmt.Sprintf("%s#%s", cp.Type, cp.Name)
+			for _, sub := range sp.Subscriptions() {
+				subscribers[sub] = append(subscribers[sub], id)
+			}
+		}
+	}
+
+	return subscribers
+}
+
 // FactsRaw returns the system facts as a JSON raw message
 func (m *CCM) FactsRaw(ctx context.Context) (json.RawMessage, error) {
 	f, err := m.Facts(ctx)
@@ -432,6 +730,7 @@ func (m *CCM) FactsRaw(ctx context.Context) (json.RawMessage, error) {
 	return j, err
 }
 
+// SetFacts replaces the facts, the fact cache is left as is as it only holds gathered facts
 func (m *CCM) SetFacts(facts map[string]any) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -439,7 +738,8 @@ func (m *CCM) SetFacts(facts map[string]any) {
 	m.facts = facts
 }
 
-// MergeFacts merges the provided facts with the facts as gathered by Facts(), which may have been set by SetFacts()
+// MergeFacts merges the provided facts with the facts as gathered by Facts(), which may have been set by SetFacts(),
+// the merged facts are not written to the fact cache
 func (m *CCM) MergeFacts(ctx context.Context, facts map[string]any) (map[string]any, error) {
 	sf, err := m.Facts(ctx)
 	if err != nil {
@@ -459,20 +759,31 @@ func (m *CCM) SystemFacts(ctx context.Context) (map[string]any, error) {
 	var to context.Context
 	var cancel context.CancelFunc
 
+	// not locked as Facts() calls this while holding the lock, the directory is only set when creating the manager
+	externalFactsDir := m.externalFactsDir
+
 	_, ok := ctx.Deadline()
 	if ok {
 		to = ctx
 	} else {
-		to, cancel = context.WithTimeout(ctx, 2*time.Second)
+		timeout := 2 * time.Second
+		if externalFactsDir != "" {
+			// external facts scripts are slower than the built in facts, give them time to complete
+			timeout += facts.ScriptTimeout
+		}
+
+		to, cancel = context.WithTimeout(ctx, timeout)
 		defer cancel()
 	}
 
 	cfg := model.NewFactsConfig()
+	cfg.ExternalFactsDirectory = externalFactsDir
 
 	return facts.Gather(to, *cfg, m.log)
 }
 
-// Facts gather system facts, cache them, and return them, if already cached return the cache
+// Facts gather system facts, cache them, and return them, if already cached return the cache. When a fact cache is
+// configured facts in it are used until they expire
 func (m *CCM) Facts(ctx context.Context) (map[string]any, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -481,7 +792,7 @@ func (m *CCM) Facts(ctx context.Context) (map[string]any, error) {
 		return m.facts, nil
 	}
 
-	f, err := m.SystemFacts(ctx)
+	f, err := m.GatherFacts(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -491,6 +802,37 @@ func (m *CCM) Facts(ctx context.Context) (map[string]any, error) {
 	return f, nil
 }
 
+// GatherFacts returns the system facts, when a fact cache is configured facts in it are used until they expire and
+// newly gathered facts are written to it. Unlike Facts() the facts of the manager are not set
+func (m *CCM) GatherFacts(ctx context.Context) (map[string]any, error) {
+	// not locked as Facts() calls this while holding the lock, the cache is only set when creating the manager
+	cache := m.factCache
+	if cache == nil {
+		return m.SystemFacts(ctx)
+	}
+
+	f, ok, err := cache.load()
+	switch {
+	case err != nil:
+		m.log.Warn("Could not read fact cache, gathering facts", "error", err)
+	case ok:
+		m.log.Debug("Using cached facts", "cache", cache.path)
+		return f, nil
+	}
+
+	f, err = m.SystemFacts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cache.store(f)
+	if err != nil {
+		m.log.Warn("Could not write fact cache", "error", err)
+	}
+
+	return f, nil
+}
+
 // Logger creates a new logger with the provided key-value pairs added to the context
 func (m *CCM) Logger(args ...any) (model.Logger, error) {
 	if len(args)%2 != 0 {
@@ -505,8 +847,19 @@ func (m *CCM) UserLogger() model.Logger {
 	return m.userLogger
 }
 
-// NewRunner creates a new command runner instance
+// NewRunner creates a new command runner instance, when recording or replaying commands the shared recorder or replayer is returned
 func (m *CCM) NewRunner() (model.CommandRunner, error) {
+	m.mu.Lock()
+	recorder, replayer := m.recorder, m.replayer
+	m.mu.Unlock()
+
+	switch {
+	case replayer != nil:
+		return replayer, nil
+	case recorder != nil:
+		return recorder, nil
+	}
+
 	log, err := m.Logger("component", "runner")
 	if err != nil {
 		return nil, err
@@ -530,7 +883,68 @@ func (m *CCM) RecordEvent(event *model.TransactionEvent) error {
 		return fmt.Errorf("resource type cannot be empty")
 	}
 
-	return m.session.RecordEvent(event)
+	err := m.session.RecordEvent(event)
+	m.writeEventSink(event)
+	if m.eventPublisher != nil {
+		m.eventPublisher.publishEvent(event)
+	}
+	m.updatePendingRefreshes(event)
+
+	return err
+}
+
+// updatePendingRefreshes clears the pending refresh of a successfully applied resource and records one for every
+// resource subscribed to a changed resource, failures are logged as the refresh still happens in the current run
+func (m *CCM) updatePendingRefreshes(event *model.TransactionEvent) {
+	if m.refreshStore == nil || event.Noop || event.HealthCheckOnly {
+		return
+	}
+
+	source := fmt.Sprintf("%s#%s", event.ResourceType, event.Name)
+
+	if !event.Failed && !event.Skipped {
+		err := m.refreshStore.ClearPendingRefresh(source)
+		if err != nil {
+			m.log.Warn("Could not clear pending refresh", "resource", source, "error", err)
+		}
+	}
+
+	if !event.Changed {
+		return
+	}
+
+	refs := []string{source}
+	if event.Alias != "" {
+		refs = append(refs, fmt.Sprintf("%s#%s", event.ResourceType, event.Alias))
+	}
+
+	for _, ref := range refs {
+		for _, subscriber := range m.subscribers[ref] {
+			err := m.refreshStore.AddPendingRefresh(subscriber, source)
+			if err != nil {
+				m.log.Warn("Could not record pending refresh", "resource", subscriber, "source", source, "error", err)
+			}
+		}
+	}
+}
+
+// writeEventSink writes the event as a single JSON line to the event sink, failures are logged as the
+// sink is informational and should never fail the apply
+func (m *CCM) writeEventSink(event *model.TransactionEvent) {
+	if m.eventSink == nil {
+		return
+	}
+
+	j, err := json.Marshal(event)
+	if err != nil {
+		m.log.Warn("Could not encode event for the event sink", "error", err)
+		return
+	}
+
+	_, err = m.eventSink.Write(append(j, '\n'))
+	if err != nil {
+		m.log.Warn("Could not write event to the event sink", "error", err)
+	}
 }
 
 // PublishRegistration publishes a registration event to the target
@@ -562,6 +976,11 @@ func (m *CCM) findEvents(resourceType string, resourceName string) ([]model.Tran
 	return events, nil
 }
 
+// ResourceEvents returns the events recorded in the session for the resource indicated by the type and name, latest event last
+func (m *CCM) ResourceEvents(resourceType string, resourceName string) ([]model.TransactionEvent, error) {
+	return m.findEvents(resourceType, resourceName)
+}
+
 // ShouldRefresh returns true if the last transaction event for the resource indicated by the type and name was changed
 func (m *CCM) ShouldRefresh(resourceType string, resourceName string) (bool, error) {
 	events, err := m.findEvents(resourceType, resourceName)
@@ -576,6 +995,24 @@ func (m *CCM) ShouldRefresh(resourceType string, resourceName string) (bool, err
 	return events[len(events)-1].Changed, nil
 }
 
+// PendingRefresh returns the resource that triggered a refresh of the resource indicated by the type and name in a
+// previous run that was not yet processed, empty when none is pending or refresh state is not persisted
+func (m *CCM) PendingRefresh(resourceType string, resourceName string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.refreshStore == nil {
+		return "", nil
+	}
+
+	source, err := m.refreshStore.PendingRefresh(fmt.Sprintf("%s#%s", resourceType, resourceName))
+	if err != nil {
+		return "", fmt.Errorf("could not retrieve pending refresh for %s#%s: %w", resourceType, resourceName, err)
+	}
+
+	return source, nil
+}
+
 func (m *CCM) IsResourceFailed(resourceType string, resourceName string) (bool, error) {
 	events, err := m.findEvents(resourceType, resourceName)
 	if err != nil {
@@ -604,7 +1041,16 @@ func (m *CCM) SessionSummary() (*model.SessionSummary, error) {
 		return nil, err
 	}
 
-	return model.BuildSessionSummary(events), nil
+	summary := model.BuildSessionSummary(events)
+
+	// callers summarize a session once it completed, only the first summary is counted so
+	// reports rendered from the same session do not inflate the counters
+	if !m.metricsRecorded {
+		updateSessionMetrics(summary)
+		m.metricsRecorded = true
+	}
+
+	return summary, nil
 }
 
 func (m *CCM) TemplateEnvironment(ctx context.Context) (*templates.Env, error) {
@@ -618,6 +1064,11 @@ func (m *CCM) TemplateEnvironment(ctx context.Context) (*templates.Env, error) {
 
 	env := &templates.Env{Facts: f, Data: m.data, Environ: m.env, WorkingDir: m.workingDir}
 
+	if m.dataResolver != nil {
+		env.Data = maps.Clone(m.data)
+		env.DataFunc = m.resolveData
+	}
+
 	env.RegistrationsFunc = func(cluster, protocol, service, ip string) (any, error) {
 		return registration.JetStreamLookup(ctx, m, cluster, protocol, service, ip)
 	}
@@ -626,6 +1077,8 @@ func (m *CCM) TemplateEnvironment(ctx context.Context) (*templates.Env, error) {
 		return m.templateKVGet(ctx, bucket, key)
 	}
 
+	env.SecretProvider = m.secretProvider
+
 	return env, nil
 }
 
@@ -639,22 +1092,288 @@ func (m *CCM) templateKVGet(ctx context.Context, bucket, key string) (string, er
 		return "", fmt.Errorf("key is required")
 	}
 
-	js, err := m.JetStream()
+	var value string
+
+	err := m.JetStreamCall(ctx, func(ctx context.Context, js jetstream.JetStream) error {
+		kv, err := js.KeyValue(ctx, bucket)
+		if err != nil {
+			return fmt.Errorf("could not access KV bucket %q: %w", bucket, err)
+		}
+
+		entry, err := kv.Get(ctx, key)
+		if err != nil {
+			return fmt.Errorf("could not get key %q from bucket %q: %w", key, bucket, err)
+		}
+
+		value = string(entry.Value())
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return value, nil
+}
+
+// PauseMarker is the file path or kv://Bucket/Key whose presence pauses management, empty when not set
+func (m *CCM) PauseMarker() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.pauseMarker
+}
+
+// ManagementPaused reports if the pause marker set using WithPauseMarker is present, while paused applies
+// only run health checks. Errors are returned when the presence of the marker could not be determined.
+func (m *CCM) ManagementPaused(ctx context.Context) (bool, error) {
+	marker := m.PauseMarker()
+	if marker == "" {
+		return false, nil
+	}
+
+	bucket, key, isKV := parseKVMarker(marker)
+	if !isKV {
+		_, err := os.Stat(marker)
+		switch {
+		case err == nil:
+			return true, nil
+		case errors.Is(err, os.ErrNotExist):
+			return false, nil
+		default:
+			return false, fmt.Errorf("could not check pause marker: %w", err)
+		}
+	}
+
+	err := m.JetStreamCall(ctx, func(ctx context.Context, js jetstream.JetStream) error {
+		kv, err := js.KeyValue(ctx, bucket)
+		if err != nil {
+			return fmt.Errorf("could not access KV bucket %q: %w", bucket, err)
+		}
+
+		_, err = kv.Get(ctx, key)
+		return err
+	})
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, jetstream.ErrKeyNotFound):
+		return false, nil
+	default:
+		return false, fmt.Errorf("could not check pause marker: %w", err)
+	}
+}
+
+// parseKVMarker parses a pause marker in kv://Bucket/Key format
+func parseKVMarker(marker string) (bucket string, key string, ok bool) {
+	rest, ok := strings.CutPrefix(marker, "kv://")
+	if !ok {
+		return "", "", false
+	}
+
+	bucket, key, _ = strings.Cut(rest, "/")
+
+	return bucket, key, true
+}
+
+// TrustConverged reports if the resource described by prop was found in its desired state by an earlier run with
+// identical inputs within the trust window set using WithConvergedStateDirectory, such resources do not need to be
+// checked. The digest summarizes the inputs of the resource and should be passed to RecordConverged.
+func (m *CCM) TrustConverged(ctx context.Context, prop model.ResourceProperties) (bool, string, error) {
+	m.mu.Lock()
+	store, window := m.convergedStore, m.trustWindow
+	m.mu.Unlock()
+
+	if store == nil || !isTrustable(prop) {
+		return false, "", nil
+	}
+
+	cp := prop.CommonProperties()
+
+	digest, err := m.convergedDigest(ctx, prop)
+	if err != nil {
+		return false, "", fmt.Errorf("could not calculate inputs of %s#%s: %w", cp.Type, cp.Name, err)
+	}
+
+	recorded, since, err := store.Converged(fmt.Sprintf("%s#%s", cp.Type, cp.Name))
+	if err != nil {
+		return false, "", fmt.Errorf("could not retrieve converged state for %s#%s: %w", cp.Type, cp.Name, err)
+	}
+
+	return recorded == digest && time.Since(since) < window, digest, nil
+}
+
+// RecordConverged records the resource described by prop as converged when event found it in its desired state,
+// a failed resource removes all records so the next run checks every resource
+func (m *CCM) RecordConverged(prop model.ResourceProperties, digest string, event *model.TransactionEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.convergedStore == nil || digest == "" || event.Trusted || event.Noop || event.HealthCheckOnly {
+		return nil
+	}
+
+	if event.Failed {
+		if m.convergedFailed {
+			return nil
+		}
+
+		m.convergedFailed = true
+
+		return m.convergedStore.ClearConverged()
+	}
+
+	if m.convergedFailed || event.Skipped || event.Changed || event.Refreshed {
+		return nil
+	}
+
+	cp := prop.CommonProperties()
+
+	return m.convergedStore.RecordConverged(fmt.Sprintf("%s#%s", cp.Type, cp.Name), digest)
+}
+
+// convergedDigest summarizes the inputs of a resource, its properties with templates resolved using the current
+// facts and data, the content of local file sources and the data
+func (m *CCM) convergedDigest(ctx context.Context, prop model.ResourceProperties) (string, error) {
+	resolved, err := model.CopyResourceProperties(prop)
+	if err != nil {
+		return "", err
+	}
+
+	env, err := m.TemplateEnvironment(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	err = resolved.ResolveTemplates(env)
+	if err != nil {
+		return "", err
+	}
+
+	err = resolved.ResolveDeferredTemplates(env)
+	if err != nil {
+		return "", err
+	}
+
+	sources, err := m.localSourceDigests(resolved)
+	if err != nil {
+		return "", err
+	}
+
+	inputs, err := json.Marshal(map[string]any{
+		"type":       prop.CommonProperties().Type,
+		"properties": resolved,
+		"sources":    sources,
+		"data":       m.Data(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return iu.Sha256HashBytes(inputs)
+}
+
+// localSourceDigests summarizes the content of the local sources of file resources by path, relative paths are
+// relative to the working directory. Missing sources have an empty digest.
+func (m *CCM) localSourceDigests(prop model.ResourceProperties) (map[string]string, error) {
+	fp, ok := prop.(*model.FileResourceProperties)
+	if !ok {
+		return nil, nil
+	}
+
+	var sources []string
+	if fp.Source != "" {
+		sources = append(sources, fp.Source)
+	}
+	for _, source := range fp.Sources {
+		if !model.IsUrlSource(source) {
+			sources = append(sources, source)
+		}
+	}
+
+	if len(sources) == 0 {
+		return nil, nil
+	}
+
+	wd := m.WorkingDirectory()
+	digests := make(map[string]string, len(sources))
+
+	for _, source := range sources {
+		path := source
+		if !filepath.IsAbs(path) && wd != "" {
+			path = filepath.Join(wd, path)
+		}
+
+		digest, err := pathDigest(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read source %s: %w", source, err)
+		}
+
+		digests[source] = digest
+	}
+
+	return digests, nil
+}
+
+// pathDigest summarizes the content of a file, or of every file below a directory, empty when path does not exist
+func pathDigest(path string) (string, error) {
+	stat, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
 	if err != nil {
 		return "", err
 	}
 
-	kv, err := js.KeyValue(ctx, bucket)
+	if !stat.IsDir() {
+		return iu.Sha256HashFile(path)
+	}
+
+	files := map[string]string{}
+	err = filepath.WalkDir(path, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(path, p)
+		if err != nil {
+			return err
+		}
+
+		files[rel], err = iu.Sha256HashFile(p)
+
+		return err
+	})
 	if err != nil {
-		return "", fmt.Errorf("could not access KV bucket %q: %w", bucket, err)
+		return "", err
 	}
 
-	entry, err := kv.Get(ctx, key)
+	j, err := json.Marshal(files)
 	if err != nil {
-		return "", fmt.Errorf("could not get key %q from bucket %q: %w", key, bucket, err)
+		return "", err
+	}
+
+	return iu.Sha256HashBytes(j)
+}
+
+// isTrustable determines if a resource can be trusted to be converged based on an earlier run, resources whose
+// outcome depends on other resources or on runtime state are always checked
+func isTrustable(prop model.ResourceProperties) bool {
+	cp := prop.CommonProperties()
+
+	if cp.Type == model.ApplyTypeName || cp.ApplyIf != "" || cp.Control != nil || len(cp.HealthChecks) > 0 || len(cp.RegisterWhenStable) > 0 {
+		return false
+	}
+
+	sp, ok := prop.(model.SubscribingResourceProperties)
+	if ok && len(sp.Subscriptions()) > 0 {
+		return false
 	}
 
-	return string(entry.Value()), nil
+	return true
 }
 
 // NoopMode reports the noop mode
@@ -665,6 +1384,102 @@ func (m *CCM) NoopMode() bool {
 	return m.noop
 }
 
+// SkipIfUnmanageable reports if resources that no provider can manage should be skipped as not applicable
+func (m *CCM) SkipIfUnmanageable() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.skipUnmanageable
+}
+
+// ResourceGraph builds the require and subscribe graph for the resources in apply, resources excluded by their
+// control expressions are marked using the current facts and data
+func (m *CCM) ResourceGraph(ctx context.Context, apply model.Apply) (*model.ResourceGraph, error) {
+	env, err := m.TemplateEnvironment(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.BuildResourceGraph(apply.Resources(), env)
+}
+
+// EffectiveResources returns the resources of apply that will be managed on this node with the properties as passed
+// to each resource, after templates and defaults were resolved. Resources whose control conditions exclude them are
+// removed and the values of sensitive properties are redacted.
+func (m *CCM) EffectiveResources(ctx context.Context, apply model.Apply) ([]map[string]model.ResourceProperties, error) {
+	env, err := m.TemplateEnvironment(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []map[string]model.ResourceProperties{}
+
+	for _, r := range apply.Resources() {
+		for typeName, prop := range r {
+			if prop == nil {
+				continue
+			}
+
+			cp := prop.CommonProperties()
+			if cp.Control != nil {
+				manage, err := cp.Control.ShouldManage(env)
+				if err != nil {
+					return nil, fmt.Errorf("%s#%s: %w", cp.Type, cp.Name, err)
+				}
+				if !manage {
+					continue
+				}
+			}
+
+			redacted, err := model.RedactedResourceProperties(prop)
+			if err != nil {
+				return nil, fmt.Errorf("%s#%s: %w", cp.Type, cp.Name, err)
+			}
+
+			res = append(res, map[string]model.ResourceProperties{typeName: redacted})
+		}
+	}
+
+	return res, nil
+}
+
+// RunDeadline is the maximum time a manifest apply may take, 0 when unlimited
+func (m *CCM) RunDeadline() time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.runDeadline
+}
+
+// ProtectedPaths are paths that are never removed by resources, the defaults and any added using WithProtectedPaths
+func (m *CCM) ProtectedPaths() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append(slices.Clone(model.DefaultProtectedPaths), m.protectedPaths...)
+}
+
+// ProviderConfig returns the configuration set for the named provider using WithProviderConfig, nil when none is set
+func (m *CCM) ProviderConfig(provider string) map[string]any {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	config, ok := m.providerConfig[provider]
+	if !ok {
+		return nil
+	}
+
+	return iu.CloneMap(config)
+}
+
+// DownloadCache returns the shared download cache, nil when no cache is configured
+func (m *CCM) DownloadCache() model.DownloadCache {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.downloadCache
+}
+
 // SetNoopMode sets the noop mode
 func (m *CCM) SetNoopMode(noop bool) {
 	m.mu.Lock()
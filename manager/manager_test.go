@@ -5,18 +5,38 @@
 package manager
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/nats-io/nats.go"
 	"github.com/nats-io/nats.go/jetstream"
+	"github.com/nats-io/nats.go/micro"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"go.uber.org/mock/gomock"
 
+	"github.com/choria-io/ccm/internal/backoff"
+	"github.com/choria-io/ccm/internal/breaker"
 	"github.com/choria-io/ccm/model"
 	"github.com/choria-io/ccm/model/modelmocks"
+	"github.com/choria-io/ccm/resources/apply"
+	"github.com/choria-io/ccm/templates"
 )
 
 func TestManager(t *testing.T) {
@@ -112,6 +132,186 @@ var _ = Describe("JetStream", func() {
 	})
 })
 
+var _ = Describe("JetStreamCall", func() {
+	var (
+		ctrl    *gomock.Controller
+		mockLog *modelmocks.MockLogger
+		mockJS  *modelmocks.MockJetStream
+		ctx     context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockLog = modelmocks.NewMockLogger(ctrl)
+		mockJS = modelmocks.NewMockJetStream(ctrl)
+		mockLog.EXPECT().With(gomock.Any()).AnyTimes().Return(mockLog)
+		mockLog.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("validates the options", func() {
+		_, err := NewManager(mockLog, mockLog, WithJetStreamTimeout(0))
+		Expect(err).To(MatchError("jetstream timeout must be positive"))
+
+		_, err = NewManager(mockLog, mockLog, WithJetStreamCircuitBreaker(-1, time.Minute))
+		Expect(err).To(MatchError("jetstream failure threshold cannot be negative"))
+
+		_, err = NewManager(mockLog, mockLog, WithJetStreamCircuitBreaker(2, 0))
+		Expect(err).To(MatchError("jetstream cooldown must be positive"))
+
+		mgr, err := NewManager(mockLog, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mgr.jsTimeout).To(Equal(DefaultJetStreamTimeout))
+		Expect(mgr.jsBreaker).NotTo(BeNil())
+	})
+
+	It("limits calls to the timeout", func() {
+		mgr, err := NewManager(mockLog, mockLog, WithJetStreamTimeout(20*time.Millisecond))
+		Expect(err).NotTo(HaveOccurred())
+		mgr.js = mockJS
+
+		err = mgr.JetStreamCall(ctx, func(ctx context.Context, js jetstream.JetStream) error {
+			Expect(js).To(Equal(mockJS))
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+	})
+
+	It("fails fast once the breaker opened and resets after a successful call", func() {
+		mockLog.EXPECT().Error("JetStream calls are failing, circuit breaker opened", gomock.Any()).Times(2)
+
+		mgr, err := NewManager(mockLog, mockLog, WithJetStreamCircuitBreaker(2, 50*time.Millisecond))
+		Expect(err).NotTo(HaveOccurred())
+		mgr.js = mockJS
+
+		calls := 0
+		failing := func(ctx context.Context, js jetstream.JetStream) error {
+			calls++
+			return nats.ErrTimeout
+		}
+		working := func(ctx context.Context, js jetstream.JetStream) error {
+			calls++
+			return nil
+		}
+
+		Expect(mgr.JetStreamCall(ctx, failing)).To(MatchError(nats.ErrTimeout))
+		Expect(mgr.JetStreamCall(ctx, failing)).To(MatchError(nats.ErrTimeout))
+		Expect(calls).To(Equal(2))
+
+		err = mgr.JetStreamCall(ctx, working)
+		Expect(err).To(MatchError(breaker.ErrOpen))
+		Expect(err).To(MatchError(ContainSubstring("jetstream unavailable: circuit breaker is open after 2 consecutive failures")))
+		Expect(calls).To(Equal(2))
+
+		// a failed trial call after the cooldown opens the breaker again
+		time.Sleep(60 * time.Millisecond)
+		Expect(mgr.JetStreamCall(ctx, failing)).To(MatchError(nats.ErrTimeout))
+		Expect(mgr.JetStreamCall(ctx, working)).To(MatchError(breaker.ErrOpen))
+		Expect(calls).To(Equal(3))
+
+		time.Sleep(60 * time.Millisecond)
+		Expect(mgr.JetStreamCall(ctx, working)).To(Succeed())
+		Expect(mgr.JetStreamCall(ctx, working)).To(Succeed())
+		Expect(calls).To(Equal(5))
+	})
+
+	It("does not count errors that show JetStream is working", func() {
+		mgr, err := NewManager(mockLog, mockLog, WithJetStreamCircuitBreaker(1, time.Minute))
+		Expect(err).NotTo(HaveOccurred())
+		mgr.js = mockJS
+
+		for range 3 {
+			err = mgr.JetStreamCall(ctx, func(ctx context.Context, js jetstream.JetStream) error {
+				return jetstream.ErrKeyNotFound
+			})
+			Expect(err).To(MatchError(jetstream.ErrKeyNotFound))
+		}
+	})
+})
+
+var _ = Describe("ManagementPaused", func() {
+	var (
+		ctrl    *gomock.Controller
+		mockLog *modelmocks.MockLogger
+		ctx     context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockLog = modelmocks.NewMockLogger(ctrl)
+		mockLog.EXPECT().With(gomock.Any()).AnyTimes().Return(mockLog)
+		ctx = context.Background()
+	})
+
+	It("validates the marker", func() {
+		_, err := NewManager(mockLog, mockLog, WithPauseMarker(""))
+		Expect(err).To(MatchError("pause marker is required"))
+
+		_, err = NewManager(mockLog, mockLog, WithPauseMarker("kv://CCM"))
+		Expect(err).To(MatchError(`pause marker "kv://CCM" must be in kv://Bucket/Key format`))
+	})
+
+	It("is never paused without a marker", func() {
+		mgr, err := NewManager(mockLog, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+
+		paused, err := mgr.ManagementPaused(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(paused).To(BeFalse())
+	})
+
+	It("is paused while the marker file exists", func() {
+		marker := filepath.Join(GinkgoT().TempDir(), "pause")
+
+		mgr, err := NewManager(mockLog, mockLog, WithPauseMarker(marker))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mgr.PauseMarker()).To(Equal(marker))
+
+		paused, err := mgr.ManagementPaused(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(paused).To(BeFalse())
+
+		Expect(os.WriteFile(marker, []byte("maintenance"), 0600)).To(Succeed())
+		paused, err = mgr.ManagementPaused(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(paused).To(BeTrue())
+
+		Expect(os.Remove(marker)).To(Succeed())
+		paused, err = mgr.ManagementPaused(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(paused).To(BeFalse())
+	})
+
+	It("is paused while the marker key exists", func() {
+		mockJS := modelmocks.NewMockJetStream(ctrl)
+		mockKV := modelmocks.NewMockKeyValue(ctrl)
+		mockJS.EXPECT().KeyValue(gomock.Any(), "CCM").Return(mockKV, nil).Times(3)
+
+		mgr, err := NewManager(mockLog, mockLog, WithPauseMarker("kv://CCM/pause"))
+		Expect(err).NotTo(HaveOccurred())
+		mgr.js = mockJS
+
+		mockKV.EXPECT().Get(gomock.Any(), "pause").Return(modelmocks.NewMockKeyValueEntry(ctrl), nil)
+		paused, err := mgr.ManagementPaused(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(paused).To(BeTrue())
+
+		mockKV.EXPECT().Get(gomock.Any(), "pause").Return(nil, jetstream.ErrKeyNotFound)
+		paused, err = mgr.ManagementPaused(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(paused).To(BeFalse())
+
+		mockKV.EXPECT().Get(gomock.Any(), "pause").Return(nil, fmt.Errorf("permission denied"))
+		_, err = mgr.ManagementPaused(ctx)
+		Expect(err).To(MatchError("could not check pause marker: permission denied"))
+	})
+})
+
 var _ = Describe("WithNoop", func() {
 	var (
 		ctrl    *gomock.Controller
@@ -142,6 +342,49 @@ var _ = Describe("WithNoop", func() {
 	})
 })
 
+var _ = Describe("WithProtectedPaths", func() {
+	var (
+		ctrl    *gomock.Controller
+		mockLog *modelmocks.MockLogger
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockLog = modelmocks.NewMockLogger(ctrl)
+		mockLog.EXPECT().With(gomock.Any()).AnyTimes().Return(mockLog)
+		mockLog.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("adds paths to the default protected paths", func() {
+		mgr, err := NewManager(mockLog, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mgr.ProtectedPaths()).To(Equal(model.DefaultProtectedPaths))
+
+		mgr, err = NewManager(mockLog, mockLog, WithProtectedPaths("/srv/data", "/data"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mgr.ProtectedPaths()).To(ContainElements("/", "/etc", "/home", "/srv/data", "/data"))
+		Expect(model.DefaultProtectedPaths).ToNot(ContainElement("/srv/data"))
+	})
+
+	It("rejects paths that are not canonical", func() {
+		_, err := NewManager(mockLog, mockLog, WithProtectedPaths("srv/data"))
+		Expect(err).To(MatchError(ContainSubstring("protected path must be an absolute path")))
+
+		_, err = NewManager(mockLog, mockLog, WithProtectedPaths("/srv/data/"))
+		Expect(err).To(MatchError(ContainSubstring("protected path must be a canonical path")))
+
+		_, err = NewManager(mockLog, mockLog, WithProtectedPaths("/etc//shadow"))
+		Expect(err).To(MatchError(ContainSubstring("protected path must be a canonical path")))
+
+		_, err = NewManager(mockLog, mockLog, WithProtectedPaths("/srv/../etc"))
+		Expect(err).To(MatchError(ContainSubstring("protected path must be a canonical path")))
+	})
+})
+
 var _ = Describe("WithEnvironmentData", func() {
 	var (
 		ctrl    *gomock.Controller
@@ -177,6 +420,154 @@ var _ = Describe("WithEnvironmentData", func() {
 	})
 })
 
+var _ = Describe("WithFactCache", func() {
+	var (
+		mockLog *modelmocks.MockLogger
+		cache   string
+		now     time.Time
+	)
+
+	BeforeEach(func() {
+		mockLog = modelmocks.NewMockLogger(gomock.NewController(GinkgoT()))
+		mockLog.EXPECT().With(gomock.Any()).AnyTimes().Return(mockLog)
+		mockLog.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLog.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLog.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+
+		cache = filepath.Join(GinkgoT().TempDir(), "facts.json")
+		now = time.Now()
+	})
+
+	newCachingManager := func() *CCM {
+		mgr, err := NewManager(mockLog, mockLog, WithFactCache(cache, time.Hour))
+		Expect(err).ToNot(HaveOccurred())
+		mgr.factCache.now = func() time.Time { return now }
+
+		return mgr
+	}
+
+	writeCache := func(facts map[string]any) {
+		jb, err := json.Marshal(cachedFacts{Gathered: now, Facts: facts})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.WriteFile(cache, jb, 0600)).To(Succeed())
+	}
+
+	It("Should validate the options", func() {
+		_, err := NewManager(mockLog, mockLog, WithFactCache("", time.Hour))
+		Expect(err).To(MatchError("fact cache path is required"))
+
+		_, err = NewManager(mockLog, mockLog, WithFactCache(cache, 0))
+		Expect(err).To(MatchError("fact cache ttl must be positive"))
+	})
+
+	It("Should reuse cached facts until the ttl passed", func(ctx context.Context) {
+		writeCache(map[string]any{"cached": true})
+
+		facts, err := newCachingManager().Facts(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(facts).To(Equal(map[string]any{"cached": true}))
+
+		now = now.Add(59 * time.Minute)
+		facts, err = newCachingManager().Facts(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(facts).To(Equal(map[string]any{"cached": true}))
+
+		now = now.Add(time.Minute)
+		facts, err = newCachingManager().Facts(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(facts).ToNot(HaveKey("cached"))
+		Expect(facts).To(HaveKey("host"))
+
+		// the gathered facts replaced the expired cache
+		facts, err = newCachingManager().Facts(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(facts).To(HaveKey("host"))
+
+		var cached cachedFacts
+		jb, err := os.ReadFile(cache)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(json.Unmarshal(jb, &cached)).To(Succeed())
+		Expect(cached.Gathered.Equal(now)).To(BeTrue())
+		Expect(cached.Facts).To(HaveKey("host"))
+	})
+
+	It("Should gather facts when the cache is invalid", func(ctx context.Context) {
+		mockLog.EXPECT().Warn("Could not read fact cache, gathering facts", "error", gomock.Any())
+		Expect(os.WriteFile(cache, []byte("not json"), 0600)).To(Succeed())
+
+		facts, err := newCachingManager().Facts(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(facts).To(HaveKey("host"))
+	})
+
+	It("Should not cache merged facts", func(ctx context.Context) {
+		writeCache(map[string]any{"cached": true})
+
+		facts, err := newCachingManager().MergeFacts(ctx, map[string]any{"override": true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(facts).To(Equal(map[string]any{"cached": true, "override": true}))
+
+		facts, err = newCachingManager().Facts(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(facts).To(Equal(map[string]any{"cached": true}))
+	})
+
+	It("Should not change the cache when facts are set", func(ctx context.Context) {
+		writeCache(map[string]any{"cached": true})
+
+		newCachingManager().SetFacts(map[string]any{"set": true})
+
+		facts, err := newCachingManager().Facts(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(facts).To(Equal(map[string]any{"cached": true}))
+	})
+
+	It("Should gather facts using the cache without setting them", func(ctx context.Context) {
+		mgr := newCachingManager()
+
+		facts, err := mgr.GatherFacts(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(facts).To(HaveKey("host"))
+		Expect(mgr.facts).To(BeNil())
+		Expect(cache).To(BeAnExistingFile())
+
+		writeCache(map[string]any{"cached": true})
+
+		facts, err = mgr.GatherFacts(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(facts).To(Equal(map[string]any{"cached": true}))
+
+		now = now.Add(time.Hour)
+		facts, err = mgr.GatherFacts(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(facts).To(HaveKey("host"))
+	})
+})
+
+var _ = Describe("WithExternalFactsDir", func() {
+	It("Should gather facts from the directory", func(ctx context.Context) {
+		mockLog := modelmocks.NewMockLogger(gomock.NewController(GinkgoT()))
+		mockLog.EXPECT().With(gomock.Any()).AnyTimes().Return(mockLog)
+		mockLog.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLog.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLog.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLog.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+
+		dir := GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, "app.json"), []byte(`{"app":{"tier":"gold"}}`), 0644)).To(Succeed())
+
+		_, err := NewManager(mockLog, mockLog, WithExternalFactsDir(""))
+		Expect(err).To(MatchError("external facts directory is required"))
+
+		mgr, err := NewManager(mockLog, mockLog, WithExternalFactsDir(dir))
+		Expect(err).NotTo(HaveOccurred())
+
+		facts, err := mgr.SystemFacts(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(facts).To(HaveKeyWithValue("app", map[string]any{"tier": "gold"}))
+	})
+})
+
 var _ = Describe("NewManager", func() {
 	var (
 		ctrl    *gomock.Controller
@@ -354,6 +745,77 @@ var _ = Describe("Data", func() {
 	It("returns empty map when no data is set", func() {
 		Expect(mgr.Data()).To(Equal(map[string]any{}))
 	})
+
+	Describe("SetDataResolver", func() {
+		var resolver *modelmocks.MockDataResolver
+
+		BeforeEach(func() {
+			resolver = modelmocks.NewMockDataResolver(ctrl)
+		})
+
+		It("resolves only referenced keys and caches them", func() {
+			mgr.SetDataResolver(resolver)
+			resolver.EXPECT().Lookup("app").Return("myapp", true, nil).Times(1)
+
+			env, err := mgr.TemplateEnvironment(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+
+			for range 2 {
+				res, err := templates.ResolveTemplateString("{{ Data.app }}", env)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal("myapp"))
+			}
+
+			env, err = mgr.TemplateEnvironment(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.Data).To(Equal(map[string]any{"app": "myapp"}))
+
+			res, err := templates.ResolveTemplateString("{{ Data.app }}", env)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal("myapp"))
+		})
+
+		It("merges external data and resolves all keys for Data()", func() {
+			mgr.SetExternalData(map[string]any{"web": map[string]any{"tls": true}, "external": "value"})
+			mgr.SetDataResolver(resolver)
+
+			resolver.EXPECT().Keys().Return([]string{"app", "web"})
+			resolver.EXPECT().Lookup("app").Return("myapp", true, nil)
+			resolver.EXPECT().Lookup("web").Return(map[string]any{"port": 80}, true, nil)
+			resolver.EXPECT().Lookup("external").Return(nil, false, nil)
+
+			Expect(mgr.Data()).To(Equal(map[string]any{
+				"app":      "myapp",
+				"web":      map[string]any{"port": 80, "tls": true},
+				"external": "value",
+			}))
+		})
+
+		It("is invalidated by SetData", func() {
+			mgr.SetDataResolver(resolver)
+			mgr.SetData(map[string]any{"app": "eager"})
+
+			env, err := mgr.TemplateEnvironment(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.DataFunc).To(BeNil())
+			Expect(mgr.Data()).To(Equal(map[string]any{"app": "eager"}))
+		})
+
+		It("discards cached keys when set again", func() {
+			resolver.EXPECT().Lookup("app").Return("first", true, nil)
+			mgr.SetDataResolver(resolver)
+			res, err := mgr.resolveData("app")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal(map[string]any{"app": "first"}))
+
+			second := modelmocks.NewMockDataResolver(ctrl)
+			second.EXPECT().Lookup("app").Return("second", true, nil)
+			mgr.SetDataResolver(second)
+			res, err = mgr.resolveData("app")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal(map[string]any{"app": "second"}))
+		})
+	})
 })
 
 var _ = Describe("Facts", func() {
@@ -567,28 +1029,1661 @@ var _ = Describe("FactsRaw", func() {
 		Expect(err).NotTo(HaveOccurred())
 	})
 
-	AfterEach(func() {
-		ctrl.Finish()
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("returns facts as JSON", func() {
+		facts := map[string]any{
+			"hostname": "test-host",
+			"count":    float64(42),
+		}
+		mgr.SetFacts(facts)
+
+		raw, err := mgr.FactsRaw(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(raw).To(MatchJSON(`{"hostname":"test-host","count":42}`))
+	})
+})
+
+var _ = Describe("Logger", func() {
+	var (
+		ctrl    *gomock.Controller
+		mockLog *modelmocks.MockLogger
+		mgr     *CCM
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockLog = modelmocks.NewMockLogger(ctrl)
+		mockLog.EXPECT().With(gomock.Any()).AnyTimes().Return(mockLog)
+		mockLog.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+		var err error
+		mgr, err = NewManager(mockLog, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("creates a logger with key-value pairs", func() {
+		log, err := mgr.Logger("component", "test", "id", 123)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(log).NotTo(BeNil())
+	})
+
+	It("returns an error for odd number of arguments", func() {
+		_, err := mgr.Logger("component", "test", "orphan")
+		Expect(err).To(MatchError("invalid logger arguments, must be key value pairs"))
+	})
+
+	It("accepts no arguments", func() {
+		log, err := mgr.Logger()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(log).NotTo(BeNil())
+	})
+})
+
+var _ = Describe("NewRunner", func() {
+	var (
+		ctrl    *gomock.Controller
+		mockLog *modelmocks.MockLogger
+		mgr     *CCM
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockLog = modelmocks.NewMockLogger(ctrl)
+		mockLog.EXPECT().With(gomock.Any()).AnyTimes().Return(mockLog)
+		mockLog.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+		var err error
+		mgr, err = NewManager(mockLog, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("creates a command runner", func() {
+		runner, err := mgr.NewRunner()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(runner).NotTo(BeNil())
+	})
+})
+
+var _ = Describe("Command recording and replay", func() {
+	var (
+		ctrl    *gomock.Controller
+		mockLog *modelmocks.MockLogger
+		dir     string
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockLog = modelmocks.NewMockLogger(ctrl)
+		mockLog.EXPECT().With(gomock.Any()).AnyTimes().Return(mockLog)
+		mockLog.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLog.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLog.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLog.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+		dir = GinkgoT().TempDir()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	applyManifest := func(opts ...Option) *model.SessionSummary {
+		mgr, err := NewManager(mockLog, mockLog, opts...)
+		Expect(err).NotTo(HaveOccurred())
+		defer mgr.Close()
+
+		manifest := fmt.Sprintf(`
+ccm:
+  resources:
+    - exec:
+        name: /usr/bin/touch %s
+        environment:
+          - TOKEN=s3cret
+        unless: /usr/bin/test -f %[1]s
+`, filepath.Join(dir, "touched"))
+
+		_, m, err := apply.ResolveManifestReader(context.Background(), mgr, dir, strings.NewReader(manifest))
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = m.Execute(context.Background(), mgr, false, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+
+		summary, err := mgr.SessionSummary()
+		Expect(err).NotTo(HaveOccurred())
+
+		return summary
+	}
+
+	It("rejects recording and replaying at the same time", func() {
+		recording := filepath.Join(dir, "recording.jsonl")
+		Expect(os.WriteFile(recording, nil, 0600)).To(Succeed())
+
+		_, err := NewManager(mockLog, mockLog, WithCommandReplay(recording), WithCommandRecording(recording))
+		Expect(err).To(MatchError("cannot record commands while replaying a recording"))
+	})
+
+	It("replays a recorded apply to the same result without running commands", func() {
+		recording := filepath.Join(dir, "recording.jsonl")
+		touched := filepath.Join(dir, "touched")
+
+		recorded := applyManifest(WithCommandRecording(recording))
+		Expect(recorded.ChangedResources).To(Equal(1))
+		Expect(touched).To(BeAnExistingFile())
+
+		raw, err := os.ReadFile(recording)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(raw)).To(ContainSubstring("/usr/bin/touch"))
+		Expect(string(raw)).To(ContainSubstring("TOKEN=" + model.RedactedValue))
+		Expect(string(raw)).NotTo(ContainSubstring("s3cret"))
+
+		Expect(os.Remove(touched)).To(Succeed())
+
+		replayed := applyManifest(WithCommandReplay(recording))
+		Expect(replayed.TotalResources).To(Equal(recorded.TotalResources))
+		Expect(replayed.ChangedResources).To(Equal(recorded.ChangedResources))
+		Expect(replayed.FailedResources).To(Equal(recorded.FailedResources))
+		Expect(touched).NotTo(BeAnExistingFile())
+	})
+})
+
+var _ = Describe("RecordEvent", func() {
+	var (
+		ctrl    *gomock.Controller
+		mockLog *modelmocks.MockLogger
+		mgr     *CCM
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockLog = modelmocks.NewMockLogger(ctrl)
+		mockLog.EXPECT().With(gomock.Any()).AnyTimes().Return(mockLog)
+		mockLog.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+		var err error
+		mgr, err = NewManager(mockLog, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("records an event in the session store", func() {
+		event := &model.TransactionEvent{
+			ResourceType: "file",
+			Name:         "/tmp/test",
+			Changed:      true,
+		}
+
+		err := mgr.RecordEvent(event)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("returns an error when session store is nil", func() {
+		mgr.session = nil
+
+		event := &model.TransactionEvent{}
+		err := mgr.RecordEvent(event)
+		Expect(err).To(MatchError("no session store available"))
+	})
+
+	It("returns an error when event name is empty", func() {
+		event := &model.TransactionEvent{
+			ResourceType: "file",
+			Name:         "",
+		}
+
+		err := mgr.RecordEvent(event)
+		Expect(err).To(MatchError("event name cannot be empty"))
+	})
+
+	It("returns an error when resource type is empty", func() {
+		event := &model.TransactionEvent{
+			ResourceType: "",
+			Name:         "/tmp/test",
+		}
+
+		err := mgr.RecordEvent(event)
+		Expect(err).To(MatchError("resource type cannot be empty"))
+	})
+})
+
+var _ = Describe("ScheduleResources", func() {
+	var (
+		ctrl    *gomock.Controller
+		mockLog *modelmocks.MockLogger
+	)
+
+	execProps := func(name string, require []string, subscribe []string) map[string]model.ResourceProperties {
+		return map[string]model.ResourceProperties{
+			model.ExecTypeName: &model.ExecResourceProperties{
+				CommonResourceProperties: model.CommonResourceProperties{
+					Type:    model.ExecTypeName,
+					Name:    name,
+					Ensure:  model.EnsurePresent,
+					Require: require,
+				},
+				Subscribe: subscribe,
+			},
+		}
+	}
+
+	execBefore := func(name string, before ...string) map[string]model.ResourceProperties {
+		res := execProps(name, nil, nil)
+		res[model.ExecTypeName].CommonProperties().Before = before
+
+		return res
+	}
+
+	// recordOrder returns a scheduled resource function that records the names of resources in the order they completed
+	recordOrder := func(mu *sync.Mutex, order *[]string) model.ScheduledResourceFunc {
+		return func(_ context.Context, prop model.ResourceProperties) (bool, error) {
+			mu.Lock()
+			*order = append(*order, prop.CommonProperties().Name)
+			mu.Unlock()
+
+			return false, nil
+		}
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockLog = modelmocks.NewMockLogger(ctrl)
+		mockLog.EXPECT().With(gomock.Any()).AnyTimes().Return(mockLog)
+		mockLog.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("validates the concurrency", func() {
+		_, err := NewManager(mockLog, mockLog, WithConcurrency(0))
+		Expect(err).To(MatchError("concurrency must be at least 1"))
+
+		mgr, err := NewManager(mockLog, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mgr.Concurrency()).To(Equal(DefaultConcurrency))
+
+		mgr, err = NewManager(mockLog, mockLog, WithConcurrency(4))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mgr.Concurrency()).To(Equal(4))
+	})
+
+	It("applies resources in manifest order by default", func() {
+		mgr, err := NewManager(mockLog, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+
+		var mu sync.Mutex
+		var order []string
+		resources := []map[string]model.ResourceProperties{
+			execProps("one", nil, nil),
+			execProps("two", nil, nil),
+			execProps("three", nil, nil),
+		}
+
+		Expect(mgr.ScheduleResources(context.Background(), resources, recordOrder(&mu, &order))).To(Succeed())
+		Expect(order).To(Equal([]string{"one", "two", "three"}))
+	})
+
+	It("applies independent resources concurrently", func() {
+		mgr, err := NewManager(mockLog, mockLog, WithConcurrency(3))
+		Expect(err).NotTo(HaveOccurred())
+
+		var running, maxRunning atomic.Int32
+		resources := []map[string]model.ResourceProperties{
+			execProps("one", nil, nil),
+			execProps("two", nil, nil),
+			execProps("three", nil, nil),
+		}
+
+		err = mgr.ScheduleResources(context.Background(), resources, func(context.Context, model.ResourceProperties) (bool, error) {
+			current := running.Add(1)
+			defer running.Add(-1)
+
+			for {
+				peak := maxRunning.Load()
+				if current <= peak || maxRunning.CompareAndSwap(peak, current) {
+					break
+				}
+			}
+
+			Eventually(maxRunning.Load).Should(Equal(int32(3)))
+
+			return false, nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(maxRunning.Load()).To(Equal(int32(3)))
+	})
+
+	It("applies resources after the resources they require or subscribe to", func() {
+		mgr, err := NewManager(mockLog, mockLog, WithConcurrency(4))
+		Expect(err).NotTo(HaveOccurred())
+
+		var mu sync.Mutex
+		var order []string
+		resources := []map[string]model.ResourceProperties{
+			execProps("restart", nil, []string{"exec#configure"}),
+			execProps("configure", []string{"exec#install"}, nil),
+			execProps("install", nil, nil),
+			execProps("missing", []string{"exec#unknown"}, nil),
+		}
+
+		Expect(mgr.ScheduleResources(context.Background(), resources, recordOrder(&mu, &order))).To(Succeed())
+		Expect(order).To(HaveLen(4))
+		Expect(slices.Index(order, "install")).To(BeNumerically("<", slices.Index(order, "configure")))
+		Expect(slices.Index(order, "configure")).To(BeNumerically("<", slices.Index(order, "restart")))
+	})
+
+	It("applies apply resources on their own", func() {
+		mgr, err := NewManager(mockLog, mockLog, WithConcurrency(3))
+		Expect(err).NotTo(HaveOccurred())
+
+		resources := []map[string]model.ResourceProperties{
+			execProps("one", nil, nil),
+			{model.ApplyTypeName: &model.ApplyResourceProperties{
+				CommonResourceProperties: model.CommonResourceProperties{Type: model.ApplyTypeName, Name: "child.yaml", Ensure: model.EnsurePresent},
+			}},
+			execProps("two", nil, nil),
+		}
+
+		var running atomic.Int32
+		var applyRunning, concurrentWithApply atomic.Bool
+		err = mgr.ScheduleResources(context.Background(), resources, func(_ context.Context, prop model.ResourceProperties) (bool, error) {
+			current := running.Add(1)
+			defer running.Add(-1)
+
+			isApply := prop.CommonProperties().Type == model.ApplyTypeName
+			if (isApply && current > 1) || applyRunning.Load() {
+				concurrentWithApply.Store(true)
+			}
+
+			applyRunning.Store(isApply)
+			time.Sleep(20 * time.Millisecond)
+			applyRunning.Store(false)
+
+			return false, nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(concurrentWithApply.Load()).To(BeFalse())
+	})
+
+	It("orders every resource sharing a repeated name", func() {
+		mgr, err := NewManager(mockLog, mockLog, WithConcurrency(4))
+		Expect(err).NotTo(HaveOccurred())
+
+		var mu sync.Mutex
+		var order []string
+		resources := []map[string]model.ResourceProperties{
+			execProps("slow", nil, nil),
+			execProps("dup", []string{"exec#slow"}, nil),
+			execProps("dup", []string{"exec#slow"}, nil),
+		}
+
+		err = mgr.ScheduleResources(context.Background(), resources, func(ctx context.Context, prop model.ResourceProperties) (bool, error) {
+			if prop.CommonProperties().Name == "slow" {
+				time.Sleep(20 * time.Millisecond)
+			}
+
+			return recordOrder(&mu, &order)(ctx, prop)
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(order).To(Equal([]string{"slow", "dup", "dup"}))
+	})
+
+	It("applies a chain of require and before in topological order", func() {
+		mgr, err := NewManager(mockLog, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+
+		var mu sync.Mutex
+		var order []string
+		resources := []map[string]model.ResourceProperties{
+			execProps("service", []string{"exec#config"}, nil),
+			execBefore("config", "exec#service"),
+			execBefore("package", "exec#config"),
+		}
+
+		Expect(mgr.ScheduleResources(context.Background(), resources, recordOrder(&mu, &order))).To(Succeed())
+		Expect(order).To(Equal([]string{"package", "config", "service"}))
+	})
+
+	It("fails on before cycles naming the cycle", func() {
+		mgr, err := NewManager(mockLog, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+
+		config := execProps("config", []string{"exec#service"}, nil)
+		config[model.ExecTypeName].CommonProperties().Before = []string{"exec#package"}
+
+		resources := []map[string]model.ResourceProperties{
+			execBefore("package", "exec#service"),
+			execProps("service", nil, nil),
+			config,
+		}
+
+		var applied []string
+		err = mgr.ScheduleResources(context.Background(), resources, func(_ context.Context, prop model.ResourceProperties) (bool, error) {
+			applied = append(applied, prop.CommonProperties().Name)
+			return false, nil
+		})
+		Expect(err).To(MatchError(model.ErrResourceCycle))
+		Expect(err).To(MatchError("resource dependency cycle: exec#package -> exec#service -> exec#config -> exec#package"))
+		Expect(applied).To(BeEmpty())
+	})
+
+	It("stops starting resources once a resource requests a stop or fails", func() {
+		mgr, err := NewManager(mockLog, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+
+		resources := []map[string]model.ResourceProperties{
+			execProps("one", nil, nil),
+			execProps("two", nil, nil),
+			execProps("three", nil, nil),
+		}
+
+		var applied []string
+		err = mgr.ScheduleResources(context.Background(), resources, func(_ context.Context, prop model.ResourceProperties) (bool, error) {
+			applied = append(applied, prop.CommonProperties().Name)
+			return prop.CommonProperties().Name == "two", nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(applied).To(Equal([]string{"one", "two"}))
+
+		applied = nil
+		err = mgr.ScheduleResources(context.Background(), resources, func(_ context.Context, prop model.ResourceProperties) (bool, error) {
+			applied = append(applied, prop.CommonProperties().Name)
+			return false, fmt.Errorf("simulated failure")
+		})
+		Expect(err).To(MatchError("simulated failure"))
+		Expect(applied).To(Equal([]string{"one"}))
+	})
+
+	It("fails on dependency cycles without applying any resource", func() {
+		mgr, err := NewManager(mockLog, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+
+		resources := []map[string]model.ResourceProperties{
+			execProps("one", []string{"exec#two"}, nil),
+			execProps("two", nil, []string{"exec#one"}),
+		}
+
+		var applied []string
+		err = mgr.ScheduleResources(context.Background(), resources, func(_ context.Context, prop model.ResourceProperties) (bool, error) {
+			applied = append(applied, prop.CommonProperties().Name)
+			return false, nil
+		})
+		Expect(err).To(MatchError(model.ErrResourceCycle))
+		Expect(err).To(MatchError(ContainSubstring("exec#one -> exec#two -> exec#one")))
+		Expect(applied).To(BeEmpty())
+	})
+
+	It("records a correct session summary when applying concurrently", func() {
+		mgr, err := NewManager(mockLog, mockLog, WithConcurrency(5))
+		Expect(err).NotTo(HaveOccurred())
+
+		var resources []map[string]model.ResourceProperties
+		for i := range 20 {
+			resources = append(resources, execProps(fmt.Sprintf("resource%d", i), nil, nil))
+		}
+
+		err = mgr.ScheduleResources(context.Background(), resources, func(_ context.Context, prop model.ResourceProperties) (bool, error) {
+			event := model.NewTransactionEvent(model.ExecTypeName, prop.CommonProperties().Name, "")
+			event.Changed = true
+
+			return false, mgr.RecordEvent(event)
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		summary, err := mgr.SessionSummary()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(summary.TotalResources).To(Equal(20))
+		Expect(summary.ChangedResources).To(Equal(20))
+	})
+})
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, fmt.Errorf("disk full") }
+
+var _ = Describe("WithEventSink", func() {
+	var (
+		ctrl    *gomock.Controller
+		mockLog *modelmocks.MockLogger
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockLog = modelmocks.NewMockLogger(ctrl)
+		mockLog.EXPECT().With(gomock.Any()).AnyTimes().Return(mockLog)
+		mockLog.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	})
+
+	It("writes every recorded event as a JSON line", func() {
+		sink := &bytes.Buffer{}
+		mgr, err := NewManager(mockLog, mockLog, WithEventSink(sink))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(mgr.RecordEvent(&model.TransactionEvent{ResourceType: "file", Name: "/tmp/a", Changed: true})).To(Succeed())
+		Expect(mgr.RecordEvent(&model.TransactionEvent{ResourceType: "service", Name: "nginx", Failed: true})).To(Succeed())
+
+		lines := bytes.Split(bytes.TrimSpace(sink.Bytes()), []byte("\n"))
+		Expect(lines).To(HaveLen(2))
+
+		var event model.TransactionEvent
+		Expect(json.Unmarshal(lines[1], &event)).To(Succeed())
+		Expect(event.ResourceType).To(Equal("service"))
+		Expect(event.Name).To(Equal("nginx"))
+		Expect(event.Failed).To(BeTrue())
+	})
+
+	It("writes one JSON object per resource as resources complete concurrently", func() {
+		sink := &bytes.Buffer{}
+		mgr, err := NewManager(mockLog, mockLog, WithEventSink(sink), WithConcurrency(4))
+		Expect(err).NotTo(HaveOccurred())
+
+		var resources []map[string]model.ResourceProperties
+		for i := range 10 {
+			resources = append(resources, map[string]model.ResourceProperties{
+				model.ExecTypeName: &model.ExecResourceProperties{
+					CommonResourceProperties: model.CommonResourceProperties{Type: model.ExecTypeName, Name: fmt.Sprintf("cmd%d", i), Alias: fmt.Sprintf("alias%d", i), Ensure: model.EnsurePresent},
+				},
+			})
+		}
+
+		err = mgr.ScheduleResources(context.Background(), resources, func(_ context.Context, prop model.ResourceProperties) (bool, error) {
+			cp := prop.CommonProperties()
+			event := model.NewTransactionEvent(cp.Type, cp.Name, cp.Alias)
+			event.Changed = true
+			event.Errors = []string{"simulated"}
+			event.Duration = time.Second
+
+			return false, mgr.RecordEvent(event)
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		lines := bytes.Split(bytes.TrimSpace(sink.Bytes()), []byte("\n"))
+		Expect(lines).To(HaveLen(10))
+
+		var names []string
+		for _, line := range lines {
+			event := map[string]any{}
+			Expect(json.Unmarshal(line, &event)).To(Succeed())
+			Expect(event).To(HaveKeyWithValue("type", "exec"))
+			Expect(event).To(HaveKeyWithValue("alias", strings.Replace(event["name"].(string), "cmd", "alias", 1)))
+			Expect(event).To(HaveKeyWithValue("changed", true))
+			Expect(event).To(HaveKeyWithValue("failed", false))
+			Expect(event).To(HaveKeyWithValue("error", []any{"simulated"}))
+			Expect(event).To(HaveKeyWithValue("duration", float64(time.Second)))
+
+			names = append(names, event["name"].(string))
+		}
+		Expect(names).To(ConsistOf("cmd0", "cmd1", "cmd2", "cmd3", "cmd4", "cmd5", "cmd6", "cmd7", "cmd8", "cmd9"))
+	})
+
+	It("logs but does not fail on write errors", func() {
+		mockLog.EXPECT().Warn("Could not write event to the event sink", "error", gomock.Any())
+
+		mgr, err := NewManager(mockLog, mockLog, WithEventSink(failingWriter{}))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(mgr.RecordEvent(&model.TransactionEvent{ResourceType: "file", Name: "/tmp/a"})).To(Succeed())
+	})
+
+	It("appends to an event file", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "events.jsonl")
+
+		for range 2 {
+			mgr, err := NewManager(mockLog, mockLog, WithEventFile(path))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mgr.RecordEvent(&model.TransactionEvent{ResourceType: "file", Name: "/tmp/a"})).To(Succeed())
+			Expect(mgr.Close()).To(Succeed())
+		}
+
+		body, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bytes.Count(body, []byte("\n"))).To(Equal(2))
+	})
+})
+
+var _ = Describe("WithRefreshStateDirectory", func() {
+	var (
+		ctrl     *gomock.Controller
+		mockLog  *modelmocks.MockLogger
+		manifest *modelmocks.MockApply
+		dir      string
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockLog = modelmocks.NewMockLogger(ctrl)
+		mockLog.EXPECT().With(gomock.Any()).AnyTimes().Return(mockLog)
+		mockLog.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLog.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+		dir = GinkgoT().TempDir()
+
+		manifest = modelmocks.NewMockApply(ctrl)
+		manifest.EXPECT().Resources().Return([]map[string]model.ResourceProperties{
+			{model.PackageTypeName: &model.PackageResourceProperties{
+				CommonResourceProperties: model.CommonResourceProperties{Type: model.PackageTypeName, Name: "httpd", Alias: "web"},
+			}},
+			{model.ServiceTypeName: &model.ServiceResourceProperties{
+				CommonResourceProperties: model.CommonResourceProperties{Type: model.ServiceTypeName, Name: "httpd"},
+				Subscribe:                []string{"package#web"},
+			}},
+		}).AnyTimes()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	run := func(opts ...Option) *CCM {
+		mgr, err := NewManager(mockLog, mockLog, append([]Option{WithRefreshStateDirectory(dir)}, opts...)...)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = mgr.StartSession(manifest)
+		Expect(err).NotTo(HaveOccurred())
+
+		return mgr
+	}
+
+	It("does not track refreshes by default", func() {
+		mgr, err := NewManager(mockLog, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = mgr.StartSession(manifest)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(mgr.RecordEvent(&model.TransactionEvent{ResourceType: "package", Name: "httpd", Alias: "web", Changed: true})).To(Succeed())
+
+		source, err := mgr.PendingRefresh("service", "httpd")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(source).To(BeEmpty())
+	})
+
+	It("triggers a refresh that was interrupted in the previous run", func() {
+		// the package changes but the run ends before the service is restarted
+		first := run()
+		Expect(first.RecordEvent(&model.TransactionEvent{ResourceType: "package", Name: "httpd", Alias: "web", Changed: true})).To(Succeed())
+
+		second := run()
+		Expect(second.RecordEvent(&model.TransactionEvent{ResourceType: "package", Name: "httpd", Alias: "web"})).To(Succeed())
+
+		should, err := second.ShouldRefresh("package", "httpd")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(should).To(BeFalse())
+
+		source, err := second.PendingRefresh("service", "httpd")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(source).To(Equal("package#httpd"))
+
+		// a failed restart leaves the refresh pending
+		Expect(second.RecordEvent(&model.TransactionEvent{ResourceType: "service", Name: "httpd", Failed: true})).To(Succeed())
+		source, err = second.PendingRefresh("service", "httpd")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(source).To(Equal("package#httpd"))
+
+		Expect(second.RecordEvent(&model.TransactionEvent{ResourceType: "service", Name: "httpd", Changed: true, Refreshed: true})).To(Succeed())
+		source, err = run().PendingRefresh("service", "httpd")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(source).To(BeEmpty())
+	})
+
+	It("does not record refreshes for noop changes", func() {
+		mgr := run(WithNoop())
+		Expect(mgr.RecordEvent(&model.TransactionEvent{ResourceType: "package", Name: "httpd", Changed: true, Noop: true})).To(Succeed())
+
+		source, err := mgr.PendingRefresh("service", "httpd")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(source).To(BeEmpty())
+	})
+})
+
+var _ = Describe("WithConvergedStateDirectory", func() {
+	var (
+		ctrl     *gomock.Controller
+		mockLog  *modelmocks.MockLogger
+		manifest *modelmocks.MockApply
+		dir      string
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockLog = modelmocks.NewMockLogger(ctrl)
+		mockLog.EXPECT().With(gomock.Any()).AnyTimes().Return(mockLog)
+		mockLog.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLog.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+		dir = GinkgoT().TempDir()
+
+		manifest = modelmocks.NewMockApply(ctrl)
+		manifest.EXPECT().Resources().Return(nil).AnyTimes()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	run := func(trust time.Duration) *CCM {
+		mgr, err := NewManager(mockLog, mockLog, WithConvergedStateDirectory(dir, trust))
+		Expect(err).NotTo(HaveOccurred())
+		mgr.SetFacts(map[string]any{"motd": "hello"})
+		_, err = mgr.StartSession(manifest)
+		Expect(err).NotTo(HaveOccurred())
+
+		return mgr
+	}
+
+	motd := func(mode string) *model.FileResourceProperties {
+		return &model.FileResourceProperties{
+			CommonResourceProperties: model.CommonResourceProperties{Type: model.FileTypeName, Name: "/etc/motd", Ensure: model.EnsurePresent},
+			Owner:                    "root",
+			Group:                    "root",
+			Mode:                     mode,
+		}
+	}
+
+	// converge checks prop in mgr and records the outcome of event, it returns if prop was trusted
+	converge := func(mgr *CCM, prop model.ResourceProperties, event *model.TransactionEvent) bool {
+		trusted, digest, err := mgr.TrustConverged(context.Background(), prop)
+		Expect(err).NotTo(HaveOccurred())
+
+		if trusted {
+			event = &model.TransactionEvent{ResourceType: prop.CommonProperties().Type, Name: prop.CommonProperties().Name, Trusted: true}
+		}
+		Expect(mgr.RecordConverged(prop, digest, event)).To(Succeed())
+
+		return trusted
+	}
+
+	stable := &model.TransactionEvent{ResourceType: model.FileTypeName, Name: "/etc/motd"}
+
+	It("requires a trust window", func() {
+		_, err := NewManager(mockLog, mockLog, WithConvergedStateDirectory(dir, 0))
+		Expect(err).To(MatchError("trust window must be positive"))
+	})
+
+	It("does not trust resources by default", func() {
+		mgr, err := NewManager(mockLog, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+
+		trusted, digest, err := mgr.TrustConverged(context.Background(), motd("0644"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(trusted).To(BeFalse())
+		Expect(digest).To(BeEmpty())
+	})
+
+	It("trusts stable resources with unchanged inputs", func() {
+		Expect(converge(run(time.Hour), motd("0644"), stable)).To(BeFalse())
+		Expect(converge(run(time.Hour), motd("0644"), stable)).To(BeTrue())
+		Expect(converge(run(time.Hour), motd("0644"), stable)).To(BeTrue())
+	})
+
+	It("checks resources whose properties changed", func() {
+		Expect(converge(run(time.Hour), motd("0644"), stable)).To(BeFalse())
+		Expect(converge(run(time.Hour), motd("0600"), stable)).To(BeFalse())
+		Expect(converge(run(time.Hour), motd("0644"), stable)).To(BeFalse())
+		Expect(converge(run(time.Hour), motd("0644"), stable)).To(BeTrue())
+	})
+
+	It("checks resources whose data changed", func() {
+		Expect(converge(run(time.Hour), motd("0644"), stable)).To(BeFalse())
+
+		mgr := run(time.Hour)
+		mgr.SetData(map[string]any{"motd": "hello"})
+		Expect(converge(mgr, motd("0644"), stable)).To(BeFalse())
+	})
+
+	It("checks resources whose facts used in templates changed", func() {
+		templated := func() *model.FileResourceProperties {
+			prop := motd("0644")
+			contents := "{{ Facts.motd }}"
+			prop.Contents = &contents
+
+			return prop
+		}
+
+		Expect(converge(run(time.Hour), templated(), stable)).To(BeFalse())
+		Expect(converge(run(time.Hour), templated(), stable)).To(BeTrue())
+
+		mgr := run(time.Hour)
+		mgr.SetFacts(map[string]any{"motd": "goodbye"})
+		Expect(converge(mgr, templated(), stable)).To(BeFalse())
+		Expect(converge(run(time.Hour), templated(), stable)).To(BeFalse())
+	})
+
+	It("checks resources whose local source changed", func() {
+		source := filepath.Join(GinkgoT().TempDir(), "motd")
+		Expect(os.WriteFile(source, []byte("hello"), 0644)).To(Succeed())
+
+		prop := motd("0644")
+		prop.Source = source
+
+		Expect(converge(run(time.Hour), prop, stable)).To(BeFalse())
+		Expect(converge(run(time.Hour), prop, stable)).To(BeTrue())
+
+		Expect(os.WriteFile(source, []byte("goodbye"), 0644)).To(Succeed())
+		Expect(converge(run(time.Hour), prop, stable)).To(BeFalse())
+		Expect(converge(run(time.Hour), prop, stable)).To(BeTrue())
+	})
+
+	It("checks resources once the trust window passed", func() {
+		Expect(converge(run(time.Hour), motd("0644"), stable)).To(BeFalse())
+		Expect(converge(run(time.Nanosecond), motd("0644"), stable)).To(BeFalse())
+	})
+
+	It("does not trust changed resources", func() {
+		Expect(converge(run(time.Hour), motd("0644"), &model.TransactionEvent{ResourceType: model.FileTypeName, Name: "/etc/motd", Changed: true})).To(BeFalse())
+		Expect(converge(run(time.Hour), motd("0644"), stable)).To(BeFalse())
+	})
+
+	It("checks every resource after a failed run", func() {
+		pkg := &model.PackageResourceProperties{
+			CommonResourceProperties: model.CommonResourceProperties{Type: model.PackageTypeName, Name: "zsh", Ensure: model.EnsurePresent},
+		}
+		pkgStable := &model.TransactionEvent{ResourceType: model.PackageTypeName, Name: "zsh"}
+
+		mgr := run(time.Hour)
+		Expect(converge(mgr, motd("0644"), stable)).To(BeFalse())
+		Expect(converge(mgr, pkg, pkgStable)).To(BeFalse())
+
+		mgr = run(time.Hour)
+		Expect(converge(mgr, motd("0600"), &model.TransactionEvent{ResourceType: model.FileTypeName, Name: "/etc/motd", Failed: true})).To(BeFalse())
+		Expect(converge(mgr, pkg, pkgStable)).To(BeFalse())
+
+		mgr = run(time.Hour)
+		Expect(converge(mgr, motd("0644"), stable)).To(BeFalse())
+		Expect(converge(mgr, pkg, pkgStable)).To(BeFalse())
+
+		Expect(converge(run(time.Hour), pkg, pkgStable)).To(BeTrue())
+	})
+
+	It("never trusts resources depending on runtime state", func() {
+		prop := motd("0644")
+		prop.HealthChecks = []model.CommonHealthCheck{{Command: "/bin/true"}}
+
+		Expect(converge(run(time.Hour), prop, stable)).To(BeFalse())
+		Expect(converge(run(time.Hour), prop, stable)).To(BeFalse())
+	})
+})
+
+var _ = Describe("WithProviderConfig", func() {
+	var (
+		ctrl    *gomock.Controller
+		mockLog *modelmocks.MockLogger
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockLog = modelmocks.NewMockLogger(ctrl)
+		mockLog.EXPECT().With(gomock.Any()).AnyTimes().Return(mockLog)
+	})
+
+	It("returns the configuration for the provider", func() {
+		mgr, err := NewManager(mockLog, mockLog, WithProviderConfig("http", map[string]any{"timeout": "5m"}))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(mgr.ProviderConfig("http")).To(Equal(map[string]any{"timeout": "5m"}))
+		Expect(mgr.ProviderConfig("apt")).To(BeNil())
+
+		// callers get a copy
+		mgr.ProviderConfig("http")["timeout"] = "1s"
+		Expect(mgr.ProviderConfig("http")).To(Equal(map[string]any{"timeout": "5m"}))
+	})
+
+	It("requires a provider name", func() {
+		_, err := NewManager(mockLog, mockLog, WithProviderConfig("", map[string]any{"timeout": "5m"}))
+		Expect(err).To(MatchError("provider name is required"))
+	})
+})
+
+var _ = Describe("EffectiveResources", func() {
+	var (
+		ctrl    *gomock.Controller
+		mockLog *modelmocks.MockLogger
+		mgr     *CCM
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockLog = modelmocks.NewMockLogger(ctrl)
+		mockLog.EXPECT().With(gomock.Any()).AnyTimes().Return(mockLog)
+		mockLog.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLog.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+
+		var err error
+		mgr, err = NewManager(mockLog, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+		mgr.SetFacts(map[string]any{"role": "web"})
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("returns resolved, defaulted and redacted resources that will be managed", func() {
+		manifest := `
+ccm:
+  defaults:
+    file:
+      owner: root
+      group: root
+      mode: "0640"
+  resources:
+    - file:
+        name: "/etc/{{ Facts.role }}.conf"
+        ensure: present
+    - file:
+        name: /etc/db.conf
+        ensure: present
+        control:
+          if: Facts.role == "db"
+    - archive:
+        name: /tmp/app.tgz
+        ensure: present
+        url: https://example.net/app.tgz
+        username: app
+        password: s3cret
+        headers:
+          X-Token: t0ken
+        extract_parent: /srv
+        owner: root
+        group: root
+`
+		_, m, err := apply.ResolveManifestReader(context.Background(), mgr, GinkgoT().TempDir(), strings.NewReader(manifest))
+		Expect(err).NotTo(HaveOccurred())
+
+		resources, err := mgr.EffectiveResources(context.Background(), m)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resources).To(HaveLen(2))
+
+		file, ok := resources[0][model.FileTypeName].(*model.FileResourceProperties)
+		Expect(ok).To(BeTrue())
+		Expect(file.Name).To(Equal("/etc/web.conf"))
+		Expect(file.Type).To(Equal(model.FileTypeName))
+		Expect(file.Owner).To(Equal("root"))
+		Expect(file.Mode).To(Equal("0640"))
+
+		archive, ok := resources[1][model.ArchiveTypeName].(*model.ArchiveResourceProperties)
+		Expect(ok).To(BeTrue())
+		Expect(archive.Username).To(Equal("app"))
+		Expect(archive.Password).To(Equal(model.RedactedValue))
+		Expect(archive.Headers).To(Equal(map[string]string{"X-Token": model.RedactedValue}))
+
+		// the manifest itself is not modified
+		original := m.Resources()[2][model.ArchiveTypeName].(*model.ArchiveResourceProperties)
+		Expect(original.Password).To(Equal("s3cret"))
+	})
+})
+
+var _ = Describe("WriteDebugDump", func() {
+	var (
+		ctrl     *gomock.Controller
+		mockLog  *modelmocks.MockLogger
+		dir      string
+		manifest = `
+data:
+  password: s3cret
+ccm:
+  resources:
+    - archive:
+        name: /tmp/app.tgz
+        ensure: present
+        url: https://example.net/app.tgz
+        username: app
+        password: "{{ Data.password }}"
+        extract_parent: /srv
+        owner: root
+        group: root
+`
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockLog = modelmocks.NewMockLogger(ctrl)
+		mockLog.EXPECT().With(gomock.Any()).AnyTimes().Return(mockLog)
+		mockLog.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLog.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+		dir = filepath.Join(GinkgoT().TempDir(), "dumps")
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	dump := func(maxSize int64) map[string]any {
+		mgr, err := NewManager(mockLog, mockLog, WithDebugDump(dir, maxSize))
+		Expect(err).NotTo(HaveOccurred())
+		mgr.SetFacts(map[string]any{"role": "web"})
+
+		_, m, err := apply.ResolveManifestReader(context.Background(), mgr, GinkgoT().TempDir(), strings.NewReader(manifest))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = mgr.StartSession(m)
+		Expect(err).NotTo(HaveOccurred())
+
+		event := model.NewTransactionEvent(model.ArchiveTypeName, "/tmp/app.tgz", "")
+		event.Failed = true
+		event.Errors = []string{"download with password s3cret failed"}
+		Expect(mgr.RecordEvent(event)).To(Succeed())
+
+		path, err := mgr.WriteDebugDump(context.Background(), m, fmt.Errorf("run failed"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(filepath.Dir(path)).To(Equal(dir))
+
+		stat, err := os.Stat(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stat.Size()).To(BeNumerically("<=", max(maxSize, DefaultDebugDumpMaxSize)))
+
+		body, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(body)).ToNot(ContainSubstring("s3cret"))
+
+		res := map[string]any{}
+		Expect(json.Unmarshal(body, &res)).To(Succeed())
+
+		return res
+	}
+
+	It("Should do nothing when not enabled", func() {
+		mgr, err := NewManager(mockLog, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+
+		path, err := mgr.WriteDebugDump(context.Background(), modelmocks.NewMockApply(ctrl), nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(path).To(BeEmpty())
+	})
+
+	It("Should write redacted facts, data, resources and events", func() {
+		res := dump(0)
+		Expect(res["error"]).To(Equal("run failed"))
+		Expect(res["source"]).To(Equal("reader"))
+		Expect(res["facts"]).To(Equal(map[string]any{"role": "web"}))
+		Expect(res["data"]).To(Equal(map[string]any{"password": model.RedactedValue}))
+		Expect(res["resources"]).To(HaveLen(1))
+		Expect(res["events"]).To(ContainElement(HaveKeyWithValue("failed", true)))
+		Expect(res).ToNot(HaveKey("omitted"))
+	})
+
+	It("Should omit the largest sections to fit the size limit", func() {
+		res := dump(1200)
+		Expect(res["omitted"]).ToNot(BeEmpty())
+		Expect(res["facts"]).To(Equal(map[string]any{"role": "web"}))
+	})
+})
+
+var _ = Describe("DeliverSessionReport", func() {
+	var (
+		ctrl     *gomock.Controller
+		mockLog  *modelmocks.MockLogger
+		srv      *httptest.Server
+		received [][]byte
+		headers  []http.Header
+		statuses []int
+		mu       sync.Mutex
+		manifest = `
+data:
+  password: s3cret
+ccm:
+  resources:
+    - archive:
+        name: /tmp/app.tgz
+        ensure: present
+        url: https://example.net/app.tgz
+        username: app
+        password: "{{ Data.password }}"
+        extract_parent: /srv
+        owner: root
+        group: root
+`
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockLog = modelmocks.NewMockLogger(ctrl)
+		mockLog.EXPECT().With(gomock.Any()).AnyTimes().Return(mockLog)
+		mockLog.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLog.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLog.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+
+		received = nil
+		headers = nil
+		statuses = nil
+
+		srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			body, err := io.ReadAll(r.Body)
+			Expect(err).NotTo(HaveOccurred())
+			received = append(received, body)
+			headers = append(headers, r.Header.Clone())
+
+			status := http.StatusOK
+			if len(statuses) > 0 {
+				status = statuses[0]
+				statuses = statuses[1:]
+			}
+			w.WriteHeader(status)
+		}))
+		DeferCleanup(srv.Close)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	newSession := func(opts ...Option) (*CCM, model.Apply) {
+		mgr, err := NewManager(mockLog, mockLog, opts...)
+		Expect(err).NotTo(HaveOccurred())
+		if mgr.reportWebhook != nil {
+			mgr.reportWebhook.backoff = backoff.Policy{Millis: []int{1}}
+		}
+
+		_, m, err := apply.ResolveManifestReader(context.Background(), mgr, GinkgoT().TempDir(), strings.NewReader(manifest))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = mgr.StartSession(m)
+		Expect(err).NotTo(HaveOccurred())
+
+		event := model.NewTransactionEvent(model.ArchiveTypeName, "/tmp/app.tgz", "")
+		event.Changed = true
+		event.Errors = []string{"download with password s3cret was slow"}
+		Expect(mgr.RecordEvent(event)).To(Succeed())
+
+		return mgr, m
+	}
+
+	It("Should do nothing when not configured", func() {
+		mgr, m := newSession()
+		Expect(mgr.DeliverSessionReport(context.Background(), m)).To(Succeed())
+		Expect(received).To(BeEmpty())
+	})
+
+	It("Should post the redacted summary and events", func() {
+		mgr, m := newSession(WithReportWebhook(srv.URL+"/report", map[string]string{"Authorization": "Bearer x"}))
+		Expect(mgr.DeliverSessionReport(context.Background(), m)).To(Succeed())
+
+		Expect(received).To(HaveLen(1))
+		Expect(headers[0].Get("Authorization")).To(Equal("Bearer x"))
+		Expect(headers[0].Get("Content-Type")).To(Equal("application/json"))
+		Expect(string(received[0])).ToNot(ContainSubstring("s3cret"))
+
+		var report model.SessionReport
+		Expect(json.Unmarshal(received[0], &report)).To(Succeed())
+		Expect(report.Events).To(HaveLen(1))
+		Expect(report.Events[0].Name).To(Equal("/tmp/app.tgz"))
+		Expect(report.Events[0].Changed).To(BeTrue())
+
+		events, err := mgr.session.AllEvents()
+		Expect(err).NotTo(HaveOccurred())
+		expected, err := json.Marshal(model.BuildSessionSummary(events))
+		Expect(err).NotTo(HaveOccurred())
+		posted, err := json.Marshal(report.Summary)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(posted).To(MatchJSON(expected))
+		Expect(report.Summary.ChangedResources).To(Equal(1))
+	})
+
+	It("Should retry failed deliveries", func() {
+		statuses = []int{http.StatusBadGateway, http.StatusServiceUnavailable}
+
+		mgr, m := newSession(WithReportWebhook(srv.URL, nil))
+		Expect(mgr.DeliverSessionReport(context.Background(), m)).To(Succeed())
+		Expect(received).To(HaveLen(3))
+	})
+
+	It("Should give up after a bounded number of tries", func() {
+		for range ReportWebhookTries + 1 {
+			statuses = append(statuses, http.StatusInternalServerError)
+		}
+
+		mgr, m := newSession(WithReportWebhook(srv.URL, nil))
+		err := mgr.DeliverSessionReport(context.Background(), m)
+		Expect(err).To(MatchError(ContainSubstring("500 Internal Server Error")))
+		Expect(received).To(HaveLen(ReportWebhookTries))
+	})
+
+	It("Should validate the url", func() {
+		_, err := NewManager(mockLog, mockLog, WithReportWebhook("ftp://example.net/", nil))
+		Expect(err).To(MatchError("report webhook url must be a http or https url"))
+
+		_, err = NewManager(mockLog, mockLog, WithReportWebhook("https:///report", nil))
+		Expect(err).To(MatchError("report webhook url must include a host"))
+	})
+})
+
+var _ = Describe("WithEventSubjectPrefix", func() {
+	var (
+		ctrl    *gomock.Controller
+		mockLog *modelmocks.MockLogger
+		pub     *modelmocks.MockEventPublisher
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockLog = modelmocks.NewMockLogger(ctrl)
+		mockLog.EXPECT().With(gomock.Any()).AnyTimes().Return(mockLog)
+		mockLog.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+		pub = modelmocks.NewMockEventPublisher(ctrl)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	newPublishingManager := func(prefix string) *CCM {
+		mgr, err := NewManager(mockLog, mockLog, WithEventSubjectPrefix(prefix))
+		Expect(err).NotTo(HaveOccurred())
+		mgr.eventPublisher.connect = func() (model.EventPublisher, error) { return pub, nil }
+		DeferCleanup(mgr.Close)
+
+		return mgr
+	}
+
+	It("Should validate the prefix", func() {
+		for _, prefix := range []string{"", "ccm.*", "ccm.>", "ccm..events", "ccm.events.", "ccm events"} {
+			_, err := NewManager(mockLog, mockLog, WithEventSubjectPrefix(prefix))
+			Expect(err).To(HaveOccurred(), prefix)
+		}
+	})
+
+	It("Should publish events and the summary", func() {
+		mgr := newPublishingManager("ccm.events")
+
+		var eventData, summaryData []byte
+		gomock.InOrder(
+			pub.EXPECT().Publish("ccm.events.resource.file", gomock.Any()).DoAndReturn(func(_ string, data []byte) error {
+				eventData = data
+				return nil
+			}),
+			pub.EXPECT().Publish("ccm.events.summary", gomock.Any()).DoAndReturn(func(_ string, data []byte) error {
+				summaryData = data
+				return nil
+			}),
+		)
+
+		Expect(mgr.RecordEvent(&model.TransactionEvent{ResourceType: "file", Name: "/tmp/test", Changed: true})).To(Succeed())
+		Expect(mgr.DeliverSessionReport(context.Background(), nil)).To(Succeed())
+
+		var event model.TransactionEvent
+		Expect(json.Unmarshal(eventData, &event)).To(Succeed())
+		Expect(event.ResourceType).To(Equal("file"))
+		Expect(event.Name).To(Equal("/tmp/test"))
+		Expect(event.Changed).To(BeTrue())
+
+		var summary model.SessionSummary
+		Expect(json.Unmarshal(summaryData, &summary)).To(Succeed())
+		Expect(summary.TotalResources).To(Equal(1))
+		Expect(summary.ChangedResources).To(Equal(1))
+	})
+
+	It("Should not fail the apply when publishing fails", func() {
+		mgr := newPublishingManager("ccm.events")
+		mockLog.EXPECT().Warn("Could not publish event", gomock.Any()).Times(2)
+		pub.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(fmt.Errorf("publish failed")).Times(2)
+
+		Expect(mgr.RecordEvent(&model.TransactionEvent{ResourceType: "file", Name: "/tmp/test"})).To(Succeed())
+		Expect(mgr.DeliverSessionReport(context.Background(), nil)).To(Succeed())
+	})
+
+	It("Should not fail the apply when connecting fails", func() {
+		mgr, err := NewManager(mockLog, mockLog, WithEventSubjectPrefix("ccm.events"))
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(mgr.Close)
+		mgr.eventPublisher.connect = func() (model.EventPublisher, error) { return nil, fmt.Errorf("no servers") }
+		mockLog.EXPECT().Warn("Could not connect to publish events", gomock.Any()).MinTimes(1)
+
+		Expect(mgr.RecordEvent(&model.TransactionEvent{ResourceType: "file", Name: "/tmp/test"})).To(Succeed())
+		Expect(mgr.DeliverSessionReport(context.Background(), nil)).To(Succeed())
+	})
+
+	It("Should share the publisher with copies", func() {
+		mgr := newPublishingManager("ccm.events")
+
+		cp, err := NewManager(mockLog, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cp.CopyFrom(mgr)).To(Succeed())
+		Expect(cp.eventPublisher).To(BeIdenticalTo(mgr.eventPublisher))
+		Expect(cp.Close()).To(Succeed())
+
+		pub.EXPECT().Publish("ccm.events.resource.package", gomock.Any())
+		pub.EXPECT().Publish("ccm.events.summary", gomock.Any())
+		Expect(mgr.RecordEvent(&model.TransactionEvent{ResourceType: "package", Name: "zsh"})).To(Succeed())
+		Expect(mgr.DeliverSessionReport(context.Background(), nil)).To(Succeed())
+	})
+})
+
+type fakeKVStatus struct {
+	jetstream.KeyValueStatus
+	ttl time.Duration
+}
+
+func (s *fakeKVStatus) TTL() time.Duration { return s.ttl }
+
+var _ = Describe("Service Registry", func() {
+	var (
+		ctrl    *gomock.Controller
+		mockLog *modelmocks.MockLogger
+		mockJS  *modelmocks.MockJetStream
+		mockKV  *modelmocks.MockKeyValue
+		ctx     context.Context
+		entry   *model.RegistrationEntry
+		key     string
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockLog = modelmocks.NewMockLogger(ctrl)
+		mockLog.EXPECT().With(gomock.Any()).AnyTimes().Return(mockLog)
+		mockLog.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+		mockJS = modelmocks.NewMockJetStream(ctrl)
+		mockKV = modelmocks.NewMockKeyValue(ctrl)
+		ctx = context.Background()
+
+		var err error
+		entry, err = model.NewRegistrationEntry("prod", "web", "http", "192.168.1.1", 8080, 1, nil)
+		Expect(err).NotTo(HaveOccurred())
+		key = "prod.http.web.192_168_1_1." + entry.InstanceId()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	newRegistryManager := func() *CCM {
+		mgr, err := NewManager(mockLog, mockLog, WithServiceRegistry("SERVICES", time.Minute))
+		Expect(err).NotTo(HaveOccurred())
+		mgr.js = mockJS
+
+		return mgr
+	}
+
+	expectStored := func(times int) {
+		mockKV.EXPECT().Put(gomock.Any(), key, gomock.Any()).DoAndReturn(func(_ context.Context, _ string, data []byte) (uint64, error) {
+			var stored map[string]any
+			Expect(json.Unmarshal(data, &stored)).To(Succeed())
+			Expect(stored["service"]).To(Equal("web"))
+			Expect(stored["address"]).To(Equal("192.168.1.1"))
+			Expect(stored["ttl"]).To(Equal("1m0s"))
+
+			return 1, nil
+		}).Times(times)
+	}
+
+	It("Should validate the options", func() {
+		_, err := NewManager(mockLog, mockLog, WithServiceRegistry("", time.Minute))
+		Expect(err).To(MatchError("service registry bucket is required"))
+
+		_, err = NewManager(mockLog, mockLog, WithServiceRegistry("SERVICES", time.Millisecond))
+		Expect(err).To(MatchError("service registry ttl must be at least 1s"))
+	})
+
+	It("Should fail when not configured", func() {
+		mgr, err := NewManager(mockLog, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(mgr.RegisterService(ctx, entry)).To(MatchError("service registry not configured"))
+		Expect(mgr.RenewServices(ctx)).To(MatchError("service registry not configured"))
+	})
+
+	It("Should reject invalid entries", func() {
+		mgr := newRegistryManager()
+		entry.Address = "invalid"
+
+		Expect(mgr.RegisterService(ctx, entry)).To(MatchError(model.ErrRegistrationInvalid))
+	})
+
+	It("Should create the bucket with the TTL and renew registered services", func() {
+		mgr := newRegistryManager()
+
+		mockJS.EXPECT().KeyValue(gomock.Any(), "SERVICES").Return(nil, jetstream.ErrBucketNotFound)
+		mockJS.EXPECT().CreateKeyValue(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, cfg jetstream.KeyValueConfig) (jetstream.KeyValue, error) {
+			Expect(cfg.Bucket).To(Equal("SERVICES"))
+			Expect(cfg.TTL).To(Equal(time.Minute))
+			Expect(cfg.History).To(Equal(uint8(1)))
+
+			return mockKV, nil
+		})
+		expectStored(3)
+
+		Expect(mgr.RegisterService(ctx, entry)).To(Succeed())
+		Expect(mgr.RenewService(ctx, entry)).To(Succeed())
+		Expect(mgr.RenewServices(ctx)).To(Succeed())
+	})
+
+	It("Should use existing buckets with a matching TTL", func() {
+		mgr := newRegistryManager()
+
+		mockJS.EXPECT().KeyValue(gomock.Any(), "SERVICES").Return(mockKV, nil)
+		mockKV.EXPECT().Status(gomock.Any()).Return(&fakeKVStatus{ttl: time.Minute}, nil)
+		expectStored(1)
+
+		Expect(mgr.RegisterService(ctx, entry)).To(Succeed())
+	})
+
+	It("Should reject existing buckets with a different TTL", func() {
+		mgr := newRegistryManager()
+
+		mockJS.EXPECT().KeyValue(gomock.Any(), "SERVICES").Return(mockKV, nil)
+		mockKV.EXPECT().Status(gomock.Any()).Return(&fakeKVStatus{}, nil)
+
+		Expect(mgr.RegisterService(ctx, entry)).To(MatchError(`service registry bucket "SERVICES" expires keys after 0s, expected 1m0s`))
+	})
+
+	It("Should only renew registered services", func() {
+		mgr := newRegistryManager()
+
+		Expect(mgr.RenewService(ctx, entry)).To(MatchError(fmt.Sprintf("service %s is not registered", key)))
+		Expect(mgr.RenewServices(ctx)).To(Succeed())
+	})
+
+	It("Should stop renewing deregistered services", func() {
+		mgr := newRegistryManager()
+
+		mockJS.EXPECT().KeyValue(gomock.Any(), "SERVICES").Return(mockKV, nil)
+		mockKV.EXPECT().Status(gomock.Any()).Return(&fakeKVStatus{ttl: time.Minute}, nil)
+		expectStored(1)
+		mockKV.EXPECT().Purge(gomock.Any(), key).Return(nil)
+
+		Expect(mgr.RegisterService(ctx, entry)).To(Succeed())
+		Expect(mgr.DeregisterService(ctx, entry)).To(Succeed())
+		Expect(mgr.RenewServices(ctx)).To(Succeed())
+		Expect(mgr.RenewService(ctx, entry)).To(HaveOccurred())
+	})
+})
+
+// fakeControlRequest is a micro.Request capturing the response of control api handlers
+type fakeControlRequest struct {
+	response    []byte
+	code        string
+	description string
+}
+
+func (r *fakeControlRequest) Respond(data []byte, _ ...micro.RespondOpt) error {
+	r.response = data
+	return nil
+}
+
+func (r *fakeControlRequest) RespondJSON(v any, _ ...micro.RespondOpt) error {
+	j, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return r.Respond(j)
+}
+
+func (r *fakeControlRequest) Error(code, description string, _ []byte, _ ...micro.RespondOpt) error {
+	r.code = code
+	r.description = description
+	return nil
+}
+
+func (r *fakeControlRequest) Data() []byte           { return nil }
+func (r *fakeControlRequest) Headers() micro.Headers { return nil }
+func (r *fakeControlRequest) Subject() string        { return "" }
+func (r *fakeControlRequest) Reply() string          { return "" }
+
+var _ = Describe("Control API", func() {
+	var (
+		ctrl      *gomock.Controller
+		mockLog   *modelmocks.MockLogger
+		mockApply *modelmocks.MockApply
+		mgr       *CCM
+		ctx       context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockLog = modelmocks.NewMockLogger(ctrl)
+		mockLog.EXPECT().With(gomock.Any()).AnyTimes().Return(mockLog)
+		mockLog.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLog.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+		mockApply = modelmocks.NewMockApply(ctrl)
+		mockApply.EXPECT().Resources().Return(nil).AnyTimes()
+		ctx = context.Background()
+
+		var err error
+		mgr, err = NewManager(mockLog, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	// executes simulates an apply of the manifest recording a changed file resource
+	executes := func(ctx context.Context, m model.Manager, healthCheckOnly bool, _ model.Logger) (model.SessionStore, error) {
+		Expect(healthCheckOnly).To(BeFalse())
+		sess, err := m.StartSession(mockApply)
+		Expect(err).NotTo(HaveOccurred())
+
+		event := model.NewTransactionEvent(model.FileTypeName, "/tmp/test", "")
+		event.Changed = true
+		Expect(m.RecordEvent(event)).To(Succeed())
+
+		return sess, nil
+	}
+
+	It("Should fail before any manifest was applied", func() {
+		req := &fakeControlRequest{}
+		mgr.controlSummary(req)
+		Expect(req.code).To(Equal("404"))
+
+		req = &fakeControlRequest{}
+		mgr.controlApply(ctx, req)
+		Expect(req.code).To(Equal("400"))
+		Expect(req.description).To(Equal("no manifest has been applied"))
+
+		req = &fakeControlRequest{}
+		mgr.controlStatus(ctx, req)
+		Expect(req.code).To(BeEmpty())
+
+		var status ControlStatus
+		Expect(json.Unmarshal(req.response, &status)).To(Succeed())
+		Expect(status.Summary).To(BeNil())
+		Expect(status.Applying).To(BeFalse())
+	})
+
+	It("Should respond with the summary of the last session", func() {
+		_, err := executes(ctx, mgr, false, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+
+		req := &fakeControlRequest{}
+		mgr.controlSummary(req)
+		Expect(req.code).To(BeEmpty())
+
+		summary, err := mgr.SessionSummary()
+		Expect(err).NotTo(HaveOccurred())
+		expected, err := json.Marshal(summary)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(req.response).To(MatchJSON(expected))
+		Expect(summary.ChangedResources).To(Equal(1))
+	})
+
+	It("Should apply the last manifest again", func() {
+		_, err := executes(ctx, mgr, false, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+
+		mockApply.EXPECT().Execute(gomock.Any(), mgr, false, gomock.Any()).DoAndReturn(executes)
+
+		req := &fakeControlRequest{}
+		mgr.controlApply(ctx, req)
+		Expect(req.code).To(BeEmpty())
+
+		var summary model.SessionSummary
+		Expect(json.Unmarshal(req.response, &summary)).To(Succeed())
+		Expect(summary.ChangedResources).To(Equal(1))
+	})
+
+	It("Should report apply failures", func() {
+		_, err := executes(ctx, mgr, false, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+
+		mockApply.EXPECT().Execute(gomock.Any(), mgr, false, gomock.Any()).Return(nil, fmt.Errorf("apply failed"))
+
+		req := &fakeControlRequest{}
+		mgr.controlApply(ctx, req)
+		Expect(req.code).To(Equal("500"))
+		Expect(req.description).To(Equal("apply failed"))
 	})
 
-	It("returns facts as JSON", func() {
-		facts := map[string]any{
-			"hostname": "test-host",
-			"count":    float64(42),
+	It("Should never run applies at the same time", func() {
+		_, err := executes(ctx, mgr, false, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+
+		var running, maxRunning atomic.Int32
+		mockApply.EXPECT().Execute(gomock.Any(), mgr, false, gomock.Any()).DoAndReturn(func(ctx context.Context, m model.Manager, hc bool, l model.Logger) (model.SessionStore, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			if n > maxRunning.Load() {
+				maxRunning.Store(n)
+			}
+
+			status := &fakeControlRequest{}
+			mgr.controlStatus(ctx, status)
+			Expect(string(status.response)).To(ContainSubstring(`"applying":true`))
+
+			time.Sleep(10 * time.Millisecond)
+
+			return executes(ctx, m, hc, l)
+		}).Times(3)
+
+		wg := sync.WaitGroup{}
+		for range 3 {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				req := &fakeControlRequest{}
+				mgr.controlApply(ctx, req)
+				Expect(req.code).To(BeEmpty())
+			}()
 		}
-		mgr.SetFacts(facts)
+		wg.Wait()
 
-		raw, err := mgr.FactsRaw(ctx)
-		Expect(err).NotTo(HaveOccurred())
-		Expect(raw).To(MatchJSON(`{"hostname":"test-host","count":42}`))
+		Expect(maxRunning.Load()).To(Equal(int32(1)))
+	})
+
+	It("Should require a nats connection to serve", func() {
+		_, err := mgr.ServeControlAPI(ctx, micro.Config{Name: "ccm", Version: "1.0.0"})
+		Expect(err).To(MatchError("nats context not set"))
 	})
 })
 
-var _ = Describe("Logger", func() {
+var _ = Describe("Watch", func() {
 	var (
 		ctrl    *gomock.Controller
 		mockLog *modelmocks.MockLogger
 		mgr     *CCM
+		ctx     context.Context
+		cancel  context.CancelFunc
 	)
 
 	BeforeEach(func() {
@@ -596,6 +2691,9 @@ var _ = Describe("Logger", func() {
 		mockLog = modelmocks.NewMockLogger(ctrl)
 		mockLog.EXPECT().With(gomock.Any()).AnyTimes().Return(mockLog)
 		mockLog.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLog.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+		ctx, cancel = context.WithCancel(context.Background())
+		DeferCleanup(cancel)
 
 		var err error
 		mgr, err = NewManager(mockLog, mockLog)
@@ -606,65 +2704,131 @@ var _ = Describe("Logger", func() {
 		ctrl.Finish()
 	})
 
-	It("creates a logger with key-value pairs", func() {
-		log, err := mgr.Logger("component", "test", "id", 123)
-		Expect(err).NotTo(HaveOccurred())
-		Expect(log).NotTo(BeNil())
+	It("Should require watchable sources", func() {
+		err := mgr.Watch(ctx, []string{"/etc/ccm/data.yaml", "https://example.net/data.yaml"}, 0, func() {})
+		Expect(err).To(MatchError("no kv:// or obj:// sources to watch"))
+
+		err = mgr.Watch(ctx, []string{"kv://DATA"}, 0, func() {})
+		Expect(err).To(MatchError(`source "kv://DATA" must be in kv://Bucket/Key format`))
+
+		err = mgr.Watch(ctx, []string{"obj:///manifest.tgz"}, 0, func() {})
+		Expect(err).To(MatchError(`source "obj:///manifest.tgz" must be in obj://Bucket/Key format`))
 	})
 
-	It("returns an error for odd number of arguments", func() {
-		_, err := mgr.Logger("component", "test", "orphan")
-		Expect(err).To(MatchError("invalid logger arguments, must be key value pairs"))
+	It("Should require a nats connection", func() {
+		err := mgr.Watch(ctx, []string{"kv://DATA/common"}, 0, func() {})
+		Expect(err).To(MatchError("nats context not set"))
 	})
 
-	It("accepts no arguments", func() {
-		log, err := mgr.Logger()
-		Expect(err).NotTo(HaveOccurred())
-		Expect(log).NotTo(BeNil())
+	It("Should call the callback once after updates stopped", func() {
+		updates := make(chan string)
+		var calls atomic.Int32
+
+		done := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			defer close(done)
+			Expect(mgr.debounceUpdates(ctx, updates, nil, 50*time.Millisecond, func() { calls.Add(1) })).To(Succeed())
+		}()
+
+		for range 5 {
+			updates <- "kv://DATA/common"
+			time.Sleep(5 * time.Millisecond)
+		}
+
+		Consistently(calls.Load, 30*time.Millisecond).Should(Equal(int32(0)))
+		Eventually(calls.Load).Should(Equal(int32(1)))
+		Consistently(calls.Load, 150*time.Millisecond).Should(Equal(int32(1)))
+
+		updates <- "obj://MANIFESTS/web.tgz"
+		Eventually(calls.Load).Should(Equal(int32(2)))
+
+		cancel()
+		Eventually(done).Should(BeClosed())
 	})
-})
 
-var _ = Describe("NewRunner", func() {
-	var (
-		ctrl    *gomock.Controller
-		mockLog *modelmocks.MockLogger
-		mgr     *CCM
-	)
+	It("Should fail once a key watcher stopped", func() {
+		watch := &fakeKeyWatcher{updates: make(chan jetstream.KeyValueEntry)}
+		updates := make(chan string, 1)
+		failed := make(chan error, 1)
 
-	BeforeEach(func() {
-		ctrl = gomock.NewController(GinkgoT())
-		mockLog = modelmocks.NewMockLogger(ctrl)
-		mockLog.EXPECT().With(gomock.Any()).AnyTimes().Return(mockLog)
-		mockLog.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+		go forwardKeyUpdates(ctx, watch, "kv://DATA/common", updates, failed)
+
+		watch.updates <- nil
+		close(watch.updates)
 
 		var err error
-		mgr, err = NewManager(mockLog, mockLog)
-		Expect(err).NotTo(HaveOccurred())
+		Eventually(failed).Should(Receive(&err))
+		Expect(err).To(MatchError("watcher for kv://DATA/common stopped"))
+		Expect(updates).To(BeEmpty())
+		Eventually(watch.stopped.Load).Should(BeTrue())
 	})
 
-	AfterEach(func() {
-		ctrl.Finish()
+	It("Should fail once an object watcher stopped", func() {
+		watch := &fakeObjectWatcher{updates: make(chan *jetstream.ObjectInfo)}
+		updates := make(chan string, 1)
+		failed := make(chan error, 1)
+
+		go forwardObjectUpdates(ctx, watch, "web.tgz", "obj://MANIFESTS/web.tgz", updates, failed)
+
+		watch.updates <- &jetstream.ObjectInfo{ObjectMeta: jetstream.ObjectMeta{Name: "web.tgz"}}
+		Eventually(updates).Should(Receive(Equal("obj://MANIFESTS/web.tgz")))
+		close(watch.updates)
+
+		var err error
+		Eventually(failed).Should(Receive(&err))
+		Expect(err).To(MatchError("watcher for obj://MANIFESTS/web.tgz stopped"))
+		Eventually(watch.stopped.Load).Should(BeTrue())
 	})
 
-	It("creates a command runner", func() {
-		runner, err := mgr.NewRunner()
-		Expect(err).NotTo(HaveOccurred())
-		Expect(runner).NotTo(BeNil())
+	It("Should return the watcher failure after calling pending updates", func() {
+		updates := make(chan string, 1)
+		failed := make(chan error, 1)
+		var calls atomic.Int32
+
+		updates <- "kv://DATA/common"
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			failed <- fmt.Errorf("watcher for kv://DATA/common stopped")
+		}()
+
+		err := mgr.debounceUpdates(ctx, updates, failed, time.Hour, func() { calls.Add(1) })
+		Expect(err).To(MatchError("watcher for kv://DATA/common stopped"))
+		Expect(calls.Load()).To(Equal(int32(1)))
 	})
 })
 
-var _ = Describe("RecordEvent", func() {
+type fakeKeyWatcher struct {
+	updates chan jetstream.KeyValueEntry
+	stopped atomic.Bool
+}
+
+func (w *fakeKeyWatcher) Updates() <-chan jetstream.KeyValueEntry { return w.updates }
+func (w *fakeKeyWatcher) Stop() error                             { w.stopped.Store(true); return nil }
+
+type fakeObjectWatcher struct {
+	updates chan *jetstream.ObjectInfo
+	stopped atomic.Bool
+}
+
+func (w *fakeObjectWatcher) Updates() <-chan *jetstream.ObjectInfo { return w.updates }
+func (w *fakeObjectWatcher) Stop() error                           { w.stopped.Store(true); return nil }
+
+var _ = Describe("RunLoop", func() {
 	var (
-		ctrl    *gomock.Controller
-		mockLog *modelmocks.MockLogger
-		mgr     *CCM
+		ctrl      *gomock.Controller
+		mockLog   *modelmocks.MockLogger
+		mockApply *modelmocks.MockApply
+		mgr       *CCM
 	)
 
 	BeforeEach(func() {
 		ctrl = gomock.NewController(GinkgoT())
 		mockLog = modelmocks.NewMockLogger(ctrl)
 		mockLog.EXPECT().With(gomock.Any()).AnyTimes().Return(mockLog)
-		mockLog.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLog.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLog.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+		mockApply = modelmocks.NewMockApply(ctrl)
 
 		var err error
 		mgr, err = NewManager(mockLog, mockLog)
@@ -675,43 +2839,54 @@ var _ = Describe("RecordEvent", func() {
 		ctrl.Finish()
 	})
 
-	It("records an event in the session store", func() {
-		event := &model.TransactionEvent{
-			ResourceType: "file",
-			Name:         "/tmp/test",
-			Changed:      true,
-		}
-
-		err := mgr.RecordEvent(event)
-		Expect(err).NotTo(HaveOccurred())
+	It("Should validate the interval", func(ctx context.Context) {
+		Expect(mgr.RunLoop(ctx, mockApply, 0, 0)).To(MatchError("interval must be greater than 0"))
+		Expect(mgr.RunLoop(ctx, mockApply, time.Minute, -1)).To(MatchError("jitter cannot be negative"))
 	})
 
-	It("returns an error when session store is nil", func() {
-		mgr.session = nil
+	It("Should apply repeatedly until canceled", func(ctx context.Context) {
+		var runs atomic.Int32
+		mockApply.EXPECT().Execute(gomock.Any(), mgr, false, gomock.Any()).DoAndReturn(func(ctx context.Context, _ model.Manager, _ bool, _ model.Logger) (model.SessionStore, error) {
+			Expect(mgr.applying.Load()).To(BeTrue())
+			runs.Add(1)
+			return nil, nil
+		}).MinTimes(3)
 
-		event := &model.TransactionEvent{}
-		err := mgr.RecordEvent(event)
-		Expect(err).To(MatchError("no session store available"))
-	})
+		ctx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			defer close(done)
+			Expect(mgr.RunLoop(ctx, mockApply, 10*time.Millisecond, 0)).To(Succeed())
+		}()
 
-	It("returns an error when event name is empty", func() {
-		event := &model.TransactionEvent{
-			ResourceType: "file",
-			Name:         "",
-		}
+		Eventually(runs.Load).Should(BeNumerically(">=", 3))
 
-		err := mgr.RecordEvent(event)
-		Expect(err).To(MatchError("event name cannot be empty"))
+		cancel()
+		Eventually(done).Should(BeClosed())
+		Expect(mgr.applying.Load()).To(BeFalse())
 	})
 
-	It("returns an error when resource type is empty", func() {
-		event := &model.TransactionEvent{
-			ResourceType: "",
-			Name:         "/tmp/test",
-		}
+	It("Should log failed applies and keep running", func(ctx context.Context) {
+		var runs atomic.Int32
+		mockLog.EXPECT().Error("Could not apply manifest", "error", gomock.Any()).MinTimes(2)
+		mockApply.EXPECT().Execute(gomock.Any(), mgr, false, gomock.Any()).DoAndReturn(func(context.Context, model.Manager, bool, model.Logger) (model.SessionStore, error) {
+			runs.Add(1)
+			return nil, fmt.Errorf("apply failed")
+		}).MinTimes(2)
 
-		err := mgr.RecordEvent(event)
-		Expect(err).To(MatchError("resource type cannot be empty"))
+		ctx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			defer close(done)
+			Expect(mgr.RunLoop(ctx, mockApply, 10*time.Millisecond, 0)).To(Succeed())
+		}()
+
+		Eventually(runs.Load).Should(BeNumerically(">=", 2))
+
+		cancel()
+		Eventually(done).Should(BeClosed())
 	})
 })
 
@@ -832,6 +3007,95 @@ var _ = Describe("SessionSummary", func() {
 	})
 })
 
+var _ = Describe("RegisterMetrics", func() {
+	var (
+		ctrl    *gomock.Controller
+		mockLog *modelmocks.MockLogger
+		mgr     *CCM
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockLog = modelmocks.NewMockLogger(ctrl)
+		mockLog.EXPECT().With(gomock.Any()).AnyTimes().Return(mockLog)
+		mockLog.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLog.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+
+		var err error
+		mgr, err = NewManager(mockLog, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("Should register the session metrics on the given registry", func() {
+		reg := prometheus.NewPedanticRegistry()
+		RegisterMetrics(reg)
+
+		families, err := reg.Gather()
+		Expect(err).NotTo(HaveOccurred())
+
+		var names []string
+		for _, f := range families {
+			names = append(names, f.GetName())
+		}
+		Expect(names).To(ConsistOf(
+			"choria_ccm_resources_total",
+			"choria_ccm_resources_changed",
+			"choria_ccm_resources_failed",
+			"choria_ccm_last_run_timestamp",
+			"choria_ccm_run_duration_seconds",
+		))
+	})
+
+	It("Should update the metrics from the session summary once per session", func() {
+		total := testutil.ToFloat64(sessionResourcesTotal)
+		changed := testutil.ToFloat64(sessionResourcesChanged)
+		failed := testutil.ToFloat64(sessionResourcesFailed)
+
+		end := time.Now().UTC()
+
+		for i, event := range []*model.TransactionEvent{
+			{ResourceType: "file", Name: "/tmp/test1", Changed: true},
+			{ResourceType: "file", Name: "/tmp/test2", Failed: true},
+			{ResourceType: "file", Name: "/tmp/test3"},
+		} {
+			event.TimeStamp = end.Add(time.Duration(i-2) * time.Second)
+			Expect(mgr.RecordEvent(event)).To(Succeed())
+		}
+
+		_, err := mgr.SessionSummary()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(testutil.ToFloat64(sessionResourcesTotal) - total).To(Equal(3.0))
+		Expect(testutil.ToFloat64(sessionResourcesChanged) - changed).To(Equal(1.0))
+		Expect(testutil.ToFloat64(sessionResourcesFailed) - failed).To(Equal(1.0))
+		Expect(testutil.ToFloat64(sessionLastRunTimestamp)).To(Equal(float64(end.Unix())))
+
+		// a second report of the same session is not counted again
+		_, err = mgr.SessionSummary()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(testutil.ToFloat64(sessionResourcesTotal) - total).To(Equal(3.0))
+	})
+
+	It("Should count every new session", func() {
+		total := testutil.ToFloat64(sessionResourcesTotal)
+
+		for range 2 {
+			_, err := mgr.StartSession(&apply.Apply{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mgr.RecordEvent(&model.TransactionEvent{ResourceType: "file", Name: "/tmp/test1", TimeStamp: time.Now().UTC()})).To(Succeed())
+
+			_, err = mgr.SessionSummary()
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		Expect(testutil.ToFloat64(sessionResourcesTotal) - total).To(Equal(2.0))
+	})
+})
+
 var _ = Describe("TemplateEnvironment", func() {
 	var (
 		ctrl    *gomock.Controller
@@ -872,9 +3136,36 @@ var _ = Describe("TemplateEnvironment", func() {
 		Expect(tplEnv.Data).To(Equal(data))
 		Expect(tplEnv.Environ).To(Equal(env))
 		Expect(tplEnv.WorkingDir).To(Equal("/tmp/working"))
+		Expect(tplEnv.SecretProvider).To(BeNil())
+	})
+
+	It("makes the secret provider available to templates", func() {
+		secrets := mapSecretProvider{"db/password": "s3cr3t"}
+
+		mgr, err := NewManager(mockLog, mockLog, WithSecretProvider(secrets))
+		Expect(err).NotTo(HaveOccurred())
+
+		tplEnv, err := mgr.TemplateEnvironment(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		res, err := templates.ResolveTemplateString("password={{ secret('db/password') }}", tplEnv)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal("password=s3cr3t"))
 	})
 })
 
+// mapSecretProvider is an in-memory secret provider for tests
+type mapSecretProvider map[string]string
+
+func (p mapSecretProvider) Secret(path string) (string, error) {
+	val, ok := p[path]
+	if !ok {
+		return "", fmt.Errorf("secret not found")
+	}
+
+	return val, nil
+}
+
 var _ = Describe("templateKVGet", func() {
 	var (
 		ctrl    *gomock.Controller
@@ -1130,6 +3421,32 @@ var _ = Describe("CopyFrom", func() {
 		Expect(dest.externData).To(Equal(map[string]any{"external": "source-external"}))
 	})
 
+	It("copies the run and cache options", func() {
+		source, err := NewManager(mockLog, mockLog,
+			WithConcurrency(4),
+			WithRunDeadline(time.Minute),
+			WithProtectedPaths("/etc/shadow"),
+			WithDownloadCache(GinkgoT().TempDir(), 1024),
+			WithFactCache(filepath.Join(GinkgoT().TempDir(), "facts.json"), time.Hour),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		dest, err := NewManager(mockLog, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dest.Concurrency()).To(Equal(DefaultConcurrency))
+
+		err = dest.CopyFrom(source)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(dest.Concurrency()).To(Equal(4))
+		Expect(dest.RunDeadline()).To(Equal(time.Minute))
+		Expect(dest.protectedPaths).To(Equal([]string{"/etc/shadow"}))
+		Expect(dest.cacheDir).To(Equal(source.cacheDir))
+		Expect(dest.cacheMaxSize).To(Equal(int64(1024)))
+		Expect(dest.DownloadCache()).To(BeIdenticalTo(source.DownloadCache()))
+		Expect(dest.factCache).To(BeIdenticalTo(source.factCache))
+	})
+
 	It("creates independent copies of map fields", func() {
 		source, err := NewManager(mockLog, mockLog)
 		Expect(err).NotTo(HaveOccurred())
@@ -1473,3 +3790,150 @@ var _ = Describe("SetNoopMode", func() {
 
 // Verify JetStream interface compliance
 var _ jetstream.JetStream = (*modelmocks.MockJetStream)(nil)
+
+var _ = Describe("Exec output data", func() {
+	var (
+		ctrl    *gomock.Controller
+		mockLog *modelmocks.MockLogger
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockLog = modelmocks.NewMockLogger(ctrl)
+		mockLog.EXPECT().With(gomock.Any()).AnyTimes().Return(mockLog)
+		mockLog.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLog.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLog.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLog.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("makes the output of an exec available to later resources", func() {
+		dir := GinkgoT().TempDir()
+		target := filepath.Join(dir, "node.conf")
+		script := filepath.Join(dir, "register.sh")
+		Expect(os.WriteFile(script, []byte("#!/bin/sh\necho '{\"id\":\"abc\",\"port\":8080}'\n"), 0700)).To(Succeed())
+
+		currentUser, err := user.Current()
+		Expect(err).NotTo(HaveOccurred())
+		currentGroup, err := user.LookupGroupId(currentUser.Gid)
+		Expect(err).NotTo(HaveOccurred())
+
+		mgr, err := NewManager(mockLog, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+		defer mgr.Close()
+
+		manifest := fmt.Sprintf(`
+ccm:
+  resources:
+    - exec:
+        name: %s
+        output_key: registration
+    - file:
+        name: %s
+        ensure: present
+        owner: %s
+        group: %s
+        mode: "0600"
+        content: "id={{ Data.registration.id }} port={{ Data.registration.port }}"
+`, script, target, currentUser.Username, currentGroup.Name)
+
+		_, m, err := apply.ResolveManifestReader(context.Background(), mgr, dir, strings.NewReader(manifest))
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = m.Execute(context.Background(), mgr, false, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+
+		summary, err := mgr.SessionSummary()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(summary.FailedResources).To(Equal(0))
+
+		content, err := os.ReadFile(target)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("id=abc port=8080"))
+	})
+})
+
+var _ = Describe("Validate", func() {
+	var (
+		ctrl    *gomock.Controller
+		mockLog *modelmocks.MockLogger
+		mgr     *CCM
+	)
+
+	fileProps := func(name string, mode string) map[string]model.ResourceProperties {
+		contents := "managed by CCM"
+
+		return map[string]model.ResourceProperties{
+			model.FileTypeName: &model.FileResourceProperties{
+				CommonResourceProperties: model.CommonResourceProperties{
+					Type:   model.FileTypeName,
+					Name:   name,
+					Ensure: model.EnsurePresent,
+				},
+				Contents: &contents,
+				Owner:    "{{ Facts.user }}",
+				Group:    "root",
+				Mode:     mode,
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockLog = modelmocks.NewMockLogger(ctrl)
+		mockLog.EXPECT().With(gomock.Any()).AnyTimes().Return(mockLog)
+		mockLog.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLog.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+		var err error
+		mgr, err = NewManager(mockLog, mockLog)
+		Expect(err).NotTo(HaveOccurred())
+		mgr.SetFacts(map[string]any{"user": "root"})
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("requires a manifest", func() {
+		_, err := mgr.Validate(context.Background(), nil)
+		Expect(err).To(MatchError("manifest is required"))
+	})
+
+	It("reports invalid resources without applying any", func() {
+		dir := GinkgoT().TempDir()
+		good := filepath.Join(dir, "good")
+		bad := filepath.Join(dir, "bad")
+
+		manifest := modelmocks.NewMockApply(ctrl)
+		manifest.EXPECT().Resources().Return([]map[string]model.ResourceProperties{
+			fileProps(good, "0644"),
+			fileProps(bad, "0999"),
+		})
+
+		errs, err := mgr.Validate(context.Background(), manifest)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Type).To(Equal(model.FileTypeName))
+		Expect(errs[0].Name).To(Equal(bad))
+		Expect(errs[0].Message).To(ContainSubstring("mode"))
+
+		Expect(good).NotTo(BeAnExistingFile())
+		Expect(bad).NotTo(BeAnExistingFile())
+	})
+
+	It("accepts valid manifests", func() {
+		manifest := modelmocks.NewMockApply(ctrl)
+		manifest.EXPECT().Resources().Return([]map[string]model.ResourceProperties{
+			fileProps(filepath.Join(GinkgoT().TempDir(), "motd"), "0644"),
+		})
+
+		errs, err := mgr.Validate(context.Background(), manifest)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errs).To(BeEmpty())
+	})
+})
@@ -0,0 +1,65 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package manager
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/choria-io/ccm/internal/metrics"
+	"github.com/choria-io/ccm/model"
+)
+
+var (
+	// sessionResourcesTotal counts how many resources were managed across all sessions
+	sessionResourcesTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: prometheus.BuildFQName(metrics.NameSpace, metrics.Subsystem, "resources_total"),
+		Help: "How many resources were managed across all sessions",
+	})
+
+	// sessionResourcesChanged counts how many resources were changed across all sessions
+	sessionResourcesChanged = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: prometheus.BuildFQName(metrics.NameSpace, metrics.Subsystem, "resources_changed"),
+		Help: "How many resources were changed across all sessions",
+	})
+
+	// sessionResourcesFailed counts how many resources failed across all sessions
+	sessionResourcesFailed = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: prometheus.BuildFQName(metrics.NameSpace, metrics.Subsystem, "resources_failed"),
+		Help: "How many resources failed across all sessions",
+	})
+
+	// sessionLastRunTimestamp is the unix time the last session completed
+	sessionLastRunTimestamp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: prometheus.BuildFQName(metrics.NameSpace, metrics.Subsystem, "last_run_timestamp"),
+		Help: "The unix time the last session completed",
+	})
+
+	// sessionRunDuration is how long the last session took
+	sessionRunDuration = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: prometheus.BuildFQName(metrics.NameSpace, metrics.Subsystem, "run_duration_seconds"),
+		Help: "How long the last session took",
+	})
+)
+
+// RegisterMetrics registers the session metrics with reg, they are updated from the summary of every session
+func RegisterMetrics(reg prometheus.Registerer) {
+	reg.MustRegister(sessionResourcesTotal)
+	reg.MustRegister(sessionResourcesChanged)
+	reg.MustRegister(sessionResourcesFailed)
+	reg.MustRegister(sessionLastRunTimestamp)
+	reg.MustRegister(sessionRunDuration)
+}
+
+// updateSessionMetrics publishes the outcome of a session
+func updateSessionMetrics(summary *model.SessionSummary) {
+	sessionResourcesTotal.Add(float64(summary.TotalResources))
+	sessionResourcesChanged.Add(float64(summary.ChangedResources))
+	sessionResourcesFailed.Add(float64(summary.FailedResources))
+	sessionRunDuration.Set(summary.TotalDuration.Seconds())
+
+	if !summary.EndTime.IsZero() {
+		sessionLastRunTimestamp.Set(float64(summary.EndTime.Unix()))
+	}
+}
@@ -5,8 +5,19 @@
 package manager
 
 import (
+	"fmt"
+	"io"
+	neturl "net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/choria-io/ccm/internal/breaker"
+	"github.com/choria-io/ccm/internal/cmdrunner"
 	"github.com/choria-io/ccm/internal/session"
+	iu "github.com/choria-io/ccm/internal/util"
 	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/templates"
 )
 
 // Option is a functional option for configuring CCM
@@ -55,6 +66,43 @@ func WithSessionDirectory(path string) Option {
 	}
 }
 
+// WithExternalFactsDir gathers facts from the facts files in dir and the output of the *.sh scripts in it
+func WithExternalFactsDir(dir string) Option {
+	return func(c *CCM) error {
+		if dir == "" {
+			return fmt.Errorf("external facts directory is required")
+		}
+
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return err
+		}
+
+		c.externalFactsDir = abs
+		return nil
+	}
+}
+
+// WithFactCache stores gathered facts in the JSON file path and reuses them in later runs until ttl passed
+func WithFactCache(path string, ttl time.Duration) Option {
+	return func(c *CCM) error {
+		if path == "" {
+			return fmt.Errorf("fact cache path is required")
+		}
+		if ttl <= 0 {
+			return fmt.Errorf("fact cache ttl must be positive")
+		}
+
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+
+		c.factCache = newFactCache(abs, ttl)
+		return nil
+	}
+}
+
 // WithEnvironmentData sets environment data
 func WithEnvironmentData(data map[string]string) Option {
 	return func(c *CCM) error {
@@ -75,9 +123,349 @@ func WithNoop() Option {
 	}
 }
 
+// WithSkipIfUnmanageable records resources that no provider can manage as not applicable rather than failing
+func WithSkipIfUnmanageable() Option {
+	return func(ccm *CCM) error {
+		ccm.skipUnmanageable = true
+		return nil
+	}
+}
+
+// WithRunDeadline limits the total time a manifest apply may take, once passed the running resource is
+// canceled and remaining resources are skipped
+func WithRunDeadline(deadline time.Duration) Option {
+	return func(ccm *CCM) error {
+		if deadline < 0 {
+			return fmt.Errorf("run deadline cannot be negative")
+		}
+
+		ccm.runDeadline = deadline
+		return nil
+	}
+}
+
+// WithConcurrency applies up to n resources at the same time, resources still wait for the resources they are
+// ordered after, defaults to DefaultConcurrency
+func WithConcurrency(n int) Option {
+	return func(ccm *CCM) error {
+		if n < 1 {
+			return fmt.Errorf("concurrency must be at least 1")
+		}
+
+		ccm.concurrency = n
+		return nil
+	}
+}
+
+// WithProtectedPaths adds paths that resources will never remove to the default protected paths
+func WithProtectedPaths(paths ...string) Option {
+	return func(ccm *CCM) error {
+		for _, path := range paths {
+			err := iu.ValidateCanonicalPath("protected path", path)
+			if err != nil {
+				return err
+			}
+		}
+
+		ccm.protectedPaths = append(ccm.protectedPaths, paths...)
+		return nil
+	}
+}
+
+// WithEventSink writes every recorded transaction event to w as a JSON line, write errors are logged and never fail the apply
+func WithEventSink(w io.Writer) Option {
+	return func(ccm *CCM) error {
+		ccm.eventSink = w
+		return nil
+	}
+}
+
+// WithEventFile appends every recorded transaction event to the file at path as a JSON line, - writes to STDOUT
+func WithEventFile(path string) Option {
+	return func(ccm *CCM) error {
+		if path == "-" {
+			ccm.eventSink = os.Stdout
+			return nil
+		}
+
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0640)
+		if err != nil {
+			return fmt.Errorf("could not open event file: %w", err)
+		}
+
+		ccm.eventFile = f
+		ccm.eventSink = f
+
+		return nil
+	}
+}
+
+// WithCommandRecording records every command providers run, and its outcome, to file as JSON lines so the run can
+// later be replayed using WithCommandReplay, values of sensitive properties and environment variables are redacted
+func WithCommandRecording(file string) Option {
+	return func(ccm *CCM) error {
+		if ccm.replayer != nil {
+			return fmt.Errorf("cannot record commands while replaying a recording")
+		}
+
+		log, err := ccm.Logger("component", "runner")
+		if err != nil {
+			return err
+		}
+
+		runner, err := cmdrunner.NewCommandRunner(log)
+		if err != nil {
+			return err
+		}
+
+		ccm.recorder, err = cmdrunner.NewRecorder(runner, file)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
+
+// WithCommandReplay replays a recording made using WithCommandRecording, providers receive the recorded outcomes
+// instead of running commands and commands not in the recording fail
+func WithCommandReplay(file string) Option {
+	return func(ccm *CCM) error {
+		if ccm.recorder != nil {
+			return fmt.Errorf("cannot replay a recording while recording commands")
+		}
+
+		var err error
+		ccm.replayer, err = cmdrunner.NewReplayer(file)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
+
+// WithRefreshStateDirectory persists refreshes triggered by subscriptions in a session store in dir, refreshes
+// that were not processed, for example because a run was interrupted, are then triggered by the next run
+func WithRefreshStateDirectory(dir string) Option {
+	return func(ccm *CCM) error {
+		log, err := ccm.Logger("session", "refresh", "path", dir)
+		if err != nil {
+			return err
+		}
+
+		store, err := session.NewDirectorySessionStore(dir, log, ccm.userLogger)
+		if err != nil {
+			return fmt.Errorf("could not create refresh state store: %w", err)
+		}
+
+		ccm.refreshStore = store
+
+		return nil
+	}
+}
+
+// WithConvergedStateDirectory records resources found in their desired state in a session store in dir, later
+// runs trust that result and skip checking resources whose properties and data are unchanged until trust passed.
+// A failed resource removes all records so the next run checks every resource.
+func WithConvergedStateDirectory(dir string, trust time.Duration) Option {
+	return func(ccm *CCM) error {
+		if trust <= 0 {
+			return fmt.Errorf("trust window must be positive")
+		}
+
+		log, err := ccm.Logger("session", "converged", "path", dir)
+		if err != nil {
+			return err
+		}
+
+		store, err := session.NewDirectorySessionStore(dir, log, ccm.userLogger)
+		if err != nil {
+			return fmt.Errorf("could not create converged state store: %w", err)
+		}
+
+		ccm.convergedStore = store
+		ccm.trustWindow = trust
+
+		return nil
+	}
+}
+
+// WithProviderConfig sets configuration for all providers named provider, providers read it when they are created
+// and properties set on a resource take precedence over it. Calling it again for the same provider replaces the
+// configuration.
+func WithProviderConfig(provider string, config map[string]any) Option {
+	return func(ccm *CCM) error {
+		if provider == "" {
+			return fmt.Errorf("provider name is required")
+		}
+
+		if ccm.providerConfig == nil {
+			ccm.providerConfig = make(map[string]map[string]any)
+		}
+
+		ccm.providerConfig[provider] = iu.CloneMap(config)
+
+		return nil
+	}
+}
+
+// WithDownloadCache enables a download cache stored in dir that is shared by all resources, a maxSize
+// in bytes larger than 0 evicts the least recently used entries once the cache grows beyond it
+func WithDownloadCache(dir string, maxSize int64) Option {
+	return func(ccm *CCM) error {
+		if dir == "" {
+			return fmt.Errorf("download cache directory is required")
+		}
+
+		ccm.cacheDir = dir
+		ccm.cacheMaxSize = maxSize
+		return nil
+	}
+}
+
+// WithDebugDump writes a debug dump to dir whenever a manifest apply has failed resources or fails, dumps larger
+// than maxSize bytes omit their largest sections, DefaultDebugDumpMaxSize is used when maxSize is 0
+func WithDebugDump(dir string, maxSize int64) Option {
+	return func(ccm *CCM) error {
+		if dir == "" {
+			return fmt.Errorf("debug dump directory is required")
+		}
+		if maxSize < 0 {
+			return fmt.Errorf("debug dump size cannot be negative")
+		}
+
+		ccm.debugDumpDir = dir
+		ccm.debugDumpMaxSize = maxSize
+		return nil
+	}
+}
+
+// WithReportWebhook posts a report holding the summary and transaction events of every completed apply to url
+// as JSON, headers are added to every request. Delivery failures are retried and logged but never fail the apply.
+func WithReportWebhook(url string, headers map[string]string) Option {
+	return func(ccm *CCM) error {
+		u, err := neturl.Parse(url)
+		if err != nil {
+			return fmt.Errorf("invalid report webhook url: %w", err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("report webhook url must be a http or https url")
+		}
+		if u.Host == "" {
+			return fmt.Errorf("report webhook url must include a host")
+		}
+
+		ccm.reportWebhook = newReportWebhook(url, headers)
+		return nil
+	}
+}
+
+// WithPauseMarker pauses management while marker exists, applies then only run health checks. The marker is a
+// file path or a key in a KV bucket given as kv://Bucket/Key.
+func WithPauseMarker(marker string) Option {
+	return func(ccm *CCM) error {
+		bucket, key, isKV := parseKVMarker(marker)
+		switch {
+		case marker == "":
+			return fmt.Errorf("pause marker is required")
+		case isKV && (bucket == "" || key == ""):
+			return fmt.Errorf("pause marker %q must be in kv://Bucket/Key format", marker)
+		}
+
+		ccm.pauseMarker = marker
+		return nil
+	}
+}
+
+// WithJetStreamTimeout sets the maximum time a single JetStream call like a hiera KV lookup or manifest fetch
+// may take, defaults to DefaultJetStreamTimeout
+func WithJetStreamTimeout(timeout time.Duration) Option {
+	return func(ccm *CCM) error {
+		if timeout <= 0 {
+			return fmt.Errorf("jetstream timeout must be positive")
+		}
+
+		ccm.jsTimeout = timeout
+		return nil
+	}
+}
+
+// WithJetStreamCircuitBreaker makes JetStream calls fail fast for cooldown after threshold consecutive calls failed
+// due to NATS being unavailable, a threshold of 0 disables the breaker
+func WithJetStreamCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(ccm *CCM) error {
+		if threshold < 0 {
+			return fmt.Errorf("jetstream failure threshold cannot be negative")
+		}
+
+		if threshold > 0 && cooldown <= 0 {
+			return fmt.Errorf("jetstream cooldown must be positive")
+		}
+
+		ccm.jsBreaker = breaker.New(threshold, cooldown)
+		return nil
+	}
+}
+
+// WithEventSubjectPrefix publishes every transaction event to <prefix>.resource.<type> and the session summary
+// to <prefix>.summary using the NATS connection, requires the context be set using WithNatsContext(). Events are
+// published in the background, failures are logged and never fail the apply
+func WithEventSubjectPrefix(prefix string) Option {
+	return func(ccm *CCM) error {
+		err := validateSubjectPrefix(prefix)
+		if err != nil {
+			return err
+		}
+
+		log, err := ccm.Logger("component", "events", "prefix", prefix)
+		if err != nil {
+			return err
+		}
+
+		ccm.eventPublisher = newEventPublisher(prefix, func() (model.EventPublisher, error) {
+			nc, err := ccm.NatsConnection()
+			if err != nil {
+				return nil, err
+			}
+
+			return nc, nil
+		}, log)
+		ccm.ownsPublisher = true
+
+		return nil
+	}
+}
+
+// WithServiceRegistry enables registering services in the KV bucket, the bucket expires services not renewed within
+// ttl. The bucket is created when it does not exist, requires the context be set using WithNatsContext()
+func WithServiceRegistry(bucket string, ttl time.Duration) Option {
+	return func(ccm *CCM) error {
+		if bucket == "" {
+			return fmt.Errorf("service registry bucket is required")
+		}
+
+		if ttl < time.Second {
+			return fmt.Errorf("service registry ttl must be at least 1s")
+		}
+
+		ccm.serviceRegistry = newServiceRegistry(bucket, ttl)
+
+		return nil
+	}
+}
+
 func WithNatsConnection(p model.NatsConnProvider) Option {
 	return func(ccm *CCM) error {
 		ccm.ncProvider = p
 		return nil
 	}
 }
+
+// WithSecretProvider sets the provider used by the secret() template function, without one secret() fails
+func WithSecretProvider(p templates.SecretProvider) Option {
+	return func(ccm *CCM) error {
+		ccm.secretProvider = p
+		return nil
+	}
+}
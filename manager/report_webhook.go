@@ -0,0 +1,153 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package manager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"time"
+
+	"github.com/choria-io/ccm/internal/backoff"
+	"github.com/choria-io/ccm/model"
+)
+
+const (
+	// ReportWebhookTries is how many times delivering a session report to the webhook is attempted
+	ReportWebhookTries = 5
+
+	// ReportWebhookTimeout is the maximum time a single webhook delivery attempt may take
+	ReportWebhookTimeout = 10 * time.Second
+)
+
+// reportWebhook posts session reports to a HTTP endpoint
+type reportWebhook struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+	backoff backoff.Policy
+}
+
+// DeliverSessionReport publishes the summary of the current session to the event subjects and posts the summary
+// and transaction events to the report webhook, values of sensitive properties are redacted. Delivery is retried
+// ReportWebhookTries times, failures are returned for logging and should never fail the run. Does nothing when
+// neither event subjects nor a webhook are configured.
+func (m *CCM) DeliverSessionReport(ctx context.Context, apply model.Apply) error {
+	m.mu.Lock()
+	webhook := m.reportWebhook
+	publisher := m.eventPublisher
+	session := m.session
+	m.mu.Unlock()
+
+	if webhook == nil && publisher == nil {
+		return nil
+	}
+
+	if session == nil {
+		return fmt.Errorf("no session store available")
+	}
+
+	events, err := session.AllEvents()
+	if err != nil {
+		return err
+	}
+
+	summary := model.BuildSessionSummary(events)
+
+	if publisher != nil {
+		publisher.publishSummary(summary)
+		publisher.flush(EventPublishFlushTimeout)
+	}
+
+	if webhook == nil {
+		return nil
+	}
+
+	report := &model.SessionReport{
+		Summary: summary,
+		Events:  []*model.TransactionEvent{},
+	}
+	for _, event := range events {
+		if txEvent, ok := event.(*model.TransactionEvent); ok {
+			report.Events = append(report.Events, txEvent)
+		}
+	}
+
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	var sensitive []string
+	for _, r := range apply.Resources() {
+		for _, prop := range r {
+			if prop != nil {
+				sensitive = append(sensitive, model.SensitiveValues(prop)...)
+			}
+		}
+	}
+	body = redactValues(body, sensitive)
+
+	for try := 0; try < ReportWebhookTries; try++ {
+		if try > 0 {
+			err = webhook.backoff.TrySleep(ctx, try-1)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = webhook.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+
+		m.log.Warn("Could not deliver session report", "url", webhook.url, "try", try+1, "error", err)
+	}
+
+	return fmt.Errorf("could not deliver session report after %d tries: %w", ReportWebhookTries, err)
+}
+
+func (w *reportWebhook) post(ctx context.Context, body []byte) error {
+	timeoutCtx, cancel := context.WithTimeout(ctx, ReportWebhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(timeoutCtx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// drain the body so the connection can be reused by later tries
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response: %s", resp.Status)
+	}
+
+	return nil
+}
+
+func newReportWebhook(url string, headers map[string]string) *reportWebhook {
+	return &reportWebhook{
+		url:     url,
+		headers: maps.Clone(headers),
+		client:  &http.Client{},
+		backoff: backoff.FiveSec,
+	}
+}
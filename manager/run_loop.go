@@ -0,0 +1,39 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package manager
+
+import (
+	"context"
+	"time"
+
+	"github.com/choria-io/ccm/internal/runloop"
+	"github.com/choria-io/ccm/model"
+)
+
+// RunLoop applies manifest immediately and then on every interval until ctx is canceled, each run is delayed by
+// a random duration up to jitter so many nodes do not apply at the same time. Runs are scheduled on a fixed period
+// regardless of how long they take, a run due while the previous one is still going is skipped. Runs never overlap
+// with applies requested using the control API.
+func (m *CCM) RunLoop(ctx context.Context, manifest model.Apply, interval time.Duration, jitter time.Duration) error {
+	log, err := m.Logger("component", "run_loop")
+	if err != nil {
+		return err
+	}
+
+	loop, err := runloop.New("apply", func(ctx context.Context) {
+		m.controlMu.Lock()
+		defer m.controlMu.Unlock()
+
+		err := m.executeManifest(ctx, manifest)
+		if err != nil {
+			log.Error("Could not apply manifest", "error", err)
+		}
+	}, runloop.Options{Interval: interval, Jitter: jitter, Immediate: true}, log)
+	if err != nil {
+		return err
+	}
+
+	return loop.Run(ctx)
+}
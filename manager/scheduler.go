@@ -0,0 +1,171 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/choria-io/ccm/model"
+)
+
+// DefaultConcurrency is the default number of resources applied at the same time
+const DefaultConcurrency = 1
+
+// scheduledResult is the outcome of applying a single scheduled resource
+type scheduledResult struct {
+	node int
+	stop bool
+	err  error
+}
+
+// ScheduleResources calls apply for every resource, a resource is only started once all resources it requires or
+// subscribes to, and all resources listing it in before, completed. Up to Concurrency() resources are applied at the
+// same time, when more resources are ready they are started in manifest order. Once apply returns an error or requests
+// a stop no further resources are started, resources already running complete and the first error is returned. Apply
+// resources change the data, noop mode and working directory of the manager so they are always applied on their own.
+func (m *CCM) ScheduleResources(ctx context.Context, resources []map[string]model.ResourceProperties, apply model.ScheduledResourceFunc) error {
+	graph, err := model.BuildResourceGraph(resources, nil)
+	if err != nil {
+		return err
+	}
+
+	// a cycle can never be ordered, report the resources involved so the manifest can be fixed
+	if len(graph.Cycles) > 0 {
+		var errs []error
+		for _, cycle := range graph.Cycles {
+			errs = append(errs, fmt.Errorf("%w: %s -> %s", model.ErrResourceCycle, strings.Join(cycle, " -> "), cycle[0]))
+		}
+
+		return errors.Join(errs...)
+	}
+
+	// nodes in the graph are in manifest order and exclude nil properties
+	var props []model.ResourceProperties
+	for _, r := range resources {
+		for _, prop := range r {
+			if prop != nil {
+				props = append(props, prop)
+			}
+		}
+	}
+
+	// manifests may repeat a resource name so nodes are referenced by position, a reference to a repeated name
+	// orders against every resource using that name
+	positions := map[string][]int{}
+	for _, node := range graph.Nodes {
+		positions[node.ID] = append(positions[node.ID], node.Order)
+		if node.Alias != "" {
+			alias := fmt.Sprintf("%s#%s", node.Type, node.Alias)
+			positions[alias] = append(positions[alias], node.Order)
+		}
+	}
+
+	pending := make([]int, len(graph.Nodes))
+	dependents := make([][]int, len(graph.Nodes))
+	addDependency := func(from int, to int) {
+		if from == to {
+			return
+		}
+
+		dependents[from] = append(dependents[from], to)
+		pending[to]++
+	}
+
+	// missing dependencies are reported by the resource as unmet requirements, conflicts do not order resources
+	for _, node := range graph.Nodes {
+		prop := props[node.Order]
+		deps := slices.Clone(prop.CommonProperties().Require)
+
+		sp, ok := prop.(model.SubscribingResourceProperties)
+		if ok {
+			deps = append(deps, sp.Subscriptions()...)
+		}
+
+		for _, dep := range deps {
+			for _, from := range positions[dep] {
+				addDependency(from, node.Order)
+			}
+		}
+
+		for _, dependent := range prop.CommonProperties().Before {
+			for _, to := range positions[dependent] {
+				addDependency(node.Order, to)
+			}
+		}
+	}
+
+	var ready []int
+	for i := range graph.Nodes {
+		if pending[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	workers := m.Concurrency()
+	results := make(chan scheduledResult)
+
+	var running int
+	var stopped bool
+	var exclusive bool
+	var firstErr error
+
+	for {
+		for !stopped && !exclusive && running < workers && len(ready) > 0 {
+			node := ready[0]
+			if graph.Nodes[node].Type == model.ApplyTypeName {
+				if running > 0 {
+					break
+				}
+
+				exclusive = true
+			}
+
+			ready = ready[1:]
+			running++
+
+			go func(node int) {
+				stop, err := apply(ctx, props[node])
+				results <- scheduledResult{node: node, stop: stop, err: err}
+			}(node)
+		}
+
+		if running == 0 {
+			break
+		}
+
+		res := <-results
+		running--
+		exclusive = false
+
+		if res.err != nil && firstErr == nil {
+			firstErr = res.err
+		}
+		if res.err != nil || res.stop {
+			stopped = true
+		}
+
+		for _, dependent := range dependents[res.node] {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				pos, _ := slices.BinarySearch(ready, dependent)
+				ready = slices.Insert(ready, pos, dependent)
+			}
+		}
+	}
+
+	return firstErr
+}
+
+// Concurrency is the maximum number of resources applied at the same time
+func (m *CCM) Concurrency() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.concurrency
+}
@@ -0,0 +1,215 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+
+	"github.com/choria-io/ccm/model"
+)
+
+// serviceRegistry keeps services registered in a KV bucket, the bucket TTL expires services that are not renewed
+type serviceRegistry struct {
+	bucket   string
+	ttl      time.Duration
+	kv       jetstream.KeyValue
+	services map[string]*model.RegistrationEntry
+
+	mu sync.Mutex
+}
+
+func newServiceRegistry(bucket string, ttl time.Duration) *serviceRegistry {
+	return &serviceRegistry{
+		bucket:   bucket,
+		ttl:      ttl,
+		services: make(map[string]*model.RegistrationEntry),
+	}
+}
+
+// serviceKey is the KV key of a service, unique per cluster, protocol, service, address and port
+func serviceKey(entry *model.RegistrationEntry) string {
+	return fmt.Sprintf("%s.%s.%s.%s.%s", entry.Cluster, entry.Protocol, entry.Service, entry.SubjectAddress(), entry.InstanceId())
+}
+
+// RegisterService validates entry and stores it in the service registry bucket, it is kept alive by RenewServices
+// until removed using DeregisterService. Services that could not be stored are still renewed so a later renewal
+// registers them. Requires the registry be configured using WithServiceRegistry()
+func (m *CCM) RegisterService(ctx context.Context, entry *model.RegistrationEntry) error {
+	reg, err := m.serviceRegistryConfigured()
+	if err != nil {
+		return err
+	}
+
+	err = entry.Validate()
+	if err != nil {
+		return err
+	}
+
+	key := serviceKey(entry)
+
+	reg.mu.Lock()
+	reg.services[key] = entry
+	reg.mu.Unlock()
+
+	return m.putService(ctx, reg, key, entry)
+}
+
+// RenewService stores a registered service again, restarting its TTL
+func (m *CCM) RenewService(ctx context.Context, entry *model.RegistrationEntry) error {
+	reg, err := m.serviceRegistryConfigured()
+	if err != nil {
+		return err
+	}
+
+	key := serviceKey(entry)
+
+	reg.mu.Lock()
+	_, ok := reg.services[key]
+	reg.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("service %s is not registered", key)
+	}
+
+	return m.putService(ctx, reg, key, entry)
+}
+
+// RenewServices renews every registered service, typically called after each monitoring pass so services
+// expire once the node stops checking them
+func (m *CCM) RenewServices(ctx context.Context) error {
+	reg, err := m.serviceRegistryConfigured()
+	if err != nil {
+		return err
+	}
+
+	reg.mu.Lock()
+	keys := slices.Sorted(maps.Keys(reg.services))
+	services := maps.Clone(reg.services)
+	reg.mu.Unlock()
+
+	var errs []error
+	for _, key := range keys {
+		err = m.putService(ctx, reg, key, services[key])
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// DeregisterService removes a service from the service registry bucket and stops renewing it
+func (m *CCM) DeregisterService(ctx context.Context, entry *model.RegistrationEntry) error {
+	reg, err := m.serviceRegistryConfigured()
+	if err != nil {
+		return err
+	}
+
+	key := serviceKey(entry)
+
+	err = m.serviceRegistryCall(ctx, reg, func(ctx context.Context, kv jetstream.KeyValue) error {
+		err := kv.Purge(ctx, key)
+		if err != nil {
+			return fmt.Errorf("could not remove service %s: %w", key, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	reg.mu.Lock()
+	delete(reg.services, key)
+	reg.mu.Unlock()
+
+	return nil
+}
+
+func (m *CCM) serviceRegistryConfigured() (*serviceRegistry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.serviceRegistry == nil {
+		return nil, fmt.Errorf("service registry not configured")
+	}
+
+	return m.serviceRegistry, nil
+}
+
+// putService stores entry under key with the TTL set to the bucket TTL
+func (m *CCM) putService(ctx context.Context, reg *serviceRegistry, key string, entry *model.RegistrationEntry) error {
+	stored := *entry
+	stored.TTL = model.NewRegistrationTTL(reg.ttl)
+
+	j, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+
+	return m.serviceRegistryCall(ctx, reg, func(ctx context.Context, kv jetstream.KeyValue) error {
+		_, err := kv.Put(ctx, key, j)
+		if err != nil {
+			return fmt.Errorf("could not store service %s: %w", key, err)
+		}
+
+		return nil
+	})
+}
+
+// serviceRegistryCall calls cb with the registry bucket, creating the bucket with the registry TTL when it does
+// not exist. Existing buckets must expire keys after the registry TTL.
+func (m *CCM) serviceRegistryCall(ctx context.Context, reg *serviceRegistry, cb func(ctx context.Context, kv jetstream.KeyValue) error) error {
+	return m.JetStreamCall(ctx, func(ctx context.Context, js jetstream.JetStream) error {
+		reg.mu.Lock()
+		kv := reg.kv
+		reg.mu.Unlock()
+
+		if kv == nil {
+			var err error
+
+			kv, err = js.KeyValue(ctx, reg.bucket)
+			switch {
+			case errors.Is(err, jetstream.ErrBucketNotFound):
+				kv, err = js.CreateKeyValue(ctx, jetstream.KeyValueConfig{
+					Bucket:      reg.bucket,
+					Description: "CCM Service Registry",
+					History:     1,
+					TTL:         reg.ttl,
+				})
+				if err != nil {
+					return fmt.Errorf("could not create service registry bucket %q: %w", reg.bucket, err)
+				}
+
+			case err != nil:
+				return fmt.Errorf("could not access service registry bucket %q: %w", reg.bucket, err)
+
+			default:
+				status, err := kv.Status(ctx)
+				if err != nil {
+					return fmt.Errorf("could not access service registry bucket %q: %w", reg.bucket, err)
+				}
+
+				if status.TTL() != reg.ttl {
+					return fmt.Errorf("service registry bucket %q expires keys after %v, expected %v", reg.bucket, status.TTL(), reg.ttl)
+				}
+			}
+
+			reg.mu.Lock()
+			reg.kv = kv
+			reg.mu.Unlock()
+		}
+
+		return cb(ctx, kv)
+	})
+}
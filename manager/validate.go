@@ -0,0 +1,40 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/resources"
+)
+
+// Validate creates every resource in the manifest, running the property validation and template resolution
+// performed before applying, without applying any of them so nothing on the system is changed.
+//
+// An error is only returned when validation could not be performed, problems with resources are returned as
+// validation errors
+func (m *CCM) Validate(ctx context.Context, manifest model.Apply) ([]*resources.ValidationError, error) {
+	if manifest == nil {
+		return nil, fmt.Errorf("manifest is required")
+	}
+
+	var errs []*resources.ValidationError
+	for _, r := range manifest.Resources() {
+		for typeName, prop := range r {
+			if prop == nil {
+				continue
+			}
+
+			_, err := resources.NewResourceFromProperties(ctx, m, prop)
+			if err != nil {
+				errs = append(errs, &resources.ValidationError{Type: typeName, Name: prop.CommonProperties().Name, Message: err.Error()})
+			}
+		}
+	}
+
+	return errs, nil
+}
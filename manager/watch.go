@@ -0,0 +1,209 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// DefaultWatchDebounce is how long Watch waits for updates to stop arriving before calling its callback
+const DefaultWatchDebounce = 5 * time.Second
+
+// Watch watches kv://Bucket/Key and obj://Bucket/Key sources for updates and calls cb once no further updates
+// arrived for debounce, a burst of updates results in a single call. Other sources are ignored. Blocks until ctx
+// is canceled or a watcher stopped, for example when the NATS connection closed, an error is returned in the
+// latter case so callers can watch again. Requires the context be set using WithNatsContext().
+func (m *CCM) Watch(ctx context.Context, sources []string, debounce time.Duration, cb func()) error {
+	if debounce <= 0 {
+		debounce = DefaultWatchDebounce
+	}
+
+	var uris []*url.URL
+	for _, source := range sources {
+		uri, err := url.Parse(source)
+		if err != nil {
+			return fmt.Errorf("invalid source %q: %w", source, err)
+		}
+
+		if uri.Scheme != "kv" && uri.Scheme != "obj" {
+			continue
+		}
+
+		if uri.Host == "" || strings.TrimPrefix(uri.Path, "/") == "" {
+			return fmt.Errorf("source %q must be in %s://Bucket/Key format", source, uri.Scheme)
+		}
+
+		uris = append(uris, uri)
+	}
+
+	if len(uris) == 0 {
+		return fmt.Errorf("no kv:// or obj:// sources to watch")
+	}
+
+	js, err := m.JetStream()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	updates := make(chan string, len(uris))
+	failed := make(chan error, len(uris))
+
+	for _, uri := range uris {
+		bucket := uri.Host
+		key := strings.TrimPrefix(uri.Path, "/")
+
+		switch uri.Scheme {
+		case "kv":
+			err = m.watchKey(ctx, js, bucket, key, updates, failed)
+		case "obj":
+			err = m.watchObject(ctx, js, bucket, key, updates, failed)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	m.log.Info("Watching sources for updates", "sources", len(uris), "debounce", debounce)
+
+	return m.debounceUpdates(ctx, updates, failed, debounce, cb)
+}
+
+// watchKey sends the source url to updates whenever key in bucket is updated or deleted
+func (m *CCM) watchKey(ctx context.Context, js jetstream.JetStream, bucket string, key string, updates chan<- string, failed chan<- error) error {
+	kv, err := js.KeyValue(ctx, bucket)
+	if err != nil {
+		return fmt.Errorf("could not access bucket %q: %w", bucket, err)
+	}
+
+	watch, err := kv.Watch(ctx, key, jetstream.UpdatesOnly())
+	if err != nil {
+		return fmt.Errorf("could not watch bucket %q: %w", bucket, err)
+	}
+
+	go forwardKeyUpdates(ctx, watch, fmt.Sprintf("kv://%s/%s", bucket, key), updates, failed)
+
+	return nil
+}
+
+// forwardKeyUpdates sends source to updates for every update seen by watch, failed receives an error once the
+// watcher stopped
+func forwardKeyUpdates(ctx context.Context, watch jetstream.KeyWatcher, source string, updates chan<- string, failed chan<- error) {
+	defer watch.Stop()
+
+	for {
+		select {
+		case entry, ok := <-watch.Updates():
+			if !ok {
+				failed <- fmt.Errorf("watcher for %s stopped", source)
+				return
+			}
+
+			if entry == nil {
+				continue
+			}
+
+			sendUpdate(ctx, updates, source)
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// watchObject sends the source url to updates whenever file in bucket is updated or deleted
+func (m *CCM) watchObject(ctx context.Context, js jetstream.JetStream, bucket string, file string, updates chan<- string, failed chan<- error) error {
+	obj, err := js.ObjectStore(ctx, bucket)
+	if err != nil {
+		return fmt.Errorf("could not access bucket %q: %w", bucket, err)
+	}
+
+	watch, err := obj.Watch(ctx, jetstream.UpdatesOnly())
+	if err != nil {
+		return fmt.Errorf("could not watch bucket %q: %w", bucket, err)
+	}
+
+	go forwardObjectUpdates(ctx, watch, file, fmt.Sprintf("obj://%s/%s", bucket, file), updates, failed)
+
+	return nil
+}
+
+// forwardObjectUpdates sends source to updates for every update to file seen by watch, failed receives an error
+// once the watcher stopped
+func forwardObjectUpdates(ctx context.Context, watch jetstream.ObjectWatcher, file string, source string, updates chan<- string, failed chan<- error) {
+	defer watch.Stop()
+
+	for {
+		select {
+		case nfo, ok := <-watch.Updates():
+			if !ok {
+				failed <- fmt.Errorf("watcher for %s stopped", source)
+				return
+			}
+
+			if nfo == nil || nfo.Name != file {
+				continue
+			}
+
+			sendUpdate(ctx, updates, source)
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func sendUpdate(ctx context.Context, updates chan<- string, source string) {
+	select {
+	case updates <- source:
+	case <-ctx.Done():
+	}
+}
+
+// debounceUpdates calls cb once no update arrived on updates for debounce, blocks until ctx is canceled or a
+// watcher reported on failed that it stopped
+func (m *CCM) debounceUpdates(ctx context.Context, updates <-chan string, failed <-chan error, debounce time.Duration, cb func()) error {
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	pending := false
+
+	for {
+		select {
+		case source := <-updates:
+			m.log.Info("Source updated", "source", source)
+			pending = true
+			timer.Reset(debounce)
+
+		case <-timer.C:
+			if !pending {
+				continue
+			}
+			pending = false
+
+			cb()
+
+		case err := <-failed:
+			// updates waiting for the debounce would be lost as watchers started again only see later updates
+			if pending {
+				cb()
+			}
+
+			return err
+
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
@@ -6,6 +6,7 @@ package model
 
 import (
 	"errors"
+	"fmt"
 )
 
 var (
@@ -13,13 +14,102 @@ var (
 	ErrResourceNameRequired    = errors.New("name is required")
 	ErrResourceEnsureRequired  = errors.New("ensure is required")
 	ErrInvalidRequires         = errors.New("invalid require properties")
+	ErrInvalidConflicts        = errors.New("invalid conflicts properties")
+	ErrInvalidBefore           = errors.New("invalid before properties")
+	ErrInvalidNotify           = errors.New("invalid notify properties")
 	ErrProviderNotFound        = errors.New("provider not found")
 	ErrProviderNotManageable   = errors.New("provider is not manageable")
 	ErrNoSuitableProvider      = errors.New("no suitable provider found")
 	ErrDuplicateProvider       = errors.New("provider already exists")
+	ErrDuplicateResourceType   = errors.New("resource type already exists")
 	ErrUnknownType             = errors.New("unknown resource type")
 	ErrDesiredStateFailed      = errors.New("failed to reach desired state")
 	ErrInvalidEnsureValue      = errors.New("invalid ensure value")
 	ErrInvalidState            = errors.New("invalid state encountered")
 	ErrNoRegistrationPublisher = errors.New("no registration publisher available")
+	ErrExecutableNotFound      = errors.New("executable not found")
+	ErrProtectedPath           = errors.New("refusing to remove protected path")
+	ErrRestartSuppressed       = errors.New("restart suppressed (flapping)")
+	ErrAclNotSupported         = errors.New("file ACLs are not supported")
+	ErrResourceConflict        = errors.New("conflicting resources")
+	ErrResourceCycle           = errors.New("resource dependency cycle")
+	ErrApplyTimeout            = errors.New("timed out")
 )
+
+// TransientError is a provider failure that might succeed when retried, for example a network error or a
+// package manager lock held by another process
+type TransientError struct {
+	Err error
+}
+
+func (e *TransientError) Error() string { return e.Err.Error() }
+func (e *TransientError) Unwrap() error { return e.Err }
+
+// PermanentError is a provider failure that will not succeed when retried without changes to the system or manifest
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string { return e.Err.Error() }
+func (e *PermanentError) Unwrap() error { return e.Err }
+
+// NotManageableError is a failure indicating the provider cannot manage the resource on this node, it matches
+// ErrProviderNotManageable using errors.Is
+type NotManageableError struct {
+	Err error
+}
+
+func (e *NotManageableError) Error() string { return e.Err.Error() }
+func (e *NotManageableError) Unwrap() error { return e.Err }
+func (e *NotManageableError) Is(target error) bool {
+	return target == ErrProviderNotManageable
+}
+
+// NewTransientError wraps err in a TransientError, nil errors are returned as nil
+func NewTransientError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &TransientError{Err: err}
+}
+
+// NewPermanentError wraps err in a PermanentError, nil errors are returned as nil
+func NewPermanentError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &PermanentError{Err: err}
+}
+
+// NewNotManageableError wraps err in a NotManageableError, nil errors are returned as nil
+func NewNotManageableError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &NotManageableError{Err: err}
+}
+
+// TransientErrorf creates a TransientError using fmt.Errorf
+func TransientErrorf(format string, a ...any) error {
+	return &TransientError{Err: fmt.Errorf(format, a...)}
+}
+
+// PermanentErrorf creates a PermanentError using fmt.Errorf
+func PermanentErrorf(format string, a ...any) error {
+	return &PermanentError{Err: fmt.Errorf(format, a...)}
+}
+
+// IsTransientError determines if err is, or wraps, a TransientError
+func IsTransientError(err error) bool {
+	var te *TransientError
+	return errors.As(err, &te)
+}
+
+// IsPermanentError determines if err is, or wraps, a PermanentError
+func IsPermanentError(err error) bool {
+	var pe *PermanentError
+	return errors.As(err, &pe)
+}
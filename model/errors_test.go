@@ -0,0 +1,88 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Provider errors", func() {
+	It("Should preserve the wrapped error text", func() {
+		Expect(NewTransientError(errors.New("lock held"))).To(MatchError("lock held"))
+		Expect(PermanentErrorf("install failed: %d", 100)).To(MatchError("install failed: 100"))
+		Expect(NewTransientError(nil)).To(BeNil())
+		Expect(NewPermanentError(nil)).To(BeNil())
+		Expect(NewNotManageableError(nil)).To(BeNil())
+	})
+
+	It("Should classify wrapped errors", func() {
+		transient := fmt.Errorf("package#zsh: %w", TransientErrorf("lock held"))
+		Expect(IsTransientError(transient)).To(BeTrue())
+		Expect(IsPermanentError(transient)).To(BeFalse())
+
+		permanent := fmt.Errorf("package#zsh: %w", PermanentErrorf("not found"))
+		Expect(IsPermanentError(permanent)).To(BeTrue())
+		Expect(IsTransientError(permanent)).To(BeFalse())
+
+		Expect(IsTransientError(errors.New("other"))).To(BeFalse())
+		Expect(IsPermanentError(errors.New("other"))).To(BeFalse())
+	})
+
+	It("Should match ErrProviderNotManageable", func() {
+		err := NewNotManageableError(errors.New("systemctl not found"))
+		Expect(err).To(MatchError(ErrProviderNotManageable))
+		Expect(err).To(MatchError("systemctl not found"))
+	})
+})
+
+var _ = Describe("CommonResourceControl", func() {
+	Describe("RetryPolicy", func() {
+		It("Should default to a single attempt", func() {
+			tries, sleep, err := (&CommonResourceControl{}).RetryPolicy()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tries).To(Equal(1))
+			Expect(sleep).To(BeZero())
+		})
+
+		It("Should parse tries and try_sleep", func() {
+			tries, sleep, err := (&CommonResourceControl{Tries: 3, TrySleep: "2s"}).RetryPolicy()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tries).To(Equal(3))
+			Expect(sleep.Seconds()).To(Equal(2.0))
+		})
+
+		It("Should be validated with the resource", func() {
+			props := CommonResourceProperties{Name: "x", Ensure: EnsurePresent, Control: &CommonResourceControl{TrySleep: "soon"}}
+			Expect(props.Validate()).To(MatchError(ContainSubstring("invalid try_sleep duration")))
+
+			props.Control = &CommonResourceControl{Tries: -1}
+			Expect(props.Validate()).To(MatchError(ContainSubstring("tries cannot be negative")))
+		})
+	})
+})
+
+var _ = Describe("ApplyTimeoutDuration", func() {
+	It("Should not time out by default", func() {
+		timeout, err := (&CommonResourceProperties{}).ApplyTimeoutDuration()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(timeout).To(BeZero())
+	})
+
+	It("Should parse apply_timeout", func() {
+		timeout, err := (&CommonResourceProperties{ApplyTimeout: "5m"}).ApplyTimeoutDuration()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(timeout.Minutes()).To(Equal(5.0))
+	})
+
+	It("Should be validated with the resource", func() {
+		props := CommonResourceProperties{Name: "x", Ensure: EnsurePresent, ApplyTimeout: "soon"}
+		Expect(props.Validate()).To(MatchError(ContainSubstring("invalid apply_timeout duration")))
+		Expect(props.Validate()).To(MatchError(ErrResourceInvalid))
+	})
+})
@@ -0,0 +1,305 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/choria-io/ccm/templates"
+)
+
+const (
+	// GraphEdgeRequire is an edge created by the require property
+	GraphEdgeRequire = "require"
+	// GraphEdgeBefore is an edge created by the before property
+	GraphEdgeBefore = "before"
+	// GraphEdgeSubscribe is an edge created by the subscribe property
+	GraphEdgeSubscribe = "subscribe"
+	// GraphEdgeConflict is an edge created by the conflicts property
+	GraphEdgeConflict = "conflict"
+)
+
+// SubscribingResourceProperties is implemented by resource properties that can subscribe to refresh events
+type SubscribingResourceProperties interface {
+	Subscriptions() []string
+	AddSubscription(ref string)
+}
+
+// ResourceGraphNode is a resource in the dependency graph
+type ResourceGraphNode struct {
+	ID       string `json:"id" yaml:"id"` // ID is the resource reference in type#name format
+	Type     string `json:"type" yaml:"type"`
+	Name     string `json:"name" yaml:"name"`
+	Alias    string `json:"alias,omitempty" yaml:"alias,omitempty"`
+	Order    int    `json:"order" yaml:"order"`                                         // Order is the position of the resource in the manifest
+	Excluded bool   `json:"excluded,omitempty" yaml:"excluded,omitempty"`               // Excluded indicates the resource will not be managed in the planned run
+	Reason   string `json:"excluded_reason,omitempty" yaml:"excluded_reason,omitempty"` // Reason explains why the resource is excluded
+}
+
+// ResourceGraphEdge is a require, before, subscribe or conflict relationship, edges point from the dependency to the
+// dependent resource and from the conflicting resource to the resource declaring the conflict
+type ResourceGraphEdge struct {
+	From    string `json:"from" yaml:"from"`
+	To      string `json:"to" yaml:"to"`
+	Type    string `json:"type" yaml:"type"`
+	Missing bool   `json:"missing,omitempty" yaml:"missing,omitempty"` // Missing indicates the referenced resource, From or To for before edges, is not in the manifest
+}
+
+// ResourceGraph is the require, before and subscribe graph of a manifest
+type ResourceGraph struct {
+	Nodes  []*ResourceGraphNode `json:"nodes" yaml:"nodes"`
+	Edges  []*ResourceGraphEdge `json:"edges" yaml:"edges"`
+	Cycles [][]string           `json:"cycles,omitempty" yaml:"cycles,omitempty"`
+}
+
+// BuildResourceGraph creates the dependency graph for resources in manifest order, resources whose control
+// expressions exclude them from the run are marked as excluded when env is not nil
+func BuildResourceGraph(resources []map[string]ResourceProperties, env *templates.Env) (*ResourceGraph, error) {
+	graph := &ResourceGraph{
+		Nodes: []*ResourceGraphNode{},
+		Edges: []*ResourceGraphEdge{},
+	}
+
+	// resources can be referenced by name or alias
+	refs := map[string]string{}
+	var props []ResourceProperties
+
+	for _, r := range resources {
+		for _, prop := range r {
+			if prop == nil {
+				continue
+			}
+
+			cp := prop.CommonProperties()
+			node := &ResourceGraphNode{
+				ID:    fmt.Sprintf("%s#%s", cp.Type, cp.Name),
+				Type:  cp.Type,
+				Name:  cp.Name,
+				Alias: cp.Alias,
+				Order: len(graph.Nodes),
+			}
+
+			if env != nil && cp.Control != nil {
+				manage, err := cp.Control.ShouldManage(env)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", node.ID, err)
+				}
+				if !manage {
+					node.Excluded = true
+					node.Reason = "control conditions not met"
+				}
+			}
+
+			refs[node.ID] = node.ID
+			if cp.Alias != "" {
+				refs[fmt.Sprintf("%s#%s", cp.Type, cp.Alias)] = node.ID
+			}
+
+			graph.Nodes = append(graph.Nodes, node)
+			props = append(props, prop)
+		}
+	}
+
+	addEdges := func(to string, deps []string, edgeType string) {
+		for _, dep := range deps {
+			edge := &ResourceGraphEdge{From: dep, To: to, Type: edgeType}
+
+			id, ok := refs[dep]
+			if ok {
+				edge.From = id
+			} else {
+				edge.Missing = true
+			}
+
+			graph.Edges = append(graph.Edges, edge)
+		}
+	}
+
+	// before is declared on the dependency so its edges point to the referenced resources
+	addBeforeEdges := func(from string, dependents []string) {
+		for _, dependent := range dependents {
+			edge := &ResourceGraphEdge{From: from, To: dependent, Type: GraphEdgeBefore}
+
+			id, ok := refs[dependent]
+			if ok {
+				edge.To = id
+			} else {
+				edge.Missing = true
+			}
+
+			graph.Edges = append(graph.Edges, edge)
+		}
+	}
+
+	for i, prop := range props {
+		id := graph.Nodes[i].ID
+
+		addEdges(id, prop.CommonProperties().Require, GraphEdgeRequire)
+		addBeforeEdges(id, prop.CommonProperties().Before)
+
+		sp, ok := prop.(SubscribingResourceProperties)
+		if ok {
+			addEdges(id, sp.Subscriptions(), GraphEdgeSubscribe)
+		}
+
+		addEdges(id, prop.CommonProperties().Conflicts, GraphEdgeConflict)
+	}
+
+	graph.Cycles = graph.findCycles()
+
+	return graph, nil
+}
+
+// findCycles reports each cycle in the graph once, as the list of resources involved
+func (g *ResourceGraph) findCycles() [][]string {
+	adjacent := map[string][]string{}
+	for _, edge := range g.Edges {
+		// conflicts do not order resources so cannot create cycles
+		if edge.Missing || edge.Type == GraphEdgeConflict {
+			continue
+		}
+		adjacent[edge.From] = append(adjacent[edge.From], edge.To)
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	var cycles [][]string
+	var stack []string
+	state := map[string]int{}
+
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = visiting
+		stack = append(stack, id)
+
+		for _, next := range adjacent[id] {
+			switch state[next] {
+			case unvisited:
+				visit(next)
+			case visiting:
+				idx := slices.Index(stack, next)
+				cycles = append(cycles, slices.Clone(stack[idx:]))
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[id] = visited
+	}
+
+	for _, node := range g.Nodes {
+		if state[node.ID] == unvisited {
+			visit(node.ID)
+		}
+	}
+
+	return cycles
+}
+
+// DOT renders the graph in Graphviz DOT format, excluded resources are dashed and missing dependencies are red
+func (g *ResourceGraph) DOT() string {
+	var sb strings.Builder
+
+	sb.WriteString("digraph resources {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box];\n")
+
+	for _, node := range g.Nodes {
+		attrs := []string{fmt.Sprintf("label=%q", node.ID)}
+		if node.Excluded {
+			attrs = append(attrs, "style=dashed", fmt.Sprintf("tooltip=%q", node.Reason))
+		}
+
+		fmt.Fprintf(&sb, "  %q [%s];\n", node.ID, strings.Join(attrs, ", "))
+	}
+
+	for _, edge := range g.Edges {
+		attrs := []string{fmt.Sprintf("label=%q", edge.Type)}
+		switch edge.Type {
+		case GraphEdgeSubscribe:
+			attrs = append(attrs, "style=dotted")
+		case GraphEdgeConflict:
+			attrs = append(attrs, "style=dashed", "dir=none")
+		}
+		if edge.Missing {
+			missing := edge.From
+			if edge.Type == GraphEdgeBefore {
+				missing = edge.To
+			}
+
+			fmt.Fprintf(&sb, "  %q [color=red, fontcolor=red];\n", missing)
+			attrs = append(attrs, "color=red")
+		}
+
+		fmt.Fprintf(&sb, "  %q -> %q [%s];\n", edge.From, edge.To, strings.Join(attrs, ", "))
+	}
+
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+// CheckResourceConflicts fails when a resource and a resource it conflicts with would both be present, resources
+// that are absent, stopped or excluded by their control expressions do not conflict
+func CheckResourceConflicts(resources []map[string]ResourceProperties, env *templates.Env) error {
+	graph, err := BuildResourceGraph(resources, env)
+	if err != nil {
+		return err
+	}
+
+	nodes := map[string]*ResourceGraphNode{}
+	props := map[string]ResourceProperties{}
+	for _, r := range resources {
+		for _, prop := range r {
+			if prop == nil {
+				continue
+			}
+
+			cp := prop.CommonProperties()
+			props[fmt.Sprintf("%s#%s", cp.Type, cp.Name)] = prop
+		}
+	}
+	for _, node := range graph.Nodes {
+		nodes[node.ID] = node
+	}
+
+	present := func(id string) (string, bool) {
+		node, ok := nodes[id]
+		if !ok || node.Excluded {
+			return "", false
+		}
+
+		ensure := props[id].CommonProperties().Ensure
+
+		return ensure, ensure != EnsureAbsent && ensure != ServiceEnsureStopped
+	}
+
+	var errs []error
+	for _, edge := range graph.Edges {
+		if edge.Type != GraphEdgeConflict || edge.Missing {
+			continue
+		}
+
+		ensure, ok := present(edge.To)
+		if !ok {
+			continue
+		}
+
+		otherEnsure, ok := present(edge.From)
+		if !ok {
+			continue
+		}
+
+		errs = append(errs, fmt.Errorf("%w: %s with ensure %s conflicts with %s with ensure %s", ErrResourceConflict, edge.To, ensure, edge.From, otherEnsure))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/choria-io/ccm/templates"
+)
+
+var _ = Describe("ResourceGraph", func() {
+	var env *templates.Env
+
+	BeforeEach(func() {
+		env = &templates.Env{
+			Facts: map[string]any{"os": "linux"},
+			Data:  map[string]any{},
+		}
+	})
+
+	common := func(typeName string, name string) CommonResourceProperties {
+		return CommonResourceProperties{Type: typeName, Name: name, Ensure: EnsurePresent}
+	}
+
+	Describe("BuildResourceGraph", func() {
+		It("Should create nodes and typed edges", func() {
+			pkg := &PackageResourceProperties{CommonResourceProperties: common(PackageTypeName, "nginx")}
+
+			file := &FileResourceProperties{CommonResourceProperties: common(FileTypeName, "/etc/nginx/nginx.conf")}
+			file.Require = []string{"package#nginx"}
+
+			svc := &ServiceResourceProperties{CommonResourceProperties: common(ServiceTypeName, "nginx")}
+			svc.Alias = "web"
+			svc.Subscribe = []string{"file#/etc/nginx/nginx.conf"}
+
+			exec := &ExecResourceProperties{CommonResourceProperties: common(ExecTypeName, "reload")}
+			exec.Subscribe = []string{"service#web", "file#/missing"}
+
+			graph, err := BuildResourceGraph([]map[string]ResourceProperties{
+				{"package": pkg},
+				{"file": file},
+				{"service": svc},
+				{"exec": exec},
+			}, env)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(graph.Nodes).To(HaveLen(4))
+			Expect(graph.Nodes[2]).To(Equal(&ResourceGraphNode{ID: "service#nginx", Type: ServiceTypeName, Name: "nginx", Alias: "web", Order: 2}))
+
+			Expect(graph.Edges).To(Equal([]*ResourceGraphEdge{
+				{From: "package#nginx", To: "file#/etc/nginx/nginx.conf", Type: GraphEdgeRequire},
+				{From: "file#/etc/nginx/nginx.conf", To: "service#nginx", Type: GraphEdgeSubscribe},
+				{From: "service#nginx", To: "exec#reload", Type: GraphEdgeSubscribe},
+				{From: "file#/missing", To: "exec#reload", Type: GraphEdgeSubscribe, Missing: true},
+			}))
+			Expect(graph.Cycles).To(BeEmpty())
+		})
+
+		It("Should mark resources excluded by control expressions", func() {
+			pkg := &PackageResourceProperties{CommonResourceProperties: common(PackageTypeName, "nginx")}
+			pkg.Control = &CommonResourceControl{ManageIf: `Facts.os == "windows"`}
+
+			graph, err := BuildResourceGraph([]map[string]ResourceProperties{{"package": pkg}}, env)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(graph.Nodes[0].Excluded).To(BeTrue())
+			Expect(graph.Nodes[0].Reason).To(Equal("control conditions not met"))
+
+			graph, err = BuildResourceGraph([]map[string]ResourceProperties{{"package": pkg}}, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(graph.Nodes[0].Excluded).To(BeFalse())
+		})
+
+		It("Should detect cycles", func() {
+			a := &FileResourceProperties{CommonResourceProperties: common(FileTypeName, "/a")}
+			a.Require = []string{"file#/b"}
+			b := &FileResourceProperties{CommonResourceProperties: common(FileTypeName, "/b")}
+			b.Require = []string{"file#/a"}
+
+			graph, err := BuildResourceGraph([]map[string]ResourceProperties{{"file": a}, {"file": b}}, env)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(graph.Cycles).To(Equal([][]string{{"file#/a", "file#/b"}}))
+		})
+
+		It("Should add before edges from the declaring resource", func() {
+			pkg := &PackageResourceProperties{CommonResourceProperties: common(PackageTypeName, "nginx")}
+			pkg.Before = []string{"service#web", "service#missing"}
+
+			svc := &ServiceResourceProperties{CommonResourceProperties: common(ServiceTypeName, "nginx")}
+			svc.Alias = "web"
+
+			graph, err := BuildResourceGraph([]map[string]ResourceProperties{{"package": pkg}, {"service": svc}}, env)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(graph.Edges).To(Equal([]*ResourceGraphEdge{
+				{From: "package#nginx", To: "service#nginx", Type: GraphEdgeBefore},
+				{From: "package#nginx", To: "service#missing", Type: GraphEdgeBefore, Missing: true},
+			}))
+			Expect(graph.Cycles).To(BeEmpty())
+			Expect(graph.DOT()).To(ContainSubstring(`"service#missing" [color=red, fontcolor=red];`))
+		})
+
+		It("Should detect cycles created by before", func() {
+			a := &FileResourceProperties{CommonResourceProperties: common(FileTypeName, "/a")}
+			a.Before = []string{"file#/b"}
+			b := &FileResourceProperties{CommonResourceProperties: common(FileTypeName, "/b")}
+			b.Before = []string{"file#/a"}
+
+			graph, err := BuildResourceGraph([]map[string]ResourceProperties{{"file": a}, {"file": b}}, env)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(graph.Cycles).To(Equal([][]string{{"file#/a", "file#/b"}}))
+		})
+
+		It("Should add conflict edges without creating cycles", func() {
+			a := &ServiceResourceProperties{CommonResourceProperties: common(ServiceTypeName, "a")}
+			a.Conflicts = []string{"service#b"}
+			b := &ServiceResourceProperties{CommonResourceProperties: common(ServiceTypeName, "b")}
+			b.Conflicts = []string{"service#a"}
+
+			graph, err := BuildResourceGraph([]map[string]ResourceProperties{{"service": a}, {"service": b}}, env)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(graph.Edges).To(Equal([]*ResourceGraphEdge{
+				{From: "service#b", To: "service#a", Type: GraphEdgeConflict},
+				{From: "service#a", To: "service#b", Type: GraphEdgeConflict},
+			}))
+			Expect(graph.Cycles).To(BeEmpty())
+		})
+	})
+
+	Describe("CheckResourceConflicts", func() {
+		var a, b *ServiceResourceProperties
+
+		BeforeEach(func() {
+			a = &ServiceResourceProperties{CommonResourceProperties: common(ServiceTypeName, "a")}
+			a.Ensure = ServiceEnsureRunning
+			a.Conflicts = []string{"service#b"}
+			b = &ServiceResourceProperties{CommonResourceProperties: common(ServiceTypeName, "b")}
+			b.Ensure = ServiceEnsureRunning
+		})
+
+		It("Should fail when conflicting resources are both present", func() {
+			err := CheckResourceConflicts([]map[string]ResourceProperties{{"service": a}, {"service": b}}, env)
+			Expect(err).To(MatchError(ErrResourceConflict))
+			Expect(err).To(MatchError("conflicting resources: service#a with ensure running conflicts with service#b with ensure running"))
+		})
+
+		It("Should match conflicts by alias", func() {
+			b.Alias = "other"
+			a.Conflicts = []string{"service#other"}
+
+			err := CheckResourceConflicts([]map[string]ResourceProperties{{"service": a}, {"service": b}}, env)
+			Expect(err).To(MatchError(ErrResourceConflict))
+		})
+
+		It("Should allow conflicting resources that are not both present", func() {
+			b.Ensure = ServiceEnsureStopped
+			Expect(CheckResourceConflicts([]map[string]ResourceProperties{{"service": a}, {"service": b}}, env)).To(Succeed())
+
+			b.Ensure = ServiceEnsureRunning
+			b.Control = &CommonResourceControl{ManageIf: `Facts.os == "windows"`}
+			Expect(CheckResourceConflicts([]map[string]ResourceProperties{{"service": a}, {"service": b}}, env)).To(Succeed())
+
+			a.Conflicts = []string{"service#missing"}
+			Expect(CheckResourceConflicts([]map[string]ResourceProperties{{"service": a}}, env)).To(Succeed())
+		})
+	})
+
+	Describe("DOT", func() {
+		It("Should render nodes and edges", func() {
+			pkg := &PackageResourceProperties{CommonResourceProperties: common(PackageTypeName, "nginx")}
+			pkg.Control = &CommonResourceControl{ManageUnless: "true"}
+			svc := &ServiceResourceProperties{CommonResourceProperties: common(ServiceTypeName, "nginx")}
+			svc.Require = []string{"package#nginx"}
+			svc.Subscribe = []string{"file#/missing"}
+
+			graph, err := BuildResourceGraph([]map[string]ResourceProperties{{"package": pkg}, {"service": svc}}, env)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(graph.DOT()).To(Equal(`digraph resources {
+  rankdir=LR;
+  node [shape=box];
+  "package#nginx" [label="package#nginx", style=dashed, tooltip="control conditions not met"];
+  "service#nginx" [label="service#nginx"];
+  "package#nginx" -> "service#nginx" [label="require"];
+  "file#/missing" [color=red, fontcolor=red];
+  "file#/missing" -> "service#nginx" [label="subscribe", style=dotted, color=red];
+}
+`))
+		})
+	})
+})
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -44,17 +45,20 @@ func (s HealthCheckStatus) String() string {
 
 // CommonHealthCheck defines the configuration for resource health checks
 type CommonHealthCheck struct {
-	Command       string            `json:"command,omitempty" yaml:"command,omitempty"`       // Command is the shell command to execute for nagios-format health checks
-	GossRules     yaml.RawMessage   `json:"goss_rules,omitempty" yaml:"goss_rules,omitempty"` // GossRules contains YAML rules for goss-format health checks
-	Name          string            `json:"name,omitempty" yaml:"name,omitempty"`             // Name is the human-readable identifier for this health check
-	Timeout       string            `json:"timeout,omitempty" yaml:"timeout,omitempty"`       // Timeout is the maximum duration to wait for health check completion (parsed into ParsedTimeout)
-	Tries         int               `json:"tries,omitempty" yaml:"tries,omitempty"`           // Tries is the number of retry attempts before marking the health check as failed
-	TrySleep      string            `json:"try_sleep,omitempty" yaml:"try_sleep,omitempty"`   // TrySleep is the duration to wait between retry attempts (parsed into ParseTrySleep)
-	Format        HealthCheckFormat `json:"format,omitempty" yaml:"format,omitempty"`         // Format specifies the health check output format (nagios or goss)
-	ParsedTimeout time.Duration     `json:"-" yaml:"-"`                                       // ParsedTimeout is the parsed duration from the Timeout field
-	ParseTrySleep time.Duration     `json:"-" yaml:"-"`                                       // ParseTrySleep is the parsed duration from the TrySleep field
-	TypeName      string            `json:"-" yaml:"-"`                                       // TypeName is the resource type this health check belongs to (e.g., "service", "package")
-	ResourceName  string            `json:"-" yaml:"-"`                                       // ResourceName is the specific resource instance this health check belongs to
+	Command       string            `json:"command,omitempty" yaml:"command,omitempty"`                 // Command is the shell command to execute for nagios-format health checks
+	GossRules     yaml.RawMessage   `json:"goss_rules,omitempty" yaml:"goss_rules,omitempty"`           // GossRules contains YAML rules for goss-format health checks
+	Name          string            `json:"name,omitempty" yaml:"name,omitempty"`                       // Name is the human-readable identifier for this health check
+	Timeout       string            `json:"timeout,omitempty" yaml:"timeout,omitempty"`                 // Timeout is the maximum duration to wait for health check completion (parsed into ParsedTimeout)
+	Tries         int               `json:"tries,omitempty" yaml:"tries,omitempty"`                     // Tries is the number of retry attempts before marking the health check as failed
+	TrySleep      string            `json:"try_sleep,omitempty" yaml:"try_sleep,omitempty"`             // TrySleep is the duration to wait between retry attempts (parsed into ParseTrySleep)
+	Format        HealthCheckFormat `json:"format,omitempty" yaml:"format,omitempty"`                   // Format specifies the health check output format (nagios or goss)
+	OkExitCodes   []int             `json:"ok_exit_codes,omitempty" yaml:"ok_exit_codes,omitempty"`     // OkExitCodes are command exit codes indicating an OK status, defaults to 0
+	WarnExitCodes []int             `json:"warn_exit_codes,omitempty" yaml:"warn_exit_codes,omitempty"` // WarnExitCodes are command exit codes indicating a WARNING status, defaults to 1
+	CritExitCodes []int             `json:"crit_exit_codes,omitempty" yaml:"crit_exit_codes,omitempty"` // CritExitCodes are command exit codes indicating a CRITICAL status, defaults to 2
+	ParsedTimeout time.Duration     `json:"-" yaml:"-"`                                                 // ParsedTimeout is the parsed duration from the Timeout field
+	ParseTrySleep time.Duration     `json:"-" yaml:"-"`                                                 // ParseTrySleep is the parsed duration from the TrySleep field
+	TypeName      string            `json:"-" yaml:"-"`                                                 // TypeName is the resource type this health check belongs to (e.g., "service", "package")
+	ResourceName  string            `json:"-" yaml:"-"`                                                 // ResourceName is the specific resource instance this health check belongs to
 }
 
 // commonHealthCheckAlias is used to prevent infinite recursion in custom unmarshallers
@@ -114,6 +118,11 @@ func (c *CommonHealthCheck) normalizeAfterUnmarshal() error {
 		return fmt.Errorf("'format' flag is required")
 	}
 
+	err := c.validateExitCodes()
+	if err != nil {
+		return err
+	}
+
 	// TODO: once builtins come make this work
 	if c.Name == "" {
 		c.Name = filepath.Base(c.Command)
@@ -122,6 +131,76 @@ func (c *CommonHealthCheck) normalizeAfterUnmarshal() error {
 	return nil
 }
 
+// HasExitCodes determines if any exit code to status mapping is configured
+func (c *CommonHealthCheck) HasExitCodes() bool {
+	return len(c.OkExitCodes) > 0 || len(c.WarnExitCodes) > 0 || len(c.CritExitCodes) > 0
+}
+
+// StatusForExitCode maps a command exit code to a status using the configured exit codes. Statuses without
+// configured codes use the Nagios convention unless their default code is configured for another status,
+// codes that are not mapped are UNKNOWN
+func (c *CommonHealthCheck) StatusForExitCode(code int) HealthCheckStatus {
+	configured := slices.Concat(c.OkExitCodes, c.WarnExitCodes, c.CritExitCodes)
+
+	codes := func(set []int, status HealthCheckStatus) []int {
+		if len(set) > 0 {
+			return set
+		}
+		if slices.Contains(configured, int(status)) {
+			return nil
+		}
+
+		return []int{int(status)}
+	}
+
+	switch {
+	case slices.Contains(codes(c.OkExitCodes, HealthCheckOK), code):
+		return HealthCheckOK
+	case slices.Contains(codes(c.WarnExitCodes, HealthCheckWarning), code):
+		return HealthCheckWarning
+	case slices.Contains(codes(c.CritExitCodes, HealthCheckCritical), code):
+		return HealthCheckCritical
+	default:
+		return HealthCheckUnknown
+	}
+}
+
+func (c *CommonHealthCheck) validateExitCodes() error {
+	if !c.HasExitCodes() {
+		return nil
+	}
+
+	if c.Format != HealthCheckNagiosFormat {
+		return fmt.Errorf("exit codes can only be set for 'nagios' format health checks")
+	}
+
+	seen := map[int]string{}
+	sets := []struct {
+		field string
+		codes []int
+	}{
+		{"ok_exit_codes", c.OkExitCodes},
+		{"warn_exit_codes", c.WarnExitCodes},
+		{"crit_exit_codes", c.CritExitCodes},
+	}
+
+	for _, set := range sets {
+		for _, code := range set.codes {
+			if code < 0 || code > 255 {
+				return fmt.Errorf("%s: exit code %d must be between 0 and 255", set.field, code)
+			}
+
+			if other, ok := seen[code]; ok && other != set.field {
+				return fmt.Errorf("exit code %d is set in both %s and %s", code, other, set.field)
+			}
+
+			seen[code] = set.field
+		}
+	}
+
+	return nil
+}
+
 // UnmarshalYAML implements yaml.BytesUnmarshaler to parse Timeout string into ParsedTimeout duration
 func (c *CommonHealthCheck) UnmarshalYAML(data []byte) error {
 	var alias commonHealthCheckAlias
@@ -191,6 +191,38 @@ name: disk_space`
 		})
 	})
 
+	Describe("Exit Codes", func() {
+		DescribeTable("maps exit codes to statuses",
+			func(input string, code int, expected HealthCheckStatus) {
+				var hc CommonHealthCheck
+				err := yaml.Unmarshal([]byte(input), &hc)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(hc.StatusForExitCode(code)).To(Equal(expected))
+			},
+			Entry("nagios ok", "command: /bin/check", 0, HealthCheckOK),
+			Entry("nagios warning", "command: /bin/check", 1, HealthCheckWarning),
+			Entry("nagios critical", "command: /bin/check", 2, HealthCheckCritical),
+			Entry("nagios unknown", "command: /bin/check", 3, HealthCheckUnknown),
+			Entry("configured warning", "command: /bin/check\nwarn_exit_codes: [1, 3]", 3, HealthCheckWarning),
+			Entry("unconfigured statuses keep defaults", "command: /bin/check\nwarn_exit_codes: [3]", 2, HealthCheckCritical),
+			Entry("replaced default is unknown", "command: /bin/check\nwarn_exit_codes: [3]", 1, HealthCheckUnknown),
+			Entry("default claimed by another status", "command: /bin/check\nok_exit_codes: [0, 1]", 1, HealthCheckOK),
+			Entry("configured critical", "command: /bin/check\ncrit_exit_codes: [2, 4]", 4, HealthCheckCritical),
+		)
+
+		DescribeTable("validates exit codes",
+			func(input string, errorText string) {
+				var hc CommonHealthCheck
+				err := yaml.Unmarshal([]byte(input), &hc)
+				Expect(err).To(MatchError(ContainSubstring(errorText)))
+			},
+			Entry("overlapping sets", "command: /bin/check\nok_exit_codes: [0, 3]\nwarn_exit_codes: [3]", "exit code 3 is set in both ok_exit_codes and warn_exit_codes"),
+			Entry("out of range", "command: /bin/check\ncrit_exit_codes: [256]", "crit_exit_codes: exit code 256 must be between 0 and 255"),
+			Entry("negative", "command: /bin/check\nok_exit_codes: [-1]", "must be between 0 and 255"),
+			Entry("goss format", "goss_rules: {}\nok_exit_codes: [0]", "only be set for 'nagios' format"),
+		)
+	})
+
 	Describe("Embedded in CommonResourceProperties", func() {
 		It("should parse health_checks timeout from JSON", func() {
 			jsonInput := `{
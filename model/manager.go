@@ -7,6 +7,8 @@ package model
 import (
 	"context"
 	"encoding/json"
+	"io"
+	"time"
 
 	"github.com/nats-io/nats.go"
 	"github.com/nats-io/nats.go/jetstream"
@@ -29,9 +31,12 @@ type Manager interface {
 	MergeFacts(ctx context.Context, facts map[string]any) (map[string]any, error)
 	SetFacts(facts map[string]any)
 	SystemFacts(ctx context.Context) (map[string]any, error)
+	GatherFacts(ctx context.Context) (map[string]any, error)
 	Data() map[string]any
 	SetData(data map[string]any) map[string]any
+	SetDataResolver(resolver DataResolver)
 	SetExternalData(data map[string]any)
+	SetDataValue(key string, value any)
 	Logger(args ...any) (Logger, error)
 	UserLogger() Logger
 	NewRunner() (CommandRunner, error)
@@ -39,7 +44,11 @@ type Manager interface {
 	PublishRegistration(ctx context.Context, entry *RegistrationEntry) error
 	RegistrationStream() string
 	ShouldRefresh(resourceType string, resourceName string) (bool, error)
+	PendingRefresh(resourceType string, resourceName string) (string, error)
+	TrustConverged(ctx context.Context, prop ResourceProperties) (bool, string, error)
+	RecordConverged(prop ResourceProperties, digest string, event *TransactionEvent) error
 	IsResourceFailed(resourceType string, resourceName string) (bool, error)
+	ResourceEvents(resourceType string, resourceName string) ([]TransactionEvent, error)
 	TemplateEnvironment(ctx context.Context) (*templates.Env, error)
 	SetWorkingDirectory(dir string)
 	WorkingDirectory() string
@@ -48,10 +57,54 @@ type Manager interface {
 	SessionSummary() (*SessionSummary, error)
 	NoopMode() bool
 	SetNoopMode(bool)
+	SkipIfUnmanageable() bool
+	RunDeadline() time.Duration
+	Concurrency() int
+	ScheduleResources(ctx context.Context, resources []map[string]ResourceProperties, apply ScheduledResourceFunc) error
+	PauseMarker() string
+	ManagementPaused(ctx context.Context) (bool, error)
+	ProtectedPaths() []string
+	DownloadCache() DownloadCache
+	ProviderConfig(provider string) map[string]any
+	ResourceGraph(ctx context.Context, apply Apply) (*ResourceGraph, error)
+	EffectiveResources(ctx context.Context, apply Apply) ([]map[string]ResourceProperties, error)
+	WriteDebugDump(ctx context.Context, apply Apply, runErr error) (string, error)
+	DeliverSessionReport(ctx context.Context, apply Apply) error
+	RegisterService(ctx context.Context, entry *RegistrationEntry) error
+	RenewService(ctx context.Context, entry *RegistrationEntry) error
+	RenewServices(ctx context.Context) error
+	DeregisterService(ctx context.Context, entry *RegistrationEntry) error
+	Watch(ctx context.Context, sources []string, debounce time.Duration, cb func()) error
 	JetStream() (jetstream.JetStream, error)
+	JetStreamCall(ctx context.Context, cb func(ctx context.Context, js jetstream.JetStream) error) error
 	NatsConnection() (*nats.Conn, error)
 }
 
+// ScheduledResourceFunc applies a single resource scheduled by the manager, returning stop prevents any further
+// resources from being started while resources already running complete
+type ScheduledResourceFunc func(ctx context.Context, prop ResourceProperties) (stop bool, err error)
+
+// DataResolver resolves top level data keys on demand
+type DataResolver interface {
+	// Lookup resolves a single top level key, reporting if the key is set
+	Lookup(key string) (any, bool, error)
+	// Keys returns all top level keys the resolver can resolve
+	Keys() []string
+}
+
+// DownloadCache is a content addressed cache of downloaded artifacts keyed by url and sha256 checksum
+type DownloadCache interface {
+	// Open opens a verified cache entry, reports false on a cache miss
+	Open(url string, checksum string) (io.ReadCloser, bool, error)
+	// Store adds file to the cache, file must match checksum
+	Store(url string, checksum string, file string) error
+}
+
 type NatsConnProvider interface {
 	Connect(natsContext string, opts ...nats.Option) (*nats.Conn, error)
 }
+
+// EventPublisher publishes messages to NATS subjects, satisfied by *nats.Conn
+type EventPublisher interface {
+	Publish(subject string, data []byte) error
+}
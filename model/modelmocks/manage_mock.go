@@ -12,7 +12,9 @@ package modelmocks
 import (
 	context "context"
 	json "encoding/json"
+	io "io"
 	reflect "reflect"
+	time "time"
 
 	model "github.com/choria-io/ccm/model"
 	templates "github.com/choria-io/ccm/templates"
@@ -171,6 +173,20 @@ func (mr *MockManagerMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockManager)(nil).Close))
 }
 
+// Concurrency mocks base method.
+func (m *MockManager) Concurrency() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Concurrency")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// Concurrency indicates an expected call of Concurrency.
+func (mr *MockManagerMockRecorder) Concurrency() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Concurrency", reflect.TypeOf((*MockManager)(nil).Concurrency))
+}
+
 // Data mocks base method.
 func (m *MockManager) Data() map[string]any {
 	m.ctrl.T.Helper()
@@ -185,6 +201,63 @@ func (mr *MockManagerMockRecorder) Data() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Data", reflect.TypeOf((*MockManager)(nil).Data))
 }
 
+// DeliverSessionReport mocks base method.
+func (m *MockManager) DeliverSessionReport(ctx context.Context, apply model.Apply) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeliverSessionReport", ctx, apply)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeliverSessionReport indicates an expected call of DeliverSessionReport.
+func (mr *MockManagerMockRecorder) DeliverSessionReport(ctx, apply any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeliverSessionReport", reflect.TypeOf((*MockManager)(nil).DeliverSessionReport), ctx, apply)
+}
+
+// DeregisterService mocks base method.
+func (m *MockManager) DeregisterService(ctx context.Context, entry *model.RegistrationEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeregisterService", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeregisterService indicates an expected call of DeregisterService.
+func (mr *MockManagerMockRecorder) DeregisterService(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeregisterService", reflect.TypeOf((*MockManager)(nil).DeregisterService), ctx, entry)
+}
+
+// DownloadCache mocks base method.
+func (m *MockManager) DownloadCache() model.DownloadCache {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadCache")
+	ret0, _ := ret[0].(model.DownloadCache)
+	return ret0
+}
+
+// DownloadCache indicates an expected call of DownloadCache.
+func (mr *MockManagerMockRecorder) DownloadCache() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadCache", reflect.TypeOf((*MockManager)(nil).DownloadCache))
+}
+
+// EffectiveResources mocks base method.
+func (m *MockManager) EffectiveResources(ctx context.Context, apply model.Apply) ([]map[string]model.ResourceProperties, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EffectiveResources", ctx, apply)
+	ret0, _ := ret[0].([]map[string]model.ResourceProperties)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EffectiveResources indicates an expected call of EffectiveResources.
+func (mr *MockManagerMockRecorder) EffectiveResources(ctx, apply any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EffectiveResources", reflect.TypeOf((*MockManager)(nil).EffectiveResources), ctx, apply)
+}
+
 // Facts mocks base method.
 func (m *MockManager) Facts(ctx context.Context) (map[string]any, error) {
 	m.ctrl.T.Helper()
@@ -215,6 +288,21 @@ func (mr *MockManagerMockRecorder) FactsRaw(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FactsRaw", reflect.TypeOf((*MockManager)(nil).FactsRaw), ctx)
 }
 
+// GatherFacts mocks base method.
+func (m *MockManager) GatherFacts(ctx context.Context) (map[string]any, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GatherFacts", ctx)
+	ret0, _ := ret[0].(map[string]any)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GatherFacts indicates an expected call of GatherFacts.
+func (mr *MockManagerMockRecorder) GatherFacts(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GatherFacts", reflect.TypeOf((*MockManager)(nil).GatherFacts), ctx)
+}
+
 // IsResourceFailed mocks base method.
 func (m *MockManager) IsResourceFailed(resourceType, resourceName string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -245,6 +333,20 @@ func (mr *MockManagerMockRecorder) JetStream() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JetStream", reflect.TypeOf((*MockManager)(nil).JetStream))
 }
 
+// JetStreamCall mocks base method.
+func (m *MockManager) JetStreamCall(ctx context.Context, cb func(context.Context, jetstream.JetStream) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JetStreamCall", ctx, cb)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// JetStreamCall indicates an expected call of JetStreamCall.
+func (mr *MockManagerMockRecorder) JetStreamCall(ctx, cb any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JetStreamCall", reflect.TypeOf((*MockManager)(nil).JetStreamCall), ctx, cb)
+}
+
 // Logger mocks base method.
 func (m *MockManager) Logger(args ...any) (model.Logger, error) {
 	m.ctrl.T.Helper()
@@ -264,6 +366,21 @@ func (mr *MockManagerMockRecorder) Logger(args ...any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logger", reflect.TypeOf((*MockManager)(nil).Logger), args...)
 }
 
+// ManagementPaused mocks base method.
+func (m *MockManager) ManagementPaused(ctx context.Context) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ManagementPaused", ctx)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ManagementPaused indicates an expected call of ManagementPaused.
+func (mr *MockManagerMockRecorder) ManagementPaused(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ManagementPaused", reflect.TypeOf((*MockManager)(nil).ManagementPaused), ctx)
+}
+
 // MergeFacts mocks base method.
 func (m *MockManager) MergeFacts(ctx context.Context, facts map[string]any) (map[string]any, error) {
 	m.ctrl.T.Helper()
@@ -323,6 +440,63 @@ func (mr *MockManagerMockRecorder) NoopMode() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NoopMode", reflect.TypeOf((*MockManager)(nil).NoopMode))
 }
 
+// PauseMarker mocks base method.
+func (m *MockManager) PauseMarker() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PauseMarker")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// PauseMarker indicates an expected call of PauseMarker.
+func (mr *MockManagerMockRecorder) PauseMarker() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseMarker", reflect.TypeOf((*MockManager)(nil).PauseMarker))
+}
+
+// PendingRefresh mocks base method.
+func (m *MockManager) PendingRefresh(resourceType, resourceName string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PendingRefresh", resourceType, resourceName)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PendingRefresh indicates an expected call of PendingRefresh.
+func (mr *MockManagerMockRecorder) PendingRefresh(resourceType, resourceName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PendingRefresh", reflect.TypeOf((*MockManager)(nil).PendingRefresh), resourceType, resourceName)
+}
+
+// ProtectedPaths mocks base method.
+func (m *MockManager) ProtectedPaths() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProtectedPaths")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// ProtectedPaths indicates an expected call of ProtectedPaths.
+func (mr *MockManagerMockRecorder) ProtectedPaths() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProtectedPaths", reflect.TypeOf((*MockManager)(nil).ProtectedPaths))
+}
+
+// ProviderConfig mocks base method.
+func (m *MockManager) ProviderConfig(provider string) map[string]any {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProviderConfig", provider)
+	ret0, _ := ret[0].(map[string]any)
+	return ret0
+}
+
+// ProviderConfig indicates an expected call of ProviderConfig.
+func (mr *MockManagerMockRecorder) ProviderConfig(provider any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProviderConfig", reflect.TypeOf((*MockManager)(nil).ProviderConfig), provider)
+}
+
 // PublishRegistration mocks base method.
 func (m *MockManager) PublishRegistration(ctx context.Context, entry *model.RegistrationEntry) error {
 	m.ctrl.T.Helper()
@@ -337,6 +511,20 @@ func (mr *MockManagerMockRecorder) PublishRegistration(ctx, entry any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishRegistration", reflect.TypeOf((*MockManager)(nil).PublishRegistration), ctx, entry)
 }
 
+// RecordConverged mocks base method.
+func (m *MockManager) RecordConverged(prop model.ResourceProperties, digest string, event *model.TransactionEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordConverged", prop, digest, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordConverged indicates an expected call of RecordConverged.
+func (mr *MockManagerMockRecorder) RecordConverged(prop, digest, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordConverged", reflect.TypeOf((*MockManager)(nil).RecordConverged), prop, digest, event)
+}
+
 // RecordEvent mocks base method.
 func (m *MockManager) RecordEvent(event *model.TransactionEvent) error {
 	m.ctrl.T.Helper()
@@ -351,6 +539,20 @@ func (mr *MockManagerMockRecorder) RecordEvent(event any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordEvent", reflect.TypeOf((*MockManager)(nil).RecordEvent), event)
 }
 
+// RegisterService mocks base method.
+func (m *MockManager) RegisterService(ctx context.Context, entry *model.RegistrationEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterService", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterService indicates an expected call of RegisterService.
+func (mr *MockManagerMockRecorder) RegisterService(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterService", reflect.TypeOf((*MockManager)(nil).RegisterService), ctx, entry)
+}
+
 // RegistrationStream mocks base method.
 func (m *MockManager) RegistrationStream() string {
 	m.ctrl.T.Helper()
@@ -365,6 +567,64 @@ func (mr *MockManagerMockRecorder) RegistrationStream() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegistrationStream", reflect.TypeOf((*MockManager)(nil).RegistrationStream))
 }
 
+// RenewService mocks base method.
+func (m *MockManager) RenewService(ctx context.Context, entry *model.RegistrationEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenewService", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenewService indicates an expected call of RenewService.
+func (mr *MockManagerMockRecorder) RenewService(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewService", reflect.TypeOf((*MockManager)(nil).RenewService), ctx, entry)
+}
+
+// RenewServices mocks base method.
+func (m *MockManager) RenewServices(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenewServices", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenewServices indicates an expected call of RenewServices.
+func (mr *MockManagerMockRecorder) RenewServices(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewServices", reflect.TypeOf((*MockManager)(nil).RenewServices), ctx)
+}
+
+// ResourceEvents mocks base method.
+func (m *MockManager) ResourceEvents(resourceType, resourceName string) ([]model.TransactionEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResourceEvents", resourceType, resourceName)
+	ret0, _ := ret[0].([]model.TransactionEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResourceEvents indicates an expected call of ResourceEvents.
+func (mr *MockManagerMockRecorder) ResourceEvents(resourceType, resourceName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceEvents", reflect.TypeOf((*MockManager)(nil).ResourceEvents), resourceType, resourceName)
+}
+
+// ResourceGraph mocks base method.
+func (m *MockManager) ResourceGraph(ctx context.Context, apply model.Apply) (*model.ResourceGraph, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResourceGraph", ctx, apply)
+	ret0, _ := ret[0].(*model.ResourceGraph)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResourceGraph indicates an expected call of ResourceGraph.
+func (mr *MockManagerMockRecorder) ResourceGraph(ctx, apply any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGraph", reflect.TypeOf((*MockManager)(nil).ResourceGraph), ctx, apply)
+}
+
 // ResourceInfo mocks base method.
 func (m *MockManager) ResourceInfo(ctx context.Context, typeName, name string) (any, error) {
 	m.ctrl.T.Helper()
@@ -380,6 +640,34 @@ func (mr *MockManagerMockRecorder) ResourceInfo(ctx, typeName, name any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceInfo", reflect.TypeOf((*MockManager)(nil).ResourceInfo), ctx, typeName, name)
 }
 
+// RunDeadline mocks base method.
+func (m *MockManager) RunDeadline() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunDeadline")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// RunDeadline indicates an expected call of RunDeadline.
+func (mr *MockManagerMockRecorder) RunDeadline() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunDeadline", reflect.TypeOf((*MockManager)(nil).RunDeadline))
+}
+
+// ScheduleResources mocks base method.
+func (m *MockManager) ScheduleResources(ctx context.Context, resources []map[string]model.ResourceProperties, apply model.ScheduledResourceFunc) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduleResources", ctx, resources, apply)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ScheduleResources indicates an expected call of ScheduleResources.
+func (mr *MockManagerMockRecorder) ScheduleResources(ctx, resources, apply any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleResources", reflect.TypeOf((*MockManager)(nil).ScheduleResources), ctx, resources, apply)
+}
+
 // SessionSummary mocks base method.
 func (m *MockManager) SessionSummary() (*model.SessionSummary, error) {
 	m.ctrl.T.Helper()
@@ -409,6 +697,30 @@ func (mr *MockManagerMockRecorder) SetData(data any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetData", reflect.TypeOf((*MockManager)(nil).SetData), data)
 }
 
+// SetDataResolver mocks base method.
+func (m *MockManager) SetDataResolver(resolver model.DataResolver) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetDataResolver", resolver)
+}
+
+// SetDataResolver indicates an expected call of SetDataResolver.
+func (mr *MockManagerMockRecorder) SetDataResolver(resolver any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDataResolver", reflect.TypeOf((*MockManager)(nil).SetDataResolver), resolver)
+}
+
+// SetDataValue mocks base method.
+func (m *MockManager) SetDataValue(key string, value any) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetDataValue", key, value)
+}
+
+// SetDataValue indicates an expected call of SetDataValue.
+func (mr *MockManagerMockRecorder) SetDataValue(key, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDataValue", reflect.TypeOf((*MockManager)(nil).SetDataValue), key, value)
+}
+
 // SetExternalData mocks base method.
 func (m *MockManager) SetExternalData(data map[string]any) {
 	m.ctrl.T.Helper()
@@ -472,6 +784,20 @@ func (mr *MockManagerMockRecorder) ShouldRefresh(resourceType, resourceName any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShouldRefresh", reflect.TypeOf((*MockManager)(nil).ShouldRefresh), resourceType, resourceName)
 }
 
+// SkipIfUnmanageable mocks base method.
+func (m *MockManager) SkipIfUnmanageable() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SkipIfUnmanageable")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// SkipIfUnmanageable indicates an expected call of SkipIfUnmanageable.
+func (mr *MockManagerMockRecorder) SkipIfUnmanageable() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SkipIfUnmanageable", reflect.TypeOf((*MockManager)(nil).SkipIfUnmanageable))
+}
+
 // StartSession mocks base method.
 func (m *MockManager) StartSession(arg0 model.Apply) (model.SessionStore, error) {
 	m.ctrl.T.Helper()
@@ -517,6 +843,22 @@ func (mr *MockManagerMockRecorder) TemplateEnvironment(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TemplateEnvironment", reflect.TypeOf((*MockManager)(nil).TemplateEnvironment), ctx)
 }
 
+// TrustConverged mocks base method.
+func (m *MockManager) TrustConverged(ctx context.Context, prop model.ResourceProperties) (bool, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrustConverged", ctx, prop)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// TrustConverged indicates an expected call of TrustConverged.
+func (mr *MockManagerMockRecorder) TrustConverged(ctx, prop any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrustConverged", reflect.TypeOf((*MockManager)(nil).TrustConverged), ctx, prop)
+}
+
 // UserLogger mocks base method.
 func (m *MockManager) UserLogger() model.Logger {
 	m.ctrl.T.Helper()
@@ -531,6 +873,20 @@ func (mr *MockManagerMockRecorder) UserLogger() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserLogger", reflect.TypeOf((*MockManager)(nil).UserLogger))
 }
 
+// Watch mocks base method.
+func (m *MockManager) Watch(ctx context.Context, sources []string, debounce time.Duration, cb func()) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Watch", ctx, sources, debounce, cb)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Watch indicates an expected call of Watch.
+func (mr *MockManagerMockRecorder) Watch(ctx, sources, debounce, cb any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockManager)(nil).Watch), ctx, sources, debounce, cb)
+}
+
 // WorkingDirectory mocks base method.
 func (m *MockManager) WorkingDirectory() string {
 	m.ctrl.T.Helper()
@@ -545,6 +901,129 @@ func (mr *MockManagerMockRecorder) WorkingDirectory() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkingDirectory", reflect.TypeOf((*MockManager)(nil).WorkingDirectory))
 }
 
+// WriteDebugDump mocks base method.
+func (m *MockManager) WriteDebugDump(ctx context.Context, apply model.Apply, runErr error) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteDebugDump", ctx, apply, runErr)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WriteDebugDump indicates an expected call of WriteDebugDump.
+func (mr *MockManagerMockRecorder) WriteDebugDump(ctx, apply, runErr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteDebugDump", reflect.TypeOf((*MockManager)(nil).WriteDebugDump), ctx, apply, runErr)
+}
+
+// MockDataResolver is a mock of DataResolver interface.
+type MockDataResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockDataResolverMockRecorder
+	isgomock struct{}
+}
+
+// MockDataResolverMockRecorder is the mock recorder for MockDataResolver.
+type MockDataResolverMockRecorder struct {
+	mock *MockDataResolver
+}
+
+// NewMockDataResolver creates a new mock instance.
+func NewMockDataResolver(ctrl *gomock.Controller) *MockDataResolver {
+	mock := &MockDataResolver{ctrl: ctrl}
+	mock.recorder = &MockDataResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDataResolver) EXPECT() *MockDataResolverMockRecorder {
+	return m.recorder
+}
+
+// Keys mocks base method.
+func (m *MockDataResolver) Keys() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Keys")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Keys indicates an expected call of Keys.
+func (mr *MockDataResolverMockRecorder) Keys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Keys", reflect.TypeOf((*MockDataResolver)(nil).Keys))
+}
+
+// Lookup mocks base method.
+func (m *MockDataResolver) Lookup(key string) (any, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lookup", key)
+	ret0, _ := ret[0].(any)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Lookup indicates an expected call of Lookup.
+func (mr *MockDataResolverMockRecorder) Lookup(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lookup", reflect.TypeOf((*MockDataResolver)(nil).Lookup), key)
+}
+
+// MockDownloadCache is a mock of DownloadCache interface.
+type MockDownloadCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockDownloadCacheMockRecorder
+	isgomock struct{}
+}
+
+// MockDownloadCacheMockRecorder is the mock recorder for MockDownloadCache.
+type MockDownloadCacheMockRecorder struct {
+	mock *MockDownloadCache
+}
+
+// NewMockDownloadCache creates a new mock instance.
+func NewMockDownloadCache(ctrl *gomock.Controller) *MockDownloadCache {
+	mock := &MockDownloadCache{ctrl: ctrl}
+	mock.recorder = &MockDownloadCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDownloadCache) EXPECT() *MockDownloadCacheMockRecorder {
+	return m.recorder
+}
+
+// Open mocks base method.
+func (m *MockDownloadCache) Open(url, checksum string) (io.ReadCloser, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", url, checksum)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockDownloadCacheMockRecorder) Open(url, checksum any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockDownloadCache)(nil).Open), url, checksum)
+}
+
+// Store mocks base method.
+func (m *MockDownloadCache) Store(url, checksum, file string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Store", url, checksum, file)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Store indicates an expected call of Store.
+func (mr *MockDownloadCacheMockRecorder) Store(url, checksum, file any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Store", reflect.TypeOf((*MockDownloadCache)(nil).Store), url, checksum, file)
+}
+
 // MockNatsConnProvider is a mock of NatsConnProvider interface.
 type MockNatsConnProvider struct {
 	ctrl     *gomock.Controller
@@ -588,3 +1067,41 @@ func (mr *MockNatsConnProviderMockRecorder) Connect(natsContext any, opts ...any
 	varargs := append([]any{natsContext}, opts...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Connect", reflect.TypeOf((*MockNatsConnProvider)(nil).Connect), varargs...)
 }
+
+// MockEventPublisher is a mock of EventPublisher interface.
+type MockEventPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventPublisherMockRecorder
+	isgomock struct{}
+}
+
+// MockEventPublisherMockRecorder is the mock recorder for MockEventPublisher.
+type MockEventPublisherMockRecorder struct {
+	mock *MockEventPublisher
+}
+
+// NewMockEventPublisher creates a new mock instance.
+func NewMockEventPublisher(ctrl *gomock.Controller) *MockEventPublisher {
+	mock := &MockEventPublisher{ctrl: ctrl}
+	mock.recorder = &MockEventPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventPublisher) EXPECT() *MockEventPublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockEventPublisher) Publish(subject string, data []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", subject, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockEventPublisherMockRecorder) Publish(subject, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockEventPublisher)(nil).Publish), subject, data)
+}
@@ -5,8 +5,13 @@
 package modelmocks
 
 import (
+	"context"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
 	"go.uber.org/mock/gomock"
 
+	"github.com/choria-io/ccm/model"
 	"github.com/choria-io/ccm/templates"
 )
 
@@ -18,6 +23,20 @@ func NewManager(facts map[string]any, data map[string]any, noop bool, ctl *gomoc
 
 	mgr.EXPECT().NoopMode().DoAndReturn(func() bool { return noop }).AnyTimes()
 	mgr.EXPECT().SetNoopMode(gomock.Any()).DoAndReturn(func(n bool) { noop = n }).AnyTimes()
+	mgr.EXPECT().SkipIfUnmanageable().Return(false).AnyTimes()
+	mgr.EXPECT().RunDeadline().Return(time.Duration(0)).AnyTimes()
+	mgr.EXPECT().Concurrency().Return(1).AnyTimes()
+	mgr.EXPECT().ScheduleResources(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(ScheduleSequentially).AnyTimes()
+	mgr.EXPECT().PauseMarker().Return("").AnyTimes()
+	mgr.EXPECT().ManagementPaused(gomock.Any()).Return(false, nil).AnyTimes()
+	mgr.EXPECT().TrustConverged(gomock.Any(), gomock.Any()).Return(false, "", nil).AnyTimes()
+	mgr.EXPECT().RecordConverged(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mgr.EXPECT().WriteDebugDump(gomock.Any(), gomock.Any(), gomock.Any()).Return("", nil).AnyTimes()
+	mgr.EXPECT().DeliverSessionReport(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mgr.EXPECT().ProtectedPaths().Return(model.DefaultProtectedPaths).AnyTimes()
+	mgr.EXPECT().DownloadCache().Return(nil).AnyTimes()
+	mgr.EXPECT().ProviderConfig(gomock.Any()).Return(nil).AnyTimes()
+	mgr.EXPECT().PendingRefresh(gomock.Any(), gomock.Any()).Return("", nil).AnyTimes()
 	mgr.EXPECT().Logger(gomock.Any()).AnyTimes().Return(logger, nil)
 	mgr.EXPECT().UserLogger().AnyTimes().Return(logger)
 	mgr.EXPECT().Facts(gomock.Any()).AnyTimes().Return(facts, nil)
@@ -33,3 +52,28 @@ func NewManager(facts map[string]any, data map[string]any, noop bool, ctl *gomoc
 
 	return mgr, logger
 }
+
+// ScheduleSequentially is for use with DoAndReturn on ScheduleResources, it applies resources one by one in manifest order
+func ScheduleSequentially(ctx context.Context, resources []map[string]model.ResourceProperties, apply model.ScheduledResourceFunc) error {
+	for _, r := range resources {
+		for _, prop := range r {
+			if prop == nil {
+				continue
+			}
+
+			stop, err := apply(ctx, prop)
+			if err != nil || stop {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// JetStreamCallWith returns a function for use with DoAndReturn on JetStreamCall that invokes the callback with js
+func JetStreamCallWith(js jetstream.JetStream) func(ctx context.Context, cb func(ctx context.Context, js jetstream.JetStream) error) error {
+	return func(ctx context.Context, cb func(ctx context.Context, js jetstream.JetStream) error) error {
+		return cb(ctx, js)
+	}
+}
@@ -138,3 +138,80 @@ func (mr *MockProviderFactoryMockRecorder) TypeName() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TypeName", reflect.TypeOf((*MockProviderFactory)(nil).TypeName))
 }
+
+// MockConfigurableProviderFactory is a mock of ConfigurableProviderFactory interface.
+type MockConfigurableProviderFactory struct {
+	ctrl     *gomock.Controller
+	recorder *MockConfigurableProviderFactoryMockRecorder
+	isgomock struct{}
+}
+
+// MockConfigurableProviderFactoryMockRecorder is the mock recorder for MockConfigurableProviderFactory.
+type MockConfigurableProviderFactoryMockRecorder struct {
+	mock *MockConfigurableProviderFactory
+}
+
+// NewMockConfigurableProviderFactory creates a new mock instance.
+func NewMockConfigurableProviderFactory(ctrl *gomock.Controller) *MockConfigurableProviderFactory {
+	mock := &MockConfigurableProviderFactory{ctrl: ctrl}
+	mock.recorder = &MockConfigurableProviderFactoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConfigurableProviderFactory) EXPECT() *MockConfigurableProviderFactoryMockRecorder {
+	return m.recorder
+}
+
+// NewWithConfig mocks base method.
+func (m *MockConfigurableProviderFactory) NewWithConfig(log model.Logger, runner model.CommandRunner, config map[string]any) (model.Provider, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewWithConfig", log, runner, config)
+	ret0, _ := ret[0].(model.Provider)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewWithConfig indicates an expected call of NewWithConfig.
+func (mr *MockConfigurableProviderFactoryMockRecorder) NewWithConfig(log, runner, config any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewWithConfig", reflect.TypeOf((*MockConfigurableProviderFactory)(nil).NewWithConfig), log, runner, config)
+}
+
+// MockProviderConfigSource is a mock of ProviderConfigSource interface.
+type MockProviderConfigSource struct {
+	ctrl     *gomock.Controller
+	recorder *MockProviderConfigSourceMockRecorder
+	isgomock struct{}
+}
+
+// MockProviderConfigSourceMockRecorder is the mock recorder for MockProviderConfigSource.
+type MockProviderConfigSourceMockRecorder struct {
+	mock *MockProviderConfigSource
+}
+
+// NewMockProviderConfigSource creates a new mock instance.
+func NewMockProviderConfigSource(ctrl *gomock.Controller) *MockProviderConfigSource {
+	mock := &MockProviderConfigSource{ctrl: ctrl}
+	mock.recorder = &MockProviderConfigSourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProviderConfigSource) EXPECT() *MockProviderConfigSourceMockRecorder {
+	return m.recorder
+}
+
+// ProviderConfig mocks base method.
+func (m *MockProviderConfigSource) ProviderConfig(provider string) map[string]any {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProviderConfig", provider)
+	ret0, _ := ret[0].(map[string]any)
+	return ret0
+}
+
+// ProviderConfig indicates an expected call of ProviderConfig.
+func (mr *MockProviderConfigSourceMockRecorder) ProviderConfig(provider any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProviderConfig", reflect.TypeOf((*MockProviderConfigSource)(nil).ProviderConfig), provider)
+}
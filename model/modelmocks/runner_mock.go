@@ -81,3 +81,82 @@ func (mr *MockCommandRunnerMockRecorder) ExecuteWithOptions(ctx, opts any) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteWithOptions", reflect.TypeOf((*MockCommandRunner)(nil).ExecuteWithOptions), ctx, opts)
 }
+
+// MockRedactingCommandRunner is a mock of RedactingCommandRunner interface.
+type MockRedactingCommandRunner struct {
+	ctrl     *gomock.Controller
+	recorder *MockRedactingCommandRunnerMockRecorder
+	isgomock struct{}
+}
+
+// MockRedactingCommandRunnerMockRecorder is the mock recorder for MockRedactingCommandRunner.
+type MockRedactingCommandRunnerMockRecorder struct {
+	mock *MockRedactingCommandRunner
+}
+
+// NewMockRedactingCommandRunner creates a new mock instance.
+func NewMockRedactingCommandRunner(ctrl *gomock.Controller) *MockRedactingCommandRunner {
+	mock := &MockRedactingCommandRunner{ctrl: ctrl}
+	mock.recorder = &MockRedactingCommandRunnerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRedactingCommandRunner) EXPECT() *MockRedactingCommandRunnerMockRecorder {
+	return m.recorder
+}
+
+// Execute mocks base method.
+func (m *MockRedactingCommandRunner) Execute(ctx context.Context, cmd string, args ...string) ([]byte, []byte, int, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, cmd}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Execute", varargs...)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].([]byte)
+	ret2, _ := ret[2].(int)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockRedactingCommandRunnerMockRecorder) Execute(ctx, cmd any, args ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, cmd}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockRedactingCommandRunner)(nil).Execute), varargs...)
+}
+
+// ExecuteWithOptions mocks base method.
+func (m *MockRedactingCommandRunner) ExecuteWithOptions(ctx context.Context, opts model.ExtendedExecOptions) ([]byte, []byte, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteWithOptions", ctx, opts)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].([]byte)
+	ret2, _ := ret[2].(int)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// ExecuteWithOptions indicates an expected call of ExecuteWithOptions.
+func (mr *MockRedactingCommandRunnerMockRecorder) ExecuteWithOptions(ctx, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteWithOptions", reflect.TypeOf((*MockRedactingCommandRunner)(nil).ExecuteWithOptions), ctx, opts)
+}
+
+// Redact mocks base method.
+func (m *MockRedactingCommandRunner) Redact(values ...string) {
+	m.ctrl.T.Helper()
+	varargs := []any{}
+	for _, a := range values {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Redact", varargs...)
+}
+
+// Redact indicates an expected call of Redact.
+func (mr *MockRedactingCommandRunnerMockRecorder) Redact(values ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redact", reflect.TypeOf((*MockRedactingCommandRunner)(nil).Redact), values...)
+}
@@ -304,3 +304,70 @@ func (mr *MockSessionStoreMockRecorder) StopSession(destroy any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopSession", reflect.TypeOf((*MockSessionStore)(nil).StopSession), destroy)
 }
+
+// MockPendingRefreshStore is a mock of PendingRefreshStore interface.
+type MockPendingRefreshStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockPendingRefreshStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockPendingRefreshStoreMockRecorder is the mock recorder for MockPendingRefreshStore.
+type MockPendingRefreshStoreMockRecorder struct {
+	mock *MockPendingRefreshStore
+}
+
+// NewMockPendingRefreshStore creates a new mock instance.
+func NewMockPendingRefreshStore(ctrl *gomock.Controller) *MockPendingRefreshStore {
+	mock := &MockPendingRefreshStore{ctrl: ctrl}
+	mock.recorder = &MockPendingRefreshStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPendingRefreshStore) EXPECT() *MockPendingRefreshStoreMockRecorder {
+	return m.recorder
+}
+
+// AddPendingRefresh mocks base method.
+func (m *MockPendingRefreshStore) AddPendingRefresh(resourceId, source string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPendingRefresh", resourceId, source)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddPendingRefresh indicates an expected call of AddPendingRefresh.
+func (mr *MockPendingRefreshStoreMockRecorder) AddPendingRefresh(resourceId, source any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPendingRefresh", reflect.TypeOf((*MockPendingRefreshStore)(nil).AddPendingRefresh), resourceId, source)
+}
+
+// ClearPendingRefresh mocks base method.
+func (m *MockPendingRefreshStore) ClearPendingRefresh(resourceId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearPendingRefresh", resourceId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearPendingRefresh indicates an expected call of ClearPendingRefresh.
+func (mr *MockPendingRefreshStoreMockRecorder) ClearPendingRefresh(resourceId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearPendingRefresh", reflect.TypeOf((*MockPendingRefreshStore)(nil).ClearPendingRefresh), resourceId)
+}
+
+// PendingRefresh mocks base method.
+func (m *MockPendingRefreshStore) PendingRefresh(resourceId string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PendingRefresh", resourceId)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PendingRefresh indicates an expected call of PendingRefresh.
+func (mr *MockPendingRefreshStoreMockRecorder) PendingRefresh(resourceId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PendingRefresh", reflect.TypeOf((*MockPendingRefreshStore)(nil).PendingRefresh), resourceId)
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ResolveNotifications subscribes every resource listed in a notify property to the notifying resource, so notify
+// and subscribe share the ordering and refresh handling. A target already subscribed to the notifying resource,
+// by name or alias, is not subscribed again so a change triggers a single refresh.
+func ResolveNotifications(resources []map[string]ResourceProperties) error {
+	// resources can be referenced by name or alias
+	ids := map[string]string{}
+	props := map[string]ResourceProperties{}
+
+	for _, r := range resources {
+		for _, prop := range r {
+			if prop == nil {
+				continue
+			}
+
+			cp := prop.CommonProperties()
+			id := fmt.Sprintf("%s#%s", cp.Type, cp.Name)
+
+			ids[id] = id
+			if cp.Alias != "" {
+				ids[fmt.Sprintf("%s#%s", cp.Type, cp.Alias)] = id
+			}
+			props[id] = prop
+		}
+	}
+
+	var errs []error
+
+	for _, r := range resources {
+		for _, prop := range r {
+			if prop == nil {
+				continue
+			}
+
+			cp := prop.CommonProperties()
+			source := fmt.Sprintf("%s#%s", cp.Type, cp.Name)
+
+			for _, ref := range cp.Notify {
+				target, ok := ids[ref]
+				if !ok {
+					errs = append(errs, fmt.Errorf("%w: %s notifies %s which is not in the manifest", ErrInvalidNotify, source, ref))
+					continue
+				}
+
+				sp, ok := props[target].(SubscribingResourceProperties)
+				if !ok {
+					errs = append(errs, fmt.Errorf("%w: %s notifies %s which does not support subscribe", ErrInvalidNotify, source, ref))
+					continue
+				}
+
+				subscribed := slices.ContainsFunc(sp.Subscriptions(), func(sub string) bool {
+					return ids[sub] == source
+				})
+				if !subscribed {
+					sp.AddSubscription(source)
+				}
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ResolveNotifications", func() {
+	common := func(typeName string, name string) CommonResourceProperties {
+		return CommonResourceProperties{Type: typeName, Name: name, Ensure: EnsurePresent}
+	}
+
+	It("Should subscribe notified resources to the notifying resource", func() {
+		file := &FileResourceProperties{CommonResourceProperties: common(FileTypeName, "/etc/nginx/nginx.conf")}
+		file.Notify = []string{"service#web", "exec#reload"}
+
+		svc := &ServiceResourceProperties{CommonResourceProperties: common(ServiceTypeName, "nginx")}
+		svc.Alias = "web"
+		svc.Subscribe = []string{"package#nginx"}
+
+		exec := &ExecResourceProperties{CommonResourceProperties: common(ExecTypeName, "reload")}
+
+		err := ResolveNotifications([]map[string]ResourceProperties{{"file": file}, {"service": svc}, {"exec": exec}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(svc.Subscribe).To(Equal([]string{"package#nginx", "file#/etc/nginx/nginx.conf"}))
+		Expect(exec.Subscribe).To(Equal([]string{"file#/etc/nginx/nginx.conf"}))
+	})
+
+	It("Should not subscribe resources twice", func() {
+		pkg := &PackageResourceProperties{CommonResourceProperties: common(PackageTypeName, "nginx")}
+		pkg.Alias = "web"
+		pkg.Notify = []string{"service#nginx", "service#nginx"}
+
+		svc := &ServiceResourceProperties{CommonResourceProperties: common(ServiceTypeName, "nginx")}
+		svc.Subscribe = []string{"package#web"}
+
+		err := ResolveNotifications([]map[string]ResourceProperties{{"package": pkg}, {"service": svc}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(svc.Subscribe).To(Equal([]string{"package#web"}))
+
+		svc.Subscribe = nil
+		err = ResolveNotifications([]map[string]ResourceProperties{{"package": pkg}, {"service": svc}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(svc.Subscribe).To(Equal([]string{"package#nginx"}))
+	})
+
+	It("Should reject unknown and unsubscribable targets", func() {
+		file := &FileResourceProperties{CommonResourceProperties: common(FileTypeName, "/etc/motd")}
+		file.Notify = []string{"service#missing", "package#nginx"}
+
+		pkg := &PackageResourceProperties{CommonResourceProperties: common(PackageTypeName, "nginx")}
+
+		err := ResolveNotifications([]map[string]ResourceProperties{{"file": file}, {"package": pkg}})
+		Expect(err).To(MatchError(ErrInvalidNotify))
+		Expect(err).To(MatchError(ContainSubstring("file#/etc/motd notifies service#missing which is not in the manifest")))
+		Expect(err).To(MatchError(ContainSubstring("file#/etc/motd notifies package#nginx which does not support subscribe")))
+	})
+})
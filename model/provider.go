@@ -9,9 +9,21 @@ type Provider interface {
 	Name() string
 }
 
+// ProviderFactory creates providers for a resource type
 type ProviderFactory interface {
 	IsManageable(map[string]any, ResourceProperties) (usable bool, priority int, err error)
 	TypeName() string
 	Name() string
 	New(Logger, CommandRunner) (Provider, error)
 }
+
+// ConfigurableProviderFactory is implemented by provider factories that accept provider level configuration, when
+// configuration is set for the provider NewWithConfig is called instead of New
+type ConfigurableProviderFactory interface {
+	NewWithConfig(log Logger, runner CommandRunner, config map[string]any) (Provider, error)
+}
+
+// ProviderConfigSource supplies provider level configuration by provider name
+type ProviderConfigSource interface {
+	ProviderConfig(provider string) map[string]any
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/goccy/go-yaml"
+)
+
+// RedactedValue replaces the values of properties tagged sensitive:"true" in redacted resource properties
+const RedactedValue = "[REDACTED]"
+
+// RedactedResourceProperties returns a copy of prop with the values of all fields tagged sensitive:"true" replaced
+// by RedactedValue, maps keep their keys so it remains visible which entries are set. prop is not modified.
+func RedactedResourceProperties(prop ResourceProperties) (ResourceProperties, error) {
+	dupProp, err := CopyResourceProperties(prop)
+	if err != nil {
+		return nil, err
+	}
+
+	redactStruct(reflect.ValueOf(dupProp).Elem())
+
+	return dupProp, nil
+}
+
+// CopyResourceProperties returns a deep copy of prop, prop is not modified
+func CopyResourceProperties(prop ResourceProperties) (ResourceProperties, error) {
+	propValue := reflect.ValueOf(prop)
+	if propValue.Kind() != reflect.Ptr || propValue.IsNil() {
+		return nil, fmt.Errorf("expected non-nil pointer to resource properties, got %T", prop)
+	}
+
+	raw, err := prop.ToYamlManifest()
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal resource for copying: %w", err)
+	}
+
+	dup := reflect.New(propValue.Type().Elem()).Interface()
+	err = yaml.Unmarshal(raw, dup)
+	if err != nil {
+		return nil, fmt.Errorf("could not copy resource: %w", err)
+	}
+
+	dupProp, ok := dup.(ResourceProperties)
+	if !ok {
+		return nil, fmt.Errorf("copy of %T does not satisfy ResourceProperties", prop)
+	}
+
+	// Type is not serialized so does not survive the copy
+	dupProp.CommonProperties().Type = prop.CommonProperties().Type
+
+	return dupProp, nil
+}
+
+func redactStruct(v reflect.Value) {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fv := v.Field(i)
+
+		if !fv.CanSet() {
+			continue
+		}
+
+		if field.Anonymous && fv.Kind() == reflect.Struct {
+			redactStruct(fv)
+			continue
+		}
+
+		if field.Tag.Get("sensitive") != "true" {
+			continue
+		}
+
+		switch fv.Kind() {
+		case reflect.String:
+			if fv.String() != "" {
+				fv.SetString(RedactedValue)
+			}
+
+		case reflect.Map:
+			if fv.Type().Elem().Kind() != reflect.String {
+				fv.SetZero()
+				continue
+			}
+			for _, k := range fv.MapKeys() {
+				fv.SetMapIndex(k, reflect.ValueOf(RedactedValue).Convert(fv.Type().Elem()))
+			}
+
+		case reflect.Slice:
+			if fv.Type().Elem().Kind() != reflect.String {
+				fv.SetZero()
+				continue
+			}
+			for j := 0; j < fv.Len(); j++ {
+				fv.Index(j).SetString(RedactedValue)
+			}
+
+		default:
+			fv.SetZero()
+		}
+	}
+}
+
+// SensitiveValues returns the non empty values of all fields in prop tagged sensitive:"true", used to keep them out of
+// anything that stores what a resource did
+func SensitiveValues(prop ResourceProperties) []string {
+	v := reflect.ValueOf(prop)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+
+	return sensitiveStructValues(v.Elem())
+}
+
+func sensitiveStructValues(v reflect.Value) []string {
+	var res []string
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fv := v.Field(i)
+
+		if field.Anonymous && fv.Kind() == reflect.Struct {
+			res = append(res, sensitiveStructValues(fv)...)
+			continue
+		}
+
+		if field.Tag.Get("sensitive") != "true" {
+			continue
+		}
+
+		switch fv.Kind() {
+		case reflect.String:
+			res = append(res, fv.String())
+
+		case reflect.Map:
+			if fv.Type().Elem().Kind() != reflect.String {
+				continue
+			}
+			for _, k := range fv.MapKeys() {
+				res = append(res, fv.MapIndex(k).String())
+			}
+
+		case reflect.Slice:
+			if fv.Type().Elem().Kind() != reflect.String {
+				continue
+			}
+			for j := 0; j < fv.Len(); j++ {
+				res = append(res, fv.Index(j).String())
+			}
+		}
+	}
+
+	return slices.DeleteFunc(res, func(s string) bool { return s == "" })
+}
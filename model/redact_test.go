@@ -0,0 +1,63 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RedactedResourceProperties", func() {
+	It("Should redact sensitive fields in a copy", func() {
+		prop := &ArchiveResourceProperties{
+			CommonResourceProperties: CommonResourceProperties{Type: ArchiveTypeName, Name: "/tmp/app.tgz", Ensure: EnsurePresent},
+			Url:                      "https://example.net/app.tgz",
+			Username:                 "app",
+			Password:                 "s3cret",
+			Headers:                  map[string]string{"Authorization": "Bearer x"},
+		}
+
+		res, err := RedactedResourceProperties(prop)
+		Expect(err).ToNot(HaveOccurred())
+
+		redacted := res.(*ArchiveResourceProperties)
+		Expect(redacted.Type).To(Equal(ArchiveTypeName))
+		Expect(redacted.Name).To(Equal("/tmp/app.tgz"))
+		Expect(redacted.Url).To(Equal("https://example.net/app.tgz"))
+		Expect(redacted.Username).To(Equal("app"))
+		Expect(redacted.Password).To(Equal(RedactedValue))
+		Expect(redacted.Headers).To(Equal(map[string]string{"Authorization": RedactedValue}))
+
+		Expect(prop.Password).To(Equal("s3cret"))
+		Expect(prop.Headers).To(Equal(map[string]string{"Authorization": "Bearer x"}))
+	})
+
+	It("Should leave unset sensitive fields empty", func() {
+		prop := &ArchiveResourceProperties{
+			CommonResourceProperties: CommonResourceProperties{Type: ArchiveTypeName, Name: "/tmp/app.tgz"},
+		}
+
+		res, err := RedactedResourceProperties(prop)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.(*ArchiveResourceProperties).Password).To(BeEmpty())
+		Expect(res.(*ArchiveResourceProperties).Headers).To(BeEmpty())
+	})
+})
+
+var _ = Describe("SensitiveValues", func() {
+	It("Should return the set sensitive values", func() {
+		prop := &ArchiveResourceProperties{
+			CommonResourceProperties: CommonResourceProperties{Type: ArchiveTypeName, Name: "/tmp/app.tgz", Ensure: EnsurePresent},
+			Url:                      "https://example.net/app.tgz",
+			Username:                 "app",
+			Password:                 "s3cret",
+			Headers:                  map[string]string{"Authorization": "Bearer x", "X-Empty": ""},
+		}
+
+		Expect(SensitiveValues(prop)).To(ConsistOf("s3cret", "Bearer x"))
+		Expect(SensitiveValues(&ArchiveResourceProperties{})).To(BeEmpty())
+		Expect(SensitiveValues(nil)).To(BeNil())
+	})
+})
@@ -7,8 +7,14 @@ package model
 import (
 	"context"
 	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"sync"
 	"time"
 
+	"github.com/choria-io/fisk"
+	"github.com/expr-lang/expr"
 	"github.com/goccy/go-yaml"
 
 	iu "github.com/choria-io/ccm/internal/util"
@@ -57,14 +63,96 @@ type CommonResourceProperties struct {
 	Provider           string                 `json:"provider,omitempty" yaml:"provider,omitempty"`
 	HealthChecks       []CommonHealthCheck    `json:"health_checks,omitempty" yaml:"health_checks,omitempty"`
 	Require            []string               `json:"require,omitempty" yaml:"require,omitempty" template:"-"`
+	Before             []string               `json:"before,omitempty" yaml:"before,omitempty" template:"-"`       // Before are resources that are only applied once this resource completed, they do not require it to succeed
+	Notify             []string               `json:"notify,omitempty" yaml:"notify,omitempty" template:"-"`       // Notify are resources refreshed when this resource changed, as if they subscribed to it
+	Conflicts          []string               `json:"conflicts,omitempty" yaml:"conflicts,omitempty" template:"-"` // Conflicts are resources that must not be present at the same time as this resource
 	Control            *CommonResourceControl `json:"control,omitempty" yaml:"control,omitempty" template:"-"`
+	ApplyIf            string                 `json:"apply_if,omitempty" yaml:"apply_if,omitempty" template:"-"` // ApplyIf is an expression evaluated just before the resource is applied, the resource is skipped when it is false
 	RegisterWhenStable []*RegistrationEntry   `json:"register_when_stable,omitempty" yaml:"register_when_stable,omitempty" template:"-"`
+	ApplyTimeout       string                 `json:"apply_timeout,omitempty" yaml:"apply_timeout,omitempty"` // ApplyTimeout is the maximum time applying the resource may take including retries, a duration like 5m
 	SkipValidate       bool                   `json:"-" yaml:"-"`
 }
 
+// ApplyTimeoutDuration parses the apply_timeout setting, zero means applying the resource does not time out
+func (p *CommonResourceProperties) ApplyTimeoutDuration() (time.Duration, error) {
+	if p.ApplyTimeout == "" {
+		return 0, nil
+	}
+
+	timeout, err := fisk.ParseDuration(p.ApplyTimeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid apply_timeout duration %q: %w", p.ApplyTimeout, err)
+	}
+	if timeout < 0 {
+		return 0, fmt.Errorf("apply_timeout cannot be negative")
+	}
+
+	return timeout, nil
+}
+
 type CommonResourceControl struct {
-	ManageIf     string `json:"if,omitempty" yaml:"if,omitempty"`
-	ManageUnless string `json:"unless,omitempty" yaml:"unless,omitempty"`
+	ManageIf           string `json:"if,omitempty" yaml:"if,omitempty"`
+	ManageUnless       string `json:"unless,omitempty" yaml:"unless,omitempty"`
+	SkipIfUnmanageable bool   `json:"skip_if_unmanageable,omitempty" yaml:"skip_if_unmanageable,omitempty"` // SkipIfUnmanageable records a not applicable event rather than failing when no provider can manage the resource
+	Tries              int    `json:"tries,omitempty" yaml:"tries,omitempty"`                               // Tries is the number of attempts made to apply the resource when the provider reports a transient error
+	TrySleep           string `json:"try_sleep,omitempty" yaml:"try_sleep,omitempty"`                       // TrySleep is the duration to wait between attempts
+}
+
+// RetryPolicy parses the tries and try_sleep settings, at least one attempt is always made
+func (c *CommonResourceControl) RetryPolicy() (int, time.Duration, error) {
+	tries := max(c.Tries, 1)
+
+	if c.TrySleep == "" {
+		return tries, 0, nil
+	}
+
+	sleep, err := fisk.ParseDuration(c.TrySleep)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid try_sleep duration %q: %w", c.TrySleep, err)
+	}
+
+	return tries, sleep, nil
+}
+
+// ShouldManage evaluates the if and unless expressions to determine if the resource should be managed
+func (c *CommonResourceControl) ShouldManage(env *templates.Env) (bool, error) {
+	ifRes := true
+	unlessRes := false
+
+	if c.ManageIf != "" {
+		res, err := templates.ExprParse(c.ManageIf, env, expr.AsBool())
+		if err != nil {
+			return false, err
+		}
+
+		ifRes = res.(bool)
+	}
+
+	if c.ManageUnless != "" {
+		res, err := templates.ExprParse(c.ManageUnless, env, expr.AsBool())
+		if err != nil {
+			return false, err
+		}
+
+		unlessRes = res.(bool)
+	}
+
+	return ifRes && !unlessRes, nil
+}
+
+// ShouldApply evaluates the apply_if expression using the environment at the time the resource is applied,
+// resources without an apply_if expression are always applied
+func (p *CommonResourceProperties) ShouldApply(env *templates.Env) (bool, error) {
+	if p.ApplyIf == "" {
+		return true, nil
+	}
+
+	res, err := templates.ExprParse(p.ApplyIf, env, expr.AsBool())
+	if err != nil {
+		return false, fmt.Errorf("invalid apply_if expression: %w", err)
+	}
+
+	return res.(bool), nil
 }
 
 // ResolveTemplates resolves template expressions in common resource properties
@@ -114,6 +202,24 @@ func (p *CommonResourceProperties) Validate() error {
 		}
 	}
 
+	if len(p.Before) > 0 {
+		if !iu.IsValidResourceRef(p.Before...) {
+			return ErrInvalidBefore
+		}
+	}
+
+	if len(p.Notify) > 0 {
+		if !iu.IsValidResourceRef(p.Notify...) {
+			return ErrInvalidNotify
+		}
+	}
+
+	if len(p.Conflicts) > 0 {
+		if !iu.IsValidResourceRef(p.Conflicts...) {
+			return ErrInvalidConflicts
+		}
+	}
+
 	for _, reg := range p.RegisterWhenStable {
 		err := reg.Validate()
 		if err != nil {
@@ -121,6 +227,22 @@ func (p *CommonResourceProperties) Validate() error {
 		}
 	}
 
+	_, err := p.ApplyTimeoutDuration()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrResourceInvalid, err)
+	}
+
+	if p.Control != nil {
+		if p.Control.Tries < 0 {
+			return fmt.Errorf("%w: tries cannot be negative", ErrResourceInvalid)
+		}
+
+		_, _, err := p.Control.RetryPolicy()
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrResourceInvalid, err)
+		}
+	}
+
 	return nil
 }
 
@@ -144,49 +266,213 @@ type CommonResourceState struct {
 	Ensure       string             `json:"ensure" yaml:"ensure"`
 	Changed      bool               `json:"changed" yaml:"changed"`
 	Refreshed    bool               `json:"refreshed" yaml:"refreshed"`
+	Corrective   bool               `json:"corrective,omitempty" yaml:"corrective,omitempty"` // Corrective indicates the change repaired drift on a resource that existed before the run
 	Stable       bool               `json:"stable" yaml:"stable"`
 	Noop         bool               `json:"noop" yaml:"noop"`
 	NoopMessage  string             `json:"noop_message,omitempty" yaml:"noop_message,omitempty"`
+	Diff         string             `json:"diff,omitempty" yaml:"diff,omitempty"` // Diff is a unified diff of the content a noop run would have changed
 	HealthCheck  *HealthCheckResult `json:"health_check,omitempty" yaml:"health_check,omitempty"`
 }
 
+// emptyResourceProperties creates empty properties for every resource type
+var emptyResourceProperties = map[string]func() ResourceProperties{
+	ApplyTypeName:            func() ResourceProperties { return &ApplyResourceProperties{} },
+	ArchiveTypeName:          func() ResourceProperties { return &ArchiveResourceProperties{} },
+	CronTypeName:             func() ResourceProperties { return &CronResourceProperties{} },
+	ExecTypeName:             func() ResourceProperties { return &ExecResourceProperties{} },
+	FileTypeName:             func() ResourceProperties { return &FileResourceProperties{} },
+	GitTypeName:              func() ResourceProperties { return &GitResourceProperties{} },
+	GroupTypeName:            func() ResourceProperties { return &GroupResourceProperties{} },
+	HostTypeName:             func() ResourceProperties { return &HostResourceProperties{} },
+	JsonEditTypeName:         func() ResourceProperties { return &JsonEditResourceProperties{} },
+	PackageTypeName:          func() ResourceProperties { return &PackageResourceProperties{} },
+	ScaffoldTypeName:         func() ResourceProperties { return &ScaffoldResourceProperties{} },
+	SSHAuthorizedKeyTypeName: func() ResourceProperties { return &SSHAuthorizedKeyResourceProperties{} },
+	ServiceTypeName:          func() ResourceProperties { return &ServiceResourceProperties{} },
+	SudoersTypeName:          func() ResourceProperties { return &SudoersResourceProperties{} },
+	YumRepoTypeName:          func() ResourceProperties { return &YumRepoResourceProperties{} },
+}
+
+var (
+	// customResourceProperties creates empty properties for resource types registered by other packages
+	customResourceProperties = map[string]func() ResourceProperties{}
+	customMu                 sync.Mutex
+
+	// resourceTypeNameRegex matches valid names for custom resource types
+	resourceTypeNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+)
+
+// RegisterResourceProperties registers the properties of a custom resource type so manifests can refer to it
+// by typeName, properties must return a new empty instance on every call. Custom properties are parsed like
+// the built-in ones and default to ensure present.
+func RegisterResourceProperties(typeName string, properties func() ResourceProperties) error {
+	if !resourceTypeNameRegex.MatchString(typeName) {
+		return fmt.Errorf("invalid resource type name %q", typeName)
+	}
+	if properties == nil {
+		return fmt.Errorf("resource type %s requires a properties function", typeName)
+	}
+
+	customMu.Lock()
+	defer customMu.Unlock()
+
+	_, builtin := emptyResourceProperties[typeName]
+	_, custom := customResourceProperties[typeName]
+	if builtin || custom {
+		return fmt.Errorf("%w: %s", ErrDuplicateResourceType, typeName)
+	}
+
+	customResourceProperties[typeName] = properties
+
+	return nil
+}
+
+// IsCustomResourceType reports if typeName was registered using RegisterResourceProperties
+func IsCustomResourceType(typeName string) bool {
+	customMu.Lock()
+	defer customMu.Unlock()
+
+	_, ok := customResourceProperties[typeName]
+
+	return ok
+}
+
+// IsResourceType reports if typeName is a built-in or registered custom resource type
+func IsResourceType(typeName string) bool {
+	_, ok := emptyResourceProperties[typeName]
+
+	return ok || IsCustomResourceType(typeName)
+}
+
+func customPropertiesFactory(typeName string) (func() ResourceProperties, bool) {
+	customMu.Lock()
+	defer customMu.Unlock()
+
+	f, ok := customResourceProperties[typeName]
+
+	return f, ok
+}
+
+// ResourceTypeNames returns the sorted names of all resource types, including custom ones
+func ResourceTypeNames() []string {
+	customMu.Lock()
+	names := slices.Collect(maps.Keys(customResourceProperties))
+	customMu.Unlock()
+
+	names = append(names, slices.Collect(maps.Keys(emptyResourceProperties))...)
+	slices.Sort(names)
+
+	return names
+}
+
+// NewEmptyResourceProperties returns empty properties for typeName, used to inspect the properties a type supports
+func NewEmptyResourceProperties(typeName string) (ResourceProperties, error) {
+	f, ok := emptyResourceProperties[typeName]
+	if !ok {
+		f, ok = customPropertiesFactory(typeName)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownType, typeName)
+	}
+
+	prop := f()
+	prop.CommonProperties().Type = typeName
+
+	return prop, nil
+}
+
 // NewResourcePropertiesFromYaml creates a new resource properties object from a yaml document, it validates the properties and expands any templates
 func NewResourcePropertiesFromYaml(typeName string, rawProperties yaml.RawMessage, env *templates.Env) ([]ResourceProperties, error) {
 	var props []ResourceProperties
 	var err error
 
-	// TODO: extend the registry to include these so its automatic and doesnt need maintenance
-
 	switch typeName {
 	case ApplyTypeName:
 		props, err = NewApplyResourcePropertiesFromYaml(rawProperties)
 	case ArchiveTypeName:
 		props, err = NewArchiveResourcePropertiesFromYaml(rawProperties)
+	case CronTypeName:
+		props, err = NewCronResourcePropertiesFromYaml(rawProperties)
 	case ExecTypeName:
 		props, err = NewExecResourcePropertiesFromYaml(rawProperties)
 	case FileTypeName:
 		props, err = NewFileResourcePropertiesFromYaml(rawProperties)
+	case GitTypeName:
+		props, err = NewGitResourcePropertiesFromYaml(rawProperties)
+	case GroupTypeName:
+		props, err = NewGroupResourcePropertiesFromYaml(rawProperties)
+	case HostTypeName:
+		props, err = NewHostResourcePropertiesFromYaml(rawProperties)
+	case JsonEditTypeName:
+		props, err = NewJsonEditResourcePropertiesFromYaml(rawProperties)
 	case PackageTypeName:
 		props, err = NewPackageResourcePropertiesFromYaml(rawProperties)
 	case ScaffoldTypeName:
 		props, err = NewScaffoldResourcePropertiesFromYaml(rawProperties)
 	case ServiceTypeName:
 		props, err = NewServiceResourcePropertiesFromYaml(rawProperties)
+	case SSHAuthorizedKeyTypeName:
+		props, err = NewSSHAuthorizedKeyResourcePropertiesFromYaml(rawProperties)
+	case SudoersTypeName:
+		props, err = NewSudoersResourcePropertiesFromYaml(rawProperties)
+	case YumRepoTypeName:
+		props, err = NewYumRepoResourcePropertiesFromYaml(rawProperties)
 	default:
-		return nil, fmt.Errorf("%w: %s %s", ErrResourceInvalid, ErrUnknownType, typeName)
+		props, err = newCustomResourcePropertiesFromYaml(typeName, rawProperties)
 	}
 	if err != nil {
 		return nil, err
 	}
 
+	var res []ResourceProperties
 	for _, prop := range props {
 		err = prop.ResolveTemplates(env)
 		if err != nil {
 			return nil, err
 		}
+
+		exp, ok := prop.(expandingResourceProperties)
+		if !ok {
+			res = append(res, prop)
+			continue
+		}
+
+		expanded, err := exp.expandResources(env)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrResourceInvalid, err)
+		}
+		res = append(res, expanded...)
 	}
 
-	return props, nil
+	return res, nil
+}
+
+// newCustomResourcePropertiesFromYaml parses properties of a registered custom resource type
+func newCustomResourcePropertiesFromYaml(typeName string, raw yaml.RawMessage) ([]ResourceProperties, error) {
+	f, ok := customPropertiesFactory(typeName)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s %s", ErrResourceInvalid, ErrUnknownType, typeName)
+	}
+
+	res, err := parseProperties(raw, typeName, f)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, prop := range res {
+		cp := prop.CommonProperties()
+		if cp.Ensure == "" {
+			cp.Ensure = EnsurePresent
+		}
+	}
+
+	return res, nil
+}
+
+// expandingResourceProperties is implemented by resource properties that can describe several resources, they
+// are expanded into one properties instance per resource once templates are resolved
+type expandingResourceProperties interface {
+	expandResources(env *templates.Env) ([]ResourceProperties, error)
 }
 
 // NewValidatedResourcePropertiesFromYaml creates and validates a new resource properties object from a yaml document, it validates the properties and expands any templates
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/choria-io/fisk"
 	"github.com/goccy/go-yaml"
 
 	iu "github.com/choria-io/ccm/internal/util"
@@ -22,21 +23,32 @@ const (
 
 	// ArchiveTypeName is the type name for archive resources
 	ArchiveTypeName = "archive"
+
+	archiveExtensionsDescription = ".zip, .tar.gz, .tgz, .tar.xz, .txz, .tar.bz2, .tbz2, or .tar"
+
+	// DefaultArchiveRetryDelay is the delay before the first download retry when retry_delay is not set
+	DefaultArchiveRetryDelay = time.Second
 )
 
+// archiveExtensions are the supported archive file extensions
+var archiveExtensions = []string{".zip", ".tar.gz", ".tgz", ".tar.xz", ".txz", ".tar.bz2", ".tbz2", ".tar"}
+
 // ArchiveResourceProperties defines the properties for a archive resource
 type ArchiveResourceProperties struct {
 	CommonResourceProperties `yaml:",inline"`
-	Url                      string            `json:"url" yaml:"url"`                                           // URL specifies the URL to download the archive from
-	Headers                  map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`               // Headers specify any HTTP headers to include in the request
-	Username                 string            `json:"username,omitempty" yaml:"username,omitempty"`             // Username specifies the username to use for basic auth
-	Password                 string            `json:"password,omitempty" yaml:"password,omitempty"`             // Password specifies the password to use for basic auth
-	Checksum                 string            `json:"checksum,omitempty" yaml:"checksum,omitempty"`             // Checksum specifies the expected sha256 checksum of the archive
-	ExtractParent            string            `json:"extract_parent,omitempty" yaml:"extract_parent,omitempty"` // ExtractParent specifies the parent directory to extract the archive into
-	Cleanup                  bool              `json:"cleanup,omitempty" yaml:"cleanup,omitempty"`               // Cleanup specifies whether to remove the archive file after extraction
-	Creates                  string            `json:"creates,omitempty" yaml:"creates,omitempty"`               // Creates specifies a file that the archive creates; if this file exists, the archive will not be extracted on future runs
-	Owner                    string            `json:"owner,omitempty" yaml:"owner,omitempty"`                   // Owner specifies the user that should own the file; required unless ensure is absent
-	Group                    string            `json:"group,omitempty" yaml:"group,omitempty"`                   // Group specifies the group that should own the file; required unless ensure is absent
+	Url                      string            `json:"url" yaml:"url"`                                                // URL specifies the URL to download the archive from
+	Headers                  map[string]string `json:"headers,omitempty" yaml:"headers,omitempty" sensitive:"true"`   // Headers specify any HTTP headers to include in the request
+	Username                 string            `json:"username,omitempty" yaml:"username,omitempty"`                  // Username specifies the username to use for basic auth
+	Password                 string            `json:"password,omitempty" yaml:"password,omitempty" sensitive:"true"` // Password specifies the password to use for basic auth
+	Checksum                 string            `json:"checksum,omitempty" yaml:"checksum,omitempty"`                  // Checksum specifies the expected sha256 checksum of the archive
+	Retries                  int               `json:"retries,omitempty" yaml:"retries,omitempty"`                    // Retries is the number of times a download failing with a network error or server error is retried
+	RetryDelay               string            `json:"retry_delay,omitempty" yaml:"retry_delay,omitempty"`            // RetryDelay is the delay before the first retry, doubled for every further retry
+	Resume                   bool              `json:"resume,omitempty" yaml:"resume,omitempty"`                      // Resume continues interrupted downloads using range requests when the server supports them, requires checksum
+	ExtractParent            string            `json:"extract_parent,omitempty" yaml:"extract_parent,omitempty"`      // ExtractParent specifies the parent directory to extract the archive into
+	Cleanup                  bool              `json:"cleanup,omitempty" yaml:"cleanup,omitempty"`                    // Cleanup specifies whether to remove the archive file after extraction
+	Creates                  string            `json:"creates,omitempty" yaml:"creates,omitempty"`                    // Creates specifies a file that the archive creates; if this file exists, the archive will not be extracted on future runs
+	Owner                    string            `json:"owner,omitempty" yaml:"owner,omitempty"`                        // Owner specifies the user that should own the file; required unless ensure is absent
+	Group                    string            `json:"group,omitempty" yaml:"group,omitempty"`                        // Group specifies the group that should own the file; required unless ensure is absent
 }
 
 // ArchiveMetadata contains detailed metadata about an archive
@@ -93,18 +105,23 @@ func (p *ArchiveResourceProperties) Validate() error {
 		return fmt.Errorf("url must be absolute (include scheme like http:// or https://)")
 	}
 
+	// catches templates like https://{{ Facts.mirror }}/app.tgz that rendered an empty host
+	if parsedURL.Host == "" {
+		return fmt.Errorf("url %q must include a host", p.Url)
+	}
+
 	filename := filepath.Base(parsedURL.Path)
 	if filename == "" || filename == "." || filename == "/" {
 		return fmt.Errorf("url must have a filename in the path")
 	}
 
-	if !iu.FileHasSuffix(filename, ".zip", ".tar.gz", ".tgz", ".tar") {
-		return fmt.Errorf("url filename must end in .zip, .tar.gz, .tgz, or .tar")
+	if !iu.FileHasSuffix(filename, archiveExtensions...) {
+		return fmt.Errorf("url filename must end in %s", archiveExtensionsDescription)
 	}
 
 	// Validate that Name has a matching archive extension
-	if !iu.FileHasSuffix(p.Name, ".zip", ".tar.gz", ".tgz", ".tar") {
-		return fmt.Errorf("name must end in .zip, .tar.gz, .tgz, or .tar")
+	if !iu.FileHasSuffix(p.Name, archiveExtensions...) {
+		return fmt.Errorf("name must end in %s", archiveExtensionsDescription)
 	}
 
 	// Ensure URL and Name have compatible archive types
@@ -119,6 +136,19 @@ func (p *ArchiveResourceProperties) Validate() error {
 		return fmt.Errorf("%w: must be one of %q or %q", ErrInvalidEnsureValue, EnsurePresent, EnsureAbsent)
 	}
 
+	if p.Resume && p.Checksum == "" {
+		return fmt.Errorf("resume requires checksum to be set")
+	}
+
+	if p.Retries < 0 {
+		return fmt.Errorf("retries cannot be negative")
+	}
+
+	_, _, err = p.RetryPolicy()
+	if err != nil {
+		return err
+	}
+
 	if p.Cleanup && p.ExtractParent == "" {
 		return fmt.Errorf("cleanup requires extract_parent to be set")
 	}
@@ -127,22 +157,21 @@ func (p *ArchiveResourceProperties) Validate() error {
 		return fmt.Errorf("cleanup requires creates to be set")
 	}
 
-	if filepath.Clean(p.Name) != p.Name {
-		return fmt.Errorf("file path must be absolute")
-	}
-
-	if !filepath.IsAbs(p.Name) {
-		return fmt.Errorf("file path must be absolute")
+	err = iu.ValidateCanonicalPath("name", p.Name)
+	if err != nil {
+		return err
 	}
 
 	if len(p.Creates) > 0 {
-		if filepath.Clean(p.Creates) != p.Creates {
-			return fmt.Errorf("creates path must be absolute")
+		err = iu.ValidateCanonicalPath("creates", p.Creates)
+		if err != nil {
+			return err
 		}
 	}
 	if len(p.ExtractParent) > 0 {
-		if filepath.Clean(p.ExtractParent) != p.ExtractParent {
-			return fmt.Errorf("extract_parent path must be absolute")
+		err = iu.ValidateCanonicalPath("extract_parent", p.ExtractParent)
+		if err != nil {
+			return err
 		}
 	}
 
@@ -156,12 +185,34 @@ func (p *ArchiveResourceProperties) Validate() error {
 	return nil
 }
 
+// RetryPolicy parses the retries and retry_delay settings, returning the number of retries and the delay before
+// the first retry
+func (p *ArchiveResourceProperties) RetryPolicy() (int, time.Duration, error) {
+	if p.RetryDelay == "" {
+		return p.Retries, DefaultArchiveRetryDelay, nil
+	}
+
+	delay, err := fisk.ParseDuration(p.RetryDelay)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid retry_delay duration %q: %w", p.RetryDelay, err)
+	}
+
+	return p.Retries, delay, nil
+}
+
 // archiveTypeFromFilename returns a normalized archive type string based on the file extension.
-// Returns "tar.gz" for .tar.gz and .tgz, "tar" for .tar, "zip" for .zip, or "unknown".
+// Returns "tar.gz" for .tar.gz and .tgz, "tar.xz" for .tar.xz and .txz, "tar.bz2" for .tar.bz2 and .tbz2,
+// "tar" for .tar, "zip" for .zip, or "unknown".
 func archiveTypeFromFilename(filename string) string {
 	if iu.FileHasSuffix(filename, ".tar.gz", ".tgz") {
 		return "tar.gz"
 	}
+	if iu.FileHasSuffix(filename, ".tar.xz", ".txz") {
+		return "tar.xz"
+	}
+	if iu.FileHasSuffix(filename, ".tar.bz2", ".tbz2") {
+		return "tar.bz2"
+	}
 	if iu.FileHasSuffix(filename, ".tar") {
 		return "tar"
 	}
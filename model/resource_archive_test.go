@@ -5,6 +5,8 @@
 package model
 
 import (
+	"time"
+
 	"github.com/goccy/go-yaml"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -44,28 +46,34 @@ var _ = Describe("ArchiveResourceProperties", func() {
 			Entry("valid archive with creates", "/tmp/archive.tar.gz", "present", "https://example.com/archive.tar.gz", "root", "root", "/opt/app/bin", "", ""),
 			Entry("valid archive with extract_parent", "/tmp/archive.tar.gz", "present", "https://example.com/archive.tar.gz", "root", "root", "", "/opt", ""),
 			Entry("valid archive with all options", "/tmp/archive.tar.gz", "present", "https://example.com/archive.tar.gz", "app", "app", "/opt/app/bin", "/opt", ""),
+			Entry("valid tar.xz archive", "/tmp/archive.tar.xz", "present", "https://example.com/archive.txz", "root", "root", "", "", ""),
+			Entry("valid tar.bz2 archive", "/tmp/archive.tbz2", "present", "https://example.com/archive.tar.bz2", "root", "root", "", "", ""),
 
 			// Name validation
 			Entry("empty name", "", "present", "https://example.com/archive.tar.gz", "root", "root", "", "", "name"),
-			Entry("path with ..", "/tmp/../etc/archive.tar.gz", "present", "https://example.com/archive.tar.gz", "root", "root", "", "", "absolute"),
-			Entry("path with .", "/tmp/./archive.tar.gz", "present", "https://example.com/archive.tar.gz", "root", "root", "", "", "absolute"),
+			Entry("relative path", "tmp/archive.tar.gz", "present", "https://example.com/archive.tar.gz", "root", "root", "", "", "name must be an absolute path"),
+			Entry("path with ..", "/tmp/../etc/archive.tar.gz", "present", "https://example.com/archive.tar.gz", "root", "root", "", "", "name must be a canonical path"),
+			Entry("path with .", "/tmp/./archive.tar.gz", "present", "https://example.com/archive.tar.gz", "root", "root", "", "", "name must be a canonical path"),
+			Entry("path with //", "/tmp//archive.tar.gz", "present", "https://example.com/archive.tar.gz", "root", "root", "", "", "name must be a canonical path"),
 
 			// URL validation
 			Entry("empty url", "/tmp/archive.tar.gz", "present", "", "root", "root", "", "", "url cannot be empty"),
 			Entry("relative url", "/tmp/archive.tar.gz", "present", "/path/to/archive.tar.gz", "root", "root", "", "", "url must be absolute"),
 			Entry("url without scheme", "/tmp/archive.tar.gz", "present", "example.com/archive.tar.gz", "root", "root", "", "", "url must be absolute"),
+			Entry("url without host", "/tmp/archive.tar.gz", "present", "https:///archive.tar.gz", "root", "root", "", "", `url "https:///archive.tar.gz" must include a host`),
 			Entry("url without filename", "/tmp/archive.tar.gz", "present", "https://example.com/", "root", "root", "", "", "url must have a filename"),
-			Entry("url with .exe extension", "/tmp/archive.tar.gz", "present", "https://example.com/archive.exe", "root", "root", "", "", "url filename must end in .zip, .tar.gz, .tgz, or .tar"),
-			Entry("url with .tar.xz extension", "/tmp/archive.tar.gz", "present", "https://example.com/archive.tar.xz", "root", "root", "", "", "url filename must end in .zip, .tar.gz, .tgz, or .tar"),
+			Entry("url with .exe extension", "/tmp/archive.tar.gz", "present", "https://example.com/archive.exe", "root", "root", "", "", "url filename must end in .zip, .tar.gz, .tgz, .tar.xz, .txz, .tar.bz2, .tbz2, or .tar"),
+			Entry("url with .tar.lz extension", "/tmp/archive.tar.gz", "present", "https://example.com/archive.tar.lz", "root", "root", "", "", "url filename must end in .zip, .tar.gz, .tgz, .tar.xz, .txz, .tar.bz2, .tbz2, or .tar"),
 
 			// Name extension validation
-			Entry("name with invalid extension", "/tmp/archive.bin", "present", "https://example.com/archive.tar.gz", "root", "root", "", "", "name must end in .zip, .tar.gz, .tgz, or .tar"),
+			Entry("name with invalid extension", "/tmp/archive.bin", "present", "https://example.com/archive.tar.gz", "root", "root", "", "", "name must end in .zip, .tar.gz, .tgz, .tar.xz, .txz, .tar.bz2, .tbz2, or .tar"),
 
 			// Archive type mismatch validation
 			Entry("tar.gz url with zip name", "/tmp/archive.zip", "present", "https://example.com/archive.tar.gz", "root", "root", "", "", "url and name must have the same archive type"),
 			Entry("zip url with tar.gz name", "/tmp/archive.tar.gz", "present", "https://example.com/archive.zip", "root", "root", "", "", "url and name must have the same archive type"),
 			Entry("tar url with tar.gz name", "/tmp/archive.tar.gz", "present", "https://example.com/archive.tar", "root", "root", "", "", "url and name must have the same archive type"),
 			Entry("tar.gz url with tar name", "/tmp/archive.tar", "present", "https://example.com/archive.tar.gz", "root", "root", "", "", "url and name must have the same archive type"),
+			Entry("tar.xz url with tar.bz2 name", "/tmp/archive.tar.bz2", "present", "https://example.com/archive.tar.xz", "root", "root", "", "", "url and name must have the same archive type"),
 
 			// Ensure validation
 			Entry("empty ensure", "/tmp/archive.tar.gz", "", "https://example.com/archive.tar.gz", "root", "root", "", "", "ensure"),
@@ -79,12 +87,18 @@ var _ = Describe("ArchiveResourceProperties", func() {
 			Entry("empty group", "/tmp/archive.tar.gz", "present", "https://example.com/archive.tar.gz", "root", "", "", "", "group cannot be empty"),
 
 			// Creates validation
-			Entry("creates path with ..", "/tmp/archive.tar.gz", "present", "https://example.com/archive.tar.gz", "root", "root", "/opt/../etc/file", "", "creates path must be absolute"),
-			Entry("creates path with .", "/tmp/archive.tar.gz", "present", "https://example.com/archive.tar.gz", "root", "root", "/opt/./file", "", "creates path must be absolute"),
+			Entry("relative creates path", "/tmp/archive.tar.gz", "present", "https://example.com/archive.tar.gz", "root", "root", "opt/file", "", "creates must be an absolute path"),
+			Entry("creates path with ..", "/tmp/archive.tar.gz", "present", "https://example.com/archive.tar.gz", "root", "root", "/opt/../etc/file", "", "creates must be a canonical path"),
+			Entry("creates path with .", "/tmp/archive.tar.gz", "present", "https://example.com/archive.tar.gz", "root", "root", "/opt/./file", "", "creates must be a canonical path"),
+			Entry("creates path with //", "/tmp/archive.tar.gz", "present", "https://example.com/archive.tar.gz", "root", "root", "/opt//file", "", "creates must be a canonical path"),
+			Entry("creates path with trailing slash", "/tmp/archive.tar.gz", "present", "https://example.com/archive.tar.gz", "root", "root", "/opt/file/", "", "creates must be a canonical path"),
 
 			// ExtractParent validation
-			Entry("extract_parent path with ..", "/tmp/archive.tar.gz", "present", "https://example.com/archive.tar.gz", "root", "root", "", "/opt/../etc", "extract_parent path must be absolute"),
-			Entry("extract_parent path with .", "/tmp/archive.tar.gz", "present", "https://example.com/archive.tar.gz", "root", "root", "", "/opt/./dir", "extract_parent path must be absolute"),
+			Entry("relative extract_parent path", "/tmp/archive.tar.gz", "present", "https://example.com/archive.tar.gz", "root", "root", "", "opt", "extract_parent must be an absolute path"),
+			Entry("extract_parent path with ..", "/tmp/archive.tar.gz", "present", "https://example.com/archive.tar.gz", "root", "root", "", "/opt/../etc", "extract_parent must be a canonical path"),
+			Entry("extract_parent path with .", "/tmp/archive.tar.gz", "present", "https://example.com/archive.tar.gz", "root", "root", "", "/opt/./dir", "extract_parent must be a canonical path"),
+			Entry("extract_parent path with //", "/tmp/archive.tar.gz", "present", "https://example.com/archive.tar.gz", "root", "root", "", "/opt//dir", "extract_parent must be a canonical path"),
+			Entry("extract_parent path with trailing slash", "/tmp/archive.tar.gz", "present", "https://example.com/archive.tar.gz", "root", "root", "", "/opt/dir/", "extract_parent must be a canonical path"),
 		)
 
 		It("Should fail when cleanup is true but extract_parent is empty", func() {
@@ -140,6 +154,55 @@ var _ = Describe("ArchiveResourceProperties", func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 
+		It("Should validate the download retries", func() {
+			prop := &ArchiveResourceProperties{
+				CommonResourceProperties: CommonResourceProperties{
+					Name:   "/tmp/archive.tar.gz",
+					Ensure: EnsurePresent,
+				},
+				Url:        "https://example.com/archive.tar.gz",
+				Owner:      "root",
+				Group:      "root",
+				Retries:    3,
+				RetryDelay: "2s",
+			}
+			Expect(prop.Validate()).To(Succeed())
+
+			retries, delay, err := prop.RetryPolicy()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(retries).To(Equal(3))
+			Expect(delay).To(Equal(2 * time.Second))
+
+			prop.RetryDelay = ""
+			_, delay, err = prop.RetryPolicy()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(delay).To(Equal(DefaultArchiveRetryDelay))
+
+			prop.RetryDelay = "soon"
+			Expect(prop.Validate()).To(MatchError(ContainSubstring(`invalid retry_delay duration "soon"`)))
+
+			prop.RetryDelay = ""
+			prop.Retries = -1
+			Expect(prop.Validate()).To(MatchError("retries cannot be negative"))
+		})
+
+		It("Should require a checksum to resume downloads", func() {
+			prop := &ArchiveResourceProperties{
+				CommonResourceProperties: CommonResourceProperties{
+					Name:   "/tmp/archive.tar.gz",
+					Ensure: EnsurePresent,
+				},
+				Url:    "https://example.com/archive.tar.gz",
+				Owner:  "root",
+				Group:  "root",
+				Resume: true,
+			}
+			Expect(prop.Validate()).To(MatchError("resume requires checksum to be set"))
+
+			prop.Checksum = "abc"
+			Expect(prop.Validate()).To(Succeed())
+		})
+
 		DescribeTable("legitimate archive paths",
 			func(name, url, owner, group string) {
 				prop := &ArchiveResourceProperties{
@@ -191,11 +254,13 @@ var _ = Describe("ArchiveResourceProperties", func() {
 					Name:   "/tmp/{{ Facts.filename }}",
 					Ensure: EnsurePresent,
 				},
-				Url:      "https://example.com/{{ Facts.version }}/archive.tar.gz",
-				Owner:    "{{ Facts.owner }}",
-				Group:    "{{ Facts.group }}",
-				Checksum: "{{ Facts.checksum }}",
-				Creates:  "/opt/{{ Facts.appname }}/bin",
+				Url:           "https://example.com/{{ Facts.version }}/archive.tar.gz",
+				Owner:         "{{ Facts.owner }}",
+				Group:         "{{ Facts.group }}",
+				Checksum:      "{{ Facts.checksum }}",
+				Creates:       "/opt/{{ Facts.appname }}/bin",
+				ExtractParent: "/opt/{{ Facts.appname }}",
+				Headers:       map[string]string{"X-Arch": "{{ Facts.arch }}"},
 			}
 
 			env := &templates.Env{
@@ -206,6 +271,7 @@ var _ = Describe("ArchiveResourceProperties", func() {
 					"group":    "wheel",
 					"checksum": "abc123def456",
 					"appname":  "myapp",
+					"arch":     "arm64",
 				},
 			}
 
@@ -217,6 +283,8 @@ var _ = Describe("ArchiveResourceProperties", func() {
 			Expect(prop.Group).To(Equal("wheel"))
 			Expect(prop.Checksum).To(Equal("abc123def456"))
 			Expect(prop.Creates).To(Equal("/opt/myapp/bin"))
+			Expect(prop.ExtractParent).To(Equal("/opt/myapp"))
+			Expect(prop.Headers).To(Equal(map[string]string{"X-Arch": "arm64"}))
 		})
 
 		It("Should handle non-template strings", func() {
@@ -0,0 +1,280 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/choria-io/ccm/templates"
+)
+
+const (
+	// ResourceStatusCronProtocol is the protocol identifier for cron resource state
+	ResourceStatusCronProtocol = "io.choria.ccm.v1.resource.cron.state"
+
+	// CronTypeName is the type name for cron resources
+	CronTypeName = "cron"
+
+	// CronDefaultUser is the user jobs run as when no user is set
+	CronDefaultUser = "root"
+
+	// CronFileHeader is the comment written at the top of every managed cron file
+	CronFileHeader = "# Managed by Choria CCM, local changes will be overwritten"
+)
+
+var (
+	// cronUserRegex matches POSIX portable user names as accepted by useradd
+	cronUserRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.-]{0,31}$`)
+
+	// cronEnvironmentRegex matches NAME=value environment lines
+	cronEnvironmentRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*=`)
+
+	// cronFileNameRegex matches characters that cron ignores files for, see run-parts(8)
+	cronFileNameRegex = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+	cronMonthNames   = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+	cronWeekdayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+)
+
+// CronResourceProperties defines the properties for a cron resource
+type CronResourceProperties struct {
+	CommonResourceProperties `yaml:",inline"`
+	Command                  string   `json:"command" yaml:"command"`                             // Command is the command to run, it must be a single line
+	User                     string   `json:"user,omitempty" yaml:"user,omitempty"`               // User is the user the command runs as, defaults to root
+	Minute                   string   `json:"minute,omitempty" yaml:"minute,omitempty"`           // Minute is the minute field of the schedule, defaults to *
+	Hour                     string   `json:"hour,omitempty" yaml:"hour,omitempty"`               // Hour is the hour field of the schedule, defaults to *
+	MonthDay                 string   `json:"monthday,omitempty" yaml:"monthday,omitempty"`       // MonthDay is the day of month field of the schedule, defaults to *
+	Month                    string   `json:"month,omitempty" yaml:"month,omitempty"`             // Month is the month field of the schedule, defaults to *
+	Weekday                  string   `json:"weekday,omitempty" yaml:"weekday,omitempty"`         // Weekday is the day of week field of the schedule, defaults to *
+	Environment              []string `json:"environment,omitempty" yaml:"environment,omitempty"` // Environment is a list of NAME=value lines placed before the job, for example MAILTO=ops@example.net
+}
+
+// CronMetadata contains detailed metadata about a cron job
+type CronMetadata struct {
+	Name     string `json:"name" yaml:"name"`
+	File     string `json:"file" yaml:"file"`
+	Content  string `json:"content,omitempty" yaml:"content,omitempty"`
+	Checksum string `json:"checksum,omitempty" yaml:"checksum,omitempty"`
+	Provider string `json:"provider,omitempty" yaml:"provider,omitempty"`
+}
+
+// CronState represents the current state of a cron job
+type CronState struct {
+	CommonResourceState
+
+	Metadata *CronMetadata `json:"metadata,omitempty"`
+}
+
+func (f *CronState) CommonState() *CommonResourceState {
+	return &f.CommonResourceState
+}
+
+func (p *CronResourceProperties) CommonProperties() *CommonResourceProperties {
+	return &p.CommonResourceProperties
+}
+
+// FileName is the name of the file holding the job, derived from the resource name with characters cron would
+// refuse in file names replaced by underscores
+func (p *CronResourceProperties) FileName() string {
+	return cronFileNameRegex.ReplaceAllString(p.Name, "_")
+}
+
+// RunAs is the user the job runs as, CronDefaultUser when User is not set
+func (p *CronResourceProperties) RunAs() string {
+	if p.User == "" {
+		return CronDefaultUser
+	}
+
+	return p.User
+}
+
+// Schedule is the five field schedule of the job, unset fields default to *
+func (p *CronResourceProperties) Schedule() []string {
+	fields := []string{p.Minute, p.Hour, p.MonthDay, p.Month, p.Weekday}
+	for i, f := range fields {
+		if f == "" {
+			fields[i] = "*"
+		}
+	}
+
+	return fields
+}
+
+// FileContent renders the cron file describing the job
+func (p *CronResourceProperties) FileContent() []byte {
+	buf := bytes.NewBufferString(CronFileHeader + "\n")
+
+	for _, env := range p.Environment {
+		fmt.Fprintln(buf, env)
+	}
+
+	fmt.Fprintf(buf, "%s %s %s\n", strings.Join(p.Schedule(), " "), p.RunAs(), p.Command)
+
+	return buf.Bytes()
+}
+
+// Validate validates the cron resource properties
+func (p *CronResourceProperties) Validate() error {
+	if p.SkipValidate {
+		return nil
+	}
+
+	// First run common validation
+	err := p.CommonResourceProperties.Validate()
+	if err != nil {
+		return err
+	}
+
+	if p.Ensure != EnsurePresent && p.Ensure != EnsureAbsent {
+		return fmt.Errorf("%w: must be one of %q or %q", ErrInvalidEnsureValue, EnsurePresent, EnsureAbsent)
+	}
+
+	if strings.Trim(p.FileName(), "_-") == "" {
+		return fmt.Errorf("name must contain at least one letter or digit")
+	}
+
+	if p.Ensure == EnsureAbsent {
+		return nil
+	}
+
+	if strings.TrimSpace(p.Command) == "" {
+		return fmt.Errorf("command is required when ensure is %q", EnsurePresent)
+	}
+	if strings.ContainsAny(p.Command, "\n\r") {
+		return fmt.Errorf("command must be a single line")
+	}
+
+	if !cronUserRegex.MatchString(p.RunAs()) {
+		return fmt.Errorf("invalid user %q", p.RunAs())
+	}
+
+	for _, env := range p.Environment {
+		if !cronEnvironmentRegex.MatchString(env) || strings.ContainsAny(env, "\n\r") {
+			return fmt.Errorf("invalid environment %q, must be a single NAME=value line", env)
+		}
+	}
+
+	schedule := p.Schedule()
+	for i, field := range []struct {
+		name     string
+		min, max int
+		names    []string
+	}{
+		{"minute", 0, 59, nil},
+		{"hour", 0, 23, nil},
+		{"monthday", 1, 31, nil},
+		{"month", 1, 12, cronMonthNames},
+		{"weekday", 0, 7, cronWeekdayNames},
+	} {
+		err = validateCronField(schedule[i], field.min, field.max, field.names)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: %w", field.name, schedule[i], err)
+		}
+	}
+
+	return nil
+}
+
+// validateCronField validates a single schedule field made up of comma separated
+// values, ranges and steps like 1,5-10,*/15 within min and max. Names, like jan or
+// mon, are accepted as single values.
+func validateCronField(field string, min int, max int, names []string) error {
+	for _, item := range strings.Split(field, ",") {
+		rng, step, hasStep := strings.Cut(item, "/")
+		if hasStep {
+			s, err := strconv.Atoi(step)
+			if err != nil || s < 1 {
+				return fmt.Errorf("step %q must be a positive number", step)
+			}
+		}
+
+		if rng == "*" {
+			continue
+		}
+
+		if !hasStep && cronFieldName(rng, names) {
+			continue
+		}
+
+		start, end, isRange := strings.Cut(rng, "-")
+		if !isRange {
+			end = start
+		}
+
+		s, err := cronFieldNumber(start, min, max)
+		if err != nil {
+			return err
+		}
+		e, err := cronFieldNumber(end, min, max)
+		if err != nil {
+			return err
+		}
+		if e < s {
+			return fmt.Errorf("range %q is reversed", rng)
+		}
+	}
+
+	return nil
+}
+
+func cronFieldName(v string, names []string) bool {
+	for _, name := range names {
+		if strings.EqualFold(v, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func cronFieldNumber(v string, min int, max int) (int, error) {
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", v)
+	}
+	if n < min || n > max {
+		return 0, fmt.Errorf("%d is not between %d and %d", n, min, max)
+	}
+
+	return n, nil
+}
+
+// ResolveTemplates resolves template expressions in the cron resource properties
+func (p *CronResourceProperties) ResolveTemplates(env *templates.Env) error {
+	err := templates.ResolveStructTemplates(p, env, false)
+	if err != nil {
+		return err
+	}
+
+	return p.resolveRegistrations(env)
+}
+
+// ToYamlManifest returns the cron resource properties as a yaml document
+func (p *CronResourceProperties) ToYamlManifest() (yaml.RawMessage, error) {
+	return yaml.Marshal(p)
+}
+
+// NewCronResourcePropertiesFromYaml creates a new cron resource properties object from a yaml document, does not validate or expand templates
+func NewCronResourcePropertiesFromYaml(raw yaml.RawMessage) ([]ResourceProperties, error) {
+	res, err := parseProperties(raw, CronTypeName, func() ResourceProperties { return &CronResourceProperties{} })
+	if err != nil {
+		return nil, err
+	}
+
+	for _, prop := range res {
+		p := prop.(*CronResourceProperties)
+		if p.Ensure == "" {
+			p.Ensure = EnsurePresent
+		}
+	}
+
+	return res, nil
+}
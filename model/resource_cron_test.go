@@ -0,0 +1,94 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CronResourceProperties", func() {
+	Describe("Validate", func() {
+		DescribeTable("validation tests",
+			func(name, ensure, command, user string, schedule []string, environment []string, errorText string) {
+				prop := &CronResourceProperties{
+					CommonResourceProperties: CommonResourceProperties{
+						Name:   name,
+						Ensure: ensure,
+					},
+					Command:     command,
+					User:        user,
+					Minute:      schedule[0],
+					Hour:        schedule[1],
+					MonthDay:    schedule[2],
+					Month:       schedule[3],
+					Weekday:     schedule[4],
+					Environment: environment,
+				}
+
+				err := prop.Validate()
+
+				if errorText != "" {
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring(errorText))
+				} else {
+					Expect(err).ToNot(HaveOccurred())
+				}
+			},
+
+			Entry("valid defaults", "backup", "present", "/bin/backup", "", []string{"", "", "", "", ""}, nil, ""),
+			Entry("valid schedule", "backup", "present", "/bin/backup", "app", []string{"*/15", "1-5,22", "1", "jan", "mon"}, nil, ""),
+			Entry("valid ranges with steps", "backup", "present", "/bin/backup", "", []string{"0-30/5", "*", "*", "1-12/2", "0-7"}, nil, ""),
+			Entry("valid environment", "backup", "present", "/bin/backup", "", []string{"", "", "", "", ""}, []string{"MAILTO=ops@example.net", "PATH=/bin"}, ""),
+			Entry("valid absent without command", "backup", "absent", "", "", []string{"", "", "", "", ""}, nil, ""),
+
+			Entry("invalid ensure", "backup", "running", "/bin/backup", "", []string{"", "", "", "", ""}, nil, "invalid ensure value"),
+			Entry("unusable name", "...", "present", "/bin/backup", "", []string{"", "", "", "", ""}, nil, "name must contain at least one letter or digit"),
+			Entry("missing command", "backup", "present", "", "", []string{"", "", "", "", ""}, nil, "command is required"),
+			Entry("multi line command", "backup", "present", "/bin/a\n/bin/b", "", []string{"", "", "", "", ""}, nil, "command must be a single line"),
+			Entry("invalid user", "backup", "present", "/bin/backup", "bad user", []string{"", "", "", "", ""}, nil, `invalid user "bad user"`),
+			Entry("minute out of range", "backup", "present", "/bin/backup", "", []string{"60", "", "", "", ""}, nil, `invalid minute "60"`),
+			Entry("hour out of range", "backup", "present", "/bin/backup", "", []string{"", "24", "", "", ""}, nil, `invalid hour "24"`),
+			Entry("monthday zero", "backup", "present", "/bin/backup", "", []string{"", "", "0", "", ""}, nil, `invalid monthday "0"`),
+			Entry("unknown month name", "backup", "present", "/bin/backup", "", []string{"", "", "", "foo", ""}, nil, `invalid month "foo"`),
+			Entry("weekday out of range", "backup", "present", "/bin/backup", "", []string{"", "", "", "", "8"}, nil, `invalid weekday "8"`),
+			Entry("reversed range", "backup", "present", "/bin/backup", "", []string{"30-10", "", "", "", ""}, nil, "is reversed"),
+			Entry("invalid step", "backup", "present", "/bin/backup", "", []string{"*/0", "", "", "", ""}, nil, "must be a positive number"),
+			Entry("field with spaces", "backup", "present", "/bin/backup", "", []string{"1 2", "", "", "", ""}, nil, `invalid minute "1 2"`),
+			Entry("invalid environment", "backup", "present", "/bin/backup", "", []string{"", "", "", "", ""}, []string{"MAILTO"}, "invalid environment"),
+		)
+	})
+
+	Describe("FileName", func() {
+		It("Should replace characters cron does not accept", func() {
+			prop := &CronResourceProperties{CommonResourceProperties: CommonResourceProperties{Name: "app.backup/nightly"}}
+			Expect(prop.FileName()).To(Equal("app_backup_nightly"))
+		})
+	})
+
+	Describe("FileContent", func() {
+		It("Should render the job with defaults", func() {
+			prop := &CronResourceProperties{
+				Command:     "/bin/backup",
+				Hour:        "2",
+				Environment: []string{"MAILTO=root"},
+			}
+
+			Expect(string(prop.FileContent())).To(Equal(CronFileHeader + "\nMAILTO=root\n* 2 * * * root /bin/backup\n"))
+		})
+	})
+
+	Describe("NewCronResourcePropertiesFromYaml", func() {
+		It("Should default ensure to present", func() {
+			res, err := NewCronResourcePropertiesFromYaml([]byte(`- backup:
+    command: /bin/backup
+    hour: "2"`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(HaveLen(1))
+			Expect(res[0].CommonProperties().Ensure).To(Equal(EnsurePresent))
+			Expect(res[0].(*CronResourceProperties).Hour).To(Equal("2"))
+		})
+	})
+})
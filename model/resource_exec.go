@@ -5,7 +5,10 @@
 package model
 
 import (
+	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 	"time"
 
@@ -22,6 +25,20 @@ const (
 
 	// ExecTypeName is the type name for exec resources
 	ExecTypeName = "exec"
+
+	// ExecOutputFormatJSON parses command output as a JSON document
+	ExecOutputFormatJSON = "json"
+
+	// ExecOutputFormatKV parses command output as key=value lines
+	ExecOutputFormatKV = "kv"
+)
+
+var (
+	// execRunnerEnvironment are variables the command runner sets that are never inherited from the manager
+	execRunnerEnvironment = []string{"PATH", "LANG", "LC_ALL", "TZ"}
+
+	// execUserEnvironment are variables the command runner sets when running as another user
+	execUserEnvironment = []string{"HOME", "USER", "LOGNAME"}
 )
 
 // ExecResourceProperties defines the properties for an exec resource
@@ -31,6 +48,7 @@ type ExecResourceProperties struct {
 	Cwd                      string   `json:"cwd,omitempty" yaml:"cwd,omitempty" template:"deferred"`                                                    // Cwd specifies the working directory from which to run the command
 	Environment              []string `json:"environment,omitempty" yaml:"environment,omitempty" template:"deferred" schema_placeholder:"PLACEHOLDER=x"` // Environment specifies additional environment variables to set when running the command
 	Path                     string   `json:"path,omitempty" yaml:"path,omitempty"`                                                                      // Path specifies the search path for executable commands, as an array of directories or a colon-separated list
+	User                     string   `json:"user,omitempty" yaml:"user,omitempty"`                                                                      // User specifies the user to run the command as, requires CCM to run with sufficient privileges
 	Returns                  []int    `json:"returns,omitempty" yaml:"returns,omitempty"`                                                                // Returns specify the expected exit codes indicating success; defaults to 0 if not specified
 	Timeout                  string   `json:"timeout,omitempty" yaml:"timeout,omitempty"`                                                                // Timeout specifies the maximum time the command is allowed to run; if exceeded the command will be terminated, the timeout is a duration like 10s
 	Creates                  string   `json:"creates,omitempty" yaml:"creates,omitempty" template:"deferred"`                                            // Creates specifies a file that the command creates; if this file exists the command will not run
@@ -39,8 +57,54 @@ type ExecResourceProperties struct {
 	RefreshOnly              bool     `json:"refreshonly,omitempty" yaml:"refreshonly,omitempty"`                                                        // RefreshOnly determines whether the command should only run when notified by a subscribed resource
 	Subscribe                []string `json:"subscribe,omitempty" yaml:"subscribe,omitempty"`                                                            // Subscribe specifies resources to subscribe to for refresh notifications in the format "type#name"
 	LogOutput                bool     `json:"logoutput,omitempty" yaml:"logoutput,omitempty"`                                                            // LogOutput determines whether to log the command's output
+	OutputKey                string   `json:"output_key,omitempty" yaml:"output_key,omitempty"`                                                          // OutputKey is the data key the parsed output of a successful command is stored under, making it available to templates of later resources
+	OutputFormat             string   `json:"output_format,omitempty" yaml:"output_format,omitempty"`                                                    // OutputFormat is the format of the output stored in OutputKey, json (default) or kv for key=value lines
+	OutputSensitive          bool     `json:"output_sensitive,omitempty" yaml:"output_sensitive,omitempty"`                                              // OutputSensitive prevents the output stored in OutputKey from being logged
+
+	ParsedTimeout        time.Duration     `json:"-" yaml:"-"` // ParsedTimeout is the parsed duration representation of Timeout, should not be set by callers
+	InheritedEnvironment map[string]string `json:"-" yaml:"-"` // InheritedEnvironment is the manager environment data commands run with, Environment takes precedence
+}
+
+// CommandEnvironment returns the environment commands run with, the inherited environment merged with Environment
+// where variables set in Environment take precedence. Inherited variables the command runner manages, like the
+// search path, locale and the identity of the user, are not inherited
+func (p *ExecResourceProperties) CommandEnvironment() []string {
+	if len(p.InheritedEnvironment) == 0 {
+		return p.Environment
+	}
+
+	set := map[string]bool{}
+	for _, key := range execRunnerEnvironment {
+		set[key] = true
+	}
+	if p.User != "" {
+		for _, key := range execUserEnvironment {
+			set[key] = true
+		}
+	}
+	for _, env := range p.Environment {
+		key, _, _ := strings.Cut(env, "=")
+		set[key] = true
+	}
+
+	var res []string
+	for _, key := range slices.Sorted(maps.Keys(p.InheritedEnvironment)) {
+		if !set[key] {
+			res = append(res, key+"="+p.InheritedEnvironment[key])
+		}
+	}
+
+	return append(res, p.Environment...)
+}
+
+// Subscriptions returns the resources this resource subscribes to for refresh events
+func (p *ExecResourceProperties) Subscriptions() []string {
+	return p.Subscribe
+}
 
-	ParsedTimeout time.Duration `json:"-" yaml:"-"` // ParsedTimeout is the parsed duration representation of Timeout, should not be set by callers
+// AddSubscription subscribes the resource to ref
+func (p *ExecResourceProperties) AddSubscription(ref string) {
+	p.Subscribe = append(p.Subscribe, ref)
 }
 
 // ExecState represents the current state of an execution
@@ -101,6 +165,24 @@ func (p *ExecResourceProperties) Validate() error {
 		}
 	}
 
+	switch p.OutputFormat {
+	case "", ExecOutputFormatJSON, ExecOutputFormatKV:
+	default:
+		return fmt.Errorf("output_format must be one of %q or %q", ExecOutputFormatJSON, ExecOutputFormatKV)
+	}
+
+	if p.OutputKey == "" && (p.OutputFormat != "" || p.OutputSensitive) {
+		return fmt.Errorf("output_format and output_sensitive require output_key")
+	}
+
+	if p.OutputKey != "" && strings.ContainsAny(p.OutputKey, ".[]") {
+		return fmt.Errorf("output_key %q must be a top level data key", p.OutputKey)
+	}
+
+	if strings.ContainsAny(p.User, " \t\r\n:") {
+		return fmt.Errorf("invalid user %q", p.User)
+	}
+
 	for _, env := range p.Environment {
 		key, value, found := strings.Cut(env, "=")
 		if !found {
@@ -117,6 +199,42 @@ func (p *ExecResourceProperties) Validate() error {
 	return nil
 }
 
+// ParseOutput parses the output of the command according to OutputFormat
+func (p *ExecResourceProperties) ParseOutput(stdout []byte) (any, error) {
+	switch p.OutputFormat {
+	case "", ExecOutputFormatJSON:
+		var res any
+		err := json.Unmarshal(stdout, &res)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse output as JSON: %w", err)
+		}
+
+		return res, nil
+
+	case ExecOutputFormatKV:
+		res := map[string]any{}
+		for i, line := range strings.Split(string(stdout), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+
+			key, value, found := strings.Cut(line, "=")
+			key = strings.TrimSpace(key)
+			if !found || key == "" {
+				return nil, fmt.Errorf("could not parse output line %d: expected key=value", i+1)
+			}
+
+			res[key] = strings.TrimSpace(value)
+		}
+
+		return res, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported output_format %q", p.OutputFormat)
+	}
+}
+
 // ResolveTemplates resolves template expressions in the exec resource properties
 func (p *ExecResourceProperties) ResolveTemplates(env *templates.Env) error {
 	err := templates.ResolveStructTemplates(p, env, false)
@@ -208,6 +208,111 @@ var _ = Describe("ExecResourceProperties", func() {
 			Entry("empty value", []string{"FOO="}, "empty value"),
 			Entry("mixed valid and invalid", []string{"FOO=bar", "INVALID"}, "missing '='"),
 		)
+
+		DescribeTable("user validation",
+			func(user string, errorText string) {
+				prop := &ExecResourceProperties{
+					CommonResourceProperties: CommonResourceProperties{
+						Name:   "/bin/echo hello",
+						Ensure: EnsurePresent,
+					},
+					User: user,
+				}
+
+				err := prop.Validate()
+
+				if errorText != "" {
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring(errorText))
+				} else {
+					Expect(err).ToNot(HaveOccurred())
+				}
+			},
+
+			Entry("no user", "", ""),
+			Entry("valid user", "app", ""),
+			Entry("user with space", "app user", "invalid user"),
+			Entry("user with colon", "app:app", "invalid user"),
+		)
+
+		DescribeTable("output validation",
+			func(key string, format string, sensitive bool, errorText string) {
+				prop := &ExecResourceProperties{
+					CommonResourceProperties: CommonResourceProperties{
+						Name:   "/bin/echo hello",
+						Ensure: EnsurePresent,
+					},
+					OutputKey:       key,
+					OutputFormat:    format,
+					OutputSensitive: sensitive,
+				}
+
+				err := prop.Validate()
+
+				if errorText != "" {
+					Expect(err).To(MatchError(ContainSubstring(errorText)))
+				} else {
+					Expect(err).ToNot(HaveOccurred())
+				}
+			},
+
+			Entry("no output key", "", "", false, ""),
+			Entry("output key only", "node", "", false, ""),
+			Entry("json format", "node", "json", true, ""),
+			Entry("kv format", "node", "kv", false, ""),
+			Entry("unknown format", "node", "xml", false, "output_format must be one of"),
+			Entry("format without key", "", "json", false, "require output_key"),
+			Entry("sensitive without key", "", "", true, "require output_key"),
+			Entry("nested key", "node.id", "", false, "must be a top level data key"),
+		)
+	})
+
+	Describe("CommandEnvironment", func() {
+		It("Should return Environment when nothing is inherited", func() {
+			prop := &ExecResourceProperties{Environment: []string{"FOO=bar"}}
+			Expect(prop.CommandEnvironment()).To(Equal([]string{"FOO=bar"}))
+		})
+
+		It("Should merge the inherited environment with Environment taking precedence", func() {
+			prop := &ExecResourceProperties{
+				Environment:          []string{"FOO=resource", "BAZ=qux"},
+				InheritedEnvironment: map[string]string{"FOO": "manager", "ZED": "z", "APP": "a"},
+			}
+
+			Expect(prop.CommandEnvironment()).To(Equal([]string{"APP=a", "ZED=z", "FOO=resource", "BAZ=qux"}))
+		})
+
+		It("Should not inherit variables managed by the runner", func() {
+			inherited := map[string]string{"PATH": "/opt/bin", "LANG": "de_DE", "HOME": "/root", "USER": "root", "APP": "a"}
+
+			prop := &ExecResourceProperties{InheritedEnvironment: inherited}
+			Expect(prop.CommandEnvironment()).To(Equal([]string{"APP=a", "HOME=/root", "USER=root"}))
+
+			prop = &ExecResourceProperties{User: "app", InheritedEnvironment: inherited, Environment: []string{"HOME=/srv/app"}}
+			Expect(prop.CommandEnvironment()).To(Equal([]string{"APP=a", "HOME=/srv/app"}))
+		})
+	})
+
+	Describe("ParseOutput", func() {
+		It("Should parse JSON by default", func() {
+			prop := &ExecResourceProperties{OutputKey: "node"}
+			res, err := prop.ParseOutput([]byte(`{"id":"abc","ports":[80,443]}`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal(map[string]any{"id": "abc", "ports": []any{float64(80), float64(443)}}))
+
+			_, err = prop.ParseOutput([]byte("id=abc"))
+			Expect(err).To(MatchError(ContainSubstring("could not parse output as JSON")))
+		})
+
+		It("Should parse key=value lines", func() {
+			prop := &ExecResourceProperties{OutputKey: "node", OutputFormat: ExecOutputFormatKV}
+			res, err := prop.ParseOutput([]byte("# generated\nid = abc\n\nurl=http://example.net/?a=b\n"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal(map[string]any{"id": "abc", "url": "http://example.net/?a=b"}))
+
+			_, err = prop.ParseOutput([]byte("id=abc\nbogus\n"))
+			Expect(err).To(MatchError("could not parse output line 2: expected key=value"))
+		})
 	})
 
 	Describe("ResolveTemplates", func() {
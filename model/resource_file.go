@@ -5,12 +5,20 @@
 package model
 
 import (
+	"bytes"
+	"encoding/base64"
 	"fmt"
+	"net/url"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/goccy/go-yaml"
+	"github.com/kballard/go-shellquote"
 
+	iu "github.com/choria-io/ccm/internal/util"
 	"github.com/choria-io/ccm/templates"
 )
 
@@ -22,24 +30,89 @@ const (
 	FileTypeName = "file"
 
 	FileEnsureDirectory = "directory"
+
+	// FileContentEncodingPlain indicates content is stored as given
+	FileContentEncodingPlain = "plain"
+	// FileContentEncodingBase64 indicates content is base64 encoded and decoded to raw bytes before storing
+	FileContentEncodingBase64 = "base64"
+
+	// FileContentNormalizationNone compares and stores content exactly as given
+	FileContentNormalizationNone = "none"
+	// FileContentNormalizationLF converts CRLF line endings to LF
+	FileContentNormalizationLF = "lf"
+	// FileContentNormalizationTrimTrailing converts CRLF line endings to LF and removes trailing whitespace from every line
+	FileContentNormalizationTrimTrailing = "trim-trailing"
+
+	// FileValidatePathPlaceholder is replaced in ValidateCommand with the path to a temporary file holding the new content
+	FileValidatePathPlaceholder = "%{path}"
 )
 
+// fileAclQualifierRegex matches the user or group names and ids ACL entries can grant access to
+var fileAclQualifierRegex = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9_.-]*\$?$`)
+
+// DefaultProtectedPaths are paths the file resource never removes, even with force, additional paths
+// can be protected using the manager
+var DefaultProtectedPaths = []string{"/", "/bin", "/boot", "/dev", "/etc", "/home", "/lib", "/lib64", "/opt", "/proc", "/root", "/run", "/sbin", "/srv", "/sys", "/tmp", "/usr", "/var"}
+
 // FileResourceProperties defines the properties for a file resource
 type FileResourceProperties struct {
 	CommonResourceProperties `yaml:",inline"`
-	Contents                 *string `json:"content,omitempty" yaml:"content,omitempty" template:"deferred"` // Contents specifies the desired file contents as a string; mutually exclusive with Source. When nil, file contents are not managed and only owner/group/mode are enforced.
-	Source                   string  `json:"source,omitempty" yaml:"source,omitempty" template:"deferred"`   // Source specifies a local file path to use as the source for the file contents; mutually exclusive with Contents
-	Owner                    string  `json:"owner,omitempty" yaml:"owner,omitempty"`                         // Owner specifies the user that should own the file; required unless ensure is absent
-	Group                    string  `json:"group,omitempty" yaml:"group,omitempty"`                         // Group specifies the group that should own the file; required unless ensure is absent
-	Mode                     string  `json:"mode,omitempty" yaml:"mode,omitempty"`                           // Mode specifies the file permissions in octal notation (e.g., "0644"); required unless ensure is absent
-	Force                    bool    `json:"force,omitempty" yaml:"force,omitempty"`                         // Force allows removal of non-empty directories when Ensure is absent; has no effect on regular files
+	Contents                 *string        `json:"content,omitempty" yaml:"content,omitempty" template:"deferred"`         // Contents specifies the desired file contents as a string; mutually exclusive with Source. When nil, file contents are not managed and only owner/group/mode are enforced.
+	Source                   string         `json:"source,omitempty" yaml:"source,omitempty" template:"deferred"`           // Source specifies a local file path to use as the source for the file contents; mutually exclusive with Contents
+	Sources                  []string       `json:"sources,omitempty" yaml:"sources,omitempty" template:"deferred"`         // Sources are local file paths or http(s) URLs tried in order, the first that resolves is used with Contents as fallback when none do; mutually exclusive with Source
+	ContentEncoding          string         `json:"content_encoding,omitempty" yaml:"content_encoding,omitempty"`           // ContentEncoding is the encoding of Contents, either plain (default) or base64 for binary content
+	ContentNormalization     string         `json:"content_normalization,omitempty" yaml:"content_normalization,omitempty"` // ContentNormalization is applied to both the desired and the current content before comparing them and to content before storing it, none (default), lf or trim-trailing
+	Owner                    string         `json:"owner,omitempty" yaml:"owner,omitempty"`                                 // Owner specifies the user that should own the file; required unless ensure is absent
+	Group                    string         `json:"group,omitempty" yaml:"group,omitempty"`                                 // Group specifies the group that should own the file; required unless ensure is absent
+	Mode                     string         `json:"mode,omitempty" yaml:"mode,omitempty"`                                   // Mode specifies the file permissions in octal notation (e.g., "0644"); required unless ensure is absent
+	Force                    bool           `json:"force,omitempty" yaml:"force,omitempty"`                                 // Force allows removal of non-empty directories when Ensure is absent; has no effect on regular files
+	Recurse                  bool           `json:"recurse,omitempty" yaml:"recurse,omitempty"`                             // Recurse copies the entries of the Source directory into the directory, requires Ensure directory
+	Purge                    bool           `json:"purge,omitempty" yaml:"purge,omitempty"`                                 // Purge removes entries from the directory that are not in the Source directory, requires Recurse
+	ManageParents            bool           `json:"manage_parents,omitempty" yaml:"manage_parents,omitempty"`               // ManageParents creates missing parent directories with ParentOwner, ParentGroup and ParentMode, existing directories are not changed
+	ParentOwner              string         `json:"parent_owner,omitempty" yaml:"parent_owner,omitempty"`                   // ParentOwner is the owner of created parent directories, defaults to Owner
+	ParentGroup              string         `json:"parent_group,omitempty" yaml:"parent_group,omitempty"`                   // ParentGroup is the group of created parent directories, defaults to Group
+	ParentMode               string         `json:"parent_mode,omitempty" yaml:"parent_mode,omitempty"`                     // ParentMode is the mode of created parent directories, defaults to Mode with execute bits added
+	Acl                      []string       `json:"acl,omitempty" yaml:"acl,omitempty"`                                     // Acl are the named user and group POSIX ACL entries like u:deploy:rwx, entries not listed are removed
+	ValidateCommand          string         `json:"validate,omitempty" yaml:"validate,omitempty"`                           // ValidateCommand is run against a temporary file holding new content, %{path} is replaced by its path, the file is not written when it fails
+	Defaults                 map[string]any `json:"defaults,omitempty" yaml:"defaults,omitempty"`                           // Defaults are data values available to content templates when not set in hiera or other data sources
+	ShowDiff                 bool           `json:"show_diff,omitempty" yaml:"show_diff,omitempty"`                         // ShowDiff includes a unified diff of the content change in noop events, never for new content rendered using secret()
+
+	// secretContent indicates templates resolved with ResolveDeferredTemplates used secret()
+	secretContent bool
 }
 
 // ManagesContent reports whether this resource manages the file's contents.
 // When false the resource only enforces owner/group/mode on an existing file
 // and creates an empty file with those attributes if it does not yet exist.
 func (p *FileResourceProperties) ManagesContent() bool {
-	return p.Contents != nil || p.Source != ""
+	return p.Contents != nil || p.Source != "" || len(p.Sources) > 0
+}
+
+// IsUrlSource reports whether source is a http or https URL rather than a local path
+func IsUrlSource(source string) bool {
+	uri, err := url.Parse(source)
+	if err != nil {
+		return false
+	}
+
+	return uri.Scheme == "http" || uri.Scheme == "https"
+}
+
+// ParentAttributes returns the owner, group and mode to use for parent directories created when ManageParents is set
+func (p *FileResourceProperties) ParentAttributes() (owner string, group string, mode string) {
+	owner, group, mode = p.ParentOwner, p.ParentGroup, p.ParentMode
+
+	if owner == "" {
+		owner = p.Owner
+	}
+	if group == "" {
+		group = p.Group
+	}
+	if mode == "" {
+		mode = p.Mode
+	}
+
+	return owner, group, mode
 }
 
 // Content returns the desired file contents as a string, or an empty string
@@ -51,6 +124,126 @@ func (p *FileResourceProperties) Content() string {
 	return *p.Contents
 }
 
+// ContentBytes returns the desired file contents as raw bytes, decoding Contents according to ContentEncoding
+func (p *FileResourceProperties) ContentBytes() ([]byte, error) {
+	if p.Contents == nil {
+		return []byte{}, nil
+	}
+
+	switch p.ContentEncoding {
+	case "", FileContentEncodingPlain:
+		return []byte(*p.Contents), nil
+	case FileContentEncodingBase64:
+		// encoded content is often wrapped over several lines in manifests
+		decoded, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(*p.Contents), ""))
+		if err != nil {
+			return nil, fmt.Errorf("could not decode base64 content: %w", err)
+		}
+
+		return decoded, nil
+	default:
+		return nil, fmt.Errorf("unsupported content_encoding %q", p.ContentEncoding)
+	}
+}
+
+// NormalizesContent reports whether content is normalized before comparing and storing it
+func (p *FileResourceProperties) NormalizesContent() bool {
+	return p.ContentNormalization != "" && p.ContentNormalization != FileContentNormalizationNone
+}
+
+// NormalizeContent normalizes content according to ContentNormalization
+func (p *FileResourceProperties) NormalizeContent(content []byte) []byte {
+	switch p.ContentNormalization {
+	case FileContentNormalizationLF:
+		return bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+
+	case FileContentNormalizationTrimTrailing:
+		lines := bytes.Split(bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n")), []byte("\n"))
+		for i, line := range lines {
+			lines[i] = bytes.TrimRight(line, " \t\r")
+		}
+
+		return bytes.Join(lines, []byte("\n"))
+
+	default:
+		return content
+	}
+}
+
+// ManagesAcl reports whether this resource manages the extended ACL entries of the file
+func (p *FileResourceProperties) ManagesAcl() bool {
+	return len(p.Acl) > 0 && p.Ensure != EnsureAbsent
+}
+
+// NormalizedAcl returns Acl in the canonical form getfacl reports entries in, sorted
+func (p *FileResourceProperties) NormalizedAcl() ([]string, error) {
+	return NormalizeFileAcl(p.Acl)
+}
+
+// NormalizeFileAcl converts named user and group ACL entries like u:deploy:rx or d:g:admins:rwx to the canonical
+// form getfacl reports them in, user:deploy:r-x and default:group:admins:rwx, sorted so sets of entries can be compared
+func NormalizeFileAcl(entries []string) ([]string, error) {
+	var normalized []string
+
+	for _, entry := range entries {
+		n, err := NormalizeFileAclEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		if !slices.Contains(normalized, n) {
+			normalized = append(normalized, n)
+		}
+	}
+
+	slices.Sort(normalized)
+
+	return normalized, nil
+}
+
+// NormalizeFileAclEntry converts a single named user or group ACL entry to the canonical form getfacl reports it in
+func NormalizeFileAclEntry(entry string) (string, error) {
+	parts := strings.Split(strings.TrimSpace(entry), ":")
+
+	prefix := ""
+	if len(parts) == 4 && (parts[0] == "d" || parts[0] == "default") {
+		prefix = "default:"
+		parts = parts[1:]
+	}
+
+	if len(parts) != 3 {
+		return "", fmt.Errorf("invalid acl entry %q, must be in [default:]user|group:name:permissions format", entry)
+	}
+
+	var kind string
+	switch parts[0] {
+	case "u", "user":
+		kind = "user"
+	case "g", "group":
+		kind = "group"
+	default:
+		return "", fmt.Errorf("invalid acl entry %q, only named user and group entries can be managed", entry)
+	}
+
+	if !fileAclQualifierRegex.MatchString(parts[1]) {
+		return "", fmt.Errorf("invalid acl entry %q, a valid user or group name is required", entry)
+	}
+
+	perms := parts[2]
+	if perms == "" || len(perms) > 3 || strings.Trim(perms, "rwx-") != "" {
+		return "", fmt.Errorf("invalid acl entry %q, permissions must be a combination of r, w, x and -", entry)
+	}
+
+	canonical := []byte("---")
+	for i, perm := range "rwx" {
+		if strings.ContainsRune(perms, perm) {
+			canonical[i] = byte(perm)
+		}
+	}
+
+	return fmt.Sprintf("%s%s:%s:%s", prefix, kind, parts[1], canonical), nil
+}
+
 // FileMetadata contains detailed metadata about a file
 type FileMetadata struct {
 	Name     string         `json:"name" yaml:"name"`
@@ -61,6 +254,10 @@ type FileMetadata struct {
 	Provider string         `json:"provider,omitempty" yaml:"provider,omitempty"`
 	MTime    time.Time      `json:"mtime,omitempty" yaml:"mtime,omitempty"`
 	Size     int64          `json:"size,omitempty" yaml:"size,omitempty"`
+	Acl      []string       `json:"acl,omitempty" yaml:"acl,omitempty"`
+	Changed  []string       `json:"changed,omitempty" yaml:"changed,omitempty"` // Changed are the entries of a recursively managed directory that differ from the source, relative to the directory
+	Stable   []string       `json:"stable,omitempty" yaml:"stable,omitempty"`   // Stable are the entries of a recursively managed directory that match the source, relative to the directory
+	Purged   []string       `json:"purged,omitempty" yaml:"purged,omitempty"`   // Purged are the entries of a recursively managed directory that are not in the source, relative to the directory
 	Extended map[string]any `json:"extended,omitempty" yaml:"extended,omitempty"`
 }
 
@@ -96,12 +293,9 @@ func (p *FileResourceProperties) Validate() error {
 		return fmt.Errorf("%w: must be one of %q, %q or %q", ErrInvalidEnsureValue, EnsurePresent, EnsureAbsent, FileEnsureDirectory)
 	}
 
-	if filepath.Clean(p.Name) != p.Name {
-		return fmt.Errorf("file path must be canonical")
-	}
-
-	if !filepath.IsAbs(p.Name) {
-		return fmt.Errorf("file path must be absolute")
+	err = iu.ValidateCanonicalPath("name", p.Name)
+	if err != nil {
+		return err
 	}
 
 	if p.Force {
@@ -113,10 +307,109 @@ func (p *FileResourceProperties) Validate() error {
 		}
 	}
 
+	if p.Recurse {
+		if p.Ensure != FileEnsureDirectory {
+			return fmt.Errorf("'recurse: true' is only valid with 'ensure: directory', got 'ensure: %s'", p.Ensure)
+		}
+		if p.Source == "" {
+			return fmt.Errorf("'recurse: true' requires 'source'")
+		}
+		if p.Contents != nil || len(p.Sources) > 0 {
+			return fmt.Errorf("'content' and 'sources' cannot be used with 'recurse: true'")
+		}
+		if p.NormalizesContent() {
+			return fmt.Errorf("content_normalization cannot be used with 'recurse: true'")
+		}
+	}
+
+	if p.Purge && !p.Recurse {
+		return fmt.Errorf("'purge: true' requires 'recurse: true'")
+	}
+
+	if p.ValidateCommand != "" {
+		if p.Ensure != EnsurePresent || !p.ManagesContent() {
+			return fmt.Errorf("'validate' requires 'ensure: present' and 'content', 'source' or 'sources'")
+		}
+
+		words, err := shellquote.Split(p.ValidateCommand)
+		if err != nil {
+			return fmt.Errorf("invalid validate command: %w", err)
+		}
+		if len(words) == 0 || !strings.Contains(p.ValidateCommand, FileValidatePathPlaceholder) {
+			return fmt.Errorf("validate command must reference the content using %s", FileValidatePathPlaceholder)
+		}
+	}
+
+	if p.ManageParents && p.Ensure == EnsureAbsent {
+		return fmt.Errorf("'manage_parents: true' is not valid with 'ensure: absent'")
+	}
+
+	if !p.ManageParents && (p.ParentOwner != "" || p.ParentGroup != "" || p.ParentMode != "") {
+		return fmt.Errorf("parent_owner, parent_group and parent_mode require 'manage_parents: true'")
+	}
+
 	if p.Contents != nil && p.Source != "" {
 		return fmt.Errorf("'content' and 'source' are mutually exclusive")
 	}
 
+	if p.Source != "" && len(p.Sources) > 0 {
+		return fmt.Errorf("'source' and 'sources' are mutually exclusive")
+	}
+
+	for i, source := range p.Sources {
+		if source == "" {
+			return fmt.Errorf("sources entry %d cannot be empty", i+1)
+		}
+
+		uri, err := url.Parse(source)
+		if err == nil && uri.Scheme != "" && !IsUrlSource(source) && filepath.VolumeName(source) == "" {
+			return fmt.Errorf("sources entry %d: unsupported scheme %q, must be a local path or a http or https URL", i+1, uri.Scheme)
+		}
+	}
+
+	switch p.ContentEncoding {
+	case "", FileContentEncodingPlain:
+	case FileContentEncodingBase64:
+		if p.Contents == nil {
+			return fmt.Errorf("'content_encoding: base64' requires 'content'")
+		}
+
+		// content is resolved later when it holds templates, it is checked again before use
+		if !templates.HasTemplateExpression(*p.Contents) {
+			_, err = p.ContentBytes()
+			if err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("content_encoding must be one of %q or %q", FileContentEncodingPlain, FileContentEncodingBase64)
+	}
+
+	switch p.ContentNormalization {
+	case "", FileContentNormalizationNone:
+	case FileContentNormalizationLF, FileContentNormalizationTrimTrailing:
+		if p.ContentEncoding == FileContentEncodingBase64 {
+			return fmt.Errorf("content_normalization cannot be used with 'content_encoding: base64'")
+		}
+	default:
+		return fmt.Errorf("content_normalization must be one of %q, %q or %q", FileContentNormalizationNone, FileContentNormalizationLF, FileContentNormalizationTrimTrailing)
+	}
+
+	if len(p.Acl) > 0 {
+		if p.Ensure == EnsureAbsent {
+			return fmt.Errorf("acl is not valid with 'ensure: absent'")
+		}
+
+		acl, err := p.NormalizedAcl()
+		if err != nil {
+			return err
+		}
+
+		if p.Ensure != FileEnsureDirectory && slices.ContainsFunc(acl, func(e string) bool { return strings.HasPrefix(e, "default:") }) {
+			return fmt.Errorf("default acl entries require 'ensure: directory'")
+		}
+	}
+
 	// owner/group/mode describe a desired on-disk state and are not
 	// consulted on the removal path, so they are optional when the
 	// resource is being removed.
@@ -148,19 +441,40 @@ func (p *FileResourceProperties) ResolveTemplates(env *templates.Env) error {
 // ResolveDeferredTemplates resolves content and source templates after control evaluation.
 // This allows controls like if/unless to prevent template errors in content when the
 // resource would be skipped.
+//
+// Any Defaults are merged beneath the data in env so templates can rely on those keys being set.
 func (p *FileResourceProperties) ResolveDeferredTemplates(env *templates.Env) error {
+	if len(p.Defaults) > 0 {
+		env = env.WithDefaultData(p.Defaults)
+	}
+
 	err := templates.ResolveStructTemplates(p, env, true)
 	if err != nil {
 		return err
 	}
 
+	p.secretContent = p.secretContent || env.SecretsUsed()
+
 	if p.Source != "" {
 		p.Source = filepath.Clean(p.Source)
 	}
 
+	for i, source := range p.Sources {
+		if source != "" && !IsUrlSource(source) {
+			p.Sources[i] = filepath.Clean(source)
+		}
+	}
+
 	return nil
 }
 
+// ShowsDiff reports if noop events may include a diff of the content, requires ShowDiff and content not rendered
+// using secret(). The current content of the file is not checked, secrets it holds from earlier runs are included
+// in the diff
+func (p *FileResourceProperties) ShowsDiff() bool {
+	return p.ShowDiff && !p.secretContent
+}
+
 // ToYamlManifest returns the file resource properties as a yaml document
 func (p *FileResourceProperties) ToYamlManifest() (yaml.RawMessage, error) {
 	return yaml.Marshal(p)
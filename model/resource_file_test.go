@@ -54,9 +54,11 @@ var _ = Describe("FileResourceProperties", func() {
 
 			// Name validation
 			Entry("empty name", "", "present", "root", "root", "0644", "name"),
-			Entry("path with ..", "/tmp/../etc/passwd", "present", "root", "root", "0644", "canonical"),
-			Entry("path with .", "/tmp/./file.txt", "present", "root", "root", "0644", "canonical"),
-			Entry("relative path", "tmp/test.txt", "present", "root", "root", "0644", "absolute"),
+			Entry("path with ..", "/tmp/../etc/passwd", "present", "root", "root", "0644", "name must be a canonical path"),
+			Entry("path with .", "/tmp/./file.txt", "present", "root", "root", "0644", "name must be a canonical path"),
+			Entry("path with //", "/tmp//file.txt", "present", "root", "root", "0644", "name must be a canonical path"),
+			Entry("path with trailing slash", "/tmp/dir/", "present", "root", "root", "0644", "name must be a canonical path"),
+			Entry("relative path", "tmp/test.txt", "present", "root", "root", "0644", "name must be an absolute path"),
 
 			// Ensure validation
 			Entry("empty ensure", "/tmp/test.txt", "", "root", "root", "0644", "ensure"),
@@ -127,6 +129,202 @@ var _ = Describe("FileResourceProperties", func() {
 			Entry("force with filesystem root is rejected", "/", "absent", true, "'force: true' cannot be used with the filesystem root"),
 		)
 
+		DescribeTable("content validation",
+			func(ensure string, content *string, command string, errorText string) {
+				prop := &FileResourceProperties{
+					CommonResourceProperties: CommonResourceProperties{
+						Name:   "/etc/nginx/nginx.conf",
+						Ensure: ensure,
+					},
+					Owner:           "root",
+					Group:           "root",
+					Mode:            "0644",
+					Contents:        content,
+					ValidateCommand: command,
+				}
+
+				err := prop.Validate()
+
+				if errorText != "" {
+					Expect(err).To(MatchError(ContainSubstring(errorText)))
+				} else {
+					Expect(err).ToNot(HaveOccurred())
+				}
+			},
+
+			Entry("validate with content is valid", "present", stringPtr("events {}"), "nginx -t -c %{path}", ""),
+			Entry("validate without content is rejected", "present", nil, "nginx -t -c %{path}", "'validate' requires 'ensure: present'"),
+			Entry("validate with directory is rejected", "directory", nil, "nginx -t -c %{path}", "'validate' requires 'ensure: present'"),
+			Entry("validate without the path placeholder is rejected", "present", stringPtr("events {}"), "nginx -t", "must reference the content using %{path}"),
+			Entry("validate with unbalanced quotes is rejected", "present", stringPtr("events {}"), "nginx -t -c '%{path}", "invalid validate command"),
+		)
+
+		DescribeTable("recursive directories",
+			func(ensure string, source string, recurse bool, purge bool, errorText string) {
+				prop := &FileResourceProperties{
+					CommonResourceProperties: CommonResourceProperties{
+						Name:   "/tmp/dir",
+						Ensure: ensure,
+					},
+					Owner:   "root",
+					Group:   "root",
+					Mode:    "0644",
+					Source:  source,
+					Recurse: recurse,
+					Purge:   purge,
+				}
+
+				err := prop.Validate()
+
+				if errorText != "" {
+					Expect(err).To(MatchError(ContainSubstring(errorText)))
+				} else {
+					Expect(err).ToNot(HaveOccurred())
+				}
+			},
+
+			Entry("recurse with directory and source is valid", "directory", "/srv/app", true, false, ""),
+			Entry("recurse and purge with directory and source is valid", "directory", "/srv/app", true, true, ""),
+			Entry("recurse with present is rejected", "present", "/srv/app", true, false, "'recurse: true' is only valid with 'ensure: directory'"),
+			Entry("recurse without source is rejected", "directory", "", true, false, "'recurse: true' requires 'source'"),
+			Entry("purge without recurse is rejected", "directory", "/srv/app", false, true, "'purge: true' requires 'recurse: true'"),
+		)
+
+		DescribeTable("parent directories",
+			func(ensure string, manage bool, parentMode string, errorText string) {
+				prop := &FileResourceProperties{
+					CommonResourceProperties: CommonResourceProperties{
+						Name:   "/tmp/dir/test.txt",
+						Ensure: ensure,
+					},
+					Owner:         "root",
+					Group:         "root",
+					Mode:          "0644",
+					ManageParents: manage,
+					ParentMode:    parentMode,
+				}
+
+				err := prop.Validate()
+
+				if errorText != "" {
+					Expect(err).To(MatchError(ContainSubstring(errorText)))
+				} else {
+					Expect(err).ToNot(HaveOccurred())
+				}
+			},
+
+			Entry("manage parents with present is valid", "present", true, "0750", ""),
+			Entry("manage parents with directory is valid", "directory", true, "", ""),
+			Entry("manage parents with absent is rejected", "absent", true, "", "'manage_parents: true' is not valid with 'ensure: absent'"),
+			Entry("parent attributes without manage parents are rejected", "present", false, "0750", "require 'manage_parents: true'"),
+		)
+
+		DescribeTable("content encoding",
+			func(encoding string, contents *string, errorText string) {
+				prop := &FileResourceProperties{
+					CommonResourceProperties: CommonResourceProperties{
+						Name:   "/tmp/test.bin",
+						Ensure: EnsurePresent,
+					},
+					Owner:           "root",
+					Group:           "root",
+					Mode:            "0644",
+					ContentEncoding: encoding,
+					Contents:        contents,
+				}
+
+				err := prop.Validate()
+
+				if errorText != "" {
+					Expect(err).To(MatchError(ContainSubstring(errorText)))
+				} else {
+					Expect(err).ToNot(HaveOccurred())
+				}
+			},
+
+			Entry("plain is valid", "plain", stringPtr("hello"), ""),
+			Entry("valid base64", "base64", stringPtr("aGVsbG8="), ""),
+			Entry("templated base64 is checked later", "base64", stringPtr("{{ lookup('data.key') }}"), ""),
+			Entry("invalid base64", "base64", stringPtr("hello!"), "could not decode base64 content"),
+			Entry("base64 without content", "base64", nil, "'content_encoding: base64' requires 'content'"),
+			Entry("unknown encoding", "hex", stringPtr("00"), "content_encoding must be one of"),
+		)
+
+		DescribeTable("content normalization",
+			func(normalization string, encoding string, errorText string) {
+				prop := &FileResourceProperties{
+					CommonResourceProperties: CommonResourceProperties{
+						Name:   "/tmp/test.txt",
+						Ensure: EnsurePresent,
+					},
+					Owner:                "root",
+					Group:                "root",
+					Mode:                 "0644",
+					Contents:             stringPtr("aGVsbG8="),
+					ContentEncoding:      encoding,
+					ContentNormalization: normalization,
+				}
+
+				err := prop.Validate()
+
+				if errorText != "" {
+					Expect(err).To(MatchError(ContainSubstring(errorText)))
+				} else {
+					Expect(err).ToNot(HaveOccurred())
+				}
+			},
+
+			Entry("none is valid", "none", "", ""),
+			Entry("lf is valid", "lf", "", ""),
+			Entry("trim-trailing is valid", "trim-trailing", "", ""),
+			Entry("none with base64", "none", "base64", ""),
+			Entry("lf with base64", "lf", "base64", "cannot be used with 'content_encoding: base64'"),
+			Entry("unknown normalization", "crlf", "", "content_normalization must be one of"),
+		)
+
+		DescribeTable("acl",
+			func(ensure string, acl []string, errorText string) {
+				prop := &FileResourceProperties{
+					CommonResourceProperties: CommonResourceProperties{
+						Name:   "/srv/shared",
+						Ensure: ensure,
+					},
+					Owner: "root",
+					Group: "root",
+					Mode:  "0750",
+					Acl:   acl,
+				}
+
+				err := prop.Validate()
+
+				if errorText != "" {
+					Expect(err).To(MatchError(ContainSubstring(errorText)))
+				} else {
+					Expect(err).ToNot(HaveOccurred())
+				}
+			},
+
+			Entry("named user and group entries", "present", []string{"u:deploy:rwx", "group:admins:r-x"}, ""),
+			Entry("default entries on a directory", "directory", []string{"d:u:deploy:rwx", "default:g:admins:rx"}, ""),
+			Entry("default entries on a file", "present", []string{"d:u:deploy:rwx"}, "default acl entries require 'ensure: directory'"),
+			Entry("with ensure absent", "absent", []string{"u:deploy:rwx"}, "acl is not valid with 'ensure: absent'"),
+			Entry("base entries", "present", []string{"u::rwx"}, "a valid user or group name is required"),
+			Entry("mask entries", "present", []string{"m::rwx"}, "only named user and group entries can be managed"),
+			Entry("invalid permissions", "present", []string{"u:deploy:rwz"}, "permissions must be a combination of r, w, x and -"),
+			Entry("missing permissions", "present", []string{"u:deploy"}, "must be in [default:]user|group:name:permissions format"),
+		)
+
+		It("Should default parent attributes to the file attributes", func() {
+			prop := &FileResourceProperties{Owner: "app", Group: "app", Mode: "0640"}
+			owner, group, mode := prop.ParentAttributes()
+			Expect([]string{owner, group, mode}).To(Equal([]string{"app", "app", "0640"}))
+
+			prop.ParentOwner = "root"
+			prop.ParentMode = "0755"
+			owner, group, mode = prop.ParentAttributes()
+			Expect([]string{owner, group, mode}).To(Equal([]string{"root", "app", "0755"}))
+		})
+
 		It("Should reject content and source set together", func() {
 			prop := &FileResourceProperties{
 				CommonResourceProperties: CommonResourceProperties{
@@ -142,6 +340,33 @@ var _ = Describe("FileResourceProperties", func() {
 			err := prop.Validate()
 			Expect(err).To(MatchError(ContainSubstring("'content' and 'source' are mutually exclusive")))
 		})
+
+		It("Should validate sources", func() {
+			prop := &FileResourceProperties{
+				CommonResourceProperties: CommonResourceProperties{
+					Name:   "/tmp/test.txt",
+					Ensure: EnsurePresent,
+				},
+				Owner:    "root",
+				Group:    "root",
+				Mode:     "0644",
+				Contents: stringPtr("fallback"),
+				Sources:  []string{"files/motd", "/etc/motd.local", "https://example.net/motd"},
+			}
+			Expect(prop.Validate()).To(Succeed())
+			Expect(prop.ManagesContent()).To(BeTrue())
+
+			prop.Contents = nil
+			prop.Source = "/etc/source"
+			Expect(prop.Validate()).To(MatchError("'source' and 'sources' are mutually exclusive"))
+
+			prop.Source = ""
+			prop.Sources = []string{"/etc/motd", ""}
+			Expect(prop.Validate()).To(MatchError("sources entry 2 cannot be empty"))
+
+			prop.Sources = []string{"ftp://example.net/motd"}
+			Expect(prop.Validate()).To(MatchError(`sources entry 1: unsupported scheme "ftp", must be a local path or a http or https URL`))
+		})
 	})
 
 	DescribeTable("ManagesContent",
@@ -155,6 +380,45 @@ var _ = Describe("FileResourceProperties", func() {
 		Entry("source is set", nil, "/etc/src", true),
 	)
 
+	Describe("ContentBytes", func() {
+		It("Should return plain content as is", func() {
+			prop := &FileResourceProperties{Contents: stringPtr("hello")}
+			Expect(prop.ContentBytes()).To(Equal([]byte("hello")))
+
+			prop.Contents = nil
+			Expect(prop.ContentBytes()).To(BeEmpty())
+		})
+
+		It("Should decode base64 content ignoring line breaks", func() {
+			prop := &FileResourceProperties{ContentEncoding: FileContentEncodingBase64, Contents: stringPtr("AAEC\n/w==\n")}
+			Expect(prop.ContentBytes()).To(Equal([]byte{0x00, 0x01, 0x02, 0xff}))
+		})
+	})
+
+	Describe("NormalizeContent", func() {
+		DescribeTable("normalizing content",
+			func(normalization string, content string, expected string) {
+				prop := &FileResourceProperties{ContentNormalization: normalization}
+				Expect(string(prop.NormalizeContent([]byte(content)))).To(Equal(expected))
+			},
+
+			Entry("none keeps CRLF", "none", "a \r\nb\r\n", "a \r\nb\r\n"),
+			Entry("unset keeps CRLF", "", "a \r\nb\r\n", "a \r\nb\r\n"),
+			Entry("lf converts CRLF", "lf", "a \r\nb\r\n", "a \nb\n"),
+			Entry("lf keeps trailing space", "lf", "a  \nb\t", "a  \nb\t"),
+			Entry("trim-trailing converts CRLF", "trim-trailing", "a\r\nb\r\n", "a\nb\n"),
+			Entry("trim-trailing removes trailing space", "trim-trailing", "a \t\r\n  b  \nc", "a\n  b\nc"),
+		)
+	})
+
+	Describe("NormalizeFileAcl", func() {
+		It("Should normalize, sort and deduplicate entries", func() {
+			acl, err := NormalizeFileAcl([]string{"u:deploy:xr", "g:admins:-", "d:user:deploy:rwx", "user:deploy:r-x"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(acl).To(Equal([]string{"default:user:deploy:rwx", "group:admins:---", "user:deploy:r-x"}))
+		})
+	})
+
 	Describe("Content", func() {
 		It("Should return empty string when content is nil", func() {
 			prop := &FileResourceProperties{}
@@ -369,6 +633,32 @@ content: ""
 			Expect(err).To(HaveOccurred())
 		})
 
+		It("Should make defaults available to content templates", func() {
+			prop := &FileResourceProperties{
+				CommonResourceProperties: CommonResourceProperties{
+					Name:   "/tmp/test.txt",
+					Ensure: EnsurePresent,
+				},
+				Owner:    "root",
+				Group:    "root",
+				Mode:     "0644",
+				Contents: stringPtr("port={{ lookup('data.app.port') }} name={{ lookup('data.app.name') }}"),
+				Defaults: map[string]any{
+					"app": map[string]any{"port": 80, "name": "default"},
+				},
+			}
+
+			env := &templates.Env{Data: map[string]any{"app": map[string]any{"name": "web"}}}
+
+			err := prop.ResolveTemplates(env)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = prop.ResolveDeferredTemplates(env)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(prop.Content()).To(Equal("port=80 name=web"))
+			Expect(env.Data).To(Equal(map[string]any{"app": map[string]any{"name": "web"}}))
+		})
+
 		It("Should resolve source in deferred templates", func() {
 			prop := &FileResourceProperties{
 				CommonResourceProperties: CommonResourceProperties{
@@ -392,6 +682,32 @@ content: ""
 			Expect(prop.Source).To(Equal("/etc/myapp/config"))
 		})
 
+		It("Should not show diffs of content rendered using secrets", func() {
+			prop := &FileResourceProperties{
+				CommonResourceProperties: CommonResourceProperties{
+					Name:   "/tmp/test.txt",
+					Ensure: EnsurePresent,
+				},
+				Owner:    "root",
+				Group:    "root",
+				Mode:     "0644",
+				Contents: stringPtr("password={{ secret('db/password') }}"),
+				ShowDiff: true,
+			}
+			Expect(prop.ShowsDiff()).To(BeTrue())
+
+			env := &templates.Env{SecretProvider: fileTestSecrets{"db/password": "s3cr3t"}}
+
+			err := prop.ResolveDeferredTemplates(env)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(prop.Content()).To(Equal("password=s3cr3t"))
+			Expect(prop.ShowsDiff()).To(BeFalse())
+
+			prop.ShowDiff = false
+			prop.secretContent = false
+			Expect(prop.ShowsDiff()).To(BeFalse())
+		})
+
 		It("Should return error for invalid template in deferred source", func() {
 			prop := &FileResourceProperties{
 				CommonResourceProperties: CommonResourceProperties{
@@ -542,3 +858,10 @@ mode: "0644"
 		})
 	})
 })
+
+// fileTestSecrets is an in-memory secret provider for tests
+type fileTestSecrets map[string]string
+
+func (s fileTestSecrets) Secret(path string) (string, error) {
+	return s[path], nil
+}
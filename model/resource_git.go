@@ -0,0 +1,190 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+
+	iu "github.com/choria-io/ccm/internal/util"
+	"github.com/choria-io/ccm/templates"
+)
+
+const (
+	// ResourceStatusGitProtocol is the protocol identifier for git resource state
+	ResourceStatusGitProtocol = "io.choria.ccm.v1.resource.git.state"
+
+	// GitTypeName is the type name for git resources
+	GitTypeName = "git"
+
+	// GitEnsureLatest keeps the checkout at the newest commit of the revision in the remote repository
+	GitEnsureLatest = "latest"
+)
+
+// gitCommitRegex matches abbreviated and full commit hashes
+var gitCommitRegex = regexp.MustCompile(`^[0-9a-f]{7,64}$`)
+
+// GitResourceProperties defines the properties for a git resource
+type GitResourceProperties struct {
+	CommonResourceProperties `yaml:",inline"`
+	Source                   string `json:"source" yaml:"source"`                                          // Source is the URL of the repository to clone
+	Path                     string `json:"path,omitempty" yaml:"path,omitempty"`                          // Path is the directory to check the repository out into, defaults to the resource name
+	Revision                 string `json:"revision,omitempty" yaml:"revision,omitempty"`                  // Revision is the branch, tag or commit to check out, defaults to the remote HEAD
+	Depth                    int    `json:"depth,omitempty" yaml:"depth,omitempty"`                        // Depth creates a shallow clone with the given number of commits
+	SSHKey                   string `json:"ssh_key,omitempty" yaml:"ssh_key,omitempty"`                    // SSHKey is the path to a private key used for ssh sources
+	Username                 string `json:"username,omitempty" yaml:"username,omitempty"`                  // Username specifies the username to use for basic auth on http sources
+	Password                 string `json:"password,omitempty" yaml:"password,omitempty" sensitive:"true"` // Password specifies the password to use for basic auth on http sources
+}
+
+// GitMetadata contains detailed metadata about a git checkout
+type GitMetadata struct {
+	Path     string `json:"path" yaml:"path"`
+	Source   string `json:"source,omitempty" yaml:"source,omitempty"`
+	Revision string `json:"revision,omitempty" yaml:"revision,omitempty"` // Revision is the commit that is checked out
+	Target   string `json:"target,omitempty" yaml:"target,omitempty"`     // Target is the commit the requested revision resolves to, empty when it is not known
+	Dirty    bool   `json:"dirty,omitempty" yaml:"dirty,omitempty"`       // Dirty indicates tracked files in the checkout were modified
+	Provider string `json:"provider,omitempty" yaml:"provider,omitempty"`
+}
+
+// GitState represents the current state of a git checkout, Ensure holds the checked out commit when present
+type GitState struct {
+	CommonResourceState
+
+	Metadata *GitMetadata `json:"metadata,omitempty"`
+}
+
+func (f *GitState) CommonState() *CommonResourceState {
+	return &f.CommonResourceState
+}
+
+func (p *GitResourceProperties) CommonProperties() *CommonResourceProperties {
+	return &p.CommonResourceProperties
+}
+
+// CheckoutPath is the directory the repository is checked out into
+func (p *GitResourceProperties) CheckoutPath() string {
+	if p.Path != "" {
+		return p.Path
+	}
+
+	return p.Name
+}
+
+// RevisionIsCommit determines if the revision is a, possibly abbreviated, commit hash
+func (p *GitResourceProperties) RevisionIsCommit() bool {
+	return gitCommitRegex.MatchString(p.Revision)
+}
+
+// Validate validates the git resource properties
+func (p *GitResourceProperties) Validate() error {
+	if p.SkipValidate {
+		return nil
+	}
+
+	// First run common validation
+	err := p.CommonResourceProperties.Validate()
+	if err != nil {
+		return err
+	}
+
+	if p.Ensure != EnsurePresent && p.Ensure != EnsureAbsent && p.Ensure != GitEnsureLatest {
+		return fmt.Errorf("%w: must be one of %q, %q or %q", ErrInvalidEnsureValue, EnsurePresent, GitEnsureLatest, EnsureAbsent)
+	}
+
+	err = iu.ValidateCanonicalPath("path", p.CheckoutPath())
+	if err != nil {
+		return err
+	}
+
+	if p.Ensure == EnsureAbsent {
+		return nil
+	}
+
+	if p.Source == "" {
+		return fmt.Errorf("source cannot be empty")
+	}
+
+	if strings.HasPrefix(p.Source, "-") || strings.ContainsAny(p.Source, " \t\r\n") {
+		return fmt.Errorf("invalid source %q", p.Source)
+	}
+
+	if p.Revision != "" && !validGitRevision(p.Revision) {
+		return fmt.Errorf("invalid revision %q", p.Revision)
+	}
+
+	if p.Depth < 0 {
+		return fmt.Errorf("depth cannot be negative")
+	}
+
+	if p.SSHKey != "" && !filepath.IsAbs(p.SSHKey) {
+		return fmt.Errorf("ssh_key %q must be an absolute path", p.SSHKey)
+	}
+
+	if p.Password != "" && p.Username == "" {
+		return fmt.Errorf("password requires username to be set")
+	}
+
+	return nil
+}
+
+// validGitRevision checks revision against the rules git applies to reference names, revisions can not be used
+// as command line options or contain revision range syntax
+func validGitRevision(revision string) bool {
+	if strings.HasPrefix(revision, "-") || strings.HasPrefix(revision, "/") || strings.HasSuffix(revision, "/") {
+		return false
+	}
+
+	if strings.HasSuffix(revision, ".") || strings.HasSuffix(revision, ".lock") {
+		return false
+	}
+
+	if strings.Contains(revision, "..") || strings.Contains(revision, "//") || strings.Contains(revision, "@{") {
+		return false
+	}
+
+	for _, r := range revision {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune(" ~^:?*[\\", r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ResolveTemplates resolves template expressions in the git resource properties
+func (p *GitResourceProperties) ResolveTemplates(env *templates.Env) error {
+	err := templates.ResolveStructTemplates(p, env, false)
+	if err != nil {
+		return err
+	}
+
+	return p.resolveRegistrations(env)
+}
+
+// ToYamlManifest returns the git resource properties as a yaml document
+func (p *GitResourceProperties) ToYamlManifest() (yaml.RawMessage, error) {
+	return yaml.Marshal(p)
+}
+
+// NewGitResourcePropertiesFromYaml creates a new git resource properties object from a yaml document, does not validate or expand templates
+func NewGitResourcePropertiesFromYaml(raw yaml.RawMessage) ([]ResourceProperties, error) {
+	res, err := parseProperties(raw, GitTypeName, func() ResourceProperties { return &GitResourceProperties{} })
+	if err != nil {
+		return nil, err
+	}
+
+	for _, prop := range res {
+		p := prop.(*GitResourceProperties)
+		if p.Ensure == "" {
+			p.Ensure = EnsurePresent
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GitResourceProperties", func() {
+	Describe("Validate", func() {
+		DescribeTable("validation tests",
+			func(prop GitResourceProperties, errorText string) {
+				err := prop.Validate()
+
+				if errorText != "" {
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring(errorText))
+				} else {
+					Expect(err).ToNot(HaveOccurred())
+				}
+			},
+
+			Entry("valid checkout", GitResourceProperties{
+				CommonResourceProperties: CommonResourceProperties{Name: "/srv/app", Ensure: EnsurePresent},
+				Source:                   "https://git.example.net/app.git",
+			}, ""),
+			Entry("valid latest branch", GitResourceProperties{
+				CommonResourceProperties: CommonResourceProperties{Name: "/srv/app", Ensure: GitEnsureLatest},
+				Source:                   "git@git.example.net:app.git",
+				Revision:                 "release/1.x",
+				Depth:                    1,
+				SSHKey:                   "/etc/ccm/deploy_key",
+			}, ""),
+			Entry("valid path", GitResourceProperties{
+				CommonResourceProperties: CommonResourceProperties{Name: "app", Ensure: EnsurePresent},
+				Source:                   "https://git.example.net/app.git",
+				Path:                     "/srv/app",
+			}, ""),
+			Entry("valid absent without source", GitResourceProperties{
+				CommonResourceProperties: CommonResourceProperties{Name: "/srv/app", Ensure: EnsureAbsent},
+			}, ""),
+
+			Entry("invalid ensure", GitResourceProperties{
+				CommonResourceProperties: CommonResourceProperties{Name: "/srv/app", Ensure: "running"},
+				Source:                   "https://git.example.net/app.git",
+			}, "invalid ensure value"),
+			Entry("relative path", GitResourceProperties{
+				CommonResourceProperties: CommonResourceProperties{Name: "app", Ensure: EnsurePresent},
+				Source:                   "https://git.example.net/app.git",
+			}, "path must be an absolute path"),
+			Entry("missing source", GitResourceProperties{
+				CommonResourceProperties: CommonResourceProperties{Name: "/srv/app", Ensure: EnsurePresent},
+			}, "source cannot be empty"),
+			Entry("option source", GitResourceProperties{
+				CommonResourceProperties: CommonResourceProperties{Name: "/srv/app", Ensure: EnsurePresent},
+				Source:                   "--upload-pack=touch",
+			}, "invalid source"),
+			Entry("option revision", GitResourceProperties{
+				CommonResourceProperties: CommonResourceProperties{Name: "/srv/app", Ensure: EnsurePresent},
+				Source:                   "https://git.example.net/app.git",
+				Revision:                 "--force",
+			}, `invalid revision "--force"`),
+			Entry("range revision", GitResourceProperties{
+				CommonResourceProperties: CommonResourceProperties{Name: "/srv/app", Ensure: EnsurePresent},
+				Source:                   "https://git.example.net/app.git",
+				Revision:                 "main..dev",
+			}, "invalid revision"),
+			Entry("negative depth", GitResourceProperties{
+				CommonResourceProperties: CommonResourceProperties{Name: "/srv/app", Ensure: EnsurePresent},
+				Source:                   "https://git.example.net/app.git",
+				Depth:                    -1,
+			}, "depth cannot be negative"),
+			Entry("relative ssh key", GitResourceProperties{
+				CommonResourceProperties: CommonResourceProperties{Name: "/srv/app", Ensure: EnsurePresent},
+				Source:                   "git@git.example.net:app.git",
+				SSHKey:                   "deploy_key",
+			}, "must be an absolute path"),
+			Entry("password without username", GitResourceProperties{
+				CommonResourceProperties: CommonResourceProperties{Name: "/srv/app", Ensure: EnsurePresent},
+				Source:                   "https://git.example.net/app.git",
+				Password:                 "secret",
+			}, "password requires username"),
+		)
+	})
+
+	Describe("RevisionIsCommit", func() {
+		It("Should detect commit hashes", func() {
+			Expect((&GitResourceProperties{Revision: "a1b2c3d"}).RevisionIsCommit()).To(BeTrue())
+			Expect((&GitResourceProperties{Revision: "2f13fcfd00e31ceaf2683788dfd164b16cb08f67"}).RevisionIsCommit()).To(BeTrue())
+			Expect((&GitResourceProperties{Revision: "main"}).RevisionIsCommit()).To(BeFalse())
+			Expect((&GitResourceProperties{Revision: "abc"}).RevisionIsCommit()).To(BeFalse())
+			Expect((&GitResourceProperties{}).RevisionIsCommit()).To(BeFalse())
+		})
+	})
+
+	Describe("NewGitResourcePropertiesFromYaml", func() {
+		It("Should default ensure to present", func() {
+			res, err := NewGitResourcePropertiesFromYaml([]byte(`- /srv/app:
+    source: https://git.example.net/app.git
+    revision: v1.2.0`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(HaveLen(1))
+
+			prop := res[0].(*GitResourceProperties)
+			Expect(prop.Ensure).To(Equal(EnsurePresent))
+			Expect(prop.Source).To(Equal("https://git.example.net/app.git"))
+			Expect(prop.Revision).To(Equal("v1.2.0"))
+			Expect(prop.CheckoutPath()).To(Equal("/srv/app"))
+		})
+	})
+})
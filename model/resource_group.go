@@ -0,0 +1,127 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/choria-io/ccm/templates"
+)
+
+const (
+	// ResourceStatusGroupProtocol is the protocol identifier for group resource state
+	ResourceStatusGroupProtocol = "io.choria.ccm.v1.resource.group.state"
+
+	// GroupTypeName is the type name for group resources
+	GroupTypeName = "group"
+)
+
+// groupNameRegex matches group and user names accepted by the shadow utilities
+var groupNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.-]{0,31}\$?$`)
+
+// GroupResourceProperties defines the properties for a group resource
+type GroupResourceProperties struct {
+	CommonResourceProperties `yaml:",inline"`
+	GID                      int      `json:"gid,omitempty" yaml:"gid,omitempty"`         // GID is the group id, chosen by the system when not set
+	System                   bool     `json:"system,omitempty" yaml:"system,omitempty"`   // System creates a system group when GID is not set, only used when creating the group
+	Members                  []string `json:"members,omitempty" yaml:"members,omitempty"` // Members are the exact members of the group, membership is not managed when empty
+}
+
+// GroupMetadata contains detailed metadata about a group
+type GroupMetadata struct {
+	Name     string   `json:"name" yaml:"name"`
+	GID      int      `json:"gid,omitempty" yaml:"gid,omitempty"`
+	Members  []string `json:"members,omitempty" yaml:"members,omitempty"`
+	Provider string   `json:"provider,omitempty" yaml:"provider,omitempty"`
+}
+
+// GroupState represents the current state of a group
+type GroupState struct {
+	CommonResourceState
+
+	Metadata *GroupMetadata `json:"metadata,omitempty"`
+}
+
+func (f *GroupState) CommonState() *CommonResourceState {
+	return &f.CommonResourceState
+}
+
+func (p *GroupResourceProperties) CommonProperties() *CommonResourceProperties {
+	return &p.CommonResourceProperties
+}
+
+// SortedMembers returns the members sorted and without duplicates
+func (p *GroupResourceProperties) SortedMembers() []string {
+	return slices.Compact(slices.Sorted(slices.Values(p.Members)))
+}
+
+// Validate validates the group resource properties
+func (p *GroupResourceProperties) Validate() error {
+	if p.SkipValidate {
+		return nil
+	}
+
+	// First run common validation
+	err := p.CommonResourceProperties.Validate()
+	if err != nil {
+		return err
+	}
+
+	if p.Ensure != EnsurePresent && p.Ensure != EnsureAbsent {
+		return fmt.Errorf("%w: must be one of %q or %q", ErrInvalidEnsureValue, EnsurePresent, EnsureAbsent)
+	}
+
+	if !groupNameRegex.MatchString(p.Name) {
+		return fmt.Errorf("invalid group name %q", p.Name)
+	}
+
+	if p.GID < 0 {
+		return fmt.Errorf("gid cannot be negative")
+	}
+
+	for _, member := range p.Members {
+		if !groupNameRegex.MatchString(member) {
+			return fmt.Errorf("invalid member %q", member)
+		}
+	}
+
+	return nil
+}
+
+// ResolveTemplates resolves template expressions in the group resource properties
+func (p *GroupResourceProperties) ResolveTemplates(env *templates.Env) error {
+	err := templates.ResolveStructTemplates(p, env, false)
+	if err != nil {
+		return err
+	}
+
+	return p.resolveRegistrations(env)
+}
+
+// ToYamlManifest returns the group resource properties as a yaml document
+func (p *GroupResourceProperties) ToYamlManifest() (yaml.RawMessage, error) {
+	return yaml.Marshal(p)
+}
+
+// NewGroupResourcePropertiesFromYaml creates a new group resource properties object from a yaml document, does not validate or expand templates
+func NewGroupResourcePropertiesFromYaml(raw yaml.RawMessage) ([]ResourceProperties, error) {
+	res, err := parseProperties(raw, GroupTypeName, func() ResourceProperties { return &GroupResourceProperties{} })
+	if err != nil {
+		return nil, err
+	}
+
+	for _, prop := range res {
+		p := prop.(*GroupResourceProperties)
+		if p.Ensure == "" {
+			p.Ensure = EnsurePresent
+		}
+	}
+
+	return res, nil
+}
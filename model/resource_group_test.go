@@ -0,0 +1,67 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GroupResourceProperties", func() {
+	Describe("Validate", func() {
+		DescribeTable("validation tests",
+			func(name, ensure string, gid int, members []string, errorText string) {
+				prop := &GroupResourceProperties{
+					CommonResourceProperties: CommonResourceProperties{
+						Name:   name,
+						Ensure: ensure,
+					},
+					GID:     gid,
+					Members: members,
+				}
+
+				err := prop.Validate()
+
+				if errorText != "" {
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring(errorText))
+				} else {
+					Expect(err).ToNot(HaveOccurred())
+				}
+			},
+
+			Entry("valid group", "app", "present", 0, nil, ""),
+			Entry("valid gid and members", "app", "present", 1500, []string{"alice", "bob"}, ""),
+			Entry("valid absent", "app", "absent", 0, nil, ""),
+			Entry("valid machine account member", "app", "present", 0, []string{"host$"}, ""),
+
+			Entry("invalid ensure", "app", "running", 0, nil, "invalid ensure value"),
+			Entry("invalid name", "1app", "present", 0, nil, `invalid group name "1app"`),
+			Entry("negative gid", "app", "present", -1, nil, "gid cannot be negative"),
+			Entry("invalid member", "app", "present", 0, []string{"bad:member"}, `invalid member "bad:member"`),
+		)
+	})
+
+	Describe("SortedMembers", func() {
+		It("Should sort and remove duplicates", func() {
+			prop := &GroupResourceProperties{Members: []string{"bob", "alice", "bob"}}
+			Expect(prop.SortedMembers()).To(Equal([]string{"alice", "bob"}))
+			Expect(prop.Members).To(Equal([]string{"bob", "alice", "bob"}))
+		})
+	})
+
+	Describe("NewGroupResourcePropertiesFromYaml", func() {
+		It("Should default ensure to present", func() {
+			res, err := NewGroupResourcePropertiesFromYaml([]byte(`- app:
+    gid: 1500
+    members: [alice, bob]`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(HaveLen(1))
+			Expect(res[0].CommonProperties().Ensure).To(Equal(EnsurePresent))
+			Expect(res[0].(*GroupResourceProperties).GID).To(Equal(1500))
+			Expect(res[0].(*GroupResourceProperties).Members).To(Equal([]string{"alice", "bob"}))
+		})
+	})
+})
@@ -0,0 +1,154 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/choria-io/ccm/templates"
+)
+
+const (
+	// ResourceStatusHostProtocol is the protocol identifier for host resource state
+	ResourceStatusHostProtocol = "io.choria.ccm.v1.resource.host.state"
+
+	// HostTypeName is the type name for host resources
+	HostTypeName = "host"
+)
+
+var hostnameRegex = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*\.?$`)
+
+// HostResourceProperties defines the properties for a host resource
+type HostResourceProperties struct {
+	CommonResourceProperties `yaml:",inline"`
+	IP                       string   `json:"ip,omitempty" yaml:"ip,omitempty"`             // IP is the address the hostname resolves to
+	Hostname                 string   `json:"hostname,omitempty" yaml:"hostname,omitempty"` // Hostname is the canonical name of the entry, defaults to the name
+	Aliases                  []string `json:"aliases,omitempty" yaml:"aliases,omitempty"`   // Aliases are additional names for the address
+}
+
+// HostMetadata contains detailed metadata about a hosts file entry
+type HostMetadata struct {
+	Name     string   `json:"name" yaml:"name"`
+	File     string   `json:"file,omitempty" yaml:"file,omitempty"`
+	IP       string   `json:"ip,omitempty" yaml:"ip,omitempty"`
+	Hostname string   `json:"hostname,omitempty" yaml:"hostname,omitempty"`
+	Aliases  []string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	Matches  int      `json:"matches,omitempty" yaml:"matches,omitempty"` // Matches is the number of entries in the file for the hostname
+	Provider string   `json:"provider,omitempty" yaml:"provider,omitempty"`
+}
+
+// HostState represents the current state of a hosts file entry
+type HostState struct {
+	CommonResourceState
+
+	Metadata *HostMetadata `json:"metadata,omitempty"`
+}
+
+func (f *HostState) CommonState() *CommonResourceState {
+	return &f.CommonResourceState
+}
+
+func (p *HostResourceProperties) CommonProperties() *CommonResourceProperties {
+	return &p.CommonResourceProperties
+}
+
+// ManagedHostname is the hostname identifying the entry, the name when no hostname is set
+func (p *HostResourceProperties) ManagedHostname() string {
+	if p.Hostname != "" {
+		return p.Hostname
+	}
+
+	return p.Name
+}
+
+// HostsLine renders the entry as a line for a hosts file, without a trailing newline
+func (p *HostResourceProperties) HostsLine() string {
+	return strings.Join(append([]string{p.IP, p.ManagedHostname()}, p.Aliases...), " ")
+}
+
+// IsValidHostname determines if name is a valid hostname as described in RFC 1123
+func IsValidHostname(name string) bool {
+	return len(name) <= 253 && hostnameRegex.MatchString(name)
+}
+
+// Validate validates the host resource properties
+func (p *HostResourceProperties) Validate() error {
+	if p.SkipValidate {
+		return nil
+	}
+
+	// First run common validation
+	err := p.CommonResourceProperties.Validate()
+	if err != nil {
+		return err
+	}
+
+	if p.Ensure != EnsurePresent && p.Ensure != EnsureAbsent {
+		return fmt.Errorf("%w: must be one of %q or %q", ErrInvalidEnsureValue, EnsurePresent, EnsureAbsent)
+	}
+
+	hostname := p.ManagedHostname()
+	if !IsValidHostname(hostname) {
+		return fmt.Errorf("invalid hostname %q", hostname)
+	}
+
+	for i, alias := range p.Aliases {
+		if !IsValidHostname(alias) {
+			return fmt.Errorf("invalid alias %q", alias)
+		}
+
+		if alias == hostname || slices.Contains(p.Aliases[:i], alias) {
+			return fmt.Errorf("duplicate alias %q", alias)
+		}
+	}
+
+	if p.IP != "" && net.ParseIP(p.IP) == nil {
+		return fmt.Errorf("invalid ip %q", p.IP)
+	}
+
+	if p.Ensure == EnsurePresent && p.IP == "" {
+		return fmt.Errorf("ip is required when ensure is %q", EnsurePresent)
+	}
+
+	return nil
+}
+
+// ResolveTemplates resolves template expressions in the host resource properties
+func (p *HostResourceProperties) ResolveTemplates(env *templates.Env) error {
+	err := templates.ResolveStructTemplates(p, env, false)
+	if err != nil {
+		return err
+	}
+
+	return p.resolveRegistrations(env)
+}
+
+// ToYamlManifest returns the host resource properties as a yaml document
+func (p *HostResourceProperties) ToYamlManifest() (yaml.RawMessage, error) {
+	return yaml.Marshal(p)
+}
+
+// NewHostResourcePropertiesFromYaml creates a new host resource properties object from a yaml document, does not validate or expand templates
+func NewHostResourcePropertiesFromYaml(raw yaml.RawMessage) ([]ResourceProperties, error) {
+	res, err := parseProperties(raw, HostTypeName, func() ResourceProperties { return &HostResourceProperties{} })
+	if err != nil {
+		return nil, err
+	}
+
+	for _, prop := range res {
+		p := prop.(*HostResourceProperties)
+		if p.Ensure == "" {
+			p.Ensure = EnsurePresent
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HostResourceProperties", func() {
+	Describe("Validate", func() {
+		DescribeTable("validation tests",
+			func(ensure, hostname, ip string, aliases []string, errorText string) {
+				prop := &HostResourceProperties{
+					CommonResourceProperties: CommonResourceProperties{
+						Name:   "db.example.net",
+						Ensure: ensure,
+					},
+					Hostname: hostname,
+					IP:       ip,
+					Aliases:  aliases,
+				}
+
+				err := prop.Validate()
+
+				if errorText != "" {
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring(errorText))
+				} else {
+					Expect(err).ToNot(HaveOccurred())
+				}
+			},
+
+			Entry("valid entry", "present", "", "192.168.1.10", []string{"db"}, ""),
+			Entry("valid ipv6 entry", "present", "db6.example.net", "fd00::10", nil, ""),
+			Entry("valid absent without ip", "absent", "", "", nil, ""),
+
+			Entry("invalid ensure", "running", "", "192.168.1.10", nil, "invalid ensure value"),
+			Entry("invalid hostname", "present", "db_1.example.net", "192.168.1.10", nil, `invalid hostname "db_1.example.net"`),
+			Entry("invalid alias", "present", "", "192.168.1.10", []string{"db server"}, `invalid alias "db server"`),
+			Entry("alias matching hostname", "present", "", "192.168.1.10", []string{"db.example.net"}, "duplicate alias"),
+			Entry("duplicate alias", "present", "", "192.168.1.10", []string{"db", "db"}, "duplicate alias"),
+			Entry("invalid ip", "present", "", "192.168.1", nil, `invalid ip "192.168.1"`),
+			Entry("missing ip", "present", "", "", nil, "ip is required"),
+		)
+	})
+
+	Describe("HostsLine", func() {
+		It("Should render the entry with the name as hostname", func() {
+			prop := &HostResourceProperties{
+				CommonResourceProperties: CommonResourceProperties{Name: "db.example.net"},
+				IP:                       "192.168.1.10",
+				Aliases:                  []string{"db", "mysql"},
+			}
+			Expect(prop.HostsLine()).To(Equal("192.168.1.10 db.example.net db mysql"))
+
+			prop.Hostname = "db1.example.net"
+			prop.Aliases = nil
+			Expect(prop.HostsLine()).To(Equal("192.168.1.10 db1.example.net"))
+		})
+	})
+
+	Describe("NewHostResourcePropertiesFromYaml", func() {
+		It("Should default ensure to present", func() {
+			res, err := NewHostResourcePropertiesFromYaml([]byte(`- db.example.net:
+    ip: 192.168.1.10
+    aliases: [db]`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(HaveLen(1))
+			Expect(res[0].CommonProperties().Ensure).To(Equal(EnsurePresent))
+			Expect(res[0].(*HostResourceProperties).Aliases).To(Equal([]string{"db"}))
+		})
+	})
+})
@@ -0,0 +1,180 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+
+	iu "github.com/choria-io/ccm/internal/util"
+	"github.com/choria-io/ccm/templates"
+)
+
+const (
+	// ResourceStatusJsonEditProtocol is the protocol identifier for jsonedit resource state
+	ResourceStatusJsonEditProtocol = "io.choria.ccm.v1.resource.jsonedit.state"
+
+	// JsonEditTypeName is the type name for jsonedit resources
+	JsonEditTypeName = "jsonedit"
+
+	// JsonEditFormatJSON edits documents stored as JSON
+	JsonEditFormatJSON = "json"
+	// JsonEditFormatYAML edits documents stored as YAML
+	JsonEditFormatYAML = "yaml"
+)
+
+// JsonEditResourceProperties defines the properties for a jsonedit resource
+type JsonEditResourceProperties struct {
+	CommonResourceProperties `yaml:",inline"`
+	Path                     string `json:"path" yaml:"path"`                         // Path is the dot separated location of the value within the document, for example "server.tls.enabled"
+	Value                    any    `json:"value,omitempty" yaml:"value,omitempty"`   // Value is the value to store at Path, can be a scalar, list or map; not used when ensure is absent
+	Format                   string `json:"format,omitempty" yaml:"format,omitempty"` // Format is the document format, json or yaml; determined from the file extension when not set
+}
+
+// JsonEditMetadata contains detailed metadata about an edited document
+type JsonEditMetadata struct {
+	Name       string `json:"name" yaml:"name"`
+	Path       string `json:"path" yaml:"path"`
+	Format     string `json:"format" yaml:"format"`
+	FileExists bool   `json:"file_exists" yaml:"file_exists"`
+	PathExists bool   `json:"path_exists" yaml:"path_exists"`
+	Value      any    `json:"value,omitempty" yaml:"value,omitempty"`
+	Checksum   string `json:"checksum,omitempty" yaml:"checksum,omitempty"`
+	Provider   string `json:"provider,omitempty" yaml:"provider,omitempty"`
+}
+
+// JsonEditState represents the current state of a value within a document
+type JsonEditState struct {
+	CommonResourceState
+
+	Metadata *JsonEditMetadata `json:"metadata,omitempty"`
+}
+
+func (f *JsonEditState) CommonState() *CommonResourceState {
+	return &f.CommonResourceState
+}
+
+func (p *JsonEditResourceProperties) CommonProperties() *CommonResourceProperties {
+	return &p.CommonResourceProperties
+}
+
+// DocumentFormat returns the format of the document, either as set in Format or based on the file extension
+func (p *JsonEditResourceProperties) DocumentFormat() (string, error) {
+	switch {
+	case p.Format != "":
+		return p.Format, nil
+	case iu.FileHasSuffix(p.Name, ".json"):
+		return JsonEditFormatJSON, nil
+	case iu.FileHasSuffix(p.Name, ".yaml", ".yml"):
+		return JsonEditFormatYAML, nil
+	default:
+		return "", fmt.Errorf("cannot determine document format from %q, set format to %q or %q", filepath.Base(p.Name), JsonEditFormatJSON, JsonEditFormatYAML)
+	}
+}
+
+// PathSegments splits Path into its components, a literal dot in a key can be escaped as `\.`
+func (p *JsonEditResourceProperties) PathSegments() []string {
+	var (
+		segments []string
+		current  strings.Builder
+	)
+
+	for i := 0; i < len(p.Path); i++ {
+		switch {
+		case p.Path[i] == '\\' && i+1 < len(p.Path) && p.Path[i+1] == '.':
+			current.WriteByte('.')
+			i++
+		case p.Path[i] == '.':
+			segments = append(segments, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(p.Path[i])
+		}
+	}
+
+	return append(segments, current.String())
+}
+
+// Validate validates the jsonedit resource properties
+func (p *JsonEditResourceProperties) Validate() error {
+	if p.SkipValidate {
+		return nil
+	}
+
+	// First run common validation
+	err := p.CommonResourceProperties.Validate()
+	if err != nil {
+		return err
+	}
+
+	if p.Ensure != EnsurePresent && p.Ensure != EnsureAbsent {
+		return fmt.Errorf("%w: must be one of %q or %q", ErrInvalidEnsureValue, EnsurePresent, EnsureAbsent)
+	}
+
+	err = iu.ValidateCanonicalPath("name", p.Name)
+	if err != nil {
+		return err
+	}
+
+	if p.Format != "" && p.Format != JsonEditFormatJSON && p.Format != JsonEditFormatYAML {
+		return fmt.Errorf("format must be one of %q or %q", JsonEditFormatJSON, JsonEditFormatYAML)
+	}
+
+	_, err = p.DocumentFormat()
+	if err != nil {
+		return err
+	}
+
+	if p.Path == "" {
+		return fmt.Errorf("path cannot be empty")
+	}
+
+	for _, segment := range p.PathSegments() {
+		if segment == "" {
+			return fmt.Errorf("path %q contains an empty segment", p.Path)
+		}
+	}
+
+	if p.Ensure == EnsurePresent && p.Value == nil {
+		return fmt.Errorf("value is required when ensure is %q", EnsurePresent)
+	}
+
+	return nil
+}
+
+// ResolveTemplates resolves template expressions in the jsonedit resource properties
+func (p *JsonEditResourceProperties) ResolveTemplates(env *templates.Env) error {
+	err := templates.ResolveStructTemplates(p, env, false)
+	if err != nil {
+		return err
+	}
+
+	return p.resolveRegistrations(env)
+}
+
+// ToYamlManifest returns the jsonedit resource properties as a yaml document
+func (p *JsonEditResourceProperties) ToYamlManifest() (yaml.RawMessage, error) {
+	return yaml.Marshal(p)
+}
+
+// NewJsonEditResourcePropertiesFromYaml creates a new jsonedit resource properties object from a yaml document, does not validate or expand templates
+func NewJsonEditResourcePropertiesFromYaml(raw yaml.RawMessage) ([]ResourceProperties, error) {
+	res, err := parseProperties(raw, JsonEditTypeName, func() ResourceProperties { return &JsonEditResourceProperties{} })
+	if err != nil {
+		return nil, err
+	}
+
+	for _, prop := range res {
+		p := prop.(*JsonEditResourceProperties)
+		if p.Ensure == "" {
+			p.Ensure = EnsurePresent
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JsonEditResourceProperties", func() {
+	Describe("Validate", func() {
+		DescribeTable("validation tests",
+			func(name, ensure, path string, value any, format, errorText string) {
+				prop := &JsonEditResourceProperties{
+					CommonResourceProperties: CommonResourceProperties{
+						Name:   name,
+						Ensure: ensure,
+					},
+					Path:   path,
+					Value:  value,
+					Format: format,
+				}
+
+				err := prop.Validate()
+
+				if errorText != "" {
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring(errorText))
+				} else {
+					Expect(err).ToNot(HaveOccurred())
+				}
+			},
+
+			Entry("valid json file", "/etc/app.json", "present", "server.port", 80, "", ""),
+			Entry("valid yaml file", "/etc/app.yml", "present", "server.port", 80, "", ""),
+			Entry("valid explicit format", "/etc/app.conf", "present", "server.port", 80, "yaml", ""),
+			Entry("valid absent without value", "/etc/app.json", "absent", "server.port", nil, "", ""),
+
+			Entry("invalid ensure", "/etc/app.json", "running", "server.port", 80, "", "invalid ensure value"),
+			Entry("relative file", "etc/app.json", "present", "server.port", 80, "", "name must be an absolute path"),
+			Entry("non canonical file", "/etc/../app.json", "present", "server.port", 80, "", "name must be a canonical path"),
+			Entry("file with //", "/etc//app.json", "present", "server.port", 80, "", "name must be a canonical path"),
+			Entry("file with trailing slash", "/etc/app.json/", "present", "server.port", 80, "", "name must be a canonical path"),
+			Entry("unknown format", "/etc/app.json", "present", "server.port", 80, "toml", "format must be one of"),
+			Entry("undetermined format", "/etc/app.conf", "present", "server.port", 80, "", "cannot determine document format"),
+			Entry("empty path", "/etc/app.json", "present", "", 80, "", "path cannot be empty"),
+			Entry("empty path segment", "/etc/app.json", "present", "server..port", 80, "", "contains an empty segment"),
+			Entry("missing value", "/etc/app.json", "present", "server.port", nil, "", "value is required"),
+		)
+	})
+
+	Describe("NewJsonEditResourcePropertiesFromYaml", func() {
+		It("Should default ensure to present", func() {
+			res, err := NewJsonEditResourcePropertiesFromYaml([]byte(`- /etc/app.json:
+    path: server.port
+    value: 80`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(HaveLen(1))
+			Expect(res[0].CommonProperties().Ensure).To(Equal(EnsurePresent))
+		})
+	})
+
+	Describe("PathSegments", func() {
+		It("Should split on dots and support escaped dots", func() {
+			prop := &JsonEditResourceProperties{Path: `hosts.www\.example\.net.ip`}
+			Expect(prop.PathSegments()).To(Equal([]string{"hosts", "www.example.net", "ip"}))
+		})
+	})
+})
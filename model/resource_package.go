@@ -7,6 +7,7 @@ package model
 import (
 	"fmt"
 	"regexp"
+	"slices"
 
 	"github.com/goccy/go-yaml"
 
@@ -21,6 +22,12 @@ const (
 	PackageTypeName = "package"
 
 	PackageEnsureLatest = "latest"
+
+	// PackageActionAutoremove is the action recorded when unused dependencies were removed after uninstalling a package
+	PackageActionAutoremove = "autoremove"
+
+	// PackageActionCleanCache is the action recorded when the package manager cache was cleaned after a change
+	PackageActionCleanCache = "clean_cache"
 )
 
 var (
@@ -35,6 +42,10 @@ var (
 // PackageResourceProperties defines the properties for a package resource
 type PackageResourceProperties struct {
 	CommonResourceProperties `yaml:",inline"`
+	Names                    any    `json:"names,omitempty" yaml:"names,omitempty" template:"-"` // Names manages one package per entry, either a list or a template expression resolving to a list
+	Autoremove               bool   `json:"autoremove,omitempty" yaml:"autoremove,omitempty"`    // Autoremove removes dependencies that are no longer needed after the package was uninstalled, requires ensure absent
+	CleanCache               bool   `json:"clean_cache,omitempty" yaml:"clean_cache,omitempty"`  // CleanCache cleans the package manager cache after the package was changed
+	Manager                  string `json:"manager,omitempty" yaml:"manager,omitempty"`          // Manager is the preferred package manager, used when it can manage the package otherwise a provider is selected by priority
 }
 
 // PackageMetadata contains detailed metadata about a package
@@ -55,6 +66,7 @@ type PackageState struct {
 	CommonResourceState
 
 	Metadata *PackageMetadata `json:"metadata,omitempty"`
+	Actions  []string         `json:"actions,omitempty"` // Actions are the maintenance actions performed after the package was changed, like autoremove
 }
 
 func (f *PackageState) CommonState() *CommonResourceState {
@@ -82,6 +94,20 @@ func (p *PackageResourceProperties) Validate() error {
 		return fmt.Errorf("package name contains invalid characters: %q (allowed: alphanumeric, ._+:~-)", p.Name)
 	}
 
+	if p.Manager != "" {
+		if p.Provider != "" {
+			return fmt.Errorf("manager cannot be used with provider")
+		}
+
+		if !commonNameRegex.MatchString(p.Manager) {
+			return fmt.Errorf("package manager contains invalid characters: %q", p.Manager)
+		}
+	}
+
+	if p.Autoremove && p.Ensure != EnsureAbsent {
+		return fmt.Errorf("autoremove requires ensure %q", EnsureAbsent)
+	}
+
 	// Validate ensure value if it's a version string
 	if p.Ensure != "" && p.Ensure != EnsurePresent && p.Ensure != EnsureAbsent && p.Ensure != PackageEnsureLatest {
 		// It's a version string, validate it
@@ -103,6 +129,89 @@ func (p *PackageResourceProperties) ResolveTemplates(env *templates.Env) error {
 	return p.resolveRegistrations(env)
 }
 
+// expandResources creates a package resource for every entry in names, names may be a list or a template
+// expression resolving to a list of strings or to a string holding a JSON or YAML list, for example a KV value
+func (p *PackageResourceProperties) expandResources(env *templates.Env) ([]ResourceProperties, error) {
+	if p.Names == nil {
+		return []ResourceProperties{p}, nil
+	}
+
+	if p.Alias != "" {
+		return nil, fmt.Errorf("alias cannot be used with names")
+	}
+
+	names, err := p.resolveNames(env)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]ResourceProperties, 0, len(names))
+	for _, name := range names {
+		prop := *p
+		prop.Name = name
+		prop.Names = nil
+		prop.HealthChecks = slices.Clone(p.HealthChecks)
+		prop.Require = slices.Clone(p.Require)
+		prop.Before = slices.Clone(p.Before)
+		prop.Notify = slices.Clone(p.Notify)
+		prop.Conflicts = slices.Clone(p.Conflicts)
+
+		res = append(res, &prop)
+	}
+
+	return res, nil
+}
+
+func (p *PackageResourceProperties) resolveNames(env *templates.Env) ([]string, error) {
+	names := p.Names
+
+	if s, ok := names.(string); ok {
+		resolved, err := templates.ResolveTemplateTyped(s, env)
+		if err != nil {
+			return nil, fmt.Errorf("could not resolve names: %w", err)
+		}
+
+		names = resolved
+	}
+
+	// values from kvGet and similar are strings holding a serialized list
+	if s, ok := names.(string); ok {
+		var list []any
+		err := yaml.Unmarshal([]byte(s), &list)
+		if err != nil {
+			return nil, fmt.Errorf("names must resolve to a list of strings, got %q", s)
+		}
+
+		names = list
+	}
+
+	list, ok := names.([]any)
+	if !ok {
+		return nil, fmt.Errorf("names must resolve to a list of strings, got %T", names)
+	}
+
+	if len(list) == 0 {
+		return nil, fmt.Errorf("names must resolve to at least one package")
+	}
+
+	res := make([]string, len(list))
+	for i, v := range list {
+		name, ok := v.(string)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("names must resolve to a list of strings, entry %d is not a string: %v", i+1, v)
+		}
+
+		name, err := templates.ResolveTemplateString(name, env)
+		if err != nil {
+			return nil, fmt.Errorf("could not resolve names entry %d: %w", i+1, err)
+		}
+
+		res[i] = name
+	}
+
+	return res, nil
+}
+
 // ToYamlManifest returns the package resource properties as a yaml document
 func (p *PackageResourceProperties) ToYamlManifest() (yaml.RawMessage, error) {
 	return yaml.Marshal(p)
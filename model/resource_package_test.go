@@ -5,11 +5,14 @@
 package model
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/goccy/go-yaml"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+
+	"github.com/choria-io/ccm/templates"
 )
 
 func TestPackageResourceProperties(t *testing.T) {
@@ -73,6 +76,38 @@ var _ = Describe("PackageResourceProperties", func() {
 			Entry("version with command substitution", "nginx", "1.2.3$(whoami)", "dangerous characters"),
 		)
 
+		It("Should only allow autoremove when removing packages", func() {
+			prop := &PackageResourceProperties{
+				CommonResourceProperties: CommonResourceProperties{Name: "nginx", Ensure: EnsurePresent},
+				Autoremove:               true,
+			}
+			Expect(prop.Validate()).To(MatchError(ContainSubstring(`autoremove requires ensure "absent"`)))
+
+			prop.Ensure = EnsureAbsent
+			Expect(prop.Validate()).To(Succeed())
+
+			prop = &PackageResourceProperties{
+				CommonResourceProperties: CommonResourceProperties{Name: "nginx", Ensure: PackageEnsureLatest},
+				CleanCache:               true,
+			}
+			Expect(prop.Validate()).To(Succeed())
+		})
+
+		It("Should validate the preferred manager", func() {
+			prop := &PackageResourceProperties{
+				CommonResourceProperties: CommonResourceProperties{Name: "nginx", Ensure: EnsurePresent},
+				Manager:                  "dnf",
+			}
+			Expect(prop.Validate()).To(Succeed())
+
+			prop.Provider = "apt"
+			Expect(prop.Validate()).To(MatchError("manager cannot be used with provider"))
+
+			prop.Provider = ""
+			prop.Manager = "dnf; reboot"
+			Expect(prop.Validate()).To(MatchError(ContainSubstring("package manager contains invalid characters")))
+		})
+
 		DescribeTable("legitimate packages",
 			func(name, ensure string) {
 				prop := &PackageResourceProperties{
@@ -104,6 +139,77 @@ var _ = Describe("PackageResourceProperties", func() {
 			Entry("httpd absent", "httpd", "absent"),
 		)
 	})
+
+	Describe("Names", func() {
+		var env *templates.Env
+
+		BeforeEach(func() {
+			env = &templates.Env{
+				Facts: map[string]any{},
+				Data: map[string]any{
+					"web":      map[string]any{"packages": []any{"nginx", "php-fpm"}},
+					"listed":   `["zsh", "vim"]`,
+					"version":  "1.2.3",
+					"invalid":  []any{"nginx", 1},
+					"not_list": "nginx",
+				},
+			}
+		})
+
+		names := func(props []ResourceProperties) []string {
+			var res []string
+			for _, prop := range props {
+				res = append(res, prop.CommonProperties().Name)
+			}
+			return res
+		}
+
+		It("Should expand a data lookup into a resource per package", func() {
+			props, err := NewValidatedResourcePropertiesFromYaml(PackageTypeName, []byte(`
+names: "{{ lookup('data.web.packages') }}"
+ensure: "{{ Data.version }}"
+require:
+  - file#/etc/yum.conf
+`), env)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names(props)).To(Equal([]string{"nginx", "php-fpm"}))
+
+			for _, prop := range props {
+				pkg := prop.(*PackageResourceProperties)
+				Expect(pkg.Names).To(BeNil())
+				Expect(pkg.Ensure).To(Equal("1.2.3"))
+				Expect(pkg.Require).To(Equal([]string{"file#/etc/yum.conf"}))
+			}
+		})
+
+		It("Should support literal lists and serialized lists", func() {
+			props, err := NewValidatedResourcePropertiesFromYaml(PackageTypeName, []byte(`
+- web:
+    ensure: present
+    names: [git, "{{ Data.version }}"]
+- shells:
+    ensure: present
+    names: "{{ Data.listed }}"
+`), env)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names(props)).To(Equal([]string{"git", "1.2.3", "zsh", "vim"}))
+		})
+
+		DescribeTable("Should reject lookups that are not lists of strings",
+			func(names string, message string) {
+				_, err := NewResourcePropertiesFromYaml(PackageTypeName, []byte(fmt.Sprintf("names: %q\nensure: present", names)), env)
+				Expect(err).To(MatchError(ContainSubstring(message)))
+			},
+			Entry("a plain string", "{{ Data.not_list }}", `names must resolve to a list of strings, got "nginx"`),
+			Entry("a non string entry", "{{ lookup('data.invalid') }}", "entry 2 is not a string: 1"),
+			Entry("a missing key", "{{ lookup('data.missing') }}", "could not resolve names"),
+		)
+
+		It("Should not support aliases", func() {
+			_, err := NewResourcePropertiesFromYaml(PackageTypeName, []byte("names: [zsh]\nalias: shell\nensure: present"), env)
+			Expect(err).To(MatchError(ContainSubstring("alias cannot be used with names")))
+		})
+	})
 })
 
 var _ = Describe("CommonResourceProperties", func() {
@@ -154,6 +260,17 @@ var _ = Describe("CommonResourceProperties", func() {
 				Expect(err).To(MatchError(ErrInvalidRequires))
 			})
 
+			It("Should reject before without hash separator", func() {
+				prop := &CommonResourceProperties{
+					Name:   "test",
+					Ensure: "present",
+					Before: []string{"service-nginx"},
+				}
+
+				err := prop.Validate()
+				Expect(err).To(MatchError(ErrInvalidBefore))
+			})
+
 			It("Should accept require with empty type (validation is lenient)", func() {
 				// IsValidResourceRef only checks for presence of # separator
 				prop := &CommonResourceProperties{
@@ -6,10 +6,12 @@ package model
 
 import (
 	"fmt"
-	"path/filepath"
+	"path"
+	"strconv"
 
 	"github.com/goccy/go-yaml"
 
+	iu "github.com/choria-io/ccm/internal/util"
 	"github.com/choria-io/ccm/templates"
 )
 
@@ -19,6 +21,9 @@ const (
 
 	// ScaffoldTypeName is the type name for scaffold resources
 	ScaffoldTypeName = "scaffold"
+
+	// ScaffoldMetaFileName is the name of the optional file in the root of a scaffold source that sets per-file ownership and mode
+	ScaffoldMetaFileName = ".ccmmeta"
 )
 
 type ScaffoldResourceEngine string
@@ -26,6 +31,8 @@ type ScaffoldResourceEngine string
 const (
 	ScaffoldEngineGo  ScaffoldResourceEngine = "go"
 	ScaffoldEngineJet ScaffoldResourceEngine = "jet"
+	// ScaffoldEngineRaw copies source files verbatim without template rendering
+	ScaffoldEngineRaw ScaffoldResourceEngine = "raw"
 )
 
 // ScaffoldResourceProperties defines the properties for a scaffold resource
@@ -39,6 +46,11 @@ type ScaffoldResourceProperties struct {
 	Data                     map[string]any         `json:"data,omitempty" yaml:"data,omitempty" template:"resolve_keys"`
 	Purge                    bool                   `json:"purge,omitempty" yaml:"purge,omitempty"`
 	Post                     []map[string]string    `json:"post,omitempty" yaml:"post,omitempty"`
+	Owner                    string                 `json:"owner,omitempty" yaml:"owner,omitempty"`         // Owner sets the owner of every written file, unmanaged when empty
+	Group                    string                 `json:"group,omitempty" yaml:"group,omitempty"`         // Group sets the group of every written file, unmanaged when empty
+	FileMode                 string                 `json:"file_mode,omitempty" yaml:"file_mode,omitempty"` // FileMode sets the octal mode of every written file, unmanaged when empty
+	Include                  []string               `json:"include,omitempty" yaml:"include,omitempty"`     // Include limits the managed files to those matching these globs
+	Exclude                  []string               `json:"exclude,omitempty" yaml:"exclude,omitempty"`     // Exclude globs select files that are never written, reported or purged
 }
 
 // ScaffoldMetadata contains detailed metadata about a scaffold
@@ -82,20 +94,47 @@ func (p *ScaffoldResourceProperties) Validate() error {
 		return fmt.Errorf("%w: must be one of %q or %q", ErrInvalidEnsureValue, EnsurePresent, EnsureAbsent)
 	}
 
-	if !filepath.IsAbs(p.Name) {
-		return fmt.Errorf("name must be an absolute path")
-	}
-
-	if filepath.Clean(p.Name) != p.Name {
-		return fmt.Errorf("name must be a canonical path")
+	err = iu.ValidateCanonicalPath("name", p.Name)
+	if err != nil {
+		return err
 	}
 
 	if p.Source == "" {
 		return fmt.Errorf("source cannot be empty")
 	}
 
-	if p.Engine != ScaffoldEngineGo && p.Engine != ScaffoldEngineJet {
-		return fmt.Errorf("engine must be one of %q or %q", ScaffoldEngineGo, ScaffoldEngineJet)
+	if p.Engine != ScaffoldEngineGo && p.Engine != ScaffoldEngineJet && p.Engine != ScaffoldEngineRaw {
+		return fmt.Errorf("engine must be one of %q, %q or %q", ScaffoldEngineGo, ScaffoldEngineJet, ScaffoldEngineRaw)
+	}
+
+	if p.Engine == ScaffoldEngineRaw {
+		if p.LeftDelimiter != "" || p.RightDelimiter != "" {
+			return fmt.Errorf("delimiters cannot be used with the %q engine", ScaffoldEngineRaw)
+		}
+		if len(p.Post) > 0 {
+			return fmt.Errorf("post cannot be used with the %q engine", ScaffoldEngineRaw)
+		}
+	}
+
+	if p.FileMode != "" {
+		_, err = strconv.ParseUint(p.FileMode, 8, 32)
+		if err != nil {
+			return fmt.Errorf("file_mode must be an octal mode: %w", err)
+		}
+	}
+
+	for _, pattern := range p.Include {
+		err = validateScaffoldGlob("include", pattern)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, pattern := range p.Exclude {
+		err = validateScaffoldGlob("exclude", pattern)
+		if err != nil {
+			return err
+		}
 	}
 
 	for _, entry := range p.Post {
@@ -112,6 +151,19 @@ func (p *ScaffoldResourceProperties) Validate() error {
 	return nil
 }
 
+func validateScaffoldGlob(property string, pattern string) error {
+	if pattern == "" {
+		return fmt.Errorf("%s patterns cannot be empty", property)
+	}
+
+	_, err := path.Match(pattern, "")
+	if err != nil {
+		return fmt.Errorf("invalid %s pattern %q: %w", property, pattern, err)
+	}
+
+	return nil
+}
+
 // ResolveTemplates resolves template expressions in the scaffold resource properties
 func (p *ScaffoldResourceProperties) ResolveTemplates(env *templates.Env) error {
 	err := templates.ResolveStructTemplates(p, env, false)
@@ -47,6 +47,8 @@ var _ = Describe("ScaffoldResourceProperties", func() {
 			Entry("relative name", "relative/path", "present", "https://example.com/scaffold.tar.gz", ScaffoldEngineGo, nil, "absolute path"),
 			Entry("path with ..", "/opt/../etc/scaffold", "present", "https://example.com/scaffold.tar.gz", ScaffoldEngineGo, nil, "canonical"),
 			Entry("path with .", "/opt/./scaffold", "present", "https://example.com/scaffold.tar.gz", ScaffoldEngineGo, nil, "canonical"),
+			Entry("path with //", "/opt//scaffold", "present", "https://example.com/scaffold.tar.gz", ScaffoldEngineGo, nil, "name must be a canonical path"),
+			Entry("path with trailing slash", "/opt/scaffold/", "present", "https://example.com/scaffold.tar.gz", ScaffoldEngineGo, nil, "name must be a canonical path"),
 
 			// Ensure validation
 			Entry("empty ensure", "/opt/app/scaffold", "", "https://example.com/scaffold.tar.gz", ScaffoldEngineGo, nil, "ensure"),
@@ -58,6 +60,8 @@ var _ = Describe("ScaffoldResourceProperties", func() {
 
 			// Engine validation
 			Entry("invalid engine", "/opt/app/scaffold", "present", "https://example.com/scaffold.tar.gz", ScaffoldResourceEngine("invalid"), nil, "engine must be one of"),
+			Entry("valid scaffold with raw engine", "/opt/app/scaffold", "present", "https://example.com/scaffold.tar.gz", ScaffoldEngineRaw, nil, ""),
+			Entry("post with raw engine", "/opt/app/scaffold", "present", "https://example.com/scaffold.tar.gz", ScaffoldEngineRaw, []map[string]string{{"key1": "value1"}}, "post cannot be used with the \"raw\" engine"),
 
 			// Post validation
 			Entry("post with empty value", "/opt/app/scaffold", "present", "https://example.com/scaffold.tar.gz", ScaffoldEngineGo, []map[string]string{{"key1": ""}}, "post value for key"),
@@ -99,6 +103,43 @@ var _ = Describe("ScaffoldResourceProperties", func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 
+		It("Should validate the file mode", func() {
+			prop := &ScaffoldResourceProperties{
+				CommonResourceProperties: CommonResourceProperties{
+					Name:   "/opt/app/scaffold",
+					Ensure: EnsurePresent,
+				},
+				Source:   "/srv/scaffold",
+				Engine:   ScaffoldEngineGo,
+				FileMode: "0644",
+			}
+			Expect(prop.Validate()).To(Succeed())
+
+			prop.FileMode = "rw-r--r--"
+			Expect(prop.Validate()).To(MatchError(ContainSubstring("file_mode must be an octal mode")))
+		})
+
+		It("Should validate include and exclude patterns", func() {
+			prop := &ScaffoldResourceProperties{
+				CommonResourceProperties: CommonResourceProperties{
+					Name:   "/opt/app/scaffold",
+					Ensure: EnsurePresent,
+				},
+				Source:  "/srv/scaffold",
+				Engine:  ScaffoldEngineGo,
+				Include: []string{"conf/*"},
+				Exclude: []string{".git", "*.bak"},
+			}
+			Expect(prop.Validate()).To(Succeed())
+
+			prop.Exclude = []string{"[invalid"}
+			Expect(prop.Validate()).To(MatchError(ContainSubstring("invalid exclude pattern")))
+
+			prop.Exclude = nil
+			prop.Include = []string{""}
+			Expect(prop.Validate()).To(MatchError(ContainSubstring("include patterns cannot be empty")))
+		})
+
 		It("Should validate post with multiple valid entries", func() {
 			prop := &ScaffoldResourceProperties{
 				CommonResourceProperties: CommonResourceProperties{
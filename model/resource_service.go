@@ -27,8 +27,19 @@ const (
 // ServiceResourceProperties defines the properties for a service resource
 type ServiceResourceProperties struct {
 	CommonResourceProperties `yaml:",inline"`
-	Enable                   *bool    `json:"enable,omitempty" yaml:"enable,omitempty"`       // Enable indicates the service should be enabled on boot
-	Subscribe                []string `json:"subscribe,omitempty" yaml:"subscribe,omitempty"` // Subscribe lists resource statusses to subscribe to in format type#name
+	Enable                   *bool    `json:"enable,omitempty" yaml:"enable,omitempty"`               // Enable indicates the service should be enabled on boot
+	Subscribe                []string `json:"subscribe,omitempty" yaml:"subscribe,omitempty"`         // Subscribe lists resource statusses to subscribe to in format type#name
+	RestartLimit             int      `json:"restart_limit,omitempty" yaml:"restart_limit,omitempty"` // RestartLimit is the number of consecutive failures after which subscribe triggered restarts are suppressed
+}
+
+// Subscriptions returns the resources this resource subscribes to for refresh events
+func (p *ServiceResourceProperties) Subscriptions() []string {
+	return p.Subscribe
+}
+
+// AddSubscription subscribes the resource to ref
+func (p *ServiceResourceProperties) AddSubscription(ref string) {
+	p.Subscribe = append(p.Subscribe, ref)
 }
 
 // ServiceMetadata contains detailed metadata about a service
@@ -86,6 +97,10 @@ func (p *ServiceResourceProperties) Validate() error {
 		}
 	}
 
+	if p.RestartLimit < 0 {
+		return fmt.Errorf("restart_limit cannot be negative")
+	}
+
 	return nil
 }
 
@@ -143,5 +143,14 @@ var _ = Describe("ServiceResourceProperties", func() {
 			Entry("invalid subscribe empty string", []string{""}, "invalid subscribe format"),
 			Entry("mixed valid and invalid", []string{"file#/etc/nginx/nginx.conf", "invalid"}, "invalid subscribe format"),
 		)
+
+		It("Should reject a negative restart limit", func() {
+			prop := &ServiceResourceProperties{
+				CommonResourceProperties: CommonResourceProperties{Name: "nginx", Ensure: ServiceEnsureRunning},
+				RestartLimit:             -1,
+			}
+
+			Expect(prop.Validate()).To(MatchError("restart_limit cannot be negative"))
+		})
 	})
 })
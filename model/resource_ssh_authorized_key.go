@@ -0,0 +1,220 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/choria-io/ccm/templates"
+)
+
+const (
+	// ResourceStatusSSHAuthorizedKeyProtocol is the protocol identifier for ssh_authorized_key resource state
+	ResourceStatusSSHAuthorizedKeyProtocol = "io.choria.ccm.v1.resource.ssh_authorized_key.state"
+
+	// SSHAuthorizedKeyTypeName is the type name for ssh_authorized_key resources
+	SSHAuthorizedKeyTypeName = "ssh_authorized_key"
+)
+
+var (
+	// SSHKeyTypes are the public key types accepted in authorized_keys files
+	SSHKeyTypes = []string{
+		"ssh-ed25519",
+		"ssh-rsa",
+		"ssh-dss",
+		"ecdsa-sha2-nistp256",
+		"ecdsa-sha2-nistp384",
+		"ecdsa-sha2-nistp521",
+		"sk-ssh-ed25519@openssh.com",
+		"sk-ecdsa-sha2-nistp256@openssh.com",
+	}
+
+	sshKeyBodyRegex = regexp.MustCompile(`^[A-Za-z0-9+/]+={0,3}$`)
+	sshKeyUserRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.-]{0,31}\$?$`)
+)
+
+// SSHAuthorizedKeyResourceProperties defines the properties for an ssh_authorized_key resource
+type SSHAuthorizedKeyResourceProperties struct {
+	CommonResourceProperties `yaml:",inline"`
+	User                     string   `json:"user" yaml:"user"`                           // User is the user whose authorized_keys file is managed
+	KeyType                  string   `json:"type,omitempty" yaml:"type,omitempty"`       // KeyType is the type of the key like ssh-ed25519
+	Key                      string   `json:"key,omitempty" yaml:"key,omitempty"`         // Key is the base64 encoded public key
+	Comment                  string   `json:"comment,omitempty" yaml:"comment,omitempty"` // Comment identifies the key in the file, defaults to the name
+	Options                  []string `json:"options,omitempty" yaml:"options,omitempty"` // Options restrict the key, like no-pty or from="10.0.0.0/8"
+}
+
+// SSHAuthorizedKeyMetadata contains detailed metadata about an authorized key
+type SSHAuthorizedKeyMetadata struct {
+	Name     string   `json:"name" yaml:"name"`
+	User     string   `json:"user" yaml:"user"`
+	File     string   `json:"file,omitempty" yaml:"file,omitempty"`
+	KeyType  string   `json:"type,omitempty" yaml:"type,omitempty"`
+	Key      string   `json:"key,omitempty" yaml:"key,omitempty"`
+	Comment  string   `json:"comment,omitempty" yaml:"comment,omitempty"`
+	Options  []string `json:"options,omitempty" yaml:"options,omitempty"`
+	Matches  int      `json:"matches,omitempty" yaml:"matches,omitempty"` // Matches is the number of entries in the file identified as this key
+	Provider string   `json:"provider,omitempty" yaml:"provider,omitempty"`
+}
+
+// SSHAuthorizedKeyState represents the current state of an authorized key
+type SSHAuthorizedKeyState struct {
+	CommonResourceState
+
+	Metadata *SSHAuthorizedKeyMetadata `json:"metadata,omitempty"`
+}
+
+func (f *SSHAuthorizedKeyState) CommonState() *CommonResourceState {
+	return &f.CommonResourceState
+}
+
+func (p *SSHAuthorizedKeyResourceProperties) CommonProperties() *CommonResourceProperties {
+	return &p.CommonResourceProperties
+}
+
+// KeyComment is the comment written with the key, the name when no comment is set
+func (p *SSHAuthorizedKeyResourceProperties) KeyComment() string {
+	if p.Comment != "" {
+		return p.Comment
+	}
+
+	return p.Name
+}
+
+// AuthorizedKeyLine renders the key as a line for an authorized_keys file, without a trailing newline
+func (p *SSHAuthorizedKeyResourceProperties) AuthorizedKeyLine() string {
+	var parts []string
+
+	if len(p.Options) > 0 {
+		parts = append(parts, strings.Join(p.Options, ","))
+	}
+
+	parts = append(parts, p.KeyType, p.Key)
+
+	comment := p.KeyComment()
+	if comment != "" {
+		parts = append(parts, comment)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// IsSSHKeyType determines if t is a known public key type
+func IsSSHKeyType(t string) bool {
+	return slices.Contains(SSHKeyTypes, t)
+}
+
+// Validate validates the ssh_authorized_key resource properties
+func (p *SSHAuthorizedKeyResourceProperties) Validate() error {
+	if p.SkipValidate {
+		return nil
+	}
+
+	// First run common validation
+	err := p.CommonResourceProperties.Validate()
+	if err != nil {
+		return err
+	}
+
+	if p.Ensure != EnsurePresent && p.Ensure != EnsureAbsent {
+		return fmt.Errorf("%w: must be one of %q or %q", ErrInvalidEnsureValue, EnsurePresent, EnsureAbsent)
+	}
+
+	if !sshKeyUserRegex.MatchString(p.User) {
+		return fmt.Errorf("invalid user %q", p.User)
+	}
+
+	if strings.ContainsAny(p.KeyComment(), "\r\n") {
+		return fmt.Errorf("comment must be a single line")
+	}
+
+	if p.Key != "" && !sshKeyBodyRegex.MatchString(p.Key) {
+		return fmt.Errorf("key must be the base64 encoded public key without the type or comment")
+	}
+
+	if p.KeyType != "" && !IsSSHKeyType(p.KeyType) {
+		return fmt.Errorf("invalid key type %q", p.KeyType)
+	}
+
+	for _, opt := range p.Options {
+		err = validateSSHKeyOption(opt)
+		if err != nil {
+			return err
+		}
+	}
+
+	if p.Ensure == EnsureAbsent {
+		return nil
+	}
+
+	if p.KeyType == "" {
+		return fmt.Errorf("type is required when ensure is %q", EnsurePresent)
+	}
+
+	if p.Key == "" {
+		return fmt.Errorf("key is required when ensure is %q", EnsurePresent)
+	}
+
+	return nil
+}
+
+// validateSSHKeyOption ensures opt is a single option, whitespace and commas are only allowed in quoted values
+func validateSSHKeyOption(opt string) error {
+	if opt == "" || strings.ContainsAny(opt, "\r\n") {
+		return fmt.Errorf("invalid option %q", opt)
+	}
+
+	quoted := false
+	for i, c := range opt {
+		switch {
+		case c == '"' && (i == 0 || opt[i-1] != '\\'):
+			quoted = !quoted
+		case !quoted && (c == ',' || c == ' ' || c == '\t'):
+			return fmt.Errorf("invalid option %q: commas and spaces must be quoted", opt)
+		}
+	}
+
+	if quoted {
+		return fmt.Errorf("invalid option %q: unterminated quote", opt)
+	}
+
+	return nil
+}
+
+// ResolveTemplates resolves template expressions in the ssh_authorized_key resource properties
+func (p *SSHAuthorizedKeyResourceProperties) ResolveTemplates(env *templates.Env) error {
+	err := templates.ResolveStructTemplates(p, env, false)
+	if err != nil {
+		return err
+	}
+
+	return p.resolveRegistrations(env)
+}
+
+// ToYamlManifest returns the ssh_authorized_key resource properties as a yaml document
+func (p *SSHAuthorizedKeyResourceProperties) ToYamlManifest() (yaml.RawMessage, error) {
+	return yaml.Marshal(p)
+}
+
+// NewSSHAuthorizedKeyResourcePropertiesFromYaml creates a new ssh_authorized_key resource properties object from a yaml document, does not validate or expand templates
+func NewSSHAuthorizedKeyResourcePropertiesFromYaml(raw yaml.RawMessage) ([]ResourceProperties, error) {
+	res, err := parseProperties(raw, SSHAuthorizedKeyTypeName, func() ResourceProperties { return &SSHAuthorizedKeyResourceProperties{} })
+	if err != nil {
+		return nil, err
+	}
+
+	for _, prop := range res {
+		p := prop.(*SSHAuthorizedKeyResourceProperties)
+		if p.Ensure == "" {
+			p.Ensure = EnsurePresent
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SSHAuthorizedKeyResourceProperties", func() {
+	Describe("Validate", func() {
+		DescribeTable("validation tests",
+			func(ensure, user, keyType, key, comment string, options []string, errorText string) {
+				prop := &SSHAuthorizedKeyResourceProperties{
+					CommonResourceProperties: CommonResourceProperties{
+						Name:   "bob@laptop",
+						Ensure: ensure,
+					},
+					User:    user,
+					KeyType: keyType,
+					Key:     key,
+					Comment: comment,
+					Options: options,
+				}
+
+				err := prop.Validate()
+
+				if errorText != "" {
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring(errorText))
+				} else {
+					Expect(err).ToNot(HaveOccurred())
+				}
+			},
+
+			Entry("valid key", "present", "bob", "ssh-ed25519", "AAAAC3NzaC1lZDI1NTE5AAAAIBob", "", nil, ""),
+			Entry("valid options", "present", "bob", "ssh-rsa", "AAAAB3NzaC1yc2E=", "", []string{"no-pty", `command="/bin/backup --all"`, `from="10.0.0.1,10.0.0.2"`}, ""),
+			Entry("valid absent by comment", "absent", "bob", "", "", "", nil, ""),
+
+			Entry("invalid ensure", "running", "bob", "ssh-ed25519", "AAAA", "", nil, "invalid ensure value"),
+			Entry("invalid user", "present", "bob smith", "ssh-ed25519", "AAAA", "", nil, `invalid user "bob smith"`),
+			Entry("missing type", "present", "bob", "", "AAAA", "", nil, "type is required"),
+			Entry("missing key", "present", "bob", "ssh-ed25519", "", "", nil, "key is required"),
+			Entry("invalid type", "present", "bob", "ssh-foo", "AAAA", "", nil, `invalid key type "ssh-foo"`),
+			Entry("key with type", "present", "bob", "ssh-ed25519", "ssh-ed25519 AAAA", "", nil, "key must be the base64 encoded public key"),
+			Entry("multi line comment", "present", "bob", "ssh-ed25519", "AAAA", "a\nb", nil, "comment must be a single line"),
+			Entry("unquoted comma", "present", "bob", "ssh-ed25519", "AAAA", "", []string{"no-pty,no-agent-forwarding"}, "commas and spaces must be quoted"),
+			Entry("unterminated quote", "present", "bob", "ssh-ed25519", "AAAA", "", []string{`command="/bin/true`}, "unterminated quote"),
+		)
+	})
+
+	Describe("AuthorizedKeyLine", func() {
+		It("Should render the key with options and the name as comment", func() {
+			prop := &SSHAuthorizedKeyResourceProperties{
+				CommonResourceProperties: CommonResourceProperties{Name: "bob@laptop"},
+				KeyType:                  "ssh-ed25519",
+				Key:                      "AAAA",
+				Options:                  []string{"no-pty", `from="10.0.0.0/8"`},
+			}
+			Expect(prop.AuthorizedKeyLine()).To(Equal(`no-pty,from="10.0.0.0/8" ssh-ed25519 AAAA bob@laptop`))
+
+			prop.Comment = "bob"
+			prop.Options = nil
+			Expect(prop.AuthorizedKeyLine()).To(Equal("ssh-ed25519 AAAA bob"))
+		})
+	})
+
+	Describe("NewSSHAuthorizedKeyResourcePropertiesFromYaml", func() {
+		It("Should default ensure to present", func() {
+			res, err := NewSSHAuthorizedKeyResourcePropertiesFromYaml([]byte(`- bob@laptop:
+    user: bob
+    type: ssh-ed25519
+    key: AAAA`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(HaveLen(1))
+			Expect(res[0].CommonProperties().Ensure).To(Equal(EnsurePresent))
+			Expect(res[0].(*SSHAuthorizedKeyResourceProperties).KeyType).To(Equal("ssh-ed25519"))
+		})
+	})
+})
@@ -0,0 +1,224 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/choria-io/ccm/templates"
+)
+
+const (
+	// ResourceStatusSudoersProtocol is the protocol identifier for sudoers resource state
+	ResourceStatusSudoersProtocol = "io.choria.ccm.v1.resource.sudoers.state"
+
+	// SudoersTypeName is the type name for sudoers resources
+	SudoersTypeName = "sudoers"
+
+	// SudoersFileMode is the mode sudoers files are installed with, sudo refuses files writable by others
+	SudoersFileMode = "0440"
+
+	// SudoersFileHeader is the comment written at the top of every managed sudoers file
+	SudoersFileHeader = "# Managed by Choria CCM, local changes will be overwritten"
+)
+
+var (
+	// sudoersFileNameRegex matches characters that sudo ignores files for when including a directory, see sudoers(5)
+	sudoersFileNameRegex = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+	// sudoersNameRegex matches user, group, host and alias names used in rules
+	sudoersNameRegex = regexp.MustCompile(`^[%+]?:?[a-zA-Z0-9_.#-]+$`)
+)
+
+// SudoersRule describes a single user specification that allows a user to run commands
+type SudoersRule struct {
+	User     string   `json:"user" yaml:"user"`                             // User is the user, %group or User_Alias the rule applies to
+	Hosts    []string `json:"hosts,omitempty" yaml:"hosts,omitempty"`       // Hosts are the hosts the rule applies to, defaults to ALL
+	RunAs    string   `json:"run_as,omitempty" yaml:"run_as,omitempty"`     // RunAs is the user the commands may be run as, defaults to ALL
+	Commands []string `json:"commands" yaml:"commands"`                     // Commands are the fully qualified commands that may be run, or ALL
+	NoPasswd bool     `json:"nopasswd,omitempty" yaml:"nopasswd,omitempty"` // NoPasswd allows the commands to run without a password
+}
+
+// SudoersResourceProperties defines the properties for a sudoers resource
+type SudoersResourceProperties struct {
+	CommonResourceProperties `yaml:",inline"`
+	Content                  string        `json:"content,omitempty" yaml:"content,omitempty"` // Content is the literal content of the sudoers file
+	Rules                    []SudoersRule `json:"rules,omitempty" yaml:"rules,omitempty"`     // Rules are rendered into the sudoers file when Content is not set
+}
+
+// SudoersMetadata contains detailed metadata about a sudoers file
+type SudoersMetadata struct {
+	Name     string `json:"name" yaml:"name"`
+	File     string `json:"file" yaml:"file"`
+	Mode     string `json:"mode,omitempty" yaml:"mode,omitempty"`
+	Checksum string `json:"checksum,omitempty" yaml:"checksum,omitempty"`
+	Provider string `json:"provider,omitempty" yaml:"provider,omitempty"`
+}
+
+// SudoersState represents the current state of a sudoers file
+type SudoersState struct {
+	CommonResourceState
+
+	Metadata *SudoersMetadata `json:"metadata,omitempty"`
+}
+
+func (f *SudoersState) CommonState() *CommonResourceState {
+	return &f.CommonResourceState
+}
+
+func (p *SudoersResourceProperties) CommonProperties() *CommonResourceProperties {
+	return &p.CommonResourceProperties
+}
+
+// FileName is the name of the sudoers file, derived from the resource name with characters sudo would
+// skip files for replaced by underscores
+func (p *SudoersResourceProperties) FileName() string {
+	return sudoersFileNameRegex.ReplaceAllString(p.Name, "_")
+}
+
+// FileContent renders the sudoers file from Content or Rules
+func (p *SudoersResourceProperties) FileContent() []byte {
+	buf := bytes.NewBufferString(SudoersFileHeader + "\n")
+
+	if p.Content != "" {
+		buf.WriteString(p.Content)
+		if !strings.HasSuffix(p.Content, "\n") {
+			buf.WriteString("\n")
+		}
+
+		return buf.Bytes()
+	}
+
+	for _, rule := range p.Rules {
+		hosts := "ALL"
+		if len(rule.Hosts) > 0 {
+			hosts = strings.Join(rule.Hosts, ", ")
+		}
+
+		runAs := rule.RunAs
+		if runAs == "" {
+			runAs = "ALL"
+		}
+
+		tag := ""
+		if rule.NoPasswd {
+			tag = "NOPASSWD: "
+		}
+
+		fmt.Fprintf(buf, "%s %s=(%s) %s%s\n", rule.User, hosts, runAs, tag, strings.Join(rule.Commands, ", "))
+	}
+
+	return buf.Bytes()
+}
+
+// Validate validates the sudoers resource properties
+func (p *SudoersResourceProperties) Validate() error {
+	if p.SkipValidate {
+		return nil
+	}
+
+	// First run common validation
+	err := p.CommonResourceProperties.Validate()
+	if err != nil {
+		return err
+	}
+
+	if p.Ensure != EnsurePresent && p.Ensure != EnsureAbsent {
+		return fmt.Errorf("%w: must be one of %q or %q", ErrInvalidEnsureValue, EnsurePresent, EnsureAbsent)
+	}
+
+	if strings.Trim(p.FileName(), "_-") == "" {
+		return fmt.Errorf("name must contain at least one letter or digit")
+	}
+
+	if p.Ensure == EnsureAbsent {
+		return nil
+	}
+
+	switch {
+	case p.Content != "" && len(p.Rules) > 0:
+		return fmt.Errorf("only one of content or rules can be set")
+	case p.Content == "" && len(p.Rules) == 0:
+		return fmt.Errorf("content or rules is required when ensure is %q", EnsurePresent)
+	}
+
+	for i, rule := range p.Rules {
+		err = rule.validate()
+		if err != nil {
+			return fmt.Errorf("rule %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *SudoersRule) validate() error {
+	if !sudoersNameRegex.MatchString(r.User) {
+		return fmt.Errorf("invalid user %q", r.User)
+	}
+
+	for _, host := range r.Hosts {
+		if !sudoersNameRegex.MatchString(host) {
+			return fmt.Errorf("invalid host %q", host)
+		}
+	}
+
+	if r.RunAs != "" && !sudoersNameRegex.MatchString(r.RunAs) {
+		return fmt.Errorf("invalid run_as %q", r.RunAs)
+	}
+
+	if len(r.Commands) == 0 {
+		return fmt.Errorf("at least one command is required")
+	}
+
+	for _, cmd := range r.Commands {
+		if strings.ContainsAny(cmd, "\n\r,") {
+			return fmt.Errorf("command %q must be a single line without commas", cmd)
+		}
+
+		if cmd != "ALL" && !strings.HasPrefix(cmd, "/") {
+			return fmt.Errorf("command %q must be fully qualified or ALL", cmd)
+		}
+	}
+
+	return nil
+}
+
+// ResolveTemplates resolves template expressions in the sudoers resource properties
+func (p *SudoersResourceProperties) ResolveTemplates(env *templates.Env) error {
+	err := templates.ResolveStructTemplates(p, env, false)
+	if err != nil {
+		return err
+	}
+
+	return p.resolveRegistrations(env)
+}
+
+// ToYamlManifest returns the sudoers resource properties as a yaml document
+func (p *SudoersResourceProperties) ToYamlManifest() (yaml.RawMessage, error) {
+	return yaml.Marshal(p)
+}
+
+// NewSudoersResourcePropertiesFromYaml creates a new sudoers resource properties object from a yaml document, does not validate or expand templates
+func NewSudoersResourcePropertiesFromYaml(raw yaml.RawMessage) ([]ResourceProperties, error) {
+	res, err := parseProperties(raw, SudoersTypeName, func() ResourceProperties { return &SudoersResourceProperties{} })
+	if err != nil {
+		return nil, err
+	}
+
+	for _, prop := range res {
+		p := prop.(*SudoersResourceProperties)
+		if p.Ensure == "" {
+			p.Ensure = EnsurePresent
+		}
+	}
+
+	return res, nil
+}
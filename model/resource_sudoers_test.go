@@ -0,0 +1,91 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SudoersResourceProperties", func() {
+	Describe("Validate", func() {
+		DescribeTable("validation tests",
+			func(name, ensure, content string, rules []SudoersRule, errorText string) {
+				prop := &SudoersResourceProperties{
+					CommonResourceProperties: CommonResourceProperties{
+						Name:   name,
+						Ensure: ensure,
+					},
+					Content: content,
+					Rules:   rules,
+				}
+
+				err := prop.Validate()
+
+				if errorText != "" {
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring(errorText))
+				} else {
+					Expect(err).ToNot(HaveOccurred())
+				}
+			},
+
+			Entry("valid content", "deploy", "present", "deploy ALL=(ALL) ALL", nil, ""),
+			Entry("valid rules", "deploy", "present", "", []SudoersRule{{User: "%admins", Hosts: []string{"web01"}, RunAs: "app", Commands: []string{"/usr/bin/systemctl restart app", "ALL"}}}, ""),
+			Entry("valid absent", "deploy", "absent", "", nil, ""),
+
+			Entry("invalid ensure", "deploy", "running", "deploy ALL=(ALL) ALL", nil, "invalid ensure value"),
+			Entry("unusable name", "...", "present", "deploy ALL=(ALL) ALL", nil, "name must contain at least one letter or digit"),
+			Entry("missing content", "deploy", "present", "", nil, "content or rules is required"),
+			Entry("content and rules", "deploy", "present", "deploy ALL=(ALL) ALL", []SudoersRule{{User: "deploy", Commands: []string{"ALL"}}}, "only one of content or rules"),
+			Entry("invalid user", "deploy", "present", "", []SudoersRule{{User: "bad user", Commands: []string{"ALL"}}}, `rule 1: invalid user "bad user"`),
+			Entry("invalid host", "deploy", "present", "", []SudoersRule{{User: "deploy", Hosts: []string{"a=b"}, Commands: []string{"ALL"}}}, `invalid host "a=b"`),
+			Entry("invalid run as", "deploy", "present", "", []SudoersRule{{User: "deploy", RunAs: "(root)", Commands: []string{"ALL"}}}, `invalid run_as "(root)"`),
+			Entry("missing commands", "deploy", "present", "", []SudoersRule{{User: "deploy"}}, "at least one command is required"),
+			Entry("relative command", "deploy", "present", "", []SudoersRule{{User: "deploy", Commands: []string{"systemctl"}}}, "must be fully qualified"),
+			Entry("multi line command", "deploy", "present", "", []SudoersRule{{User: "deploy", Commands: []string{"/bin/true\ndeploy ALL=(ALL) ALL"}}}, "must be a single line"),
+		)
+	})
+
+	Describe("FileName", func() {
+		It("Should replace characters sudo does not accept", func() {
+			prop := &SudoersResourceProperties{CommonResourceProperties: CommonResourceProperties{Name: "app.deploy~"}}
+			Expect(prop.FileName()).To(Equal("app_deploy_"))
+		})
+	})
+
+	Describe("FileContent", func() {
+		It("Should render content with a trailing newline", func() {
+			prop := &SudoersResourceProperties{Content: "deploy ALL=(ALL) ALL"}
+			Expect(string(prop.FileContent())).To(Equal(SudoersFileHeader + "\ndeploy ALL=(ALL) ALL\n"))
+		})
+
+		It("Should render rules with defaults", func() {
+			prop := &SudoersResourceProperties{Rules: []SudoersRule{
+				{User: "deploy", Commands: []string{"/usr/bin/systemctl restart app", "/usr/bin/systemctl reload app"}, NoPasswd: true},
+				{User: "%admins", Hosts: []string{"web01", "web02"}, RunAs: "app", Commands: []string{"ALL"}},
+			}}
+
+			Expect(string(prop.FileContent())).To(Equal(SudoersFileHeader + "\n" +
+				"deploy ALL=(ALL) NOPASSWD: /usr/bin/systemctl restart app, /usr/bin/systemctl reload app\n" +
+				"%admins web01, web02=(app) ALL\n"))
+		})
+	})
+
+	Describe("NewSudoersResourcePropertiesFromYaml", func() {
+		It("Should default ensure to present", func() {
+			res, err := NewSudoersResourcePropertiesFromYaml([]byte(`- deploy:
+    rules:
+      - user: deploy
+        commands: [/usr/bin/systemctl restart app]
+        nopasswd: true`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(HaveLen(1))
+			Expect(res[0].CommonProperties().Ensure).To(Equal(EnsurePresent))
+			Expect(res[0].(*SudoersResourceProperties).Rules).To(HaveLen(1))
+			Expect(res[0].(*SudoersResourceProperties).Rules[0].NoPasswd).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,211 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/choria-io/ccm/templates"
+)
+
+const (
+	// ResourceStatusYumRepoProtocol is the protocol identifier for yumrepo resource state
+	ResourceStatusYumRepoProtocol = "io.choria.ccm.v1.resource.yumrepo.state"
+
+	// YumRepoTypeName is the type name for yumrepo resources
+	YumRepoTypeName = "yumrepo"
+
+	// YumRepoFileHeader is the comment written at the top of every managed repository file
+	YumRepoFileHeader = "# Managed by Choria CCM, local changes will be overwritten"
+)
+
+var (
+	// yumRepoIdRegex matches repository ids accepted by dnf and yum
+	yumRepoIdRegex = regexp.MustCompile(`^[a-zA-Z0-9_.:-]+$`)
+
+	yumRepoUrlSchemes = []string{"http", "https", "ftp", "file"}
+)
+
+// YumRepoResourceProperties defines the properties for a yumrepo resource
+type YumRepoResourceProperties struct {
+	CommonResourceProperties `yaml:",inline"`
+	BaseURL                  string `json:"baseurl,omitempty" yaml:"baseurl,omitempty"`         // BaseURL is the url of the repository
+	Description              string `json:"description,omitempty" yaml:"description,omitempty"` // Description is the human readable name of the repository, defaults to the name
+	Enabled                  *bool  `json:"enabled,omitempty" yaml:"enabled,omitempty"`         // Enabled indicates the repository is used by the package manager, defaults to true
+	GPGKey                   string `json:"gpgkey,omitempty" yaml:"gpgkey,omitempty"`           // GPGKey is the url of the key packages are signed with
+}
+
+// YumRepoMetadata contains detailed metadata about a yum repository
+type YumRepoMetadata struct {
+	Name        string `json:"name" yaml:"name"`
+	File        string `json:"file" yaml:"file"`
+	BaseURL     string `json:"baseurl,omitempty" yaml:"baseurl,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Enabled     bool   `json:"enabled" yaml:"enabled"`
+	GPGKey      string `json:"gpgkey,omitempty" yaml:"gpgkey,omitempty"`
+	Provider    string `json:"provider,omitempty" yaml:"provider,omitempty"`
+}
+
+// YumRepoState represents the current state of a yum repository
+type YumRepoState struct {
+	CommonResourceState
+
+	Metadata *YumRepoMetadata `json:"metadata,omitempty"`
+}
+
+func (f *YumRepoState) CommonState() *CommonResourceState {
+	return &f.CommonResourceState
+}
+
+func (p *YumRepoResourceProperties) CommonProperties() *CommonResourceProperties {
+	return &p.CommonResourceProperties
+}
+
+// FileName is the name of the file holding the repository
+func (p *YumRepoResourceProperties) FileName() string {
+	return p.Name + ".repo"
+}
+
+// RepoDescription is the human readable name of the repository, the name when no description is set
+func (p *YumRepoResourceProperties) RepoDescription() string {
+	if p.Description != "" {
+		return p.Description
+	}
+
+	return p.Name
+}
+
+// IsEnabled determines if the repository should be enabled, repositories are enabled unless Enabled is false
+func (p *YumRepoResourceProperties) IsEnabled() bool {
+	return p.Enabled == nil || *p.Enabled
+}
+
+// FileContent renders the repository file
+func (p *YumRepoResourceProperties) FileContent() []byte {
+	buf := bytes.NewBufferString(YumRepoFileHeader + "\n")
+
+	fmt.Fprintf(buf, "[%s]\n", p.Name)
+	fmt.Fprintf(buf, "name=%s\n", p.RepoDescription())
+	fmt.Fprintf(buf, "baseurl=%s\n", p.BaseURL)
+	if p.IsEnabled() {
+		fmt.Fprintln(buf, "enabled=1")
+	} else {
+		fmt.Fprintln(buf, "enabled=0")
+	}
+	if p.GPGKey != "" {
+		fmt.Fprintf(buf, "gpgkey=%s\n", p.GPGKey)
+	}
+
+	return buf.Bytes()
+}
+
+// Validate validates the yumrepo resource properties
+func (p *YumRepoResourceProperties) Validate() error {
+	if p.SkipValidate {
+		return nil
+	}
+
+	// First run common validation
+	err := p.CommonResourceProperties.Validate()
+	if err != nil {
+		return err
+	}
+
+	if p.Ensure != EnsurePresent && p.Ensure != EnsureAbsent {
+		return fmt.Errorf("%w: must be one of %q or %q", ErrInvalidEnsureValue, EnsurePresent, EnsureAbsent)
+	}
+
+	if !yumRepoIdRegex.MatchString(p.Name) {
+		return fmt.Errorf("invalid repository id %q", p.Name)
+	}
+
+	if p.Ensure == EnsureAbsent {
+		return nil
+	}
+
+	if strings.ContainsAny(p.Description, "\n\r") {
+		return fmt.Errorf("description must be a single line")
+	}
+
+	if p.BaseURL == "" {
+		return fmt.Errorf("baseurl is required when ensure is %q", EnsurePresent)
+	}
+
+	err = validateYumRepoUrl("baseurl", p.BaseURL)
+	if err != nil {
+		return err
+	}
+
+	if p.GPGKey != "" {
+		err = validateYumRepoUrl("gpgkey", p.GPGKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateYumRepoUrl ensures u is a single absolute url the package manager can fetch
+func validateYumRepoUrl(field string, u string) error {
+	if strings.ContainsAny(u, "\n\r \t") {
+		return fmt.Errorf("%s must be a single url", field)
+	}
+
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", field, err)
+	}
+
+	if !slices.Contains(yumRepoUrlSchemes, parsed.Scheme) {
+		return fmt.Errorf("%s must be a %s url", field, strings.Join(yumRepoUrlSchemes, ", "))
+	}
+
+	// catches templates like https://{{ Facts.mirror }}/repo that rendered an empty host
+	if parsed.Scheme != "file" && parsed.Host == "" {
+		return fmt.Errorf("%s %q must include a host", field, u)
+	}
+
+	return nil
+}
+
+// ResolveTemplates resolves template expressions in the yumrepo resource properties
+func (p *YumRepoResourceProperties) ResolveTemplates(env *templates.Env) error {
+	err := templates.ResolveStructTemplates(p, env, false)
+	if err != nil {
+		return err
+	}
+
+	return p.resolveRegistrations(env)
+}
+
+// ToYamlManifest returns the yumrepo resource properties as a yaml document
+func (p *YumRepoResourceProperties) ToYamlManifest() (yaml.RawMessage, error) {
+	return yaml.Marshal(p)
+}
+
+// NewYumRepoResourcePropertiesFromYaml creates a new yumrepo resource properties object from a yaml document, does not validate or expand templates
+func NewYumRepoResourcePropertiesFromYaml(raw yaml.RawMessage) ([]ResourceProperties, error) {
+	res, err := parseProperties(raw, YumRepoTypeName, func() ResourceProperties { return &YumRepoResourceProperties{} })
+	if err != nil {
+		return nil, err
+	}
+
+	for _, prop := range res {
+		p := prop.(*YumRepoResourceProperties)
+		if p.Ensure == "" {
+			p.Ensure = EnsurePresent
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("YumRepoResourceProperties", func() {
+	Describe("Validate", func() {
+		DescribeTable("validation tests",
+			func(name, ensure, baseurl, gpgkey, description string, errorText string) {
+				prop := &YumRepoResourceProperties{
+					CommonResourceProperties: CommonResourceProperties{
+						Name:   name,
+						Ensure: ensure,
+					},
+					BaseURL:     baseurl,
+					GPGKey:      gpgkey,
+					Description: description,
+				}
+
+				err := prop.Validate()
+
+				if errorText != "" {
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring(errorText))
+				} else {
+					Expect(err).ToNot(HaveOccurred())
+				}
+			},
+
+			Entry("valid repository", "epel", "present", "https://mirror.example.net/epel/$releasever/", "https://mirror.example.net/KEY", "EPEL", ""),
+			Entry("valid file repository", "local", "present", "file:///srv/repo", "", "", ""),
+			Entry("valid absent without baseurl", "epel", "absent", "", "", "", ""),
+
+			Entry("invalid ensure", "epel", "running", "https://mirror.example.net/", "", "", "invalid ensure value"),
+			Entry("invalid id", "../epel", "present", "https://mirror.example.net/", "", "", `invalid repository id "../epel"`),
+			Entry("missing baseurl", "epel", "present", "", "", "", "baseurl is required"),
+			Entry("relative baseurl", "epel", "present", "mirror.example.net/epel", "", "", "baseurl must be a http, https, ftp, file url"),
+			Entry("baseurl without host", "epel", "present", "https:///epel", "", "", "must include a host"),
+			Entry("multiple baseurls", "epel", "present", "https://a.example.net/ https://b.example.net/", "", "", "baseurl must be a single url"),
+			Entry("invalid gpgkey", "epel", "present", "https://mirror.example.net/", "KEY", "", "gpgkey must be a http"),
+			Entry("multi line description", "epel", "present", "https://mirror.example.net/", "", "a\nb", "description must be a single line"),
+		)
+	})
+
+	Describe("FileContent", func() {
+		It("Should render the repository with the name as description", func() {
+			enabled := false
+			prop := &YumRepoResourceProperties{
+				CommonResourceProperties: CommonResourceProperties{Name: "epel"},
+				BaseURL:                  "https://mirror.example.net/",
+				Enabled:                  &enabled,
+			}
+			Expect(string(prop.FileContent())).To(Equal(YumRepoFileHeader + "\n[epel]\nname=epel\nbaseurl=https://mirror.example.net/\nenabled=0\n"))
+			Expect(prop.FileName()).To(Equal("epel.repo"))
+		})
+	})
+
+	Describe("NewYumRepoResourcePropertiesFromYaml", func() {
+		It("Should default ensure to present", func() {
+			res, err := NewYumRepoResourcePropertiesFromYaml([]byte(`- epel:
+    baseurl: https://mirror.example.net/`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(HaveLen(1))
+			Expect(res[0].CommonProperties().Ensure).To(Equal(EnsurePresent))
+			Expect(res[0].(*YumRepoResourceProperties).IsEnabled()).To(BeTrue())
+		})
+	})
+})
@@ -16,9 +16,18 @@ type ExtendedExecOptions struct {
 	Environment []string
 	Path        string
 	Timeout     time.Duration
+	Locale      string // Locale sets LANG and LC_ALL, defaults to C so command output parses the same on every host
+	Timezone    string // Timezone sets TZ, the system timezone is used when empty
+	User        string // User runs the command as another user, requires sufficient privileges
 }
 
 type CommandRunner interface {
 	Execute(ctx context.Context, cmd string, args ...string) (stdout []byte, stderr []byte, exitCode int, err error)
 	ExecuteWithOptions(ctx context.Context, opts ExtendedExecOptions) ([]byte, []byte, int, error)
 }
+
+// RedactingCommandRunner is a CommandRunner that stores the commands it runs, values passed to Redact are never stored
+type RedactingCommandRunner interface {
+	CommandRunner
+	Redact(values ...string)
+}
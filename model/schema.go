@@ -0,0 +1,96 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// SchemaDialect is the JSON Schema dialect of schemas produced by Schema
+const SchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema returns a JSON Schema describing the properties of the resource type typeName. The schema is generated
+// from the json tags of the properties struct, including the common properties, so it always matches the
+// properties the type supports. Only name and ensure are required as those are the only properties required
+// by every resource type.
+func Schema(typeName string) ([]byte, error) {
+	prop, err := NewEmptyResourceProperties(typeName)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := schemaForType(reflect.TypeOf(prop), map[reflect.Type]bool{})
+	schema["$schema"] = SchemaDialect
+	schema["title"] = typeName + " resource properties"
+	schema["required"] = []string{"ensure", "name"}
+	schema["additionalProperties"] = false
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// schemaFields returns the serialized properties of a struct and their types, embedded structs are flattened
+func schemaFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			for name, ft := range schemaFields(field.Type) {
+				fields[name] = ft
+			}
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || name == "" {
+			continue
+		}
+
+		fields[name] = field.Type
+	}
+
+	return fields
+}
+
+// schemaForType produces a schema based on the Go type, seen guards against recursive types which are
+// described without their properties
+func schemaForType(t reflect.Type, seen map[reflect.Type]bool) map[string]any {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaForType(t.Elem(), seen)
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": schemaForType(t.Elem(), seen)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaForType(t.Elem(), seen)}
+	case reflect.Struct:
+		if seen[t] {
+			return map[string]any{"type": "object"}
+		}
+		seen[t] = true
+		defer delete(seen, t)
+
+		properties := map[string]any{}
+		for name, ft := range schemaFields(t) {
+			properties[name] = schemaForType(ft, seen)
+		}
+		return map[string]any{"type": "object", "properties": properties}
+	default:
+		return map[string]any{}
+	}
+}
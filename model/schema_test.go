@@ -0,0 +1,81 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+var _ = Describe("Schema", func() {
+	It("Should require name and ensure for files", func() {
+		out, err := Schema(FileTypeName)
+		Expect(err).ToNot(HaveOccurred())
+
+		var schema map[string]any
+		Expect(json.Unmarshal(out, &schema)).To(Succeed())
+
+		Expect(schema["$schema"]).To(Equal(SchemaDialect))
+		Expect(schema["required"]).To(ConsistOf("name", "ensure"))
+
+		properties := schema["properties"].(map[string]any)
+		Expect(properties).To(HaveKey("name"))
+		Expect(properties).To(HaveKey("ensure"))
+		Expect(properties).To(HaveKey("require"))
+		Expect(properties).ToNot(HaveKey("Type"))
+		Expect(properties["mode"]).To(Equal(map[string]any{"type": "string"}))
+		Expect(properties["manage_parents"]).To(Equal(map[string]any{"type": "boolean"}))
+		Expect(properties["recurse"]).To(Equal(map[string]any{"type": "boolean"}))
+	})
+
+	It("Should generate a valid schema for every resource type", func() {
+		for _, typeName := range []string{FileTypeName, ServiceTypeName, PackageTypeName, ArchiveTypeName, ScaffoldTypeName, ExecTypeName} {
+			out, err := Schema(typeName)
+			Expect(err).ToNot(HaveOccurred(), typeName)
+
+			parsed, err := jsonschema.UnmarshalJSON(bytes.NewReader(out))
+			Expect(err).ToNot(HaveOccurred(), typeName)
+
+			c := jsonschema.NewCompiler()
+			Expect(c.AddResource("schema.json", parsed)).To(Succeed(), typeName)
+			_, err = c.Compile("schema.json")
+			Expect(err).ToNot(HaveOccurred(), typeName)
+		}
+	})
+
+	It("Should validate properties", func() {
+		out, err := Schema(PackageTypeName)
+		Expect(err).ToNot(HaveOccurred())
+
+		parsed, err := jsonschema.UnmarshalJSON(bytes.NewReader(out))
+		Expect(err).ToNot(HaveOccurred())
+
+		c := jsonschema.NewCompiler()
+		Expect(c.AddResource("package.json", parsed)).To(Succeed())
+		sch, err := c.Compile("package.json")
+		Expect(err).ToNot(HaveOccurred())
+
+		valid, err := jsonschema.UnmarshalJSON(bytes.NewReader([]byte(`{"name": "zsh", "ensure": "present"}`)))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sch.Validate(valid)).To(Succeed())
+
+		missing, err := jsonschema.UnmarshalJSON(bytes.NewReader([]byte(`{"name": "zsh"}`)))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sch.Validate(missing)).ToNot(Succeed())
+
+		unknown, err := jsonschema.UnmarshalJSON(bytes.NewReader([]byte(`{"name": "zsh", "ensure": "present", "bogus": true}`)))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sch.Validate(unknown)).ToNot(Succeed())
+	})
+
+	It("Should fail for unknown types", func() {
+		_, err := Schema("unknown")
+		Expect(err).To(MatchError(ErrUnknownType))
+	})
+})
@@ -39,8 +39,31 @@ type SessionStore interface {
 	AllEvents() ([]SessionEvent, error)
 }
 
+// PendingRefreshStore is implemented by session stores that can persist refreshes across runs, a refresh is pending
+// from the time a subscribed resource changed until the subscribing resource was successfully applied
+type PendingRefreshStore interface {
+	// AddPendingRefresh records that resourceId, in type#name format, should be refreshed because source changed
+	AddPendingRefresh(resourceId string, source string) error
+	// PendingRefresh returns the source of an unprocessed refresh for resourceId, empty when none is pending
+	PendingRefresh(resourceId string) (string, error)
+	// ClearPendingRefresh removes any pending refresh for resourceId
+	ClearPendingRefresh(resourceId string) error
+}
+
+// ConvergedStateStore is implemented by session stores that remember resources found in their desired state, later
+// runs can trust that result and skip checking the resource while its inputs are unchanged
+type ConvergedStateStore interface {
+	// RecordConverged records that resourceId, in type#name format, was in its desired state with inputs summarized by digest
+	RecordConverged(resourceId string, digest string) error
+	// Converged returns the input digest and the time resourceId was recorded as converged, empty when not recorded
+	Converged(resourceId string) (string, time.Time, error)
+	// ClearConverged removes all converged records so the next run checks every resource
+	ClearConverged() error
+}
+
 const TransactionEventProtocol = "io.choria.ccm.v1.transaction.event"
 const SessionStartEventProtocol = "io.choria.ccm.v1.session.start"
+const ManagementPausedEventProtocol = "io.choria.ccm.v1.session.paused"
 
 // **NOTE** If this change also update metrics, event summary, cmd and CommonResourceState
 
@@ -55,18 +78,26 @@ type TransactionEvent struct {
 	Alias           string               `json:"alias,omitempty" yaml:"alias,omitempty"`
 	RequestedEnsure string               `json:"requested_ensure" yaml:"requested_ensure"` // RequestedEnsure is the requested ensure value in the initial properties
 	FinalEnsure     string               `json:"final_ensure" yaml:"final_ensure"`         // FinalEnsure is the actual `ensure` value after the session
+	StartedAt       time.Time            `json:"started_at" yaml:"started_at"`             // StartedAt is when the resource started being applied or checked
+	CompletedAt     time.Time            `json:"completed_at" yaml:"completed_at"`         // CompletedAt is when the resource completed being applied or checked
 	Duration        time.Duration        `json:"duration" yaml:"duration"`
 	Properties      any                  `json:"properties" yaml:"properties"`
 	Status          any                  `json:"status" yaml:"status"`
 	NoopMessage     string               `json:"noop_message,omitempty" yaml:"noop_message,omitempty"`
+	Diff            string               `json:"diff,omitempty" yaml:"diff,omitempty"` // Diff is a unified diff of the content a noop run would have changed
 	HealthChecks    []*HealthCheckResult `json:"health_check,omitempty" yaml:"health_check,omitempty"`
 	HealthCheckOnly bool                 `json:"health_check_only,omitempty" yaml:"health_check_only,omitempty"`
 
 	Errors            []string `json:"error" yaml:"error"`
 	Changed           bool     `json:"changed" yaml:"changed"`
-	Refreshed         bool     `json:"refreshed" yaml:"refreshed"` // Refreshed indicates the resource was restarted/reloaded via subscribe
+	Refreshed         bool     `json:"refreshed" yaml:"refreshed"`                       // Refreshed indicates the resource was restarted/reloaded via subscribe
+	Corrective        bool     `json:"corrective,omitempty" yaml:"corrective,omitempty"` // Corrective indicates the change repaired drift rather than creating the resource
 	Failed            bool     `json:"failed" yaml:"failed"`
 	Skipped           bool     `json:"skipped" yaml:"skipped"`
+	NotApplicable     bool     `json:"not_applicable,omitempty" yaml:"not_applicable,omitempty"`       // NotApplicable indicates the resource was skipped as no provider could manage it on this node
+	DeadlineExceeded  bool     `json:"deadline_exceeded,omitempty" yaml:"deadline_exceeded,omitempty"` // DeadlineExceeded indicates the resource was canceled or skipped as the run deadline passed
+	ConditionNotMet   bool     `json:"condition_not_met,omitempty" yaml:"condition_not_met,omitempty"` // ConditionNotMet indicates the resource was skipped as its apply_if expression was false
+	Trusted           bool     `json:"trusted,omitempty" yaml:"trusted,omitempty"`                     // Trusted indicates the resource was not checked as an earlier run found it converged with identical inputs
 	Noop              bool     `json:"noop" yaml:"noop"`
 	UnmetRequirements []string `json:"unmet_requirements" yaml:"unmet_requirements"`
 }
@@ -85,6 +116,23 @@ func NewSessionStartEvent() *SessionStartEvent {
 	}
 }
 
+// ManagementPausedEvent records that a session ran in monitor-only mode because the pause marker was present
+type ManagementPausedEvent struct {
+	Protocol  string    `json:"protocol" yaml:"protocol"`
+	EventID   string    `json:"event_id" yaml:"event_id"`
+	TimeStamp time.Time `json:"timestamp" yaml:"timestamp"`
+	Marker    string    `json:"marker" yaml:"marker"`
+}
+
+func NewManagementPausedEvent(marker string) *ManagementPausedEvent {
+	return &ManagementPausedEvent{
+		Protocol:  ManagementPausedEventProtocol,
+		EventID:   ksuid.New().String(),
+		TimeStamp: time.Now().UTC(),
+		Marker:    marker,
+	}
+}
+
 func NewTransactionEvent(typeName string, name string, alias string) *TransactionEvent {
 	return &TransactionEvent{
 		Protocol:          TransactionEventProtocol,
@@ -103,6 +151,11 @@ func (t *SessionStartEvent) String() string {
 	return fmt.Sprintf("session %s started %s", t.EventID, t.TimeStamp.Format(time.RFC3339))
 }
 
+func (t *ManagementPausedEvent) SessionEventID() string { return t.EventID }
+func (t *ManagementPausedEvent) String() string {
+	return fmt.Sprintf("management paused by %s at %s", t.Marker, t.TimeStamp.Format(time.RFC3339))
+}
+
 func (t *TransactionEvent) SessionEventID() string { return t.EventID }
 
 func (t *TransactionEvent) LogStatus(log Logger) {
@@ -134,11 +187,22 @@ func (t *TransactionEvent) LogStatus(log Logger) {
 			args = append(args, "unmet", req)
 		}
 		log.Error(fmt.Sprintf("%s skipped due to unmet requirement", rname), args...)
+	case t.NotApplicable:
+		log.Info(fmt.Sprintf("%s not applicable", rname), append(args, "reason", strings.Join(t.Errors, ", "))...)
+	case t.DeadlineExceeded:
+		log.Warn(fmt.Sprintf("%s skipped due to run deadline", rname), args...)
+	case t.ConditionNotMet:
+		log.Info(fmt.Sprintf("%s skipped as condition not met", rname), args...)
 	case t.Skipped:
 		log.Warn(fmt.Sprintf("%s skipped", rname), args...)
+	case t.Trusted:
+		log.Info(fmt.Sprintf("%s stable (trusted)", rname), args...)
 	case t.Refreshed:
 		log.Warn(fmt.Sprintf("%s refreshed", rname), args...)
 	case t.Changed:
+		if t.Corrective {
+			args = append(args, "corrective", true)
+		}
 		log.Warn(fmt.Sprintf("%s changed", rname), args...)
 	default:
 		log.Info(fmt.Sprintf("%s stable", rname), args...)
@@ -156,8 +220,16 @@ func (t *TransactionEvent) String() string {
 		return fmt.Sprintf("%s failed ensure=%s runtime=%v errors=%v provider=%s", rname, t.RequestedEnsure, t.Duration, strings.Join(t.Errors, ","), t.Provider)
 	case len(t.UnmetRequirements) > 0:
 		return fmt.Sprintf("%s skipped unmet requirements ensure=%s runtime=%v provider=%s unmet:%s", rname, t.RequestedEnsure, t.Duration, t.Provider, strings.Join(t.UnmetRequirements, ", "))
+	case t.NotApplicable:
+		return fmt.Sprintf("%s not applicable ensure=%s runtime=%v reason=%s", rname, t.RequestedEnsure, t.Duration, strings.Join(t.Errors, ","))
+	case t.DeadlineExceeded:
+		return fmt.Sprintf("%s skipped run deadline exceeded ensure=%s runtime=%v provider=%s", rname, t.RequestedEnsure, t.Duration, t.Provider)
+	case t.ConditionNotMet:
+		return fmt.Sprintf("%s skipped condition not met ensure=%s runtime=%v provider=%s", rname, t.RequestedEnsure, t.Duration, t.Provider)
 	case t.Skipped:
 		return fmt.Sprintf("%s skipped ensure=%s runtime=%v provider=%s", rname, t.RequestedEnsure, t.Duration, t.Provider)
+	case t.Trusted:
+		return fmt.Sprintf("%s trusted ensure=%s runtime=%v provider=%s", rname, t.RequestedEnsure, t.Duration, t.Provider)
 	case t.Changed:
 		return fmt.Sprintf("%s changed ensure=%s runtime=%v provider=%s", rname, t.RequestedEnsure, t.Duration, t.Provider)
 	case t.Refreshed:
@@ -169,28 +241,83 @@ func (t *TransactionEvent) String() string {
 
 // SessionSummary provides a statistical summary of a configuration management session
 type SessionSummary struct {
-	StartTime                time.Time     `json:"start_time" yaml:"start_time"`
-	EndTime                  time.Time     `json:"end_time" yaml:"end_time"`
-	TotalDuration            time.Duration `json:"total_duration" yaml:"total_duration"`
-	TotalResources           int           `json:"total_resources" yaml:"total_resources"`
-	UniqueResources          int           `json:"unique_resources" yaml:"unique_resources"`
-	ChangedResources         int           `json:"changed_resources" yaml:"changed_resources"`
-	FailedResources          int           `json:"failed_resources" yaml:"failed_resources"`
-	SkippedResources         int           `json:"skipped_resources" yaml:"skipped_resources"`
-	StableResources          int           `json:"stable_resources" yaml:"stable_resources"`
-	RefreshedCount           int           `json:"refreshed_count" yaml:"refreshed_count"`
-	RequirementsUnMetCount   int           `json:"requirements_unmet_count" yaml:"requirements_unmet_count"`
-	HealthCheckedCount       int           `json:"health_checked_count" yaml:"health_checked_count"`
-	HealthCheckOKCount       int           `json:"health_check_ok_count" yaml:"health_check_ok_count"`
-	HealthCheckWarningCount  int           `json:"health_check_warning_count" yaml:"health_check_warning_count"`
-	HealthCheckCriticalCount int           `json:"health_check_critical_count" yaml:"health_check_critical_count"`
-	HealthCheckUnknownCount  int           `json:"health_check_unknown_count" yaml:"health_check_unknown_count"`
-	TotalErrors              int           `json:"total_errors" yaml:"total_errors"`
+	StartTime                 time.Time              `json:"start_time" yaml:"start_time"`
+	EndTime                   time.Time              `json:"end_time" yaml:"end_time"`
+	TotalDuration             time.Duration          `json:"total_duration" yaml:"total_duration"`
+	SlowestResource           string                 `json:"slowest_resource,omitempty" yaml:"slowest_resource,omitempty"`                   // SlowestResource is the type#name of the resource that took the longest
+	SlowestResourceDuration   time.Duration          `json:"slowest_resource_duration,omitempty" yaml:"slowest_resource_duration,omitempty"` // SlowestResourceDuration is how long the slowest resource took
+	TotalResources            int                    `json:"total_resources" yaml:"total_resources"`
+	UniqueResources           int                    `json:"unique_resources" yaml:"unique_resources"`
+	ChangedResources          int                    `json:"changed_resources" yaml:"changed_resources"`
+	CorrectiveResources       int                    `json:"corrective_resources" yaml:"corrective_resources"`
+	FailedResources           int                    `json:"failed_resources" yaml:"failed_resources"`
+	SkippedResources          int                    `json:"skipped_resources" yaml:"skipped_resources"`
+	NotApplicableResources    int                    `json:"not_applicable_resources" yaml:"not_applicable_resources"`
+	DeadlineExceededResources int                    `json:"deadline_exceeded_resources" yaml:"deadline_exceeded_resources"`
+	StableResources           int                    `json:"stable_resources" yaml:"stable_resources"`
+	TrustedResources          int                    `json:"trusted_resources,omitempty" yaml:"trusted_resources,omitempty"`
+	RefreshedCount            int                    `json:"refreshed_count" yaml:"refreshed_count"`
+	RequirementsUnMetCount    int                    `json:"requirements_unmet_count" yaml:"requirements_unmet_count"`
+	HealthCheckedCount        int                    `json:"health_checked_count" yaml:"health_checked_count"`
+	HealthCheckOKCount        int                    `json:"health_check_ok_count" yaml:"health_check_ok_count"`
+	HealthCheckWarningCount   int                    `json:"health_check_warning_count" yaml:"health_check_warning_count"`
+	HealthCheckCriticalCount  int                    `json:"health_check_critical_count" yaml:"health_check_critical_count"`
+	HealthCheckUnknownCount   int                    `json:"health_check_unknown_count" yaml:"health_check_unknown_count"`
+	TotalErrors               int                    `json:"total_errors" yaml:"total_errors"`
+	Noop                      bool                   `json:"noop" yaml:"noop"`
+	Paused                    bool                   `json:"paused,omitempty" yaml:"paused,omitempty"`
+	DriftRatio                float64                `json:"drift_ratio" yaml:"drift_ratio"`
+	WouldDriftRatio           float64                `json:"would_drift_ratio" yaml:"would_drift_ratio"`
+	DriftByType               map[string]*DriftStats `json:"drift_by_type,omitempty" yaml:"drift_by_type,omitempty"`
+}
+
+// SessionReport is the summary and transaction events of a completed session as delivered to report sinks
+type SessionReport struct {
+	Summary *SessionSummary     `json:"summary" yaml:"summary"`
+	Events  []*TransactionEvent `json:"events" yaml:"events"`
+}
+
+// DriftStats is the share of resources of a type that were not in their desired state
+type DriftStats struct {
+	Total   int     `json:"total" yaml:"total"`
+	Drifted int     `json:"drifted" yaml:"drifted"`
+	Ratio   float64 `json:"ratio" yaml:"ratio"`
+}
+
+const (
+	// ResultCodeNoChanges is the result code of a session where every resource was stable
+	ResultCodeNoChanges = 0
+	// ResultCodeFailed is the result code of a session where any resource failed
+	ResultCodeFailed = 1
+	// ResultCodeChanged is the result code of a session that made changes without failures
+	ResultCodeChanged = 2
+)
+
+// ResultCode summarizes the session as a process exit code in the style of terraform and puppet detailed exit codes,
+// failures take precedence over changes. In noop mode changes that would have been made count as changes.
+func (s *SessionSummary) ResultCode() int {
+	switch {
+	case s.FailedResources > 0 || s.TotalErrors > 0:
+		return ResultCodeFailed
+	case s.ChangedResources > 0:
+		return ResultCodeChanged
+	default:
+		return ResultCodeNoChanges
+	}
+}
+
+// Drift is the ratio of drifted resources across all types, the would drift ratio in noop mode
+func (s *SessionSummary) Drift() float64 {
+	if s.Noop {
+		return s.WouldDriftRatio
+	}
+
+	return s.DriftRatio
 }
 
 // BuildSessionSummary creates a summary report from all events in a session
 func BuildSessionSummary(events []SessionEvent) *SessionSummary {
-	summary := &SessionSummary{}
+	summary := &SessionSummary{DriftByType: map[string]*DriftStats{}}
 	var totalTime time.Duration
 	var uniques = map[string]struct{}{}
 
@@ -201,6 +328,12 @@ func BuildSessionSummary(events []SessionEvent) *SessionSummary {
 			continue
 		}
 
+		// Management being paused turns the session into a monitor-only run
+		if _, ok := event.(*ManagementPausedEvent); ok {
+			summary.Paused = true
+			continue
+		}
+
 		// Handle transaction events
 		txEvent, ok := event.(*TransactionEvent)
 		if !ok {
@@ -209,8 +342,27 @@ func BuildSessionSummary(events []SessionEvent) *SessionSummary {
 
 		totalTime += txEvent.Duration
 		summary.TotalResources++
+
+		if txEvent.Duration > summary.SlowestResourceDuration {
+			summary.SlowestResource = txEvent.ResourceType + "#" + txEvent.Name
+			summary.SlowestResourceDuration = txEvent.Duration
+		}
 		uniques[txEvent.ResourceType+"#"+txEvent.Name] = struct{}{}
 
+		if txEvent.Noop {
+			summary.Noop = true
+		}
+
+		drift, ok := summary.DriftByType[txEvent.ResourceType]
+		if !ok {
+			drift = &DriftStats{}
+			summary.DriftByType[txEvent.ResourceType] = drift
+		}
+		drift.Total++
+		if txEvent.Failed || (txEvent.Changed && !txEvent.Skipped) {
+			drift.Drifted++
+		}
+
 		// Track the latest timestamp as end time
 		if txEvent.TimeStamp.After(summary.EndTime) {
 			summary.EndTime = txEvent.TimeStamp
@@ -223,10 +375,26 @@ func BuildSessionSummary(events []SessionEvent) *SessionSummary {
 			summary.TotalErrors++
 		case txEvent.Skipped:
 			summary.SkippedResources++
+			// Not applicable resources are a subset of skipped resources
+			if txEvent.NotApplicable {
+				summary.NotApplicableResources++
+			}
+			// So are resources canceled or not started due to the run deadline
+			if txEvent.DeadlineExceeded {
+				summary.DeadlineExceededResources++
+			}
 		case txEvent.Changed:
 			summary.ChangedResources++
+			// Corrective changes are a subset of changed resources
+			if txEvent.Corrective {
+				summary.CorrectiveResources++
+			}
 		default:
 			summary.StableResources++
+			// Trusted resources are a subset of stable resources
+			if txEvent.Trusted {
+				summary.TrustedResources++
+			}
 		}
 
 		// Track requirements aren't met separately as they are considered stable
@@ -254,6 +422,21 @@ func BuildSessionSummary(events []SessionEvent) *SessionSummary {
 
 	summary.UniqueResources = len(uniques)
 
+	// Drift is changed and failed resources over all resources, in noop mode
+	// changes are not made so it is reported as the would drift ratio
+	if summary.TotalResources > 0 {
+		ratio := float64(summary.ChangedResources+summary.FailedResources) / float64(summary.TotalResources)
+		if summary.Noop {
+			summary.WouldDriftRatio = ratio
+		} else {
+			summary.DriftRatio = ratio
+		}
+	}
+
+	for _, drift := range summary.DriftByType {
+		drift.Ratio = float64(drift.Drifted) / float64(drift.Total)
+	}
+
 	// Calculate total duration
 	if !summary.StartTime.IsZero() && !summary.EndTime.IsZero() {
 		summary.TotalDuration = summary.EndTime.Sub(summary.StartTime)
@@ -275,6 +458,22 @@ func (s *SessionSummary) String() string {
 		"refreshed=" + strconv.Itoa(s.RefreshedCount),
 	}
 
+	if s.CorrectiveResources > 0 {
+		parts = append(parts, "corrective="+strconv.Itoa(s.CorrectiveResources))
+	}
+
+	if s.TrustedResources > 0 {
+		parts = append(parts, "trusted="+strconv.Itoa(s.TrustedResources))
+	}
+
+	if s.NotApplicableResources > 0 {
+		parts = append(parts, "not_applicable="+strconv.Itoa(s.NotApplicableResources))
+	}
+
+	if s.DeadlineExceededResources > 0 {
+		parts = append(parts, "deadline_exceeded="+strconv.Itoa(s.DeadlineExceededResources))
+	}
+
 	if s.HealthCheckedCount > 0 {
 		if s.HealthCheckCriticalCount > 0 {
 			parts = append(parts, "health_critical="+strconv.Itoa(s.HealthCheckCriticalCount))
@@ -290,6 +489,16 @@ func (s *SessionSummary) String() string {
 		}
 	}
 
+	if s.Paused {
+		parts = append(parts, "paused=true")
+	}
+
+	if s.Noop {
+		parts = append(parts, "would_drift="+formatDriftRatio(s.WouldDriftRatio))
+	} else {
+		parts = append(parts, "drift="+formatDriftRatio(s.DriftRatio))
+	}
+
 	parts = append(parts, "duration="+s.TotalDuration.Round(time.Millisecond).String())
 
 	return fmt.Sprintf("Session: %s", strings.Join(parts, ", "))
@@ -299,11 +508,26 @@ func (s *SessionSummary) RenderText(w io.Writer) {
 	fmt.Fprintln(w, "Manifest Run Summary")
 	fmt.Fprintln(w)
 	fmt.Fprintf(w, "             Run Time: %v\n", s.TotalDuration.Round(time.Millisecond))
+	if s.SlowestResource != "" {
+		fmt.Fprintf(w, "     Slowest Resource: %s (%v)\n", s.SlowestResource, s.SlowestResourceDuration.Round(time.Millisecond))
+	}
 	fmt.Fprintf(w, "      Total Resources: %d\n", s.TotalResources)
 	fmt.Fprintf(w, "     Stable Resources: %d\n", s.StableResources)
+	if s.TrustedResources > 0 {
+		fmt.Fprintf(w, "    Trusted Resources: %d\n", s.TrustedResources)
+	}
 	fmt.Fprintf(w, "    Changed Resources: %d\n", s.ChangedResources)
+	if s.CorrectiveResources > 0 {
+		fmt.Fprintf(w, "   Corrective Changes: %d\n", s.CorrectiveResources)
+	}
 	fmt.Fprintf(w, "     Failed Resources: %d\n", s.FailedResources)
 	fmt.Fprintf(w, "    Skipped Resources: %d\n", s.SkippedResources)
+	if s.NotApplicableResources > 0 {
+		fmt.Fprintf(w, "       Not Applicable: %d\n", s.NotApplicableResources)
+	}
+	if s.DeadlineExceededResources > 0 {
+		fmt.Fprintf(w, "    Deadline Exceeded: %d\n", s.DeadlineExceededResources)
+	}
 	fmt.Fprintf(w, "  Refreshed Resources: %d\n", s.RefreshedCount)
 	fmt.Fprintf(w, "   Unmet Requirements: %d\n", s.RequirementsUnMetCount)
 	if s.HealthCheckOKCount > 0 || s.HealthCheckWarningCount > 0 || s.HealthCheckCriticalCount > 0 || s.HealthCheckUnknownCount > 0 {
@@ -312,4 +536,13 @@ func (s *SessionSummary) RenderText(w io.Writer) {
 		fmt.Fprintf(w, "    Checked Resources: %d\n", s.HealthCheckedCount)
 	}
 	fmt.Fprintf(w, "         Total Errors: %d\n", s.TotalErrors)
+	if s.Noop {
+		fmt.Fprintf(w, "          Would Drift: %s\n", formatDriftRatio(s.WouldDriftRatio))
+	} else {
+		fmt.Fprintf(w, "                Drift: %s\n", formatDriftRatio(s.DriftRatio))
+	}
+}
+
+func formatDriftRatio(ratio float64) string {
+	return strconv.FormatFloat(ratio*100, 'f', 1, 64) + "%"
 }
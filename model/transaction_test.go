@@ -5,6 +5,7 @@
 package model
 
 import (
+	"bytes"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -78,6 +79,27 @@ var _ = Describe("TransactionEvent", func() {
 			Expect(str).To(ContainSubstring("skipped"))
 		})
 
+		It("Should format not applicable event correctly", func() {
+			event := NewTransactionEvent("package", "vim", "")
+			event.Skipped = true
+			event.NotApplicable = true
+			event.RequestedEnsure = "present"
+			event.Errors = append(event.Errors, "no suitable provider found")
+
+			str := event.String()
+			Expect(str).To(ContainSubstring("package#vim not applicable"))
+			Expect(str).To(ContainSubstring("reason=no suitable provider found"))
+		})
+
+		It("Should format deadline exceeded event correctly", func() {
+			event := NewTransactionEvent("exec", "slow", "")
+			event.Skipped = true
+			event.DeadlineExceeded = true
+			event.RequestedEnsure = "present"
+
+			Expect(event.String()).To(ContainSubstring("exec#slow skipped run deadline exceeded"))
+		})
+
 		It("Should format refreshed event correctly", func() {
 			event := NewTransactionEvent("service", "nginx", "proxy")
 			event.Refreshed = true
@@ -168,6 +190,155 @@ var _ = Describe("SessionSummary", func() {
 			Expect(summary.TotalDuration).To(Equal(20 * time.Second))
 		})
 
+		It("Should count not applicable resources as skipped", func() {
+			naEvent := NewTransactionEvent("package", "vim", "")
+			naEvent.Skipped = true
+			naEvent.NotApplicable = true
+
+			skippedEvent := NewTransactionEvent("package", "zsh", "")
+			skippedEvent.Skipped = true
+
+			summary := BuildSessionSummary([]SessionEvent{naEvent, skippedEvent})
+
+			Expect(summary.SkippedResources).To(Equal(2))
+			Expect(summary.NotApplicableResources).To(Equal(1))
+			Expect(summary.FailedResources).To(Equal(0))
+			Expect(summary.String()).To(ContainSubstring("not_applicable=1"))
+		})
+
+		It("Should count deadline exceeded resources as skipped rather than failed", func() {
+			canceledEvent := NewTransactionEvent("exec", "slow", "")
+			canceledEvent.Skipped = true
+			canceledEvent.DeadlineExceeded = true
+			canceledEvent.Errors = append(canceledEvent.Errors, "context deadline exceeded")
+
+			remainingEvent := NewTransactionEvent("package", "zsh", "")
+			remainingEvent.Skipped = true
+			remainingEvent.DeadlineExceeded = true
+
+			summary := BuildSessionSummary([]SessionEvent{canceledEvent, remainingEvent})
+
+			Expect(summary.SkippedResources).To(Equal(2))
+			Expect(summary.DeadlineExceededResources).To(Equal(2))
+			Expect(summary.FailedResources).To(Equal(0))
+			Expect(summary.TotalErrors).To(Equal(0))
+			Expect(summary.String()).To(ContainSubstring("deadline_exceeded=2"))
+		})
+
+		It("Should count corrective changes as changed", func() {
+			correctiveEvent := NewTransactionEvent("file", "/etc/motd", "")
+			correctiveEvent.Changed = true
+			correctiveEvent.Corrective = true
+
+			createdEvent := NewTransactionEvent("file", "/etc/issue", "")
+			createdEvent.Changed = true
+
+			summary := BuildSessionSummary([]SessionEvent{correctiveEvent, createdEvent})
+
+			Expect(summary.ChangedResources).To(Equal(2))
+			Expect(summary.CorrectiveResources).To(Equal(1))
+			Expect(summary.String()).To(ContainSubstring("corrective=1"))
+
+			summary = BuildSessionSummary([]SessionEvent{createdEvent})
+			Expect(summary.CorrectiveResources).To(Equal(0))
+			Expect(summary.String()).ToNot(ContainSubstring("corrective="))
+		})
+
+		It("Should calculate the drift ratio overall and per type", func() {
+			changed := NewTransactionEvent("file", "/etc/motd", "")
+			changed.Changed = true
+			failed := NewTransactionEvent("package", "nginx", "")
+			failed.Failed = true
+			stable := NewTransactionEvent("file", "/etc/issue", "")
+			skipped := NewTransactionEvent("package", "zsh", "")
+			skipped.Skipped = true
+
+			summary := BuildSessionSummary([]SessionEvent{changed, failed, stable, skipped})
+
+			Expect(summary.Noop).To(BeFalse())
+			Expect(summary.DriftRatio).To(Equal(0.5))
+			Expect(summary.WouldDriftRatio).To(Equal(0.0))
+			Expect(summary.Drift()).To(Equal(0.5))
+			Expect(summary.DriftByType).To(Equal(map[string]*DriftStats{
+				"file":    {Total: 2, Drifted: 1, Ratio: 0.5},
+				"package": {Total: 2, Drifted: 1, Ratio: 0.5},
+			}))
+			Expect(summary.String()).To(ContainSubstring("drift=50.0%"))
+		})
+
+		It("Should report noop drift as would drift", func() {
+			changed := NewTransactionEvent("file", "/etc/motd", "")
+			changed.Changed = true
+			changed.Noop = true
+			stable := NewTransactionEvent("file", "/etc/issue", "")
+			stable.Noop = true
+
+			summary := BuildSessionSummary([]SessionEvent{changed, stable})
+
+			Expect(summary.Noop).To(BeTrue())
+			Expect(summary.DriftRatio).To(Equal(0.0))
+			Expect(summary.WouldDriftRatio).To(Equal(0.5))
+			Expect(summary.Drift()).To(Equal(0.5))
+			Expect(summary.String()).To(ContainSubstring("would_drift=50.0%"))
+		})
+
+		It("Should report paused sessions", func() {
+			checked := NewTransactionEvent("file", "/etc/motd", "")
+			checked.HealthCheckOnly = true
+
+			summary := BuildSessionSummary([]SessionEvent{NewSessionStartEvent(), NewManagementPausedEvent("/etc/choria/ccm/pause"), checked})
+
+			Expect(summary.Paused).To(BeTrue())
+			Expect(summary.TotalResources).To(Equal(1))
+			Expect(summary.String()).To(ContainSubstring("paused=true"))
+
+			summary = BuildSessionSummary([]SessionEvent{checked})
+			Expect(summary.Paused).To(BeFalse())
+			Expect(summary.String()).ToNot(ContainSubstring("paused"))
+		})
+
+		It("Should report the result code", func() {
+			stable := NewTransactionEvent("file", "/etc/issue", "")
+			changed := NewTransactionEvent("file", "/etc/motd", "")
+			changed.Changed = true
+			failed := NewTransactionEvent("package", "nginx", "")
+			failed.Failed = true
+			skipped := NewTransactionEvent("service", "nginx", "")
+			skipped.Skipped = true
+
+			Expect(BuildSessionSummary(nil).ResultCode()).To(Equal(ResultCodeNoChanges))
+			Expect(BuildSessionSummary([]SessionEvent{NewSessionStartEvent(), stable, skipped}).ResultCode()).To(Equal(ResultCodeNoChanges))
+			Expect(BuildSessionSummary([]SessionEvent{NewSessionStartEvent(), stable, changed}).ResultCode()).To(Equal(ResultCodeChanged))
+			Expect(BuildSessionSummary([]SessionEvent{NewSessionStartEvent(), stable, failed}).ResultCode()).To(Equal(ResultCodeFailed))
+			Expect(BuildSessionSummary([]SessionEvent{NewSessionStartEvent(), changed, failed}).ResultCode()).To(Equal(ResultCodeFailed))
+
+			noopChanged := NewTransactionEvent("file", "/etc/motd", "")
+			noopChanged.Changed = true
+			noopChanged.Noop = true
+			Expect(BuildSessionSummary([]SessionEvent{noopChanged}).ResultCode()).To(Equal(ResultCodeChanged))
+		})
+
+		It("Should identify the slowest resource", func() {
+			fast := NewTransactionEvent("file", "/etc/motd", "")
+			fast.Duration = 10 * time.Millisecond
+			slow := NewTransactionEvent("package", "nginx", "web")
+			slow.Duration = 3 * time.Second
+			medium := NewTransactionEvent("service", "nginx", "")
+			medium.Duration = time.Second
+
+			summary := BuildSessionSummary([]SessionEvent{fast, slow, medium})
+			Expect(summary.SlowestResource).To(Equal("package#nginx"))
+			Expect(summary.SlowestResourceDuration).To(Equal(3 * time.Second))
+			Expect(summary.TotalDuration).To(Equal(4010 * time.Millisecond))
+
+			out := &bytes.Buffer{}
+			summary.RenderText(out)
+			Expect(out.String()).To(ContainSubstring("Slowest Resource: package#nginx (3s)"))
+
+			summary = BuildSessionSummary([]SessionEvent{NewTransactionEvent("file", "/etc/motd", "")})
+			Expect(summary.SlowestResource).To(BeEmpty())
+		})
+
 		It("Should handle empty events", func() {
 			summary := BuildSessionSummary([]SessionEvent{})
 
@@ -179,6 +350,7 @@ var _ = Describe("SessionSummary", func() {
 			Expect(summary.RefreshedCount).To(Equal(0))
 			Expect(summary.TotalErrors).To(Equal(0))
 			Expect(summary.TotalDuration).To(Equal(time.Duration(0)))
+			Expect(summary.DriftRatio).To(Equal(0.0))
 		})
 
 		It("Should handle only session start event", func() {
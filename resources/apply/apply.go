@@ -21,6 +21,7 @@ import (
 
 	"github.com/choria-io/ccm/internal/metrics"
 	"github.com/goccy/go-yaml"
+	"github.com/nats-io/nats.go/jetstream"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/santhosh-tekuri/jsonschema/v6"
 
@@ -100,6 +101,11 @@ func (a *Apply) validateManifest(mb []byte) error {
 		return fmt.Errorf("manifest not parsed")
 	}
 
+	return validateSchema("manifest.json", mb)
+}
+
+// validateSchema validates the yaml document mb against ref, a location within the manifest schema
+func validateSchema(ref string, mb []byte) error {
 	jmb, err := yaml.YAMLToJSON(mb)
 	if err != nil {
 		return err
@@ -127,7 +133,7 @@ func (a *Apply) validateManifest(mb []byte) error {
 		return err
 	}
 
-	sch, err := c.Compile("manifest.json")
+	sch, err := c.Compile(ref)
 	if err != nil {
 		return err
 	}
@@ -289,33 +295,35 @@ func ResolveManifestObjectValue(ctx context.Context, mgr model.Manager, bucket s
 		return nil, nil, "", fmt.Errorf("key is required for object store manifest source")
 	}
 
-	js, err := mgr.JetStream()
-	if err != nil {
-		return nil, nil, "", err
-	}
-
 	log.Debug("Getting manifest data from JetStream Object Store", "key", file, "bucket", bucket)
 
-	timeoutCtx, cancel := context.WithTimeout(ctx, time.Minute)
-	defer cancel()
+	var body []byte
+	err = mgr.JetStreamCall(ctx, func(ctx context.Context, js jetstream.JetStream) error {
+		obj, err := js.ObjectStore(ctx, bucket)
+		if err != nil {
+			return err
+		}
 
-	obj, err := js.ObjectStore(timeoutCtx, bucket)
-	if err != nil {
-		return nil, nil, "", err
-	}
+		res, err := obj.Get(ctx, file)
+		if err != nil {
+			return err
+		}
+		defer res.Close()
 
-	res, err := obj.Get(timeoutCtx, file)
+		body, err = io.ReadAll(res)
+
+		return err
+	})
 	if err != nil {
 		return nil, nil, "", err
 	}
-	defer res.Close()
 
 	td, err := os.MkdirTemp("", "manifest-*")
 	if err != nil {
 		return nil, nil, "", err
 	}
 
-	resolved, apply, manifestPath, err := unTarAndResolve(ctx, res, mgr, td, opts...)
+	resolved, apply, manifestPath, err := unTarAndResolve(ctx, bytes.NewReader(body), mgr, td, opts...)
 	if err != nil {
 		os.RemoveAll(td)
 		return nil, nil, "", err
@@ -388,6 +396,7 @@ type manifestResourcesParser struct {
 	PostMessage      string          `json:"post_message,omitempty" yaml:"post_message,omitempty"`
 	ResourcesJetFile string          `json:"resources_jet_file,omitempty" yaml:"resources_jet_file,omitempty"`
 	FailOnError      bool            `json:"fail_on_error,omitempty" yaml:"fail_on_error,omitempty"`
+	Defaults         yaml.RawMessage `json:"defaults,omitempty" yaml:"defaults,omitempty"`
 	Resources        yaml.RawMessage `json:"resources" yaml:"resources"`
 }
 
@@ -436,6 +445,11 @@ func ResolveManifestReader(ctx context.Context, mgr model.Manager, dir string, m
 		return nil, nil, fmt.Errorf("jet_file requires a directory to be set")
 	}
 
+	defaults, err := parseManifestDefaults(parser.CCM.Defaults)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	apply.preMessage = parser.CCM.PreMessage
 	apply.postMessage = parser.CCM.PostMessage
 
@@ -522,6 +536,11 @@ func ResolveManifestReader(ctx context.Context, mgr model.Manager, dir string, m
 
 	for i, resource := range resources {
 		for typeName, v := range resource {
+			v, err := applyManifestDefaults(v, defaults[typeName])
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid manifest resource %d: %w", i+1, err)
+			}
+
 			props, err := model.NewValidatedResourcePropertiesFromYaml(typeName, v, env)
 			if err != nil {
 				return nil, nil, fmt.Errorf("invalid manifest resource %d: %w", i+1, err)
@@ -534,6 +553,11 @@ func ResolveManifestReader(ctx context.Context, mgr model.Manager, dir string, m
 		}
 	}
 
+	err = model.ResolveNotifications(apply.resources)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Schema validation runs after per-resource parsing so that non-deferred
 	// templates have already been resolved into concrete values. For fields
 	// that remain templated (e.g. deferred or runtime references) we substitute
@@ -574,6 +598,11 @@ func buildValidationParser(parser manifestParser, resources []map[string]model.R
 
 	for _, resMap := range resources {
 		for typeName, prop := range resMap {
+			// custom types are not described by the schema, their properties are validated by the type
+			if model.IsCustomResourceType(typeName) {
+				continue
+			}
+
 			substituted, err := substituteTemplatesForValidation(prop)
 			if err != nil {
 				return parser, fmt.Errorf("preparing %s resource for validation: %w", typeName, err)
@@ -600,25 +629,69 @@ func buildValidationParser(parser manifestParser, resources []map[string]model.R
 	return parser, nil
 }
 
+// Execute applies the manifest, or only runs health checks when healthCheckOnly is set. When any resource failed,
+// or the apply itself failed, the manager writes a debug dump if enabled. Once the apply completed the manager
+// delivers the session report to any configured report sink.
 func (a *Apply) Execute(ctx context.Context, mgr model.Manager, healthCheckOnly bool, userLog model.Logger) (model.SessionStore, error) {
+	session, failed, err := a.execute(ctx, mgr, healthCheckOnly, userLog)
+
+	// nested applies share the session of their parent, the parent writes the dump for the whole run
+	if (failed || err != nil) && mgr != nil && a.currentDepth == 0 {
+		path, derr := mgr.WriteDebugDump(ctx, a, err)
+		switch {
+		case derr != nil:
+			userLog.Error("Could not write debug dump", "error", derr)
+		case path != "":
+			userLog.Warn("Wrote debug dump", "file", path)
+		}
+	}
+
+	// failing to deliver the report is not a failure of the apply
+	if session != nil && mgr != nil && a.currentDepth == 0 {
+		rerr := mgr.DeliverSessionReport(ctx, a)
+		if rerr != nil {
+			userLog.Error("Could not deliver session report", "error", rerr)
+		}
+	}
+
+	return session, err
+}
+
+func (a *Apply) execute(ctx context.Context, mgr model.Manager, healthCheckOnly bool, userLog model.Logger) (model.SessionStore, bool, error) {
+	var failed bool
+
 	if mgr == nil {
-		return nil, fmt.Errorf("manager is required")
+		return nil, failed, fmt.Errorf("manager is required")
 	}
 
 	if ResourceFactory == nil {
-		return nil, fmt.Errorf("ResourceFactory is not initialized; import github.com/choria-io/ccm/resources to register it")
+		return nil, failed, fmt.Errorf("ResourceFactory is not initialized; import github.com/choria-io/ccm/resources to register it")
 	}
 
 	timer := prometheus.NewTimer(metrics.ManifestApplyTime.WithLabelValues(a.source))
 	defer timer.ObserveDuration()
 
 	if mgr.NoopMode() && healthCheckOnly {
-		return nil, fmt.Errorf("cannot set healthcheck only and noop mode at the same time")
+		return nil, failed, fmt.Errorf("cannot set healthcheck only and noop mode at the same time")
 	}
 
 	log, err := mgr.Logger("component", "apply")
 	if err != nil {
-		return nil, err
+		return nil, failed, err
+	}
+
+	var paused bool
+	if !healthCheckOnly && !mgr.NoopMode() {
+		paused, err = mgr.ManagementPaused(ctx)
+		if err != nil {
+			// when we cannot tell if an operator paused management it is safest to not make changes
+			userLog.Error("Could not determine if management is paused, only running health checks", "marker", mgr.PauseMarker(), "error", err)
+			paused = true
+		} else if paused {
+			userLog.Warn("Management is paused, only running health checks", "marker", mgr.PauseMarker())
+		}
+
+		healthCheckOnly = paused
 	}
 
 	if healthCheckOnly {
@@ -635,82 +708,204 @@ func (a *Apply) Execute(ctx context.Context, mgr model.Manager, healthCheckOnly
 	if !a.skipSession {
 		session, err = mgr.StartSession(a)
 		if err != nil {
-			return nil, err
+			return nil, failed, err
+		}
+	}
+
+	if paused && session != nil {
+		err = session.RecordEvent(model.NewManagementPausedEvent(mgr.PauseMarker()))
+		if err != nil {
+			log.Error("Could not save management paused event", "error", err)
 		}
 	}
 
 	if a.maxDepthExceeded() {
-		return session, fmt.Errorf("maximum apply depth of %d exceeded", a.maxDepth)
+		return session, failed, fmt.Errorf("maximum apply depth of %d exceeded", a.maxDepth)
 	}
 
 	if a.denyApplyResources && a.hasApplyResources() {
-		return session, fmt.Errorf("apply resources are denied")
+		return session, failed, fmt.Errorf("apply resources are denied")
 	}
 
-	var terminate bool
+	// conflicts are checked before any resource is applied so a conflicting resource is never started
+	if !healthCheckOnly && a.hasConflicts() {
+		env, err := mgr.TemplateEnvironment(ctx)
+		if err != nil {
+			return session, failed, err
+		}
+
+		err = model.CheckResourceConflicts(a.Resources(), env)
+		if err != nil {
+			return session, failed, err
+		}
+	}
+
+	if deadline := mgr.RunDeadline(); deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, deadline)
+		defer cancel()
+	}
 
 	for n, r := range a.Resources() {
 		if len(r) > 1 {
-			return nil, fmt.Errorf("only one resource type per resource is supported")
+			return nil, failed, fmt.Errorf("only one resource type per resource is supported")
 		}
 
 		for _, prop := range r {
 			if prop == nil {
 				userLog.Error("invalid properties received for resource", "resource", n)
-				continue
 			}
+		}
+	}
 
-			var event *model.TransactionEvent
-			var resource model.Resource
-			var err error
+	// resources may be applied concurrently, state shared between them is protected by mu
+	var mu sync.Mutex
+	var deadlineLogged bool
 
-			// TODO: error here should rather create a TransactionEvent with an error status
-			// TODO: this stuff should be stored in the registry so it knows when to call what so its automatic
+	err = mgr.ScheduleResources(ctx, a.Resources(), func(ctx context.Context, prop model.ResourceProperties) (bool, error) {
+		var event *model.TransactionEvent
+		var resource model.Resource
+		var trusted bool
+		var digest string
+		var err error
 
-			resource, err = ResourceFactory(ctx, mgr, prop)
-			if err != nil {
-				return nil, err
-			}
+		// TODO: error here should rather create a TransactionEvent with an error status
+		// TODO: this stuff should be stored in the registry so it knows when to call what so its automatic
 
-			if healthCheckOnly {
-				event, err = resource.Healthcheck(ctx)
-			} else {
-				event, err = resource.Apply(ctx)
-			}
+		if !healthCheckOnly && !mgr.NoopMode() && !deadlineExceeded(ctx) {
+			trusted, digest, err = mgr.TrustConverged(ctx, prop)
 			if err != nil {
-				return nil, err
+				log.Warn("Could not determine if the resource converged in an earlier run", "type", prop.CommonProperties().Type, "name", prop.CommonProperties().Name, "error", err)
 			}
+		}
 
-			if event.HealthCheckOnly {
-				if len(event.HealthChecks) > 0 {
-					event.LogStatus(userLog)
-				}
-			} else {
-				event.LogStatus(userLog)
+		switch {
+		case deadlineExceeded(ctx):
+			mu.Lock()
+			if !deadlineLogged {
+				userLog.Warn("Run deadline exceeded, skipping remaining resources", "deadline", mgr.RunDeadline())
+				deadlineLogged = true
 			}
+			mu.Unlock()
+
+			event = newDeadlineEvent(prop, healthCheckOnly)
 
-			err = mgr.RecordEvent(event)
+		case trusted:
+			event = newTrustedEvent(prop)
+
+		default:
+			resource, err = ResourceFactory(ctx, mgr, prop)
 			if err != nil {
-				log.Error("Could not save event", "event", event.String())
+				return true, err
 			}
 
-			err = publishRegistration(ctx, mgr, prop, event, log)
-			if err != nil {
-				return nil, err
+			event, err = runResource(ctx, resource, healthCheckOnly)
+
+			switch {
+			case err != nil && deadlineExceeded(ctx):
+				event = newDeadlineEvent(prop, healthCheckOnly)
+				event.Errors = append(event.Errors, err.Error())
+			case err != nil:
+				return true, err
+			case event.Failed && deadlineExceeded(ctx):
+				// the resource was canceled while running, it did not fail on its own
+				event.Failed = false
+				event.Skipped = true
+				event.DeadlineExceeded = true
 			}
+		}
 
-			if !healthCheckOnly && a.FailOnError() && event.Failed {
-				userLog.Warn("Terminating manifest execution due to failed resource")
-				terminate = true
+		if event.HealthCheckOnly {
+			if len(event.HealthChecks) > 0 {
+				event.LogStatus(userLog)
 			}
+		} else {
+			event.LogStatus(userLog)
 		}
 
-		if terminate {
-			break
+		err = mgr.RecordEvent(event)
+		if err != nil {
+			log.Error("Could not save event", "event", event.String())
+		}
+
+		err = mgr.RecordConverged(prop, digest, event)
+		if err != nil {
+			log.Warn("Could not record converged state", "event", event.String(), "error", err)
+		}
+
+		err = publishRegistration(ctx, mgr, prop, event, log)
+		if err != nil {
+			return true, err
+		}
+
+		if !event.Failed {
+			return false, nil
+		}
+
+		mu.Lock()
+		failed = true
+		mu.Unlock()
+
+		if !healthCheckOnly && a.FailOnError() {
+			userLog.Warn("Terminating manifest execution due to failed resource")
+			return true, nil
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return nil, failed, err
+	}
+
+	return session, failed, nil
+}
+
+// runResource applies or health checks resource, a panic in the resource is recovered and returned as an error
+func runResource(ctx context.Context, resource model.Resource, healthCheckOnly bool) (event *model.TransactionEvent, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			event = nil
+			err = fmt.Errorf("%s panicked: %v", resource.String(), r)
 		}
+	}()
+
+	if healthCheckOnly {
+		return resource.Healthcheck(ctx)
 	}
 
-	return session, nil
+	return resource.Apply(ctx)
+}
+
+// deadlineExceeded determines if the run deadline, or any deadline set by the caller, has passed
+func deadlineExceeded(ctx context.Context) bool {
+	return errors.Is(ctx.Err(), context.DeadlineExceeded)
+}
+
+// newTrustedEvent creates the event for a resource that was not checked as an earlier run found it converged
+func newTrustedEvent(prop model.ResourceProperties) *model.TransactionEvent {
+	common := prop.CommonProperties()
+
+	event := model.NewTransactionEvent(common.Type, common.Name, common.Alias)
+	event.Properties = prop
+	event.RequestedEnsure = common.Ensure
+	event.FinalEnsure = common.Ensure
+	event.Trusted = true
+
+	return event
+}
+
+// newDeadlineEvent creates the event for a resource that was not applied as the run deadline passed
+func newDeadlineEvent(prop model.ResourceProperties, healthCheckOnly bool) *model.TransactionEvent {
+	common := prop.CommonProperties()
+
+	event := model.NewTransactionEvent(common.Type, common.Name, common.Alias)
+	event.Properties = prop
+	event.RequestedEnsure = common.Ensure
+	event.HealthCheckOnly = healthCheckOnly
+	event.Skipped = true
+	event.DeadlineExceeded = true
+
+	return event
 }
 
 func (a *Apply) hasApplyResources() bool {
@@ -725,6 +920,18 @@ func (a *Apply) hasApplyResources() bool {
 	return false
 }
 
+func (a *Apply) hasConflicts() bool {
+	for _, r := range a.Resources() {
+		for _, prop := range r {
+			if prop != nil && len(prop.CommonProperties().Conflicts) > 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 func (a *Apply) maxDepthExceeded() bool {
 	if a.maxDepth == 0 {
 		a.maxDepth = DefaultMaxRecursionDepth
@@ -747,6 +954,10 @@ func publishRegistration(ctx context.Context, mgr model.Manager, prop model.Reso
 		log.Debug("Skipping registration due to failed event", "resource", common.Name)
 		return nil
 	}
+	if event.DeadlineExceeded {
+		log.Debug("Skipping registration due to run deadline", "resource", common.Name)
+		return nil
+	}
 	if !allHealthChecksPassed(event) {
 		log.Debug("Skipping registration due to failed health checks", "resource", common.Name)
 		return nil
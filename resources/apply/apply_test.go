@@ -15,6 +15,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/nats-io/nats.go/jetstream"
 	. "github.com/onsi/ginkgo/v2"
@@ -62,6 +63,59 @@ func testResourceFactory(ctx context.Context, mgr model.Manager, props model.Res
 	}
 }
 
+// slowResource is a resource that takes delay to apply unless its context is canceled first
+type slowResource struct {
+	props model.ResourceProperties
+	delay time.Duration
+}
+
+func (r *slowResource) Type() string                         { return r.props.CommonProperties().Type }
+func (r *slowResource) Name() string                         { return r.props.CommonProperties().Name }
+func (r *slowResource) ResourceId() string                   { return r.Type() + "#" + r.Name() }
+func (r *slowResource) String() string                       { return r.ResourceId() }
+func (r *slowResource) Provider() string                     { return "slow" }
+func (r *slowResource) Properties() model.ResourceProperties { return r.props }
+func (r *slowResource) Info(context.Context) (any, error)    { return nil, nil }
+func (r *slowResource) Healthcheck(ctx context.Context) (*model.TransactionEvent, error) {
+	return r.Apply(ctx)
+}
+
+func (r *slowResource) Apply(ctx context.Context) (*model.TransactionEvent, error) {
+	event := model.NewTransactionEvent(r.Type(), r.Name(), "")
+	event.Provider = r.Provider()
+
+	select {
+	case <-time.After(r.delay):
+		event.Changed = true
+	case <-ctx.Done():
+		event.Failed = true
+		event.Errors = append(event.Errors, ctx.Err().Error())
+	}
+
+	return event, nil
+}
+
+// checkedResource is a resource whose events report if it was health checked rather than applied
+type checkedResource struct {
+	slowResource
+}
+
+func (r *checkedResource) Healthcheck(ctx context.Context) (*model.TransactionEvent, error) {
+	event, err := r.Apply(ctx)
+	event.HealthCheckOnly = true
+
+	return event, err
+}
+
+// panicResource is a resource that panics when applied
+type panicResource struct {
+	slowResource
+}
+
+func (r *panicResource) Apply(context.Context) (*model.TransactionEvent, error) {
+	panic("boom")
+}
+
 var _ = Describe("Apply", func() {
 	var (
 		mockctl *gomock.Controller
@@ -548,6 +602,385 @@ var _ = Describe("Apply", func() {
 			})
 		})
 
+		Context("run deadline", func() {
+			var (
+				deadlineMgr *modelmocks.MockManager
+				events      []*model.TransactionEvent
+			)
+
+			execProps := func(name string) model.ResourceProperties {
+				return &model.ExecResourceProperties{
+					CommonResourceProperties: model.CommonResourceProperties{
+						Type:   model.ExecTypeName,
+						Name:   name,
+						Ensure: model.EnsurePresent,
+					},
+				}
+			}
+
+			BeforeEach(func() {
+				events = nil
+
+				deadlineMgr = modelmocks.NewMockManager(mockctl)
+				deadlineMgr.EXPECT().DeliverSessionReport(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+				deadlineMgr.EXPECT().NoopMode().Return(false).AnyTimes()
+				deadlineMgr.EXPECT().Logger(gomock.Any()).Return(mgrLogger, nil).AnyTimes()
+				deadlineMgr.EXPECT().RunDeadline().Return(200 * time.Millisecond).AnyTimes()
+				deadlineMgr.EXPECT().ScheduleResources(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(modelmocks.ScheduleSequentially).AnyTimes()
+				deadlineMgr.EXPECT().TrustConverged(gomock.Any(), gomock.Any()).Return(false, "", nil).AnyTimes()
+				deadlineMgr.EXPECT().RecordConverged(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+				deadlineMgr.EXPECT().ManagementPaused(gomock.Any()).Return(false, nil).AnyTimes()
+				deadlineMgr.EXPECT().RecordEvent(gomock.Any()).DoAndReturn(func(e *model.TransactionEvent) error {
+					events = append(events, e)
+					return nil
+				}).AnyTimes()
+
+				userLogger.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+
+				ResourceFactory = func(_ context.Context, _ model.Manager, props model.ResourceProperties) (model.Resource, error) {
+					delay := 10 * time.Millisecond
+					if props.CommonProperties().Name == "slow" {
+						delay = time.Hour
+					}
+
+					return &slowResource{props: props, delay: delay}, nil
+				}
+			})
+
+			It("Should cancel the running resource and skip remaining resources", func(ctx context.Context) {
+				apply := &Apply{
+					resources: []map[string]model.ResourceProperties{
+						{model.ExecTypeName: execProps("fast")},
+						{model.ExecTypeName: execProps("slow")},
+						{model.ExecTypeName: execProps("after")},
+					},
+					failOnError: true,
+				}
+
+				deadlineMgr.EXPECT().StartSession(apply).Return(session, nil)
+
+				start := time.Now()
+				result, err := apply.Execute(ctx, deadlineMgr, false, userLogger)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(session))
+				Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+
+				Expect(events).To(HaveLen(3))
+
+				Expect(events[0].Name).To(Equal("fast"))
+				Expect(events[0].Changed).To(BeTrue())
+				Expect(events[0].DeadlineExceeded).To(BeFalse())
+
+				Expect(events[1].Name).To(Equal("slow"))
+				Expect(events[1].Failed).To(BeFalse())
+				Expect(events[1].Skipped).To(BeTrue())
+				Expect(events[1].DeadlineExceeded).To(BeTrue())
+				Expect(events[1].Errors).To(ContainElement(ContainSubstring("deadline exceeded")))
+
+				Expect(events[2].Name).To(Equal("after"))
+				Expect(events[2].Skipped).To(BeTrue())
+				Expect(events[2].DeadlineExceeded).To(BeTrue())
+				Expect(events[2].RequestedEnsure).To(Equal(model.EnsurePresent))
+
+				summary := model.BuildSessionSummary([]model.SessionEvent{events[0], events[1], events[2]})
+				Expect(summary.FailedResources).To(Equal(0))
+				Expect(summary.DeadlineExceededResources).To(Equal(2))
+			})
+
+			It("Should not affect runs completing within the deadline", func(ctx context.Context) {
+				apply := &Apply{
+					resources: []map[string]model.ResourceProperties{
+						{model.ExecTypeName: execProps("one")},
+						{model.ExecTypeName: execProps("two")},
+					},
+				}
+
+				deadlineMgr.EXPECT().StartSession(apply).Return(session, nil)
+
+				_, err := apply.Execute(ctx, deadlineMgr, false, userLogger)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(events).To(HaveLen(2))
+				for _, event := range events {
+					Expect(event.Changed).To(BeTrue())
+					Expect(event.DeadlineExceeded).To(BeFalse())
+				}
+			})
+		})
+
+		Context("management paused", func() {
+			var (
+				pausedMgr *modelmocks.MockManager
+				events    []*model.TransactionEvent
+				apply     *Apply
+			)
+
+			BeforeEach(func() {
+				events = nil
+
+				pausedMgr = modelmocks.NewMockManager(mockctl)
+				pausedMgr.EXPECT().DeliverSessionReport(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+				pausedMgr.EXPECT().NoopMode().Return(false).AnyTimes()
+				pausedMgr.EXPECT().Logger(gomock.Any()).Return(mgrLogger, nil).AnyTimes()
+				pausedMgr.EXPECT().RunDeadline().Return(time.Duration(0)).AnyTimes()
+				pausedMgr.EXPECT().ScheduleResources(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(modelmocks.ScheduleSequentially).AnyTimes()
+				pausedMgr.EXPECT().TrustConverged(gomock.Any(), gomock.Any()).Return(false, "", nil).AnyTimes()
+				pausedMgr.EXPECT().RecordConverged(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+				pausedMgr.EXPECT().PauseMarker().Return("/etc/choria/ccm/pause").AnyTimes()
+				pausedMgr.EXPECT().RecordEvent(gomock.Any()).DoAndReturn(func(e *model.TransactionEvent) error {
+					events = append(events, e)
+					return nil
+				}).AnyTimes()
+
+				ResourceFactory = func(_ context.Context, _ model.Manager, props model.ResourceProperties) (model.Resource, error) {
+					return &checkedResource{slowResource{props: props}}, nil
+				}
+
+				apply = &Apply{
+					resources: []map[string]model.ResourceProperties{
+						{model.ExecTypeName: &model.ExecResourceProperties{
+							CommonResourceProperties: model.CommonResourceProperties{Type: model.ExecTypeName, Name: "one", Ensure: model.EnsurePresent},
+						}},
+					},
+				}
+				pausedMgr.EXPECT().StartSession(apply).Return(session, nil)
+			})
+
+			It("Should only run health checks and record the pause while the marker is present", func(ctx context.Context) {
+				pausedMgr.EXPECT().ManagementPaused(gomock.Any()).Return(true, nil)
+				userLogger.EXPECT().Warn("Management is paused, only running health checks", "marker", "/etc/choria/ccm/pause")
+				userLogger.EXPECT().With("healthcheck", true).Return(userLogger)
+				session.EXPECT().RecordEvent(gomock.Any()).DoAndReturn(func(e model.SessionEvent) error {
+					paused, ok := e.(*model.ManagementPausedEvent)
+					Expect(ok).To(BeTrue())
+					Expect(paused.Marker).To(Equal("/etc/choria/ccm/pause"))
+					return nil
+				})
+
+				_, err := apply.Execute(ctx, pausedMgr, false, userLogger)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(events).To(HaveLen(1))
+				Expect(events[0].HealthCheckOnly).To(BeTrue())
+			})
+
+			It("Should only run health checks when the marker cannot be checked", func(ctx context.Context) {
+				pausedMgr.EXPECT().ManagementPaused(gomock.Any()).Return(false, fmt.Errorf("kv unavailable"))
+				userLogger.EXPECT().Error(gomock.Any(), "marker", "/etc/choria/ccm/pause", "error", gomock.Any())
+				userLogger.EXPECT().With("healthcheck", true).Return(userLogger)
+				session.EXPECT().RecordEvent(gomock.Any()).Return(nil)
+
+				_, err := apply.Execute(ctx, pausedMgr, false, userLogger)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(events).To(HaveLen(1))
+				Expect(events[0].HealthCheckOnly).To(BeTrue())
+			})
+
+			It("Should apply when the marker is absent", func(ctx context.Context) {
+				pausedMgr.EXPECT().ManagementPaused(gomock.Any()).Return(false, nil)
+				userLogger.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+
+				_, err := apply.Execute(ctx, pausedMgr, false, userLogger)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(events).To(HaveLen(1))
+				Expect(events[0].HealthCheckOnly).To(BeFalse())
+				Expect(events[0].Changed).To(BeTrue())
+			})
+		})
+
+		Context("converged resources", func() {
+			var (
+				trustMgr *modelmocks.MockManager
+				events   []*model.TransactionEvent
+				created  []string
+				apply    *Apply
+			)
+
+			BeforeEach(func() {
+				events = nil
+				created = nil
+
+				trustMgr = modelmocks.NewMockManager(mockctl)
+				trustMgr.EXPECT().DeliverSessionReport(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+				trustMgr.EXPECT().NoopMode().Return(false).AnyTimes()
+				trustMgr.EXPECT().Logger(gomock.Any()).Return(mgrLogger, nil).AnyTimes()
+				trustMgr.EXPECT().RunDeadline().Return(time.Duration(0)).AnyTimes()
+				trustMgr.EXPECT().ScheduleResources(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(modelmocks.ScheduleSequentially).AnyTimes()
+				trustMgr.EXPECT().ManagementPaused(gomock.Any()).Return(false, nil).AnyTimes()
+				trustMgr.EXPECT().RecordEvent(gomock.Any()).DoAndReturn(func(e *model.TransactionEvent) error {
+					events = append(events, e)
+					return nil
+				}).AnyTimes()
+				userLogger.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+
+				ResourceFactory = func(_ context.Context, _ model.Manager, props model.ResourceProperties) (model.Resource, error) {
+					created = append(created, props.CommonProperties().Name)
+					return &slowResource{props: props}, nil
+				}
+
+				apply = &Apply{
+					resources: []map[string]model.ResourceProperties{
+						{model.ExecTypeName: &model.ExecResourceProperties{
+							CommonResourceProperties: model.CommonResourceProperties{Type: model.ExecTypeName, Name: "trusted", Ensure: model.EnsurePresent},
+						}},
+						{model.ExecTypeName: &model.ExecResourceProperties{
+							CommonResourceProperties: model.CommonResourceProperties{Type: model.ExecTypeName, Name: "checked", Ensure: model.EnsurePresent},
+						}},
+					},
+				}
+				trustMgr.EXPECT().StartSession(apply).Return(session, nil)
+			})
+
+			It("Should not check trusted resources and record the outcome of checked ones", func(ctx context.Context) {
+				trustMgr.EXPECT().TrustConverged(gomock.Any(), apply.resources[0][model.ExecTypeName]).Return(true, "d1", nil)
+				trustMgr.EXPECT().TrustConverged(gomock.Any(), apply.resources[1][model.ExecTypeName]).Return(false, "d2", nil)
+				trustMgr.EXPECT().RecordConverged(apply.resources[0][model.ExecTypeName], "d1", gomock.Any()).Return(nil)
+				trustMgr.EXPECT().RecordConverged(apply.resources[1][model.ExecTypeName], "d2", gomock.Any()).Return(nil)
+
+				_, err := apply.Execute(ctx, trustMgr, false, userLogger)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(created).To(Equal([]string{"checked"}))
+				Expect(events).To(HaveLen(2))
+				Expect(events[0].Trusted).To(BeTrue())
+				Expect(events[0].Changed).To(BeFalse())
+				Expect(events[1].Trusted).To(BeFalse())
+				Expect(events[1].Changed).To(BeTrue())
+			})
+
+			It("Should check resources when trust cannot be determined", func(ctx context.Context) {
+				trustMgr.EXPECT().TrustConverged(gomock.Any(), gomock.Any()).Return(false, "", fmt.Errorf("store failed")).Times(2)
+				trustMgr.EXPECT().RecordConverged(gomock.Any(), "", gomock.Any()).Return(nil).Times(2)
+
+				_, err := apply.Execute(ctx, trustMgr, false, userLogger)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(created).To(Equal([]string{"trusted", "checked"}))
+			})
+
+			It("Should not consult the converged state for health checks", func(ctx context.Context) {
+				userLogger.EXPECT().With("healthcheck", true).Return(userLogger)
+				trustMgr.EXPECT().RecordConverged(gomock.Any(), "", gomock.Any()).Return(nil).Times(2)
+
+				_, err := apply.Execute(ctx, trustMgr, true, userLogger)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(created).To(Equal([]string{"trusted", "checked"}))
+			})
+		})
+
+		Context("debug dumps", func() {
+			var (
+				dumpMgr *modelmocks.MockManager
+				apply   *Apply
+			)
+
+			BeforeEach(func() {
+				dumpMgr = modelmocks.NewMockManager(mockctl)
+				dumpMgr.EXPECT().NoopMode().Return(false).AnyTimes()
+				dumpMgr.EXPECT().Logger(gomock.Any()).Return(mgrLogger, nil).AnyTimes()
+				dumpMgr.EXPECT().RunDeadline().Return(time.Duration(0)).AnyTimes()
+				dumpMgr.EXPECT().ScheduleResources(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(modelmocks.ScheduleSequentially).AnyTimes()
+				dumpMgr.EXPECT().ManagementPaused(gomock.Any()).Return(false, nil).AnyTimes()
+				dumpMgr.EXPECT().TrustConverged(gomock.Any(), gomock.Any()).Return(false, "", nil).AnyTimes()
+				dumpMgr.EXPECT().RecordConverged(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+				dumpMgr.EXPECT().RecordEvent(gomock.Any()).Return(nil).AnyTimes()
+				userLogger.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+
+				apply = &Apply{
+					resources: []map[string]model.ResourceProperties{
+						{model.ExecTypeName: &model.ExecResourceProperties{
+							CommonResourceProperties: model.CommonResourceProperties{Type: model.ExecTypeName, Name: "one", Ensure: model.EnsurePresent},
+						}},
+					},
+					skipSession: true,
+					maxDepth:    DefaultMaxRecursionDepth,
+				}
+			})
+
+			It("Should write a dump when a resource panics", func(ctx context.Context) {
+				ResourceFactory = func(_ context.Context, _ model.Manager, props model.ResourceProperties) (model.Resource, error) {
+					return &panicResource{slowResource{props: props}}, nil
+				}
+				var dumpErr error
+				dumpMgr.EXPECT().WriteDebugDump(gomock.Any(), apply, gomock.Any()).DoAndReturn(func(_ context.Context, _ model.Apply, err error) (string, error) {
+					dumpErr = err
+					return "/tmp/dump.json", nil
+				})
+
+				_, err := apply.Execute(ctx, dumpMgr, false, userLogger)
+				Expect(err).To(MatchError("exec#one panicked: boom"))
+				Expect(dumpErr).To(Equal(err))
+			})
+
+			It("Should write a dump when a resource failed", func(ctx context.Context) {
+				cctx, cancel := context.WithCancel(ctx)
+				cancel()
+
+				ResourceFactory = func(_ context.Context, _ model.Manager, props model.ResourceProperties) (model.Resource, error) {
+					return &slowResource{props: props, delay: time.Hour}, nil
+				}
+				dumpMgr.EXPECT().WriteDebugDump(gomock.Any(), apply, nil).Return("", nil)
+				userLogger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+
+				_, err := apply.Execute(cctx, dumpMgr, false, userLogger)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("Should not write a dump for successful runs or nested applies", func(ctx context.Context) {
+				ResourceFactory = func(_ context.Context, _ model.Manager, props model.ResourceProperties) (model.Resource, error) {
+					return &slowResource{props: props}, nil
+				}
+
+				_, err := apply.Execute(ctx, dumpMgr, false, userLogger)
+				Expect(err).ToNot(HaveOccurred())
+
+				ResourceFactory = func(_ context.Context, _ model.Manager, props model.ResourceProperties) (model.Resource, error) {
+					return &panicResource{slowResource{props: props}}, nil
+				}
+				apply.currentDepth = 1
+
+				_, err = apply.Execute(ctx, dumpMgr, false, userLogger)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Context("session reports", func() {
+			var reportMgr *modelmocks.MockManager
+
+			BeforeEach(func() {
+				reportMgr = modelmocks.NewMockManager(mockctl)
+				reportMgr.EXPECT().NoopMode().Return(false).AnyTimes()
+				reportMgr.EXPECT().Logger(gomock.Any()).Return(mgrLogger, nil).AnyTimes()
+				reportMgr.EXPECT().RunDeadline().Return(time.Duration(0)).AnyTimes()
+				reportMgr.EXPECT().ManagementPaused(gomock.Any()).Return(false, nil).AnyTimes()
+				reportMgr.EXPECT().ScheduleResources(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(modelmocks.ScheduleSequentially).AnyTimes()
+			})
+
+			It("Should deliver the report without failing the apply", func(ctx context.Context) {
+				apply := &Apply{
+					resources: []map[string]model.ResourceProperties{},
+					maxDepth:  DefaultMaxRecursionDepth,
+				}
+
+				reportMgr.EXPECT().StartSession(apply).Return(session, nil)
+				reportMgr.EXPECT().DeliverSessionReport(gomock.Any(), apply).Return(fmt.Errorf("delivery failed"))
+				userLogger.EXPECT().Error("Could not deliver session report", "error", gomock.Any())
+
+				result, err := apply.Execute(ctx, reportMgr, false, userLogger)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(session))
+			})
+
+			It("Should not deliver reports for nested applies", func(ctx context.Context) {
+				apply := &Apply{
+					resources:    []map[string]model.ResourceProperties{},
+					maxDepth:     DefaultMaxRecursionDepth,
+					currentDepth: 1,
+				}
+
+				reportMgr.EXPECT().StartSession(apply).Return(session, nil)
+
+				_, err := apply.Execute(ctx, reportMgr, false, userLogger)
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+
 		It("Should skip StartSession when skipSession is set", func(ctx context.Context) {
 			apply := &Apply{
 				resources:   []map[string]model.ResourceProperties{},
@@ -682,6 +1115,29 @@ var _ = Describe("Apply", func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 
+		It("Should fail before applying any resource when conflicting resources are both present", func(ctx context.Context) {
+			svc := func(name string, conflicts ...string) map[string]model.ResourceProperties {
+				return map[string]model.ResourceProperties{model.ServiceTypeName: &model.ServiceResourceProperties{
+					CommonResourceProperties: model.CommonResourceProperties{
+						Name:      name,
+						Ensure:    model.ServiceEnsureRunning,
+						Type:      model.ServiceTypeName,
+						Conflicts: conflicts,
+					},
+				}}
+			}
+
+			apply := &Apply{
+				resources:   []map[string]model.ResourceProperties{svc("a", "service#b"), svc("b")},
+				skipSession: true,
+				maxDepth:    DefaultMaxRecursionDepth,
+			}
+
+			_, err := apply.Execute(ctx, mgr, false, userLogger)
+			Expect(err).To(MatchError(model.ErrResourceConflict))
+			Expect(err).To(MatchError(ContainSubstring("service#a with ensure running conflicts with service#b with ensure running")))
+		})
+
 		It("Should let a later exec read a file created by an earlier exec via a deferred ${ file() } template", func(ctx context.Context) {
 			tmpDir, err := os.MkdirTemp("", "apply-exec-deferred-template-*")
 			Expect(err).ToNot(HaveOccurred())
@@ -880,7 +1336,7 @@ ccm:
 			info:   &jetstream.ObjectInfo{ObjectMeta: jetstream.ObjectMeta{Name: "test.tar.gz"}},
 		}
 
-		mockMgr.EXPECT().JetStream().Return(mockJS, nil)
+		mockMgr.EXPECT().JetStreamCall(gomock.Any(), gomock.Any()).DoAndReturn(modelmocks.JetStreamCallWith(mockJS))
 		mockJS.EXPECT().ObjectStore(gomock.Any(), "mybucket").Return(mockObjStore, nil)
 		mockObjStore.EXPECT().Get(gomock.Any(), "manifest.tar.gz").Return(mockResult, nil)
 
@@ -995,14 +1451,14 @@ var _ = Describe("ResolveManifestObjectValue", func() {
 
 	It("returns an error when JetStream fails", func() {
 		jsErr := errors.New("jetstream unavailable")
-		mockMgr.EXPECT().JetStream().Return(nil, jsErr)
+		mockMgr.EXPECT().JetStreamCall(gomock.Any(), gomock.Any()).Return(jsErr)
 
 		_, _, _, err := ResolveManifestObjectValue(ctx, mockMgr, "bucket", "key", mockLog)
 		Expect(err).To(MatchError(jsErr))
 	})
 
 	It("returns an error when ObjectStore fails", func() {
-		mockMgr.EXPECT().JetStream().Return(mockJS, nil)
+		mockMgr.EXPECT().JetStreamCall(gomock.Any(), gomock.Any()).DoAndReturn(modelmocks.JetStreamCallWith(mockJS))
 		mockJS.EXPECT().ObjectStore(gomock.Any(), "missing-bucket").Return(nil, jetstream.ErrBucketNotFound)
 
 		_, _, _, err := ResolveManifestObjectValue(ctx, mockMgr, "missing-bucket", "key", mockLog)
@@ -1010,7 +1466,7 @@ var _ = Describe("ResolveManifestObjectValue", func() {
 	})
 
 	It("returns an error when Get fails", func() {
-		mockMgr.EXPECT().JetStream().Return(mockJS, nil)
+		mockMgr.EXPECT().JetStreamCall(gomock.Any(), gomock.Any()).DoAndReturn(modelmocks.JetStreamCallWith(mockJS))
 		mockJS.EXPECT().ObjectStore(gomock.Any(), "bucket").Return(mockObjStore, nil)
 		mockObjStore.EXPECT().Get(gomock.Any(), "missing-key").Return(nil, jetstream.ErrObjectNotFound)
 
@@ -1024,7 +1480,7 @@ var _ = Describe("ResolveManifestObjectValue", func() {
 			info:   &jetstream.ObjectInfo{ObjectMeta: jetstream.ObjectMeta{Name: "invalid.tar.gz"}},
 		}
 
-		mockMgr.EXPECT().JetStream().Return(mockJS, nil)
+		mockMgr.EXPECT().JetStreamCall(gomock.Any(), gomock.Any()).DoAndReturn(modelmocks.JetStreamCallWith(mockJS))
 		mockJS.EXPECT().ObjectStore(gomock.Any(), "bucket").Return(mockObjStore, nil)
 		mockObjStore.EXPECT().Get(gomock.Any(), "invalid.tar.gz").Return(mockResult, nil)
 
@@ -1050,7 +1506,7 @@ ccm:
 			info:   &jetstream.ObjectInfo{ObjectMeta: jetstream.ObjectMeta{Name: "manifest.tar.gz"}},
 		}
 
-		mockMgr.EXPECT().JetStream().Return(mockJS, nil)
+		mockMgr.EXPECT().JetStreamCall(gomock.Any(), gomock.Any()).DoAndReturn(modelmocks.JetStreamCallWith(mockJS))
 		mockJS.EXPECT().ObjectStore(gomock.Any(), "manifests").Return(mockObjStore, nil)
 		mockObjStore.EXPECT().Get(gomock.Any(), "app/manifest.tar.gz").Return(mockResult, nil)
 
@@ -1170,6 +1626,118 @@ ccm:
 		Expect(resources).To(HaveLen(3)) // 1 archive + 2 packages from loop
 	})
 
+	Context("manifest defaults", func() {
+		resolve := func(manifest string) (model.Apply, error) {
+			manifestPath := tempDir + "/manifest.yaml"
+			err := os.WriteFile(manifestPath, []byte(manifest), 0644)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, apply, err := ResolveManifestFilePath(ctx, mockMgr, manifestPath)
+			return apply, err
+		}
+
+		It("seeds unset properties only on resources of the matching type", func() {
+			apply, err := resolve(`
+ccm:
+  defaults:
+    file:
+      owner: app
+      group: app
+      mode: "0644"
+      ensure: present
+  resources:
+    - file:
+        name: /app/config.conf
+        content: config
+    - file:
+        - /app/bin/app:
+            content: app
+            mode: "0700"
+    - service:
+        name: app
+        ensure: running
+`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(apply.Resources()).To(HaveLen(3))
+
+			cfg := apply.Resources()[0][model.FileTypeName].(*model.FileResourceProperties)
+			Expect(cfg.Owner).To(Equal("app"))
+			Expect(cfg.Group).To(Equal("app"))
+			Expect(cfg.Mode).To(Equal("0644"))
+			Expect(cfg.Ensure).To(Equal(model.EnsurePresent))
+
+			bin := apply.Resources()[1][model.FileTypeName].(*model.FileResourceProperties)
+			Expect(bin.Name).To(Equal("/app/bin/app"))
+			Expect(bin.Owner).To(Equal("app"))
+			Expect(bin.Mode).To(Equal("0700"))
+
+			svc := apply.Resources()[2][model.ServiceTypeName].(*model.ServiceResourceProperties)
+			Expect(svc.Ensure).To(Equal(model.ServiceEnsureRunning))
+
+			svcYaml, err := svc.ToYamlManifest()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(svcYaml)).NotTo(ContainSubstring("owner"))
+		})
+
+		It("prefers block defaults over manifest defaults", func() {
+			apply, err := resolve(`
+ccm:
+  defaults:
+    file:
+      owner: app
+      group: app
+      mode: "0644"
+      ensure: present
+  resources:
+    - file:
+        - defaults:
+            owner: root
+        - /etc/motd:
+            content: hello
+        - /etc/issue:
+            content: hello
+            owner: nobody
+`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(apply.Resources()).To(HaveLen(2))
+
+			motd := apply.Resources()[0][model.FileTypeName].(*model.FileResourceProperties)
+			Expect(motd.Owner).To(Equal("root"))
+			Expect(motd.Group).To(Equal("app"))
+
+			issue := apply.Resources()[1][model.FileTypeName].(*model.FileResourceProperties)
+			Expect(issue.Owner).To(Equal("nobody"))
+		})
+
+		It("rejects defaults for unknown resource types", func() {
+			_, err := resolve(`
+ccm:
+  defaults:
+    files:
+      owner: app
+  resources:
+    - package:
+        name: vim
+        ensure: present
+`)
+			Expect(err).To(MatchError(ContainSubstring("unknown resource type files")))
+		})
+
+		It("rejects defaults that set a name", func() {
+			_, err := resolve(`
+ccm:
+  defaults:
+    package:
+      name: vim
+  resources:
+    - package:
+        name: vim
+        ensure: present
+`)
+			Expect(err).To(MatchError(ContainSubstring("package defaults cannot set a name")))
+		})
+	})
+
 	It("returns an error for invalid YAML", func() {
 		manifestContent := `
 data:
@@ -1231,6 +1799,34 @@ ccm:
 		Expect(props.Group).To(Equal("wheel"))
 	})
 
+	It("subscribes notified resources to the notifying resource", func() {
+		manifestContent := `
+ccm:
+  resources:
+    - file:
+      - /etc/nginx/nginx.conf:
+          ensure: present
+          content: "worker_processes 1;"
+          owner: root
+          group: root
+          mode: "0644"
+          notify:
+            - service#nginx
+    - service:
+      - nginx:
+          ensure: running
+`
+		manifestPath := tempDir + "/notify.yaml"
+		err := os.WriteFile(manifestPath, []byte(manifestContent), 0644)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, apply, err := ResolveManifestFilePath(ctx, mockMgr, manifestPath)
+		Expect(err).NotTo(HaveOccurred())
+
+		svc := apply.Resources()[1]["service"].(*model.ServiceResourceProperties)
+		Expect(svc.Subscribe).To(Equal([]string{"file#/etc/nginx/nginx.conf"}))
+	})
+
 	It("accepts ensure:absent file resources that omit mode, owner and group", func() {
 		manifestContent := `
 data:
@@ -0,0 +1,94 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package apply
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/choria-io/ccm/model"
+)
+
+// parseManifestDefaults parses the ccm.defaults block, it is keyed by resource type with the values
+// being properties to set on all resources of that type that do not set them
+func parseManifestDefaults(raw yaml.RawMessage) (map[string]map[string]any, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	var defaults map[string]map[string]any
+	err := yaml.Unmarshal(raw, &defaults)
+	if err != nil {
+		return nil, fmt.Errorf("invalid defaults: %w", err)
+	}
+
+	for typeName, props := range defaults {
+		if !model.IsResourceType(typeName) {
+			return nil, fmt.Errorf("invalid defaults: %w %s", model.ErrUnknownType, typeName)
+		}
+
+		_, ok := props["name"]
+		if ok {
+			return nil, fmt.Errorf("invalid defaults: %s defaults cannot set a name", typeName)
+		}
+	}
+
+	return defaults, nil
+}
+
+// applyManifestDefaults seeds any property not set in raw, the properties of a single resource block,
+// from defaults. Blocks listing multiple named resources get the defaults merged into their own
+// defaults entry so the precedence is resource, then block defaults, then manifest defaults
+func applyManifestDefaults(raw yaml.RawMessage, defaults map[string]any) (yaml.RawMessage, error) {
+	if len(defaults) == 0 {
+		return raw, nil
+	}
+
+	var named []map[string]map[string]any
+	err := yaml.Unmarshal(raw, &named)
+	if err != nil || len(named) == 0 {
+		// single resource format
+		var props map[string]any
+		err = yaml.Unmarshal(raw, &props)
+		if err != nil {
+			return nil, err
+		}
+
+		return yaml.Marshal(mergeDefaults(props, defaults))
+	}
+
+	var found bool
+	for _, entry := range named {
+		blockDefaults, ok := entry["defaults"]
+		if ok {
+			entry["defaults"] = mergeDefaults(blockDefaults, defaults)
+			found = true
+		}
+	}
+
+	if !found {
+		named = append([]map[string]map[string]any{{"defaults": maps.Clone(defaults)}}, named...)
+	}
+
+	return yaml.Marshal(named)
+}
+
+// mergeDefaults sets every key in defaults that props does not have
+func mergeDefaults(props map[string]any, defaults map[string]any) map[string]any {
+	if props == nil {
+		props = make(map[string]any)
+	}
+
+	for k, v := range defaults {
+		_, ok := props[k]
+		if !ok {
+			props[k] = v
+		}
+	}
+
+	return props
+}
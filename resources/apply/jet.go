@@ -35,6 +35,11 @@ func jetParseManifestResources(path string, env *templates.Env) (yaml.RawMessage
 		return nil, err
 	}
 
+	err = env.LoadAllData()
+	if err != nil {
+		return nil, err
+	}
+
 	buff := bytes.NewBuffer([]byte{})
 	err = tpl.Execute(buff, env.JetVariables(), env)
 	if err != nil {
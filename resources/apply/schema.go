@@ -0,0 +1,126 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package apply
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/choria-io/ccm/internal/fs"
+	"github.com/choria-io/ccm/model"
+)
+
+// ManifestSchema returns the JSON Schema for manifests, suitable for use by editors to validate and complete
+// manifests. It is based on the embedded manifest schema with the resource property definitions reconciled
+// against the schemas generated from the model property structs: properties supported by a resource type that
+// the schema lacks are added, and properties the schema describes that the type no longer supports are removed.
+func ManifestSchema() ([]byte, error) {
+	rawSchema, err := fs.FS.Open("schemas/manifest.json")
+	if err != nil {
+		return nil, err
+	}
+	defer rawSchema.Close()
+
+	body, err := io.ReadAll(rawSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	var schema map[string]any
+	err = json.Unmarshal(body, &schema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest schema: %w", err)
+	}
+
+	defs, ok := schema["$defs"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid manifest schema: no definitions found")
+	}
+
+	for _, typeName := range model.ResourceTypeNames() {
+		// custom types are not part of the published schema
+		if model.IsCustomResourceType(typeName) {
+			continue
+		}
+
+		fields, err := propertySchemas(typeName)
+		if err != nil {
+			return nil, err
+		}
+
+		// the list format keys resources by name so only the direct format has a name property
+		err = reconcileSchemaDefinition(defs, typeName+"ResourceProperties", fields, "name")
+		if err != nil {
+			return nil, err
+		}
+
+		err = reconcileSchemaDefinition(defs, typeName+"ResourcePropertiesWithName", fields)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// propertySchemas returns the generated schema of every property supported by typeName
+func propertySchemas(typeName string) (map[string]any, error) {
+	raw, err := model.Schema(typeName)
+	if err != nil {
+		return nil, err
+	}
+
+	var schema struct {
+		Properties map[string]any `json:"properties"`
+	}
+	err = json.Unmarshal(raw, &schema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s schema: %w", typeName, err)
+	}
+
+	return schema.Properties, nil
+}
+
+// reconcileSchemaDefinition updates the named definition so its properties match fields, except those in skip
+func reconcileSchemaDefinition(defs map[string]any, name string, fields map[string]any, skip ...string) error {
+	def, ok := defs[name].(map[string]any)
+	if !ok {
+		return fmt.Errorf("invalid manifest schema: no %s definition found", name)
+	}
+
+	properties, ok := def["properties"].(map[string]any)
+	if !ok {
+		properties = map[string]any{}
+		def["properties"] = properties
+	}
+
+	for field, fieldSchema := range fields {
+		if slices.Contains(skip, field) {
+			continue
+		}
+
+		if _, ok := properties[field]; !ok {
+			properties[field] = fieldSchema
+		}
+	}
+
+	for field := range properties {
+		if _, ok := fields[field]; !ok {
+			delete(properties, field)
+		}
+	}
+
+	required, ok := def["required"].([]any)
+	if ok {
+		def["required"] = slices.DeleteFunc(required, func(r any) bool {
+			_, ok := properties[r.(string)]
+			return !ok
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package apply
+
+import (
+	"bytes"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+
+	"github.com/choria-io/ccm/model"
+)
+
+var _ = Describe("ManifestSchema", func() {
+	var schema map[string]any
+
+	BeforeEach(func() {
+		out, err := ManifestSchema()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(json.Unmarshal(out, &schema)).To(Succeed())
+	})
+
+	It("Should describe every property of every resource type", func() {
+		defs := schema["$defs"].(map[string]any)
+
+		for _, typeName := range model.ResourceTypeNames() {
+			fields, err := propertySchemas(typeName)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fields).To(HaveKey("ensure"))
+
+			withName := defs[typeName+"ResourcePropertiesWithName"].(map[string]any)["properties"].(map[string]any)
+			listed := defs[typeName+"ResourceProperties"].(map[string]any)["properties"].(map[string]any)
+
+			for field := range fields {
+				Expect(withName).To(HaveKey(field), "%s is missing %s", typeName, field)
+				if field != "name" {
+					Expect(listed).To(HaveKey(field), "%s is missing %s", typeName, field)
+				}
+			}
+
+			Expect(withName).To(HaveLen(len(fields)), typeName)
+			Expect(listed).ToNot(HaveKey("name"), typeName)
+		}
+	})
+
+	It("Should include ensure values and required properties", func() {
+		defs := schema["$defs"].(map[string]any)
+
+		file := defs["fileResourcePropertiesWithName"].(map[string]any)
+		Expect(file["required"]).To(ContainElement("name"))
+		Expect(file["properties"].(map[string]any)["ensure"].(map[string]any)["enum"]).To(ContainElements("present", "absent"))
+	})
+
+	It("Should validate manifests", func() {
+		out, err := ManifestSchema()
+		Expect(err).ToNot(HaveOccurred())
+
+		parsed, err := jsonschema.UnmarshalJSON(bytes.NewReader(out))
+		Expect(err).ToNot(HaveOccurred())
+
+		c := jsonschema.NewCompiler()
+		Expect(c.AddResource("manifest.json", parsed)).To(Succeed())
+		sch, err := c.Compile("manifest.json")
+		Expect(err).ToNot(HaveOccurred())
+
+		valid, err := jsonschema.UnmarshalJSON(bytes.NewReader([]byte(`{"ccm": {"resources": [{"package": {"name": "zsh", "ensure": "present"}}]}}`)))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sch.Validate(valid)).To(Succeed())
+
+		invalid, err := jsonschema.UnmarshalJSON(bytes.NewReader([]byte(`{"ccm": {"resources": [{"package": {"name": "zsh", "ensure": "present", "bogus": true}}]}}`)))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sch.Validate(invalid)).ToNot(Succeed())
+	})
+
+	Describe("reconcileSchemaDefinition", func() {
+		It("Should add missing and remove stale properties", func() {
+			defs := map[string]any{
+				"testResourceProperties": map[string]any{
+					"properties": map[string]any{
+						"name":  map[string]any{"type": "string"},
+						"stale": map[string]any{"type": "string"},
+					},
+					"required": []any{"name", "stale"},
+				},
+			}
+
+			fields := map[string]any{
+				"name":    map[string]any{"type": "string"},
+				"enabled": map[string]any{"type": "boolean"},
+				"items":   map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			}
+
+			Expect(reconcileSchemaDefinition(defs, "testResourceProperties", fields)).To(Succeed())
+
+			def := defs["testResourceProperties"].(map[string]any)
+			Expect(def["properties"]).To(Equal(map[string]any{
+				"name":    map[string]any{"type": "string"},
+				"enabled": map[string]any{"type": "boolean"},
+				"items":   map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			}))
+			Expect(def["required"]).To(Equal([]any{"name"}))
+		})
+	})
+})
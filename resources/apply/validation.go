@@ -6,6 +6,7 @@ package apply
 
 import (
 	"fmt"
+	"os"
 	"reflect"
 
 	"github.com/goccy/go-yaml"
@@ -19,6 +20,27 @@ import (
 // generic schema patterns used for names and identifiers across the manifest.
 const defaultSchemaPlaceholder = "ccmplaceholder"
 
+// ValidateResourceSchema validates the properties of a single resource against its definition in the manifest
+// schema. Remaining template expressions are replaced by placeholders as when validating a manifest. Custom
+// resource types are not described by the schema and are not validated.
+func ValidateResourceSchema(prop model.ResourceProperties) error {
+	if os.Getenv("NO_SCHEMA_VALIDATION") == "1" || model.IsCustomResourceType(prop.CommonProperties().Type) {
+		return nil
+	}
+
+	substituted, err := substituteTemplatesForValidation(prop)
+	if err != nil {
+		return err
+	}
+
+	raw, err := substituted.ToYamlManifest()
+	if err != nil {
+		return fmt.Errorf("could not marshal resource for validation: %w", err)
+	}
+
+	return validateSchema(fmt.Sprintf("manifest.json#/$defs/%sResourcePropertiesWithName", prop.CommonProperties().Type), raw)
+}
+
 // substituteTemplatesForValidation produces a deep copy of prop with any
 // remaining template expressions replaced by placeholder values, ready to be
 // schema-validated. The copy is independent of prop, so callers may continue to
@@ -103,7 +103,7 @@ func (t *Type) selectProviderUnlocked() error {
 		return err
 	}
 
-	selected, err := registry.FindSuitableProvider(model.ApplyTypeName, t.prop.Provider, t.Facts, t.prop, t.log, runner)
+	selected, err := registry.FindSuitableProvider(model.ApplyTypeName, t.prop.Provider, t.Facts, t.prop, t.log, runner, t.mgr)
 	if err != nil {
 		return err
 	}
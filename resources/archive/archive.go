@@ -9,6 +9,7 @@ import (
 
 	"github.com/choria-io/ccm/model"
 	"github.com/choria-io/ccm/resources/archive/http"
+	"github.com/choria-io/ccm/resources/archive/s3"
 )
 
 type ArchiveFactory interface {
@@ -17,12 +18,13 @@ type ArchiveFactory interface {
 
 func init() {
 	http.Register()
+	s3.Register()
 }
 
 type ArchiveProvider interface {
 	model.Provider
 
-	Download(ctx context.Context, properties *model.ArchiveResourceProperties, log model.Logger) error
+	Download(ctx context.Context, properties *model.ArchiveResourceProperties, cache model.DownloadCache, log model.Logger) error
 	Extract(ctx context.Context, properties *model.ArchiveResourceProperties, log model.Logger) error
 	Status(ctx context.Context, properties *model.ArchiveResourceProperties) (*model.ArchiveState, error)
 }
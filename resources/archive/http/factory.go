@@ -24,6 +24,9 @@ func (p *factory) Name() string     { return ProviderName }
 func (p *factory) New(log model.Logger, runner model.CommandRunner) (model.Provider, error) {
 	return NewHttpProvider(log, runner)
 }
+func (p *factory) NewWithConfig(log model.Logger, runner model.CommandRunner, config map[string]any) (model.Provider, error) {
+	return NewHttpProviderWithConfig(log, runner, config)
+}
 func (p *factory) IsManageable(_ map[string]any, prop model.ResourceProperties) (bool, int, error) {
 	ap, ok := prop.(*model.ArchiveResourceProperties)
 	if !ok {
@@ -39,7 +42,7 @@ func (p *factory) IsManageable(_ map[string]any, prop model.ResourceProperties)
 		return false, 0, nil
 	}
 
-	tool := toolForFileName(ap.Name)
+	tool := ToolForFileName(ap.Name)
 	if tool == "" {
 		return false, 0, nil
 	}
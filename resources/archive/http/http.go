@@ -5,7 +5,9 @@
 package http
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -13,8 +15,12 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/choria-io/fisk"
+
+	"github.com/choria-io/ccm/internal/backoff"
 	iu "github.com/choria-io/ccm/internal/util"
 	"github.com/choria-io/ccm/model"
 )
@@ -24,40 +30,107 @@ const ProviderName = "http"
 type Provider struct {
 	log    model.Logger
 	runner model.CommandRunner
+	config Config
+}
+
+// Config is the provider configuration set using the manager WithProviderConfig option
+type Config struct {
+	Timeout string            `json:"timeout,omitempty"` // Timeout is the maximum time a download may take, 1 minute when unset
+	Headers map[string]string `json:"headers,omitempty"` // Headers are sent with every request, headers set on the resource take precedence
+
+	timeout time.Duration
 }
 
 func NewHttpProvider(log model.Logger, runner model.CommandRunner) (*Provider, error) {
 	return &Provider{log: log, runner: runner}, nil
 }
 
-func (p *Provider) Download(ctx context.Context, properties *model.ArchiveResourceProperties, log model.Logger) error {
-	uri, err := url.Parse(properties.Url)
+// NewHttpProviderWithConfig creates a provider configured using the provider level configuration in config
+func NewHttpProviderWithConfig(log model.Logger, runner model.CommandRunner, config map[string]any) (*Provider, error) {
+	p, err := NewHttpProvider(log, runner)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if properties.Username != "" && properties.Password != "" {
-		uri.User = url.UserPassword(properties.Username, properties.Password)
+	j, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s provider configuration: %w", ProviderName, err)
 	}
 
-	p.log.Info("Downloading", "url", iu.RedactUrlCredentials(uri))
+	dec := json.NewDecoder(bytes.NewReader(j))
+	dec.DisallowUnknownFields()
+	err = dec.Decode(&p.config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s provider configuration: %w", ProviderName, err)
+	}
 
-	hdr := http.Header{}
-	if properties.Headers != nil {
-		for k, v := range properties.Headers {
-			hdr.Add(k, v)
+	if p.config.Timeout != "" {
+		p.config.timeout, err = fisk.ParseDuration(p.config.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s provider configuration: invalid timeout: %w", ProviderName, err)
 		}
 	}
 
-	resp, cancel, err := iu.HttpGetResponse(ctx, uri.String(), 0, hdr)
+	return p, nil
+}
+
+// Download fetches the archive into place, when a cache is given and a checksum is set the archive is served from
+// and stored in the cache. Downloads failing with network or server errors are retried as often as the retries
+// property allows, every attempt verifies the checksum again. With resume set an interrupted download is kept and
+// continued by later attempts using a range request, otherwise every attempt starts with a new temporary file.
+func (p *Provider) Download(ctx context.Context, properties *model.ArchiveResourceProperties, cache model.DownloadCache, log model.Logger) error {
+	uri, err := url.Parse(properties.Url)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-	defer cancel()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("HTTP request failed with status %d: %s", resp.StatusCode, resp.Status)
+	retries, delay, err := properties.RetryPolicy()
+	if err != nil {
+		return err
+	}
+
+	var partial string
+	if properties.Resume {
+		partial = PartialDownloadPath(properties.Name)
+	}
+
+	for attempt := 0; ; attempt++ {
+		err = saveArchive(p.log, properties, cache, log, partial, func(tf *os.File) error {
+			return p.fetch(ctx, uri, properties, tf, log)
+		})
+		if err == nil || attempt >= retries || !model.IsTransientError(err) {
+			return err
+		}
+
+		log.Warn("Retrying failed download", "url", iu.RedactUrlCredentials(uri), "attempt", attempt+1, "retries", retries, "delay", delay, "error", err)
+
+		err = backoff.InterruptableSleep(ctx, delay)
+		if err != nil {
+			return err
+		}
+
+		delay *= 2
+	}
+}
+
+// PartialDownloadPath is the file an interrupted download of the archive name is kept in when resuming downloads
+func PartialDownloadPath(name string) string {
+	return filepath.Join(filepath.Dir(name), fmt.Sprintf(".%s.partial", filepath.Base(name)))
+}
+
+// SaveArchive saves the archive using fetch to write it into a temporary file next to the archive. When a cache is
+// given and a checksum is set the archive is served from and stored in the cache. The checksum is verified before
+// the temporary file is renamed into place, the temporary file is removed on any failure.
+func SaveArchive(plog model.Logger, properties *model.ArchiveResourceProperties, cache model.DownloadCache, log model.Logger, fetch func(tf *os.File) error) error {
+	return saveArchive(plog, properties, cache, log, "", fetch)
+}
+
+// saveArchive saves the archive like SaveArchive, when partial is set it is used as the temporary file and kept
+// after transient failures so a later attempt can continue the download, fetch has to append to existing content
+func saveArchive(plog model.Logger, properties *model.ArchiveResourceProperties, cache model.DownloadCache, log model.Logger, partial string, fetch func(tf *os.File) error) (err error) {
+	uri, err := url.Parse(properties.Url)
+	if err != nil {
+		return err
 	}
 
 	parent := filepath.Dir(properties.Name)
@@ -69,20 +142,57 @@ func (p *Provider) Download(ctx context.Context, properties *model.ArchiveResour
 		return err
 	}
 
-	tf, err := os.CreateTemp(parent, fmt.Sprintf("%s-*", archiveName))
-	if err != nil {
-		return err
+	var tf *os.File
+	if partial == "" {
+		tf, err = os.CreateTemp(parent, fmt.Sprintf("%s-*", archiveName))
+		if err != nil {
+			return err
+		}
+		defer os.Remove(tf.Name())
+	} else {
+		tf, err = os.OpenFile(partial, os.O_RDWR|os.O_CREATE, 0600)
+		if err != nil {
+			return err
+		}
+
+		// only transient failures leave content worth resuming, empty files are never kept
+		defer func() {
+			if err == nil {
+				return
+			}
+
+			stat, serr := os.Stat(partial)
+			if model.IsTransientError(err) && serr == nil && stat.Size() > 0 {
+				return
+			}
+
+			os.Remove(partial)
+		}()
+
+		_, err = tf.Seek(0, io.SeekEnd)
+		if err != nil {
+			tf.Close()
+			return err
+		}
 	}
-	defer os.Remove(tf.Name())
 
-	p.log.Info("Saving archive", "dest", properties.Name, "tf", tf.Name())
+	plog.Info("Saving archive", "dest", properties.Name, "tf", tf.Name())
 
-	copied, err := io.Copy(tf, resp.Body)
-	if err != nil {
-		tf.Close()
-		return fmt.Errorf("could not copy file: %w", err)
+	var cached bool
+	if cache != nil && properties.Checksum != "" {
+		cached, err = copyFromCache(cache, properties, tf, log)
+		if err != nil {
+			plog.Warn("Could not read from download cache", "url", iu.RedactUrlCredentials(uri), "error", err)
+		}
+	}
+
+	if !cached {
+		err = fetch(tf)
+		if err != nil {
+			tf.Close()
+			return err
+		}
 	}
-	log.Info("Archive downloaded", "bytes", copied)
 
 	err = tf.Close()
 	if err != nil {
@@ -95,7 +205,14 @@ func (p *Provider) Download(ctx context.Context, properties *model.ArchiveResour
 			return fmt.Errorf("could not checksum archive: %w", err)
 		}
 		if sum != properties.Checksum {
-			return fmt.Errorf("checksum mismatch, expected %q got %q", properties.Checksum, sum)
+			return model.PermanentErrorf("checksum mismatch, expected %q got %q", properties.Checksum, sum)
+		}
+
+		if cache != nil && !cached {
+			err = cache.Store(properties.Url, properties.Checksum, tf.Name())
+			if err != nil {
+				plog.Warn("Could not store archive in download cache", "url", iu.RedactUrlCredentials(uri), "error", err)
+			}
 		}
 	}
 
@@ -109,6 +226,134 @@ func (p *Provider) Download(ctx context.Context, properties *model.ArchiveResour
 	return os.Chown(properties.Name, uid, gid)
 }
 
+// copyFromCache copies a cached archive into tf, reports false on a cache miss
+func copyFromCache(cache model.DownloadCache, properties *model.ArchiveResourceProperties, tf *os.File, log model.Logger) (bool, error) {
+	r, found, err := cache.Open(properties.Url, properties.Checksum)
+	if err != nil || !found {
+		return false, err
+	}
+	defer r.Close()
+
+	// a partial download being resumed is replaced by the cached archive
+	err = truncateFile(tf)
+	if err != nil {
+		return false, err
+	}
+
+	copied, err := io.Copy(tf, r)
+	if err != nil {
+		// discard any partially copied data so a fresh download starts with an empty file
+		terr := truncateFile(tf)
+		if terr != nil {
+			return false, terr
+		}
+
+		return false, err
+	}
+
+	log.Info("Archive copied from download cache", "bytes", copied)
+
+	return true, nil
+}
+
+// truncateFile discards all content of tf so it can be written again from the start
+func truncateFile(tf *os.File) error {
+	err := tf.Truncate(0)
+	if err != nil {
+		return err
+	}
+
+	_, err = tf.Seek(0, io.SeekStart)
+
+	return err
+}
+
+// fetch downloads the archive from uri into tf, when tf holds part of the archive from an interrupted download
+// only the remainder is requested using a range request
+func (p *Provider) fetch(ctx context.Context, uri *url.URL, properties *model.ArchiveResourceProperties, tf *os.File, log model.Logger) error {
+	if properties.Username != "" && properties.Password != "" {
+		uri.User = url.UserPassword(properties.Username, properties.Password)
+	}
+
+	offset, err := tf.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+
+	hdr := http.Header{}
+	for k, v := range p.config.Headers {
+		hdr.Set(k, v)
+	}
+	for k, v := range properties.Headers {
+		hdr.Set(k, v)
+	}
+
+	if offset > 0 {
+		p.log.Info("Resuming download", "url", iu.RedactUrlCredentials(uri), "offset", offset)
+		hdr.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	} else {
+		p.log.Info("Downloading", "url", iu.RedactUrlCredentials(uri))
+	}
+
+	resp, cancel, err := iu.HttpGetResponse(ctx, uri.String(), p.config.timeout, hdr)
+	if err != nil {
+		return model.NewTransientError(err)
+	}
+	defer resp.Body.Close()
+	defer cancel()
+
+	switch {
+	case offset > 0 && resp.StatusCode == http.StatusPartialContent && strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", offset)):
+		// the remainder is appended to the partial download
+
+	case offset > 0 && (resp.StatusCode == http.StatusPartialContent || resp.StatusCode == http.StatusRequestedRangeNotSatisfiable):
+		// the partial download does not match the archive on the server, start over
+		log.Warn("Server could not resume the download, downloading the full archive", "status", resp.StatusCode)
+
+		err = truncateFile(tf)
+		if err != nil {
+			return err
+		}
+
+		return p.fetch(ctx, uri, properties, tf, log)
+
+	case resp.StatusCode == http.StatusOK:
+		// servers without range support send the full archive
+		if offset > 0 {
+			log.Warn("Server does not support resuming downloads, downloading the full archive", "status", resp.StatusCode)
+
+			err = truncateFile(tf)
+			if err != nil {
+				return err
+			}
+		}
+
+	default:
+		err = fmt.Errorf("HTTP request failed with status %d: %s", resp.StatusCode, resp.Status)
+		if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusRequestTimeout {
+			return model.NewTransientError(err)
+		}
+
+		return model.NewPermanentError(err)
+	}
+
+	copied, err := io.Copy(tf, resp.Body)
+	if err != nil {
+		// content can only be resumed from servers supporting range requests
+		if resp.Header.Get("Accept-Ranges") != "bytes" {
+			terr := truncateFile(tf)
+			if terr != nil {
+				return terr
+			}
+		}
+
+		return model.TransientErrorf("could not copy file: %w", err)
+	}
+	log.Info("Archive downloaded", "bytes", copied)
+
+	return nil
+}
+
 func (p *Provider) Extract(ctx context.Context, properties *model.ArchiveResourceProperties, log model.Logger) error {
 	// TODO: realistically this probably belong to the type else we end up with a ton of duplication, or perhaps a utility class or something
 
@@ -127,6 +372,10 @@ func (p *Provider) Extract(ctx context.Context, properties *model.ArchiveResourc
 	switch {
 	case iu.FileHasSuffix(properties.Name, ".tar.gz", ".tgz"):
 		return p.extractTarGz(ctx, properties, log)
+	case iu.FileHasSuffix(properties.Name, ".tar.xz", ".txz"):
+		return p.extractTarXz(ctx, properties, log)
+	case iu.FileHasSuffix(properties.Name, ".tar.bz2", ".tbz2"):
+		return p.extractTarBz2(ctx, properties, log)
 	case iu.FileHasSuffix(properties.Name, ".tar"):
 		return p.extractTar(ctx, properties, log)
 	case iu.FileHasSuffix(properties.Name, ".zip"):
@@ -136,11 +385,12 @@ func (p *Provider) Extract(ctx context.Context, properties *model.ArchiveResourc
 	}
 }
 
-func toolForFileName(name string) string {
+// ToolForFileName is the command needed to extract the archive name, empty when the archive type is not supported
+func ToolForFileName(name string) string {
 	switch {
 	case iu.FileHasSuffix(name, ".zip"):
 		return "unzip"
-	case iu.FileHasSuffix(name, ".tar.gz", ".tgz", ".tar"):
+	case iu.FileHasSuffix(name, ".tar.gz", ".tgz", ".tar.xz", ".txz", ".tar.bz2", ".tbz2", ".tar"):
 		return "tar"
 	default:
 		return ""
@@ -180,6 +430,41 @@ func (p *Provider) extractTar(ctx context.Context, properties *model.ArchiveReso
 
 	return nil
 }
+
+func (p *Provider) extractTarXz(ctx context.Context, properties *model.ArchiveResourceProperties, log model.Logger) error {
+	_, stderr, exitCode, err := p.runner.ExecuteWithOptions(ctx, model.ExtendedExecOptions{
+		Command: "tar",
+		Args:    []string{"-xJf", properties.Name, "-C", properties.ExtractParent},
+		Cwd:     properties.ExtractParent,
+		Timeout: time.Minute,
+	})
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		return fmt.Errorf("tar exited with code %d: %s", exitCode, stderr)
+	}
+
+	return nil
+}
+
+func (p *Provider) extractTarBz2(ctx context.Context, properties *model.ArchiveResourceProperties, log model.Logger) error {
+	_, stderr, exitCode, err := p.runner.ExecuteWithOptions(ctx, model.ExtendedExecOptions{
+		Command: "tar",
+		Args:    []string{"-xjf", properties.Name, "-C", properties.ExtractParent},
+		Cwd:     properties.ExtractParent,
+		Timeout: time.Minute,
+	})
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		return fmt.Errorf("tar exited with code %d: %s", exitCode, stderr)
+	}
+
+	return nil
+}
+
 func (p *Provider) extractZip(ctx context.Context, properties *model.ArchiveResourceProperties, log model.Logger) error {
 	_, stderr, exitCode, err := p.runner.ExecuteWithOptions(ctx, model.ExtendedExecOptions{
 		Command: "unzip",
@@ -5,13 +5,19 @@
 package http
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"os/user"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -19,6 +25,7 @@ import (
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
 
+	"github.com/choria-io/ccm/internal/downloadcache"
 	iu "github.com/choria-io/ccm/internal/util"
 	"github.com/choria-io/ccm/model"
 	"github.com/choria-io/ccm/model/modelmocks"
@@ -110,7 +117,7 @@ var _ = Describe("Http Provider", func() {
 				Group: currentGroup.Name,
 			}
 
-			err = provider.Download(context.Background(), properties, logger)
+			err = provider.Download(context.Background(), properties, nil, logger)
 			Expect(err).ToNot(HaveOccurred())
 
 			// Verify file was created
@@ -150,13 +157,79 @@ var _ = Describe("Http Provider", func() {
 				},
 			}
 
-			err = provider.Download(context.Background(), properties, logger)
+			err = provider.Download(context.Background(), properties, nil, logger)
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(receivedHeaders.Get("X-Custom-Header")).To(Equal("custom-value"))
 			Expect(receivedHeaders.Get("Authorization")).To(Equal("Bearer token123"))
 		})
 
+		Context("with provider configuration", func() {
+			var properties *model.ArchiveResourceProperties
+
+			BeforeEach(func() {
+				currentUser, err := user.Current()
+				Expect(err).ToNot(HaveOccurred())
+				currentGroup, err := user.LookupGroupId(currentUser.Gid)
+				Expect(err).ToNot(HaveOccurred())
+
+				properties = &model.ArchiveResourceProperties{
+					CommonResourceProperties: model.CommonResourceProperties{
+						Name: filepath.Join(tempDir, "archive.tar.gz"),
+					},
+					Owner: currentUser.Username,
+					Group: currentGroup.Name,
+				}
+			})
+
+			It("Should reject invalid configuration", func() {
+				_, err := NewHttpProviderWithConfig(logger, runner, map[string]any{"timeout": "soon"})
+				Expect(err).To(MatchError(ContainSubstring("invalid http provider configuration: invalid timeout")))
+
+				_, err = NewHttpProviderWithConfig(logger, runner, map[string]any{"proxy": "http://proxy"})
+				Expect(err).To(MatchError(ContainSubstring("unknown field \"proxy\"")))
+			})
+
+			It("Should use the configured timeout", func() {
+				done := make(chan struct{})
+				server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					select {
+					case <-r.Context().Done():
+					case <-done:
+					}
+				}))
+				defer close(done)
+				properties.Url = server.URL + "/archive.tar.gz"
+
+				configured, err := NewHttpProviderWithConfig(logger, runner, map[string]any{"timeout": "100ms"})
+				Expect(err).ToNot(HaveOccurred())
+
+				start := time.Now()
+				err = configured.Download(context.Background(), properties, nil, logger)
+				Expect(err).To(MatchError(ContainSubstring("context deadline exceeded")))
+				Expect(time.Since(start)).To(BeNumerically("<", 10*time.Second))
+			})
+
+			It("Should send configured headers with resource headers taking precedence", func() {
+				var receivedHeaders http.Header
+				server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					receivedHeaders = r.Header.Clone()
+					w.WriteHeader(http.StatusOK)
+				}))
+				properties.Url = server.URL + "/archive.tar.gz"
+				properties.Headers = map[string]string{"X-Env": "resource"}
+
+				configured, err := NewHttpProviderWithConfig(logger, runner, map[string]any{
+					"headers": map[string]any{"X-Env": "provider", "X-Site": "provider"},
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(configured.Download(context.Background(), properties, nil, logger)).To(Succeed())
+				Expect(receivedHeaders.Get("X-Env")).To(Equal("resource"))
+				Expect(receivedHeaders.Get("X-Site")).To(Equal("provider"))
+			})
+		})
+
 		It("Should use basic auth when username and password are provided", func() {
 			var receivedAuth string
 			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -183,7 +256,7 @@ var _ = Describe("Http Provider", func() {
 				Password: "testpass",
 			}
 
-			err = provider.Download(context.Background(), properties, logger)
+			err = provider.Download(context.Background(), properties, nil, logger)
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(receivedAuth).To(HavePrefix("Basic "))
@@ -211,9 +284,283 @@ var _ = Describe("Http Provider", func() {
 				Group: currentGroup.Name,
 			}
 
-			err = provider.Download(context.Background(), properties, logger)
+			err = provider.Download(context.Background(), properties, nil, logger)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("500"))
+			Expect(model.IsTransientError(err)).To(BeTrue())
+		})
+
+		It("Should report client errors as permanent", func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			}))
+
+			currentUser, err := user.Current()
+			Expect(err).ToNot(HaveOccurred())
+
+			currentGroup, err := user.LookupGroupId(currentUser.Gid)
+			Expect(err).ToNot(HaveOccurred())
+
+			properties := &model.ArchiveResourceProperties{
+				CommonResourceProperties: model.CommonResourceProperties{
+					Name: filepath.Join(tempDir, "archive.tar.gz"),
+				},
+				Url:   server.URL + "/archive.tar.gz",
+				Owner: currentUser.Username,
+				Group: currentGroup.Name,
+			}
+
+			err = provider.Download(context.Background(), properties, nil, logger)
+			Expect(err).To(MatchError(ContainSubstring("404")))
+			Expect(model.IsPermanentError(err)).To(BeTrue())
+			Expect(model.IsTransientError(err)).To(BeFalse())
+		})
+
+		Describe("Retries", func() {
+			var (
+				requests   atomic.Int32
+				properties *model.ArchiveResourceProperties
+				content    = []byte("retried archive content")
+			)
+
+			// flakyServer responds with failStatus to the first failures requests and serves the archive afterward
+			flakyServer := func(failures int32, failStatus int) {
+				requests.Store(0)
+				server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if requests.Add(1) <= failures {
+						w.WriteHeader(failStatus)
+						return
+					}
+
+					w.WriteHeader(http.StatusOK)
+					_, _ = w.Write(content)
+				}))
+
+				currentUser, err := user.Current()
+				Expect(err).ToNot(HaveOccurred())
+
+				currentGroup, err := user.LookupGroupId(currentUser.Gid)
+				Expect(err).ToNot(HaveOccurred())
+
+				checksum, err := iu.Sha256HashBytes(content)
+				Expect(err).ToNot(HaveOccurred())
+
+				properties = &model.ArchiveResourceProperties{
+					CommonResourceProperties: model.CommonResourceProperties{
+						Name: filepath.Join(tempDir, "archive.tar.gz"),
+					},
+					Url:        server.URL + "/archive.tar.gz",
+					Owner:      currentUser.Username,
+					Group:      currentGroup.Name,
+					Checksum:   checksum,
+					RetryDelay: "1ms",
+				}
+			}
+
+			// tempDirEntries lists the files left in the download directory
+			tempDirEntries := func() []string {
+				entries, err := os.ReadDir(tempDir)
+				Expect(err).ToNot(HaveOccurred())
+
+				var names []string
+				for _, entry := range entries {
+					names = append(names, entry.Name())
+				}
+
+				return names
+			}
+
+			It("Should retry server errors until the download succeeds", func() {
+				flakyServer(2, http.StatusServiceUnavailable)
+				properties.Retries = 2
+
+				Expect(provider.Download(context.Background(), properties, nil, logger)).To(Succeed())
+				Expect(requests.Load()).To(Equal(int32(3)))
+
+				data, err := os.ReadFile(properties.Name)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(data).To(Equal(content))
+				Expect(tempDirEntries()).To(Equal([]string{"archive.tar.gz"}))
+			})
+
+			It("Should fail once all retries failed", func() {
+				flakyServer(3, http.StatusBadGateway)
+				properties.Retries = 2
+
+				err := provider.Download(context.Background(), properties, nil, logger)
+				Expect(err).To(MatchError(ContainSubstring("502")))
+				Expect(model.IsTransientError(err)).To(BeTrue())
+				Expect(requests.Load()).To(Equal(int32(3)))
+				Expect(tempDirEntries()).To(BeEmpty())
+			})
+
+			It("Should not retry client errors", func() {
+				flakyServer(1, http.StatusForbidden)
+				properties.Retries = 3
+
+				err := provider.Download(context.Background(), properties, nil, logger)
+				Expect(err).To(MatchError(ContainSubstring("403")))
+				Expect(requests.Load()).To(Equal(int32(1)))
+				Expect(tempDirEntries()).To(BeEmpty())
+			})
+
+			It("Should not retry without retries", func() {
+				flakyServer(1, http.StatusServiceUnavailable)
+
+				err := provider.Download(context.Background(), properties, nil, logger)
+				Expect(model.IsTransientError(err)).To(BeTrue())
+				Expect(requests.Load()).To(Equal(int32(1)))
+			})
+
+			It("Should stop retrying when the context is canceled", func() {
+				flakyServer(1, http.StatusServiceUnavailable)
+				properties.Retries = 1
+				properties.RetryDelay = "1m"
+
+				ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+				defer cancel()
+
+				err := provider.Download(ctx, properties, nil, logger)
+				Expect(err).To(MatchError("sleep interrupted by context"))
+				Expect(requests.Load()).To(Equal(int32(1)))
+			})
+		})
+
+		Describe("Resume", func() {
+			var (
+				requests   atomic.Int32
+				ranges     []string
+				mu         sync.Mutex
+				properties *model.ArchiveResourceProperties
+				content    []byte
+			)
+
+			BeforeEach(func() {
+				content = make([]byte, 64*1024)
+				for i := range content {
+					content[i] = byte(i % 251)
+				}
+
+				requests.Store(0)
+				ranges = nil
+			})
+
+			// interruptedServer sends half the archive and drops the connection on the first request, later requests
+			// are served by serve
+			interruptedServer := func(acceptRanges bool, serve http.HandlerFunc) {
+				server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					mu.Lock()
+					ranges = append(ranges, r.Header.Get("Range"))
+					mu.Unlock()
+
+					if requests.Add(1) == 1 {
+						if acceptRanges {
+							w.Header().Set("Accept-Ranges", "bytes")
+						}
+						w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+						w.WriteHeader(http.StatusOK)
+						_, _ = w.Write(content[:len(content)/2])
+						return
+					}
+
+					serve(w, r)
+				}))
+
+				currentUser, err := user.Current()
+				Expect(err).ToNot(HaveOccurred())
+
+				currentGroup, err := user.LookupGroupId(currentUser.Gid)
+				Expect(err).ToNot(HaveOccurred())
+
+				checksum, err := iu.Sha256HashBytes(content)
+				Expect(err).ToNot(HaveOccurred())
+
+				properties = &model.ArchiveResourceProperties{
+					CommonResourceProperties: model.CommonResourceProperties{
+						Name: filepath.Join(tempDir, "archive.tar.gz"),
+					},
+					Url:        server.URL + "/archive.tar.gz",
+					Owner:      currentUser.Username,
+					Group:      currentGroup.Name,
+					Checksum:   checksum,
+					Resume:     true,
+					RetryDelay: "1ms",
+				}
+			}
+
+			serveRanges := func(w http.ResponseWriter, r *http.Request) {
+				http.ServeContent(w, r, "archive.tar.gz", time.Time{}, bytes.NewReader(content))
+			}
+
+			It("Should resume interrupted downloads using range requests", func() {
+				interruptedServer(true, serveRanges)
+				properties.Retries = 1
+
+				Expect(provider.Download(context.Background(), properties, nil, logger)).To(Succeed())
+				Expect(ranges).To(Equal([]string{"", fmt.Sprintf("bytes=%d-", len(content)/2)}))
+
+				data, err := os.ReadFile(properties.Name)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(data).To(Equal(content))
+				Expect(PartialDownloadPath(properties.Name)).ToNot(BeAnExistingFile())
+			})
+
+			It("Should keep the partial download for later runs", func() {
+				interruptedServer(true, serveRanges)
+
+				err := provider.Download(context.Background(), properties, nil, logger)
+				Expect(model.IsTransientError(err)).To(BeTrue())
+
+				partial, err := os.ReadFile(PartialDownloadPath(properties.Name))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(partial).To(Equal(content[:len(content)/2]))
+
+				Expect(provider.Download(context.Background(), properties, nil, logger)).To(Succeed())
+				Expect(ranges).To(HaveLen(2))
+				Expect(ranges[1]).To(Equal(fmt.Sprintf("bytes=%d-", len(content)/2)))
+
+				data, err := os.ReadFile(properties.Name)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(data).To(Equal(content))
+			})
+
+			It("Should download the full archive when the server ignores the range", func() {
+				interruptedServer(true, func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+					_, _ = w.Write(content)
+				})
+				properties.Retries = 1
+
+				Expect(provider.Download(context.Background(), properties, nil, logger)).To(Succeed())
+				Expect(ranges[1]).ToNot(BeEmpty())
+
+				data, err := os.ReadFile(properties.Name)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(data).To(Equal(content))
+			})
+
+			It("Should not resume downloads from servers without range support", func() {
+				interruptedServer(false, serveRanges)
+				properties.Retries = 1
+
+				Expect(provider.Download(context.Background(), properties, nil, logger)).To(Succeed())
+				Expect(ranges).To(Equal([]string{"", ""}))
+
+				data, err := os.ReadFile(properties.Name)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(data).To(Equal(content))
+			})
+
+			It("Should discard partial downloads that fail the checksum", func() {
+				interruptedServer(true, serveRanges)
+				properties.Retries = 1
+				properties.Checksum = strings.Repeat("0", 64)
+
+				err := provider.Download(context.Background(), properties, nil, logger)
+				Expect(err).To(MatchError(ContainSubstring("checksum mismatch")))
+				Expect(PartialDownloadPath(properties.Name)).ToNot(BeAnExistingFile())
+				Expect(properties.Name).ToNot(BeAnExistingFile())
+			})
 		})
 
 		It("Should verify checksum when provided", func() {
@@ -244,12 +591,54 @@ var _ = Describe("Http Provider", func() {
 				Checksum: expectedChecksum,
 			}
 
-			err = provider.Download(context.Background(), properties, logger)
+			err = provider.Download(context.Background(), properties, nil, logger)
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(iu.FileExists(destFile)).To(BeTrue())
 		})
 
+		It("Should use the download cache when a checksum is provided", func() {
+			content := []byte("cached archive content")
+			requests := 0
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write(content)
+			}))
+
+			currentUser, err := user.Current()
+			Expect(err).ToNot(HaveOccurred())
+
+			currentGroup, err := user.LookupGroupId(currentUser.Gid)
+			Expect(err).ToNot(HaveOccurred())
+
+			checksum, err := iu.Sha256HashBytes(content)
+			Expect(err).ToNot(HaveOccurred())
+
+			cache, err := downloadcache.New(filepath.Join(tempDir, "cache"), 0, logger)
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, dest := range []string{"first.tar.gz", "second.tar.gz"} {
+				properties := &model.ArchiveResourceProperties{
+					CommonResourceProperties: model.CommonResourceProperties{
+						Name: filepath.Join(tempDir, dest),
+					},
+					Url:      server.URL + "/archive.tar.gz",
+					Owner:    currentUser.Username,
+					Group:    currentGroup.Name,
+					Checksum: checksum,
+				}
+
+				Expect(provider.Download(context.Background(), properties, cache, logger)).To(Succeed())
+
+				data, err := os.ReadFile(properties.Name)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(data).To(Equal(content))
+			}
+
+			Expect(requests).To(Equal(1))
+		})
+
 		It("Should fail on checksum mismatch", func() {
 			content := []byte("test content")
 			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -274,9 +663,10 @@ var _ = Describe("Http Provider", func() {
 				Checksum: "invalid_checksum_that_will_not_match",
 			}
 
-			err = provider.Download(context.Background(), properties, logger)
+			err = provider.Download(context.Background(), properties, nil, logger)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("checksum mismatch"))
+			Expect(model.IsPermanentError(err)).To(BeTrue())
 
 			// Temp file should be cleaned up, dest file should not exist
 			Expect(iu.FileExists(destFile)).To(BeFalse())
@@ -293,7 +683,7 @@ var _ = Describe("Http Provider", func() {
 				Group: "root",
 			}
 
-			err = provider.Download(context.Background(), properties, logger)
+			err = provider.Download(context.Background(), properties, nil, logger)
 			Expect(err).To(HaveOccurred())
 		})
 	})
@@ -359,6 +749,39 @@ var _ = Describe("Http Provider", func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 
+		DescribeTable("Should extract xz and bzip2 compressed tar archives",
+			func(name string, flags string) {
+				archiveFile := filepath.Join(tempDir, name)
+				extractDir := filepath.Join(tempDir, "extract")
+
+				err := os.WriteFile(archiveFile, []byte("fake archive"), 0644)
+				Expect(err).ToNot(HaveOccurred())
+
+				properties := &model.ArchiveResourceProperties{
+					CommonResourceProperties: model.CommonResourceProperties{
+						Name: archiveFile,
+					},
+					ExtractParent: extractDir,
+				}
+
+				runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{
+					Command: "tar",
+					Args:    []string{flags, archiveFile, "-C", extractDir},
+					Cwd:     extractDir,
+					Timeout: time.Minute,
+				}).Return([]byte{}, []byte{}, 0, nil)
+
+				err = provider.Extract(context.Background(), properties, logger)
+				Expect(err).ToNot(HaveOccurred())
+			},
+			Entry("tar.xz", "test.tar.xz", "-xJf"),
+			Entry("txz", "test.txz", "-xJf"),
+			Entry("TAR.XZ", "test.TAR.XZ", "-xJf"),
+			Entry("tar.bz2", "test.tar.bz2", "-xjf"),
+			Entry("tbz2", "test.tbz2", "-xjf"),
+			Entry("TBZ2", "test.TBZ2", "-xjf"),
+		)
+
 		It("Should extract tar archive", func() {
 			archiveFile := filepath.Join(tempDir, "test.tar")
 			extractDir := filepath.Join(tempDir, "extract")
@@ -679,33 +1102,47 @@ var _ = Describe("Http Provider", func() {
 		})
 	})
 
-	Describe("toolForFileName", func() {
+	Describe("ToolForFileName", func() {
 		It("Should return tar for .tar.gz files", func() {
-			Expect(toolForFileName("/path/to/file.tar.gz")).To(Equal("tar"))
+			Expect(ToolForFileName("/path/to/file.tar.gz")).To(Equal("tar"))
 		})
 
 		It("Should return tar for .tgz files", func() {
-			Expect(toolForFileName("/path/to/file.tgz")).To(Equal("tar"))
+			Expect(ToolForFileName("/path/to/file.tgz")).To(Equal("tar"))
 		})
 
 		It("Should return tar for .tar files", func() {
-			Expect(toolForFileName("/path/to/file.tar")).To(Equal("tar"))
+			Expect(ToolForFileName("/path/to/file.tar")).To(Equal("tar"))
 		})
 
 		It("Should return unzip for .zip files", func() {
-			Expect(toolForFileName("/path/to/file.zip")).To(Equal("unzip"))
+			Expect(ToolForFileName("/path/to/file.zip")).To(Equal("unzip"))
 		})
 
 		It("Should return empty string for unsupported extensions", func() {
-			Expect(toolForFileName("/path/to/file.rar")).To(Equal(""))
-			Expect(toolForFileName("/path/to/file.7z")).To(Equal(""))
-			Expect(toolForFileName("/path/to/file.txt")).To(Equal(""))
+			Expect(ToolForFileName("/path/to/file.rar")).To(Equal(""))
+			Expect(ToolForFileName("/path/to/file.7z")).To(Equal(""))
+			Expect(ToolForFileName("/path/to/file.txt")).To(Equal(""))
 		})
 
 		It("Should handle case insensitive extensions", func() {
-			Expect(toolForFileName("/path/to/file.TAR.GZ")).To(Equal("tar"))
-			Expect(toolForFileName("/path/to/file.ZIP")).To(Equal("unzip"))
+			Expect(ToolForFileName("/path/to/file.TAR.GZ")).To(Equal("tar"))
+			Expect(ToolForFileName("/path/to/file.ZIP")).To(Equal("unzip"))
 		})
+
+		DescribeTable("Should return tar for xz and bzip2 compressed tar archives",
+			func(name string) {
+				Expect(ToolForFileName(name)).To(Equal("tar"))
+			},
+			Entry(".tar.xz", "/path/to/file.tar.xz"),
+			Entry(".txz", "/path/to/file.txz"),
+			Entry(".tar.bz2", "/path/to/file.tar.bz2"),
+			Entry(".tbz2", "/path/to/file.tbz2"),
+			Entry(".TAR.XZ", "/path/to/file.TAR.XZ"),
+			Entry(".TXZ", "/path/to/file.TXZ"),
+			Entry(".TAR.BZ2", "/path/to/file.TAR.BZ2"),
+			Entry(".TBZ2", "/path/to/file.TBZ2"),
+		)
 	})
 
 	Describe("Constants", func() {
@@ -768,6 +1205,28 @@ var _ = Describe("Http Factory", func() {
 			}
 		})
 
+		It("Should accept xz and bzip2 compressed tar archives", func() {
+			_, found, err := iu.ExecutableInPath("tar")
+			Expect(err).ToNot(HaveOccurred())
+			if !found {
+				Skip("tar is not in PATH")
+			}
+
+			for _, name := range []string{"archive.tar.xz", "archive.txz", "archive.tar.bz2", "archive.tbz2"} {
+				props := &model.ArchiveResourceProperties{
+					CommonResourceProperties: model.CommonResourceProperties{
+						Name: "/tmp/" + name,
+					},
+					Url: "https://example.com/" + name,
+				}
+
+				manageable, priority, err := f.IsManageable(nil, props)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(manageable).To(BeTrue(), name)
+				Expect(priority).To(Equal(1))
+			}
+		})
+
 		It("Should return false for non-http URLs", func() {
 			props := &model.ArchiveResourceProperties{
 				CommonResourceProperties: model.CommonResourceProperties{
@@ -127,17 +127,17 @@ func (m *MockArchiveProvider) EXPECT() *MockArchiveProviderMockRecorder {
 }
 
 // Download mocks base method.
-func (m *MockArchiveProvider) Download(ctx context.Context, properties *model.ArchiveResourceProperties, log model.Logger) error {
+func (m *MockArchiveProvider) Download(ctx context.Context, properties *model.ArchiveResourceProperties, cache model.DownloadCache, log model.Logger) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Download", ctx, properties, log)
+	ret := m.ctrl.Call(m, "Download", ctx, properties, cache, log)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Download indicates an expected call of Download.
-func (mr *MockArchiveProviderMockRecorder) Download(ctx, properties, log any) *gomock.Call {
+func (mr *MockArchiveProviderMockRecorder) Download(ctx, properties, cache, log any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Download", reflect.TypeOf((*MockArchiveProvider)(nil).Download), ctx, properties, log)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Download", reflect.TypeOf((*MockArchiveProvider)(nil).Download), ctx, properties, cache, log)
 }
 
 // Extract mocks base method.
@@ -0,0 +1,60 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package s3
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/choria-io/ccm/internal/registry"
+	iu "github.com/choria-io/ccm/internal/util"
+	"github.com/choria-io/ccm/model"
+	archivehttp "github.com/choria-io/ccm/resources/archive/http"
+)
+
+func Register() {
+	registry.MustRegister(&factory{})
+}
+
+type factory struct{}
+
+func (p *factory) TypeName() string { return model.ArchiveTypeName }
+func (p *factory) Name() string     { return ProviderName }
+func (p *factory) New(log model.Logger, runner model.CommandRunner) (model.Provider, error) {
+	return NewS3Provider(log, runner)
+}
+func (p *factory) NewWithConfig(log model.Logger, runner model.CommandRunner, config map[string]any) (model.Provider, error) {
+	return NewS3ProviderWithConfig(log, runner, config)
+}
+func (p *factory) IsManageable(_ map[string]any, prop model.ResourceProperties) (bool, int, error) {
+	ap, ok := prop.(*model.ArchiveResourceProperties)
+	if !ok {
+		return false, 0, fmt.Errorf("invalid properties %T", prop)
+	}
+
+	uri, err := url.Parse(ap.Url)
+	if err != nil {
+		return false, 0, fmt.Errorf("invalid URL %q: %w", ap.Url, err)
+	}
+
+	if uri.Scheme != "s3" {
+		return false, 0, nil
+	}
+
+	tool := archivehttp.ToolForFileName(ap.Name)
+	if tool == "" {
+		return false, 0, nil
+	}
+
+	_, found, err := iu.ExecutableInPath(tool)
+	if err != nil {
+		return false, 0, err
+	}
+	if !found {
+		return false, 0, nil
+	}
+
+	return true, 1, nil
+}
@@ -0,0 +1,217 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package s3
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/choria-io/fisk"
+
+	"github.com/choria-io/ccm/model"
+	archivehttp "github.com/choria-io/ccm/resources/archive/http"
+)
+
+const ProviderName = "s3"
+
+type Provider struct {
+	log    model.Logger
+	runner model.CommandRunner
+	config Config
+
+	// archive extracts and inspects downloaded archives, this is the same for all archive sources
+	archive *archivehttp.Provider
+}
+
+// Config is the provider configuration set using the manager WithProviderConfig option
+type Config struct {
+	Timeout  string `json:"timeout,omitempty"`  // Timeout is the maximum time a download may take, 1 minute when unset
+	Region   string `json:"region,omitempty"`   // Region is the region of the buckets, taken from the environment or profile when unset
+	Profile  string `json:"profile,omitempty"`  // Profile is the shared configuration profile to load credentials and settings from
+	Endpoint string `json:"endpoint,omitempty"` // Endpoint is the URL of an S3 compatible object store, buckets are addressed using path style requests
+
+	timeout time.Duration
+}
+
+func NewS3Provider(log model.Logger, runner model.CommandRunner) (*Provider, error) {
+	archive, err := archivehttp.NewHttpProvider(log, runner)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Provider{log: log, runner: runner, archive: archive}, nil
+}
+
+// NewS3ProviderWithConfig creates a provider configured using the provider level configuration in config
+func NewS3ProviderWithConfig(log model.Logger, runner model.CommandRunner, config map[string]any) (*Provider, error) {
+	p, err := NewS3Provider(log, runner)
+	if err != nil {
+		return nil, err
+	}
+
+	j, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s provider configuration: %w", ProviderName, err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(j))
+	dec.DisallowUnknownFields()
+	err = dec.Decode(&p.config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s provider configuration: %w", ProviderName, err)
+	}
+
+	if p.config.Timeout != "" {
+		p.config.timeout, err = fisk.ParseDuration(p.config.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s provider configuration: invalid timeout: %w", ProviderName, err)
+		}
+	}
+
+	if p.config.Endpoint != "" {
+		_, err = url.ParseRequestURI(p.config.Endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s provider configuration: invalid endpoint: %w", ProviderName, err)
+		}
+	}
+
+	return p, nil
+}
+
+// Download fetches the archive from the bucket into place, when a cache is given and a checksum is set the archive
+// is served from and stored in the cache
+func (p *Provider) Download(ctx context.Context, properties *model.ArchiveResourceProperties, cache model.DownloadCache, log model.Logger) error {
+	bucket, key, err := parseObjectUrl(properties.Url)
+	if err != nil {
+		return err
+	}
+
+	return archivehttp.SaveArchive(p.log, properties, cache, log, func(tf *os.File) error {
+		return p.fetch(ctx, bucket, key, tf, log)
+	})
+}
+
+// fetch downloads the object into tf
+func (p *Provider) fetch(ctx context.Context, bucket string, key string, tf *os.File, log model.Logger) error {
+	timeout := p.config.timeout
+	if timeout == 0 {
+		timeout = time.Minute
+	}
+
+	tctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client, err := p.client(tctx)
+	if err != nil {
+		return err
+	}
+
+	p.log.Info("Downloading", "bucket", bucket, "key", key)
+
+	resp, err := client.GetObject(tctx, &s3.GetObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+	if err != nil {
+		return classifyError(fmt.Errorf("could not get s3://%s/%s: %w", bucket, key, err))
+	}
+	defer resp.Body.Close()
+
+	copied, err := io.Copy(tf, resp.Body)
+	if err != nil {
+		return model.TransientErrorf("could not copy file: %w", err)
+	}
+	log.Info("Archive downloaded", "bytes", copied)
+
+	return nil
+}
+
+// client creates a S3 client using credentials and settings from the environment and shared configuration files,
+// the provider configuration takes precedence
+func (p *Provider) client(ctx context.Context) (*s3.Client, error) {
+	var opts []func(*config.LoadOptions) error
+	if p.config.Region != "" {
+		opts = append(opts, config.WithRegion(p.config.Region))
+	}
+	if p.config.Profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(p.config.Profile))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, model.PermanentErrorf("could not load s3 configuration: %w", err)
+	}
+
+	return s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if p.config.Endpoint != "" {
+			o.BaseEndpoint = aws.String(p.config.Endpoint)
+			o.UsePathStyle = true
+		}
+	}), nil
+}
+
+// classifyError marks server side failures and throttling as transient, other failures such as missing objects or
+// denied access are permanent
+func classifyError(err error) error {
+	var re *awshttp.ResponseError
+	if !errors.As(err, &re) {
+		return model.NewTransientError(err)
+	}
+
+	code := re.HTTPStatusCode()
+	if code >= 500 || code == http.StatusTooManyRequests || code == http.StatusRequestTimeout {
+		return model.NewTransientError(err)
+	}
+
+	return model.NewPermanentError(err)
+}
+
+// parseObjectUrl extracts the bucket and key from a s3://bucket/key url
+func parseObjectUrl(u string) (string, string, error) {
+	uri, err := url.Parse(u)
+	if err != nil {
+		return "", "", err
+	}
+
+	if uri.Scheme != "s3" {
+		return "", "", fmt.Errorf("invalid s3 url %q: scheme must be s3", u)
+	}
+
+	key := strings.TrimPrefix(uri.Path, "/")
+	if uri.Host == "" || key == "" {
+		return "", "", fmt.Errorf("invalid s3 url %q: must be in the form s3://bucket/key", u)
+	}
+
+	return uri.Host, key, nil
+}
+
+func (p *Provider) Extract(ctx context.Context, properties *model.ArchiveResourceProperties, log model.Logger) error {
+	return p.archive.Extract(ctx, properties, log)
+}
+
+func (p *Provider) Status(ctx context.Context, properties *model.ArchiveResourceProperties) (*model.ArchiveState, error) {
+	state, err := p.archive.Status(ctx, properties)
+	if err != nil {
+		return nil, err
+	}
+
+	state.Metadata.Provider = ProviderName
+
+	return state, nil
+}
+
+func (p *Provider) Name() string {
+	return ProviderName
+}
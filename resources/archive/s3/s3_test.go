@@ -0,0 +1,295 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package s3
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/choria-io/ccm/internal/downloadcache"
+	iu "github.com/choria-io/ccm/internal/util"
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/model/modelmocks"
+)
+
+func TestS3Provider(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Resources/Archive/S3")
+}
+
+var _ = Describe("S3 Provider", func() {
+	var (
+		mockctl  *gomock.Controller
+		logger   *modelmocks.MockLogger
+		runner   *modelmocks.MockCommandRunner
+		provider *Provider
+		server   *httptest.Server
+		objects  map[string][]byte
+		requests []*http.Request
+		tempDir  string
+		owner    string
+		group    string
+	)
+
+	BeforeEach(func() {
+		mockctl = gomock.NewController(GinkgoT())
+		logger = modelmocks.NewMockLogger(mockctl)
+		runner = modelmocks.NewMockCommandRunner(mockctl)
+
+		logger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+		logger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+		logger.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+
+		tempDir = GinkgoT().TempDir()
+
+		// credentials and region come from the environment, shared configuration files are isolated from the host
+		GinkgoT().Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+		GinkgoT().Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+		GinkgoT().Setenv("AWS_REGION", "eu-west-1")
+		GinkgoT().Setenv("AWS_CONFIG_FILE", filepath.Join(tempDir, "config"))
+		GinkgoT().Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(tempDir, "credentials"))
+		GinkgoT().Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+		objects = map[string][]byte{}
+		requests = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.Clone(context.Background()))
+
+			switch {
+			case r.URL.Path == "/broken/archive.tar.gz":
+				w.WriteHeader(http.StatusServiceUnavailable)
+			case objects[r.URL.Path] != nil:
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write(objects[r.URL.Path])
+			default:
+				w.Header().Set("Content-Type", "application/xml")
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`))
+			}
+		}))
+		DeferCleanup(server.Close)
+
+		var err error
+		provider, err = NewS3ProviderWithConfig(logger, runner, map[string]any{"endpoint": server.URL})
+		Expect(err).ToNot(HaveOccurred())
+
+		currentUser, err := user.Current()
+		Expect(err).ToNot(HaveOccurred())
+		currentGroup, err := user.LookupGroupId(currentUser.Gid)
+		Expect(err).ToNot(HaveOccurred())
+		owner = currentUser.Username
+		group = currentGroup.Name
+	})
+
+	AfterEach(func() {
+		mockctl.Finish()
+	})
+
+	properties := func(url string, checksum string) *model.ArchiveResourceProperties {
+		return &model.ArchiveResourceProperties{
+			CommonResourceProperties: model.CommonResourceProperties{
+				Name: filepath.Join(tempDir, "archive.tar.gz"),
+			},
+			Url:      url,
+			Owner:    owner,
+			Group:    group,
+			Checksum: checksum,
+		}
+	}
+
+	Describe("NewS3ProviderWithConfig", func() {
+		It("Should reject invalid configuration", func() {
+			_, err := NewS3ProviderWithConfig(logger, runner, map[string]any{"bucket": "x"})
+			Expect(err).To(MatchError(ContainSubstring("invalid s3 provider configuration")))
+
+			_, err = NewS3ProviderWithConfig(logger, runner, map[string]any{"timeout": "soon"})
+			Expect(err).To(MatchError(ContainSubstring("invalid timeout")))
+
+			_, err = NewS3ProviderWithConfig(logger, runner, map[string]any{"endpoint": "minio"})
+			Expect(err).To(MatchError(ContainSubstring("invalid endpoint")))
+		})
+
+		It("Should parse the configuration", func() {
+			p, err := NewS3ProviderWithConfig(logger, runner, map[string]any{"timeout": "10s", "region": "us-east-1", "profile": "deploy"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(p.config.timeout.String()).To(Equal("10s"))
+			Expect(p.config.Region).To(Equal("us-east-1"))
+			Expect(p.config.Profile).To(Equal("deploy"))
+		})
+	})
+
+	Describe("Download", func() {
+		It("Should download the object using signed path style requests", func() {
+			objects["/releases/app/archive.tar.gz"] = []byte("archive content")
+
+			err := provider.Download(context.Background(), properties("s3://releases/app/archive.tar.gz", ""), nil, logger)
+			Expect(err).ToNot(HaveOccurred())
+
+			data, err := os.ReadFile(filepath.Join(tempDir, "archive.tar.gz"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(data).To(Equal([]byte("archive content")))
+
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].Method).To(Equal(http.MethodGet))
+			Expect(requests[0].Header.Get("Authorization")).To(ContainSubstring("Credential=AKIDEXAMPLE/"))
+			Expect(requests[0].Header.Get("Authorization")).To(ContainSubstring("/eu-west-1/s3/"))
+		})
+
+		It("Should verify the checksum", func() {
+			content := []byte("checked content")
+			objects["/releases/archive.tar.gz"] = content
+
+			checksum, err := iu.Sha256HashBytes(content)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(provider.Download(context.Background(), properties("s3://releases/archive.tar.gz", checksum), nil, logger)).To(Succeed())
+			Expect(iu.FileExists(filepath.Join(tempDir, "archive.tar.gz"))).To(BeTrue())
+		})
+
+		It("Should fail on checksum mismatch and remove the temporary file", func() {
+			objects["/releases/archive.tar.gz"] = []byte("unexpected content")
+
+			err := provider.Download(context.Background(), properties("s3://releases/archive.tar.gz", "invalid_checksum_that_will_not_match"), nil, logger)
+			Expect(err).To(MatchError(ContainSubstring("checksum mismatch")))
+			Expect(model.IsPermanentError(err)).To(BeTrue())
+
+			entries, err := os.ReadDir(tempDir)
+			Expect(err).ToNot(HaveOccurred())
+			for _, entry := range entries {
+				Expect(strings.HasPrefix(entry.Name(), "archive.tar.gz")).To(BeFalse(), entry.Name())
+			}
+		})
+
+		It("Should use the download cache when a checksum is provided", func() {
+			content := []byte("cached content")
+			objects["/releases/archive.tar.gz"] = content
+
+			checksum, err := iu.Sha256HashBytes(content)
+			Expect(err).ToNot(HaveOccurred())
+
+			cache, err := downloadcache.New(filepath.Join(tempDir, "cache"), 0, logger)
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, dest := range []string{"first.tar.gz", "second.tar.gz"} {
+				props := properties("s3://releases/archive.tar.gz", checksum)
+				props.Name = filepath.Join(tempDir, dest)
+
+				Expect(provider.Download(context.Background(), props, cache, logger)).To(Succeed())
+
+				data, err := os.ReadFile(props.Name)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(data).To(Equal(content))
+			}
+
+			Expect(requests).To(HaveLen(1))
+		})
+
+		It("Should report missing objects as permanent errors", func() {
+			err := provider.Download(context.Background(), properties("s3://releases/missing.tar.gz", ""), nil, logger)
+			Expect(err).To(MatchError(ContainSubstring("could not get s3://releases/missing.tar.gz")))
+			Expect(model.IsPermanentError(err)).To(BeTrue())
+			Expect(iu.FileExists(filepath.Join(tempDir, "archive.tar.gz"))).To(BeFalse())
+		})
+
+		It("Should report server errors as transient errors", func() {
+			err := provider.Download(context.Background(), properties("s3://broken/archive.tar.gz", ""), nil, logger)
+			Expect(err).To(HaveOccurred())
+			Expect(model.IsTransientError(err)).To(BeTrue())
+		})
+
+		It("Should reject invalid urls", func() {
+			err := provider.Download(context.Background(), properties("s3://releases", ""), nil, logger)
+			Expect(err).To(MatchError(ContainSubstring("must be in the form s3://bucket/key")))
+			Expect(requests).To(BeEmpty())
+		})
+	})
+
+	Describe("Status", func() {
+		It("Should report the s3 provider", func() {
+			Expect(os.WriteFile(filepath.Join(tempDir, "archive.tar.gz"), []byte("x"), 0644)).To(Succeed())
+
+			state, err := provider.Status(context.Background(), properties("s3://releases/archive.tar.gz", ""))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Ensure).To(Equal(model.EnsurePresent))
+			Expect(state.Metadata.Provider).To(Equal("s3"))
+		})
+	})
+
+	Describe("Extract", func() {
+		It("Should extract using the archive tools", func() {
+			props := properties("s3://releases/archive.tar.gz", "")
+			props.ExtractParent = filepath.Join(tempDir, "extract")
+
+			runner.EXPECT().ExecuteWithOptions(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, opts model.ExtendedExecOptions) ([]byte, []byte, int, error) {
+				Expect(opts.Command).To(Equal("tar"))
+				Expect(opts.Args).To(Equal([]string{"-xzf", props.Name, "-C", props.ExtractParent}))
+				return nil, nil, 0, nil
+			})
+
+			Expect(provider.Extract(context.Background(), props, logger)).To(Succeed())
+		})
+	})
+})
+
+var _ = Describe("S3 Factory", func() {
+	var f *factory
+
+	BeforeEach(func() {
+		f = &factory{}
+	})
+
+	Describe("IsManageable", func() {
+		It("Should only manage s3 urls with supported archive types", func() {
+			props := &model.ArchiveResourceProperties{
+				CommonResourceProperties: model.CommonResourceProperties{Name: "/tmp/archive.tar.gz"},
+				Url:                      "s3://releases/archive.tar.gz",
+			}
+
+			manageable, priority, err := f.IsManageable(nil, props)
+			Expect(err).ToNot(HaveOccurred())
+			// Will fail if tar is not in PATH, which is fine for CI
+			if manageable {
+				Expect(priority).To(Equal(1))
+			}
+
+			props.Url = "https://example.com/archive.tar.gz"
+			manageable, _, err = f.IsManageable(nil, props)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(manageable).To(BeFalse())
+
+			props.Url = "s3://releases/archive.rar"
+			props.Name = "/tmp/archive.rar"
+			manageable, _, err = f.IsManageable(nil, props)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(manageable).To(BeFalse())
+		})
+
+		It("Should return error for invalid properties type", func() {
+			_, _, err := f.IsManageable(nil, &model.ExecResourceProperties{})
+			Expect(err).To(MatchError(ContainSubstring("invalid properties")))
+		})
+	})
+
+	Describe("New", func() {
+		It("Should create a new provider", func() {
+			mockctl := gomock.NewController(GinkgoT())
+			defer mockctl.Finish()
+
+			provider, err := f.New(modelmocks.NewMockLogger(mockctl), modelmocks.NewMockCommandRunner(mockctl))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(provider.Name()).To(Equal("s3"))
+		})
+	})
+})
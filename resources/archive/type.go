@@ -29,6 +29,8 @@ type Type struct {
 }
 
 var _ model.Resource = (*Type)(nil)
+var _ base.ProviderFallback = (*Type)(nil)
+var _ base.StatusReporter = (*Type)(nil)
 
 func New(ctx context.Context, mgr model.Manager, properties model.ArchiveResourceProperties) (*Type, error) {
 	env, err := mgr.TemplateEnvironment(ctx)
@@ -129,7 +131,7 @@ func (t *Type) ApplyResource(ctx context.Context) (model.ResourceState, error) {
 
 			if !noop {
 				t.log.Info("Downloading archive")
-				err = p.Download(ctx, properties, t.log)
+				err = p.Download(ctx, properties, t.mgr.DownloadCache(), t.log)
 				if err != nil {
 					return nil, fmt.Errorf("download failed: %w", err)
 				}
@@ -183,6 +185,7 @@ func (t *Type) ApplyResource(ctx context.Context) (model.ResourceState, error) {
 	}
 
 	t.FinalizeState(finalStatus, noop, strings.Join(noopMessage, ". "), refreshState, isStable, false)
+	t.ClassifyChange(finalStatus, initialStatus.Ensure != model.EnsureAbsent)
 
 	return finalStatus, nil
 }
@@ -248,6 +251,16 @@ func (t *Type) Info(ctx context.Context) (any, error) {
 	return t.provider.(ArchiveProvider).Status(ctx, t.prop)
 }
 
+// CurrentState reports the current state of the resource without making any changes
+func (t *Type) CurrentState(ctx context.Context) (model.ResourceState, error) {
+	state, err := t.provider.(ArchiveProvider).Status(ctx, t.prop)
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
 func (t *Type) selectProviderUnlocked() error {
 	// TODO: move to base
 
@@ -261,7 +274,7 @@ func (t *Type) selectProviderUnlocked() error {
 	}
 
 	t.log.Debug("Trying to find providers")
-	selected, err := registry.FindSuitableProvider(model.ArchiveTypeName, t.prop.Provider, t.Facts, t.prop, t.log, runner)
+	selected, err := registry.FindSuitableProvider(model.ArchiveTypeName, t.prop.Provider, t.Facts, t.prop, t.log, runner, t.mgr)
 	if err != nil {
 		return err
 	}
@@ -276,6 +289,27 @@ func (t *Type) selectProviderUnlocked() error {
 	return nil
 }
 
+// SelectAlternateProvider replaces the selected provider with the most suitable provider not listed in exclude
+func (t *Type) SelectAlternateProvider(exclude []string) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	runner, err := t.mgr.NewRunner()
+	if err != nil {
+		return "", err
+	}
+
+	selected, err := registry.FindAlternateProvider(model.ArchiveTypeName, exclude, t.Facts, t.prop, t.log, runner, t.mgr)
+	if err != nil {
+		return "", err
+	}
+
+	t.log.Debug("Selected alternate provider", "provider", selected.Name())
+	t.provider = selected
+
+	return t.providerUnlocked(), nil
+}
+
 func (t *Type) SelectProvider() (string, error) {
 	// TODO: move to base
 
@@ -147,6 +147,36 @@ var _ = Describe("Archive Type", func() {
 			Expect(err).To(MatchError(model.ErrResourceEnsureRequired))
 		})
 
+		It("Should resolve templates before validating", func(ctx context.Context) {
+			factsMgr, _ := modelmocks.NewManager(map[string]any{"arch": "arm64", "mirror": ""}, data, false, mockctl)
+
+			archive, err := New(ctx, factsMgr, model.ArchiveResourceProperties{
+				CommonResourceProperties: model.CommonResourceProperties{
+					Name:   "/tmp/app-{{ Facts.arch }}.tar.gz",
+					Ensure: model.EnsurePresent,
+				},
+				Url:           "https://example.com/{{ Facts.arch }}/app.tar.gz",
+				ExtractParent: "/opt/app-{{ Facts.arch }}",
+				Owner:         "root",
+				Group:         "root",
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(archive.prop.Name).To(Equal("/tmp/app-arm64.tar.gz"))
+			Expect(archive.prop.Url).To(Equal("https://example.com/arm64/app.tar.gz"))
+			Expect(archive.prop.ExtractParent).To(Equal("/opt/app-arm64"))
+
+			_, err = New(ctx, factsMgr, model.ArchiveResourceProperties{
+				CommonResourceProperties: model.CommonResourceProperties{
+					Name:   "/tmp/app.tar.gz",
+					Ensure: model.EnsurePresent,
+				},
+				Url:   "https://{{ Facts.mirror }}/app.tar.gz",
+				Owner: "root",
+				Group: "root",
+			})
+			Expect(err).To(MatchError(ContainSubstring(`url "https:///app.tar.gz" must include a host`)))
+		})
+
 		It("Should set alias from properties", func(ctx context.Context) {
 			archive, err := New(ctx, mgr, model.ArchiveResourceProperties{
 				CommonResourceProperties: model.CommonResourceProperties{
@@ -239,7 +269,7 @@ var _ = Describe("Archive Type", func() {
 					}
 
 					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(initialState, nil)
-					provider.EXPECT().Download(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+					provider.EXPECT().Download(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 					provider.EXPECT().Extract(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(finalState, nil)
 
@@ -273,7 +303,7 @@ var _ = Describe("Archive Type", func() {
 					}
 
 					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(initialState, nil)
-					provider.EXPECT().Download(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+					provider.EXPECT().Download(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 					provider.EXPECT().Extract(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(finalState, nil)
 
@@ -289,7 +319,7 @@ var _ = Describe("Archive Type", func() {
 					}
 
 					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(initialState, nil)
-					provider.EXPECT().Download(gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("download failed"))
+					provider.EXPECT().Download(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("download failed"))
 
 					event, err := archive.Apply(ctx)
 					Expect(err).ToNot(HaveOccurred())
@@ -304,7 +334,7 @@ var _ = Describe("Archive Type", func() {
 					}
 
 					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(initialState, nil)
-					provider.EXPECT().Download(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+					provider.EXPECT().Download(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 					provider.EXPECT().Extract(gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("extract failed"))
 
 					event, err := archive.Apply(ctx)
@@ -361,7 +391,7 @@ var _ = Describe("Archive Type", func() {
 				}
 
 				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(initialState, nil)
-				provider.EXPECT().Download(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				provider.EXPECT().Download(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 				provider.EXPECT().Extract(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("final status failed"))
 
@@ -382,7 +412,7 @@ var _ = Describe("Archive Type", func() {
 				}
 
 				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(initialState, nil)
-				provider.EXPECT().Download(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				provider.EXPECT().Download(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 				provider.EXPECT().Extract(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(finalState, nil)
 
@@ -8,19 +8,20 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/choria-io/ccm/internal/backoff"
 	"github.com/choria-io/ccm/internal/healthcheck/goss"
 	"github.com/choria-io/ccm/internal/healthcheck/nagios"
 	"github.com/choria-io/ccm/internal/metrics"
-	"github.com/expr-lang/expr"
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/choria-io/ccm/model"
-	"github.com/choria-io/ccm/templates"
 )
 
 // EmbeddedResource is an interface that must be implemented by all resources that are based on this base
@@ -31,6 +32,18 @@ type EmbeddedResource interface {
 	Type() string
 }
 
+// ProviderFallback is implemented by resources whose providers rely on system executables, it allows
+// another suitable provider to be selected when the executable of the current one can not be found
+type ProviderFallback interface {
+	SelectAlternateProvider(exclude []string) (string, error)
+}
+
+// StatusReporter is implemented by resources that can report their current state without making changes, in
+// health check only mode the state is included in the event alongside the health check results
+type StatusReporter interface {
+	CurrentState(ctx context.Context) (model.ResourceState, error)
+}
+
 type Base struct {
 	Resource           EmbeddedResource
 	CommonProperties   model.CommonResourceProperties
@@ -72,18 +85,29 @@ func (b *Base) Apply(ctx context.Context) (*model.TransactionEvent, error) {
 }
 
 func (b *Base) applyOrHealthCheck(ctx context.Context, healthCheckOnly bool) (*model.TransactionEvent, error) {
+	start := time.Now()
+
 	provName, err := b.Resource.SelectProvider()
 	if err != nil {
-		return nil, err
+		if !b.shouldSkipUnmanageable(err) {
+			return nil, err
+		}
+
+		event := b.Resource.NewTransactionEvent()
+		recordTiming(event, start)
+		event.HealthCheckOnly = healthCheckOnly
+		event.RequestedEnsure = b.CommonProperties.Ensure
+		event.Skipped = true
+		event.NotApplicable = true
+		event.Errors = append(event.Errors, err.Error())
+
+		return event, nil
 	}
 
 	event := b.Resource.NewTransactionEvent()
 	event.Provider = provName
 	event.HealthCheckOnly = healthCheckOnly
-	start := time.Now()
-	defer func() {
-		event.Duration = time.Since(start)
-	}()
+	defer recordTiming(event, start)
 
 	var state model.ResourceState
 
@@ -100,6 +124,17 @@ func (b *Base) applyOrHealthCheck(ctx context.Context, healthCheckOnly bool) (*m
 	if err != nil {
 		return nil, err
 	}
+
+	// evaluated here rather than when loading so it sees facts and data as they are at this point in the run
+	should, err = b.ResourceProperties.CommonProperties().ShouldApply(env)
+	if err != nil {
+		return nil, err
+	}
+	if !should {
+		event.Skipped = true
+		event.ConditionNotMet = true
+		return event, nil
+	}
 	err = b.ResourceProperties.ResolveDeferredTemplates(env)
 	if err != nil {
 		return nil, err
@@ -129,14 +164,23 @@ func (b *Base) applyOrHealthCheck(ctx context.Context, healthCheckOnly bool) (*m
 		}
 
 		timer := prometheus.NewTimer(metrics.ResourceApplyTime.WithLabelValues(b.CommonProperties.Type, provName, name))
-		state, err = b.Resource.ApplyResource(ctx)
+		state, provName, err = b.applyWithTimeout(ctx, provName)
 		timer.ObserveDuration()
 
+		event.Provider = provName
+
 		event.Duration = time.Since(start)
 		if err != nil {
 			event.Failed = true
 			event.Errors = append(event.Errors, err.Error())
 		}
+	} else if sr, ok := b.Resource.(StatusReporter); ok {
+		state, err = sr.CurrentState(ctx)
+		if err != nil {
+			state = nil
+			event.Failed = true
+			event.Errors = append(event.Errors, fmt.Sprintf("status check failed: %v", err))
+		}
 	}
 
 	// TODO: make helper in healthchecks
@@ -176,45 +220,135 @@ func (b *Base) applyOrHealthCheck(ctx context.Context, healthCheckOnly bool) (*m
 		event.FinalEnsure = cs.Ensure
 		event.Noop = cs.Noop
 		event.NoopMessage = cs.NoopMessage
+		event.Diff = cs.Diff
 		event.Refreshed = cs.Refreshed
+		event.Corrective = cs.Corrective
 	}
 
 	return event, nil
 }
 
-func (b *Base) checkControl(ctx context.Context) (bool, error) {
-	cp := b.ResourceProperties.CommonProperties()
-	if cp.Control == nil {
-		return true, nil
+// applyWithTimeout applies the resource, when the resource has an apply timeout providers are called with a context
+// that is cancelled once it passes
+func (b *Base) applyWithTimeout(ctx context.Context, provName string) (model.ResourceState, string, error) {
+	timeout, err := b.ResourceProperties.CommonProperties().ApplyTimeoutDuration()
+	if err != nil {
+		return nil, provName, err
+	}
+	if timeout == 0 {
+		return b.applyResource(ctx, provName)
 	}
 
-	env, err := b.Manager.TemplateEnvironment(ctx)
-	if err != nil {
-		return false, err
+	tctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	state, provName, err := b.applyResource(tctx, provName)
+	if err != nil && ctx.Err() == nil && errors.Is(tctx.Err(), context.DeadlineExceeded) {
+		return nil, provName, fmt.Errorf("%w after %v: %w", model.ErrApplyTimeout, timeout, err)
 	}
 
-	ifRes := true
-	unlessRes := false
+	return state, provName, err
+}
 
-	if cp.Control.ManageIf != "" {
-		res, err := templates.ExprParse(cp.Control.ManageIf, env, expr.AsBool())
-		if err != nil {
-			return false, err
+// applyResource applies the resource and, when the provider executable can not be found, falls back to alternative
+// providers if the resource supports it and no specific provider was requested. Returns the name of the provider used.
+func (b *Base) applyResource(ctx context.Context, provName string) (model.ResourceState, string, error) {
+	tried := []string{provName}
+
+	for {
+		state, err := b.applyWithRetries(ctx)
+		if err == nil || !errors.Is(err, model.ErrExecutableNotFound) {
+			return state, provName, err
+		}
+
+		fb, ok := b.Resource.(ProviderFallback)
+		if !ok {
+			return state, provName, err
+		}
+
+		notManageable := model.NewNotManageableError(fmt.Errorf("%s: %w: %s provider could not run: %w", b.String(), model.ErrProviderNotManageable, provName, err))
+
+		if b.CommonProperties.Provider != "" {
+			return nil, provName, notManageable
+		}
+
+		alternate, ferr := fb.SelectAlternateProvider(tried)
+		if ferr != nil || slices.Contains(tried, alternate) {
+			b.Log.Debug("No alternate provider available", "provider", provName, "error", ferr)
+			return nil, provName, notManageable
 		}
 
-		ifRes = res.(bool)
+		b.UserLogger.Warn("Falling back to alternate provider", "failed", provName, "provider", alternate, "error", err)
+
+		tried = append(tried, alternate)
+		provName = alternate
+	}
+}
+
+// applyWithRetries applies the resource, retrying failures the provider reports as transient as often as the
+// resource control tries setting allows, permanent and unclassified failures are never retried
+func (b *Base) applyWithRetries(ctx context.Context) (model.ResourceState, error) {
+	tries := 1
+	var sleep time.Duration
+
+	cp := b.ResourceProperties.CommonProperties()
+	if cp.Control != nil {
+		var err error
+		tries, sleep, err = cp.Control.RetryPolicy()
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	if cp.Control.ManageUnless != "" {
-		res, err := templates.ExprParse(cp.Control.ManageUnless, env, expr.AsBool())
+	for attempt := 1; ; attempt++ {
+		state, err := b.Resource.ApplyResource(ctx)
+		if err == nil || attempt >= tries || !model.IsTransientError(err) {
+			return state, err
+		}
+
+		b.UserLogger.Warn("Retrying after transient failure", "resource", b.String(), "attempt", attempt, "tries", tries, "error", err)
+
+		err = backoff.InterruptableSleep(ctx, sleep)
 		if err != nil {
-			return false, err
+			return nil, err
 		}
+	}
+}
+
+// shouldSkipUnmanageable determines if a provider selection error means no provider can manage the resource on this
+// node and skipping such resources was requested for the resource or the manager, other errors are never skipped
+func (b *Base) shouldSkipUnmanageable(err error) bool {
+	if !errors.Is(err, model.ErrNoSuitableProvider) && !errors.Is(err, model.ErrProviderNotManageable) {
+		return false
+	}
+
+	cp := b.ResourceProperties.CommonProperties()
+	if cp.Control != nil && cp.Control.SkipIfUnmanageable {
+		return true
+	}
 
-		unlessRes = res.(bool)
+	return b.Manager.SkipIfUnmanageable()
+}
+
+func (b *Base) checkControl(ctx context.Context) (bool, error) {
+	cp := b.ResourceProperties.CommonProperties()
+	if cp.Control == nil {
+		return true, nil
+	}
+
+	env, err := b.Manager.TemplateEnvironment(ctx)
+	if err != nil {
+		return false, err
 	}
 
-	return ifRes && !unlessRes, nil
+	return cp.Control.ShouldManage(env)
+}
+
+// recordTiming sets when the resource started and completed, and how long it took, on event
+func recordTiming(event *model.TransactionEvent, start time.Time) {
+	event.Duration = time.Since(start)
+	event.StartedAt = start.UTC()
+	event.CompletedAt = event.StartedAt.Add(event.Duration)
 }
 
 func (b *Base) Type() string {
@@ -249,6 +383,14 @@ func (b *Base) FinalizeState(state model.ResourceState, noop bool, noopMessage s
 	cs.Refreshed = refreshed
 }
 
+// ClassifyChange marks a change as corrective when the resource existed before the run and was brought back to
+// the desired state, changes that created the resource or were triggered by a refresh are intentional.
+// Call after FinalizeState.
+func (b *Base) ClassifyChange(state model.ResourceState, existed bool) {
+	cs := state.CommonState()
+	cs.Corrective = cs.Changed && existed && !cs.Refreshed
+}
+
 // ShouldRefresh checks if any of the subscribed resources have changed and should trigger a refresh.
 // Returns true if a refresh should occur, the resource that triggered the refresh, and any error.
 func (b *Base) ShouldRefresh(subscribe []string) (bool, string, error) {
@@ -265,5 +407,18 @@ func (b *Base) ShouldRefresh(subscribe []string) (bool, string, error) {
 		}
 	}
 
+	if len(subscribe) == 0 {
+		return false, "", nil
+	}
+
+	// a refresh triggered in an earlier run that did not complete, only set when refresh state is persisted
+	source, err := b.Manager.PendingRefresh(b.CommonProperties.Type, b.CommonProperties.Name)
+	if err != nil {
+		return false, "", err
+	}
+	if source != "" {
+		return true, source, nil
+	}
+
 	return false, "", nil
 }
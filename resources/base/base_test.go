@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -22,6 +23,18 @@ func TestBase(t *testing.T) {
 	RunSpecs(t, "Resources/Base")
 }
 
+// statusResource adds StatusReporter support to the mock resource
+type statusResource struct {
+	*MockEmbeddedResource
+
+	state model.ResourceState
+	err   error
+}
+
+func (r *statusResource) CurrentState(_ context.Context) (model.ResourceState, error) {
+	return r.state, r.err
+}
+
 func stringPtr(s string) *string {
 	return &s
 }
@@ -185,13 +198,43 @@ var _ = Describe("Base", func() {
 			Expect(result.Changed).To(BeFalse())
 		})
 
+		It("Should include the current state without applying when supported", func(ctx context.Context) {
+			props.HealthChecks = nil
+			b.Resource = &statusResource{
+				MockEmbeddedResource: mockRes,
+				state: &model.FileState{
+					CommonResourceState: model.CommonResourceState{Ensure: model.EnsureAbsent},
+					Metadata:            &model.FileMetadata{},
+				},
+			}
+
+			result, err := b.Healthcheck(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Failed).To(BeFalse())
+			Expect(result.Changed).To(BeFalse())
+			Expect(result.HealthCheckOnly).To(BeTrue())
+			Expect(result.FinalEnsure).To(Equal(model.EnsureAbsent))
+			Expect(result.Status).ToNot(BeNil())
+		})
+
+		It("Should fail when the current state cannot be determined", func(ctx context.Context) {
+			props.HealthChecks = nil
+			b.Resource = &statusResource{MockEmbeddedResource: mockRes, err: fmt.Errorf("status failed")}
+
+			result, err := b.Healthcheck(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Failed).To(BeTrue())
+			Expect(result.Errors).To(ContainElement("status check failed: status failed"))
+			Expect(result.Status).To(BeNil())
+		})
+
 		It("Should succeed when health check passes", func(ctx context.Context) {
 			props.HealthChecks = []model.CommonHealthCheck{{
 				Command: "/usr/bin/test -f /tmp/testfile",
 				Format:  model.HealthCheckNagiosFormat,
 			}}
 
-			runner.EXPECT().Execute(gomock.Any(), "/usr/bin/test", "-f", "/tmp/testfile").
+			runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/bin/test", Args: []string{"-f", "/tmp/testfile"}}).
 				Return([]byte("OK"), []byte{}, 0, nil)
 
 			result, err := b.Healthcheck(ctx)
@@ -208,7 +251,7 @@ var _ = Describe("Base", func() {
 				Format:  model.HealthCheckNagiosFormat,
 			}}
 
-			runner.EXPECT().Execute(gomock.Any(), "/usr/bin/test", "-f", "/tmp/testfile").
+			runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/bin/test", Args: []string{"-f", "/tmp/testfile"}}).
 				Return([]byte("CRITICAL"), []byte{}, 2, nil)
 
 			result, err := b.Healthcheck(ctx)
@@ -226,7 +269,7 @@ var _ = Describe("Base", func() {
 				Format:  model.HealthCheckNagiosFormat,
 			}}
 
-			runner.EXPECT().Execute(gomock.Any(), "/usr/bin/check_something").
+			runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/bin/check_something"}).
 				Return([]byte("WARNING: something is not quite right"), []byte{}, 1, nil)
 
 			result, err := b.Healthcheck(ctx)
@@ -237,13 +280,39 @@ var _ = Describe("Base", func() {
 			Expect(result.HealthChecks[0].Status).To(Equal(model.HealthCheckWarning))
 		})
 
+		It("Should pass when a retried health check recovers", func(ctx context.Context) {
+			props.HealthChecks = []model.CommonHealthCheck{{
+				Command:       "/usr/bin/check_something",
+				Format:        model.HealthCheckNagiosFormat,
+				Tries:         2,
+				ParsedTimeout: 10 * time.Second,
+				ParseTrySleep: time.Millisecond,
+			}}
+
+			opts := model.ExtendedExecOptions{Command: "/usr/bin/check_something", Timeout: 10 * time.Second}
+			gomock.InOrder(
+				runner.EXPECT().ExecuteWithOptions(gomock.Any(), opts).
+					Return([]byte("WARNING: something is not quite right"), []byte{}, 1, nil),
+				runner.EXPECT().ExecuteWithOptions(gomock.Any(), opts).
+					Return([]byte("OK: all good"), []byte{}, 0, nil),
+			)
+
+			result, err := b.Healthcheck(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Failed).To(BeFalse())
+			Expect(result.Errors).To(BeEmpty())
+			Expect(result.HealthChecks).To(HaveLen(1))
+			Expect(result.HealthChecks[0].Status).To(Equal(model.HealthCheckOK))
+			Expect(result.HealthChecks[0].Tries).To(Equal(2))
+		})
+
 		It("Should fail when health check command execution fails", func(ctx context.Context) {
 			props.HealthChecks = []model.CommonHealthCheck{{
 				Command: "/usr/bin/nonexistent",
 				Format:  model.HealthCheckNagiosFormat,
 			}}
 
-			runner.EXPECT().Execute(gomock.Any(), "/usr/bin/nonexistent").
+			runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/bin/nonexistent"}).
 				Return(nil, nil, 0, fmt.Errorf("command not found"))
 
 			result, err := b.Healthcheck(ctx)
@@ -268,7 +337,7 @@ var _ = Describe("Base", func() {
 				Format:  model.HealthCheckNagiosFormat,
 			}}
 
-			runner.EXPECT().Execute(gomock.Any(), "/usr/bin/check_disk").
+			runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/bin/check_disk"}).
 				Return([]byte("DISK OK - free space: 50%"), []byte{}, 0, nil)
 
 			result, err := b.Healthcheck(ctx)
@@ -333,6 +402,88 @@ var _ = Describe("Base", func() {
 			Expect(result.Errors).To(ContainElement(ContainSubstring("apply failed")))
 		})
 
+		Context("when the provider executable is missing", func() {
+			var (
+				fallback *MockProviderFallback
+				missing  = fmt.Errorf("%w: systemctl", model.ErrExecutableNotFound)
+			)
+
+			BeforeEach(func() {
+				props.HealthChecks = nil
+				fallback = NewMockProviderFallback(mockctl)
+				b.Resource = struct {
+					*MockEmbeddedResource
+					*MockProviderFallback
+				}{mockRes, fallback}
+				b.UserLogger = logger
+				logger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+				logger.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+			})
+
+			It("Should not translate errors for resources without fallback support", func(ctx context.Context) {
+				b.Resource = mockRes
+				mockRes.EXPECT().ApplyResource(gomock.Any()).Return(nil, missing)
+
+				result, err := b.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Errors).To(Equal([]string{missing.Error()}))
+			})
+
+			It("Should report a friendly error when no alternate provider exists", func(ctx context.Context) {
+				mockRes.EXPECT().ApplyResource(gomock.Any()).Return(nil, missing)
+				fallback.EXPECT().SelectAlternateProvider([]string{"mock"}).Return("", model.ErrNoSuitableProvider)
+
+				result, err := b.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Failed).To(BeTrue())
+				Expect(result.Provider).To(Equal("mock"))
+				Expect(result.Errors).To(Equal([]string{"file#/tmp/testfile: provider is not manageable: mock provider could not run: executable not found: systemctl"}))
+			})
+
+			It("Should not fall back when a provider was requested", func(ctx context.Context) {
+				b.CommonProperties.Provider = "mock"
+				mockRes.EXPECT().ApplyResource(gomock.Any()).Return(nil, missing)
+
+				result, err := b.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Failed).To(BeTrue())
+				Expect(result.Errors).To(ContainElement(ContainSubstring("provider is not manageable")))
+			})
+
+			It("Should fall back to an alternate provider", func(ctx context.Context) {
+				state := &model.FileState{
+					CommonResourceState: model.CommonResourceState{Ensure: model.EnsurePresent, Changed: true},
+					Metadata:            &model.FileMetadata{},
+				}
+
+				gomock.InOrder(
+					mockRes.EXPECT().ApplyResource(gomock.Any()).Return(nil, missing),
+					fallback.EXPECT().SelectAlternateProvider([]string{"mock"}).Return("other", nil),
+					mockRes.EXPECT().ApplyResource(gomock.Any()).Return(state, nil),
+				)
+
+				result, err := b.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Failed).To(BeFalse())
+				Expect(result.Changed).To(BeTrue())
+				Expect(result.Provider).To(Equal("other"))
+			})
+
+			It("Should not retry providers that already failed", func(ctx context.Context) {
+				gomock.InOrder(
+					mockRes.EXPECT().ApplyResource(gomock.Any()).Return(nil, missing),
+					fallback.EXPECT().SelectAlternateProvider([]string{"mock"}).Return("other", nil),
+					mockRes.EXPECT().ApplyResource(gomock.Any()).Return(nil, missing),
+					fallback.EXPECT().SelectAlternateProvider([]string{"mock", "other"}).Return("", model.ErrNoSuitableProvider),
+				)
+
+				result, err := b.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Failed).To(BeTrue())
+				Expect(result.Errors).To(ContainElement(ContainSubstring("other provider could not run")))
+			})
+		})
+
 		It("Should run health check after apply", func(ctx context.Context) {
 			props.HealthChecks = []model.CommonHealthCheck{{
 				Command: "/usr/bin/test -f /tmp/testfile",
@@ -347,7 +498,7 @@ var _ = Describe("Base", func() {
 			}
 
 			mockRes.EXPECT().ApplyResource(gomock.Any()).Return(state, nil)
-			runner.EXPECT().Execute(gomock.Any(), "/usr/bin/test", "-f", "/tmp/testfile").
+			runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/bin/test", Args: []string{"-f", "/tmp/testfile"}}).
 				Return([]byte("OK"), []byte{}, 0, nil)
 
 			result, err := b.Apply(ctx)
@@ -372,7 +523,7 @@ var _ = Describe("Base", func() {
 			}
 
 			mockRes.EXPECT().ApplyResource(gomock.Any()).Return(state, nil)
-			runner.EXPECT().Execute(gomock.Any(), "/usr/bin/test", "-f", "/tmp/testfile").
+			runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/bin/test", Args: []string{"-f", "/tmp/testfile"}}).
 				Return([]byte("CRITICAL"), []byte{}, 2, nil)
 
 			result, err := b.Apply(ctx)
@@ -402,6 +553,49 @@ var _ = Describe("Base", func() {
 			Expect(result.NoopMessage).To(Equal("Would have created the file"))
 		})
 
+		It("Should record timing for applied and noop resources", func(ctx context.Context) {
+			props.HealthChecks = nil
+
+			for _, noop := range []bool{false, true} {
+				state := &model.FileState{
+					CommonResourceState: model.CommonResourceState{Ensure: model.EnsurePresent, Changed: true, Noop: noop},
+					Metadata:            &model.FileMetadata{},
+				}
+
+				mockRes.EXPECT().ApplyResource(gomock.Any()).DoAndReturn(func(context.Context) (model.ResourceState, error) {
+					time.Sleep(5 * time.Millisecond)
+					return state, nil
+				})
+
+				before := time.Now().UTC()
+				result, err := b.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Noop).To(Equal(noop))
+				Expect(result.Duration).To(BeNumerically(">=", 5*time.Millisecond))
+				Expect(result.StartedAt).To(BeTemporally(">=", before))
+				Expect(result.CompletedAt).To(Equal(result.StartedAt.Add(result.Duration)))
+			}
+		})
+
+		It("Should set corrective from state", func(ctx context.Context) {
+			props.HealthChecks = nil
+			state := &model.FileState{
+				CommonResourceState: model.CommonResourceState{
+					Ensure:     model.EnsurePresent,
+					Changed:    true,
+					Corrective: true,
+				},
+				Metadata: &model.FileMetadata{},
+			}
+
+			mockRes.EXPECT().ApplyResource(gomock.Any()).Return(state, nil)
+
+			result, err := b.Apply(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Changed).To(BeTrue())
+			Expect(result.Corrective).To(BeTrue())
+		})
+
 		It("Should return error when SelectProvider fails", func(ctx context.Context) {
 			failMock := NewMockEmbeddedResource(mockctl)
 			failMock.EXPECT().SelectProvider().Return("", fmt.Errorf("no suitable provider"))
@@ -420,6 +614,253 @@ var _ = Describe("Base", func() {
 		})
 	})
 
+	Describe("Unmanageable resources", func() {
+		BeforeEach(func() {
+			props.HealthChecks = nil
+			mockRes.EXPECT().NewTransactionEvent().DoAndReturn(func() *model.TransactionEvent {
+				return model.NewTransactionEvent(model.FileTypeName, "/tmp/testfile", "")
+			}).AnyTimes()
+		})
+
+		It("Should fail by default when no provider matches", func(ctx context.Context) {
+			mockRes.EXPECT().SelectProvider().Return("", model.ErrNoSuitableProvider)
+
+			_, err := b.Apply(ctx)
+			Expect(err).To(MatchError(model.ErrNoSuitableProvider))
+		})
+
+		It("Should skip as not applicable when requested by the resource", func(ctx context.Context) {
+			props.Control = &model.CommonResourceControl{SkipIfUnmanageable: true}
+			mockRes.EXPECT().SelectProvider().Return("", fmt.Errorf("file#/tmp/testfile: %w", model.ErrNoSuitableProvider))
+
+			result, err := b.Apply(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Skipped).To(BeTrue())
+			Expect(result.NotApplicable).To(BeTrue())
+			Expect(result.Failed).To(BeFalse())
+			Expect(result.Errors).To(ContainElement(ContainSubstring("no suitable provider found")))
+		})
+
+		It("Should skip as not applicable when requested by the manager", func(ctx context.Context) {
+			skipMgr := modelmocks.NewMockManager(mockctl)
+			skipMgr.EXPECT().SkipIfUnmanageable().Return(true)
+			b.Manager = skipMgr
+
+			mockRes.EXPECT().SelectProvider().Return("", fmt.Errorf("%w: not applicable to instance", model.ErrProviderNotManageable))
+
+			result, err := b.Healthcheck(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.NotApplicable).To(BeTrue())
+			Expect(result.HealthCheckOnly).To(BeTrue())
+		})
+
+		It("Should not skip other provider selection errors", func(ctx context.Context) {
+			props.Control = &model.CommonResourceControl{SkipIfUnmanageable: true}
+			mockRes.EXPECT().SelectProvider().Return("", model.ErrProviderNotFound)
+
+			_, err := b.Apply(ctx)
+			Expect(err).To(MatchError(model.ErrProviderNotFound))
+		})
+
+		It("Should still fail when the selected provider fails", func(ctx context.Context) {
+			props.Control = &model.CommonResourceControl{SkipIfUnmanageable: true}
+			mockRes.EXPECT().SelectProvider().Return("mock", nil)
+			mockRes.EXPECT().ApplyResource(gomock.Any()).Return(nil, fmt.Errorf("apply failed"))
+
+			result, err := b.Apply(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Failed).To(BeTrue())
+			Expect(result.NotApplicable).To(BeFalse())
+		})
+	})
+
+	Describe("Apply conditions", func() {
+		BeforeEach(func() {
+			props.HealthChecks = nil
+			facts["role"] = "web"
+			DeferCleanup(func() { delete(facts, "role") })
+
+			mockRes.EXPECT().SelectProvider().Return("mock", nil).AnyTimes()
+			mockRes.EXPECT().NewTransactionEvent().DoAndReturn(func() *model.TransactionEvent {
+				return model.NewTransactionEvent(model.FileTypeName, "/tmp/testfile", "")
+			}).AnyTimes()
+		})
+
+		It("Should apply when the condition is met", func(ctx context.Context) {
+			props.ApplyIf = `Facts.role == "web"`
+			mockRes.EXPECT().ApplyResource(gomock.Any()).Return(&model.FileState{Metadata: &model.FileMetadata{}}, nil)
+
+			result, err := b.Apply(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Skipped).To(BeFalse())
+			Expect(result.ConditionNotMet).To(BeFalse())
+		})
+
+		It("Should skip when the condition is not met", func(ctx context.Context) {
+			props.ApplyIf = `Facts.role == "db"`
+
+			result, err := b.Apply(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Skipped).To(BeTrue())
+			Expect(result.ConditionNotMet).To(BeTrue())
+			Expect(result.Failed).To(BeFalse())
+			Expect(result.String()).To(ContainSubstring("skipped condition not met"))
+		})
+
+		It("Should use facts as they are at apply time", func(ctx context.Context) {
+			props.ApplyIf = `Facts.role == "db"`
+			facts["role"] = "db"
+			mockRes.EXPECT().ApplyResource(gomock.Any()).Return(&model.FileState{Metadata: &model.FileMetadata{}}, nil)
+
+			result, err := b.Apply(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.ConditionNotMet).To(BeFalse())
+		})
+
+		It("Should fail for invalid expressions", func(ctx context.Context) {
+			props.ApplyIf = `Facts.role ==`
+
+			_, err := b.Apply(ctx)
+			Expect(err).To(MatchError(ContainSubstring("invalid apply_if expression")))
+		})
+	})
+
+	Describe("Retries", func() {
+		var state *model.FileState
+
+		BeforeEach(func() {
+			props.HealthChecks = nil
+			props.Control = &model.CommonResourceControl{Tries: 3}
+			b.UserLogger = logger
+			logger.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+
+			mockRes.EXPECT().SelectProvider().Return("mock", nil).AnyTimes()
+			mockRes.EXPECT().NewTransactionEvent().DoAndReturn(func() *model.TransactionEvent {
+				return model.NewTransactionEvent(model.FileTypeName, "/tmp/testfile", "")
+			}).AnyTimes()
+
+			state = &model.FileState{
+				CommonResourceState: model.CommonResourceState{Ensure: model.EnsurePresent, Changed: true},
+				Metadata:            &model.FileMetadata{},
+			}
+		})
+
+		It("Should retry transient errors", func(ctx context.Context) {
+			gomock.InOrder(
+				mockRes.EXPECT().ApplyResource(gomock.Any()).Return(nil, model.TransientErrorf("lock held")),
+				mockRes.EXPECT().ApplyResource(gomock.Any()).Return(nil, fmt.Errorf("install failed: %w", model.TransientErrorf("lock held"))),
+				mockRes.EXPECT().ApplyResource(gomock.Any()).Return(state, nil),
+			)
+
+			result, err := b.Apply(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Failed).To(BeFalse())
+			Expect(result.Changed).To(BeTrue())
+		})
+
+		It("Should fail once all tries are used", func(ctx context.Context) {
+			mockRes.EXPECT().ApplyResource(gomock.Any()).Return(nil, model.TransientErrorf("lock held")).Times(3)
+
+			result, err := b.Apply(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Failed).To(BeTrue())
+			Expect(result.Errors).To(Equal([]string{"lock held"}))
+		})
+
+		It("Should not retry permanent errors", func(ctx context.Context) {
+			mockRes.EXPECT().ApplyResource(gomock.Any()).Return(nil, model.PermanentErrorf("install failed")).Times(1)
+
+			result, err := b.Apply(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Failed).To(BeTrue())
+			Expect(result.Errors).To(Equal([]string{"install failed"}))
+		})
+
+		It("Should not retry unclassified errors", func(ctx context.Context) {
+			mockRes.EXPECT().ApplyResource(gomock.Any()).Return(nil, fmt.Errorf("install failed")).Times(1)
+
+			result, err := b.Apply(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Failed).To(BeTrue())
+		})
+
+		It("Should not retry without tries", func(ctx context.Context) {
+			props.Control = nil
+			mockRes.EXPECT().ApplyResource(gomock.Any()).Return(nil, model.TransientErrorf("lock held")).Times(1)
+
+			result, err := b.Apply(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Failed).To(BeTrue())
+		})
+
+		It("Should stop retrying when the context is canceled", func(ctx context.Context) {
+			props.Control.TrySleep = "1h"
+			cctx, cancel := context.WithCancel(ctx)
+			cancel()
+
+			mockRes.EXPECT().ApplyResource(gomock.Any()).Return(nil, model.TransientErrorf("lock held")).Times(1)
+
+			result, err := b.Apply(cctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Failed).To(BeTrue())
+			Expect(result.Errors).To(ContainElement(ContainSubstring("interrupted")))
+		})
+	})
+
+	Describe("Apply timeout", func() {
+		BeforeEach(func() {
+			props.HealthChecks = nil
+			b.UserLogger = logger
+
+			mockRes.EXPECT().SelectProvider().Return("mock", nil).AnyTimes()
+			mockRes.EXPECT().NewTransactionEvent().DoAndReturn(func() *model.TransactionEvent {
+				return model.NewTransactionEvent(model.FileTypeName, "/tmp/testfile", "")
+			}).AnyTimes()
+		})
+
+		It("Should fail resources that do not complete in time", func(ctx context.Context) {
+			props.ApplyTimeout = "50ms"
+
+			mockRes.EXPECT().ApplyResource(gomock.Any()).DoAndReturn(func(ctx context.Context) (model.ResourceState, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			})
+
+			result, err := b.Apply(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Failed).To(BeTrue())
+			Expect(result.Errors).To(Equal([]string{"timed out after 50ms: context deadline exceeded"}))
+		})
+
+		It("Should not limit resources without a timeout", func(ctx context.Context) {
+			mockRes.EXPECT().ApplyResource(gomock.Any()).DoAndReturn(func(ctx context.Context) (model.ResourceState, error) {
+				_, hasDeadline := ctx.Deadline()
+				Expect(hasDeadline).To(BeFalse())
+
+				return &model.FileState{CommonResourceState: model.CommonResourceState{Ensure: model.EnsurePresent}}, nil
+			})
+
+			result, err := b.Apply(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Failed).To(BeFalse())
+		})
+
+		It("Should not report a timeout when the run is canceled", func(ctx context.Context) {
+			props.ApplyTimeout = "1h"
+			cctx, cancel := context.WithCancel(ctx)
+			cancel()
+
+			mockRes.EXPECT().ApplyResource(gomock.Any()).DoAndReturn(func(ctx context.Context) (model.ResourceState, error) {
+				return nil, ctx.Err()
+			})
+
+			result, err := b.Apply(cctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Failed).To(BeTrue())
+			Expect(result.Errors).To(Equal([]string{"context canceled"}))
+		})
+	})
+
 	Describe("FinalizeState", func() {
 		It("Should set all state fields correctly", func() {
 			state := &model.FileState{
@@ -456,6 +897,37 @@ var _ = Describe("Base", func() {
 		})
 	})
 
+	Describe("ClassifyChange", func() {
+		state := func(changed bool, refreshed bool) *model.FileState {
+			return &model.FileState{
+				CommonResourceState: model.CommonResourceState{Changed: changed, Refreshed: refreshed},
+				Metadata:            &model.FileMetadata{},
+			}
+		}
+
+		It("Should mark changes to existing resources as corrective", func() {
+			s := state(true, false)
+			b.ClassifyChange(s, true)
+			Expect(s.Corrective).To(BeTrue())
+		})
+
+		It("Should treat creating a resource as intentional", func() {
+			s := state(true, false)
+			b.ClassifyChange(s, false)
+			Expect(s.Corrective).To(BeFalse())
+		})
+
+		It("Should not mark unchanged or refreshed resources as corrective", func() {
+			s := state(false, false)
+			b.ClassifyChange(s, true)
+			Expect(s.Corrective).To(BeFalse())
+
+			s = state(true, true)
+			b.ClassifyChange(s, true)
+			Expect(s.Corrective).To(BeFalse())
+		})
+	})
+
 	Describe("Require", func() {
 		BeforeEach(func() {
 			mockRes.EXPECT().SelectProvider().Return("mock", nil).AnyTimes()
@@ -574,7 +1046,7 @@ var _ = Describe("Base", func() {
 			b.CommonProperties.Require = []string{"package#nginx"}
 
 			mgr.EXPECT().IsResourceFailed("package", "nginx").Return(false, nil)
-			runner.EXPECT().Execute(gomock.Any(), "/usr/bin/test", "-f", "/tmp/testfile").
+			runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/bin/test", Args: []string{"-f", "/tmp/testfile"}}).
 				Return([]byte("OK"), []byte{}, 0, nil)
 
 			result, err := b.Healthcheck(ctx)
@@ -892,7 +1364,7 @@ var _ = Describe("Base", func() {
 				ManageIf: "true",
 			}
 
-			runner.EXPECT().Execute(gomock.Any(), "/usr/bin/test", "-f", "/tmp/testfile").
+			runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/bin/test", Args: []string{"-f", "/tmp/testfile"}}).
 				Return([]byte("OK"), []byte{}, 0, nil)
 
 			result, err := b.Healthcheck(ctx)
@@ -929,6 +1401,18 @@ var _ = Describe("Base", func() {
 			Expect(resource).To(BeEmpty())
 		})
 
+		It("Should return a refresh pending from a previous run", func() {
+			pending := modelmocks.NewMockManager(mockctl)
+			pending.EXPECT().ShouldRefresh("package", "nginx").Return(false, nil)
+			pending.EXPECT().PendingRefresh(model.FileTypeName, "/tmp/testfile").Return("package#nginx", nil)
+			b.Manager = pending
+
+			should, resource, err := b.ShouldRefresh([]string{"package#nginx"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(should).To(BeTrue())
+			Expect(resource).To(Equal("package#nginx"))
+		})
+
 		It("Should return first changed resource when multiple have changed", func() {
 			mgr.EXPECT().ShouldRefresh("package", "nginx").Return(false, nil)
 			mgr.EXPECT().ShouldRefresh(model.FileTypeName, "/etc/nginx.conf").Return(true, nil)
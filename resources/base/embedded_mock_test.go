@@ -100,3 +100,42 @@ func (mr *MockEmbeddedResourceMockRecorder) Type() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Type", reflect.TypeOf((*MockEmbeddedResource)(nil).Type))
 }
+
+// MockProviderFallback is a mock of ProviderFallback interface.
+type MockProviderFallback struct {
+	ctrl     *gomock.Controller
+	recorder *MockProviderFallbackMockRecorder
+	isgomock struct{}
+}
+
+// MockProviderFallbackMockRecorder is the mock recorder for MockProviderFallback.
+type MockProviderFallbackMockRecorder struct {
+	mock *MockProviderFallback
+}
+
+// NewMockProviderFallback creates a new mock instance.
+func NewMockProviderFallback(ctrl *gomock.Controller) *MockProviderFallback {
+	mock := &MockProviderFallback{ctrl: ctrl}
+	mock.recorder = &MockProviderFallbackMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProviderFallback) EXPECT() *MockProviderFallbackMockRecorder {
+	return m.recorder
+}
+
+// SelectAlternateProvider mocks base method.
+func (m *MockProviderFallback) SelectAlternateProvider(exclude []string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectAlternateProvider", exclude)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectAlternateProvider indicates an expected call of SelectAlternateProvider.
+func (mr *MockProviderFallbackMockRecorder) SelectAlternateProvider(exclude any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectAlternateProvider", reflect.TypeOf((*MockProviderFallback)(nil).SelectAlternateProvider), exclude)
+}
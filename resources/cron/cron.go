@@ -0,0 +1,24 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cronresource
+
+import (
+	"context"
+
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/resources/cron/crond"
+)
+
+func init() {
+	crond.Register()
+}
+
+type CronProvider interface {
+	model.Provider
+
+	Set(ctx context.Context, properties *model.CronResourceProperties) error
+	Remove(ctx context.Context, properties *model.CronResourceProperties) error
+	Status(ctx context.Context, properties *model.CronResourceProperties) (*model.CronState, error)
+}
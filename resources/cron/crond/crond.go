@@ -0,0 +1,129 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package crond
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	iu "github.com/choria-io/ccm/internal/util"
+	"github.com/choria-io/ccm/model"
+)
+
+const (
+	ProviderName = "crond"
+
+	// DefaultDirectory is where cron reads system jobs from
+	DefaultDirectory = "/etc/cron.d"
+)
+
+type Provider struct {
+	log model.Logger
+	dir string
+}
+
+// NewCronDProvider creates a provider managing jobs as files in dir
+func NewCronDProvider(log model.Logger, dir string) (*Provider, error) {
+	return &Provider{log: log, dir: dir}, nil
+}
+
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+func (p *Provider) path(properties *model.CronResourceProperties) string {
+	return filepath.Join(p.dir, properties.FileName())
+}
+
+// Status reads the file holding the job and reports its content
+func (p *Provider) Status(ctx context.Context, properties *model.CronResourceProperties) (*model.CronState, error) {
+	file := p.path(properties)
+
+	state := &model.CronState{
+		CommonResourceState: model.NewCommonResourceState(model.ResourceStatusCronProtocol, model.CronTypeName, properties.Name, model.EnsureAbsent),
+		Metadata: &model.CronMetadata{
+			Name:     properties.Name,
+			File:     file,
+			Provider: ProviderName,
+		},
+	}
+
+	raw, err := os.ReadFile(file)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return state, nil
+	case err != nil:
+		return nil, err
+	}
+
+	state.Ensure = model.EnsurePresent
+	state.Metadata.Content = string(raw)
+	state.Metadata.Checksum, err = iu.Sha256HashBytes(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// Set writes the file holding the job, the file is replaced atomically so cron never reads a partial job
+func (p *Provider) Set(ctx context.Context, properties *model.CronResourceProperties) error {
+	file := p.path(properties)
+
+	// cron ignores files with dots in their names so the temporary file is never loaded
+	tf, err := os.CreateTemp(p.dir, fmt.Sprintf(".%s.*", filepath.Base(file)))
+	if err != nil {
+		return err
+	}
+	defer tf.Close()
+	defer os.Remove(tf.Name())
+
+	_, err = tf.Write(properties.FileContent())
+	if err != nil {
+		return err
+	}
+
+	// cron refuses files that are writable by group or other
+	err = tf.Chmod(0644)
+	if err != nil {
+		return fmt.Errorf("could not set mode on temporary file: %w", err)
+	}
+
+	err = tf.Sync()
+	if err != nil {
+		return fmt.Errorf("could not sync temporary file: %w", err)
+	}
+
+	err = tf.Close()
+	if err != nil {
+		return fmt.Errorf("could not close temporary file: %w", err)
+	}
+
+	err = os.Rename(tf.Name(), file)
+	if err != nil {
+		return fmt.Errorf("could not rename temporary file: %w", err)
+	}
+
+	p.log.Debug("Wrote cron file", "file", file)
+
+	return nil
+}
+
+// Remove deletes the file holding the job, a missing file is not an error
+func (p *Provider) Remove(ctx context.Context, properties *model.CronResourceProperties) error {
+	file := p.path(properties)
+
+	err := os.Remove(file)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	p.log.Debug("Removed cron file", "file", file)
+
+	return nil
+}
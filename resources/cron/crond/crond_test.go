@@ -0,0 +1,120 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package crond
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/model/modelmocks"
+)
+
+func TestCronDProvider(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Resources/Cron/CronD")
+}
+
+var _ = Describe("CronD Provider", func() {
+	var (
+		mockctl  *gomock.Controller
+		logger   *modelmocks.MockLogger
+		provider *Provider
+		tmpDir   string
+		err      error
+	)
+
+	BeforeEach(func() {
+		mockctl = gomock.NewController(GinkgoT())
+		logger = modelmocks.NewMockLogger(mockctl)
+		logger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+
+		tmpDir = GinkgoT().TempDir()
+
+		provider, err = NewCronDProvider(logger, tmpDir)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	props := func(name string) *model.CronResourceProperties {
+		return &model.CronResourceProperties{
+			CommonResourceProperties: model.CommonResourceProperties{Name: name, Ensure: model.EnsurePresent},
+			Command:                  "/usr/local/bin/backup --full",
+			User:                     "backup",
+			Minute:                   "30",
+			Hour:                     "2",
+			Environment:              []string{"MAILTO=ops@example.net"},
+		}
+	}
+
+	Describe("Status", func() {
+		It("Should handle missing files", func(ctx context.Context) {
+			state, err := provider.Status(ctx, props("backup"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Ensure).To(Equal(model.EnsureAbsent))
+			Expect(state.Metadata.File).To(Equal(filepath.Join(tmpDir, "backup")))
+			Expect(state.Metadata.Content).To(BeEmpty())
+		})
+
+		It("Should report the file content", func(ctx context.Context) {
+			Expect(os.WriteFile(filepath.Join(tmpDir, "backup"), []byte("* * * * * root /bin/true\n"), 0644)).To(Succeed())
+
+			state, err := provider.Status(ctx, props("backup"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Ensure).To(Equal(model.EnsurePresent))
+			Expect(state.Metadata.Content).To(Equal("* * * * * root /bin/true\n"))
+			Expect(state.Metadata.Checksum).ToNot(BeEmpty())
+		})
+	})
+
+	Describe("Set", func() {
+		It("Should write the job to a file derived from the name", func(ctx context.Context) {
+			Expect(provider.Set(ctx, props("nightly backup.db"))).To(Succeed())
+
+			file := filepath.Join(tmpDir, "nightly_backup_db")
+			raw, err := os.ReadFile(file)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(raw)).To(Equal(model.CronFileHeader + "\nMAILTO=ops@example.net\n30 2 * * * backup /usr/local/bin/backup --full\n"))
+
+			stat, err := os.Stat(file)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stat.Mode().Perm()).To(Equal(os.FileMode(0644)))
+
+			entries, err := os.ReadDir(tmpDir)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+		})
+
+		It("Should replace an existing file", func(ctx context.Context) {
+			file := filepath.Join(tmpDir, "backup")
+			Expect(os.WriteFile(file, []byte("old\n"), 0600)).To(Succeed())
+
+			Expect(provider.Set(ctx, props("backup"))).To(Succeed())
+
+			state, err := provider.Status(ctx, props("backup"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Metadata.Content).To(Equal(string(props("backup").FileContent())))
+		})
+	})
+
+	Describe("Remove", func() {
+		It("Should remove the file", func(ctx context.Context) {
+			file := filepath.Join(tmpDir, "backup")
+			Expect(os.WriteFile(file, []byte("old\n"), 0644)).To(Succeed())
+
+			Expect(provider.Remove(ctx, props("backup"))).To(Succeed())
+			Expect(file).ToNot(BeAnExistingFile())
+		})
+
+		It("Should ignore missing files", func(ctx context.Context) {
+			Expect(provider.Remove(ctx, props("backup"))).To(Succeed())
+		})
+	})
+})
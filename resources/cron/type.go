@@ -0,0 +1,242 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cronresource
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/choria-io/ccm/internal/registry"
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/resources/base"
+	"github.com/choria-io/ccm/resources/cron/crond"
+)
+
+var _ base.StatusReporter = (*Type)(nil)
+
+type Type struct {
+	*base.Base
+
+	prop     *model.CronResourceProperties
+	mgr      model.Manager
+	log      model.Logger
+	provider model.Provider
+
+	mu sync.Mutex
+}
+
+var _ model.Resource = (*Type)(nil)
+var _ CronProvider = (*crond.Provider)(nil)
+
+// New creates a new cron resource with the given properties
+func New(ctx context.Context, mgr model.Manager, properties model.CronResourceProperties) (*Type, error) {
+	env, err := mgr.TemplateEnvironment(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = properties.ResolveTemplates(env)
+	if err != nil {
+		return nil, err
+	}
+
+	loggerArgs := []any{"type", model.CronTypeName, "name", properties.Name}
+	logger, err := mgr.Logger(loggerArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	properties.CommonResourceProperties.Type = model.CronTypeName
+
+	t := &Type{
+		prop: &properties,
+		mgr:  mgr,
+		log:  logger,
+	}
+	t.Base = &base.Base{
+		Resource:           t,
+		ResourceProperties: &properties,
+		CommonProperties:   properties.CommonResourceProperties,
+		Log:                logger,
+		UserLogger:         mgr.UserLogger().With(loggerArgs...),
+		Manager:            mgr,
+		Facts:              env.Facts,
+		Data:               env.Data,
+	}
+
+	err = t.Base.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %w", t.String(), model.ErrResourceInvalid, err)
+	}
+
+	t.log.Debug("Created resource instance")
+
+	return t, nil
+}
+
+func (t *Type) ApplyResource(ctx context.Context) (model.ResourceState, error) {
+	var (
+		initialStatus *model.CronState
+		finalStatus   *model.CronState
+		p             = t.provider.(CronProvider)
+		properties    = t.prop
+		noop          = t.mgr.NoopMode()
+		noopMessage   string
+		err           error
+	)
+
+	initialStatus, err = p.Status(ctx, properties)
+	if err != nil {
+		return nil, err
+	}
+
+	isStable, _, err := t.isDesiredState(properties, initialStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	if isStable {
+		t.FinalizeState(initialStatus, noop, "", false, true, false)
+		return initialStatus, nil
+	}
+
+	switch properties.Ensure {
+	case model.EnsureAbsent:
+		if !noop {
+			t.log.Info("Removing job")
+			err = p.Remove(ctx, properties)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			t.log.Info("Skipping remove as noop")
+			noopMessage = "Would have removed the job"
+		}
+
+	default:
+		if !noop {
+			t.log.Info("Writing job")
+			err = p.Set(ctx, properties)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			t.log.Info("Skipping write as noop")
+			noopMessage = "Would have written the job"
+		}
+	}
+
+	finalStatus = initialStatus
+	if !noop {
+		finalStatus, err = p.Status(ctx, properties)
+		if err != nil {
+			return nil, err
+		}
+
+		var reason string
+		isStable, reason, err = t.isDesiredState(properties, finalStatus)
+		if err != nil {
+			return nil, err
+		}
+		if !isStable {
+			return nil, fmt.Errorf("%w: %s: %s", model.ErrDesiredStateFailed, properties.Ensure, reason)
+		}
+	}
+
+	t.FinalizeState(finalStatus, noop, noopMessage, true, isStable, false)
+	t.ClassifyChange(finalStatus, initialStatus.Ensure != model.EnsureAbsent)
+
+	return finalStatus, nil
+}
+
+// isDesiredState reports whether state matches properties by comparing the content
+// of the job file with the rendered job. The second return is a human-readable reason
+// describing the mismatch when stable is false, suitable for inclusion in error messages.
+func (t *Type) isDesiredState(properties *model.CronResourceProperties, state *model.CronState) (bool, string, error) {
+	if properties.Ensure == model.EnsureAbsent {
+		if state.Ensure == model.EnsureAbsent {
+			return true, "", nil
+		}
+		return false, fmt.Sprintf("%s still exists", state.Metadata.File), nil
+	}
+
+	if state.Ensure != model.EnsurePresent {
+		return false, fmt.Sprintf("%s does not exist", state.Metadata.File), nil
+	}
+
+	if state.Metadata.Content != string(properties.FileContent()) {
+		t.log.Debug("Job file content does not match", "file", state.Metadata.File)
+		return false, fmt.Sprintf("content mismatch in %s", state.Metadata.File), nil
+	}
+
+	return true, "", nil
+}
+
+func (t *Type) Info(ctx context.Context) (any, error) {
+	_, err := t.SelectProvider()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", t.String(), err)
+	}
+
+	return t.provider.(CronProvider).Status(ctx, t.prop)
+}
+
+// CurrentState reports the current state of the resource without making any changes
+func (t *Type) CurrentState(ctx context.Context) (model.ResourceState, error) {
+	state, err := t.provider.(CronProvider).Status(ctx, t.prop)
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+func (t *Type) providerUnlocked() string {
+	if t.provider == nil {
+		return ""
+	}
+
+	return t.provider.Name()
+}
+
+func (t *Type) Provider() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.providerUnlocked()
+}
+
+func (t *Type) selectProviderUnlocked() error {
+	if t.provider != nil {
+		return nil
+	}
+
+	selected, err := registry.FindSuitableProvider(model.CronTypeName, t.prop.Provider, t.Facts, t.prop, t.log, nil, t.mgr)
+	if err != nil {
+		return err
+	}
+
+	if selected == nil {
+		return model.ErrNoSuitableProvider
+	}
+
+	t.log.Debug("Selected provider", "provider", selected.Name())
+	t.provider = selected
+
+	return nil
+}
+
+func (t *Type) SelectProvider() (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	err := t.selectProviderUnlocked()
+	if err != nil {
+		return "", err
+	}
+
+	return t.providerUnlocked(), nil
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cronresource
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/choria-io/ccm/internal/registry"
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/model/modelmocks"
+)
+
+func TestCronResource(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Resources/Cron")
+}
+
+var _ = Describe("Cron Type", func() {
+	var (
+		facts    = make(map[string]any)
+		data     = make(map[string]any)
+		mgr      *modelmocks.MockManager
+		logger   *modelmocks.MockLogger
+		mockctl  *gomock.Controller
+		provider *MockCronProvider
+	)
+
+	BeforeEach(func() {
+		mockctl = gomock.NewController(GinkgoT())
+		mgr, logger = modelmocks.NewManager(facts, data, false, mockctl)
+		provider = NewMockCronProvider(mockctl)
+
+		provider.EXPECT().Name().Return("mock").AnyTimes()
+		logger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+		logger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+	})
+
+	Describe("New", func() {
+		It("Should validate properties", func(ctx context.Context) {
+			_, err := New(ctx, mgr, model.CronResourceProperties{})
+			Expect(err).To(MatchError(model.ErrResourceNameRequired))
+
+			_, err = New(ctx, mgr, model.CronResourceProperties{
+				CommonResourceProperties: model.CommonResourceProperties{Name: "backup", Ensure: model.EnsurePresent},
+			})
+			Expect(err).To(MatchError(ContainSubstring("command is required")))
+		})
+	})
+
+	Context("with a prepared provider", func() {
+		var factory *modelmocks.MockProviderFactory
+		var res *Type
+		var err error
+
+		BeforeEach(func(ctx context.Context) {
+			factory = modelmocks.NewMockProviderFactory(mockctl)
+			factory.EXPECT().Name().Return("test").AnyTimes()
+			factory.EXPECT().TypeName().Return(model.CronTypeName).AnyTimes()
+			factory.EXPECT().New(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(log model.Logger, runner model.CommandRunner) (model.Provider, error) {
+				return provider, nil
+			})
+			factory.EXPECT().IsManageable(facts, gomock.Any()).Return(true, 1, nil).AnyTimes()
+
+			res, err = New(ctx, mgr, model.CronResourceProperties{
+				CommonResourceProperties: model.CommonResourceProperties{
+					Name:     "backup",
+					Ensure:   model.EnsurePresent,
+					Provider: "test",
+				},
+				Command: "/usr/local/bin/backup",
+				Minute:  "30",
+				Hour:    "2",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			registry.Clear()
+			registry.MustRegister(factory)
+		})
+
+		state := func(content string) *model.CronState {
+			s := &model.CronState{
+				CommonResourceState: model.CommonResourceState{Ensure: model.EnsureAbsent},
+				Metadata:            &model.CronMetadata{Name: "backup", File: "/etc/cron.d/backup"},
+			}
+
+			if content != "" {
+				s.Ensure = model.EnsurePresent
+				s.Metadata.Content = content
+			}
+
+			return s
+		}
+
+		Describe("Apply", func() {
+			It("Should fail if initial status check fails", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("status failed"))
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Errors).To(ContainElement(ContainSubstring("status failed")))
+			})
+
+			It("Should write the job when the content differs", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state("0 * * * * root /bin/true\n"), nil)
+				provider.EXPECT().Set(gomock.Any(), res.prop).Return(nil)
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(string(res.prop.FileContent())), nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Errors).To(BeEmpty())
+				Expect(event.Changed).To(BeTrue())
+			})
+
+			It("Should not change when the content matches", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(string(res.prop.FileContent())), nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Changed).To(BeFalse())
+			})
+
+			It("Should fail when the job is not written after applying", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(""), nil).Times(2)
+				provider.EXPECT().Set(gomock.Any(), res.prop).Return(nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Errors).To(ContainElement(ContainSubstring("/etc/cron.d/backup does not exist")))
+			})
+
+			It("Should remove the job when absent", func(ctx context.Context) {
+				res.prop.Ensure = model.EnsureAbsent
+
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(string(res.prop.FileContent())), nil)
+				provider.EXPECT().Remove(gomock.Any(), res.prop).Return(nil)
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(""), nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Errors).To(BeEmpty())
+				Expect(event.Changed).To(BeTrue())
+			})
+		})
+
+		Describe("Apply in noop mode", func() {
+			It("Should not write the job", func(ctx context.Context) {
+				noopMgr, _ := modelmocks.NewManager(facts, data, true, mockctl)
+				noopRes, err := New(ctx, noopMgr, *res.prop)
+				Expect(err).ToNot(HaveOccurred())
+
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(""), nil)
+
+				event, err := noopRes.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Changed).To(BeTrue())
+				Expect(event.Noop).To(BeTrue())
+				Expect(event.NoopMessage).To(Equal("Would have written the job"))
+			})
+		})
+	})
+})
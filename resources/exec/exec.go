@@ -20,7 +20,7 @@ func init() {
 type ExecProvider interface {
 	model.Provider
 
-	Execute(ctx context.Context, properties *model.ExecResourceProperties, log model.Logger) (int, error)
+	Execute(ctx context.Context, properties *model.ExecResourceProperties, log model.Logger) (stdout []byte, exitCode int, err error)
 	EvaluateGuard(ctx context.Context, command string, properties *model.ExecResourceProperties) (bool, error)
 	Status(ctx context.Context, properties *model.ExecResourceProperties) (*model.ExecState, error)
 }
@@ -27,7 +27,7 @@ func NewPosixProvider(log model.Logger, runner model.CommandRunner) (*Provider,
 	return &Provider{log: log, runner: runner}, nil
 }
 
-func (p *Provider) Execute(ctx context.Context, properties *model.ExecResourceProperties, log model.Logger) (int, error) {
+func (p *Provider) Execute(ctx context.Context, properties *model.ExecResourceProperties, log model.Logger) ([]byte, int, error) {
 	cmd := properties.Name
 	if properties.Command != "" {
 		cmd = properties.Command
@@ -35,7 +35,7 @@ func (p *Provider) Execute(ctx context.Context, properties *model.ExecResourcePr
 
 	words, err := shellquote.Split(cmd)
 	if err != nil {
-		return -1, err
+		return nil, -1, err
 	}
 
 	var command string
@@ -43,7 +43,7 @@ func (p *Provider) Execute(ctx context.Context, properties *model.ExecResourcePr
 
 	switch len(words) {
 	case 0:
-		return -1, fmt.Errorf("no command specified")
+		return nil, -1, fmt.Errorf("no command specified")
 	case 1:
 		command = words[0]
 	default:
@@ -52,16 +52,17 @@ func (p *Provider) Execute(ctx context.Context, properties *model.ExecResourcePr
 	}
 
 	if p.runner == nil {
-		return -1, fmt.Errorf("no command runner configured")
+		return nil, -1, fmt.Errorf("no command runner configured")
 	}
 
 	stdout, _, exitCode, err := p.runner.ExecuteWithOptions(ctx, model.ExtendedExecOptions{
 		Command:     command,
 		Args:        args,
 		Cwd:         properties.Cwd,
-		Environment: properties.Environment,
+		Environment: properties.CommandEnvironment(),
 		Path:        properties.Path,
 		Timeout:     properties.ParsedTimeout,
+		User:        properties.User,
 	})
 
 	p.log.Info("Command finished", "command", command, "exitcode", exitCode)
@@ -73,7 +74,7 @@ func (p *Provider) Execute(ctx context.Context, properties *model.ExecResourcePr
 		}
 	}
 
-	return exitCode, err
+	return stdout, exitCode, err
 }
 
 func (p *Provider) EvaluateGuard(ctx context.Context, command string, properties *model.ExecResourceProperties) (bool, error) {
@@ -105,9 +106,10 @@ func (p *Provider) EvaluateGuard(ctx context.Context, command string, properties
 		Command:     cmd,
 		Args:        args,
 		Cwd:         properties.Cwd,
-		Environment: properties.Environment,
+		Environment: properties.CommandEnvironment(),
 		Path:        properties.Path,
 		Timeout:     properties.ParsedTimeout,
+		User:        properties.User,
 	})
 	if err != nil {
 		return false, err
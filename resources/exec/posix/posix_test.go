@@ -87,7 +87,7 @@ var _ = Describe("Posix Provider", func() {
 				Args:    []string{"hello"},
 			}).Return([]byte("hello\n"), []byte{}, 0, nil)
 
-			exitCode, err := provider.Execute(context.Background(), properties, nil)
+			_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(exitCode).To(Equal(0))
 		})
@@ -104,7 +104,7 @@ var _ = Describe("Posix Provider", func() {
 				Args:    nil,
 			}).Return([]byte("/tmp\n"), []byte{}, 0, nil)
 
-			exitCode, err := provider.Execute(context.Background(), properties, nil)
+			_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(exitCode).To(Equal(0))
 		})
@@ -123,7 +123,7 @@ var _ = Describe("Posix Provider", func() {
 				Cwd:     "/tmp",
 			}).Return([]byte("/tmp\n"), []byte{}, 0, nil)
 
-			exitCode, err := provider.Execute(context.Background(), properties, nil)
+			_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(exitCode).To(Equal(0))
 		})
@@ -142,7 +142,30 @@ var _ = Describe("Posix Provider", func() {
 				Environment: []string{"FOO=bar", "BAZ=qux"},
 			}).Return([]byte("FOO=bar\nBAZ=qux\n"), []byte{}, 0, nil)
 
-			exitCode, err := provider.Execute(context.Background(), properties, nil)
+			_, exitCode, err := provider.Execute(context.Background(), properties, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(exitCode).To(Equal(0))
+		})
+
+		It("Should merge the inherited environment and pass User to the runner", func() {
+			properties := &model.ExecResourceProperties{
+				CommonResourceProperties: model.CommonResourceProperties{
+					Name: "/bin/env",
+				},
+				Cwd:                  "/srv/app",
+				User:                 "app",
+				Environment:          []string{"FOO=resource"},
+				InheritedEnvironment: map[string]string{"FOO": "manager", "BAR": "baz"},
+			}
+
+			runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{
+				Command:     "/bin/env",
+				Cwd:         "/srv/app",
+				Environment: []string{"BAR=baz", "FOO=resource"},
+				User:        "app",
+			}).Return([]byte{}, []byte{}, 0, nil)
+
+			_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(exitCode).To(Equal(0))
 		})
@@ -161,7 +184,7 @@ var _ = Describe("Posix Provider", func() {
 				Path:    "/usr/local/bin:/usr/bin:/bin",
 			}).Return([]byte("hello\n"), []byte{}, 0, nil)
 
-			exitCode, err := provider.Execute(context.Background(), properties, nil)
+			_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(exitCode).To(Equal(0))
 		})
@@ -180,7 +203,7 @@ var _ = Describe("Posix Provider", func() {
 				Timeout: 30 * time.Second,
 			}).Return([]byte{}, []byte{}, 0, nil)
 
-			exitCode, err := provider.Execute(context.Background(), properties, nil)
+			_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(exitCode).To(Equal(0))
 		})
@@ -197,7 +220,7 @@ var _ = Describe("Posix Provider", func() {
 				Args:    nil,
 			}).Return([]byte{}, []byte{}, 1, nil)
 
-			exitCode, err := provider.Execute(context.Background(), properties, nil)
+			_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(exitCode).To(Equal(1))
 		})
@@ -212,7 +235,7 @@ var _ = Describe("Posix Provider", func() {
 			expectedErr := errors.New("command not found")
 			runner.EXPECT().ExecuteWithOptions(gomock.Any(), gomock.Any()).Return(nil, nil, -1, expectedErr)
 
-			exitCode, err := provider.Execute(context.Background(), properties, nil)
+			_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 			Expect(err).To(HaveOccurred())
 			Expect(err).To(Equal(expectedErr))
 			Expect(exitCode).To(Equal(-1))
@@ -225,7 +248,7 @@ var _ = Describe("Posix Provider", func() {
 				},
 			}
 
-			exitCode, err := provider.Execute(context.Background(), properties, nil)
+			_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("Unterminated"))
 			Expect(exitCode).To(Equal(-1))
@@ -238,7 +261,7 @@ var _ = Describe("Posix Provider", func() {
 				},
 			}
 
-			exitCode, err := provider.Execute(context.Background(), properties, nil)
+			_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("no command specified"))
 			Expect(exitCode).To(Equal(-1))
@@ -254,7 +277,7 @@ var _ = Describe("Posix Provider", func() {
 				},
 			}
 
-			exitCode, err := provider.Execute(context.Background(), properties, nil)
+			_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("no command runner configured"))
 			Expect(exitCode).To(Equal(-1))
@@ -272,7 +295,7 @@ var _ = Describe("Posix Provider", func() {
 				Args:    []string{"hello world"},
 			}).Return([]byte("hello world\n"), []byte{}, 0, nil)
 
-			exitCode, err := provider.Execute(context.Background(), properties, nil)
+			_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(exitCode).To(Equal(0))
 		})
@@ -289,7 +312,7 @@ var _ = Describe("Posix Provider", func() {
 				Args:    []string{"/var", "-name", "*.log", "-type", "f"},
 			}).Return([]byte{}, []byte{}, 0, nil)
 
-			exitCode, err := provider.Execute(context.Background(), properties, nil)
+			_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(exitCode).To(Equal(0))
 		})
@@ -314,7 +337,7 @@ var _ = Describe("Posix Provider", func() {
 				Timeout:     60 * time.Second,
 			}).Return([]byte{}, []byte{}, 0, nil)
 
-			exitCode, err := provider.Execute(context.Background(), properties, nil)
+			_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(exitCode).To(Equal(0))
 		})
@@ -333,7 +356,7 @@ var _ = Describe("Posix Provider", func() {
 					Args:    []string{"hello"},
 				}).Return([]byte("hello\n"), []byte{}, 0, nil)
 
-				exitCode, err := provider.Execute(context.Background(), properties, nil)
+				_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(exitCode).To(Equal(0))
 			})
@@ -351,7 +374,7 @@ var _ = Describe("Posix Provider", func() {
 					Args:    []string{"from-name"},
 				}).Return([]byte("from-name\n"), []byte{}, 0, nil)
 
-				exitCode, err := provider.Execute(context.Background(), properties, nil)
+				_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(exitCode).To(Equal(0))
 			})
@@ -369,7 +392,7 @@ var _ = Describe("Posix Provider", func() {
 					Args:    []string{"-fsSL", "https://example.com/file.tar.gz", "-o", "/tmp/file.tar.gz"},
 				}).Return([]byte{}, []byte{}, 0, nil)
 
-				exitCode, err := provider.Execute(context.Background(), properties, nil)
+				_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(exitCode).To(Equal(0))
 			})
@@ -387,7 +410,7 @@ var _ = Describe("Posix Provider", func() {
 					Args:    nil,
 				}).Return([]byte{}, []byte{}, 0, nil)
 
-				exitCode, err := provider.Execute(context.Background(), properties, nil)
+				_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(exitCode).To(Equal(0))
 			})
@@ -400,7 +423,7 @@ var _ = Describe("Posix Provider", func() {
 					Command: "/bin/echo 'unterminated",
 				}
 
-				exitCode, err := provider.Execute(context.Background(), properties, nil)
+				_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("Unterminated"))
 				Expect(exitCode).To(Equal(-1))
@@ -427,7 +450,7 @@ var _ = Describe("Posix Provider", func() {
 					Timeout:     120 * time.Second,
 				}).Return([]byte{}, []byte{}, 0, nil)
 
-				exitCode, err := provider.Execute(context.Background(), properties, nil)
+				_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(exitCode).To(Equal(0))
 			})
@@ -450,7 +473,7 @@ var _ = Describe("Posix Provider", func() {
 					Args:    []string{"hello", "world"},
 				}).Return([]byte("hello world\n"), []byte{}, 0, nil)
 
-				exitCode, err := provider.Execute(context.Background(), properties, userLogger)
+				_, exitCode, err := provider.Execute(context.Background(), properties, userLogger)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(exitCode).To(Equal(0))
 			})
@@ -472,7 +495,7 @@ var _ = Describe("Posix Provider", func() {
 
 				runner.EXPECT().ExecuteWithOptions(gomock.Any(), gomock.Any()).Return([]byte("line one\nline two\nline three\n"), []byte{}, 0, nil)
 
-				exitCode, err := provider.Execute(context.Background(), properties, userLogger)
+				_, exitCode, err := provider.Execute(context.Background(), properties, userLogger)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(exitCode).To(Equal(0))
 			})
@@ -493,7 +516,7 @@ var _ = Describe("Posix Provider", func() {
 					Args:    []string{"hello"},
 				}).Return([]byte("hello\n"), []byte{}, 0, nil)
 
-				exitCode, err := provider.Execute(context.Background(), properties, userLogger)
+				_, exitCode, err := provider.Execute(context.Background(), properties, userLogger)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(exitCode).To(Equal(0))
 			})
@@ -511,7 +534,7 @@ var _ = Describe("Posix Provider", func() {
 					Args:    []string{"hello"},
 				}).Return([]byte("hello\n"), []byte{}, 0, nil)
 
-				exitCode, err := provider.Execute(context.Background(), properties, nil)
+				_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(exitCode).To(Equal(0))
 			})
@@ -533,7 +556,7 @@ var _ = Describe("Posix Provider", func() {
 
 				runner.EXPECT().ExecuteWithOptions(gomock.Any(), gomock.Any()).Return([]byte("first\n\nsecond\n"), []byte{}, 0, nil)
 
-				exitCode, err := provider.Execute(context.Background(), properties, userLogger)
+				_, exitCode, err := provider.Execute(context.Background(), properties, userLogger)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(exitCode).To(Equal(0))
 			})
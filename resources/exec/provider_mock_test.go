@@ -59,12 +59,13 @@ func (mr *MockExecProviderMockRecorder) EvaluateGuard(ctx, command, properties a
 }
 
 // Execute mocks base method.
-func (m *MockExecProvider) Execute(ctx context.Context, properties *model.ExecResourceProperties, log model.Logger) (int, error) {
+func (m *MockExecProvider) Execute(ctx context.Context, properties *model.ExecResourceProperties, log model.Logger) ([]byte, int, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Execute", ctx, properties, log)
-	ret0, _ := ret[0].(int)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // Execute indicates an expected call of Execute.
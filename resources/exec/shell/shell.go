@@ -27,9 +27,9 @@ func NewShellProvider(log model.Logger, runner model.CommandRunner) (*Provider,
 	return &Provider{log: log, runner: runner}, nil
 }
 
-func (p *Provider) Execute(ctx context.Context, properties *model.ExecResourceProperties, log model.Logger) (int, error) {
+func (p *Provider) Execute(ctx context.Context, properties *model.ExecResourceProperties, log model.Logger) ([]byte, int, error) {
 	if p.runner == nil {
-		return -1, fmt.Errorf("no command runner configured")
+		return nil, -1, fmt.Errorf("no command runner configured")
 	}
 
 	cmd := properties.Name
@@ -37,16 +37,17 @@ func (p *Provider) Execute(ctx context.Context, properties *model.ExecResourcePr
 		cmd = properties.Command
 	}
 	if cmd == "" {
-		return -1, fmt.Errorf("no command to execute")
+		return nil, -1, fmt.Errorf("no command to execute")
 	}
 
 	stdout, _, exitCode, err := p.runner.ExecuteWithOptions(ctx, model.ExtendedExecOptions{
 		Command:     shellPath,
 		Args:        append([]string{}, "-c", cmd),
 		Cwd:         properties.Cwd,
-		Environment: properties.Environment,
+		Environment: properties.CommandEnvironment(),
 		Path:        properties.Path,
 		Timeout:     properties.ParsedTimeout,
+		User:        properties.User,
 	})
 
 	p.log.Info("Command finished", "command", properties.Name, "exitcode", exitCode)
@@ -58,7 +59,7 @@ func (p *Provider) Execute(ctx context.Context, properties *model.ExecResourcePr
 		}
 	}
 
-	return exitCode, err
+	return stdout, exitCode, err
 }
 
 func (p *Provider) EvaluateGuard(ctx context.Context, command string, properties *model.ExecResourceProperties) (bool, error) {
@@ -74,9 +75,10 @@ func (p *Provider) EvaluateGuard(ctx context.Context, command string, properties
 		Command:     shellPath,
 		Args:        []string{"-c", command},
 		Cwd:         properties.Cwd,
-		Environment: properties.Environment,
+		Environment: properties.CommandEnvironment(),
 		Path:        properties.Path,
 		Timeout:     properties.ParsedTimeout,
+		User:        properties.User,
 	})
 	if err != nil {
 		return false, err
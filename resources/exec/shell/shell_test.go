@@ -81,7 +81,7 @@ var _ = Describe("Shell Provider", func() {
 				Args:    []string{"-c", "echo hello"},
 			}).Return([]byte("hello\n"), []byte{}, 0, nil)
 
-			exitCode, err := provider.Execute(context.Background(), properties, nil)
+			_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(exitCode).To(Equal(0))
 		})
@@ -99,7 +99,7 @@ var _ = Describe("Shell Provider", func() {
 				Args:    []string{"-c", "echo hello | grep hello"},
 			}).Return([]byte("hello\n"), []byte{}, 0, nil)
 
-			exitCode, err := provider.Execute(context.Background(), properties, nil)
+			_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(exitCode).To(Equal(0))
 		})
@@ -117,7 +117,7 @@ var _ = Describe("Shell Provider", func() {
 				Args:    []string{"-c", "for i in 1 2 3; do echo $i; done"},
 			}).Return([]byte("1\n2\n3\n"), []byte{}, 0, nil)
 
-			exitCode, err := provider.Execute(context.Background(), properties, nil)
+			_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(exitCode).To(Equal(0))
 		})
@@ -135,7 +135,7 @@ var _ = Describe("Shell Provider", func() {
 				Args:    []string{"-c", "echo hello > /tmp/test.txt && cat /tmp/test.txt"},
 			}).Return([]byte("hello\n"), []byte{}, 0, nil)
 
-			exitCode, err := provider.Execute(context.Background(), properties, nil)
+			_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(exitCode).To(Equal(0))
 		})
@@ -155,7 +155,7 @@ var _ = Describe("Shell Provider", func() {
 				Cwd:     "/tmp",
 			}).Return([]byte("/tmp\n"), []byte{}, 0, nil)
 
-			exitCode, err := provider.Execute(context.Background(), properties, nil)
+			_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(exitCode).To(Equal(0))
 		})
@@ -175,7 +175,7 @@ var _ = Describe("Shell Provider", func() {
 				Environment: []string{"FOO=bar", "BAZ=qux"},
 			}).Return([]byte("bar qux\n"), []byte{}, 0, nil)
 
-			exitCode, err := provider.Execute(context.Background(), properties, nil)
+			_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(exitCode).To(Equal(0))
 		})
@@ -195,7 +195,7 @@ var _ = Describe("Shell Provider", func() {
 				Path:    "/usr/local/bin:/usr/bin:/bin",
 			}).Return([]byte{}, []byte{}, 0, nil)
 
-			exitCode, err := provider.Execute(context.Background(), properties, nil)
+			_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(exitCode).To(Equal(0))
 		})
@@ -215,7 +215,7 @@ var _ = Describe("Shell Provider", func() {
 				Timeout: 30 * time.Second,
 			}).Return([]byte{}, []byte{}, 0, nil)
 
-			exitCode, err := provider.Execute(context.Background(), properties, nil)
+			_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(exitCode).To(Equal(0))
 		})
@@ -233,7 +233,7 @@ var _ = Describe("Shell Provider", func() {
 				Args:    []string{"-c", "exit 1"},
 			}).Return([]byte{}, []byte{}, 1, nil)
 
-			exitCode, err := provider.Execute(context.Background(), properties, nil)
+			_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(exitCode).To(Equal(1))
 		})
@@ -249,7 +249,7 @@ var _ = Describe("Shell Provider", func() {
 			expectedErr := errors.New("runner error")
 			runner.EXPECT().ExecuteWithOptions(gomock.Any(), gomock.Any()).Return(nil, nil, -1, expectedErr)
 
-			exitCode, err := provider.Execute(context.Background(), properties, nil)
+			_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 			Expect(err).To(HaveOccurred())
 			Expect(err).To(Equal(expectedErr))
 			Expect(exitCode).To(Equal(-1))
@@ -266,7 +266,7 @@ var _ = Describe("Shell Provider", func() {
 				Command: "echo hello",
 			}
 
-			exitCode, err := provider.Execute(context.Background(), properties, nil)
+			_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("no command runner configured"))
 			Expect(exitCode).To(Equal(-1))
@@ -293,7 +293,7 @@ var _ = Describe("Shell Provider", func() {
 				Timeout:     60 * time.Second,
 			}).Return([]byte{}, []byte{}, 0, nil)
 
-			exitCode, err := provider.Execute(context.Background(), properties, nil)
+			_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(exitCode).To(Equal(0))
 		})
@@ -316,7 +316,7 @@ var _ = Describe("Shell Provider", func() {
 					Args:    []string{"-c", "echo 'hello world'"},
 				}).Return([]byte("hello world\n"), []byte{}, 0, nil)
 
-				exitCode, err := provider.Execute(context.Background(), properties, userLogger)
+				_, exitCode, err := provider.Execute(context.Background(), properties, userLogger)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(exitCode).To(Equal(0))
 			})
@@ -339,7 +339,7 @@ var _ = Describe("Shell Provider", func() {
 
 				runner.EXPECT().ExecuteWithOptions(gomock.Any(), gomock.Any()).Return([]byte("line one\nline two\nline three\n"), []byte{}, 0, nil)
 
-				exitCode, err := provider.Execute(context.Background(), properties, userLogger)
+				_, exitCode, err := provider.Execute(context.Background(), properties, userLogger)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(exitCode).To(Equal(0))
 			})
@@ -361,7 +361,7 @@ var _ = Describe("Shell Provider", func() {
 					Args:    []string{"-c", "echo hello"},
 				}).Return([]byte("hello\n"), []byte{}, 0, nil)
 
-				exitCode, err := provider.Execute(context.Background(), properties, userLogger)
+				_, exitCode, err := provider.Execute(context.Background(), properties, userLogger)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(exitCode).To(Equal(0))
 			})
@@ -380,7 +380,7 @@ var _ = Describe("Shell Provider", func() {
 					Args:    []string{"-c", "echo hello"},
 				}).Return([]byte("hello\n"), []byte{}, 0, nil)
 
-				exitCode, err := provider.Execute(context.Background(), properties, nil)
+				_, exitCode, err := provider.Execute(context.Background(), properties, nil)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(exitCode).To(Equal(0))
 			})
@@ -403,7 +403,7 @@ var _ = Describe("Shell Provider", func() {
 
 				runner.EXPECT().ExecuteWithOptions(gomock.Any(), gomock.Any()).Return([]byte("first\n\nsecond\n"), []byte{}, 0, nil)
 
-				exitCode, err := provider.Execute(context.Background(), properties, userLogger)
+				_, exitCode, err := provider.Execute(context.Background(), properties, userLogger)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(exitCode).To(Equal(0))
 			})
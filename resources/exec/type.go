@@ -7,6 +7,7 @@ package execresource
 import (
 	"context"
 	"fmt"
+	"maps"
 	"slices"
 	"sync"
 
@@ -42,6 +43,8 @@ func New(ctx context.Context, mgr model.Manager, properties model.ExecResourcePr
 		return nil, err
 	}
 
+	properties.InheritedEnvironment = maps.Clone(env.Environ)
+
 	loggerArgs := []any{"type", model.ExecTypeName, "name", properties.Name}
 	logger, err := mgr.Logger(loggerArgs...)
 	if err != nil {
@@ -127,7 +130,7 @@ func (t *Type) ApplyResource(ctx context.Context) (model.ResourceState, error) {
 			t.log.Info("Skipping execution as noop")
 			noopMessage = "Would have executed via subscribe"
 		} else {
-			exitCode, err = p.Execute(ctx, properties, t.mgr.UserLogger())
+			exitCode, err = t.execute(ctx, p, properties)
 			exitCodePtr = &exitCode
 			t.log.Info("Executed", "exitcode", exitCode)
 		}
@@ -145,7 +148,7 @@ func (t *Type) ApplyResource(ctx context.Context) (model.ResourceState, error) {
 			noopMessage = "Would have executed"
 			refreshState = true
 		} else {
-			exitCode, err = p.Execute(ctx, properties, t.mgr.UserLogger())
+			exitCode, err = t.execute(ctx, p, properties)
 			exitCodePtr = &exitCode
 			refreshState = true
 			t.log.Info("Executed", "exitcode", exitCode)
@@ -182,6 +185,49 @@ func (t *Type) ApplyResource(ctx context.Context) (model.ResourceState, error) {
 	return finalStatus, nil
 }
 
+// execute runs the command and stores its parsed output in the data when an output key is set, the output
+// of commands that fail is not stored
+func (t *Type) execute(ctx context.Context, p ExecProvider, properties *model.ExecResourceProperties) (int, error) {
+	runProperties := properties
+	if properties.OutputSensitive && properties.LogOutput {
+		t.log.Warn("Not logging command output marked as sensitive")
+
+		unlogged := *properties
+		unlogged.LogOutput = false
+		runProperties = &unlogged
+	}
+
+	stdout, exitCode, err := p.Execute(ctx, runProperties, t.mgr.UserLogger())
+	if err != nil || properties.OutputKey == "" || !slices.Contains(acceptedExitCodes(properties), exitCode) {
+		return exitCode, err
+	}
+
+	value, err := properties.ParseOutput(stdout)
+	if err != nil {
+		return exitCode, err
+	}
+
+	t.mgr.SetDataValue(properties.OutputKey, value)
+
+	if properties.OutputSensitive {
+		t.log.Info("Stored sensitive command output in data", "key", properties.OutputKey)
+	} else {
+		t.log.Info("Stored command output in data", "key", properties.OutputKey)
+		t.log.Debug("Stored command output", "key", properties.OutputKey, "value", value)
+	}
+
+	return exitCode, nil
+}
+
+// acceptedExitCodes are the exit codes that indicate the command succeeded
+func acceptedExitCodes(properties *model.ExecResourceProperties) []int {
+	if len(properties.Returns) > 0 {
+		return properties.Returns
+	}
+
+	return []int{0}
+}
+
 // isDesiredState reports whether status matches properties. The second return is
 // a human-readable reason describing the mismatch when stable is false, suitable
 // for inclusion in error messages.
@@ -204,10 +250,7 @@ func (t *Type) isDesiredState(properties *model.ExecResourceProperties, status *
 		return true, ""
 	}
 
-	returns := []int{0}
-	if len(properties.Returns) > 0 {
-		returns = properties.Returns
-	}
+	returns := acceptedExitCodes(properties)
 
 	if status.ExitCode != nil {
 		if slices.Contains(returns, *status.ExitCode) {
@@ -231,7 +274,7 @@ func (t *Type) selectProviderUnlocked() error {
 		return err
 	}
 
-	selected, err := registry.FindSuitableProvider(model.ExecTypeName, t.prop.Provider, t.Facts, t.prop, t.log, runner)
+	selected, err := registry.FindSuitableProvider(model.ExecTypeName, t.prop.Provider, t.Facts, t.prop, t.log, runner, t.mgr)
 	if err != nil {
 		return err
 	}
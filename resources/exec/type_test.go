@@ -125,6 +125,28 @@ var _ = Describe("Exec Type", func() {
 			Expect(err.Error()).To(ContainSubstring("invalid subscribe format"))
 		})
 
+		It("Should resolve user templates and inherit the manager environment", func(ctx context.Context) {
+			env, err := mgr.TemplateEnvironment(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			env.Facts = map[string]any{"user": "app"}
+			env.Environ = map[string]string{"FOO": "manager", "BAR": "baz"}
+
+			exec, err := New(ctx, mgr, model.ExecResourceProperties{
+				CommonResourceProperties: model.CommonResourceProperties{
+					Name:   "/bin/env",
+					Ensure: model.EnsurePresent,
+				},
+				User:        "{{ Facts.user }}",
+				Environment: []string{"FOO=resource"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(exec.prop.User).To(Equal("app"))
+			Expect(exec.prop.CommandEnvironment()).To(Equal([]string{"BAR=baz", "FOO=resource"}))
+
+			env.Environ["BAR"] = "changed"
+			Expect(exec.prop.InheritedEnvironment).To(HaveKeyWithValue("BAR", "baz"))
+		})
+
 		It("Should skip validation when SkipValidate is true", func(ctx context.Context) {
 			exec, err := New(ctx, mgr, model.ExecResourceProperties{
 				CommonResourceProperties: model.CommonResourceProperties{
@@ -325,7 +347,7 @@ var _ = Describe("Exec Type", func() {
 					finalState := &model.ExecState{CreatesSatisfied: false, ExitCode: intPtr(0)}
 
 					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(initialState, nil)
-					provider.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Return(0, nil)
+					provider.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, 0, nil)
 					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(finalState, nil)
 
 					result, err := exec.Apply(ctx)
@@ -340,7 +362,7 @@ var _ = Describe("Exec Type", func() {
 					finalState := &model.ExecState{CreatesSatisfied: false, ExitCode: intPtr(1)}
 
 					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(initialState, nil)
-					provider.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Return(1, nil)
+					provider.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, 1, nil)
 					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(finalState, nil)
 
 					event, err := exec.Apply(ctx)
@@ -354,7 +376,7 @@ var _ = Describe("Exec Type", func() {
 					finalState := &model.ExecState{CreatesSatisfied: false, ExitCode: intPtr(1)}
 
 					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(initialState, nil)
-					provider.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Return(1, nil)
+					provider.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, 1, nil)
 					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(finalState, nil)
 
 					result, err := exec.Apply(ctx)
@@ -367,7 +389,7 @@ var _ = Describe("Exec Type", func() {
 					initialState := &model.ExecState{CreatesSatisfied: false, ExitCode: nil}
 
 					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(initialState, nil)
-					provider.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Return(-1, fmt.Errorf("execution failed"))
+					provider.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, -1, fmt.Errorf("execution failed"))
 
 					event, err := exec.Apply(ctx)
 					Expect(err).ToNot(HaveOccurred())
@@ -375,6 +397,65 @@ var _ = Describe("Exec Type", func() {
 				})
 			})
 
+			Context("with OutputKey", func() {
+				var initialState, finalState *model.ExecState
+
+				BeforeEach(func() {
+					exec.prop.OutputKey = "node"
+					initialState = &model.ExecState{CreatesSatisfied: false, ExitCode: nil}
+					finalState = &model.ExecState{CreatesSatisfied: false, ExitCode: intPtr(0)}
+				})
+
+				It("Should store parsed output in data", func(ctx context.Context) {
+					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(initialState, nil)
+					provider.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte(`{"id":"abc"}`), 0, nil)
+					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(finalState, nil)
+					mgr.EXPECT().SetDataValue("node", map[string]any{"id": "abc"})
+
+					result, err := exec.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.Changed).To(BeTrue())
+					Expect(result.Failed).To(BeFalse())
+				})
+
+				It("Should not log sensitive output", func(ctx context.Context) {
+					exec.prop.OutputSensitive = true
+					exec.prop.OutputFormat = model.ExecOutputFormatKV
+					exec.prop.LogOutput = true
+
+					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(initialState, nil)
+					provider.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, p *model.ExecResourceProperties, _ model.Logger) ([]byte, int, error) {
+						Expect(p.LogOutput).To(BeFalse())
+						return []byte("token=s3cret\n"), 0, nil
+					})
+					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(finalState, nil)
+					mgr.EXPECT().SetDataValue("node", map[string]any{"token": "s3cret"})
+
+					result, err := exec.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.Failed).To(BeFalse())
+				})
+
+				It("Should not store output of failed commands", func(ctx context.Context) {
+					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(initialState, nil)
+					provider.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte(`{"id":"abc"}`), 1, nil)
+					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(&model.ExecState{ExitCode: intPtr(1)}, nil)
+
+					event, err := exec.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(event.Errors).To(ContainElement(ContainSubstring("failed to reach desired state")))
+				})
+
+				It("Should fail when the output cannot be parsed", func(ctx context.Context) {
+					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(initialState, nil)
+					provider.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte("not json"), 0, nil)
+
+					event, err := exec.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(event.Errors).To(ContainElement(ContainSubstring("could not parse output as JSON")))
+				})
+			})
+
 			Context("with OnlyIf", func() {
 				It("Should not execute when OnlyIf is not satisfied", func(ctx context.Context) {
 					exec.prop.OnlyIf = "test -f /tmp/ready"
@@ -395,7 +476,7 @@ var _ = Describe("Exec Type", func() {
 
 					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(initialState, nil)
 					provider.EXPECT().EvaluateGuard(gomock.Any(), "test -f /tmp/ready", gomock.Any()).Return(true, nil)
-					provider.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Return(0, nil)
+					provider.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, 0, nil)
 					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(finalState, nil)
 
 					result, err := exec.Apply(ctx)
@@ -436,7 +517,7 @@ var _ = Describe("Exec Type", func() {
 
 					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(initialState, nil)
 					provider.EXPECT().EvaluateGuard(gomock.Any(), "pgrep myapp", gomock.Any()).Return(false, nil)
-					provider.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Return(0, nil)
+					provider.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, 0, nil)
 					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(finalState, nil)
 
 					result, err := exec.Apply(ctx)
@@ -445,6 +526,32 @@ var _ = Describe("Exec Type", func() {
 				})
 			})
 
+			Context("with OnlyIf and Unless", func() {
+				DescribeTable("Should only execute when both guards allow it",
+					func(ctx context.Context, onlyIf bool, unless bool, executes bool) {
+						exec.prop.OnlyIf = "test -f /tmp/ready"
+						exec.prop.Unless = "pgrep myapp"
+
+						provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(&model.ExecState{}, nil)
+						provider.EXPECT().EvaluateGuard(gomock.Any(), "test -f /tmp/ready", gomock.Any()).Return(onlyIf, nil)
+						provider.EXPECT().EvaluateGuard(gomock.Any(), "pgrep myapp", gomock.Any()).Return(unless, nil)
+						if executes {
+							provider.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, 0, nil)
+							provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(&model.ExecState{ExitCode: intPtr(0)}, nil)
+						}
+
+						result, err := exec.Apply(ctx)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(result.Errors).To(BeEmpty())
+						Expect(result.Changed).To(Equal(executes))
+					},
+					Entry("onlyif succeeds and unless fails", true, false, true),
+					Entry("onlyif fails and unless fails", false, false, false),
+					Entry("onlyif succeeds and unless succeeds", true, true, false),
+					Entry("onlyif fails and unless succeeds", false, true, false),
+				)
+			})
+
 			Context("with Subscribe and OnlyIf", func() {
 				It("Should execute via subscribe even when OnlyIf is not satisfied", func(ctx context.Context) {
 					exec.prop.OnlyIf = "test -f /tmp/ready"
@@ -456,7 +563,7 @@ var _ = Describe("Exec Type", func() {
 
 					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(initialState, nil)
 					provider.EXPECT().EvaluateGuard(gomock.Any(), "test -f /tmp/ready", gomock.Any()).Return(false, nil)
-					provider.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Return(0, nil)
+					provider.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, 0, nil)
 					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(finalState, nil)
 
 					result, err := exec.Apply(ctx)
@@ -481,6 +588,32 @@ var _ = Describe("Exec Type", func() {
 					Expect(err).ToNot(HaveOccurred())
 					Expect(result.Changed).To(BeFalse())
 				})
+
+				It("Should execute when a subscribed resource changed", func(ctx context.Context) {
+					exec.prop.Subscribe = []string{"file#/etc/app.conf"}
+					mgr.EXPECT().ShouldRefresh("file", "/etc/app.conf").Return(true, nil)
+
+					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(&model.ExecState{}, nil)
+					provider.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, 0, nil)
+					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(&model.ExecState{ExitCode: intPtr(0)}, nil)
+
+					result, err := exec.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.Changed).To(BeTrue())
+					Expect(result.Refreshed).To(BeTrue())
+				})
+
+				It("Should not execute when subscribed resources did not change", func(ctx context.Context) {
+					exec.prop.Subscribe = []string{"file#/etc/app.conf"}
+					mgr.EXPECT().ShouldRefresh("file", "/etc/app.conf").Return(false, nil)
+
+					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(&model.ExecState{}, nil)
+
+					result, err := exec.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.Changed).To(BeFalse())
+					Expect(result.Refreshed).To(BeFalse())
+				})
 			})
 
 			Context("with Subscribe", func() {
@@ -495,7 +628,7 @@ var _ = Describe("Exec Type", func() {
 					finalState := &model.ExecState{CreatesSatisfied: false, ExitCode: intPtr(0)}
 
 					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(initialState, nil)
-					provider.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Return(0, nil)
+					provider.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, 0, nil)
 					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(finalState, nil)
 
 					result, err := exec.Apply(ctx)
@@ -513,7 +646,7 @@ var _ = Describe("Exec Type", func() {
 					finalState := &model.ExecState{CreatesSatisfied: false, ExitCode: intPtr(0)}
 
 					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(initialState, nil)
-					provider.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Return(0, nil)
+					provider.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, 0, nil)
 					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(finalState, nil)
 
 					result, err := exec.Apply(ctx)
@@ -529,7 +662,7 @@ var _ = Describe("Exec Type", func() {
 					finalState := &model.ExecState{CreatesSatisfied: true, ExitCode: intPtr(0)}
 
 					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(initialState, nil)
-					provider.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Return(0, nil)
+					provider.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, 0, nil)
 					provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(finalState, nil)
 
 					result, err := exec.Apply(ctx)
@@ -616,6 +749,33 @@ var _ = Describe("Exec Type", func() {
 				Expect(result.Refreshed).To(BeTrue())
 			})
 
+			It("Should not report changes in noop mode when RefreshOnly is not triggered", func(ctx context.Context) {
+				noopExec.prop.RefreshOnly = true
+				noopExec.prop.Subscribe = []string{"file#/etc/app.conf"}
+				noopMgr.EXPECT().ShouldRefresh("file", "/etc/app.conf").Return(false, nil)
+
+				noopProvider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(&model.ExecState{}, nil)
+
+				result, err := noopExec.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Changed).To(BeFalse())
+				Expect(result.Noop).To(BeTrue())
+			})
+
+			It("Should report would have executed in noop mode when RefreshOnly is triggered", func(ctx context.Context) {
+				noopExec.prop.RefreshOnly = true
+				noopExec.prop.Subscribe = []string{"file#/etc/app.conf"}
+				noopMgr.EXPECT().ShouldRefresh("file", "/etc/app.conf").Return(true, nil)
+
+				noopProvider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(&model.ExecState{}, nil)
+
+				result, err := noopExec.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Changed).To(BeTrue())
+				Expect(result.Noop).To(BeTrue())
+				Expect(result.NoopMessage).To(Equal("Would have executed via subscribe"))
+			})
+
 			It("Should not execute in noop mode when OnlyIf is not satisfied", func(ctx context.Context) {
 				noopExec.prop.OnlyIf = "test -f /tmp/ready"
 				initialState := &model.ExecState{ExitCode: nil}
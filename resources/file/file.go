@@ -19,8 +19,16 @@ type FileProvider interface {
 	model.Provider
 
 	CreateDirectory(ctx context.Context, dir string, owner string, group string, mode string) error
+	CreateParents(ctx context.Context, path string, owner string, group string, mode string) ([]string, error)
 	Store(ctx context.Context, file string, contents []byte, source string, owner string, group string, mode string) error
+	ValidateContent(ctx context.Context, file string, command string, contents []byte, source string) error
 	SetAttributes(ctx context.Context, file string, owner string, group string, mode string) error
+	Acl(ctx context.Context, file string) ([]string, error)
+	SetAcl(ctx context.Context, file string, acl []string) error
 	Remove(ctx context.Context, file string, force bool) error
+	CountEntries(ctx context.Context, dir string) (int, error)
+	ListEntries(ctx context.Context, dir string) ([]string, error)
 	Status(ctx context.Context, file string) (*model.FileState, error)
+	Contents(ctx context.Context, file string) ([]byte, error)
+	ResolveSources(ctx context.Context, sources []string) (source string, contents []byte, err error)
 }
@@ -19,7 +19,7 @@ type factory struct{}
 func (p *factory) TypeName() string { return model.FileTypeName }
 func (p *factory) Name() string     { return ProviderName }
 func (p *factory) New(log model.Logger, runner model.CommandRunner) (model.Provider, error) {
-	return NewPosixProvider(log)
+	return NewPosixProvider(log, runner)
 }
 func (p *factory) IsManageable(_ map[string]any, _ model.ResourceProperties) (bool, int, error) {
 	return true, 1, nil
@@ -5,15 +5,23 @@
 package posix
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
 	"syscall"
 
+	"github.com/kballard/go-shellquote"
+
 	iu "github.com/choria-io/ccm/internal/util"
 	"github.com/choria-io/ccm/model"
 )
@@ -21,11 +29,12 @@ import (
 const ProviderName = "posix"
 
 type Provider struct {
-	log model.Logger
+	log    model.Logger
+	runner model.CommandRunner
 }
 
-func NewPosixProvider(log model.Logger) (*Provider, error) {
-	return &Provider{log: log}, nil
+func NewPosixProvider(log model.Logger, runner model.CommandRunner) (*Provider, error) {
+	return &Provider{log: log, runner: runner}, nil
 }
 
 func (p *Provider) CreateDirectory(ctx context.Context, dir string, owner string, group string, mode string) error {
@@ -63,6 +72,70 @@ func (p *Provider) CreateDirectory(ctx context.Context, dir string, owner string
 	return os.Chown(dir, uid, gid)
 }
 
+// CreateParents creates the missing parent directories of path from the top down, only directories that are
+// created are given owner, group and mode. Existing directories are never changed. Returns the created directories.
+func (p *Provider) CreateParents(ctx context.Context, path string, owner string, group string, mode string) ([]string, error) {
+	parsedMode, err := parseFileMode(mode)
+	if err != nil {
+		return nil, err
+	}
+	parsedMode = iu.DirectoryMode(parsedMode)
+
+	var missing []string
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		stat, err := os.Stat(dir)
+		if err == nil {
+			if !stat.IsDir() {
+				return nil, fmt.Errorf("%q is not a directory", dir)
+			}
+			break
+		}
+		// ENOTDIR means an ancestor is not a directory, keep walking up to report it
+		if !errors.Is(err, os.ErrNotExist) && !errors.Is(err, syscall.ENOTDIR) {
+			return nil, err
+		}
+
+		missing = append(missing, dir)
+
+		if dir == filepath.Dir(dir) {
+			break
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil, nil
+	}
+
+	uid, gid, err := iu.LookupOwnerGroup(owner, group)
+	if err != nil {
+		return nil, err
+	}
+
+	var created []string
+	for i := len(missing) - 1; i >= 0; i-- {
+		dir := missing[i]
+
+		err = os.Mkdir(dir, parsedMode)
+		if err != nil {
+			return created, err
+		}
+		created = append(created, dir)
+
+		// chown before chmod as chown(2) clears setuid/setgid bits
+		err = os.Chown(dir, uid, gid)
+		if err != nil {
+			return created, err
+		}
+
+		err = os.Chmod(dir, parsedMode)
+		if err != nil {
+			return created, err
+		}
+	}
+
+	return created, nil
+}
+
 func parseFileMode(mode string) (os.FileMode, error) {
 	parsedMode, err := strconv.ParseUint(mode, 8, 32)
 	if err != nil {
@@ -114,26 +187,63 @@ func (p *Provider) Store(ctx context.Context, file string, contents []byte, sour
 		return err
 	}
 
+	// the content has to be on disk before the rename makes it visible, else a crash
+	// could leave an empty or truncated file in place of the original
+	err = tf.Sync()
+	if err != nil {
+		return fmt.Errorf("could not sync temporary file: %w", err)
+	}
+
 	err = tf.Close()
 	if err != nil {
 		return fmt.Errorf("could not close temporary file: %w", err)
 	}
 
-	err = os.Rename(tf.Name(), file)
+	// the temporary file gets its final ownership and mode before the rename so the
+	// file is never visible with the default permissions of a new file
+	err = setOwnerAndMode(tf.Name(), uid, gid, parsedMode)
+	if err != nil {
+		return err
+	}
+
+	err = renameFile(tf.Name(), file)
 	if err != nil {
 		return fmt.Errorf("could not rename temporary file: %w", err)
 	}
 
-	// chown and chmod by path rather than fd: some filesystems (Docker
-	// Desktop bind mounts via VirtioFS/gRPC-FUSE) silently drop fchown
-	// across a rename. chown runs before chmod because chown(2) clears
-	// setuid/setgid bits.
-	err = os.Chown(file, uid, gid)
+	err = syncDirectory(dir)
+	if err != nil {
+		p.log.Debug("Could not sync directory after rename", "dir", dir, "error", err)
+	}
+
+	// some filesystems (Docker Desktop bind mounts via VirtioFS/gRPC-FUSE)
+	// silently drop ownership across a rename so it is set again by path
+	return setOwnerAndMode(file, uid, gid, parsedMode)
+}
+
+// renameFile moves the temporary file into place, replaceable in tests to simulate failures
+var renameFile = os.Rename
+
+// setOwnerAndMode sets ownership and mode by path rather than fd, chown runs
+// before chmod because chown(2) clears setuid/setgid bits
+func setOwnerAndMode(file string, uid int, gid int, mode os.FileMode) error {
+	err := os.Chown(file, uid, gid)
 	if err != nil {
 		return err
 	}
 
-	return os.Chmod(file, parsedMode)
+	return os.Chmod(file, mode)
+}
+
+// syncDirectory flushes the directory entry of a renamed file to disk
+func syncDirectory(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
 }
 
 // SetAttributes updates owner, group and mode on an existing regular file
@@ -170,6 +280,112 @@ func (p *Provider) SetAttributes(ctx context.Context, file string, owner string,
 	return os.Chmod(file, parsedMode)
 }
 
+// Acl returns the named user and group acl entries of file in canonical form, sorted. The base entries and the
+// mask are not included as they are represented by the file mode.
+func (p *Provider) Acl(ctx context.Context, file string) ([]string, error) {
+	stdout, stderr, exitCode, err := p.runner.Execute(ctx, "getfacl", "--absolute-names", "--omit-header", "--", file)
+	if err != nil {
+		return nil, aclCommandError("getfacl", err)
+	}
+	if exitCode != 0 {
+		return nil, aclFailure(file, "read", stderr)
+	}
+
+	var entries []string
+	for _, line := range strings.Split(string(stdout), "\n") {
+		entry, _, _ := strings.Cut(line, "#")
+
+		// base entries, the mask and entries we cannot manage fail to normalize and are skipped
+		normalized, err := model.NormalizeFileAclEntry(entry)
+		if err != nil {
+			continue
+		}
+
+		entries = append(entries, normalized)
+	}
+
+	return model.NormalizeFileAcl(entries)
+}
+
+// SetAcl sets the named user and group acl entries of file to acl, removing entries not listed. The file mode is
+// preserved, while an acl is set the group bits of the mode are the acl mask that limits the effective permissions
+// of the entries.
+func (p *Provider) SetAcl(ctx context.Context, file string, acl []string) error {
+	desired, err := model.NormalizeFileAcl(acl)
+	if err != nil {
+		return err
+	}
+
+	current, err := p.Acl(ctx, file)
+	if err != nil {
+		return err
+	}
+
+	stat, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+
+	var remove, add []string
+	for _, entry := range current {
+		if !slices.Contains(desired, entry) {
+			// entries are removed by type and qualifier without permissions
+			remove = append(remove, entry[:strings.LastIndex(entry, ":")])
+		}
+	}
+	for _, entry := range desired {
+		if !slices.Contains(current, entry) {
+			add = append(add, entry)
+		}
+	}
+
+	if len(remove) > 0 {
+		err = p.setfacl(ctx, file, "-x", strings.Join(remove, ","))
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(add) > 0 {
+		err = p.setfacl(ctx, file, "-m", strings.Join(add, ","))
+		if err != nil {
+			return err
+		}
+	}
+
+	// setfacl recalculates the mask which changes the group bits of the mode, the managed mode is restored
+	return os.Chmod(file, stat.Mode()&(os.ModePerm|os.ModeSetuid|os.ModeSetgid|os.ModeSticky))
+}
+
+func (p *Provider) setfacl(ctx context.Context, file string, operation string, entries string) error {
+	_, stderr, exitCode, err := p.runner.Execute(ctx, "setfacl", operation, entries, "--", file)
+	if err != nil {
+		return aclCommandError("setfacl", err)
+	}
+	if exitCode != 0 {
+		return aclFailure(file, "set", stderr)
+	}
+
+	return nil
+}
+
+func aclCommandError(command string, err error) error {
+	if errors.Is(err, model.ErrExecutableNotFound) {
+		return fmt.Errorf("%w: %s is not installed", model.ErrAclNotSupported, command)
+	}
+
+	return err
+}
+
+func aclFailure(file string, action string, stderr []byte) error {
+	msg := strings.TrimSpace(string(stderr))
+	if strings.Contains(msg, "Operation not supported") {
+		return fmt.Errorf("%w by the filesystem holding %s", model.ErrAclNotSupported, file)
+	}
+
+	return fmt.Errorf("could not %s acl of %s: %s", action, file, msg)
+}
+
 // Remove removes a file or directory.
 //
 // When force is true, non-empty directories are removed recursively via
@@ -200,6 +416,124 @@ func (p *Provider) Remove(ctx context.Context, file string, force bool) error {
 	}
 }
 
+// Contents reads the current content of file
+func (p *Provider) Contents(_ context.Context, file string) ([]byte, error) {
+	return os.ReadFile(file)
+}
+
+// ValidateContent writes the content, or the source file, to a temporary file and runs command with %{path} replaced
+// by its path, a non zero exit code fails validation
+func (p *Provider) ValidateContent(ctx context.Context, file string, command string, contents []byte, source string) error {
+	words, err := shellquote.Split(command)
+	if err != nil {
+		return fmt.Errorf("invalid validate command: %w", err)
+	}
+	if len(words) == 0 {
+		return fmt.Errorf("validate command is empty")
+	}
+
+	// keeps the file name as a suffix since some validators look at the extension
+	tf, err := os.CreateTemp("", fmt.Sprintf("ccm-validate-*-%s", filepath.Base(file)))
+	if err != nil {
+		return err
+	}
+	defer tf.Close()
+	defer os.Remove(tf.Name())
+
+	if source != "" {
+		sf, err := os.Open(source)
+		if err != nil {
+			return err
+		}
+		defer sf.Close()
+
+		_, err = io.Copy(tf, sf)
+		if err != nil {
+			return err
+		}
+	} else {
+		_, err = tf.Write(contents)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tf.Close()
+	if err != nil {
+		return fmt.Errorf("could not close temporary file: %w", err)
+	}
+
+	for i, word := range words {
+		words[i] = strings.ReplaceAll(word, model.FileValidatePathPlaceholder, tf.Name())
+	}
+
+	stdout, stderr, exitCode, err := p.runner.Execute(ctx, words[0], words[1:]...)
+	if err != nil {
+		return fmt.Errorf("could not validate content: %w", err)
+	}
+	if exitCode != 0 {
+		return fmt.Errorf("content failed validation: %s", bytes.TrimSpace(append(stdout, stderr...)))
+	}
+
+	p.log.Debug("Validated content", "file", file, "command", words[0])
+
+	return nil
+}
+
+// ListEntries returns the paths of all entries below dir relative to dir, parents are listed before their entries
+func (p *Provider) ListEntries(ctx context.Context, dir string) ([]string, error) {
+	var entries []string
+
+	err := filepath.WalkDir(dir, func(path string, _ fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if path == dir {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, rel)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// CountEntries counts the files and directories below dir recursively, symlinks are counted but not followed
+func (p *Provider) CountEntries(ctx context.Context, dir string) (int, error) {
+	count := 0
+
+	err := filepath.WalkDir(dir, func(path string, _ fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if path != dir {
+			count++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
 // Status returns the current installation status of a file
 func (p *Provider) Status(ctx context.Context, file string) (*model.FileState, error) {
 	metadata := &model.FileMetadata{
@@ -248,6 +582,48 @@ func (p *Provider) Status(ctx context.Context, file string) (*model.FileState, e
 	return state, nil
 }
 
+// ResolveSources tries each source in order and returns the first that resolves, local paths resolve when they are
+// a regular file and are returned without content while URLs resolve when they can be fetched with a 200 status
+// code and are returned with the fetched content. An empty source is returned when none resolve.
+func (p *Provider) ResolveSources(ctx context.Context, sources []string) (string, []byte, error) {
+	for _, source := range sources {
+		if !model.IsUrlSource(source) {
+			stat, err := os.Stat(source)
+			if err != nil || !stat.Mode().IsRegular() {
+				p.log.Debug("Skipping source that is not a readable file", "source", source, "error", err)
+				continue
+			}
+
+			return source, nil, nil
+		}
+
+		uri, err := url.Parse(source)
+		if err != nil {
+			return "", nil, err
+		}
+		redacted := iu.RedactUrlCredentials(uri)
+
+		res, err := iu.HttpGet(ctx, source, 0)
+		if err != nil {
+			if ctx.Err() != nil {
+				return "", nil, ctx.Err()
+			}
+
+			p.log.Debug("Skipping source that could not be fetched", "source", redacted, "error", err)
+			continue
+		}
+
+		if res.StatusCode != http.StatusOK {
+			p.log.Debug("Skipping source that could not be fetched", "source", redacted, "status", res.Status)
+			continue
+		}
+
+		return source, res.Body, nil
+	}
+
+	return "", nil, nil
+}
+
 func (p *Provider) Name() string {
 	return ProviderName
 }
@@ -8,6 +8,9 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/user"
 	"path/filepath"
@@ -45,7 +48,7 @@ var _ = Describe("Posix Provider", func() {
 		logger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
 		logger.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
 
-		provider, err = NewPosixProvider(logger)
+		provider, err = NewPosixProvider(logger, nil)
 		Expect(err).ToNot(HaveOccurred())
 	})
 
@@ -214,6 +217,64 @@ var _ = Describe("Posix Provider", func() {
 			Expect(readContent).To(Equal(newContent))
 		})
 
+		Context("when writing atomically", func() {
+			var (
+				tmpDir   string
+				testFile string
+			)
+
+			BeforeEach(func() {
+				tmpDir = GinkgoT().TempDir()
+				testFile = filepath.Join(tmpDir, "config.txt")
+
+				Expect(os.WriteFile(testFile, []byte("original content"), 0644)).To(Succeed())
+
+				DeferCleanup(func() { renameFile = os.Rename })
+			})
+
+			It("Should leave the original intact when interrupted before the rename", func() {
+				renameFile = func(string, string) error { return fmt.Errorf("killed") }
+
+				err := provider.Store(context.Background(), testFile, []byte("new content"), "", currentUser.Username, currentGroup.Name, "0600")
+				Expect(err).To(MatchError(ContainSubstring("could not rename temporary file: killed")))
+
+				readContent, err := os.ReadFile(testFile)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(readContent)).To(Equal("original content"))
+
+				entries, err := os.ReadDir(tmpDir)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(entries).To(HaveLen(1))
+			})
+
+			It("Should apply ownership and mode to the temporary file before the rename", func() {
+				var tempStat os.FileInfo
+				var tempContent []byte
+
+				renameFile = func(oldpath string, newpath string) error {
+					var err error
+					tempStat, err = os.Stat(oldpath)
+					Expect(err).ToNot(HaveOccurred())
+					tempContent, err = os.ReadFile(oldpath)
+					Expect(err).ToNot(HaveOccurred())
+
+					return os.Rename(oldpath, newpath)
+				}
+
+				err := provider.Store(context.Background(), testFile, []byte("new content"), "", currentUser.Username, currentGroup.Name, "0600")
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(tempStat.Mode().Perm()).To(Equal(os.FileMode(0600)))
+				Expect(strconv.Itoa(int(tempStat.Sys().(*syscall.Stat_t).Uid))).To(Equal(currentUser.Uid))
+				Expect(strconv.Itoa(int(tempStat.Sys().(*syscall.Stat_t).Gid))).To(Equal(currentGroup.Gid))
+				Expect(string(tempContent)).To(Equal("new content"))
+
+				readContent, err := os.ReadFile(testFile)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(readContent)).To(Equal("new content"))
+			})
+		})
+
 		It("Should store empty content", func() {
 			tmpDir := GinkgoT().TempDir()
 			testFile := filepath.Join(tmpDir, "emptyfile.txt")
@@ -294,6 +355,49 @@ var _ = Describe("Posix Provider", func() {
 		})
 	})
 
+	Describe("ResolveSources", func() {
+		var (
+			dir    string
+			server *httptest.Server
+		)
+
+		BeforeEach(func() {
+			dir = GinkgoT().TempDir()
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/motd" {
+					http.NotFound(w, r)
+					return
+				}
+				fmt.Fprint(w, "url content")
+			}))
+			DeferCleanup(server.Close)
+		})
+
+		It("Should fall through missing sources to the first local file", func(ctx context.Context) {
+			source := filepath.Join(dir, "source.txt")
+			Expect(os.WriteFile(source, []byte("local content"), 0644)).To(Succeed())
+
+			selected, contents, err := provider.ResolveSources(ctx, []string{filepath.Join(dir, "missing.txt"), dir, server.URL + "/missing", source, server.URL + "/motd"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(selected).To(Equal(source))
+			Expect(contents).To(BeNil())
+		})
+
+		It("Should fetch URL sources", func(ctx context.Context) {
+			selected, contents, err := provider.ResolveSources(ctx, []string{filepath.Join(dir, "missing.txt"), server.URL + "/motd"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(selected).To(Equal(server.URL + "/motd"))
+			Expect(string(contents)).To(Equal("url content"))
+		})
+
+		It("Should return no source when none resolve", func(ctx context.Context) {
+			selected, contents, err := provider.ResolveSources(ctx, []string{filepath.Join(dir, "missing.txt"), server.URL + "/missing"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(selected).To(BeEmpty())
+			Expect(contents).To(BeNil())
+		})
+	})
+
 	Describe("SetAttributes", func() {
 		var (
 			currentUser  *user.User
@@ -358,9 +462,65 @@ var _ = Describe("Posix Provider", func() {
 		})
 	})
 
+	Describe("Acl", func() {
+		var (
+			runner *modelmocks.MockCommandRunner
+			file   string
+		)
+
+		BeforeEach(func() {
+			runner = modelmocks.NewMockCommandRunner(mockctl)
+			provider, err = NewPosixProvider(logger, runner)
+			Expect(err).ToNot(HaveOccurred())
+
+			file = filepath.Join(GinkgoT().TempDir(), "shared")
+			Expect(os.WriteFile(file, nil, 0640)).To(Succeed())
+		})
+
+		It("Should report only named entries sorted", func(ctx context.Context) {
+			runner.EXPECT().Execute(gomock.Any(), "getfacl", "--absolute-names", "--omit-header", "--", file).Return([]byte("user::rw-\nuser:deploy:rwx\t\t#effective:r--\ngroup::r--\ngroup:admins:r-x\nmask::r--\nother::---\ndefault:user:deploy:rwx\n\n"), nil, 0, nil)
+
+			acl, err := provider.Acl(ctx, file)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(acl).To(Equal([]string{"default:user:deploy:rwx", "group:admins:r-x", "user:deploy:rwx"}))
+		})
+
+		It("Should report a clear error when acl tools are not installed", func(ctx context.Context) {
+			runner.EXPECT().Execute(gomock.Any(), "getfacl", "--absolute-names", "--omit-header", "--", file).Return(nil, nil, -1, fmt.Errorf("%w: getfacl", model.ErrExecutableNotFound))
+
+			_, err := provider.Acl(ctx, file)
+			Expect(err).To(MatchError(model.ErrAclNotSupported))
+			Expect(err).To(MatchError("file ACLs are not supported: getfacl is not installed"))
+		})
+
+		It("Should replace only the entries that differ and keep the mode", func(ctx context.Context) {
+			runner.EXPECT().Execute(gomock.Any(), "getfacl", "--absolute-names", "--omit-header", "--", file).Return([]byte("user::rw-\nuser:deploy:r--\nuser:intruder:rwx\ngroup:admins:r-x\n"), nil, 0, nil)
+			runner.EXPECT().Execute(gomock.Any(), "setfacl", "-x", "user:deploy,user:intruder", "--", file).Return(nil, nil, 0, nil)
+			runner.EXPECT().Execute(gomock.Any(), "setfacl", "-m", "user:deploy:rwx", "--", file).DoAndReturn(func(_ context.Context, _ string, _ ...string) ([]byte, []byte, int, error) {
+				// setfacl recalculates the mask which changes the group bits
+				return nil, nil, 0, os.Chmod(file, 0670)
+			})
+
+			Expect(provider.SetAcl(ctx, file, []string{"g:admins:rx", "u:deploy:rwx"})).To(Succeed())
+
+			stat, err := os.Stat(file)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stat.Mode().Perm()).To(Equal(os.FileMode(0640)))
+		})
+
+		It("Should report filesystems without acl support", func(ctx context.Context) {
+			runner.EXPECT().Execute(gomock.Any(), "getfacl", "--absolute-names", "--omit-header", "--", file).Return([]byte("user::rw-\ngroup::r--\nother::---\n"), nil, 0, nil)
+			runner.EXPECT().Execute(gomock.Any(), "setfacl", "-m", "user:deploy:rwx", "--", file).Return(nil, []byte("setfacl: "+file+": Operation not supported\n"), 1, nil)
+
+			err := provider.SetAcl(ctx, file, []string{"u:deploy:rwx"})
+			Expect(err).To(MatchError(model.ErrAclNotSupported))
+			Expect(err).To(MatchError(ContainSubstring("by the filesystem holding " + file)))
+		})
+	})
+
 	Describe("NewPosixProvider", func() {
 		It("Should create a provider with the given logger", func() {
-			p, err := NewPosixProvider(logger)
+			p, err := NewPosixProvider(logger, nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(p).ToNot(BeNil())
 			Expect(p.log).To(Equal(logger))
@@ -569,6 +729,75 @@ var _ = Describe("Posix Provider", func() {
 		})
 	})
 
+	Describe("CreateParents", func() {
+		var (
+			currentUser  *user.User
+			currentGroup *user.Group
+		)
+
+		BeforeEach(func() {
+			var err error
+			currentUser, err = user.Current()
+			Expect(err).ToNot(HaveOccurred())
+
+			currentGroup, err = user.LookupGroupId(currentUser.Gid)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should create only missing parents with the requested mode", func() {
+			tmpDir := GinkgoT().TempDir()
+			Expect(os.Chmod(tmpDir, 0711)).To(Succeed())
+
+			file := filepath.Join(tmpDir, "a", "b", "file.conf")
+
+			created, err := provider.CreateParents(context.Background(), file, currentUser.Username, currentGroup.Name, "0640")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(created).To(Equal([]string{filepath.Join(tmpDir, "a"), filepath.Join(tmpDir, "a", "b")}))
+
+			for _, dir := range created {
+				stat, err := os.Stat(dir)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stat.IsDir()).To(BeTrue())
+				Expect(stat.Mode().Perm()).To(Equal(os.FileMode(0750)))
+			}
+
+			// the existing directory is left alone
+			stat, err := os.Stat(tmpDir)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stat.Mode().Perm()).To(Equal(os.FileMode(0711)))
+
+			Expect(iu.FileExists(file)).To(BeFalse())
+		})
+
+		It("Should not change existing parents", func() {
+			tmpDir := GinkgoT().TempDir()
+			parent := filepath.Join(tmpDir, "existing")
+			Expect(os.Mkdir(parent, 0700)).To(Succeed())
+
+			created, err := provider.CreateParents(context.Background(), filepath.Join(parent, "file.conf"), currentUser.Username, currentGroup.Name, "0755")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(created).To(BeEmpty())
+
+			stat, err := os.Stat(parent)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stat.Mode().Perm()).To(Equal(os.FileMode(0700)))
+		})
+
+		It("Should fail when a parent is not a directory", func() {
+			tmpDir := GinkgoT().TempDir()
+			blocker := filepath.Join(tmpDir, "blocker")
+			Expect(os.WriteFile(blocker, []byte("x"), 0644)).To(Succeed())
+
+			_, err := provider.CreateParents(context.Background(), filepath.Join(blocker, "sub", "file.conf"), currentUser.Username, currentGroup.Name, "0755")
+			Expect(err).To(MatchError(ContainSubstring("is not a directory")))
+		})
+
+		It("Should fail for invalid modes", func() {
+			_, err := provider.CreateParents(context.Background(), "/nonexistent/file", currentUser.Username, currentGroup.Name, "bad")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 	Describe("Status for directories", func() {
 		It("Should return directory ensure for existing directories", func() {
 			tmpDir := GinkgoT().TempDir()
@@ -697,4 +926,124 @@ var _ = Describe("Posix Provider", func() {
 			Expect(keeperErr).ToNot(HaveOccurred())
 		})
 	})
+
+	Describe("ValidateContent", func() {
+		var runner *modelmocks.MockCommandRunner
+
+		BeforeEach(func() {
+			runner = modelmocks.NewMockCommandRunner(mockctl)
+			provider, err = NewPosixProvider(logger, runner)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should run the command against a temporary file holding the content", func(ctx context.Context) {
+			var validated string
+
+			runner.EXPECT().Execute(gomock.Any(), "nginx", "-t", "-c", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, args ...string) ([]byte, []byte, int, error) {
+				validated = args[2]
+				Expect(filepath.Base(validated)).To(HaveSuffix("-nginx.conf"))
+
+				content, err := os.ReadFile(validated)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(content)).To(Equal("events {}\n"))
+
+				return nil, nil, 0, nil
+			})
+
+			Expect(provider.ValidateContent(ctx, "/etc/nginx/nginx.conf", "nginx -t -c %{path}", []byte("events {}\n"), "")).To(Succeed())
+			Expect(validated).ToNot(BeAnExistingFile())
+		})
+
+		It("Should validate the content of a source file", func(ctx context.Context) {
+			source := filepath.Join(GinkgoT().TempDir(), "sshd_config")
+			Expect(os.WriteFile(source, []byte("PermitRootLogin no\n"), 0644)).To(Succeed())
+
+			runner.EXPECT().Execute(gomock.Any(), "sshd", "-t", "-f", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, args ...string) ([]byte, []byte, int, error) {
+				content, err := os.ReadFile(args[2])
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(content)).To(Equal("PermitRootLogin no\n"))
+
+				return nil, nil, 0, nil
+			})
+
+			Expect(provider.ValidateContent(ctx, "/etc/ssh/sshd_config", "sshd -t -f %{path}", nil, source)).To(Succeed())
+		})
+
+		It("Should fail when the command exits non zero", func(ctx context.Context) {
+			runner.EXPECT().Execute(gomock.Any(), "nginx", "-t", "-c", gomock.Any()).Return(nil, []byte("unexpected end of file\n"), 1, nil)
+
+			err := provider.ValidateContent(ctx, "/etc/nginx/nginx.conf", "nginx -t -c %{path}", []byte("events {"), "")
+			Expect(err).To(MatchError("content failed validation: unexpected end of file"))
+		})
+
+		It("Should fail when the command cannot be run", func(ctx context.Context) {
+			runner.EXPECT().Execute(gomock.Any(), "nginx", "-t", "-c", gomock.Any()).Return(nil, nil, -1, fmt.Errorf("%w: nginx", model.ErrExecutableNotFound))
+
+			err := provider.ValidateContent(ctx, "/etc/nginx/nginx.conf", "nginx -t -c %{path}", []byte("events {}"), "")
+			Expect(err).To(MatchError(model.ErrExecutableNotFound))
+		})
+	})
+
+	Describe("ListEntries", func() {
+		It("Should list all entries below a directory relative to it with parents first", func() {
+			target := filepath.Join(GinkgoT().TempDir(), "tree")
+			Expect(os.MkdirAll(filepath.Join(target, "sub"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(target, "one"), []byte("1"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(target, "sub", "two"), []byte("2"), 0644)).To(Succeed())
+
+			entries, err := provider.ListEntries(context.Background(), target)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(Equal([]string{"one", "sub", filepath.Join("sub", "two")}))
+		})
+
+		It("Should fail for missing directories", func() {
+			_, err := provider.ListEntries(context.Background(), filepath.Join(GinkgoT().TempDir(), "missing"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("CountEntries", func() {
+		It("Should count all entries below a directory without following symlinks", func() {
+			tmpDir := GinkgoT().TempDir()
+			target := filepath.Join(tmpDir, "tree")
+			Expect(os.MkdirAll(filepath.Join(target, "sub", "deeper"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(target, "one"), []byte("1"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(target, "sub", "two"), []byte("2"), 0644)).To(Succeed())
+
+			outside := filepath.Join(tmpDir, "outside")
+			Expect(os.Mkdir(outside, 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(outside, "skipped"), []byte("x"), 0644)).To(Succeed())
+			Expect(os.Symlink(outside, filepath.Join(target, "link"))).To(Succeed())
+
+			count, err := provider.CountEntries(context.Background(), target)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(count).To(Equal(5))
+
+			Expect(provider.Remove(context.Background(), target, true)).To(Succeed())
+			_, statErr := os.Stat(target)
+			Expect(os.IsNotExist(statErr)).To(BeTrue())
+			Expect(filepath.Join(outside, "skipped")).To(BeAnExistingFile())
+		})
+
+		It("Should fail for missing directories", func() {
+			_, err := provider.CountEntries(context.Background(), filepath.Join(GinkgoT().TempDir(), "missing"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Contents", func() {
+		It("Should read the current file content", func() {
+			target := filepath.Join(GinkgoT().TempDir(), "file")
+			Expect(os.WriteFile(target, []byte("hello world\n"), 0644)).To(Succeed())
+
+			contents, err := provider.Contents(context.Background(), target)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(contents)).To(Equal("hello world\n"))
+		})
+
+		It("Should fail for missing files", func() {
+			_, err := provider.Contents(context.Background(), filepath.Join(GinkgoT().TempDir(), "missing"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
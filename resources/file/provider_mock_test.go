@@ -43,6 +43,51 @@ func (m *MockFileProvider) EXPECT() *MockFileProviderMockRecorder {
 	return m.recorder
 }
 
+// Acl mocks base method.
+func (m *MockFileProvider) Acl(ctx context.Context, file string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Acl", ctx, file)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Acl indicates an expected call of Acl.
+func (mr *MockFileProviderMockRecorder) Acl(ctx, file any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Acl", reflect.TypeOf((*MockFileProvider)(nil).Acl), ctx, file)
+}
+
+// Contents mocks base method.
+func (m *MockFileProvider) Contents(ctx context.Context, file string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Contents", ctx, file)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Contents indicates an expected call of Contents.
+func (mr *MockFileProviderMockRecorder) Contents(ctx, file any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Contents", reflect.TypeOf((*MockFileProvider)(nil).Contents), ctx, file)
+}
+
+// CountEntries mocks base method.
+func (m *MockFileProvider) CountEntries(ctx context.Context, dir string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountEntries", ctx, dir)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountEntries indicates an expected call of CountEntries.
+func (mr *MockFileProviderMockRecorder) CountEntries(ctx, dir any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountEntries", reflect.TypeOf((*MockFileProvider)(nil).CountEntries), ctx, dir)
+}
+
 // CreateDirectory mocks base method.
 func (m *MockFileProvider) CreateDirectory(ctx context.Context, dir, owner, group, mode string) error {
 	m.ctrl.T.Helper()
@@ -57,6 +102,36 @@ func (mr *MockFileProviderMockRecorder) CreateDirectory(ctx, dir, owner, group,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDirectory", reflect.TypeOf((*MockFileProvider)(nil).CreateDirectory), ctx, dir, owner, group, mode)
 }
 
+// CreateParents mocks base method.
+func (m *MockFileProvider) CreateParents(ctx context.Context, path, owner, group, mode string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateParents", ctx, path, owner, group, mode)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateParents indicates an expected call of CreateParents.
+func (mr *MockFileProviderMockRecorder) CreateParents(ctx, path, owner, group, mode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateParents", reflect.TypeOf((*MockFileProvider)(nil).CreateParents), ctx, path, owner, group, mode)
+}
+
+// ListEntries mocks base method.
+func (m *MockFileProvider) ListEntries(ctx context.Context, dir string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntries", ctx, dir)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntries indicates an expected call of ListEntries.
+func (mr *MockFileProviderMockRecorder) ListEntries(ctx, dir any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockFileProvider)(nil).ListEntries), ctx, dir)
+}
+
 // Name mocks base method.
 func (m *MockFileProvider) Name() string {
 	m.ctrl.T.Helper()
@@ -85,6 +160,36 @@ func (mr *MockFileProviderMockRecorder) Remove(ctx, file, force any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockFileProvider)(nil).Remove), ctx, file, force)
 }
 
+// ResolveSources mocks base method.
+func (m *MockFileProvider) ResolveSources(ctx context.Context, sources []string) (string, []byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveSources", ctx, sources)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].([]byte)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ResolveSources indicates an expected call of ResolveSources.
+func (mr *MockFileProviderMockRecorder) ResolveSources(ctx, sources any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveSources", reflect.TypeOf((*MockFileProvider)(nil).ResolveSources), ctx, sources)
+}
+
+// SetAcl mocks base method.
+func (m *MockFileProvider) SetAcl(ctx context.Context, file string, acl []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAcl", ctx, file, acl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAcl indicates an expected call of SetAcl.
+func (mr *MockFileProviderMockRecorder) SetAcl(ctx, file, acl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAcl", reflect.TypeOf((*MockFileProvider)(nil).SetAcl), ctx, file, acl)
+}
+
 // SetAttributes mocks base method.
 func (m *MockFileProvider) SetAttributes(ctx context.Context, file, owner, group, mode string) error {
 	m.ctrl.T.Helper()
@@ -127,3 +232,17 @@ func (mr *MockFileProviderMockRecorder) Store(ctx, file, contents, source, owner
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Store", reflect.TypeOf((*MockFileProvider)(nil).Store), ctx, file, contents, source, owner, group, mode)
 }
+
+// ValidateContent mocks base method.
+func (m *MockFileProvider) ValidateContent(ctx context.Context, file, command string, contents []byte, source string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateContent", ctx, file, command, contents, source)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateContent indicates an expected call of ValidateContent.
+func (mr *MockFileProviderMockRecorder) ValidateContent(ctx, file, command, contents, source any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateContent", reflect.TypeOf((*MockFileProvider)(nil).ValidateContent), ctx, file, command, contents, source)
+}
@@ -5,21 +5,29 @@
 package fileresource
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io/fs"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"unicode/utf8"
 
 	"github.com/choria-io/ccm/internal/registry"
 	iu "github.com/choria-io/ccm/internal/util"
 	"github.com/choria-io/ccm/model"
 	"github.com/choria-io/ccm/resources/base"
 	"github.com/choria-io/ccm/resources/file/posix"
+	"github.com/pmezard/go-difflib/difflib"
 )
 
+var _ base.StatusReporter = (*Type)(nil)
+
 type Type struct {
 	*base.Base
 
@@ -89,10 +97,16 @@ func (t *Type) ApplyResource(ctx context.Context) (model.ResourceState, error) {
 		properties    = t.prop
 		noop          = t.mgr.NoopMode()
 		noopMessage   string
+		diff          string
 		err           error
 	)
 
-	initialStatus, err = p.Status(ctx, t.prop.Name)
+	initialStatus, err = t.status(ctx, p, t.prop)
+	if err != nil {
+		return nil, err
+	}
+
+	properties, err = t.resolveSources(ctx, p, properties)
 	if err != nil {
 		return nil, err
 	}
@@ -105,20 +119,43 @@ func (t *Type) ApplyResource(ctx context.Context) (model.ResourceState, error) {
 	switch {
 	case isStable:
 	// nothing to do
+	case properties.ManagesAcl() && initialStatus.Ensure == properties.Ensure && t.onlyAclDiffers(properties, initialStatus):
+		// the acl is set below along with the acl of files and directories that are created or updated
+		if noop {
+			t.log.Info("Skipping acl update as noop")
+			noopMessage = "Would have updated the acl"
+		}
+		refreshState = true
 	case properties.Ensure == model.FileEnsureDirectory:
 		if !noop {
+			err = t.createParents(ctx, p, properties)
+			if err != nil {
+				return nil, err
+			}
+
 			t.log.Info("Creating directory")
 			err = p.CreateDirectory(ctx, properties.Name, properties.Owner, properties.Group, properties.Mode)
 			if err != nil {
 				return nil, err
 			}
+
+			if properties.Recurse {
+				err = t.syncTree(ctx, p, properties, initialStatus.Metadata)
+				if err != nil {
+					return nil, err
+				}
+			}
 		} else {
 			t.log.Info("Skipping create directory as noop")
-			noopMessage = "Would have created directory"
+			noopMessage = directoryNoopMessage(properties, initialStatus)
 		}
 		refreshState = true
 	case properties.Ensure == model.EnsureAbsent && initialStatus.Ensure != model.EnsureAbsent:
 		// remove
+		if slices.Contains(t.mgr.ProtectedPaths(), properties.Name) {
+			return nil, fmt.Errorf("%w %s", model.ErrProtectedPath, properties.Name)
+		}
+
 		if !noop {
 			t.log.Info("Removing file due to ensure=absent", "force", properties.Force)
 			err = p.Remove(ctx, properties.Name, properties.Force)
@@ -127,7 +164,16 @@ func (t *Type) ApplyResource(ctx context.Context) (model.ResourceState, error) {
 			}
 		} else {
 			t.log.Info("Skipping remove as noop")
-			noopMessage = removeNoopMessage(initialStatus.Ensure, properties.Force)
+
+			entries := 0
+			if initialStatus.Ensure == model.FileEnsureDirectory && properties.Force {
+				entries, err = p.CountEntries(ctx, properties.Name)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			noopMessage = removeNoopMessage(initialStatus.Ensure, properties.Force, entries)
 		}
 		refreshState = true
 	case initialStatus.Ensure == model.FileEnsureDirectory && properties.Ensure == model.EnsurePresent:
@@ -150,25 +196,64 @@ func (t *Type) ApplyResource(ctx context.Context) (model.ResourceState, error) {
 		t.log.Debug("Creating file", "source", properties.Source, "working_dir", t.mgr.WorkingDirectory())
 
 		if !noop {
-			source := t.adjustedSource(properties)
-			err = p.Store(ctx, properties.Name, []byte(properties.Content()), source, properties.Owner, properties.Group, properties.Mode)
+			err = t.createParents(ctx, p, properties)
+			if err != nil {
+				return nil, err
+			}
+
+			contents, source, err := t.desiredContent(properties)
+			if err != nil {
+				return nil, err
+			}
+
+			if properties.ValidateCommand != "" {
+				t.log.Info("Validating content", "command", properties.ValidateCommand)
+				err = p.ValidateContent(ctx, properties.Name, properties.ValidateCommand, contents, source)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			err = p.Store(ctx, properties.Name, contents, source, properties.Owner, properties.Group, properties.Mode)
 			if err != nil {
 				t.log.Error(fmt.Sprintf("Could not store new file %v", err))
 				return nil, err
 			}
 		} else {
 			t.log.Info("Skipping create as noop")
-			if properties.ManagesContent() {
+			switch {
+			case properties.ManagesContent() && initialStatus.Ensure == model.EnsurePresent:
+				noopMessage = "Would have updated the file"
+				// content may be sensitive and events are stored and shipped elsewhere so diffs are opt in
+				if properties.ShowsDiff() {
+					diff, err = t.contentDiff(ctx, p, properties)
+					if err != nil {
+						return nil, err
+					}
+				}
+			case properties.ManagesContent():
 				noopMessage = "Would have created the file"
-			} else {
+			default:
 				noopMessage = "Would have created an empty file with requested attributes"
 			}
+
+			if properties.ValidateCommand != "" {
+				noopMessage = fmt.Sprintf("%s, would validate the content using %q", noopMessage, properties.ValidateCommand)
+			}
 		}
 		refreshState = true
 	}
 
+	if refreshState && !noop && properties.ManagesAcl() {
+		t.log.Info("Setting acl", "acl", properties.Acl)
+		err = p.SetAcl(ctx, properties.Name, properties.Acl)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if refreshState && !noop {
-		finalStatus, err = p.Status(ctx, properties.Name)
+		finalStatus, err = t.status(ctx, p, properties)
 		if err != nil {
 			return nil, err
 		}
@@ -185,6 +270,8 @@ func (t *Type) ApplyResource(ctx context.Context) (model.ResourceState, error) {
 	}
 
 	t.FinalizeState(finalStatus, noop, noopMessage, refreshState, isStable, false)
+	finalStatus.Diff = diff
+	t.ClassifyChange(finalStatus, initialStatus.Ensure != model.EnsureAbsent)
 
 	return finalStatus, nil
 }
@@ -193,6 +280,34 @@ func (t *Type) ApplyResource(ctx context.Context) (model.ResourceState, error) {
 // a human-readable reason describing the mismatch when stable is false, suitable
 // for inclusion in error messages.
 func (t *Type) isDesiredState(properties *model.FileResourceProperties, state *model.FileState) (bool, string, error) {
+	stable, reason, err := t.isDesiredStateExceptAcl(properties, state)
+	if err != nil || !stable || !properties.ManagesAcl() {
+		return stable, reason, err
+	}
+
+	desired, err := properties.NormalizedAcl()
+	if err != nil {
+		return false, "", err
+	}
+
+	// both are sorted so the order entries were given in does not matter
+	if !slices.Equal(desired, state.Metadata.Acl) {
+		t.log.Debug("ACL does not match", "state", state.Metadata.Acl, "requested", desired)
+		return false, fmt.Sprintf("acl mismatch: state=%s requested=%s", strings.Join(state.Metadata.Acl, ","), strings.Join(desired, ",")), nil
+	}
+
+	return true, "", nil
+}
+
+// onlyAclDiffers reports whether state matches properties in everything but the acl
+func (t *Type) onlyAclDiffers(properties *model.FileResourceProperties, state *model.FileState) bool {
+	stable, _, err := t.isDesiredStateExceptAcl(properties, state)
+
+	return err == nil && stable
+}
+
+// isDesiredStateExceptAcl is isDesiredState without comparing the acl
+func (t *Type) isDesiredStateExceptAcl(properties *model.FileResourceProperties, state *model.FileState) (bool, string, error) {
 	if properties.Ensure == model.EnsureAbsent {
 		t.log.Debug("Checking if file is absent due to ensure=absent", "ensure", state.Ensure)
 		if state.Ensure == model.EnsureAbsent {
@@ -213,22 +328,29 @@ func (t *Type) isDesiredState(properties *model.FileResourceProperties, state *m
 			contentChecksum string
 			err             error
 		)
-		if properties.Source != "" {
-			path := t.adjustedSource(properties)
-			contentChecksum, err = iu.Sha256HashFile(path)
-			if err != nil {
-				return false, "", err
-			}
+
+		contents, source, err := t.desiredContent(properties)
+		if err != nil {
+			return false, "", err
+		}
+
+		if source != "" {
+			contentChecksum, err = iu.Sha256HashFile(source)
 		} else {
-			contentChecksum, err = iu.Sha256HashBytes([]byte(properties.Content()))
-			if err != nil {
-				return false, "", err
-			}
+			contentChecksum, err = iu.Sha256HashBytes(contents)
+		}
+		if err != nil {
+			return false, "", err
 		}
 
-		if contentChecksum != meta.Checksum {
-			t.log.Debug("Content does not match", "requested", contentChecksum, "state", meta.Checksum)
-			return false, fmt.Sprintf("content checksum mismatch: state=%s requested=%s", meta.Checksum, contentChecksum), nil
+		stateChecksum, err := t.currentChecksum(properties, meta)
+		if err != nil {
+			return false, "", err
+		}
+
+		if contentChecksum != stateChecksum {
+			t.log.Debug("Content does not match", "requested", contentChecksum, "state", stateChecksum)
+			return false, fmt.Sprintf("content checksum mismatch: state=%s requested=%s", stateChecksum, contentChecksum), nil
 		}
 	}
 
@@ -242,29 +364,258 @@ func (t *Type) isDesiredState(properties *model.FileResourceProperties, state *m
 		return false, fmt.Sprintf("group mismatch: state=%s requested=%s", meta.Group, properties.Group), nil
 	}
 
-	desiredMode := properties.Mode
-	if properties.Ensure == model.FileEnsureDirectory {
-		parsed, err := strconv.ParseUint(desiredMode, 8, 32)
-		if err == nil {
-			desiredMode = fmt.Sprintf("%04o", iu.DirectoryMode(os.FileMode(parsed)))
-		}
-	}
-
+	desiredMode := desiredMode(properties.Mode, properties.Ensure == model.FileEnsureDirectory)
 	if meta.Mode != desiredMode {
 		t.log.Debug("Mode does not match", "state", meta.Mode, "requested", desiredMode)
 		return false, fmt.Sprintf("mode mismatch: state=%s requested=%s", meta.Mode, desiredMode), nil
 	}
 
+	if properties.Recurse && (len(meta.Changed) > 0 || len(meta.Purged) > 0) {
+		t.log.Debug("Directory entries do not match", "changed", meta.Changed, "purged", meta.Purged)
+		return false, fmt.Sprintf("directory entries mismatch: changed=%d purged=%d", len(meta.Changed), len(meta.Purged)), nil
+	}
+
 	return true, "", nil
 }
 
+// desiredMode is mode as reported by the provider, directories get execute bits wherever mode has read bits
+func desiredMode(mode string, directory bool) string {
+	if !directory {
+		return mode
+	}
+
+	parsed, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil {
+		return mode
+	}
+
+	return fmt.Sprintf("%04o", iu.DirectoryMode(os.FileMode(parsed)))
+}
+
 func (t *Type) Info(ctx context.Context) (any, error) {
 	_, err := t.SelectProvider()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", t.String(), err)
 	}
 
-	return t.provider.(FileProvider).Status(ctx, t.prop.Name)
+	return t.status(ctx, t.provider.(FileProvider), t.prop)
+}
+
+// CurrentState reports the current state of the resource without making any changes
+func (t *Type) CurrentState(ctx context.Context) (model.ResourceState, error) {
+	state, err := t.status(ctx, t.provider.(FileProvider), t.prop)
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// status is the status of the file, including its acl when the acl is managed
+func (t *Type) status(ctx context.Context, p FileProvider, properties *model.FileResourceProperties) (*model.FileState, error) {
+	state, err := p.Status(ctx, properties.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if properties.Recurse {
+		err = t.treeStatus(ctx, p, properties, state)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !properties.ManagesAcl() || state.Ensure == model.EnsureAbsent {
+		return state, nil
+	}
+
+	state.Metadata.Acl, err = p.Acl(ctx, properties.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// treeStatus records every entry of the source directory as changed or stable depending on whether the matching
+// entry in the directory has the same type, content and attributes. Entries only found in the directory are recorded
+// as purged when purging, entries of purged directories are removed with them and are not listed.
+func (t *Type) treeStatus(ctx context.Context, p FileProvider, properties *model.FileResourceProperties, state *model.FileState) error {
+	meta := state.Metadata
+	source := t.adjustedSource(properties)
+	if !iu.IsDirectory(source) {
+		return fmt.Errorf("source %s is not a directory", source)
+	}
+
+	known := map[string]bool{}
+
+	err := filepath.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == source {
+			return nil
+		}
+
+		rel, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		known[rel] = true
+
+		if !d.IsDir() && !d.Type().IsRegular() {
+			t.log.Warn("Skipping source entry that is not a file or directory", "entry", path)
+			return nil
+		}
+
+		stable, err := t.isStableTreeEntry(ctx, p, properties, path, filepath.Join(properties.Name, rel), d.IsDir())
+		if err != nil {
+			return err
+		}
+
+		if stable {
+			meta.Stable = append(meta.Stable, rel)
+		} else {
+			meta.Changed = append(meta.Changed, rel)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !properties.Purge || state.Ensure != model.FileEnsureDirectory {
+		return nil
+	}
+
+	entries, err := p.ListEntries(ctx, properties.Name)
+	if err != nil {
+		return err
+	}
+
+	for _, rel := range entries {
+		if known[rel] {
+			continue
+		}
+
+		// parents are listed before their entries
+		if len(meta.Purged) > 0 && strings.HasPrefix(rel, meta.Purged[len(meta.Purged)-1]+string(filepath.Separator)) {
+			continue
+		}
+
+		meta.Purged = append(meta.Purged, rel)
+	}
+
+	return nil
+}
+
+// isStableTreeEntry determines if target has the type and content of the source entry and the attributes of the resource
+func (t *Type) isStableTreeEntry(ctx context.Context, p FileProvider, properties *model.FileResourceProperties, source string, target string, directory bool) (bool, error) {
+	state, err := p.Status(ctx, target)
+	if err != nil {
+		return false, err
+	}
+
+	switch {
+	case directory && state.Ensure != model.FileEnsureDirectory:
+		return false, nil
+	case !directory && state.Ensure != model.EnsurePresent:
+		return false, nil
+	case !directory:
+		checksum, err := iu.Sha256HashFile(source)
+		if err != nil {
+			return false, err
+		}
+		if checksum != state.Metadata.Checksum {
+			return false, nil
+		}
+	}
+
+	meta := state.Metadata
+
+	return iu.UserIDMatches(properties.Owner, meta.Owner) && iu.GroupIDMatches(properties.Group, meta.Group) && meta.Mode == desiredMode(properties.Mode, directory), nil
+}
+
+// syncTree copies the changed entries of the source directory into the directory and removes purged entries, an
+// entry of a different type is only replaced when purging
+func (t *Type) syncTree(ctx context.Context, p FileProvider, properties *model.FileResourceProperties, meta *model.FileMetadata) error {
+	source := t.adjustedSource(properties)
+
+	for _, rel := range meta.Changed {
+		src := filepath.Join(source, rel)
+		target := filepath.Join(properties.Name, rel)
+
+		stat, err := os.Stat(src)
+		if err != nil {
+			return err
+		}
+
+		current, err := p.Status(ctx, target)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case stat.IsDir() && current.Ensure == model.EnsurePresent, !stat.IsDir() && current.Ensure == model.FileEnsureDirectory:
+			if !properties.Purge {
+				return fmt.Errorf("%s exists as a different type than %s, set 'purge: true' to replace it", target, src)
+			}
+
+			t.log.Info("Removing entry to replace it with an entry of a different type", "entry", target)
+			err = p.Remove(ctx, target, true)
+			if err != nil {
+				return err
+			}
+		}
+
+		if stat.IsDir() {
+			t.log.Info("Creating directory entry", "entry", target)
+			err = p.CreateDirectory(ctx, target, properties.Owner, properties.Group, properties.Mode)
+		} else {
+			t.log.Info("Copying file entry", "entry", target, "source", src)
+			err = p.Store(ctx, target, nil, src, properties.Owner, properties.Group, properties.Mode)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, rel := range meta.Purged {
+		target := filepath.Join(properties.Name, rel)
+
+		if slices.Contains(t.mgr.ProtectedPaths(), target) {
+			return fmt.Errorf("%w %s", model.ErrProtectedPath, target)
+		}
+
+		t.log.Info("Purging entry", "entry", target)
+		err := p.Remove(ctx, target, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createParents creates missing parent directories when requested, existing directories are not changed
+func (t *Type) createParents(ctx context.Context, p FileProvider, properties *model.FileResourceProperties) error {
+	if !properties.ManageParents {
+		return nil
+	}
+
+	owner, group, mode := properties.ParentAttributes()
+
+	created, err := p.CreateParents(ctx, properties.Name, owner, group, mode)
+	if err != nil {
+		return fmt.Errorf("could not create parent directories: %w", err)
+	}
+
+	if len(created) > 0 {
+		t.log.Info("Created parent directories", "directories", created, "owner", owner, "group", group, "mode", mode)
+	}
+
+	return nil
 }
 
 func (t *Type) validate() error {
@@ -279,26 +630,40 @@ func (t *Type) validate() error {
 
 	// Mode may legitimately be empty when ensure=absent; the property
 	// validator below enforces non-empty for other ensure values.
-	if t.prop.Mode != "" {
-		mode := t.prop.Mode
+	err = validateMode("mode", t.prop.Mode)
+	if err != nil {
+		return err
+	}
 
-		// Strip common octal prefixes (0o, 0O)
-		mode = strings.TrimPrefix(mode, "0o")
-		mode = strings.TrimPrefix(mode, "0O")
+	err = validateMode("parent_mode", t.prop.ParentMode)
+	if err != nil {
+		return err
+	}
 
-		// Parse as octal number
-		parsed, err := strconv.ParseUint(mode, 8, 32)
-		if err != nil {
-			return fmt.Errorf("mode %q is not a valid octal number: %w", t.prop.Mode, err)
-		}
+	return t.prop.Validate()
+}
 
-		// Validate it's within the valid Unix permission range (0-0777)
-		if parsed > 0o777 {
-			return fmt.Errorf("mode %q exceeds maximum value 0777", t.prop.Mode)
-		}
+func validateMode(field string, mode string) error {
+	if mode == "" {
+		return nil
 	}
 
-	return t.prop.Validate()
+	// Strip common octal prefixes (0o, 0O)
+	trimmed := strings.TrimPrefix(mode, "0o")
+	trimmed = strings.TrimPrefix(trimmed, "0O")
+
+	// Parse as octal number
+	parsed, err := strconv.ParseUint(trimmed, 8, 32)
+	if err != nil {
+		return fmt.Errorf("%s %q is not a valid octal number: %w", field, mode, err)
+	}
+
+	// Validate it's within the valid Unix permission range (0-0777)
+	if parsed > 0o777 {
+		return fmt.Errorf("%s %q exceeds maximum value 0777", field, mode)
+	}
+
+	return nil
 }
 
 func (t *Type) providerUnlocked() string {
@@ -323,7 +688,7 @@ func (t *Type) selectProviderUnlocked() error {
 		return nil
 	}
 
-	selected, err := registry.FindSuitableProvider(model.FileTypeName, t.prop.Provider, t.Facts, t.prop, t.log, nil)
+	selected, err := registry.FindSuitableProvider(model.FileTypeName, t.prop.Provider, t.Facts, t.prop, t.log, nil, t.mgr)
 	if err != nil {
 		return err
 	}
@@ -352,10 +717,25 @@ func (t *Type) SelectProvider() (string, error) {
 	return t.providerUnlocked(), nil
 }
 
-func removeNoopMessage(currentEnsure string, force bool) string {
+func directoryNoopMessage(properties *model.FileResourceProperties, initialStatus *model.FileState) string {
+	meta := initialStatus.Metadata
+
+	switch {
+	case !properties.Recurse:
+		return "Would have created directory"
+	case initialStatus.Ensure != model.FileEnsureDirectory:
+		return fmt.Sprintf("Would have created directory with %d entries", len(meta.Changed))
+	case len(meta.Changed) > 0 || len(meta.Purged) > 0:
+		return fmt.Sprintf("Would have updated %d and purged %d entries", len(meta.Changed), len(meta.Purged))
+	default:
+		return "Would have created directory"
+	}
+}
+
+func removeNoopMessage(currentEnsure string, force bool, entries int) string {
 	switch {
 	case currentEnsure == model.FileEnsureDirectory && force:
-		return "Would have recursively removed the directory"
+		return fmt.Sprintf("Would have recursively removed the directory and %d entries", entries)
 	case currentEnsure == model.FileEnsureDirectory:
 		return "Would have removed the directory"
 	default:
@@ -364,11 +744,150 @@ func removeNoopMessage(currentEnsure string, force bool) string {
 }
 
 func (t *Type) adjustedSource(properties *model.FileResourceProperties) string {
-	source := properties.Source
+	return t.adjustedSourcePath(properties.Source)
+}
+
+// desiredContent returns the content the file should have, either as a local source file or as bytes. Normalized
+// content is always returned as bytes so sources are normalized the same way as content.
+func (t *Type) desiredContent(properties *model.FileResourceProperties) (contents []byte, source string, err error) {
+	source = t.adjustedSource(properties)
+
+	if source == "" || !properties.NormalizesContent() {
+		contents, err = properties.ContentBytes()
+		if err != nil {
+			return nil, "", err
+		}
+	} else {
+		contents, err = os.ReadFile(source)
+		if err != nil {
+			return nil, "", err
+		}
+		source = ""
+	}
+
+	if properties.NormalizesContent() {
+		contents = properties.NormalizeContent(contents)
+	}
+
+	return contents, source, nil
+}
+
+// contentDiff is a unified diff between the current content of the file and the content it would be changed to,
+// binary content is not diffed
+func (t *Type) contentDiff(ctx context.Context, p FileProvider, properties *model.FileResourceProperties) (string, error) {
+	current, err := p.Contents(ctx, properties.Name)
+	if err != nil {
+		return "", err
+	}
+
+	desired, source, err := t.desiredContent(properties)
+	if err != nil {
+		return "", err
+	}
+
+	if source != "" {
+		desired, err = os.ReadFile(source)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if bytes.Equal(current, desired) {
+		return "", nil
+	}
+
+	if isBinary(current) || isBinary(desired) {
+		return "binary file would change", nil
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(current)),
+		B:        difflib.SplitLines(string(desired)),
+		FromFile: properties.Name,
+		ToFile:   properties.Name,
+		Context:  3,
+	})
+}
 
+// isBinary determines if content should be treated as binary, content that is not valid UTF-8 or that holds NUL
+// bytes is binary
+func isBinary(content []byte) bool {
+	return bytes.IndexByte(content, 0) != -1 || !utf8.Valid(content)
+}
+
+// currentChecksum is the checksum of the current content, with normalization the current content is normalized
+// the same way as the desired content before calculating the checksum
+func (t *Type) currentChecksum(properties *model.FileResourceProperties, meta *model.FileMetadata) (string, error) {
+	if !properties.NormalizesContent() {
+		return meta.Checksum, nil
+	}
+
+	current, err := os.ReadFile(properties.Name)
+	if err != nil {
+		return "", err
+	}
+
+	return iu.Sha256HashBytes(properties.NormalizeContent(current))
+}
+
+// adjustedSourcePath resolves a relative local source against the working directory
+func (t *Type) adjustedSourcePath(source string) string {
 	if source != "" && !filepath.IsAbs(source) && t.mgr.WorkingDirectory() != "" {
-		source = filepath.Join(t.mgr.WorkingDirectory(), properties.Source)
+		source = filepath.Join(t.mgr.WorkingDirectory(), source)
 	}
 
 	return source
 }
+
+// resolveSources selects the first of the sources that resolves and returns a copy of properties using it as the
+// source or content, the content is used when no source resolves
+func (t *Type) resolveSources(ctx context.Context, p FileProvider, properties *model.FileResourceProperties) (*model.FileResourceProperties, error) {
+	if len(properties.Sources) == 0 || properties.Ensure != model.EnsurePresent {
+		return properties, nil
+	}
+
+	sources := make([]string, len(properties.Sources))
+	for i, source := range properties.Sources {
+		if model.IsUrlSource(source) {
+			sources[i] = source
+		} else {
+			sources[i] = t.adjustedSourcePath(source)
+		}
+	}
+
+	selected, contents, err := p.ResolveSources(ctx, sources)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := *properties
+	resolved.Sources = nil
+
+	switch {
+	case selected == "" && properties.Contents == nil:
+		return nil, fmt.Errorf("none of the sources could be resolved and no content is set")
+
+	case selected == "":
+		t.log.Info("Using content as none of the sources could be resolved")
+
+	case model.IsUrlSource(selected):
+		uri, err := url.Parse(selected)
+		if err != nil {
+			return nil, err
+		}
+		t.log.Info("Using content from source", "source", iu.RedactUrlCredentials(uri))
+
+		content := string(contents)
+		resolved.Contents = &content
+		resolved.ContentEncoding = model.FileContentEncodingPlain
+
+	default:
+		t.log.Info("Using content from source", "source", selected)
+
+		resolved.Source = selected
+		resolved.Contents = nil
+		resolved.ContentEncoding = ""
+	}
+
+	return &resolved, nil
+}
@@ -10,6 +10,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"os"
+	"os/user"
 	"path/filepath"
 	"testing"
 
@@ -20,6 +21,7 @@ import (
 	"github.com/choria-io/ccm/internal/registry"
 	"github.com/choria-io/ccm/model"
 	"github.com/choria-io/ccm/model/modelmocks"
+	"github.com/choria-io/ccm/resources/file/posix"
 )
 
 func TestFileResource(t *testing.T) {
@@ -120,6 +122,27 @@ var _ = Describe("File Type", func() {
 			Expect(err.Error()).To(ContainSubstring("exceeds maximum value"))
 		})
 
+		It("Should validate base64 content decodes", func(ctx context.Context) {
+			props := model.FileResourceProperties{
+				CommonResourceProperties: model.CommonResourceProperties{
+					Name:   "/tmp/foo",
+					Ensure: model.EnsurePresent,
+				},
+				Owner:           "root",
+				Group:           "root",
+				Mode:            "0644",
+				ContentEncoding: model.FileContentEncodingBase64,
+				Contents:        stringPtr("not base64!"),
+			}
+
+			_, err := New(ctx, mgr, props)
+			Expect(err).To(MatchError(ContainSubstring("could not decode base64 content")))
+
+			props.Contents = stringPtr("AAEC\n/w==\n")
+			_, err = New(ctx, mgr, props)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
 		DescribeTable("valid mode formats",
 			func(ctx context.Context, mode string) {
 				_, err := New(ctx, mgr, model.FileResourceProperties{
@@ -438,9 +461,98 @@ var _ = Describe("File Type", func() {
 					result, err := file.Apply(ctx)
 					Expect(err).ToNot(HaveOccurred())
 					Expect(result.Changed).To(BeTrue())
+					Expect(result.Corrective).To(BeFalse())
 					Expect(result.RequestedEnsure).To(Equal(model.EnsurePresent))
 				})
 
+				It("Should validate content before storing it", func(ctx context.Context) {
+					file.prop.ValidateCommand = "/usr/sbin/nginx -t -c %{path}"
+					initialState := &model.FileState{
+						CommonResourceState: model.CommonResourceState{Ensure: model.EnsureAbsent},
+						Metadata:            &model.FileMetadata{},
+					}
+					finalState := &model.FileState{
+						CommonResourceState: model.CommonResourceState{Ensure: model.EnsurePresent},
+						Metadata: &model.FileMetadata{
+							Owner:    "root",
+							Group:    "root",
+							Mode:     "0644",
+							Checksum: checksum("file content"),
+						},
+					}
+
+					provider.EXPECT().Status(gomock.Any(), "/tmp/testfile").Return(initialState, nil)
+					gomock.InOrder(
+						provider.EXPECT().ValidateContent(gomock.Any(), "/tmp/testfile", "/usr/sbin/nginx -t -c %{path}", []byte("file content"), "").Return(nil),
+						provider.EXPECT().Store(gomock.Any(), "/tmp/testfile", []byte("file content"), "", "root", "root", "0644").Return(nil),
+					)
+					provider.EXPECT().Status(gomock.Any(), "/tmp/testfile").Return(finalState, nil)
+
+					result, err := file.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.Errors).To(BeEmpty())
+					Expect(result.Changed).To(BeTrue())
+				})
+
+				It("Should not store content that fails validation", func(ctx context.Context) {
+					file.prop.ValidateCommand = "/usr/sbin/nginx -t -c %{path}"
+					initialState := &model.FileState{
+						CommonResourceState: model.CommonResourceState{Ensure: model.EnsureAbsent},
+						Metadata:            &model.FileMetadata{},
+					}
+
+					provider.EXPECT().Status(gomock.Any(), "/tmp/testfile").Return(initialState, nil)
+					provider.EXPECT().ValidateContent(gomock.Any(), "/tmp/testfile", "/usr/sbin/nginx -t -c %{path}", []byte("file content"), "").Return(fmt.Errorf("content failed validation: syntax error"))
+					// No Store call expected
+
+					result, err := file.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.Errors).To(ContainElement(ContainSubstring("content failed validation: syntax error")))
+				})
+
+				It("Should create missing parents before the file when requested", func(ctx context.Context) {
+					file.prop.ManageParents = true
+					initialState := &model.FileState{
+						CommonResourceState: model.CommonResourceState{Ensure: model.EnsureAbsent},
+						Metadata:            &model.FileMetadata{},
+					}
+					finalState := &model.FileState{
+						CommonResourceState: model.CommonResourceState{Ensure: model.EnsurePresent},
+						Metadata: &model.FileMetadata{
+							Owner:    "root",
+							Group:    "root",
+							Mode:     "0644",
+							Checksum: checksum("file content"),
+						},
+					}
+
+					provider.EXPECT().Status(gomock.Any(), "/tmp/testfile").Return(initialState, nil)
+					gomock.InOrder(
+						provider.EXPECT().CreateParents(gomock.Any(), "/tmp/testfile", "root", "root", "0644").Return(nil, nil),
+						provider.EXPECT().Store(gomock.Any(), "/tmp/testfile", []byte("file content"), "", "root", "root", "0644").Return(nil),
+					)
+					provider.EXPECT().Status(gomock.Any(), "/tmp/testfile").Return(finalState, nil)
+
+					result, err := file.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.Changed).To(BeTrue())
+				})
+
+				It("Should fail when parents cannot be created", func(ctx context.Context) {
+					file.prop.ManageParents = true
+					initialState := &model.FileState{
+						CommonResourceState: model.CommonResourceState{Ensure: model.EnsureAbsent},
+						Metadata:            &model.FileMetadata{},
+					}
+
+					provider.EXPECT().Status(gomock.Any(), "/tmp/testfile").Return(initialState, nil)
+					provider.EXPECT().CreateParents(gomock.Any(), "/tmp/testfile", "root", "root", "0644").Return(nil, fmt.Errorf("permission denied"))
+
+					event, err := file.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(event.Errors).To(ContainElement(ContainSubstring("could not create parent directories: permission denied")))
+				})
+
 				It("Should update file when content differs", func(ctx context.Context) {
 					initialState := &model.FileState{
 						CommonResourceState: model.CommonResourceState{Ensure: model.EnsurePresent},
@@ -468,6 +580,7 @@ var _ = Describe("File Type", func() {
 					result, err := file.Apply(ctx)
 					Expect(err).ToNot(HaveOccurred())
 					Expect(result.Changed).To(BeTrue())
+					Expect(result.Corrective).To(BeTrue())
 				})
 
 				It("Should update file when owner differs", func(ctx context.Context) {
@@ -499,6 +612,41 @@ var _ = Describe("File Type", func() {
 					Expect(result.Changed).To(BeTrue())
 				})
 
+				It("Should store decoded base64 content", func(ctx context.Context) {
+					file.prop.ContentEncoding = model.FileContentEncodingBase64
+					file.prop.Contents = stringPtr("AAEC/w==")
+					binary := []byte{0x00, 0x01, 0x02, 0xff}
+
+					initialState := &model.FileState{
+						CommonResourceState: model.CommonResourceState{Ensure: model.EnsureAbsent},
+						Metadata:            &model.FileMetadata{},
+					}
+					finalState := &model.FileState{
+						CommonResourceState: model.CommonResourceState{Ensure: model.EnsurePresent},
+						Metadata: &model.FileMetadata{
+							Owner:    "root",
+							Group:    "root",
+							Mode:     "0644",
+							Checksum: checksum(string(binary)),
+						},
+					}
+
+					provider.EXPECT().Status(gomock.Any(), "/tmp/testfile").Return(initialState, nil)
+					provider.EXPECT().Store(gomock.Any(), "/tmp/testfile", binary, "", "root", "root", "0644").Return(nil)
+					provider.EXPECT().Status(gomock.Any(), "/tmp/testfile").Return(finalState, nil)
+
+					result, err := file.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.Changed).To(BeTrue())
+
+					// the decoded content is compared so the stored file is stable
+					provider.EXPECT().Status(gomock.Any(), "/tmp/testfile").Return(finalState, nil)
+
+					result, err = file.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.Changed).To(BeFalse())
+				})
+
 				It("Should not change when file already matches", func(ctx context.Context) {
 					state := &model.FileState{
 						CommonResourceState: model.CommonResourceState{Ensure: model.EnsurePresent},
@@ -713,6 +861,241 @@ var _ = Describe("File Type", func() {
 				})
 			})
 
+			Context("with sources", func() {
+				var sourceFile string
+				var absentState *model.FileState
+
+				BeforeEach(func() {
+					sourceFile = filepath.Join(GinkgoT().TempDir(), "source.txt")
+					Expect(os.WriteFile(sourceFile, []byte("source content"), 0644)).To(Succeed())
+
+					file.prop.Contents = nil
+					file.prop.Sources = []string{"/nonexistent/source.txt", sourceFile}
+					absentState = &model.FileState{
+						CommonResourceState: model.CommonResourceState{Ensure: model.EnsureAbsent},
+						Metadata:            &model.FileMetadata{},
+					}
+				})
+
+				finalState := func(content string) *model.FileState {
+					return &model.FileState{
+						CommonResourceState: model.CommonResourceState{Ensure: model.EnsurePresent},
+						Metadata: &model.FileMetadata{
+							Owner:    "root",
+							Group:    "root",
+							Mode:     "0644",
+							Checksum: checksum(content),
+						},
+					}
+				}
+
+				It("Should fall through to the first local source that resolves", func(ctx context.Context) {
+					provider.EXPECT().Status(gomock.Any(), "/tmp/testfile").Return(absentState, nil)
+					provider.EXPECT().ResolveSources(gomock.Any(), []string{"/nonexistent/source.txt", sourceFile}).Return(sourceFile, nil, nil)
+					provider.EXPECT().Store(gomock.Any(), "/tmp/testfile", []byte{}, sourceFile, "root", "root", "0644").Return(nil)
+					provider.EXPECT().Status(gomock.Any(), "/tmp/testfile").Return(finalState("source content"), nil)
+
+					result, err := file.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.Changed).To(BeTrue())
+					Expect(file.prop.Sources).To(HaveLen(2))
+				})
+
+				It("Should be stable when the file matches the selected source", func(ctx context.Context) {
+					provider.EXPECT().Status(gomock.Any(), "/tmp/testfile").Return(finalState("source content"), nil)
+					provider.EXPECT().ResolveSources(gomock.Any(), gomock.Any()).Return(sourceFile, nil, nil)
+
+					result, err := file.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.Changed).To(BeFalse())
+				})
+
+				It("Should store the content fetched from a URL source", func(ctx context.Context) {
+					file.prop.Sources = []string{"https://example.net/motd"}
+
+					provider.EXPECT().Status(gomock.Any(), "/tmp/testfile").Return(absentState, nil)
+					provider.EXPECT().ResolveSources(gomock.Any(), []string{"https://example.net/motd"}).Return("https://example.net/motd", []byte("url content"), nil)
+					provider.EXPECT().Store(gomock.Any(), "/tmp/testfile", []byte("url content"), "", "root", "root", "0644").Return(nil)
+					provider.EXPECT().Status(gomock.Any(), "/tmp/testfile").Return(finalState("url content"), nil)
+
+					result, err := file.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.Changed).To(BeTrue())
+				})
+
+				It("Should fall back to content when no source resolves", func(ctx context.Context) {
+					file.prop.Contents = stringPtr("fallback content")
+
+					provider.EXPECT().Status(gomock.Any(), "/tmp/testfile").Return(absentState, nil)
+					provider.EXPECT().ResolveSources(gomock.Any(), gomock.Any()).Return("", nil, nil)
+					provider.EXPECT().Store(gomock.Any(), "/tmp/testfile", []byte("fallback content"), "", "root", "root", "0644").Return(nil)
+					provider.EXPECT().Status(gomock.Any(), "/tmp/testfile").Return(finalState("fallback content"), nil)
+
+					result, err := file.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.Changed).To(BeTrue())
+				})
+
+				It("Should fail when no source resolves and no content is set", func(ctx context.Context) {
+					provider.EXPECT().Status(gomock.Any(), "/tmp/testfile").Return(absentState, nil)
+					provider.EXPECT().ResolveSources(gomock.Any(), gomock.Any()).Return("", nil, nil)
+
+					result, err := file.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.Errors).To(ContainElement(ContainSubstring("none of the sources could be resolved and no content is set")))
+				})
+			})
+
+			Context("with content normalization", func() {
+				var target string
+
+				BeforeEach(func() {
+					target = filepath.Join(GinkgoT().TempDir(), "motd")
+					file.prop.Name = target
+				})
+
+				presentState := func(content string) *model.FileState {
+					return &model.FileState{
+						CommonResourceState: model.CommonResourceState{Ensure: model.EnsurePresent},
+						Metadata: &model.FileMetadata{
+							Owner:    "root",
+							Group:    "root",
+							Mode:     "0644",
+							Checksum: checksum(content),
+						},
+					}
+				}
+
+				It("Should treat CRLF line endings as matching LF content", func(ctx context.Context) {
+					file.prop.ContentNormalization = model.FileContentNormalizationLF
+					file.prop.Contents = stringPtr("line one\nline two\n")
+					Expect(os.WriteFile(target, []byte("line one\r\nline two\r\n"), 0644)).To(Succeed())
+
+					provider.EXPECT().Status(gomock.Any(), target).Return(presentState("line one\r\nline two\r\n"), nil)
+
+					result, err := file.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.Changed).To(BeFalse())
+				})
+
+				It("Should treat trailing whitespace as matching", func(ctx context.Context) {
+					file.prop.ContentNormalization = model.FileContentNormalizationTrimTrailing
+					file.prop.Contents = stringPtr("line one  \r\nline two\t\n")
+					Expect(os.WriteFile(target, []byte("line one\nline two \n"), 0644)).To(Succeed())
+
+					provider.EXPECT().Status(gomock.Any(), target).Return(presentState("line one\nline two \n"), nil)
+
+					result, err := file.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.Changed).To(BeFalse())
+				})
+
+				It("Should detect trailing whitespace differences without trim-trailing", func(ctx context.Context) {
+					file.prop.ContentNormalization = model.FileContentNormalizationLF
+					file.prop.Contents = stringPtr("line one\r\n")
+					Expect(os.WriteFile(target, []byte("line one \n"), 0644)).To(Succeed())
+
+					provider.EXPECT().Status(gomock.Any(), target).Return(presentState("line one \n"), nil)
+					provider.EXPECT().Store(gomock.Any(), target, []byte("line one\n"), "", "root", "root", "0644").DoAndReturn(func(_ context.Context, name string, contents []byte, _ string, _ string, _ string, _ string) error {
+						return os.WriteFile(name, contents, 0644)
+					})
+					provider.EXPECT().Status(gomock.Any(), target).Return(presentState("line one\n"), nil)
+
+					result, err := file.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.Changed).To(BeTrue())
+				})
+
+				It("Should store the normalized form of a source", func(ctx context.Context) {
+					source := filepath.Join(GinkgoT().TempDir(), "source.txt")
+					Expect(os.WriteFile(source, []byte("from source \r\n"), 0644)).To(Succeed())
+
+					file.prop.ContentNormalization = model.FileContentNormalizationTrimTrailing
+					file.prop.Contents = nil
+					file.prop.Source = source
+
+					absentState := &model.FileState{
+						CommonResourceState: model.CommonResourceState{Ensure: model.EnsureAbsent},
+						Metadata:            &model.FileMetadata{},
+					}
+
+					provider.EXPECT().Status(gomock.Any(), target).Return(absentState, nil)
+					provider.EXPECT().Store(gomock.Any(), target, []byte("from source\n"), "", "root", "root", "0644").DoAndReturn(func(_ context.Context, name string, contents []byte, _ string, _ string, _ string, _ string) error {
+						return os.WriteFile(name, contents, 0644)
+					})
+					provider.EXPECT().Status(gomock.Any(), target).Return(presentState("from source\n"), nil)
+
+					result, err := file.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.Changed).To(BeTrue())
+				})
+			})
+
+			Context("with an acl", func() {
+				var state *model.FileState
+
+				BeforeEach(func() {
+					file.prop.Acl = []string{"u:deploy:rwx", "g:admins:rx"}
+					state = &model.FileState{
+						CommonResourceState: model.CommonResourceState{Ensure: model.EnsurePresent},
+						Metadata: &model.FileMetadata{
+							Owner:    "root",
+							Group:    "root",
+							Mode:     "0644",
+							Checksum: checksum("file content"),
+						},
+					}
+				})
+
+				It("Should compare the entries unordered", func(ctx context.Context) {
+					provider.EXPECT().Status(gomock.Any(), "/tmp/testfile").Return(state, nil)
+					provider.EXPECT().Acl(gomock.Any(), "/tmp/testfile").Return([]string{"group:admins:r-x", "user:deploy:rwx"}, nil)
+
+					result, err := file.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.Changed).To(BeFalse())
+				})
+
+				It("Should detect and correct entries added out of band", func(ctx context.Context) {
+					provider.EXPECT().Status(gomock.Any(), "/tmp/testfile").Return(state, nil).Times(2)
+					provider.EXPECT().Acl(gomock.Any(), "/tmp/testfile").Return([]string{"group:admins:r-x", "user:deploy:rwx", "user:intruder:rwx"}, nil)
+					provider.EXPECT().SetAcl(gomock.Any(), "/tmp/testfile", []string{"u:deploy:rwx", "g:admins:rx"}).Return(nil)
+					provider.EXPECT().Acl(gomock.Any(), "/tmp/testfile").Return([]string{"group:admins:r-x", "user:deploy:rwx"}, nil)
+
+					result, err := file.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.Changed).To(BeTrue())
+					Expect(result.Status.(*model.FileState).Metadata.Acl).To(Equal([]string{"group:admins:r-x", "user:deploy:rwx"}))
+				})
+
+				It("Should set the acl on files it creates", func(ctx context.Context) {
+					absent := &model.FileState{
+						CommonResourceState: model.CommonResourceState{Ensure: model.EnsureAbsent},
+						Metadata:            &model.FileMetadata{},
+					}
+
+					provider.EXPECT().Status(gomock.Any(), "/tmp/testfile").Return(absent, nil)
+					provider.EXPECT().Store(gomock.Any(), "/tmp/testfile", []byte("file content"), "", "root", "root", "0644").Return(nil)
+					provider.EXPECT().SetAcl(gomock.Any(), "/tmp/testfile", []string{"u:deploy:rwx", "g:admins:rx"}).Return(nil)
+					provider.EXPECT().Status(gomock.Any(), "/tmp/testfile").Return(state, nil)
+					provider.EXPECT().Acl(gomock.Any(), "/tmp/testfile").Return([]string{"group:admins:r-x", "user:deploy:rwx"}, nil)
+
+					result, err := file.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.Changed).To(BeTrue())
+				})
+
+				It("Should fail clearly when acls are not supported", func(ctx context.Context) {
+					provider.EXPECT().Status(gomock.Any(), "/tmp/testfile").Return(state, nil)
+					provider.EXPECT().Acl(gomock.Any(), "/tmp/testfile").Return(nil, fmt.Errorf("%w: getfacl is not installed", model.ErrAclNotSupported))
+
+					result, err := file.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.Failed).To(BeTrue())
+					Expect(result.Errors).To(ContainElement("file ACLs are not supported: getfacl is not installed"))
+				})
+			})
+
 			Context("when ensure is absent", func() {
 				BeforeEach(func() {
 					file.prop.Ensure = model.EnsureAbsent
@@ -731,6 +1114,24 @@ var _ = Describe("File Type", func() {
 					Expect(result.Changed).To(BeFalse())
 				})
 
+				It("Should refuse to remove protected paths even with force", func(ctx context.Context) {
+					file.prop.Name = "/etc"
+					file.prop.Force = true
+
+					initial := &model.FileState{
+						CommonResourceState: model.CommonResourceState{Ensure: model.FileEnsureDirectory},
+						Metadata:            &model.FileMetadata{Owner: "root", Group: "root", Mode: "0755"},
+					}
+
+					provider.EXPECT().Status(gomock.Any(), "/etc").Return(initial, nil)
+					// No Remove call expected for a protected path
+
+					result, err := file.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.Failed).To(BeTrue())
+					Expect(result.Errors).To(ContainElement(ContainSubstring("refusing to remove protected path /etc")))
+				})
+
 				It("Should call provider Remove without force by default", func(ctx context.Context) {
 					initial := &model.FileState{
 						CommonResourceState: model.CommonResourceState{Ensure: model.EnsurePresent},
@@ -837,6 +1238,30 @@ var _ = Describe("File Type", func() {
 					Expect(result.Changed).To(BeFalse())
 				})
 
+				It("Should create missing parents before the directory when requested", func(ctx context.Context) {
+					file.prop.ManageParents = true
+					file.prop.ParentOwner = "app"
+					initialState := &model.FileState{
+						CommonResourceState: model.CommonResourceState{Ensure: model.EnsureAbsent},
+						Metadata:            &model.FileMetadata{},
+					}
+					finalState := &model.FileState{
+						CommonResourceState: model.CommonResourceState{Ensure: model.FileEnsureDirectory},
+						Metadata:            &model.FileMetadata{Owner: "root", Group: "root", Mode: "0755"},
+					}
+
+					provider.EXPECT().Status(gomock.Any(), "/tmp/testfile").Return(initialState, nil)
+					gomock.InOrder(
+						provider.EXPECT().CreateParents(gomock.Any(), "/tmp/testfile", "app", "root", "0644").Return([]string{"/tmp"}, nil),
+						provider.EXPECT().CreateDirectory(gomock.Any(), "/tmp/testfile", "root", "root", "0644").Return(nil),
+					)
+					provider.EXPECT().Status(gomock.Any(), "/tmp/testfile").Return(finalState, nil)
+
+					result, err := file.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.Changed).To(BeTrue())
+				})
+
 				It("Should fail if CreateDirectory fails", func(ctx context.Context) {
 					initialState := &model.FileState{
 						CommonResourceState: model.CommonResourceState{Ensure: model.EnsureAbsent},
@@ -905,7 +1330,7 @@ var _ = Describe("File Type", func() {
 					}
 
 					provider.EXPECT().Status(gomock.Any(), "/tmp/testfile").Return(state, nil)
-					runner.EXPECT().Execute(gomock.Any(), "/usr/bin/test", "-f", "/tmp/testfile").
+					runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/bin/test", Args: []string{"-f", "/tmp/testfile"}}).
 						Return([]byte("OK"), []byte{}, 0, nil)
 
 					result, err := file.Apply(ctx)
@@ -929,7 +1354,7 @@ var _ = Describe("File Type", func() {
 					}
 
 					provider.EXPECT().Status(gomock.Any(), "/tmp/testfile").Return(state, nil)
-					runner.EXPECT().Execute(gomock.Any(), "/usr/bin/test", "-f", "/tmp/testfile").
+					runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/bin/test", Args: []string{"-f", "/tmp/testfile"}}).
 						Return([]byte("CRITICAL"), []byte{}, 2, nil)
 
 					result, err := file.Apply(ctx)
@@ -996,6 +1421,94 @@ var _ = Describe("File Type", func() {
 				Expect(result.NoopMessage).To(Equal("Would have created the file"))
 			})
 
+			It("Should report that content would be validated", func(ctx context.Context) {
+				noopFile.prop.ValidateCommand = "/usr/sbin/sshd -t -f %{path}"
+				initialState := &model.FileState{
+					CommonResourceState: model.CommonResourceState{Ensure: model.EnsureAbsent},
+					Metadata:            &model.FileMetadata{},
+				}
+
+				noopProvider.EXPECT().Status(gomock.Any(), "/tmp/noopfile").Return(initialState, nil)
+				// No ValidateContent or Store call expected
+
+				result, err := noopFile.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Changed).To(BeTrue())
+				Expect(result.NoopMessage).To(Equal(`Would have created the file, would validate the content using "/usr/sbin/sshd -t -f %{path}"`))
+			})
+
+			It("Should not report a diff unless requested", func(ctx context.Context) {
+				noopFile.prop.Contents = stringPtr("line one\nnew line\n")
+				initialState := &model.FileState{
+					CommonResourceState: model.CommonResourceState{Ensure: model.EnsurePresent},
+					Metadata: &model.FileMetadata{
+						Owner:    "root",
+						Group:    "root",
+						Mode:     "0644",
+						Checksum: checksum("line one\nold line\n"),
+					},
+				}
+
+				noopProvider.EXPECT().Status(gomock.Any(), "/tmp/noopfile").Return(initialState, nil)
+				// No Contents call expected, content is not read without show_diff
+
+				result, err := noopFile.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Changed).To(BeTrue())
+				Expect(result.NoopMessage).To(Equal("Would have updated the file"))
+				Expect(result.Diff).To(BeEmpty())
+			})
+
+			It("Should report a diff when the content differs", func(ctx context.Context) {
+				noopFile.prop.Contents = stringPtr("line one\nnew line\n")
+				noopFile.prop.ShowDiff = true
+				initialState := &model.FileState{
+					CommonResourceState: model.CommonResourceState{Ensure: model.EnsurePresent},
+					Metadata: &model.FileMetadata{
+						Owner:    "root",
+						Group:    "root",
+						Mode:     "0644",
+						Checksum: checksum("line one\nold line\n"),
+					},
+				}
+
+				noopProvider.EXPECT().Status(gomock.Any(), "/tmp/noopfile").Return(initialState, nil)
+				noopProvider.EXPECT().Contents(gomock.Any(), "/tmp/noopfile").Return([]byte("line one\nold line\n"), nil)
+				// No Store call expected in noop mode
+
+				result, err := noopFile.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Changed).To(BeTrue())
+				Expect(result.Noop).To(BeTrue())
+				Expect(result.NoopMessage).To(Equal("Would have updated the file"))
+				Expect(result.Diff).To(ContainSubstring("--- /tmp/noopfile"))
+				Expect(result.Diff).To(ContainSubstring("+++ /tmp/noopfile"))
+				Expect(result.Diff).To(ContainSubstring("\n line one\n"))
+				Expect(result.Diff).To(ContainSubstring("\n-old line\n"))
+				Expect(result.Diff).To(ContainSubstring("\n+new line\n"))
+			})
+
+			It("Should not diff binary content", func(ctx context.Context) {
+				noopFile.prop.ShowDiff = true
+				initialState := &model.FileState{
+					CommonResourceState: model.CommonResourceState{Ensure: model.EnsurePresent},
+					Metadata: &model.FileMetadata{
+						Owner:    "root",
+						Group:    "root",
+						Mode:     "0644",
+						Checksum: checksum("\x00\x01\x02"),
+					},
+				}
+
+				noopProvider.EXPECT().Status(gomock.Any(), "/tmp/noopfile").Return(initialState, nil)
+				noopProvider.EXPECT().Contents(gomock.Any(), "/tmp/noopfile").Return([]byte("\x00\x01\x02"), nil)
+
+				result, err := noopFile.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.NoopMessage).To(Equal("Would have updated the file"))
+				Expect(result.Diff).To(Equal("binary file would change"))
+			})
+
 			It("Should not remove file when present", func(ctx context.Context) {
 				noopFile.prop.Ensure = model.EnsureAbsent
 				initialState := &model.FileState{
@@ -1041,10 +1554,11 @@ var _ = Describe("File Type", func() {
 				}
 
 				noopProvider.EXPECT().Status(gomock.Any(), "/tmp/noopfile").Return(initialState, nil)
+				noopProvider.EXPECT().CountEntries(gomock.Any(), "/tmp/noopfile").Return(12, nil)
 
 				result, err := noopFile.Apply(ctx)
 				Expect(err).ToNot(HaveOccurred())
-				Expect(result.NoopMessage).To(Equal("Would have recursively removed the directory"))
+				Expect(result.NoopMessage).To(Equal("Would have recursively removed the directory and 12 entries"))
 			})
 
 			It("Should not change when already in desired state", func(ctx context.Context) {
@@ -1160,4 +1674,144 @@ var _ = Describe("File Type", func() {
 			})
 		})
 	})
+
+	Context("with a recursively managed directory", func() {
+		var (
+			source string
+			target string
+			owner  string
+			group  string
+		)
+
+		BeforeEach(func() {
+			current, err := user.Current()
+			Expect(err).ToNot(HaveOccurred())
+			grp, err := user.LookupGroupId(current.Gid)
+			Expect(err).ToNot(HaveOccurred())
+			owner, group = current.Username, grp.Name
+
+			posixProvider, err := posix.NewPosixProvider(logger, runner)
+			Expect(err).ToNot(HaveOccurred())
+
+			factory := modelmocks.NewMockProviderFactory(mockctl)
+			factory.EXPECT().Name().Return("posix").AnyTimes()
+			factory.EXPECT().TypeName().Return(model.FileTypeName).AnyTimes()
+			factory.EXPECT().New(gomock.Any(), gomock.Any()).Return(posixProvider, nil).AnyTimes()
+			factory.EXPECT().IsManageable(facts, gomock.Any()).Return(true, 1, nil).AnyTimes()
+
+			registry.Clear()
+			registry.MustRegister(factory)
+
+			source = GinkgoT().TempDir()
+			target = filepath.Join(GinkgoT().TempDir(), "app")
+
+			Expect(os.MkdirAll(filepath.Join(source, "conf.d"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(source, "app.conf"), []byte("main"), 0600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(source, "conf.d", "db.conf"), []byte("db"), 0600)).To(Succeed())
+		})
+
+		newTree := func(ctx context.Context, purge bool) *Type {
+			res, err := New(ctx, mgr, model.FileResourceProperties{
+				CommonResourceProperties: model.CommonResourceProperties{Name: target, Ensure: model.FileEnsureDirectory},
+				Source:                   source,
+				Recurse:                  true,
+				Purge:                    purge,
+				Owner:                    owner,
+				Group:                    group,
+				Mode:                     "0640",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			return res
+		}
+
+		It("Should copy the source tree and be idempotent", func(ctx context.Context) {
+			event, err := newTree(ctx, false).Apply(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(event.Errors).To(BeEmpty())
+			Expect(event.Changed).To(BeTrue())
+
+			content, err := os.ReadFile(filepath.Join(target, "conf.d", "db.conf"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal("db"))
+
+			stat, err := os.Stat(filepath.Join(target, "app.conf"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stat.Mode().Perm()).To(Equal(os.FileMode(0640)))
+
+			stat, err = os.Stat(filepath.Join(target, "conf.d"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stat.Mode().Perm()).To(Equal(os.FileMode(0750)))
+
+			event, err = newTree(ctx, false).Apply(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(event.Errors).To(BeEmpty())
+			Expect(event.Changed).To(BeFalse())
+		})
+
+		It("Should report changed, stable and purged entries", func(ctx context.Context) {
+			_, err := newTree(ctx, false).Apply(ctx)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(os.WriteFile(filepath.Join(target, "app.conf"), []byte("edited"), 0640)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(target, "old", "nested"), 0750)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(target, "old", "nested", "x.conf"), []byte("x"), 0640)).To(Succeed())
+
+			state, err := newTree(ctx, true).Info(ctx)
+			Expect(err).ToNot(HaveOccurred())
+
+			meta := state.(*model.FileState).Metadata
+			Expect(meta.Changed).To(Equal([]string{"app.conf"}))
+			Expect(meta.Stable).To(Equal([]string{"conf.d", filepath.Join("conf.d", "db.conf")}))
+			Expect(meta.Purged).To(Equal([]string{"old"}))
+		})
+
+		It("Should only remove unmanaged entries when purging", func(ctx context.Context) {
+			Expect(os.MkdirAll(target, 0750)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(target, "local.conf"), []byte("local"), 0640)).To(Succeed())
+
+			_, err := newTree(ctx, false).Apply(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(filepath.Join(target, "local.conf")).To(BeAnExistingFile())
+
+			event, err := newTree(ctx, true).Apply(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(event.Errors).To(BeEmpty())
+			Expect(event.Changed).To(BeTrue())
+			Expect(filepath.Join(target, "local.conf")).ToNot(BeAnExistingFile())
+			Expect(filepath.Join(target, "app.conf")).To(BeAnExistingFile())
+		})
+
+		It("Should only replace entries of a different type when purging", func(ctx context.Context) {
+			Expect(os.MkdirAll(filepath.Join(target, "app.conf"), 0750)).To(Succeed())
+
+			event, err := newTree(ctx, false).Apply(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(event.Errors).To(ContainElement(ContainSubstring("set 'purge: true' to replace it")))
+
+			event, err = newTree(ctx, true).Apply(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(event.Errors).To(BeEmpty())
+			Expect(filepath.Join(target, "app.conf")).To(BeARegularFile())
+		})
+
+		It("Should not change anything in noop mode", func(ctx context.Context) {
+			noopMgr, _ := modelmocks.NewManager(facts, data, true, mockctl)
+			res, err := New(ctx, noopMgr, model.FileResourceProperties{
+				CommonResourceProperties: model.CommonResourceProperties{Name: target, Ensure: model.FileEnsureDirectory},
+				Source:                   source,
+				Recurse:                  true,
+				Owner:                    owner,
+				Group:                    group,
+				Mode:                     "0640",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			event, err := res.Apply(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(event.Changed).To(BeTrue())
+			Expect(event.NoopMessage).To(Equal("Would have created directory with 3 entries"))
+			Expect(target).ToNot(BeADirectory())
+		})
+	})
 })
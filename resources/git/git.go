@@ -0,0 +1,25 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gitresource
+
+import (
+	"context"
+
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/resources/git/gitcli"
+)
+
+func init() {
+	gitcli.Register()
+}
+
+type GitProvider interface {
+	model.Provider
+
+	Status(ctx context.Context, properties *model.GitResourceProperties) (*model.GitState, error)
+	Clone(ctx context.Context, properties *model.GitResourceProperties) error
+	Update(ctx context.Context, properties *model.GitResourceProperties) error
+	Remove(ctx context.Context, properties *model.GitResourceProperties) error
+}
@@ -0,0 +1,347 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gitcli
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/choria-io/ccm/model"
+	"github.com/kballard/go-shellquote"
+)
+
+const (
+	ProviderName = "git"
+
+	// revisionRefPrefix is where fetched revisions are stored in the checkout, the stored ref tells which commit
+	// the revision resolved to when it was last fetched
+	revisionRefPrefix = "refs/ccm/revisions/"
+)
+
+type Provider struct {
+	log    model.Logger
+	runner model.CommandRunner
+}
+
+// NewGitProvider creates a provider managing checkouts using the git command line
+func NewGitProvider(log model.Logger, runner model.CommandRunner) (*Provider, error) {
+	return &Provider{log: log, runner: runner}, nil
+}
+
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+// Status inspects the checkout, for ensure latest the remote repository is queried for the commit the revision
+// points to
+func (p *Provider) Status(ctx context.Context, properties *model.GitResourceProperties) (*model.GitState, error) {
+	path := properties.CheckoutPath()
+	state := &model.GitState{
+		CommonResourceState: model.NewCommonResourceState(model.ResourceStatusGitProtocol, model.GitTypeName, properties.Name, model.EnsureAbsent),
+		Metadata: &model.GitMetadata{
+			Path:     path,
+			Provider: ProviderName,
+		},
+	}
+
+	exists, err := isCheckout(path)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return state, nil
+	}
+
+	state.Metadata.Source, err = p.optionalOutput(ctx, properties, "config", "--get", "remote.origin.url")
+	if err != nil {
+		return nil, err
+	}
+
+	state.Metadata.Revision, err = p.optionalOutput(ctx, properties, "rev-parse", "--verify", "--quiet", "HEAD^{commit}")
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := p.output(ctx, properties, "status", "--porcelain", "--untracked-files=no")
+	if err != nil {
+		return nil, err
+	}
+	state.Metadata.Dirty = changes != ""
+
+	if properties.Ensure != model.EnsureAbsent {
+		state.Metadata.Target, err = p.target(ctx, properties)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	state.Ensure = model.EnsurePresent
+	if state.Metadata.Revision != "" {
+		state.Ensure = state.Metadata.Revision
+	}
+
+	return state, nil
+}
+
+// Clone clones the source without checking out any files and then checks out the revision
+func (p *Provider) Clone(ctx context.Context, properties *model.GitResourceProperties) error {
+	args := []string{"clone", "--quiet", "--no-checkout"}
+	if properties.Depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(properties.Depth))
+	}
+	args = append(args, "--", properties.Source, properties.CheckoutPath())
+
+	_, err := p.git(ctx, properties, args...)
+	if err != nil {
+		return err
+	}
+
+	return p.Update(ctx, properties)
+}
+
+// Update points origin at the source, fetches the revision and checks it out discarding any local modifications
+// to tracked files
+func (p *Provider) Update(ctx context.Context, properties *model.GitResourceProperties) error {
+	_, err := p.repoGit(ctx, properties, "config", "remote.origin.url", properties.Source)
+	if err != nil {
+		return err
+	}
+
+	var target string
+
+	if properties.RevisionIsCommit() {
+		target, err = p.optionalOutput(ctx, properties, "rev-parse", "--verify", "--quiet", properties.Revision+"^{commit}")
+		if err != nil {
+			return err
+		}
+
+		if target == "" {
+			_, err = p.repoGit(ctx, properties, p.fetchArgs(properties, properties.Revision)...)
+			if err != nil {
+				return err
+			}
+		}
+
+		target = properties.Revision
+	} else {
+		ref := revisionOrHead(properties)
+		target = revisionRefPrefix + ref
+
+		_, err = p.repoGit(ctx, properties, p.fetchArgs(properties, fmt.Sprintf("+%s:%s", ref, target))...)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = p.repoGit(ctx, properties, "checkout", "--quiet", "--force", "--detach", target)
+
+	return err
+}
+
+// Remove deletes the checkout
+func (p *Provider) Remove(_ context.Context, properties *model.GitResourceProperties) error {
+	return os.RemoveAll(properties.CheckoutPath())
+}
+
+// target is the commit the revision resolves to. For ensure latest the remote is asked, otherwise the commit the
+// revision resolved to when it was last fetched is used. Empty when the revision is not known locally.
+func (p *Provider) target(ctx context.Context, properties *model.GitResourceProperties) (string, error) {
+	switch {
+	case properties.RevisionIsCommit():
+		return p.optionalOutput(ctx, properties, "rev-parse", "--verify", "--quiet", properties.Revision+"^{commit}")
+
+	case properties.Ensure == model.GitEnsureLatest:
+		return p.remoteCommit(ctx, properties)
+
+	case properties.Revision == "":
+		return "", nil
+
+	default:
+		return p.optionalOutput(ctx, properties, "rev-parse", "--verify", "--quiet", revisionRefPrefix+properties.Revision+"^{commit}")
+	}
+}
+
+// remoteCommit finds the commit the revision points to in the source repository, branches are preferred over tags
+// and annotated tags are resolved to the commit they point to
+func (p *Provider) remoteCommit(ctx context.Context, properties *model.GitResourceProperties) (string, error) {
+	ref := revisionOrHead(properties)
+
+	out, err := p.git(ctx, properties, "ls-remote", "--", properties.Source, ref)
+	if err != nil {
+		return "", err
+	}
+
+	refs := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		parts := strings.Fields(line)
+		if len(parts) == 2 {
+			refs[parts[1]] = parts[0]
+		}
+	}
+
+	for _, candidate := range []string{ref, "refs/heads/" + ref, "refs/tags/" + ref + "^{}", "refs/tags/" + ref} {
+		commit, ok := refs[candidate]
+		if ok {
+			return commit, nil
+		}
+	}
+
+	return "", fmt.Errorf("revision %s not found in %s", ref, properties.Source)
+}
+
+func (p *Provider) fetchArgs(properties *model.GitResourceProperties, refspec string) []string {
+	args := []string{"fetch", "--quiet"}
+	if properties.Depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(properties.Depth))
+	}
+
+	return append(args, "origin", refspec)
+}
+
+// optionalOutput runs git in the checkout and returns its output, a command that exits with code 1 returns an
+// empty output as git uses it to indicate a missing config item or revision
+func (p *Provider) optionalOutput(ctx context.Context, properties *model.GitResourceProperties, args ...string) (string, error) {
+	stdout, stderr, exitCode, err := p.execute(ctx, properties, repoArgs(properties, args)...)
+	switch {
+	case err != nil:
+		return "", err
+	case exitCode == 1:
+		return "", nil
+	case exitCode != 0:
+		return "", fmt.Errorf("git %s failed with exit code %d: %s", args[0], exitCode, bytes.TrimSpace(append(stdout, stderr...)))
+	}
+
+	return strings.TrimSpace(string(stdout)), nil
+}
+
+func (p *Provider) output(ctx context.Context, properties *model.GitResourceProperties, args ...string) (string, error) {
+	out, err := p.repoGit(ctx, properties, args...)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// repoGit runs git in the checkout
+func (p *Provider) repoGit(ctx context.Context, properties *model.GitResourceProperties, args ...string) ([]byte, error) {
+	return p.git(ctx, properties, repoArgs(properties, args)...)
+}
+
+func (p *Provider) git(ctx context.Context, properties *model.GitResourceProperties, args ...string) ([]byte, error) {
+	stdout, stderr, exitCode, err := p.execute(ctx, properties, args...)
+	if err != nil {
+		return nil, err
+	}
+	if exitCode != 0 {
+		return nil, fmt.Errorf("git %s failed with exit code %d: %s", gitCommand(args), exitCode, bytes.TrimSpace(append(stdout, stderr...)))
+	}
+
+	return stdout, nil
+}
+
+func (p *Provider) execute(ctx context.Context, properties *model.GitResourceProperties, args ...string) ([]byte, []byte, int, error) {
+	stdout, stderr, exitCode, err := p.runner.ExecuteWithOptions(ctx, model.ExtendedExecOptions{
+		Command:     "git",
+		Args:        args,
+		Environment: environment(properties),
+	})
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	p.log.Debug("Executed command", "command", "git", "args", args, "exitcode", exitCode)
+
+	return stdout, stderr, exitCode, nil
+}
+
+// repoArgs prefixes args to run git in the checkout, the checkout is marked safe as it is often owned by another user
+func repoArgs(properties *model.GitResourceProperties, args []string) []string {
+	path := properties.CheckoutPath()
+
+	return append([]string{"-C", path, "-c", "safe.directory=" + path}, args...)
+}
+
+// gitCommand is the git sub command in args
+func gitCommand(args []string) string {
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "-C", "-c":
+			i++
+		default:
+			return args[i]
+		}
+	}
+
+	return ""
+}
+
+// environment configures git to never prompt and to authenticate using the ssh key or the username and password,
+// the password is passed in the environment so it does not show in process listings or the repository config
+func environment(properties *model.GitResourceProperties) []string {
+	env := []string{"GIT_TERMINAL_PROMPT=0"}
+
+	if properties.SSHKey != "" {
+		env = append(env, "GIT_SSH_COMMAND="+shellquote.Join("ssh", "-i", properties.SSHKey, "-o", "IdentitiesOnly=yes", "-o", "BatchMode=yes"))
+	}
+
+	if properties.Username != "" {
+		auth := base64.StdEncoding.EncodeToString([]byte(properties.Username + ":" + properties.Password))
+		env = append(env,
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=http.extraHeader",
+			"GIT_CONFIG_VALUE_0=Authorization: Basic "+auth,
+		)
+	}
+
+	return env
+}
+
+func revisionOrHead(properties *model.GitResourceProperties) string {
+	if properties.Revision == "" {
+		return "HEAD"
+	}
+
+	return properties.Revision
+}
+
+// isCheckout determines if path is a git checkout, an empty directory is treated as a missing checkout so it can be
+// cloned into
+func isCheckout(path string) (bool, error) {
+	stat, err := os.Stat(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return false, nil
+	case err != nil:
+		return false, err
+	case !stat.IsDir():
+		return false, fmt.Errorf("%s exists and is not a directory", path)
+	}
+
+	_, err = os.Stat(filepath.Join(path, ".git"))
+	if err == nil {
+		return true, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return false, err
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return false, err
+	}
+	if len(entries) > 0 {
+		return false, fmt.Errorf("%s exists and is not a git checkout", path)
+	}
+
+	return false, nil
+}
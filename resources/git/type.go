@@ -0,0 +1,308 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gitresource
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/choria-io/ccm/internal/registry"
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/resources/base"
+	"github.com/choria-io/ccm/resources/git/gitcli"
+)
+
+var _ base.ProviderFallback = (*Type)(nil)
+var _ base.StatusReporter = (*Type)(nil)
+
+type Type struct {
+	*base.Base
+
+	prop     *model.GitResourceProperties
+	mgr      model.Manager
+	log      model.Logger
+	provider model.Provider
+
+	mu sync.Mutex
+}
+
+var _ model.Resource = (*Type)(nil)
+var _ GitProvider = (*gitcli.Provider)(nil)
+
+// New creates a new git resource with the given properties
+func New(ctx context.Context, mgr model.Manager, properties model.GitResourceProperties) (*Type, error) {
+	env, err := mgr.TemplateEnvironment(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = properties.ResolveTemplates(env)
+	if err != nil {
+		return nil, err
+	}
+
+	loggerArgs := []any{"type", model.GitTypeName, "name", properties.Name}
+	logger, err := mgr.Logger(loggerArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	properties.CommonResourceProperties.Type = model.GitTypeName
+
+	t := &Type{
+		prop: &properties,
+		mgr:  mgr,
+		log:  logger,
+	}
+	t.Base = &base.Base{
+		Resource:           t,
+		ResourceProperties: &properties,
+		CommonProperties:   properties.CommonResourceProperties,
+		Log:                logger,
+		UserLogger:         mgr.UserLogger().With(loggerArgs...),
+		Manager:            mgr,
+		Facts:              env.Facts,
+		Data:               env.Data,
+	}
+
+	err = t.Base.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %w", t.String(), model.ErrResourceInvalid, err)
+	}
+
+	t.log.Debug("Created resource instance")
+
+	return t, nil
+}
+
+func (t *Type) ApplyResource(ctx context.Context) (model.ResourceState, error) {
+	var (
+		initialStatus *model.GitState
+		finalStatus   *model.GitState
+		p             = t.provider.(GitProvider)
+		properties    = t.prop
+		noop          = t.mgr.NoopMode()
+		noopMessage   string
+		err           error
+	)
+
+	initialStatus, err = p.Status(ctx, properties)
+	if err != nil {
+		return nil, err
+	}
+
+	isStable, _ := t.isDesiredState(properties, initialStatus)
+	if isStable {
+		t.FinalizeState(initialStatus, noop, "", false, true, false)
+		return initialStatus, nil
+	}
+
+	switch {
+	case properties.Ensure == model.EnsureAbsent:
+		if !noop {
+			t.log.Info("Removing checkout")
+			err = p.Remove(ctx, properties)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			t.log.Info("Skipping remove as noop")
+			noopMessage = "Would have removed the checkout"
+		}
+
+	case initialStatus.Ensure == model.EnsureAbsent:
+		if !noop {
+			t.log.Info("Cloning repository", "source", properties.Source, "revision", properties.Revision)
+			err = p.Clone(ctx, properties)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			t.log.Info("Skipping clone as noop")
+			noopMessage = fmt.Sprintf("Would have cloned %s", properties.Source)
+		}
+
+	default:
+		if !noop {
+			t.log.Info("Updating checkout", "revision", properties.Revision, "current", initialStatus.Metadata.Revision)
+			err = p.Update(ctx, properties)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			t.log.Info("Skipping update as noop")
+			noopMessage = t.updateNoopMessage(initialStatus)
+		}
+	}
+
+	finalStatus = initialStatus
+	if !noop {
+		finalStatus, err = p.Status(ctx, properties)
+		if err != nil {
+			return nil, err
+		}
+
+		var reason string
+		isStable, reason = t.isDesiredState(properties, finalStatus)
+		if !isStable {
+			return nil, fmt.Errorf("%w: %s: %s", model.ErrDesiredStateFailed, properties.Ensure, reason)
+		}
+	}
+
+	t.FinalizeState(finalStatus, noop, noopMessage, true, isStable, false)
+	t.ClassifyChange(finalStatus, initialStatus.Ensure != model.EnsureAbsent)
+
+	return finalStatus, nil
+}
+
+// updateNoopMessage describes the change Update would make to an existing checkout
+func (t *Type) updateNoopMessage(state *model.GitState) string {
+	if state.Metadata.Target != "" {
+		return fmt.Sprintf("Would have checked out %s", state.Metadata.Target)
+	}
+
+	if t.prop.Revision != "" {
+		return fmt.Sprintf("Would have checked out %s", t.prop.Revision)
+	}
+
+	return "Would have updated the checkout"
+}
+
+// isDesiredState reports whether state matches properties. A checkout is stable when its origin is the source, it
+// has no local modifications and the checked out commit is the one the revision resolves to, when ensure is present
+// without a revision any clean checkout of the source is stable. The second return is a human-readable reason
+// describing the mismatch when stable is false, suitable for inclusion in error messages.
+func (t *Type) isDesiredState(properties *model.GitResourceProperties, state *model.GitState) (bool, string) {
+	if properties.Ensure == model.EnsureAbsent {
+		if state.Ensure == model.EnsureAbsent {
+			return true, ""
+		}
+		return false, fmt.Sprintf("checkout %s still exists", properties.CheckoutPath())
+	}
+
+	if state.Ensure == model.EnsureAbsent {
+		return false, fmt.Sprintf("%s is not checked out", properties.CheckoutPath())
+	}
+
+	if state.Metadata.Source != properties.Source {
+		return false, fmt.Sprintf("source is %s, expected %s", state.Metadata.Source, properties.Source)
+	}
+
+	if state.Metadata.Dirty {
+		return false, "checkout has local modifications"
+	}
+
+	if state.Metadata.Revision == "" {
+		return false, "no commit is checked out"
+	}
+
+	if properties.Ensure == model.EnsurePresent && properties.Revision == "" {
+		return true, ""
+	}
+
+	if state.Metadata.Target == "" {
+		return false, fmt.Sprintf("revision %q could not be resolved", properties.Revision)
+	}
+
+	if state.Metadata.Revision != state.Metadata.Target {
+		return false, fmt.Sprintf("commit %s is checked out, expected %s", state.Metadata.Revision, state.Metadata.Target)
+	}
+
+	return true, ""
+}
+
+func (t *Type) Info(ctx context.Context) (any, error) {
+	_, err := t.SelectProvider()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", t.String(), err)
+	}
+
+	return t.provider.(GitProvider).Status(ctx, t.prop)
+}
+
+// CurrentState reports the current state of the resource without making any changes
+func (t *Type) CurrentState(ctx context.Context) (model.ResourceState, error) {
+	state, err := t.provider.(GitProvider).Status(ctx, t.prop)
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+func (t *Type) providerUnlocked() string {
+	if t.provider == nil {
+		return ""
+	}
+
+	return t.provider.Name()
+}
+
+// Provider returns the name of the selected provider
+func (t *Type) Provider() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.providerUnlocked()
+}
+
+func (t *Type) selectProviderUnlocked() error {
+	if t.provider != nil {
+		return nil
+	}
+
+	runner, err := t.mgr.NewRunner()
+	if err != nil {
+		return err
+	}
+
+	selected, err := registry.FindSuitableProvider(model.GitTypeName, t.prop.Provider, t.Facts, t.prop, t.log, runner, t.mgr)
+	if err != nil {
+		return err
+	}
+
+	if selected == nil {
+		return model.ErrNoSuitableProvider
+	}
+
+	t.log.Debug("Selected provider", "provider", selected.Name())
+	t.provider = selected
+
+	return nil
+}
+
+// SelectAlternateProvider replaces the selected provider with the most suitable provider not listed in exclude
+func (t *Type) SelectAlternateProvider(exclude []string) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	runner, err := t.mgr.NewRunner()
+	if err != nil {
+		return "", err
+	}
+
+	selected, err := registry.FindAlternateProvider(model.GitTypeName, exclude, t.Facts, t.prop, t.log, runner, t.mgr)
+	if err != nil {
+		return "", err
+	}
+
+	t.log.Debug("Selected alternate provider", "provider", selected.Name())
+	t.provider = selected
+
+	return t.providerUnlocked(), nil
+}
+
+func (t *Type) SelectProvider() (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	err := t.selectProviderUnlocked()
+	if err != nil {
+		return "", err
+	}
+
+	return t.providerUnlocked(), nil
+}
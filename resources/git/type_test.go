@@ -0,0 +1,369 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gitresource
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/choria-io/ccm/internal/cmdrunner"
+	"github.com/choria-io/ccm/internal/registry"
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/model/modelmocks"
+	"github.com/choria-io/ccm/resources/git/gitcli"
+)
+
+func TestGitResource(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Resources/Git")
+}
+
+var _ = Describe("Git Type", func() {
+	var (
+		facts    = make(map[string]any)
+		data     = make(map[string]any)
+		mgr      *modelmocks.MockManager
+		logger   *modelmocks.MockLogger
+		mockctl  *gomock.Controller
+		provider *MockGitProvider
+	)
+
+	BeforeEach(func() {
+		mockctl = gomock.NewController(GinkgoT())
+		mgr, logger = modelmocks.NewManager(facts, data, false, mockctl)
+		mgr.EXPECT().NewRunner().AnyTimes().Return(modelmocks.NewMockCommandRunner(mockctl), nil)
+		provider = NewMockGitProvider(mockctl)
+
+		provider.EXPECT().Name().Return("mock").AnyTimes()
+		logger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+		logger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+	})
+
+	Describe("New", func() {
+		It("Should validate properties", func(ctx context.Context) {
+			_, err := New(ctx, mgr, model.GitResourceProperties{})
+			Expect(err).To(MatchError(model.ErrResourceNameRequired))
+
+			_, err = New(ctx, mgr, model.GitResourceProperties{
+				CommonResourceProperties: model.CommonResourceProperties{Name: "/srv/app", Ensure: model.EnsurePresent},
+			})
+			Expect(err).To(MatchError(ContainSubstring("source cannot be empty")))
+		})
+	})
+
+	Context("with a prepared provider", func() {
+		var factory *modelmocks.MockProviderFactory
+		var res *Type
+		var err error
+
+		BeforeEach(func(ctx context.Context) {
+			factory = modelmocks.NewMockProviderFactory(mockctl)
+			factory.EXPECT().Name().Return("test").AnyTimes()
+			factory.EXPECT().TypeName().Return(model.GitTypeName).AnyTimes()
+			factory.EXPECT().New(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(log model.Logger, runner model.CommandRunner) (model.Provider, error) {
+				return provider, nil
+			})
+			factory.EXPECT().IsManageable(facts, gomock.Any()).Return(true, 1, nil).AnyTimes()
+
+			res, err = New(ctx, mgr, model.GitResourceProperties{
+				CommonResourceProperties: model.CommonResourceProperties{
+					Name:     "/srv/app",
+					Ensure:   model.EnsurePresent,
+					Provider: "test",
+				},
+				Source:   "https://git.example.net/app.git",
+				Revision: "main",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			registry.Clear()
+			registry.MustRegister(factory)
+		})
+
+		state := func(revision string, target string) *model.GitState {
+			s := &model.GitState{
+				CommonResourceState: model.CommonResourceState{Ensure: model.EnsureAbsent},
+				Metadata:            &model.GitMetadata{Path: "/srv/app"},
+			}
+
+			if revision != "" {
+				s.Ensure = revision
+				s.Metadata.Source = "https://git.example.net/app.git"
+				s.Metadata.Revision = revision
+				s.Metadata.Target = target
+			}
+
+			return s
+		}
+
+		Describe("Apply", func() {
+			It("Should fail if initial status check fails", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("status failed"))
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Errors).To(ContainElement(ContainSubstring("status failed")))
+			})
+
+			It("Should clone missing checkouts", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state("", ""), nil)
+				provider.EXPECT().Clone(gomock.Any(), res.prop).Return(nil)
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state("abc1234", "abc1234"), nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Errors).To(BeEmpty())
+				Expect(event.Changed).To(BeTrue())
+				Expect(event.FinalEnsure).To(Equal("abc1234"))
+			})
+
+			It("Should update checkouts at another commit", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state("abc1234", "def5678"), nil)
+				provider.EXPECT().Update(gomock.Any(), res.prop).Return(nil)
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state("def5678", "def5678"), nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Errors).To(BeEmpty())
+				Expect(event.Changed).To(BeTrue())
+				Expect(event.Corrective).To(BeTrue())
+			})
+
+			It("Should update checkouts with local modifications", func(ctx context.Context) {
+				dirty := state("abc1234", "abc1234")
+				dirty.Metadata.Dirty = true
+
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(dirty, nil)
+				provider.EXPECT().Update(gomock.Any(), res.prop).Return(nil)
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state("abc1234", "abc1234"), nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Changed).To(BeTrue())
+			})
+
+			It("Should update checkouts of another source", func(ctx context.Context) {
+				moved := state("abc1234", "abc1234")
+				moved.Metadata.Source = "https://old.example.net/app.git"
+
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(moved, nil)
+				provider.EXPECT().Update(gomock.Any(), res.prop).Return(nil)
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state("abc1234", "abc1234"), nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Changed).To(BeTrue())
+			})
+
+			It("Should fail when the checkout does not reach the desired state", func(ctx context.Context) {
+				initial := state("abc1234", "def5678")
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(initial, nil)
+				provider.EXPECT().Update(gomock.Any(), res.prop).Return(nil)
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(initial, nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Errors).To(ContainElement(ContainSubstring("commit abc1234 is checked out, expected def5678")))
+			})
+
+			It("Should not change when the checkout matches", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state("abc1234", "abc1234"), nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Changed).To(BeFalse())
+			})
+
+			It("Should accept any clean checkout without a revision", func(ctx context.Context) {
+				res.prop.Revision = ""
+
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state("abc1234", ""), nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Changed).To(BeFalse())
+			})
+
+			It("Should remove the checkout when absent", func(ctx context.Context) {
+				res.prop.Ensure = model.EnsureAbsent
+
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state("abc1234", ""), nil)
+				provider.EXPECT().Remove(gomock.Any(), res.prop).Return(nil)
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state("", ""), nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Errors).To(BeEmpty())
+				Expect(event.Changed).To(BeTrue())
+			})
+		})
+
+		Describe("Apply in noop mode", func() {
+			var noopRes *Type
+
+			BeforeEach(func(ctx context.Context) {
+				noopMgr, _ := modelmocks.NewManager(facts, data, true, mockctl)
+				noopMgr.EXPECT().NewRunner().AnyTimes().Return(modelmocks.NewMockCommandRunner(mockctl), nil)
+				noopRes, err = New(ctx, noopMgr, *res.prop)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("Should not clone the repository", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state("", ""), nil)
+
+				event, err := noopRes.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Changed).To(BeTrue())
+				Expect(event.Noop).To(BeTrue())
+				Expect(event.NoopMessage).To(Equal("Would have cloned https://git.example.net/app.git"))
+			})
+
+			It("Should describe the commit it would check out", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state("abc1234", "def5678"), nil)
+
+				event, err := noopRes.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Changed).To(BeTrue())
+				Expect(event.NoopMessage).To(Equal("Would have checked out def5678"))
+			})
+		})
+	})
+
+	Context("with a local repository", func() {
+		var (
+			bare   string
+			work   string
+			path   string
+			gitMgr *modelmocks.MockManager
+		)
+
+		git := func(dir string, args ...string) string {
+			GinkgoHelper()
+
+			cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+			cmd.Env = append(os.Environ(),
+				"GIT_AUTHOR_NAME=ccm", "GIT_AUTHOR_EMAIL=ccm@example.net",
+				"GIT_COMMITTER_NAME=ccm", "GIT_COMMITTER_EMAIL=ccm@example.net",
+				"GIT_CONFIG_NOSYSTEM=1", "GIT_CONFIG_GLOBAL=/dev/null",
+			)
+			out, err := cmd.CombinedOutput()
+			Expect(err).ToNot(HaveOccurred(), string(out))
+
+			return strings.TrimSpace(string(out))
+		}
+
+		commit := func(content string) string {
+			GinkgoHelper()
+
+			Expect(os.WriteFile(filepath.Join(work, "app.txt"), []byte(content), 0644)).To(Succeed())
+			git(work, "add", "app.txt")
+			git(work, "commit", "--quiet", "-m", content)
+			git(work, "push", "--quiet", "origin", "HEAD:refs/heads/main")
+
+			return git(work, "rev-parse", "HEAD")
+		}
+
+		apply := func(ctx context.Context, ensure string) *model.TransactionEvent {
+			GinkgoHelper()
+
+			res, err := New(ctx, gitMgr, model.GitResourceProperties{
+				CommonResourceProperties: model.CommonResourceProperties{Name: path, Ensure: ensure},
+				Source:                   "file://" + bare,
+				Revision:                 "main",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			event, err := res.Apply(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(event.Errors).To(BeEmpty())
+
+			return event
+		}
+
+		BeforeEach(func() {
+			_, err := exec.LookPath("git")
+			if err != nil {
+				Skip("git is not installed")
+			}
+
+			td := GinkgoT().TempDir()
+			bare = filepath.Join(td, "remote.git")
+			work = filepath.Join(td, "work")
+			path = filepath.Join(td, "checkout")
+
+			git(td, "init", "--quiet", "--bare", "--initial-branch=main", bare)
+			git(td, "init", "--quiet", "--initial-branch=main", work)
+			git(work, "remote", "add", "origin", "file://"+bare)
+
+			var gitLogger *modelmocks.MockLogger
+			gitMgr, gitLogger = modelmocks.NewManager(facts, data, false, mockctl)
+			runner, err := cmdrunner.NewCommandRunner(gitLogger)
+			Expect(err).ToNot(HaveOccurred())
+			gitMgr.EXPECT().NewRunner().AnyTimes().Return(runner, nil)
+
+			registry.Clear()
+			gitcli.Register()
+		})
+
+		It("Should clone once and then be stable", func(ctx context.Context) {
+			head := commit("v1")
+
+			event := apply(ctx, model.EnsurePresent)
+			Expect(event.Changed).To(BeTrue())
+			Expect(event.FinalEnsure).To(Equal(head))
+
+			event = apply(ctx, model.EnsurePresent)
+			Expect(event.Changed).To(BeFalse())
+			Expect(event.FinalEnsure).To(Equal(head))
+
+			commit("v2")
+
+			event = apply(ctx, model.EnsurePresent)
+			Expect(event.Changed).To(BeFalse())
+			Expect(event.FinalEnsure).To(Equal(head))
+		})
+
+		It("Should follow new commits for latest", func(ctx context.Context) {
+			commit("v1")
+
+			event := apply(ctx, model.GitEnsureLatest)
+			Expect(event.Changed).To(BeTrue())
+
+			event = apply(ctx, model.GitEnsureLatest)
+			Expect(event.Changed).To(BeFalse())
+
+			head := commit("v2")
+
+			event = apply(ctx, model.GitEnsureLatest)
+			Expect(event.Changed).To(BeTrue())
+			Expect(event.FinalEnsure).To(Equal(head))
+			Expect(os.ReadFile(filepath.Join(path, "app.txt"))).To(Equal([]byte("v2")))
+
+			event = apply(ctx, model.GitEnsureLatest)
+			Expect(event.Changed).To(BeFalse())
+		})
+
+		It("Should remove the checkout", func(ctx context.Context) {
+			commit("v1")
+			apply(ctx, model.EnsurePresent)
+
+			event := apply(ctx, model.EnsureAbsent)
+			Expect(event.Changed).To(BeTrue())
+			Expect(path).ToNot(BeADirectory())
+
+			event = apply(ctx, model.EnsureAbsent)
+			Expect(event.Changed).To(BeFalse())
+		})
+	})
+})
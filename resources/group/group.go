@@ -0,0 +1,25 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package groupresource
+
+import (
+	"context"
+
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/resources/group/groupadd"
+)
+
+func init() {
+	groupadd.Register()
+}
+
+type GroupProvider interface {
+	model.Provider
+
+	Status(ctx context.Context, properties *model.GroupResourceProperties) (*model.GroupState, error)
+	Create(ctx context.Context, properties *model.GroupResourceProperties) error
+	Modify(ctx context.Context, properties *model.GroupResourceProperties, state *model.GroupState) error
+	Delete(ctx context.Context, properties *model.GroupResourceProperties) error
+}
@@ -0,0 +1,37 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package groupadd
+
+import (
+	"github.com/choria-io/ccm/internal/registry"
+	iu "github.com/choria-io/ccm/internal/util"
+	"github.com/choria-io/ccm/model"
+)
+
+// Register registers this provider with the registry
+func Register() {
+	registry.MustRegister(&factory{})
+}
+
+type factory struct{}
+
+func (p *factory) TypeName() string { return model.GroupTypeName }
+func (p *factory) Name() string     { return ProviderName }
+func (p *factory) New(log model.Logger, runner model.CommandRunner) (model.Provider, error) {
+	return NewGroupaddProvider(log, runner)
+}
+func (p *factory) IsManageable(_ map[string]any, _ model.ResourceProperties) (bool, int, error) {
+	for _, cmd := range []string{"getent", "groupadd", "groupmod", "groupdel", "gpasswd"} {
+		_, found, err := iu.ExecutableInPath(cmd)
+		if err != nil {
+			return false, 0, err
+		}
+		if !found {
+			return false, 0, nil
+		}
+	}
+
+	return true, 1, nil
+}
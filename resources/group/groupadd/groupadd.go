@@ -0,0 +1,135 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package groupadd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/choria-io/ccm/model"
+)
+
+const ProviderName = "groupadd"
+
+type Provider struct {
+	log    model.Logger
+	runner model.CommandRunner
+}
+
+// NewGroupaddProvider creates a provider managing groups using the shadow utilities
+func NewGroupaddProvider(log model.Logger, runner model.CommandRunner) (*Provider, error) {
+	return &Provider{log: log, runner: runner}, nil
+}
+
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+// Status reads the group from the group database using getent
+func (p *Provider) Status(ctx context.Context, properties *model.GroupResourceProperties) (*model.GroupState, error) {
+	state := &model.GroupState{
+		CommonResourceState: model.NewCommonResourceState(model.ResourceStatusGroupProtocol, model.GroupTypeName, properties.Name, model.EnsureAbsent),
+		Metadata: &model.GroupMetadata{
+			Name:     properties.Name,
+			Provider: ProviderName,
+		},
+	}
+
+	stdout, stderr, exitCode, err := p.runner.Execute(ctx, "getent", "group", properties.Name)
+	switch {
+	case err != nil:
+		return nil, err
+	case exitCode == 2:
+		// the group does not exist
+		return state, nil
+	case exitCode != 0:
+		return nil, fmt.Errorf("could not look up group %s: %s", properties.Name, bytes.TrimSpace(append(stdout, stderr...)))
+	}
+
+	// name:password:gid:member,member
+	parts := strings.Split(strings.TrimSpace(string(stdout)), ":")
+	if len(parts) != 4 || parts[0] != properties.Name {
+		return nil, fmt.Errorf("%w: invalid group entry %q", model.ErrInvalidState, bytes.TrimSpace(stdout))
+	}
+
+	state.Metadata.GID, err = strconv.Atoi(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid gid %q", model.ErrInvalidState, parts[2])
+	}
+
+	if parts[3] != "" {
+		state.Metadata.Members = slices.Sorted(slices.Values(strings.Split(parts[3], ",")))
+	}
+
+	state.Ensure = model.EnsurePresent
+
+	return state, nil
+}
+
+// Create adds the group using groupadd and sets its members
+func (p *Provider) Create(ctx context.Context, properties *model.GroupResourceProperties) error {
+	var args []string
+	if properties.GID > 0 {
+		args = append(args, "-g", strconv.Itoa(properties.GID))
+	}
+	if properties.System {
+		args = append(args, "-r")
+	}
+	args = append(args, properties.Name)
+
+	err := p.run(ctx, "groupadd", args...)
+	if err != nil {
+		return err
+	}
+
+	if len(properties.Members) == 0 {
+		return nil
+	}
+
+	return p.setMembers(ctx, properties)
+}
+
+// Modify changes the gid using groupmod and the members of the group to match properties
+func (p *Provider) Modify(ctx context.Context, properties *model.GroupResourceProperties, state *model.GroupState) error {
+	if properties.GID > 0 && properties.GID != state.Metadata.GID {
+		err := p.run(ctx, "groupmod", "-g", strconv.Itoa(properties.GID), properties.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(properties.Members) > 0 && !slices.Equal(properties.SortedMembers(), state.Metadata.Members) {
+		return p.setMembers(ctx, properties)
+	}
+
+	return nil
+}
+
+// Delete removes the group using groupdel
+func (p *Provider) Delete(ctx context.Context, properties *model.GroupResourceProperties) error {
+	return p.run(ctx, "groupdel", properties.Name)
+}
+
+func (p *Provider) setMembers(ctx context.Context, properties *model.GroupResourceProperties) error {
+	return p.run(ctx, "gpasswd", "-M", strings.Join(properties.SortedMembers(), ","), properties.Name)
+}
+
+func (p *Provider) run(ctx context.Context, cmd string, args ...string) error {
+	stdout, stderr, exitCode, err := p.runner.Execute(ctx, cmd, args...)
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		return fmt.Errorf("%s failed with exit code %d: %s", cmd, exitCode, bytes.TrimSpace(append(stdout, stderr...)))
+	}
+
+	p.log.Debug("Executed command", "command", cmd, "args", args)
+
+	return nil
+}
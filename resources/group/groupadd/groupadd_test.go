@@ -0,0 +1,146 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package groupadd
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/model/modelmocks"
+)
+
+func TestGroupaddProvider(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Resources/Group/Groupadd")
+}
+
+var _ = Describe("Groupadd Provider", func() {
+	var (
+		mockctl  *gomock.Controller
+		logger   *modelmocks.MockLogger
+		runner   *modelmocks.MockCommandRunner
+		provider *Provider
+		err      error
+	)
+
+	BeforeEach(func() {
+		mockctl = gomock.NewController(GinkgoT())
+		logger = modelmocks.NewMockLogger(mockctl)
+		logger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+		runner = modelmocks.NewMockCommandRunner(mockctl)
+
+		provider, err = NewGroupaddProvider(logger, runner)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	props := func(gid int, members ...string) *model.GroupResourceProperties {
+		return &model.GroupResourceProperties{
+			CommonResourceProperties: model.CommonResourceProperties{Name: "app", Ensure: model.EnsurePresent},
+			GID:                      gid,
+			Members:                  members,
+		}
+	}
+
+	state := func(gid int, members ...string) *model.GroupState {
+		return &model.GroupState{
+			CommonResourceState: model.CommonResourceState{Ensure: model.EnsurePresent},
+			Metadata:            &model.GroupMetadata{Name: "app", GID: gid, Members: members},
+		}
+	}
+
+	Describe("Status", func() {
+		It("Should handle missing groups", func(ctx context.Context) {
+			runner.EXPECT().Execute(gomock.Any(), "getent", "group", "app").Return(nil, nil, 2, nil)
+
+			state, err := provider.Status(ctx, props(0))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Ensure).To(Equal(model.EnsureAbsent))
+			Expect(state.Metadata.Provider).To(Equal(ProviderName))
+		})
+
+		It("Should parse the group entry", func(ctx context.Context) {
+			runner.EXPECT().Execute(gomock.Any(), "getent", "group", "app").Return([]byte("app:x:1500:bob,alice\n"), nil, 0, nil)
+
+			state, err := provider.Status(ctx, props(0))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Ensure).To(Equal(model.EnsurePresent))
+			Expect(state.Metadata.GID).To(Equal(1500))
+			Expect(state.Metadata.Members).To(Equal([]string{"alice", "bob"}))
+		})
+
+		It("Should handle groups without members", func(ctx context.Context) {
+			runner.EXPECT().Execute(gomock.Any(), "getent", "group", "app").Return([]byte("app:x:1500:\n"), nil, 0, nil)
+
+			state, err := provider.Status(ctx, props(0))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Metadata.Members).To(BeEmpty())
+		})
+
+		It("Should fail for invalid entries", func(ctx context.Context) {
+			runner.EXPECT().Execute(gomock.Any(), "getent", "group", "app").Return([]byte("app:x:abc:\n"), nil, 0, nil)
+
+			_, err := provider.Status(ctx, props(0))
+			Expect(err).To(MatchError(model.ErrInvalidState))
+		})
+
+		It("Should fail when getent fails", func(ctx context.Context) {
+			runner.EXPECT().Execute(gomock.Any(), "getent", "group", "app").Return(nil, []byte("broken\n"), 1, nil)
+
+			_, err := provider.Status(ctx, props(0))
+			Expect(err).To(MatchError("could not look up group app: broken"))
+		})
+	})
+
+	Describe("Create", func() {
+		It("Should create the group with a gid and members", func(ctx context.Context) {
+			runner.EXPECT().Execute(gomock.Any(), "groupadd", "-g", "1500", "app").Return(nil, nil, 0, nil)
+			runner.EXPECT().Execute(gomock.Any(), "gpasswd", "-M", "alice,bob", "app").Return(nil, nil, 0, nil)
+
+			Expect(provider.Create(ctx, props(1500, "bob", "alice", "bob"))).To(Succeed())
+		})
+
+		It("Should create system groups", func(ctx context.Context) {
+			prop := props(0)
+			prop.System = true
+			runner.EXPECT().Execute(gomock.Any(), "groupadd", "-r", "app").Return(nil, nil, 0, nil)
+
+			Expect(provider.Create(ctx, prop)).To(Succeed())
+		})
+
+		It("Should report failures", func(ctx context.Context) {
+			runner.EXPECT().Execute(gomock.Any(), "groupadd", "app").Return(nil, []byte("groupadd: group 'app' already exists\n"), 9, nil)
+
+			Expect(provider.Create(ctx, props(0))).To(MatchError("groupadd failed with exit code 9: groupadd: group 'app' already exists"))
+		})
+	})
+
+	Describe("Modify", func() {
+		It("Should change the gid and members", func(ctx context.Context) {
+			runner.EXPECT().Execute(gomock.Any(), "groupmod", "-g", "1500", "app").Return(nil, nil, 0, nil)
+			runner.EXPECT().Execute(gomock.Any(), "gpasswd", "-M", "alice,bob", "app").Return(nil, nil, 0, nil)
+
+			Expect(provider.Modify(ctx, props(1500, "alice", "bob"), state(1400, "carol"))).To(Succeed())
+		})
+
+		It("Should only change what differs", func(ctx context.Context) {
+			runner.EXPECT().Execute(gomock.Any(), "gpasswd", "-M", "alice,bob", "app").Return(nil, nil, 0, nil)
+
+			Expect(provider.Modify(ctx, props(1500, "alice", "bob"), state(1500, "alice"))).To(Succeed())
+		})
+	})
+
+	Describe("Delete", func() {
+		It("Should delete the group", func(ctx context.Context) {
+			runner.EXPECT().Execute(gomock.Any(), "groupdel", "app").Return(nil, nil, 0, nil)
+
+			Expect(provider.Delete(ctx, props(0))).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,317 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package groupresource
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/choria-io/ccm/internal/registry"
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/resources/base"
+	"github.com/choria-io/ccm/resources/group/groupadd"
+)
+
+var _ base.ProviderFallback = (*Type)(nil)
+var _ base.StatusReporter = (*Type)(nil)
+
+type Type struct {
+	*base.Base
+
+	prop     *model.GroupResourceProperties
+	mgr      model.Manager
+	log      model.Logger
+	provider model.Provider
+
+	mu sync.Mutex
+}
+
+var _ model.Resource = (*Type)(nil)
+var _ GroupProvider = (*groupadd.Provider)(nil)
+
+// New creates a new group resource with the given properties
+func New(ctx context.Context, mgr model.Manager, properties model.GroupResourceProperties) (*Type, error) {
+	env, err := mgr.TemplateEnvironment(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = properties.ResolveTemplates(env)
+	if err != nil {
+		return nil, err
+	}
+
+	loggerArgs := []any{"type", model.GroupTypeName, "name", properties.Name}
+	logger, err := mgr.Logger(loggerArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	properties.CommonResourceProperties.Type = model.GroupTypeName
+
+	t := &Type{
+		prop: &properties,
+		mgr:  mgr,
+		log:  logger,
+	}
+	t.Base = &base.Base{
+		Resource:           t,
+		ResourceProperties: &properties,
+		CommonProperties:   properties.CommonResourceProperties,
+		Log:                logger,
+		UserLogger:         mgr.UserLogger().With(loggerArgs...),
+		Manager:            mgr,
+		Facts:              env.Facts,
+		Data:               env.Data,
+	}
+
+	err = t.Base.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %w", t.String(), model.ErrResourceInvalid, err)
+	}
+
+	t.log.Debug("Created resource instance")
+
+	return t, nil
+}
+
+func (t *Type) ApplyResource(ctx context.Context) (model.ResourceState, error) {
+	var (
+		initialStatus *model.GroupState
+		finalStatus   *model.GroupState
+		p             = t.provider.(GroupProvider)
+		properties    = t.prop
+		noop          = t.mgr.NoopMode()
+		noopMessage   string
+		err           error
+	)
+
+	initialStatus, err = p.Status(ctx, properties)
+	if err != nil {
+		return nil, err
+	}
+
+	isStable, _ := t.isDesiredState(properties, initialStatus)
+	if isStable {
+		t.FinalizeState(initialStatus, noop, "", false, true, false)
+		return initialStatus, nil
+	}
+
+	switch {
+	case properties.Ensure == model.EnsureAbsent:
+		if !noop {
+			t.log.Info("Deleting group")
+			err = p.Delete(ctx, properties)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			t.log.Info("Skipping delete as noop")
+			noopMessage = "Would have deleted group"
+		}
+
+	case initialStatus.Ensure == model.EnsureAbsent:
+		if !noop {
+			t.log.Info("Creating group")
+			err = p.Create(ctx, properties)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			t.log.Info("Skipping create as noop")
+			noopMessage = "Would have created group"
+		}
+
+	default:
+		if !noop {
+			t.log.Info("Modifying group")
+			err = p.Modify(ctx, properties, initialStatus)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			t.log.Info("Skipping modify as noop")
+			noopMessage = t.modifyNoopMessage(properties, initialStatus)
+		}
+	}
+
+	finalStatus = initialStatus
+	if !noop {
+		finalStatus, err = p.Status(ctx, properties)
+		if err != nil {
+			return nil, err
+		}
+
+		var reason string
+		isStable, reason = t.isDesiredState(properties, finalStatus)
+		if !isStable {
+			return nil, fmt.Errorf("%w: %s: %s", model.ErrDesiredStateFailed, properties.Ensure, reason)
+		}
+	}
+
+	t.FinalizeState(finalStatus, noop, noopMessage, true, isStable, false)
+	t.ClassifyChange(finalStatus, initialStatus.Ensure != model.EnsureAbsent)
+
+	return finalStatus, nil
+}
+
+// modifyNoopMessage describes the changes Modify would make to an existing group
+func (t *Type) modifyNoopMessage(properties *model.GroupResourceProperties, state *model.GroupState) string {
+	var msgs []string
+
+	if properties.GID > 0 && properties.GID != state.Metadata.GID {
+		msgs = append(msgs, fmt.Sprintf("Would have changed gid to %d", properties.GID))
+	}
+
+	if len(properties.Members) > 0 {
+		var added, removed []string
+		desired := properties.SortedMembers()
+
+		for _, member := range desired {
+			if !slices.Contains(state.Metadata.Members, member) {
+				added = append(added, member)
+			}
+		}
+		for _, member := range state.Metadata.Members {
+			if !slices.Contains(desired, member) {
+				removed = append(removed, member)
+			}
+		}
+
+		if len(added) > 0 {
+			msgs = append(msgs, fmt.Sprintf("Would have added members %s", strings.Join(added, ",")))
+		}
+		if len(removed) > 0 {
+			msgs = append(msgs, fmt.Sprintf("Would have removed members %s", strings.Join(removed, ",")))
+		}
+	}
+
+	return strings.Join(msgs, ", ")
+}
+
+// isDesiredState reports whether state matches properties by comparing the gid and the sorted member list when
+// set. The second return is a human-readable reason describing the mismatch when stable is false, suitable for
+// inclusion in error messages.
+func (t *Type) isDesiredState(properties *model.GroupResourceProperties, state *model.GroupState) (bool, string) {
+	if properties.Ensure == model.EnsureAbsent {
+		if state.Ensure == model.EnsureAbsent {
+			return true, ""
+		}
+		return false, fmt.Sprintf("group %s still exists", properties.Name)
+	}
+
+	if state.Ensure != model.EnsurePresent {
+		return false, fmt.Sprintf("group %s does not exist", properties.Name)
+	}
+
+	if properties.GID > 0 && state.Metadata.GID != properties.GID {
+		return false, fmt.Sprintf("gid is %d, expected %d", state.Metadata.GID, properties.GID)
+	}
+
+	if len(properties.Members) > 0 {
+		desired := properties.SortedMembers()
+		if !slices.Equal(state.Metadata.Members, desired) {
+			return false, fmt.Sprintf("members are %s, expected %s", strings.Join(state.Metadata.Members, ","), strings.Join(desired, ","))
+		}
+	}
+
+	return true, ""
+}
+
+func (t *Type) Info(ctx context.Context) (any, error) {
+	_, err := t.SelectProvider()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", t.String(), err)
+	}
+
+	return t.provider.(GroupProvider).Status(ctx, t.prop)
+}
+
+// CurrentState reports the current state of the resource without making any changes
+func (t *Type) CurrentState(ctx context.Context) (model.ResourceState, error) {
+	state, err := t.provider.(GroupProvider).Status(ctx, t.prop)
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+func (t *Type) providerUnlocked() string {
+	if t.provider == nil {
+		return ""
+	}
+
+	return t.provider.Name()
+}
+
+// Provider returns the name of the selected provider
+func (t *Type) Provider() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.providerUnlocked()
+}
+
+func (t *Type) selectProviderUnlocked() error {
+	if t.provider != nil {
+		return nil
+	}
+
+	runner, err := t.mgr.NewRunner()
+	if err != nil {
+		return err
+	}
+
+	selected, err := registry.FindSuitableProvider(model.GroupTypeName, t.prop.Provider, t.Facts, t.prop, t.log, runner, t.mgr)
+	if err != nil {
+		return err
+	}
+
+	if selected == nil {
+		return model.ErrNoSuitableProvider
+	}
+
+	t.log.Debug("Selected provider", "provider", selected.Name())
+	t.provider = selected
+
+	return nil
+}
+
+// SelectAlternateProvider replaces the selected provider with the most suitable provider not listed in exclude
+func (t *Type) SelectAlternateProvider(exclude []string) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	runner, err := t.mgr.NewRunner()
+	if err != nil {
+		return "", err
+	}
+
+	selected, err := registry.FindAlternateProvider(model.GroupTypeName, exclude, t.Facts, t.prop, t.log, runner, t.mgr)
+	if err != nil {
+		return "", err
+	}
+
+	t.log.Debug("Selected alternate provider", "provider", selected.Name())
+	t.provider = selected
+
+	return t.providerUnlocked(), nil
+}
+
+func (t *Type) SelectProvider() (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	err := t.selectProviderUnlocked()
+	if err != nil {
+		return "", err
+	}
+
+	return t.providerUnlocked(), nil
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package groupresource
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/choria-io/ccm/internal/registry"
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/model/modelmocks"
+)
+
+func TestGroupResource(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Resources/Group")
+}
+
+var _ = Describe("Group Type", func() {
+	var (
+		facts    = make(map[string]any)
+		data     = make(map[string]any)
+		mgr      *modelmocks.MockManager
+		logger   *modelmocks.MockLogger
+		mockctl  *gomock.Controller
+		provider *MockGroupProvider
+	)
+
+	BeforeEach(func() {
+		mockctl = gomock.NewController(GinkgoT())
+		mgr, logger = modelmocks.NewManager(facts, data, false, mockctl)
+		mgr.EXPECT().NewRunner().AnyTimes().Return(modelmocks.NewMockCommandRunner(mockctl), nil)
+		provider = NewMockGroupProvider(mockctl)
+
+		provider.EXPECT().Name().Return("mock").AnyTimes()
+		logger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+		logger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+	})
+
+	Describe("New", func() {
+		It("Should validate properties", func(ctx context.Context) {
+			_, err := New(ctx, mgr, model.GroupResourceProperties{})
+			Expect(err).To(MatchError(model.ErrResourceNameRequired))
+
+			_, err = New(ctx, mgr, model.GroupResourceProperties{
+				CommonResourceProperties: model.CommonResourceProperties{Name: "app", Ensure: model.EnsurePresent},
+				Members:                  []string{"bad member"},
+			})
+			Expect(err).To(MatchError(ContainSubstring(`invalid member "bad member"`)))
+		})
+	})
+
+	Context("with a prepared provider", func() {
+		var factory *modelmocks.MockProviderFactory
+		var res *Type
+		var err error
+
+		BeforeEach(func(ctx context.Context) {
+			factory = modelmocks.NewMockProviderFactory(mockctl)
+			factory.EXPECT().Name().Return("test").AnyTimes()
+			factory.EXPECT().TypeName().Return(model.GroupTypeName).AnyTimes()
+			factory.EXPECT().New(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(log model.Logger, runner model.CommandRunner) (model.Provider, error) {
+				return provider, nil
+			})
+			factory.EXPECT().IsManageable(facts, gomock.Any()).Return(true, 1, nil).AnyTimes()
+
+			res, err = New(ctx, mgr, model.GroupResourceProperties{
+				CommonResourceProperties: model.CommonResourceProperties{
+					Name:     "app",
+					Ensure:   model.EnsurePresent,
+					Provider: "test",
+				},
+				GID:     1500,
+				Members: []string{"bob", "alice"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			registry.Clear()
+			registry.MustRegister(factory)
+		})
+
+		state := func(gid int, members ...string) *model.GroupState {
+			s := &model.GroupState{
+				CommonResourceState: model.CommonResourceState{Ensure: model.EnsureAbsent},
+				Metadata:            &model.GroupMetadata{Name: "app"},
+			}
+
+			if gid > 0 {
+				s.Ensure = model.EnsurePresent
+				s.Metadata.GID = gid
+				s.Metadata.Members = members
+			}
+
+			return s
+		}
+
+		Describe("Apply", func() {
+			It("Should fail if initial status check fails", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("status failed"))
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Errors).To(ContainElement(ContainSubstring("status failed")))
+			})
+
+			It("Should create missing groups", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(0), nil)
+				provider.EXPECT().Create(gomock.Any(), res.prop).Return(nil)
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(1500, "alice", "bob"), nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Errors).To(BeEmpty())
+				Expect(event.Changed).To(BeTrue())
+			})
+
+			It("Should modify groups with different members", func(ctx context.Context) {
+				initial := state(1500, "alice", "carol")
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(initial, nil)
+				provider.EXPECT().Modify(gomock.Any(), res.prop, initial).Return(nil)
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(1500, "alice", "bob"), nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Errors).To(BeEmpty())
+				Expect(event.Changed).To(BeTrue())
+			})
+
+			It("Should fail when the group does not reach the desired state", func(ctx context.Context) {
+				initial := state(1400, "alice", "bob")
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(initial, nil)
+				provider.EXPECT().Modify(gomock.Any(), res.prop, initial).Return(nil)
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(initial, nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Errors).To(ContainElement(ContainSubstring("gid is 1400, expected 1500")))
+			})
+
+			It("Should not change when the group matches", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(1500, "alice", "bob"), nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Changed).To(BeFalse())
+			})
+
+			It("Should not manage members when none are set", func(ctx context.Context) {
+				res.prop.Members = nil
+
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(1500, "carol"), nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Changed).To(BeFalse())
+			})
+
+			It("Should delete the group when absent", func(ctx context.Context) {
+				res.prop.Ensure = model.EnsureAbsent
+
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(1500), nil)
+				provider.EXPECT().Delete(gomock.Any(), res.prop).Return(nil)
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(0), nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Errors).To(BeEmpty())
+				Expect(event.Changed).To(BeTrue())
+			})
+		})
+
+		Describe("Apply in noop mode", func() {
+			var noopRes *Type
+
+			BeforeEach(func(ctx context.Context) {
+				noopMgr, _ := modelmocks.NewManager(facts, data, true, mockctl)
+				noopMgr.EXPECT().NewRunner().AnyTimes().Return(modelmocks.NewMockCommandRunner(mockctl), nil)
+				noopRes, err = New(ctx, noopMgr, *res.prop)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("Should not create the group", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(0), nil)
+
+				event, err := noopRes.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Changed).To(BeTrue())
+				Expect(event.Noop).To(BeTrue())
+				Expect(event.NoopMessage).To(Equal("Would have created group"))
+			})
+
+			It("Should describe membership changes", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(1400, "carol"), nil)
+
+				event, err := noopRes.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Changed).To(BeTrue())
+				Expect(event.NoopMessage).To(Equal("Would have changed gid to 1500, Would have added members alice,bob, Would have removed members carol"))
+			})
+		})
+	})
+})
@@ -0,0 +1,24 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package hostresource
+
+import (
+	"context"
+
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/resources/host/hostsfile"
+)
+
+func init() {
+	hostsfile.Register()
+}
+
+type HostProvider interface {
+	model.Provider
+
+	Status(ctx context.Context, properties *model.HostResourceProperties) (*model.HostState, error)
+	Set(ctx context.Context, properties *model.HostResourceProperties) error
+	Remove(ctx context.Context, properties *model.HostResourceProperties) error
+}
@@ -0,0 +1,27 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package hostsfile
+
+import (
+	"github.com/choria-io/ccm/internal/registry"
+	iu "github.com/choria-io/ccm/internal/util"
+	"github.com/choria-io/ccm/model"
+)
+
+// Register registers this provider with the registry
+func Register() {
+	registry.MustRegister(&factory{})
+}
+
+type factory struct{}
+
+func (p *factory) TypeName() string { return model.HostTypeName }
+func (p *factory) Name() string     { return ProviderName }
+func (p *factory) New(log model.Logger, runner model.CommandRunner) (model.Provider, error) {
+	return NewHostsFileProvider(log, DefaultFile)
+}
+func (p *factory) IsManageable(_ map[string]any, _ model.ResourceProperties) (bool, int, error) {
+	return iu.FileExists(DefaultFile), 1, nil
+}
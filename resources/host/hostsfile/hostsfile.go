@@ -0,0 +1,238 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package hostsfile
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	iu "github.com/choria-io/ccm/internal/util"
+	"github.com/choria-io/ccm/model"
+)
+
+const (
+	ProviderName = "hostsfile"
+
+	// DefaultFile is the static table lookup file for hostnames
+	DefaultFile = "/etc/hosts"
+)
+
+type Provider struct {
+	log  model.Logger
+	file string
+}
+
+// NewHostsFileProvider creates a provider managing entries in the hosts file file
+func NewHostsFileProvider(log model.Logger, file string) (*Provider, error) {
+	return &Provider{log: log, file: file}, nil
+}
+
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+// hostEntry is a parsed entry from a hosts file
+type hostEntry struct {
+	ip       string
+	hostname string
+	aliases  []string
+}
+
+// Status reports the entry for the hostname in the hosts file
+func (p *Provider) Status(ctx context.Context, properties *model.HostResourceProperties) (*model.HostState, error) {
+	hostname := properties.ManagedHostname()
+
+	state := &model.HostState{
+		CommonResourceState: model.NewCommonResourceState(model.ResourceStatusHostProtocol, model.HostTypeName, properties.Name, model.EnsureAbsent),
+		Metadata: &model.HostMetadata{
+			Name:     properties.Name,
+			File:     p.file,
+			Hostname: hostname,
+			Provider: ProviderName,
+		},
+	}
+
+	lines, err := readLines(p.file)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range lines {
+		entry, ok := parseHostEntry(line)
+		if !ok || entry.hostname != hostname {
+			continue
+		}
+
+		state.Metadata.Matches++
+		if state.Metadata.Matches == 1 {
+			state.Ensure = model.EnsurePresent
+			state.Metadata.IP = entry.ip
+			state.Metadata.Aliases = entry.aliases
+		}
+	}
+
+	return state, nil
+}
+
+// Set writes the entry in place of the first entry for the hostname, other entries for the hostname are removed and
+// the entry is appended when none were found. Unrelated lines are kept as they are.
+func (p *Provider) Set(ctx context.Context, properties *model.HostResourceProperties) error {
+	hostname := properties.ManagedHostname()
+
+	lines, err := readLines(p.file)
+	if err != nil {
+		return err
+	}
+
+	var result []string
+	written := false
+	for _, line := range lines {
+		entry, ok := parseHostEntry(line)
+		if ok && entry.hostname == hostname {
+			if !written {
+				result = append(result, properties.HostsLine())
+				written = true
+			}
+			continue
+		}
+
+		result = append(result, line)
+	}
+
+	if !written {
+		result = append(result, properties.HostsLine())
+	}
+
+	return p.write(result)
+}
+
+// Remove removes every entry for the hostname, a missing file is not an error
+func (p *Provider) Remove(ctx context.Context, properties *model.HostResourceProperties) error {
+	hostname := properties.ManagedHostname()
+
+	lines, err := readLines(p.file)
+	if err != nil {
+		return err
+	}
+
+	var result []string
+	for _, line := range lines {
+		entry, ok := parseHostEntry(line)
+		if ok && entry.hostname == hostname {
+			continue
+		}
+
+		result = append(result, line)
+	}
+
+	if len(result) == len(lines) {
+		return nil
+	}
+
+	return p.write(result)
+}
+
+// write atomically replaces the hosts file keeping the mode and ownership of the existing file
+func (p *Provider) write(lines []string) error {
+	var (
+		mode         os.FileMode = 0644
+		owner, group string
+	)
+
+	stat, err := os.Stat(p.file)
+	switch {
+	case err == nil:
+		mode = stat.Mode().Perm()
+		owner, group, _, err = iu.GetFileOwner(stat)
+		if err != nil {
+			return err
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return err
+	}
+
+	tf, err := os.CreateTemp(filepath.Dir(p.file), fmt.Sprintf(".%s.*", filepath.Base(p.file)))
+	if err != nil {
+		return err
+	}
+	defer tf.Close()
+	defer os.Remove(tf.Name())
+
+	var content []byte
+	if len(lines) > 0 {
+		content = []byte(strings.Join(lines, "\n") + "\n")
+	}
+
+	_, err = tf.Write(content)
+	if err != nil {
+		return err
+	}
+
+	err = tf.Chmod(mode)
+	if err != nil {
+		return fmt.Errorf("could not set mode on temporary file: %w", err)
+	}
+
+	if owner != "" {
+		err = iu.ChownFile(tf, owner, group)
+		if err != nil {
+			return fmt.Errorf("could not set owner on temporary file: %w", err)
+		}
+	}
+
+	err = tf.Sync()
+	if err != nil {
+		return fmt.Errorf("could not sync temporary file: %w", err)
+	}
+
+	err = tf.Close()
+	if err != nil {
+		return fmt.Errorf("could not close temporary file: %w", err)
+	}
+
+	err = os.Rename(tf.Name(), p.file)
+	if err != nil {
+		return fmt.Errorf("could not rename temporary file: %w", err)
+	}
+
+	p.log.Debug("Wrote hosts file", "file", p.file)
+
+	return nil
+}
+
+func readLines(file string) ([]string, error) {
+	content, err := os.ReadFile(file)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	content = bytes.TrimSuffix(content, []byte("\n"))
+	if len(content) == 0 {
+		return nil, nil
+	}
+
+	return strings.Split(string(content), "\n"), nil
+}
+
+// parseHostEntry parses a line in the format described in hosts(5), returns false for comments, blank lines and
+// lines without a hostname
+func parseHostEntry(line string) (*hostEntry, bool) {
+	line, _, _ = strings.Cut(line, "#")
+
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return nil, false
+	}
+
+	return &hostEntry{ip: fields[0], hostname: fields[1], aliases: fields[2:]}, true
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package hostsfile
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/model/modelmocks"
+)
+
+func TestHostsFileProvider(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Resources/Host/HostsFile")
+}
+
+var _ = Describe("HostsFile Provider", func() {
+	var (
+		mockctl   *gomock.Controller
+		logger    *modelmocks.MockLogger
+		provider  *Provider
+		hostsFile string
+		err       error
+	)
+
+	const hosts = `# static hosts
+127.0.0.1	localhost localhost.localdomain
+::1	localhost ip6-localhost
+
+192.168.1.5	web.example.net web # frontend
+`
+
+	BeforeEach(func() {
+		mockctl = gomock.NewController(GinkgoT())
+		logger = modelmocks.NewMockLogger(mockctl)
+		logger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+
+		hostsFile = filepath.Join(GinkgoT().TempDir(), "hosts")
+		Expect(os.WriteFile(hostsFile, []byte(hosts), 0640)).To(Succeed())
+
+		provider, err = NewHostsFileProvider(logger, hostsFile)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	props := func() *model.HostResourceProperties {
+		return &model.HostResourceProperties{
+			CommonResourceProperties: model.CommonResourceProperties{Name: "db.example.net", Ensure: model.EnsurePresent},
+			IP:                       "192.168.1.10",
+			Aliases:                  []string{"db"},
+		}
+	}
+
+	readHosts := func() string {
+		c, err := os.ReadFile(hostsFile)
+		Expect(err).ToNot(HaveOccurred())
+		return string(c)
+	}
+
+	Describe("Status", func() {
+		It("Should handle missing files", func(ctx context.Context) {
+			Expect(os.Remove(hostsFile)).To(Succeed())
+
+			state, err := provider.Status(ctx, props())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Ensure).To(Equal(model.EnsureAbsent))
+			Expect(state.Metadata.File).To(Equal(hostsFile))
+		})
+
+		It("Should report entries keyed by hostname", func(ctx context.Context) {
+			p := props()
+			p.Name = "web"
+			p.Hostname = "web.example.net"
+
+			state, err := provider.Status(ctx, p)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Ensure).To(Equal(model.EnsurePresent))
+			Expect(state.Metadata.Matches).To(Equal(1))
+			Expect(state.Metadata.IP).To(Equal("192.168.1.5"))
+			Expect(state.Metadata.Hostname).To(Equal("web.example.net"))
+			Expect(state.Metadata.Aliases).To(Equal([]string{"web"}))
+		})
+
+		It("Should not match hostnames listed as aliases", func(ctx context.Context) {
+			p := props()
+			p.Name = "web"
+
+			state, err := provider.Status(ctx, p)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Ensure).To(Equal(model.EnsureAbsent))
+		})
+	})
+
+	Describe("Set", func() {
+		It("Should append new entries and preserve other lines and the mode", func(ctx context.Context) {
+			Expect(provider.Set(ctx, props())).To(Succeed())
+			Expect(readHosts()).To(Equal(hosts + "192.168.1.10 db.example.net db\n"))
+
+			stat, err := os.Stat(hostsFile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stat.Mode().Perm()).To(Equal(os.FileMode(0640)))
+		})
+
+		It("Should be idempotent", func(ctx context.Context) {
+			Expect(provider.Set(ctx, props())).To(Succeed())
+			first := readHosts()
+
+			state, err := provider.Status(ctx, props())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Ensure).To(Equal(model.EnsurePresent))
+			Expect(state.Metadata.Matches).To(Equal(1))
+
+			Expect(provider.Set(ctx, props())).To(Succeed())
+			Expect(readHosts()).To(Equal(first))
+		})
+
+		It("Should replace the entry in place and remove duplicates", func(ctx context.Context) {
+			Expect(os.WriteFile(hostsFile, []byte("127.0.0.1 localhost\n10.0.0.1 db.example.net\n10.0.0.2 web\n10.0.0.3 db.example.net old\n"), 0644)).To(Succeed())
+
+			Expect(provider.Set(ctx, props())).To(Succeed())
+			Expect(readHosts()).To(Equal("127.0.0.1 localhost\n192.168.1.10 db.example.net db\n10.0.0.2 web\n"))
+		})
+
+		It("Should create missing files", func(ctx context.Context) {
+			Expect(os.Remove(hostsFile)).To(Succeed())
+
+			Expect(provider.Set(ctx, props())).To(Succeed())
+			Expect(readHosts()).To(Equal("192.168.1.10 db.example.net db\n"))
+		})
+	})
+
+	Describe("Remove", func() {
+		It("Should remove all entries for the hostname", func(ctx context.Context) {
+			Expect(os.WriteFile(hostsFile, []byte("127.0.0.1 localhost\n10.0.0.1 db.example.net\n# keep\n10.0.0.3 db.example.net old\n"), 0644)).To(Succeed())
+
+			Expect(provider.Remove(ctx, props())).To(Succeed())
+			Expect(readHosts()).To(Equal("127.0.0.1 localhost\n# keep\n"))
+		})
+
+		It("Should not modify files without the entry", func(ctx context.Context) {
+			Expect(provider.Remove(ctx, props())).To(Succeed())
+			Expect(readHosts()).To(Equal(hosts))
+		})
+
+		It("Should handle missing files", func(ctx context.Context) {
+			Expect(os.Remove(hostsFile)).To(Succeed())
+			Expect(provider.Remove(ctx, props())).To(Succeed())
+		})
+	})
+})
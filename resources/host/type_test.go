@@ -0,0 +1,195 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package hostresource
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/choria-io/ccm/internal/registry"
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/model/modelmocks"
+)
+
+func TestHostResource(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Resources/Host")
+}
+
+var _ = Describe("Host Type", func() {
+	var (
+		facts    = make(map[string]any)
+		data     = make(map[string]any)
+		mgr      *modelmocks.MockManager
+		logger   *modelmocks.MockLogger
+		mockctl  *gomock.Controller
+		provider *MockHostProvider
+	)
+
+	BeforeEach(func() {
+		mockctl = gomock.NewController(GinkgoT())
+		mgr, logger = modelmocks.NewManager(facts, data, false, mockctl)
+		mgr.EXPECT().NewRunner().AnyTimes().Return(modelmocks.NewMockCommandRunner(mockctl), nil)
+		provider = NewMockHostProvider(mockctl)
+
+		provider.EXPECT().Name().Return("mock").AnyTimes()
+		logger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+		logger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+	})
+
+	Describe("New", func() {
+		It("Should validate properties", func(ctx context.Context) {
+			_, err := New(ctx, mgr, model.HostResourceProperties{})
+			Expect(err).To(MatchError(model.ErrResourceNameRequired))
+
+			_, err = New(ctx, mgr, model.HostResourceProperties{
+				CommonResourceProperties: model.CommonResourceProperties{Name: "db.example.net", Ensure: model.EnsurePresent},
+			})
+			Expect(err).To(MatchError(ContainSubstring("ip is required")))
+		})
+	})
+
+	Context("with a prepared provider", func() {
+		var factory *modelmocks.MockProviderFactory
+		var res *Type
+		var err error
+
+		BeforeEach(func(ctx context.Context) {
+			factory = modelmocks.NewMockProviderFactory(mockctl)
+			factory.EXPECT().Name().Return("test").AnyTimes()
+			factory.EXPECT().TypeName().Return(model.HostTypeName).AnyTimes()
+			factory.EXPECT().New(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(log model.Logger, runner model.CommandRunner) (model.Provider, error) {
+				return provider, nil
+			})
+			factory.EXPECT().IsManageable(facts, gomock.Any()).Return(true, 1, nil).AnyTimes()
+
+			res, err = New(ctx, mgr, model.HostResourceProperties{
+				CommonResourceProperties: model.CommonResourceProperties{
+					Name:     "db.example.net",
+					Ensure:   model.EnsurePresent,
+					Provider: "test",
+				},
+				IP:      "192.168.1.10",
+				Aliases: []string{"db", "mysql"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			registry.Clear()
+			registry.MustRegister(factory)
+		})
+
+		state := func(matches int, ip string, aliases ...string) *model.HostState {
+			s := &model.HostState{
+				CommonResourceState: model.CommonResourceState{Ensure: model.EnsureAbsent},
+				Metadata:            &model.HostMetadata{Name: "db.example.net", Hostname: "db.example.net"},
+			}
+
+			if matches > 0 {
+				s.Ensure = model.EnsurePresent
+				s.Metadata.Matches = matches
+				s.Metadata.IP = ip
+				s.Metadata.Aliases = aliases
+			}
+
+			return s
+		}
+
+		Describe("Apply", func() {
+			It("Should fail if initial status check fails", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("status failed"))
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Errors).To(ContainElement(ContainSubstring("status failed")))
+			})
+
+			It("Should add missing entries", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(0, ""), nil)
+				provider.EXPECT().Set(gomock.Any(), res.prop).Return(nil)
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(1, "192.168.1.10", "db", "mysql"), nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Errors).To(BeEmpty())
+				Expect(event.Changed).To(BeTrue())
+			})
+
+			It("Should update entries with a different ip", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(1, "192.168.1.11", "db", "mysql"), nil)
+				provider.EXPECT().Set(gomock.Any(), res.prop).Return(nil)
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(1, "192.168.1.10", "db", "mysql"), nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Errors).To(BeEmpty())
+				Expect(event.Changed).To(BeTrue())
+			})
+
+			It("Should update entries with different aliases", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(1, "192.168.1.10", "db"), nil)
+				provider.EXPECT().Set(gomock.Any(), res.prop).Return(nil)
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(1, "192.168.1.10", "db", "mysql"), nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Errors).To(BeEmpty())
+				Expect(event.Changed).To(BeTrue())
+			})
+
+			It("Should fail when duplicates remain", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(2, "192.168.1.10", "db", "mysql"), nil)
+				provider.EXPECT().Set(gomock.Any(), res.prop).Return(nil)
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(2, "192.168.1.10", "db", "mysql"), nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Errors).To(ContainElement(ContainSubstring("2 matching entries found")))
+			})
+
+			It("Should not change when the entry matches in any alias order", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(1, "192.168.1.10", "mysql", "db"), nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Changed).To(BeFalse())
+			})
+
+			It("Should remove the entry when absent", func(ctx context.Context) {
+				res.prop.Ensure = model.EnsureAbsent
+
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(1, "192.168.1.10"), nil)
+				provider.EXPECT().Remove(gomock.Any(), res.prop).Return(nil)
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(0, ""), nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Errors).To(BeEmpty())
+				Expect(event.Changed).To(BeTrue())
+			})
+		})
+
+		Describe("Apply in noop mode", func() {
+			It("Should not add the entry", func(ctx context.Context) {
+				noopMgr, _ := modelmocks.NewManager(facts, data, true, mockctl)
+				noopMgr.EXPECT().NewRunner().AnyTimes().Return(modelmocks.NewMockCommandRunner(mockctl), nil)
+				noopRes, err := New(ctx, noopMgr, *res.prop)
+				Expect(err).ToNot(HaveOccurred())
+
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(0, ""), nil)
+
+				event, err := noopRes.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Changed).To(BeTrue())
+				Expect(event.Noop).To(BeTrue())
+				Expect(event.NoopMessage).To(Equal("Would have added host entry"))
+			})
+		})
+	})
+})
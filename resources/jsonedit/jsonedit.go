@@ -0,0 +1,24 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package jsoneditresource
+
+import (
+	"context"
+
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/resources/jsonedit/posix"
+)
+
+func init() {
+	posix.Register()
+}
+
+type JsonEditProvider interface {
+	model.Provider
+
+	Set(ctx context.Context, properties *model.JsonEditResourceProperties) error
+	Remove(ctx context.Context, properties *model.JsonEditResourceProperties) error
+	Status(ctx context.Context, properties *model.JsonEditResourceProperties) (*model.JsonEditState, error)
+}
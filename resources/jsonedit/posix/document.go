@@ -0,0 +1,190 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package posix
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/choria-io/ccm/model"
+)
+
+// decodeDocument parses a JSON or YAML document, empty documents are treated as an empty object
+func decodeDocument(raw []byte, format string) (any, error) {
+	var doc any
+
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return map[string]any{}, nil
+	}
+
+	switch format {
+	case model.JsonEditFormatJSON:
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		err := dec.Decode(&doc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON document: %w", err)
+		}
+
+	case model.JsonEditFormatYAML:
+		err := yaml.Unmarshal(raw, &doc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid YAML document: %w", err)
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+
+	return doc, nil
+}
+
+// encodeDocument serializes doc using a stable layout so that repeated writes of
+// the same document always produce identical bytes
+func encodeDocument(doc any, format string) ([]byte, error) {
+	switch format {
+	case model.JsonEditFormatJSON:
+		buf := bytes.NewBuffer(nil)
+		enc := json.NewEncoder(buf)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+
+		err := enc.Encode(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+
+	case model.JsonEditFormatYAML:
+		return yaml.Marshal(doc)
+
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// lookupPath finds the value at path in doc
+func lookupPath(doc any, path []string) (any, bool) {
+	current := doc
+
+	for _, segment := range path {
+		switch typed := current.(type) {
+		case map[string]any:
+			val, ok := typed[segment]
+			if !ok {
+				return nil, false
+			}
+			current = val
+
+		case []any:
+			idx, err := strconv.Atoi(segment)
+			if err != nil || idx < 0 || idx >= len(typed) {
+				return nil, false
+			}
+			current = typed[idx]
+
+		default:
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+// setPath stores value at path in doc creating any missing intermediate objects, the updated document is returned
+func setPath(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	segment := path[0]
+
+	switch typed := doc.(type) {
+	case nil:
+		child, err := setPath(nil, path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]any{segment: child}, nil
+
+	case map[string]any:
+		child, err := setPath(typed[segment], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		typed[segment] = child
+
+		return typed, nil
+
+	case []any:
+		idx, err := strconv.Atoi(segment)
+		if err != nil || idx < 0 || idx >= len(typed) {
+			return nil, fmt.Errorf("invalid list index %q", segment)
+		}
+
+		child, err := setPath(typed[idx], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		typed[idx] = child
+
+		return typed, nil
+
+	default:
+		return nil, fmt.Errorf("cannot set %q on a %T value", segment, doc)
+	}
+}
+
+// removePath deletes the value at path from doc, reports if anything was removed
+func removePath(doc any, path []string) (any, bool) {
+	if len(path) == 0 {
+		return doc, false
+	}
+
+	segment := path[0]
+	last := len(path) == 1
+
+	switch typed := doc.(type) {
+	case map[string]any:
+		child, ok := typed[segment]
+		if !ok {
+			return typed, false
+		}
+
+		if last {
+			delete(typed, segment)
+			return typed, true
+		}
+
+		updated, removed := removePath(child, path[1:])
+		typed[segment] = updated
+
+		return typed, removed
+
+	case []any:
+		idx, err := strconv.Atoi(segment)
+		if err != nil || idx < 0 || idx >= len(typed) {
+			return typed, false
+		}
+
+		if last {
+			return append(typed[:idx], typed[idx+1:]...), true
+		}
+
+		updated, removed := removePath(typed[idx], path[1:])
+		typed[idx] = updated
+
+		return typed, removed
+
+	default:
+		return doc, false
+	}
+}
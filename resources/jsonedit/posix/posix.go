@@ -0,0 +1,181 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package posix
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	iu "github.com/choria-io/ccm/internal/util"
+	"github.com/choria-io/ccm/model"
+)
+
+const ProviderName = "posix"
+
+type Provider struct {
+	log model.Logger
+}
+
+func NewPosixProvider(log model.Logger) (*Provider, error) {
+	return &Provider{log: log}, nil
+}
+
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+// Status reads the document and reports on the value found at the requested path
+func (p *Provider) Status(ctx context.Context, properties *model.JsonEditResourceProperties) (*model.JsonEditState, error) {
+	format, err := properties.DocumentFormat()
+	if err != nil {
+		return nil, err
+	}
+
+	state := &model.JsonEditState{
+		CommonResourceState: model.NewCommonResourceState(model.ResourceStatusJsonEditProtocol, model.JsonEditTypeName, properties.Name, model.EnsureAbsent),
+		Metadata: &model.JsonEditMetadata{
+			Name:     properties.Name,
+			Path:     properties.Path,
+			Format:   format,
+			Provider: ProviderName,
+		},
+	}
+
+	raw, err := os.ReadFile(properties.Name)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return state, nil
+	case err != nil:
+		return nil, err
+	}
+
+	state.Metadata.FileExists = true
+
+	state.Metadata.Checksum, err = iu.Sha256HashBytes(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := decodeDocument(raw, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", properties.Name, err)
+	}
+
+	val, ok := lookupPath(doc, properties.PathSegments())
+	if ok {
+		state.Ensure = model.EnsurePresent
+		state.Metadata.PathExists = true
+		state.Metadata.Value = val
+	}
+
+	return state, nil
+}
+
+// Set stores the value at the requested path, creating intermediate objects as needed
+func (p *Provider) Set(ctx context.Context, properties *model.JsonEditResourceProperties) error {
+	return p.edit(properties, func(doc any, path []string) (any, bool, error) {
+		updated, err := setPath(doc, path, properties.Value)
+		return updated, true, err
+	})
+}
+
+// Remove deletes the value at the requested path, a path that does not exist is a no-op
+func (p *Provider) Remove(ctx context.Context, properties *model.JsonEditResourceProperties) error {
+	if !iu.FileExists(properties.Name) {
+		return nil
+	}
+
+	return p.edit(properties, func(doc any, path []string) (any, bool, error) {
+		updated, removed := removePath(doc, path)
+		return updated, removed, nil
+	})
+}
+
+// edit parses the document, passes it to cb and writes back the result when cb reports a change and the encoded document differs
+func (p *Provider) edit(properties *model.JsonEditResourceProperties, cb func(doc any, path []string) (any, bool, error)) error {
+	format, err := properties.DocumentFormat()
+	if err != nil {
+		return err
+	}
+
+	stat, err := os.Stat(properties.Name)
+	if err != nil {
+		return err
+	}
+	if !stat.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", properties.Name)
+	}
+
+	raw, err := os.ReadFile(properties.Name)
+	if err != nil {
+		return err
+	}
+
+	doc, err := decodeDocument(raw, format)
+	if err != nil {
+		return fmt.Errorf("%s: %w", properties.Name, err)
+	}
+
+	doc, changed, err := cb(doc, properties.PathSegments())
+	if err != nil {
+		return fmt.Errorf("%s: %w", properties.Name, err)
+	}
+	if !changed {
+		p.log.Debug("Document unchanged, not writing", "file", properties.Name)
+		return nil
+	}
+
+	updated, err := encodeDocument(doc, format)
+	if err != nil {
+		return err
+	}
+
+	if bytes.Equal(raw, updated) {
+		p.log.Debug("Document unchanged, not writing", "file", properties.Name)
+		return nil
+	}
+
+	return p.write(properties.Name, stat, updated)
+}
+
+// write atomically replaces file with contents while retaining the original mode and ownership
+func (p *Provider) write(file string, stat os.FileInfo, contents []byte) error {
+	tf, err := os.CreateTemp(filepath.Dir(file), fmt.Sprintf("%s.*", filepath.Base(file)))
+	if err != nil {
+		return err
+	}
+	defer tf.Close()
+	defer os.Remove(tf.Name())
+
+	_, err = tf.Write(contents)
+	if err != nil {
+		return err
+	}
+
+	err = tf.Close()
+	if err != nil {
+		return fmt.Errorf("could not close temporary file: %w", err)
+	}
+
+	err = os.Rename(tf.Name(), file)
+	if err != nil {
+		return fmt.Errorf("could not rename temporary file: %w", err)
+	}
+
+	// chown runs before chmod because chown(2) clears setuid/setgid bits.
+	owner, group, _, err := iu.GetFileOwner(stat)
+	if err == nil {
+		err = iu.ChownPath(file, owner, group)
+		if err != nil {
+			return err
+		}
+	}
+
+	return os.Chmod(file, stat.Mode().Perm())
+}
@@ -0,0 +1,196 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package posix
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	iu "github.com/choria-io/ccm/internal/util"
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/model/modelmocks"
+)
+
+func TestPosixProvider(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Resources/JsonEdit/Posix")
+}
+
+var _ = Describe("Posix Provider", func() {
+	var (
+		mockctl  *gomock.Controller
+		logger   *modelmocks.MockLogger
+		provider *Provider
+		tmpDir   string
+		err      error
+	)
+
+	BeforeEach(func() {
+		mockctl = gomock.NewController(GinkgoT())
+		logger = modelmocks.NewMockLogger(mockctl)
+		logger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+
+		provider, err = NewPosixProvider(logger)
+		Expect(err).ToNot(HaveOccurred())
+
+		tmpDir = GinkgoT().TempDir()
+	})
+
+	props := func(file string, path string, value any) *model.JsonEditResourceProperties {
+		return &model.JsonEditResourceProperties{
+			CommonResourceProperties: model.CommonResourceProperties{Name: file, Ensure: model.EnsurePresent},
+			Path:                     path,
+			Value:                    value,
+		}
+	}
+
+	Describe("Status", func() {
+		It("Should handle missing files", func(ctx context.Context) {
+			state, err := provider.Status(ctx, props(filepath.Join(tmpDir, "missing.json"), "a", 1))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Ensure).To(Equal(model.EnsureAbsent))
+			Expect(state.Metadata.FileExists).To(BeFalse())
+			Expect(state.Metadata.PathExists).To(BeFalse())
+			Expect(state.Metadata.Format).To(Equal(model.JsonEditFormatJSON))
+		})
+
+		It("Should find values in JSON documents", func(ctx context.Context) {
+			file := filepath.Join(tmpDir, "app.json")
+			Expect(os.WriteFile(file, []byte(`{"server":{"port":80,"hosts":["a","b"]}}`), 0644)).To(Succeed())
+
+			state, err := provider.Status(ctx, props(file, "server.port", 80))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Ensure).To(Equal(model.EnsurePresent))
+			Expect(state.Metadata.PathExists).To(BeTrue())
+			Expect(state.Metadata.Value).To(Equal(json.Number("80")))
+
+			state, err = provider.Status(ctx, props(file, "server.hosts.1", "b"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Metadata.Value).To(Equal("b"))
+
+			state, err = provider.Status(ctx, props(file, "server.tls", true))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Ensure).To(Equal(model.EnsureAbsent))
+			Expect(state.Metadata.FileExists).To(BeTrue())
+			Expect(state.Metadata.PathExists).To(BeFalse())
+		})
+
+		It("Should find values in YAML documents", func(ctx context.Context) {
+			file := filepath.Join(tmpDir, "app.yaml")
+			Expect(os.WriteFile(file, []byte("server:\n  name: web\n"), 0644)).To(Succeed())
+
+			state, err := provider.Status(ctx, props(file, "server.name", "web"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Metadata.Format).To(Equal(model.JsonEditFormatYAML))
+			Expect(state.Metadata.Value).To(Equal("web"))
+		})
+
+		It("Should fail for invalid documents", func(ctx context.Context) {
+			file := filepath.Join(tmpDir, "app.json")
+			Expect(os.WriteFile(file, []byte(`{"server":`), 0644)).To(Succeed())
+
+			_, err := provider.Status(ctx, props(file, "server", 1))
+			Expect(err).To(MatchError(ContainSubstring("invalid JSON document")))
+		})
+	})
+
+	Describe("Set", func() {
+		It("Should require the file to exist", func(ctx context.Context) {
+			err := provider.Set(ctx, props(filepath.Join(tmpDir, "missing.json"), "a", 1))
+			Expect(err).To(MatchError(os.ErrNotExist))
+		})
+
+		It("Should create intermediate objects and preserve other values", func(ctx context.Context) {
+			file := filepath.Join(tmpDir, "app.json")
+			Expect(os.WriteFile(file, []byte(`{"name":"web","count":10}`), 0600)).To(Succeed())
+
+			Expect(provider.Set(ctx, props(file, "server.tls.enabled", true))).To(Succeed())
+
+			contents, err := os.ReadFile(file)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(contents)).To(Equal("{\n  \"count\": 10,\n  \"name\": \"web\",\n  \"server\": {\n    \"tls\": {\n      \"enabled\": true\n    }\n  }\n}\n"))
+
+			stat, err := os.Stat(file)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stat.Mode().Perm()).To(Equal(os.FileMode(0600)))
+		})
+
+		It("Should support escaped dots in keys", func(ctx context.Context) {
+			file := filepath.Join(tmpDir, "app.json")
+			Expect(os.WriteFile(file, []byte(`{}`), 0644)).To(Succeed())
+
+			Expect(provider.Set(ctx, props(file, `hosts.www\.example\.net`, "1.2.3.4"))).To(Succeed())
+
+			state, err := provider.Status(ctx, props(file, `hosts.www\.example\.net`, "1.2.3.4"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Metadata.Value).To(Equal("1.2.3.4"))
+		})
+
+		It("Should not rewrite documents that are already in the desired state", func(ctx context.Context) {
+			file := filepath.Join(tmpDir, "app.json")
+			Expect(os.WriteFile(file, []byte(`{}`), 0644)).To(Succeed())
+
+			Expect(provider.Set(ctx, props(file, "a", 1))).To(Succeed())
+			before, err := iu.Sha256HashFile(file)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(provider.Set(ctx, props(file, "a", 1))).To(Succeed())
+			after, err := iu.Sha256HashFile(file)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(after).To(Equal(before))
+		})
+
+		It("Should fail when an intermediate value is not an object", func(ctx context.Context) {
+			file := filepath.Join(tmpDir, "app.json")
+			Expect(os.WriteFile(file, []byte(`{"server":"web"}`), 0644)).To(Succeed())
+
+			err := provider.Set(ctx, props(file, "server.port", 80))
+			Expect(err).To(MatchError(ContainSubstring(`cannot set "port" on a string value`)))
+		})
+
+		It("Should edit YAML documents", func(ctx context.Context) {
+			file := filepath.Join(tmpDir, "app.yaml")
+			Expect(os.WriteFile(file, []byte("server:\n  name: web\n"), 0644)).To(Succeed())
+
+			Expect(provider.Set(ctx, props(file, "server.port", 80))).To(Succeed())
+
+			contents, err := os.ReadFile(file)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(contents)).To(Equal("server:\n  name: web\n  port: 80\n"))
+		})
+	})
+
+	Describe("Remove", func() {
+		It("Should remove keys and leave the rest of the document", func(ctx context.Context) {
+			file := filepath.Join(tmpDir, "app.json")
+			Expect(os.WriteFile(file, []byte(`{"server":{"port":80,"name":"web"}}`), 0644)).To(Succeed())
+
+			Expect(provider.Remove(ctx, props(file, "server.port", nil))).To(Succeed())
+
+			contents, err := os.ReadFile(file)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(contents)).To(Equal("{\n  \"server\": {\n    \"name\": \"web\"\n  }\n}\n"))
+		})
+
+		It("Should treat missing files and paths as a no-op", func(ctx context.Context) {
+			Expect(provider.Remove(ctx, props(filepath.Join(tmpDir, "missing.json"), "a", nil))).To(Succeed())
+
+			file := filepath.Join(tmpDir, "app.json")
+			Expect(os.WriteFile(file, []byte(`{"a":1}`), 0644)).To(Succeed())
+			Expect(provider.Remove(ctx, props(file, "b.c", nil))).To(Succeed())
+
+			contents, err := os.ReadFile(file)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(contents)).To(Equal(`{"a":1}`))
+		})
+	})
+})
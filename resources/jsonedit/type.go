@@ -0,0 +1,254 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package jsoneditresource
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/choria-io/ccm/internal/registry"
+	iu "github.com/choria-io/ccm/internal/util"
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/resources/base"
+	"github.com/choria-io/ccm/resources/jsonedit/posix"
+)
+
+var _ base.StatusReporter = (*Type)(nil)
+
+type Type struct {
+	*base.Base
+
+	prop     *model.JsonEditResourceProperties
+	mgr      model.Manager
+	log      model.Logger
+	provider model.Provider
+
+	mu sync.Mutex
+}
+
+var _ model.Resource = (*Type)(nil)
+var _ JsonEditProvider = (*posix.Provider)(nil)
+
+// New creates a new jsonedit resource with the given properties
+func New(ctx context.Context, mgr model.Manager, properties model.JsonEditResourceProperties) (*Type, error) {
+	env, err := mgr.TemplateEnvironment(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = properties.ResolveTemplates(env)
+	if err != nil {
+		return nil, err
+	}
+
+	loggerArgs := []any{"type", model.JsonEditTypeName, "name", properties.Name, "path", properties.Path}
+	logger, err := mgr.Logger(loggerArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	properties.CommonResourceProperties.Type = model.JsonEditTypeName
+
+	t := &Type{
+		prop: &properties,
+		mgr:  mgr,
+		log:  logger,
+	}
+	t.Base = &base.Base{
+		Resource:           t,
+		ResourceProperties: &properties,
+		CommonProperties:   properties.CommonResourceProperties,
+		Log:                logger,
+		UserLogger:         mgr.UserLogger().With(loggerArgs...),
+		Manager:            mgr,
+		Facts:              env.Facts,
+		Data:               env.Data,
+	}
+
+	err = t.Base.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %w", t.String(), model.ErrResourceInvalid, err)
+	}
+
+	t.log.Debug("Created resource instance")
+
+	return t, nil
+}
+
+func (t *Type) ApplyResource(ctx context.Context) (model.ResourceState, error) {
+	var (
+		initialStatus *model.JsonEditState
+		finalStatus   *model.JsonEditState
+		p             = t.provider.(JsonEditProvider)
+		properties    = t.prop
+		noop          = t.mgr.NoopMode()
+		noopMessage   string
+		err           error
+	)
+
+	initialStatus, err = p.Status(ctx, properties)
+	if err != nil {
+		return nil, err
+	}
+
+	isStable, _, err := t.isDesiredState(properties, initialStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	if isStable {
+		t.FinalizeState(initialStatus, noop, "", false, true, false)
+		return initialStatus, nil
+	}
+
+	switch properties.Ensure {
+	case model.EnsureAbsent:
+		if !noop {
+			t.log.Info("Removing value")
+			err = p.Remove(ctx, properties)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			t.log.Info("Skipping remove as noop")
+			noopMessage = fmt.Sprintf("Would have removed %s", properties.Path)
+		}
+
+	default:
+		if !noop {
+			t.log.Info("Setting value")
+			err = p.Set(ctx, properties)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			t.log.Info("Skipping set as noop")
+			noopMessage = fmt.Sprintf("Would have set %s", properties.Path)
+		}
+	}
+
+	finalStatus = initialStatus
+	if !noop {
+		finalStatus, err = p.Status(ctx, properties)
+		if err != nil {
+			return nil, err
+		}
+
+		var reason string
+		isStable, reason, err = t.isDesiredState(properties, finalStatus)
+		if err != nil {
+			return nil, err
+		}
+		if !isStable {
+			return nil, fmt.Errorf("%w: %s: %s", model.ErrDesiredStateFailed, properties.Ensure, reason)
+		}
+	}
+
+	t.FinalizeState(finalStatus, noop, noopMessage, true, isStable, false)
+	t.ClassifyChange(finalStatus, initialStatus.Ensure != model.EnsureAbsent)
+
+	return finalStatus, nil
+}
+
+// isDesiredState reports whether state matches properties, only the value at the
+// requested path is considered. The second return is a human-readable reason
+// describing the mismatch when stable is false, suitable for inclusion in error messages.
+func (t *Type) isDesiredState(properties *model.JsonEditResourceProperties, state *model.JsonEditState) (bool, string, error) {
+	meta := state.Metadata
+
+	if properties.Ensure == model.EnsureAbsent {
+		if !meta.PathExists {
+			return true, "", nil
+		}
+		return false, fmt.Sprintf("%s still exists", properties.Path), nil
+	}
+
+	if !meta.FileExists {
+		return false, "file does not exist", nil
+	}
+
+	if !meta.PathExists {
+		t.log.Debug("Path does not exist", "path", properties.Path)
+		return false, fmt.Sprintf("%s does not exist", properties.Path), nil
+	}
+
+	matched, err := iu.JSONEqual(properties.Value, meta.Value)
+	if err != nil {
+		return false, "", err
+	}
+	if !matched {
+		t.log.Debug("Value does not match", "requested", properties.Value, "state", meta.Value)
+		return false, fmt.Sprintf("value mismatch at %s", properties.Path), nil
+	}
+
+	return true, "", nil
+}
+
+func (t *Type) Info(ctx context.Context) (any, error) {
+	_, err := t.SelectProvider()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", t.String(), err)
+	}
+
+	return t.provider.(JsonEditProvider).Status(ctx, t.prop)
+}
+
+// CurrentState reports the current state of the resource without making any changes
+func (t *Type) CurrentState(ctx context.Context) (model.ResourceState, error) {
+	state, err := t.provider.(JsonEditProvider).Status(ctx, t.prop)
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+func (t *Type) providerUnlocked() string {
+	if t.provider == nil {
+		return ""
+	}
+
+	return t.provider.Name()
+}
+
+func (t *Type) Provider() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.providerUnlocked()
+}
+
+func (t *Type) selectProviderUnlocked() error {
+	if t.provider != nil {
+		return nil
+	}
+
+	selected, err := registry.FindSuitableProvider(model.JsonEditTypeName, t.prop.Provider, t.Facts, t.prop, t.log, nil, t.mgr)
+	if err != nil {
+		return err
+	}
+
+	if selected == nil {
+		return model.ErrNoSuitableProvider
+	}
+
+	t.log.Debug("Selected provider", "provider", selected.Name())
+	t.provider = selected
+
+	return nil
+}
+
+func (t *Type) SelectProvider() (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	err := t.selectProviderUnlocked()
+	if err != nil {
+		return "", err
+	}
+
+	return t.providerUnlocked(), nil
+}
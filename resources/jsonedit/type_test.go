@@ -0,0 +1,167 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package jsoneditresource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/choria-io/ccm/internal/registry"
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/model/modelmocks"
+)
+
+func TestJsonEditResource(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Resources/JsonEdit")
+}
+
+var _ = Describe("JsonEdit Type", func() {
+	var (
+		facts    = make(map[string]any)
+		data     = make(map[string]any)
+		mgr      *modelmocks.MockManager
+		logger   *modelmocks.MockLogger
+		mockctl  *gomock.Controller
+		provider *MockJsonEditProvider
+	)
+
+	BeforeEach(func() {
+		mockctl = gomock.NewController(GinkgoT())
+		mgr, logger = modelmocks.NewManager(facts, data, false, mockctl)
+		provider = NewMockJsonEditProvider(mockctl)
+
+		provider.EXPECT().Name().Return("mock").AnyTimes()
+		logger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+		logger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+	})
+
+	Describe("New", func() {
+		It("Should validate properties", func(ctx context.Context) {
+			_, err := New(ctx, mgr, model.JsonEditResourceProperties{})
+			Expect(err).To(MatchError(model.ErrResourceNameRequired))
+
+			_, err = New(ctx, mgr, model.JsonEditResourceProperties{
+				CommonResourceProperties: model.CommonResourceProperties{Name: "/etc/app.json", Ensure: model.EnsurePresent},
+			})
+			Expect(err).To(MatchError(ContainSubstring("path cannot be empty")))
+		})
+	})
+
+	Context("with a prepared provider", func() {
+		var factory *modelmocks.MockProviderFactory
+		var res *Type
+		var err error
+
+		BeforeEach(func(ctx context.Context) {
+			factory = modelmocks.NewMockProviderFactory(mockctl)
+			factory.EXPECT().Name().Return("test").AnyTimes()
+			factory.EXPECT().TypeName().Return(model.JsonEditTypeName).AnyTimes()
+			factory.EXPECT().New(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(log model.Logger, runner model.CommandRunner) (model.Provider, error) {
+				return provider, nil
+			})
+			factory.EXPECT().IsManageable(facts, gomock.Any()).Return(true, 1, nil).AnyTimes()
+
+			res, err = New(ctx, mgr, model.JsonEditResourceProperties{
+				CommonResourceProperties: model.CommonResourceProperties{
+					Name:     "/etc/app.json",
+					Ensure:   model.EnsurePresent,
+					Provider: "test",
+				},
+				Path:  "server.port",
+				Value: 8080,
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			registry.Clear()
+			registry.MustRegister(factory)
+		})
+
+		state := func(pathExists bool, value any) *model.JsonEditState {
+			ensure := model.EnsureAbsent
+			if pathExists {
+				ensure = model.EnsurePresent
+			}
+
+			return &model.JsonEditState{
+				CommonResourceState: model.CommonResourceState{Ensure: ensure},
+				Metadata:            &model.JsonEditMetadata{FileExists: true, PathExists: pathExists, Value: value},
+			}
+		}
+
+		Describe("Apply", func() {
+			It("Should fail if initial status check fails", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("status failed"))
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Errors).To(ContainElement(ContainSubstring("status failed")))
+			})
+
+			It("Should set the value when it differs", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(true, json.Number("80")), nil)
+				provider.EXPECT().Set(gomock.Any(), res.prop).Return(nil)
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(true, json.Number("8080")), nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Errors).To(BeEmpty())
+				Expect(event.Changed).To(BeTrue())
+			})
+
+			It("Should not change when the value matches", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(true, uint64(8080)), nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Changed).To(BeFalse())
+			})
+
+			It("Should fail when the value is not set after applying", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(false, nil), nil).Times(2)
+				provider.EXPECT().Set(gomock.Any(), res.prop).Return(nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Errors).To(ContainElement(ContainSubstring("server.port does not exist")))
+			})
+
+			It("Should remove the value when absent", func(ctx context.Context) {
+				res.prop.Ensure = model.EnsureAbsent
+
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(true, json.Number("80")), nil)
+				provider.EXPECT().Remove(gomock.Any(), res.prop).Return(nil)
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(false, nil), nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Errors).To(BeEmpty())
+				Expect(event.Changed).To(BeTrue())
+			})
+		})
+
+		Describe("Apply in noop mode", func() {
+			It("Should not set the value", func(ctx context.Context) {
+				noopMgr, _ := modelmocks.NewManager(facts, data, true, mockctl)
+				noopRes, err := New(ctx, noopMgr, *res.prop)
+				Expect(err).ToNot(HaveOccurred())
+
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(false, nil), nil)
+
+				event, err := noopRes.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Changed).To(BeTrue())
+				Expect(event.Noop).To(BeTrue())
+				Expect(event.NoopMessage).To(Equal("Would have set server.port"))
+			})
+		})
+	})
+})
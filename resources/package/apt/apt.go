@@ -6,6 +6,7 @@ package apt
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"fmt"
 	"strings"
@@ -69,13 +70,13 @@ func (p *Provider) Install(ctx context.Context, pkg string, version string) erro
 	}
 	args = append(args, pkgVersion)
 
-	_, _, exitcode, err := p.execute(ctx, "apt-get", args...)
+	_, stderr, exitcode, err := p.execute(ctx, "apt-get", args...)
 	if err != nil {
 		return err
 	}
 
 	if exitcode != 0 {
-		return fmt.Errorf("failed to install package %q, apt-get exited %d", pkg, exitcode)
+		return classifyFailure(stderr, fmt.Errorf("failed to install package %q, apt-get exited %d", pkg, exitcode))
 	}
 
 	return nil
@@ -96,7 +97,35 @@ func (p *Provider) Uninstall(ctx context.Context, pkg string) error {
 	}
 
 	if exitcode != 0 {
-		return fmt.Errorf("failed to uninstall %s: %s", pkg, stderr)
+		return classifyFailure(stderr, fmt.Errorf("failed to uninstall %s: %s", pkg, stderr))
+	}
+
+	return nil
+}
+
+// Autoremove removes packages that were installed as dependencies and are no longer needed
+func (p *Provider) Autoremove(ctx context.Context) error {
+	_, stderr, exitcode, err := p.execute(ctx, "apt-get", "-q", "-y", "autoremove")
+	if err != nil {
+		return fmt.Errorf("failed to remove unused dependencies: %w", err)
+	}
+
+	if exitcode != 0 {
+		return classifyFailure(stderr, fmt.Errorf("failed to remove unused dependencies, apt-get exited %d", exitcode))
+	}
+
+	return nil
+}
+
+// CleanCache removes downloaded package files from the local repository
+func (p *Provider) CleanCache(ctx context.Context) error {
+	_, stderr, exitcode, err := p.execute(ctx, "apt-get", "-q", "clean")
+	if err != nil {
+		return fmt.Errorf("failed to clean the package cache: %w", err)
+	}
+
+	if exitcode != 0 {
+		return classifyFailure(stderr, fmt.Errorf("failed to clean the package cache, apt-get exited %d", exitcode))
 	}
 
 	return nil
@@ -175,3 +204,22 @@ func parseLatestAvailable(output string, pkg string) (string, error) {
 
 	return "", fmt.Errorf("could not find Candidate: line in apt-cache policy output for %s", pkg)
 }
+
+// transientFailures are apt-get error messages for failures that might succeed when retried
+var transientFailures = []string{
+	"Could not get lock",
+	"Unable to acquire the dpkg frontend lock",
+	"Temporary failure resolving",
+	"Failed to fetch",
+}
+
+// classifyFailure marks err as transient when stderr shows lock contention or network errors, otherwise permanent
+func classifyFailure(stderr []byte, err error) error {
+	for _, msg := range transientFailures {
+		if bytes.Contains(stderr, []byte(msg)) {
+			return model.NewTransientError(err)
+		}
+	}
+
+	return model.NewPermanentError(err)
+}
@@ -171,6 +171,15 @@ var _ = Describe("APT Provider", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("failed to install package"))
 			Expect(err.Error()).To(ContainSubstring("apt-get exited 100"))
+			Expect(model.IsPermanentError(err)).To(BeTrue())
+		})
+
+		It("Should report lock contention as transient", func() {
+			runner.EXPECT().ExecuteWithOptions(gomock.Any(), gomock.Any()).Times(1).Return(nil, []byte("E: Could not get lock /var/lib/dpkg/lock-frontend. It is held by process 1234 (apt-get)"), 100, nil)
+
+			err := provider.Install(context.Background(), "zsh", model.EnsurePresent)
+			Expect(err).To(MatchError(`failed to install package "zsh", apt-get exited 100`))
+			Expect(model.IsTransientError(err)).To(BeTrue())
 		})
 	})
 
@@ -205,6 +214,37 @@ var _ = Describe("APT Provider", func() {
 		})
 	})
 
+	Describe("Maintenance", func() {
+		It("Should remove unused dependencies", func() {
+			runner.EXPECT().ExecuteWithOptions(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, opts model.ExtendedExecOptions) ([]byte, []byte, int, error) {
+				Expect(opts.Command).To(Equal("apt-get"))
+				Expect(opts.Args).To(Equal([]string{"-q", "-y", "autoremove"}))
+				Expect(opts.Environment).To(ContainElement("DEBIAN_FRONTEND=noninteractive"))
+				return nil, nil, 0, nil
+			})
+
+			Expect(provider.Autoremove(context.Background())).To(Succeed())
+		})
+
+		It("Should treat lock contention during autoremove as transient", func() {
+			runner.EXPECT().ExecuteWithOptions(gomock.Any(), gomock.Any()).Return(nil, []byte("E: Could not get lock /var/lib/dpkg/lock-frontend"), 100, nil)
+
+			err := provider.Autoremove(context.Background())
+			Expect(err).To(MatchError(ContainSubstring("failed to remove unused dependencies, apt-get exited 100")))
+			Expect(model.IsTransientError(err)).To(BeTrue())
+		})
+
+		It("Should clean the cache", func() {
+			runner.EXPECT().ExecuteWithOptions(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, opts model.ExtendedExecOptions) ([]byte, []byte, int, error) {
+				Expect(opts.Command).To(Equal("apt-get"))
+				Expect(opts.Args).To(Equal([]string{"-q", "clean"}))
+				return nil, nil, 0, nil
+			})
+
+			Expect(provider.CleanCache(context.Background())).To(Succeed())
+		})
+	})
+
 	Describe("Upgrade", func() {
 		It("Should delegate to Install", func() {
 			runner.EXPECT().ExecuteWithOptions(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(func(ctx context.Context, opts model.ExtendedExecOptions) ([]byte, []byte, int, error) {
@@ -0,0 +1,170 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package brew
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	iu "github.com/choria-io/ccm/internal/util"
+	"github.com/choria-io/ccm/model"
+)
+
+const ProviderName = "brew"
+
+// Provider manages packages using the Homebrew package manager
+type Provider struct {
+	log    model.Logger
+	runner model.CommandRunner
+}
+
+// NewBrewProvider creates a new Homebrew package provider
+func NewBrewProvider(log model.Logger, runner model.CommandRunner) (*Provider, error) {
+	return &Provider{log: log, runner: runner}, nil
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+// We ensure that any user of this provider in the same process will not call brew multiple times, brew is told not
+// to update itself or clean up as side effects of managing a package
+func (p *Provider) execute(ctx context.Context, args ...string) (stdout []byte, stderr []byte, exitCode int, err error) {
+	model.PackageGlobalLock.Lock()
+	defer model.PackageGlobalLock.Unlock()
+
+	return p.runner.ExecuteWithOptions(ctx, model.ExtendedExecOptions{
+		Command: "brew",
+		Args:    args,
+		Environment: []string{
+			"HOMEBREW_NO_AUTO_UPDATE=1",
+			"HOMEBREW_NO_INSTALL_CLEANUP=1",
+			"HOMEBREW_NO_ENV_HINTS=1",
+		},
+	})
+}
+
+// Install installs a formula using brew, brew always installs the current version of the formula so requests for
+// other versions fail the desired state check after installing
+func (p *Provider) Install(ctx context.Context, pkg string, version string) error {
+	_, stderr, exitcode, err := p.execute(ctx, "install", pkg)
+	if err != nil {
+		return err
+	}
+
+	if exitcode != 0 {
+		return classifyFailure(stderr, fmt.Errorf("failed to install package %q, brew exited %d", pkg, exitcode))
+	}
+
+	return nil
+}
+
+// Upgrade upgrades a formula to the current version using brew
+func (p *Provider) Upgrade(ctx context.Context, pkg string, version string) error {
+	_, stderr, exitcode, err := p.execute(ctx, "upgrade", pkg)
+	if err != nil {
+		return err
+	}
+
+	if exitcode != 0 {
+		return classifyFailure(stderr, fmt.Errorf("failed to upgrade %s, brew exited %d", pkg, exitcode))
+	}
+
+	return nil
+}
+
+// Downgrade is not supported, brew can only install the current version of a formula
+func (p *Provider) Downgrade(_ context.Context, pkg string, version string) error {
+	return model.NewPermanentError(fmt.Errorf("cannot downgrade %s to %s, brew does not support installing older versions", pkg, version))
+}
+
+// Uninstall removes a formula using brew
+func (p *Provider) Uninstall(ctx context.Context, pkg string) error {
+	_, stderr, exitcode, err := p.execute(ctx, "uninstall", pkg)
+	if err != nil {
+		return err
+	}
+
+	if exitcode != 0 {
+		return classifyFailure(stderr, fmt.Errorf("failed to uninstall %s, brew exited %d", pkg, exitcode))
+	}
+
+	return nil
+}
+
+// Status returns the current installation status of a formula, when several versions are installed the newest
+// is reported
+func (p *Provider) Status(ctx context.Context, pkg string) (*model.PackageState, error) {
+	stdout, _, exitcode, err := p.execute(ctx, "list", "--versions", pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	// brew exits 1 without output for formulae that are not installed
+	parts := strings.Fields(string(stdout))
+	if exitcode != 0 || len(parts) == 0 {
+		return &model.PackageState{
+			CommonResourceState: model.NewCommonResourceState(model.ResourceStatusPackageProtocol, model.PackageTypeName, pkg, model.EnsureAbsent),
+			Metadata: &model.PackageMetadata{
+				Name:     pkg,
+				Provider: ProviderName,
+				Version:  "absent",
+				Extended: map[string]any{},
+			},
+		}, nil
+	}
+
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("failed to parse brew list output for %s", pkg)
+	}
+
+	versions := parts[1:]
+	newest := versions[0]
+	for _, v := range versions[1:] {
+		if iu.VersionCmp(v, newest, false) > 0 {
+			newest = v
+		}
+	}
+
+	state := &model.PackageState{
+		CommonResourceState: model.NewCommonResourceState(model.ResourceStatusPackageProtocol, model.PackageTypeName, pkg, newest),
+		Metadata: &model.PackageMetadata{
+			Name:     parts[0],
+			Version:  newest,
+			Provider: ProviderName,
+			Extended: map[string]any{
+				"versions": versions,
+			},
+		},
+	}
+
+	return state, nil
+}
+
+func (p *Provider) VersionCmp(versionA, versionB string, ignoreTrailingZeroes bool) (int, error) {
+	return iu.VersionCmp(versionA, versionB, ignoreTrailingZeroes), nil
+}
+
+// transientFailures are brew error messages for failures that might succeed when retried
+var transientFailures = []string{
+	"has already locked",
+	"Failed to download",
+	"Could not resolve host",
+	"Connection timed out",
+}
+
+// classifyFailure marks err as transient when stderr shows lock contention or network errors, otherwise permanent
+func classifyFailure(stderr []byte, err error) error {
+	for _, msg := range transientFailures {
+		if bytes.Contains(stderr, []byte(msg)) {
+			return model.NewTransientError(err)
+		}
+	}
+
+	return model.NewPermanentError(err)
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package brew
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/model/modelmocks"
+)
+
+func TestBrewProvider(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Resources/Package/Brew")
+}
+
+var _ = Describe("Brew Provider", func() {
+	var (
+		mockctl  *gomock.Controller
+		logger   *modelmocks.MockLogger
+		runner   *modelmocks.MockCommandRunner
+		provider *Provider
+		err      error
+	)
+
+	BeforeEach(func() {
+		mockctl = gomock.NewController(GinkgoT())
+		logger = modelmocks.NewMockLogger(mockctl)
+		runner = modelmocks.NewMockCommandRunner(mockctl)
+
+		logger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+		logger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+		provider, err = NewBrewProvider(logger, runner)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	// expectBrew expects a single brew invocation with args, returning the content of the fixture files
+	expectBrew := func(args []string, stdoutFile string, stderrFile string, exitCode int) {
+		runner.EXPECT().ExecuteWithOptions(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(func(ctx context.Context, opts model.ExtendedExecOptions) ([]byte, []byte, int, error) {
+			Expect(opts.Command).To(Equal("brew"))
+			Expect(opts.Args).To(Equal(args))
+			Expect(opts.Environment).To(ContainElement("HOMEBREW_NO_AUTO_UPDATE=1"))
+
+			var stdout, stderr []byte
+			if stdoutFile != "" {
+				stdout, err = os.ReadFile(stdoutFile)
+				Expect(err).ToNot(HaveOccurred())
+			}
+			if stderrFile != "" {
+				stderr, err = os.ReadFile(stderrFile)
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			return stdout, stderr, exitCode, nil
+		})
+	}
+
+	Describe("Status", func() {
+		It("Should report the newest installed version", func(ctx context.Context) {
+			expectBrew([]string{"list", "--versions", "wget"}, "testdata/brew_list_versions.txt", "", 0)
+
+			res, err := provider.Status(ctx, "wget")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Ensure).To(Equal("1.24.5"))
+			Expect(res.Metadata.Name).To(Equal("wget"))
+			Expect(res.Metadata.Version).To(Equal("1.24.5"))
+			Expect(res.Metadata.Provider).To(Equal("brew"))
+			Expect(res.Metadata.Extended).To(HaveKeyWithValue("versions", []string{"1.21.4", "1.24.5"}))
+		})
+
+		It("Should handle absent formulae", func(ctx context.Context) {
+			expectBrew([]string{"list", "--versions", "wget"}, "", "testdata/brew_list_versions_absent_stderr.txt", 1)
+
+			res, err := provider.Status(ctx, "wget")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Ensure).To(Equal(model.EnsureAbsent))
+			Expect(res.Metadata.Version).To(Equal("absent"))
+			Expect(res.Metadata.Provider).To(Equal("brew"))
+		})
+	})
+
+	Describe("Install", func() {
+		It("Should install the formula", func(ctx context.Context) {
+			expectBrew([]string{"install", "wget"}, "testdata/brew_install.txt", "", 0)
+			Expect(provider.Install(ctx, "wget", model.EnsurePresent)).To(Succeed())
+		})
+
+		It("Should report permanent failures", func(ctx context.Context) {
+			expectBrew([]string{"install", "nonexistent-pkg"}, "", "testdata/brew_install_fail_stderr.txt", 1)
+
+			err := provider.Install(ctx, "nonexistent-pkg", model.EnsurePresent)
+			Expect(err).To(MatchError(ContainSubstring(`failed to install package "nonexistent-pkg", brew exited 1`)))
+			Expect(model.IsTransientError(err)).To(BeFalse())
+		})
+
+		It("Should report lock contention as transient", func(ctx context.Context) {
+			expectBrew([]string{"install", "wget"}, "", "testdata/brew_install_locked_stderr.txt", 1)
+
+			err := provider.Install(ctx, "wget", model.EnsurePresent)
+			Expect(model.IsTransientError(err)).To(BeTrue())
+		})
+	})
+
+	Describe("Upgrade", func() {
+		It("Should upgrade the formula", func(ctx context.Context) {
+			expectBrew([]string{"upgrade", "wget"}, "testdata/brew_install.txt", "", 0)
+			Expect(provider.Upgrade(ctx, "wget", model.PackageEnsureLatest)).To(Succeed())
+		})
+	})
+
+	Describe("Downgrade", func() {
+		It("Should fail without calling brew", func(ctx context.Context) {
+			err := provider.Downgrade(ctx, "wget", "1.21.4")
+			Expect(err).To(MatchError("cannot downgrade wget to 1.21.4, brew does not support installing older versions"))
+			Expect(model.IsTransientError(err)).To(BeFalse())
+		})
+	})
+
+	Describe("Uninstall", func() {
+		It("Should uninstall the formula", func(ctx context.Context) {
+			expectBrew([]string{"uninstall", "wget"}, "", "", 0)
+			Expect(provider.Uninstall(ctx, "wget")).To(Succeed())
+		})
+	})
+
+	Describe("IsManageable", func() {
+		var origGoos string
+
+		BeforeEach(func() {
+			origGoos = goos
+
+			dir := GinkgoT().TempDir()
+			Expect(os.WriteFile(filepath.Join(dir, "brew"), []byte("#!/bin/sh\n"), 0755)).To(Succeed())
+			GinkgoT().Setenv("PATH", dir)
+
+			DeferCleanup(func() { goos = origGoos })
+		})
+
+		It("Should only manage packages on macOS", func() {
+			f := &factory{}
+
+			goos = "linux"
+			ok, _, err := f.IsManageable(nil, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ok).To(BeFalse())
+
+			goos = "darwin"
+			ok, prio, err := f.IsManageable(nil, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(prio).To(Equal(nativePriority))
+		})
+	})
+})
@@ -0,0 +1,44 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package brew
+
+import (
+	"runtime"
+
+	"github.com/choria-io/ccm/internal/registry"
+	iu "github.com/choria-io/ccm/internal/util"
+	"github.com/choria-io/ccm/model"
+)
+
+// Register registers this provider with the registry
+func Register() {
+	registry.MustRegister(&factory{})
+}
+
+const nativePriority = 1
+
+// goos is the operating system brew is used on, a variable so tests can simulate macOS
+var goos = runtime.GOOS
+
+type factory struct{}
+
+func (p *factory) TypeName() string { return model.PackageTypeName }
+func (p *factory) Name() string     { return ProviderName }
+func (p *factory) New(log model.Logger, runner model.CommandRunner) (model.Provider, error) {
+	return NewBrewProvider(log, runner)
+}
+func (p *factory) IsManageable(_ map[string]any, _ model.ResourceProperties) (bool, int, error) {
+	// Homebrew is also available on Linux but is not the system package manager there
+	if goos != "darwin" {
+		return false, 0, nil
+	}
+
+	_, found, err := iu.ExecutableInPath("brew")
+	if err != nil || !found {
+		return false, 0, err
+	}
+
+	return true, nativePriority, nil
+}
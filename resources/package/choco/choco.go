@@ -0,0 +1,160 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package choco
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	iu "github.com/choria-io/ccm/internal/util"
+	"github.com/choria-io/ccm/model"
+)
+
+const ProviderName = "choco"
+
+// successExitCodes are the choco exit codes for successful operations, 1641 and 3010 indicate a reboot is required
+var successExitCodes = []int{0, 1641, 3010}
+
+// Provider manages packages using the Chocolatey package manager
+type Provider struct {
+	log    model.Logger
+	runner model.CommandRunner
+}
+
+// NewChocoProvider creates a new Chocolatey package provider
+func NewChocoProvider(log model.Logger, runner model.CommandRunner) (*Provider, error) {
+	return &Provider{log: log, runner: runner}, nil
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+// We ensure that any user of this provider in the same process will not call choco multiple times
+func (p *Provider) execute(ctx context.Context, args ...string) (stdout []byte, stderr []byte, exitCode int, err error) {
+	model.PackageGlobalLock.Lock()
+	defer model.PackageGlobalLock.Unlock()
+
+	return p.runner.ExecuteWithOptions(ctx, model.ExtendedExecOptions{
+		Command: "choco",
+		Args:    args,
+	})
+}
+
+// change runs a choco command that changes a package, prompts are confirmed and a specific version is passed
+// using --version
+func (p *Provider) change(ctx context.Context, action string, pkg string, version string, extra ...string) error {
+	args := []string{action, pkg, "-y", "--no-progress"}
+	if version != "" && version != model.EnsurePresent && version != model.PackageEnsureLatest {
+		args = append(args, "--version", version)
+	}
+	args = append(args, extra...)
+
+	stdout, stderr, exitcode, err := p.execute(ctx, args...)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(successExitCodes, exitcode) {
+		return classifyFailure(stdout, stderr, fmt.Errorf("failed to %s package %q, choco exited %d", action, pkg, exitcode))
+	}
+
+	if exitcode != 0 {
+		p.log.Warn("Package change requires a reboot", "package", pkg, "exitcode", exitcode)
+	}
+
+	return nil
+}
+
+// Install installs a package using choco, a specific version is installed when requested
+func (p *Provider) Install(ctx context.Context, pkg string, version string) error {
+	return p.change(ctx, "install", pkg, version)
+}
+
+// Upgrade upgrades a package to the latest or a specific version using choco
+func (p *Provider) Upgrade(ctx context.Context, pkg string, version string) error {
+	return p.change(ctx, "upgrade", pkg, version)
+}
+
+// Downgrade downgrades a package to a specific version using choco
+func (p *Provider) Downgrade(ctx context.Context, pkg string, version string) error {
+	return p.change(ctx, "upgrade", pkg, version, "--allow-downgrade")
+}
+
+// Uninstall removes a package using choco
+func (p *Provider) Uninstall(ctx context.Context, pkg string) error {
+	return p.change(ctx, "uninstall", pkg, "")
+}
+
+// Status returns the current installation status of a package
+func (p *Provider) Status(ctx context.Context, pkg string) (*model.PackageState, error) {
+	stdout, stderr, exitcode, err := p.execute(ctx, "list", "--local-only", "--exact", "--limit-output", pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	// choco exits 2 when enhanced exit codes are enabled and no package matched
+	if exitcode != 0 && exitcode != 2 {
+		return nil, classifyFailure(stdout, stderr, fmt.Errorf("failed to list package %q, choco exited %d", pkg, exitcode))
+	}
+
+	// limited output lists installed packages as name|version, package ids are case insensitive
+	scanner := bufio.NewScanner(bytes.NewReader(stdout))
+	for scanner.Scan() {
+		name, version, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "|")
+		if !ok || !strings.EqualFold(name, pkg) {
+			continue
+		}
+
+		return &model.PackageState{
+			CommonResourceState: model.NewCommonResourceState(model.ResourceStatusPackageProtocol, model.PackageTypeName, pkg, version),
+			Metadata: &model.PackageMetadata{
+				Name:     name,
+				Version:  version,
+				Provider: ProviderName,
+				Extended: map[string]any{},
+			},
+		}, nil
+	}
+
+	return &model.PackageState{
+		CommonResourceState: model.NewCommonResourceState(model.ResourceStatusPackageProtocol, model.PackageTypeName, pkg, model.EnsureAbsent),
+		Metadata: &model.PackageMetadata{
+			Name:     pkg,
+			Provider: ProviderName,
+			Version:  "absent",
+			Extended: map[string]any{},
+		},
+	}, nil
+}
+
+func (p *Provider) VersionCmp(versionA, versionB string, ignoreTrailingZeroes bool) (int, error) {
+	return iu.VersionCmp(versionA, versionB, ignoreTrailingZeroes), nil
+}
+
+// transientFailures are choco error messages for failures that might succeed when retried
+var transientFailures = []string{
+	"Unable to connect to the remote server",
+	"The operation has timed out",
+	"The remote name could not be resolved",
+	"being used by another process",
+}
+
+// classifyFailure marks err as transient when the output shows network errors or locked files, otherwise permanent,
+// choco reports most errors on stdout
+func classifyFailure(stdout []byte, stderr []byte, err error) error {
+	for _, msg := range transientFailures {
+		if bytes.Contains(stdout, []byte(msg)) || bytes.Contains(stderr, []byte(msg)) {
+			return model.NewTransientError(err)
+		}
+	}
+
+	return model.NewPermanentError(err)
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package choco
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/model/modelmocks"
+)
+
+func TestChocoProvider(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Resources/Package/Choco")
+}
+
+var _ = Describe("Choco Provider", func() {
+	var (
+		mockctl  *gomock.Controller
+		logger   *modelmocks.MockLogger
+		runner   *modelmocks.MockCommandRunner
+		provider *Provider
+		err      error
+	)
+
+	BeforeEach(func() {
+		mockctl = gomock.NewController(GinkgoT())
+		logger = modelmocks.NewMockLogger(mockctl)
+		runner = modelmocks.NewMockCommandRunner(mockctl)
+
+		logger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+		logger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+		provider, err = NewChocoProvider(logger, runner)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	// expectChoco expects a single choco invocation with args, returning the content of the fixture file as stdout
+	expectChoco := func(args []string, stdoutFile string, exitCode int) {
+		runner.EXPECT().ExecuteWithOptions(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(func(ctx context.Context, opts model.ExtendedExecOptions) ([]byte, []byte, int, error) {
+			Expect(opts.Command).To(Equal("choco"))
+			Expect(opts.Args).To(Equal(args))
+
+			var stdout []byte
+			if stdoutFile != "" {
+				stdout, err = os.ReadFile(stdoutFile)
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			return stdout, nil, exitCode, nil
+		})
+	}
+
+	Describe("Status", func() {
+		It("Should report the installed version", func(ctx context.Context) {
+			expectChoco([]string{"list", "--local-only", "--exact", "--limit-output", "Git"}, "testdata/choco_list.txt", 0)
+
+			res, err := provider.Status(ctx, "Git")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Ensure).To(Equal("2.47.1"))
+			Expect(res.Metadata.Name).To(Equal("git"))
+			Expect(res.Metadata.Version).To(Equal("2.47.1"))
+			Expect(res.Metadata.Provider).To(Equal("choco"))
+		})
+
+		It("Should handle absent packages", func(ctx context.Context) {
+			expectChoco([]string{"list", "--local-only", "--exact", "--limit-output", "git"}, "", 2)
+
+			res, err := provider.Status(ctx, "git")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Ensure).To(Equal(model.EnsureAbsent))
+			Expect(res.Metadata.Version).To(Equal("absent"))
+			Expect(res.Metadata.Provider).To(Equal("choco"))
+		})
+
+		It("Should fail when choco fails", func(ctx context.Context) {
+			expectChoco([]string{"list", "--local-only", "--exact", "--limit-output", "git"}, "", 1)
+
+			_, err := provider.Status(ctx, "git")
+			Expect(err).To(MatchError(`failed to list package "git", choco exited 1`))
+		})
+	})
+
+	Describe("Install", func() {
+		It("Should install the package", func(ctx context.Context) {
+			expectChoco([]string{"install", "git", "-y", "--no-progress"}, "testdata/choco_install.txt", 0)
+			Expect(provider.Install(ctx, "git", model.EnsurePresent)).To(Succeed())
+		})
+
+		It("Should install specific versions", func(ctx context.Context) {
+			expectChoco([]string{"install", "git", "-y", "--no-progress", "--version", "2.47.1"}, "testdata/choco_install.txt", 0)
+			Expect(provider.Install(ctx, "git", "2.47.1")).To(Succeed())
+		})
+
+		It("Should accept changes requiring a reboot", func(ctx context.Context) {
+			logger.EXPECT().Warn("Package change requires a reboot", "package", "git", "exitcode", 3010)
+
+			expectChoco([]string{"install", "git", "-y", "--no-progress"}, "testdata/choco_install.txt", 3010)
+			Expect(provider.Install(ctx, "git", model.EnsurePresent)).To(Succeed())
+		})
+
+		It("Should report permanent failures", func(ctx context.Context) {
+			expectChoco([]string{"install", "nonexistent-pkg", "-y", "--no-progress"}, "testdata/choco_install_fail.txt", 1)
+
+			err := provider.Install(ctx, "nonexistent-pkg", model.EnsurePresent)
+			Expect(err).To(MatchError(`failed to install package "nonexistent-pkg", choco exited 1`))
+			Expect(model.IsTransientError(err)).To(BeFalse())
+		})
+
+		It("Should report network failures as transient", func(ctx context.Context) {
+			expectChoco([]string{"install", "git", "-y", "--no-progress"}, "testdata/choco_install_network_fail.txt", 1)
+
+			err := provider.Install(ctx, "git", model.EnsurePresent)
+			Expect(model.IsTransientError(err)).To(BeTrue())
+		})
+	})
+
+	Describe("Upgrade", func() {
+		It("Should upgrade to the latest version", func(ctx context.Context) {
+			expectChoco([]string{"upgrade", "git", "-y", "--no-progress"}, "", 0)
+			Expect(provider.Upgrade(ctx, "git", model.PackageEnsureLatest)).To(Succeed())
+		})
+
+		It("Should upgrade to specific versions", func(ctx context.Context) {
+			expectChoco([]string{"upgrade", "git", "-y", "--no-progress", "--version", "2.47.1"}, "", 0)
+			Expect(provider.Upgrade(ctx, "git", "2.47.1")).To(Succeed())
+		})
+	})
+
+	Describe("Downgrade", func() {
+		It("Should allow downgrades", func(ctx context.Context) {
+			expectChoco([]string{"upgrade", "git", "-y", "--no-progress", "--version", "2.40.0", "--allow-downgrade"}, "", 0)
+			Expect(provider.Downgrade(ctx, "git", "2.40.0")).To(Succeed())
+		})
+	})
+
+	Describe("Uninstall", func() {
+		It("Should uninstall the package", func(ctx context.Context) {
+			expectChoco([]string{"uninstall", "git", "-y", "--no-progress"}, "", 0)
+			Expect(provider.Uninstall(ctx, "git")).To(Succeed())
+		})
+	})
+
+	Describe("IsManageable", func() {
+		var origGoos string
+
+		BeforeEach(func() {
+			origGoos = goos
+
+			dir := GinkgoT().TempDir()
+			Expect(os.WriteFile(filepath.Join(dir, "choco"), []byte("#!/bin/sh\n"), 0755)).To(Succeed())
+			GinkgoT().Setenv("PATH", dir)
+
+			DeferCleanup(func() { goos = origGoos })
+		})
+
+		It("Should only manage packages on Windows", func() {
+			f := &factory{}
+
+			goos = "linux"
+			ok, _, err := f.IsManageable(nil, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ok).To(BeFalse())
+
+			goos = "windows"
+			ok, prio, err := f.IsManageable(nil, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(prio).To(Equal(nativePriority))
+		})
+	})
+})
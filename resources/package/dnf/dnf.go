@@ -5,6 +5,7 @@
 package dnf
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"regexp"
@@ -61,13 +62,13 @@ func (p *Provider) Install(ctx context.Context, pkg string, version string) erro
 		pkgVersion = fmt.Sprintf("%s-%s", pkg, version)
 	}
 
-	_, _, exitcode, err := p.execute(ctx, "dnf", "install", "-y", pkgVersion)
+	_, stderr, exitcode, err := p.execute(ctx, "dnf", "install", "-y", pkgVersion)
 	if err != nil {
 		return err
 	}
 
 	if exitcode != 0 {
-		return fmt.Errorf("failed to Install package %q, dnf exited %d", pkg, exitcode)
+		return classifyFailure(stderr, fmt.Errorf("failed to Install package %q, dnf exited %d", pkg, exitcode))
 	}
 
 	return nil
@@ -80,13 +81,13 @@ func (p *Provider) Upgrade(ctx context.Context, pkg string, version string) erro
 
 // Downgrade downgrades a package to a specific version using DNF
 func (p *Provider) Downgrade(ctx context.Context, pkg string, version string) error {
-	_, _, exitcode, err := p.execute(ctx, "dnf", "downgrade", "-y", fmt.Sprintf("%s-%s", pkg, version))
+	_, stderr, exitcode, err := p.execute(ctx, "dnf", "downgrade", "-y", fmt.Sprintf("%s-%s", pkg, version))
 	if err != nil {
 		return err
 	}
 
 	if exitcode != 0 {
-		return fmt.Errorf("failed to Downgrade %s, dnf exited %d", pkg, exitcode)
+		return classifyFailure(stderr, fmt.Errorf("failed to Downgrade %s, dnf exited %d", pkg, exitcode))
 	}
 
 	return nil
@@ -95,13 +96,41 @@ func (p *Provider) Downgrade(ctx context.Context, pkg string, version string) er
 
 // Uninstall removes a package using DNF
 func (p *Provider) Uninstall(ctx context.Context, pkg string) error {
-	_, _, exitcode, err := p.execute(ctx, "dnf", "remove", "-y", pkg)
+	_, stderr, exitcode, err := p.execute(ctx, "dnf", "remove", "-y", pkg)
 	if err != nil {
 		return err
 	}
 
 	if exitcode != 0 {
-		return fmt.Errorf("failed to Uninstall %s, dnf exited %d", pkg, exitcode)
+		return classifyFailure(stderr, fmt.Errorf("failed to Uninstall %s, dnf exited %d", pkg, exitcode))
+	}
+
+	return nil
+}
+
+// Autoremove removes packages that were installed as dependencies and are no longer needed using DNF
+func (p *Provider) Autoremove(ctx context.Context) error {
+	_, stderr, exitcode, err := p.execute(ctx, "dnf", "autoremove", "-y")
+	if err != nil {
+		return err
+	}
+
+	if exitcode != 0 {
+		return classifyFailure(stderr, fmt.Errorf("failed to remove unused dependencies, dnf exited %d", exitcode))
+	}
+
+	return nil
+}
+
+// CleanCache removes cached packages and metadata using DNF
+func (p *Provider) CleanCache(ctx context.Context) error {
+	_, stderr, exitcode, err := p.execute(ctx, "dnf", "clean", "all")
+	if err != nil {
+		return err
+	}
+
+	if exitcode != 0 {
+		return classifyFailure(stderr, fmt.Errorf("failed to clean the package cache, dnf exited %d", exitcode))
 	}
 
 	return nil
@@ -151,3 +180,22 @@ func (p *Provider) Status(ctx context.Context, pkg string) (*model.PackageState,
 func (p *Provider) VersionCmp(versionA, versionB string, ignoreTrailingZeroes bool) (int, error) {
 	return iu.VersionCmp(versionA, versionB, ignoreTrailingZeroes), nil
 }
+
+// transientFailures are dnf error messages for failures that might succeed when retried
+var transientFailures = []string{
+	"Cannot download",
+	"Curl error",
+	"Failed to download",
+	"Failed to obtain the transaction lock",
+}
+
+// classifyFailure marks err as transient when stderr shows lock contention or network errors, otherwise permanent
+func classifyFailure(stderr []byte, err error) error {
+	for _, msg := range transientFailures {
+		if bytes.Contains(stderr, []byte(msg)) {
+			return model.NewTransientError(err)
+		}
+	}
+
+	return model.NewPermanentError(err)
+}
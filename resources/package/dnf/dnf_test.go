@@ -108,4 +108,19 @@ var _ = Describe("DNF Provider", func() {
 		Entry("upgrade", "upgrade", "zsh", "6.2.3", "dnf", []string{"install", "-y", "zsh-6.2.3"}, "testdata/dnf/dnf_install_zsh.txt"),
 		Entry("downgrade", "downgrade", "zsh", "0.0.1", "dnf", []string{"downgrade", "-y", "zsh-0.0.1"}, "testdata/dnf/rpm_q.txt"),
 	)
+
+	Describe("Maintenance", func() {
+		It("Should remove unused dependencies", func() {
+			runner.EXPECT().Execute(gomock.Any(), "dnf", "autoremove", "-y").Return(nil, nil, 0, nil)
+			Expect(provider.Autoremove(context.Background())).To(Succeed())
+
+			runner.EXPECT().Execute(gomock.Any(), "dnf", "autoremove", "-y").Return(nil, []byte("error"), 1, nil)
+			Expect(provider.Autoremove(context.Background())).To(MatchError(ContainSubstring("failed to remove unused dependencies, dnf exited 1")))
+		})
+
+		It("Should clean the cache", func() {
+			runner.EXPECT().Execute(gomock.Any(), "dnf", "clean", "all").Return(nil, nil, 0, nil)
+			Expect(provider.CleanCache(context.Background())).To(Succeed())
+		})
+	})
 })
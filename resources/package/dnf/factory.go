@@ -15,6 +15,18 @@ func Register() {
 	registry.MustRegister(&factory{})
 }
 
+// Providers native to the node win over others that are merely installed, see iu.PlatformMatches for how facts are consulted
+const (
+	nativePriority  = 1
+	defaultPriority = 5
+)
+
+// RHEL and Fedora family systems where dnf is the native package manager
+var (
+	nativeFamilies  = []string{"rhel", "fedora"}
+	nativePlatforms = []string{"rhel", "redhat", "centos", "fedora", "rocky", "almalinux", "oracle", "amazon"}
+)
+
 type factory struct{}
 
 func (p *factory) TypeName() string { return model.PackageTypeName }
@@ -22,7 +34,7 @@ func (p *factory) Name() string     { return ProviderName }
 func (p *factory) New(log model.Logger, runner model.CommandRunner) (model.Provider, error) {
 	return NewDnfProvider(log, runner)
 }
-func (p *factory) IsManageable(_ map[string]any, _ model.ResourceProperties) (bool, int, error) {
+func (p *factory) IsManageable(facts map[string]any, _ model.ResourceProperties) (bool, int, error) {
 	for _, path := range []string{"dnf", "rpm"} {
 		_, found, err := iu.ExecutableInPath(path)
 		if err != nil {
@@ -33,5 +45,9 @@ func (p *factory) IsManageable(_ map[string]any, _ model.ResourceProperties) (bo
 		}
 	}
 
-	return true, 1, nil
+	if iu.PlatformMatches(facts, nativeFamilies, nativePlatforms) {
+		return true, nativePriority, nil
+	}
+
+	return true, defaultPriority, nil
 }
@@ -9,12 +9,16 @@ import (
 
 	"github.com/choria-io/ccm/model"
 	"github.com/choria-io/ccm/resources/package/apt"
+	"github.com/choria-io/ccm/resources/package/brew"
+	"github.com/choria-io/ccm/resources/package/choco"
 	"github.com/choria-io/ccm/resources/package/dnf"
 )
 
 func init() {
 	dnf.Register()
 	apt.Register()
+	brew.Register()
+	choco.Register()
 }
 
 type PackageProvider interface {
@@ -27,3 +31,11 @@ type PackageProvider interface {
 	Status(ctx context.Context, pkg string) (*model.PackageState, error)
 	VersionCmp(versionA, versionB string, ignoreTrailingZeroes bool) (int, error)
 }
+
+// MaintenanceProvider is implemented by package providers that can clean up after package changes
+type MaintenanceProvider interface {
+	// Autoremove removes packages that were installed as dependencies and are no longer needed
+	Autoremove(ctx context.Context) error
+	// CleanCache removes downloaded packages and metadata from the package manager cache
+	CleanCache(ctx context.Context) error
+}
@@ -142,3 +142,55 @@ func (mr *MockPackageProviderMockRecorder) VersionCmp(versionA, versionB, ignore
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VersionCmp", reflect.TypeOf((*MockPackageProvider)(nil).VersionCmp), versionA, versionB, ignoreTrailingZeroes)
 }
+
+// MockMaintenanceProvider is a mock of MaintenanceProvider interface.
+type MockMaintenanceProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockMaintenanceProviderMockRecorder
+	isgomock struct{}
+}
+
+// MockMaintenanceProviderMockRecorder is the mock recorder for MockMaintenanceProvider.
+type MockMaintenanceProviderMockRecorder struct {
+	mock *MockMaintenanceProvider
+}
+
+// NewMockMaintenanceProvider creates a new mock instance.
+func NewMockMaintenanceProvider(ctrl *gomock.Controller) *MockMaintenanceProvider {
+	mock := &MockMaintenanceProvider{ctrl: ctrl}
+	mock.recorder = &MockMaintenanceProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMaintenanceProvider) EXPECT() *MockMaintenanceProviderMockRecorder {
+	return m.recorder
+}
+
+// Autoremove mocks base method.
+func (m *MockMaintenanceProvider) Autoremove(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Autoremove", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Autoremove indicates an expected call of Autoremove.
+func (mr *MockMaintenanceProviderMockRecorder) Autoremove(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Autoremove", reflect.TypeOf((*MockMaintenanceProvider)(nil).Autoremove), ctx)
+}
+
+// CleanCache mocks base method.
+func (m *MockMaintenanceProvider) CleanCache(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CleanCache", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CleanCache indicates an expected call of CleanCache.
+func (mr *MockMaintenanceProviderMockRecorder) CleanCache(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CleanCache", reflect.TypeOf((*MockMaintenanceProvider)(nil).CleanCache), ctx)
+}
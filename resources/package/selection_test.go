@@ -0,0 +1,134 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package packageresource
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/choria-io/ccm/internal/registry"
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/model/modelmocks"
+	"github.com/choria-io/ccm/resources/package/apt"
+	"github.com/choria-io/ccm/resources/package/dnf"
+)
+
+var _ = Describe("Provider selection", func() {
+	var (
+		mockctl *gomock.Controller
+		logger  *modelmocks.MockLogger
+		runner  *modelmocks.MockCommandRunner
+		props   *model.PackageResourceProperties
+	)
+
+	BeforeEach(func() {
+		mockctl = gomock.NewController(GinkgoT())
+		logger = modelmocks.NewMockLogger(mockctl)
+		logger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+		runner = modelmocks.NewMockCommandRunner(mockctl)
+
+		props = &model.PackageResourceProperties{
+			CommonResourceProperties: model.CommonResourceProperties{
+				Type:   model.PackageTypeName,
+				Name:   "zsh",
+				Ensure: model.EnsurePresent,
+			},
+		}
+
+		// both apt and dnf are installed, as found on build hosts and some containers
+		bin := GinkgoT().TempDir()
+		for _, tool := range []string{"apt-get", "apt-cache", "apt-mark", "dpkg-query", "dnf", "rpm"} {
+			Expect(os.WriteFile(filepath.Join(bin, tool), []byte("#!/bin/sh\n"), 0755)).To(Succeed())
+		}
+		GinkgoT().Setenv("PATH", bin)
+
+		registry.Clear()
+		dnf.Register()
+		apt.Register()
+	})
+
+	hostFacts := func(info map[string]any) map[string]any {
+		return map[string]any{"host": map[string]any{"info": info}}
+	}
+
+	selected := func(facts map[string]any) string {
+		p, err := registry.FindSuitableProvider(model.PackageTypeName, "", facts, props, logger, runner, nil)
+		Expect(err).ToNot(HaveOccurred())
+		return p.Name()
+	}
+
+	DescribeTable("Should prefer the native package manager",
+		func(info map[string]any, expected string) {
+			Expect(selected(hostFacts(info))).To(Equal(expected))
+		},
+		Entry("debian", map[string]any{"os": "linux", "platform": "debian", "platformFamily": "debian"}, apt.ProviderName),
+		Entry("ubuntu", map[string]any{"os": "linux", "platform": "ubuntu", "platformFamily": "debian"}, apt.ProviderName),
+		Entry("rocky", map[string]any{"os": "linux", "platform": "rocky", "platformFamily": "rhel"}, dnf.ProviderName),
+		Entry("fedora", map[string]any{"os": "linux", "platform": "fedora", "platformFamily": "fedora"}, dnf.ProviderName),
+		Entry("platform only debian", map[string]any{"os": "linux", "platform": "ubuntu"}, apt.ProviderName),
+		Entry("platform only rhel", map[string]any{"os": "linux", "platform": "almalinux"}, dnf.ProviderName),
+	)
+
+	It("Should not depend on registration order", func() {
+		registry.Clear()
+		apt.Register()
+		dnf.Register()
+
+		Expect(selected(hostFacts(map[string]any{"platform": "rocky", "platformFamily": "rhel"}))).To(Equal(dnf.ProviderName))
+		Expect(selected(hostFacts(map[string]any{"platform": "debian", "platformFamily": "debian"}))).To(Equal(apt.ProviderName))
+	})
+
+	It("Should remain manageable without facts", func() {
+		for _, facts := range []map[string]any{nil, {}, hostFacts(map[string]any{})} {
+			p, err := registry.FindSuitableProvider(model.PackageTypeName, "", facts, props, logger, runner, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(p.Name()).To(BeElementOf(apt.ProviderName, dnf.ProviderName))
+		}
+	})
+
+	Describe("Preferred manager", func() {
+		var mgr *modelmocks.MockManager
+
+		BeforeEach(func() {
+			mgr, logger = modelmocks.NewManager(map[string]any{}, map[string]any{}, false, mockctl)
+			mgr.EXPECT().NewRunner().Return(runner, nil).AnyTimes()
+			logger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+		})
+
+		selectedFor := func(ctx context.Context, manager string) string {
+			props.Manager = manager
+
+			pkg, err := New(ctx, mgr, *props)
+			Expect(err).ToNot(HaveOccurred())
+
+			name, err := pkg.SelectProvider()
+			Expect(err).ToNot(HaveOccurred())
+
+			return name
+		}
+
+		It("Should select the preferred manager when several providers can manage the package", func(ctx context.Context) {
+			Expect(selectedFor(ctx, dnf.ProviderName)).To(Equal(dnf.ProviderName))
+			Expect(selectedFor(ctx, apt.ProviderName)).To(Equal(apt.ProviderName))
+		})
+
+		It("Should prefer the manager over the native package manager", func(ctx context.Context) {
+			mgr, logger = modelmocks.NewManager(hostFacts(map[string]any{"platform": "debian", "platformFamily": "debian"}), map[string]any{}, false, mockctl)
+			mgr.EXPECT().NewRunner().Return(runner, nil).AnyTimes()
+			logger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+
+			Expect(selectedFor(ctx, dnf.ProviderName)).To(Equal(dnf.ProviderName))
+		})
+
+		It("Should fall back to priority selection when the manager cannot be used", func(ctx context.Context) {
+			Expect(selectedFor(ctx, "pip")).To(BeElementOf(apt.ProviderName, dnf.ProviderName))
+		})
+	})
+})
@@ -37,6 +37,8 @@ const (
 )
 
 var _ model.Resource = (*Type)(nil)
+var _ base.ProviderFallback = (*Type)(nil)
+var _ base.StatusReporter = (*Type)(nil)
 
 // New creates a new package resource with the given properties
 func New(ctx context.Context, mgr model.Manager, properties model.PackageResourceProperties) (*Type, error) {
@@ -89,12 +91,20 @@ func (t *Type) ApplyResource(ctx context.Context) (model.ResourceState, error) {
 		initialStatus *model.PackageState
 		finalStatus   *model.PackageState
 		refreshState  bool
+		removed       bool
 		p             = t.provider.(PackageProvider)
 		properties    = t.prop
 		noop          = t.mgr.NoopMode()
 		noopMessage   string
 	)
 
+	if properties.Autoremove || properties.CleanCache {
+		_, ok := p.(MaintenanceProvider)
+		if !ok {
+			return nil, fmt.Errorf("provider %s does not support autoremove or clean_cache", p.Name())
+		}
+	}
+
 	initialStatus, err := p.Status(ctx, t.prop.Name)
 	if err != nil {
 		return nil, err
@@ -144,9 +154,13 @@ func (t *Type) ApplyResource(ctx context.Context) (model.ResourceState, error) {
 			if err != nil {
 				return nil, err
 			}
+			removed = true
 		} else {
 			t.log.Info("Skipping uninstall as noop")
 			noopMessage = "Would have uninstalled"
+			if properties.Autoremove {
+				noopMessage = "Would have uninstalled and removed unused dependencies"
+			}
 		}
 
 		refreshState = true
@@ -232,11 +246,50 @@ func (t *Type) ApplyResource(ctx context.Context) (model.ResourceState, error) {
 	if noop && refreshState {
 		changed = true
 	}
+
+	if changed && !noop {
+		finalStatus.Actions, err = t.maintenance(ctx, p, properties, removed)
+		if err != nil {
+			return nil, err
+		}
+	}
 	t.FinalizeState(finalStatus, noop, noopMessage, changed, !refreshState, false)
+	t.ClassifyChange(finalStatus, initialStatus.Ensure != EnsureAbsent)
 
 	return finalStatus, nil
 }
 
+// maintenance runs the requested clean up after the package changed and returns the actions performed, unused
+// dependencies are only removed after the package was uninstalled
+func (t *Type) maintenance(ctx context.Context, p PackageProvider, properties *model.PackageResourceProperties, removed bool) ([]string, error) {
+	mp, ok := p.(MaintenanceProvider)
+	if !ok {
+		return nil, nil
+	}
+
+	var actions []string
+
+	if properties.Autoremove && removed {
+		t.log.Info("Removing unused dependencies", "provider", p.Name())
+		err := mp.Autoremove(ctx)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, model.PackageActionAutoremove)
+	}
+
+	if properties.CleanCache {
+		t.log.Info("Cleaning package cache", "provider", p.Name())
+		err := mp.CleanCache(ctx)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, model.PackageActionCleanCache)
+	}
+
+	return actions, nil
+}
+
 // isDesiredState reports whether state matches properties. The second return is
 // a human-readable reason describing the mismatch when stable is false, suitable
 // for inclusion in error messages.
@@ -278,6 +331,37 @@ func (t *Type) Info(ctx context.Context) (any, error) {
 	return t.provider.(PackageProvider).Status(ctx, t.prop.Name)
 }
 
+// CurrentState reports the current state of the resource without making any changes
+func (t *Type) CurrentState(ctx context.Context) (model.ResourceState, error) {
+	state, err := t.provider.(PackageProvider).Status(ctx, t.prop.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// SelectAlternateProvider replaces the selected provider with the most suitable provider not listed in exclude
+func (t *Type) SelectAlternateProvider(exclude []string) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	runner, err := t.mgr.NewRunner()
+	if err != nil {
+		return "", err
+	}
+
+	selected, err := registry.FindAlternateProvider(model.PackageTypeName, exclude, t.Facts, t.prop, t.log, runner, t.mgr)
+	if err != nil {
+		return "", err
+	}
+
+	t.log.Debug("Selected alternate provider", "provider", selected.Name())
+	t.provider = selected
+
+	return t.providerUnlocked(), nil
+}
+
 func (t *Type) SelectProvider() (string, error) {
 	// TODO: move to base
 
@@ -304,9 +388,22 @@ func (t *Type) selectProviderUnlocked() error {
 		return err
 	}
 
-	selected, err := registry.FindSuitableProvider(model.PackageTypeName, t.prop.Provider, t.Facts, t.prop, t.log, runner)
-	if err != nil {
-		return err
+	var selected model.Provider
+
+	// the preferred manager is only a hint, when it cannot manage the package the provider is selected by priority
+	if t.prop.Provider == "" && t.prop.Manager != "" {
+		selected, err = registry.FindSuitableProvider(model.PackageTypeName, t.prop.Manager, t.Facts, t.prop, t.log, runner, t.mgr)
+		if err != nil {
+			t.log.Warn("Preferred package manager cannot be used, selecting a provider by priority", "manager", t.prop.Manager, "error", err)
+			selected = nil
+		}
+	}
+
+	if selected == nil {
+		selected, err = registry.FindSuitableProvider(model.PackageTypeName, t.prop.Provider, t.Facts, t.prop, t.log, runner, t.mgr)
+		if err != nil {
+			return err
+		}
 	}
 
 	if selected == nil {
@@ -116,14 +116,16 @@ var _ = Describe("Package Type", func() {
 		var factory *modelmocks.MockProviderFactory
 		var pkg *Type
 		var properties *model.PackageResourceProperties
+		var selected model.Provider
 		var err error
 
 		BeforeEach(func(ctx context.Context) {
+			selected = provider
 			factory = modelmocks.NewMockProviderFactory(mockctl)
 			factory.EXPECT().Name().Return("test").AnyTimes()
 			factory.EXPECT().TypeName().Return(model.PackageTypeName).AnyTimes()
 			factory.EXPECT().New(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(log model.Logger, runner model.CommandRunner) (model.Provider, error) {
-				return provider, nil
+				return selected, nil
 			})
 			properties = &model.PackageResourceProperties{
 				CommonResourceProperties: model.CommonResourceProperties{
@@ -236,6 +238,66 @@ var _ = Describe("Package Type", func() {
 				})
 			})
 
+			Context("with maintenance", func() {
+				var maintenance *MockMaintenanceProvider
+
+				BeforeEach(func() {
+					maintenance = NewMockMaintenanceProvider(mockctl)
+					selected = &maintainingProvider{MockPackageProvider: provider, MockMaintenanceProvider: maintenance}
+
+					pkg.prop.Ensure = EnsureAbsent
+					pkg.prop.Autoremove = true
+					pkg.Base.CommonProperties = pkg.prop.CommonResourceProperties
+				})
+
+				It("Should remove unused dependencies and clean the cache after uninstalling", func(ctx context.Context) {
+					pkg.prop.CleanCache = true
+
+					provider.EXPECT().Status(gomock.Any(), "zsh").Return(&model.PackageState{CommonResourceState: model.CommonResourceState{Name: "zsh", Ensure: "1.0.0"}}, nil)
+					provider.EXPECT().Uninstall(gomock.Any(), "zsh").Return(nil)
+					provider.EXPECT().Status(gomock.Any(), "zsh").Return(&model.PackageState{CommonResourceState: model.CommonResourceState{Name: "zsh", Ensure: EnsureAbsent}}, nil)
+					gomock.InOrder(
+						maintenance.EXPECT().Autoremove(gomock.Any()).Return(nil),
+						maintenance.EXPECT().CleanCache(gomock.Any()).Return(nil),
+					)
+
+					result, err := pkg.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.Changed).To(BeTrue())
+					Expect(result.Status.(*model.PackageState).Actions).To(Equal([]string{model.PackageActionAutoremove, model.PackageActionCleanCache}))
+				})
+
+				It("Should not remove unused dependencies when the package is already absent", func(ctx context.Context) {
+					pkg.prop.CleanCache = true
+
+					provider.EXPECT().Status(gomock.Any(), "zsh").Return(&model.PackageState{CommonResourceState: model.CommonResourceState{Name: "zsh", Ensure: EnsureAbsent}}, nil)
+
+					result, err := pkg.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.Changed).To(BeFalse())
+					Expect(result.Status.(*model.PackageState).Actions).To(BeEmpty())
+				})
+
+				It("Should fail when autoremove fails", func(ctx context.Context) {
+					provider.EXPECT().Status(gomock.Any(), "zsh").Return(&model.PackageState{CommonResourceState: model.CommonResourceState{Name: "zsh", Ensure: "1.0.0"}}, nil)
+					provider.EXPECT().Uninstall(gomock.Any(), "zsh").Return(nil)
+					provider.EXPECT().Status(gomock.Any(), "zsh").Return(&model.PackageState{CommonResourceState: model.CommonResourceState{Name: "zsh", Ensure: EnsureAbsent}}, nil)
+					maintenance.EXPECT().Autoremove(gomock.Any()).Return(fmt.Errorf("autoremove failed"))
+
+					event, err := pkg.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(event.Errors).To(ContainElement("autoremove failed"))
+				})
+
+				It("Should fail when the provider does not support maintenance", func(ctx context.Context) {
+					selected = provider
+
+					event, err := pkg.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(event.Errors).To(ContainElement("provider mock does not support autoremove or clean_cache"))
+				})
+			})
+
 			Context("when ensure is latest", func() {
 				BeforeEach(func() {
 					pkg.prop.Ensure = EnsureLatest
@@ -398,7 +460,7 @@ var _ = Describe("Package Type", func() {
 					state := &model.PackageState{CommonResourceState: model.CommonResourceState{Name: "zsh", Ensure: "1.0.0"}}
 
 					provider.EXPECT().Status(gomock.Any(), "zsh").Return(state, nil)
-					runner.EXPECT().Execute(gomock.Any(), "/usr/lib/nagios/plugins/check_disk", "-w", "20%").
+					runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/lib/nagios/plugins/check_disk", Args: []string{"-w", "20%"}}).
 						Return([]byte("DISK OK"), []byte{}, 0, nil)
 
 					result, err := pkg.Apply(ctx)
@@ -414,7 +476,7 @@ var _ = Describe("Package Type", func() {
 					state := &model.PackageState{CommonResourceState: model.CommonResourceState{Name: "zsh", Ensure: "1.0.0"}}
 
 					provider.EXPECT().Status(gomock.Any(), "zsh").Return(state, nil)
-					runner.EXPECT().Execute(gomock.Any(), "/usr/lib/nagios/plugins/check_disk", "-w", "20%").
+					runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/lib/nagios/plugins/check_disk", Args: []string{"-w", "20%"}}).
 						Return([]byte("DISK WARNING - 15% free"), []byte{}, 1, nil)
 
 					result, err := pkg.Apply(ctx)
@@ -432,7 +494,7 @@ var _ = Describe("Package Type", func() {
 					state := &model.PackageState{CommonResourceState: model.CommonResourceState{Name: "zsh", Ensure: "1.0.0"}}
 
 					provider.EXPECT().Status(gomock.Any(), "zsh").Return(state, nil)
-					runner.EXPECT().Execute(gomock.Any(), "/usr/lib/nagios/plugins/check_disk", "-w", "20%").
+					runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/lib/nagios/plugins/check_disk", Args: []string{"-w", "20%"}}).
 						Return([]byte("DISK CRITICAL - 5% free"), []byte{}, 2, nil)
 
 					result, err := pkg.Apply(ctx)
@@ -450,7 +512,7 @@ var _ = Describe("Package Type", func() {
 					state := &model.PackageState{CommonResourceState: model.CommonResourceState{Name: "zsh", Ensure: "1.0.0"}}
 
 					provider.EXPECT().Status(gomock.Any(), "zsh").Return(state, nil)
-					runner.EXPECT().Execute(gomock.Any(), "/usr/lib/nagios/plugins/check_disk", "-w", "20%").
+					runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/lib/nagios/plugins/check_disk", Args: []string{"-w", "20%"}}).
 						Return(nil, nil, 0, fmt.Errorf("command not found"))
 
 					result, err := pkg.Apply(ctx)
@@ -474,6 +536,33 @@ var _ = Describe("Package Type", func() {
 			})
 		})
 
+		Describe("Healthcheck", func() {
+			BeforeEach(func() {
+				factory.EXPECT().IsManageable(facts, gomock.Any()).Return(true, 1, nil).AnyTimes()
+			})
+
+			It("Should record health results without installing the package", func(ctx context.Context) {
+				pkg.prop.Ensure = EnsurePresent
+				pkg.prop.HealthChecks = []model.CommonHealthCheck{{
+					Command: "/usr/lib/nagios/plugins/check_disk -w 20%",
+				}}
+				absent := &model.PackageState{CommonResourceState: model.CommonResourceState{Name: "zsh", Ensure: EnsureAbsent}}
+
+				// no Install expectation, the mock fails the test when it is called
+				provider.EXPECT().Status(gomock.Any(), "zsh").Return(absent, nil)
+				runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/lib/nagios/plugins/check_disk", Args: []string{"-w", "20%"}}).
+					Return([]byte("DISK WARNING - 15% free"), []byte{}, 1, nil)
+
+				result, err := pkg.Healthcheck(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.HealthCheckOnly).To(BeTrue())
+				Expect(result.Changed).To(BeFalse())
+				Expect(result.FinalEnsure).To(Equal(EnsureAbsent))
+				Expect(result.HealthChecks).To(HaveLen(1))
+				Expect(result.HealthChecks[0].Status).To(Equal(model.HealthCheckWarning))
+			})
+		})
+
 		Describe("Apply in noop mode", func() {
 			var noopMgr *modelmocks.MockManager
 			var noopPkg *Type
@@ -660,3 +749,9 @@ var _ = Describe("Package Type", func() {
 		})
 	})
 })
+
+// maintainingProvider is a package provider that supports maintenance actions
+type maintainingProvider struct {
+	*MockPackageProvider
+	*MockMaintenanceProvider
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/choria-io/ccm/internal/registry"
+	"github.com/choria-io/ccm/model"
+)
+
+// Constructor creates a resource of a custom type, properties are always of the type returned by the
+// Properties function of the ResourceType
+type Constructor func(ctx context.Context, mgr model.Manager, properties model.ResourceProperties) (model.Resource, error)
+
+// ResourceType describes a resource type implemented outside of CCM
+type ResourceType struct {
+	// Name is the name manifests use to refer to the type
+	Name string
+	// Properties returns new empty properties for the type
+	Properties func() model.ResourceProperties
+	// New creates a resource from properties
+	New Constructor
+	// Providers are the provider factories that can manage resources of the type
+	Providers []model.ProviderFactory
+}
+
+var (
+	constructors = make(map[string]Constructor)
+	mu           sync.Mutex
+)
+
+// Register registers a custom resource type and its providers, after which manifests can use the type by name.
+// Types should be registered once, typically in an init function, before any manifest is loaded.
+func Register(t ResourceType) error {
+	if t.New == nil {
+		return fmt.Errorf("resource type %s requires a constructor", t.Name)
+	}
+
+	for _, p := range t.Providers {
+		if p.TypeName() != t.Name {
+			return fmt.Errorf("provider %s manages %s resources, not %s", p.Name(), p.TypeName(), t.Name)
+		}
+	}
+
+	err := model.RegisterResourceProperties(t.Name, t.Properties)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	constructors[t.Name] = t.New
+	mu.Unlock()
+
+	for _, p := range t.Providers {
+		err = registry.Register(p)
+		if err != nil {
+			return fmt.Errorf("could not register provider %s: %w", p.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// MustRegister registers a custom resource type and panics if registration fails
+func MustRegister(t ResourceType) {
+	err := Register(t)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// FindSuitableProvider selects a provider for a resource of a custom type, a specific provider is used when
+// provider is set otherwise the most suitable registered provider is chosen
+func FindSuitableProvider(mgr model.Manager, properties model.ResourceProperties, facts map[string]any, log model.Logger) (model.Provider, error) {
+	runner, err := mgr.NewRunner()
+	if err != nil {
+		return nil, err
+	}
+
+	cp := properties.CommonProperties()
+
+	selected, err := registry.FindSuitableProvider(cp.Type, cp.Provider, facts, properties, log, runner, mgr)
+	if err != nil {
+		return nil, err
+	}
+
+	if selected == nil {
+		return nil, model.ErrNoSuitableProvider
+	}
+
+	return selected, nil
+}
+
+func customConstructor(typeName string) (Constructor, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	c, ok := constructors[typeName]
+
+	return c, ok
+}
@@ -0,0 +1,301 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/goccy/go-yaml"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/choria-io/ccm/internal/registry"
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/model/modelmocks"
+	"github.com/choria-io/ccm/resources/apply"
+	"github.com/choria-io/ccm/resources/base"
+	"github.com/choria-io/ccm/templates"
+)
+
+// widget is an example of a resource type implemented outside of CCM, it manages an in-memory widget color
+
+const widgetTypeName = "widget"
+
+type widgetProperties struct {
+	model.CommonResourceProperties `yaml:",inline"`
+	Color                          string `json:"color" yaml:"color"`
+}
+
+func (p *widgetProperties) CommonProperties() *model.CommonResourceProperties {
+	return &p.CommonResourceProperties
+}
+
+func (p *widgetProperties) Validate() error {
+	err := p.CommonResourceProperties.Validate()
+	if err != nil {
+		return err
+	}
+
+	if p.Ensure != model.EnsurePresent && p.Ensure != model.EnsureAbsent {
+		return fmt.Errorf("%w: must be one of %q or %q", model.ErrInvalidEnsureValue, model.EnsurePresent, model.EnsureAbsent)
+	}
+
+	if p.Ensure == model.EnsurePresent && p.Color == "" {
+		return fmt.Errorf("color is required")
+	}
+
+	return nil
+}
+
+func (p *widgetProperties) ResolveTemplates(env *templates.Env) error {
+	return templates.ResolveStructTemplates(p, env, false)
+}
+
+func (p *widgetProperties) ToYamlManifest() (yaml.RawMessage, error) {
+	return yaml.Marshal(p)
+}
+
+type widgetState struct {
+	model.CommonResourceState
+
+	Color string `json:"color,omitempty"`
+}
+
+func (s *widgetState) CommonState() *model.CommonResourceState {
+	return &s.CommonResourceState
+}
+
+type widgetProvider struct {
+	widgets map[string]string
+	mu      sync.Mutex
+}
+
+func (p *widgetProvider) Name() string { return "memory" }
+
+func (p *widgetProvider) status(name string) *widgetState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	state := &widgetState{CommonResourceState: model.NewCommonResourceState("io.example.widget.state", widgetTypeName, name, model.EnsureAbsent)}
+	color, ok := p.widgets[name]
+	if ok {
+		state.Ensure = model.EnsurePresent
+		state.Color = color
+	}
+
+	return state
+}
+
+func (p *widgetProvider) set(name string, color string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if color == "" {
+		delete(p.widgets, name)
+		return
+	}
+
+	p.widgets[name] = color
+}
+
+type widgetFactory struct {
+	provider *widgetProvider
+}
+
+func (f *widgetFactory) TypeName() string { return widgetTypeName }
+func (f *widgetFactory) Name() string     { return "memory" }
+func (f *widgetFactory) New(model.Logger, model.CommandRunner) (model.Provider, error) {
+	return f.provider, nil
+}
+func (f *widgetFactory) IsManageable(map[string]any, model.ResourceProperties) (bool, int, error) {
+	return true, 1, nil
+}
+
+type widgetType struct {
+	*base.Base
+
+	prop     *widgetProperties
+	mgr      model.Manager
+	provider *widgetProvider
+}
+
+func newWidget(ctx context.Context, mgr model.Manager, props model.ResourceProperties) (model.Resource, error) {
+	prop := props.(*widgetProperties)
+
+	env, err := mgr.TemplateEnvironment(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = prop.ResolveTemplates(env)
+	if err != nil {
+		return nil, err
+	}
+
+	logger, err := mgr.Logger("type", widgetTypeName, "name", prop.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &widgetType{prop: prop, mgr: mgr}
+	t.Base = &base.Base{
+		Resource:           t,
+		ResourceProperties: prop,
+		CommonProperties:   prop.CommonResourceProperties,
+		Log:                logger,
+		UserLogger:         mgr.UserLogger(),
+		Manager:            mgr,
+		Facts:              env.Facts,
+		Data:               env.Data,
+	}
+
+	err = t.Base.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %w", t.String(), model.ErrResourceInvalid, err)
+	}
+
+	return t, nil
+}
+
+func (t *widgetType) ApplyResource(context.Context) (model.ResourceState, error) {
+	initial := t.provider.status(t.prop.Name)
+	if initial.Ensure == t.prop.Ensure && initial.Color == t.prop.Color {
+		t.FinalizeState(initial, t.mgr.NoopMode(), "", false, true, false)
+		return initial, nil
+	}
+
+	t.provider.set(t.prop.Name, t.prop.Color)
+
+	final := t.provider.status(t.prop.Name)
+	t.FinalizeState(final, false, "", true, true, false)
+
+	return final, nil
+}
+
+func (t *widgetType) SelectProvider() (string, error) {
+	if t.provider == nil {
+		p, err := FindSuitableProvider(t.mgr, t.prop, t.Facts, t.Log)
+		if err != nil {
+			return "", err
+		}
+		t.provider = p.(*widgetProvider)
+	}
+
+	return t.provider.Name(), nil
+}
+
+func (t *widgetType) Provider() string {
+	if t.provider == nil {
+		return ""
+	}
+
+	return t.provider.Name()
+}
+
+func (t *widgetType) Info(context.Context) (any, error) {
+	_, err := t.SelectProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return t.provider.status(t.prop.Name), nil
+}
+
+var widgets = &widgetProvider{widgets: map[string]string{}}
+
+func init() {
+	MustRegister(ResourceType{
+		Name:       widgetTypeName,
+		Properties: func() model.ResourceProperties { return &widgetProperties{} },
+		New:        newWidget,
+		Providers:  []model.ProviderFactory{&widgetFactory{provider: widgets}},
+	})
+}
+
+var _ = Describe("Register", func() {
+	var (
+		mgr     *modelmocks.MockManager
+		mockctl *gomock.Controller
+	)
+
+	BeforeEach(func() {
+		mockctl = gomock.NewController(GinkgoT())
+		mgr, _ = modelmocks.NewManager(map[string]any{}, map[string]any{}, false, mockctl)
+		runner := modelmocks.NewMockCommandRunner(mockctl)
+		mgr.EXPECT().NewRunner().AnyTimes().Return(runner, nil)
+
+		// other tests clear the provider registry
+		registry.Clear()
+		registry.MustRegister(&widgetFactory{provider: widgets})
+		widgets.set("w1", "")
+	})
+
+	It("Should validate registrations", func() {
+		props := func() model.ResourceProperties { return &widgetProperties{} }
+
+		Expect(Register(ResourceType{Name: "gadget", Properties: props})).To(MatchError("resource type gadget requires a constructor"))
+		Expect(Register(ResourceType{Name: "Gadget", Properties: props, New: newWidget})).To(MatchError(`invalid resource type name "Gadget"`))
+		Expect(Register(ResourceType{Name: model.FileTypeName, Properties: props, New: newWidget})).To(MatchError(model.ErrDuplicateResourceType))
+		Expect(Register(ResourceType{Name: widgetTypeName, Properties: props, New: newWidget})).To(MatchError(model.ErrDuplicateResourceType))
+		Expect(Register(ResourceType{Name: "gadget", Properties: props, New: newWidget, Providers: []model.ProviderFactory{&widgetFactory{}}})).To(MatchError("provider memory manages widget resources, not gadget"))
+		Expect(model.IsResourceType("gadget")).To(BeFalse())
+	})
+
+	It("Should list custom types with the built-in ones", func() {
+		Expect(model.ResourceTypeNames()).To(ContainElements(model.FileTypeName, widgetTypeName))
+
+		prop, err := model.NewEmptyResourceProperties(widgetTypeName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(prop).To(BeAssignableToTypeOf(&widgetProperties{}))
+		Expect(prop.CommonProperties().Type).To(Equal(widgetTypeName))
+	})
+
+	It("Should load and apply custom types from manifests", func(ctx context.Context) {
+		manifest := `
+ccm:
+  resources:
+    - widget:
+        - w1:
+            color: blue
+`
+		mgr.EXPECT().SetData(gomock.Any()).AnyTimes().Return(map[string]any{})
+
+		_, res, err := apply.ResolveManifestReader(ctx, mgr, ".", strings.NewReader(manifest))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Resources()).To(HaveLen(1))
+
+		prop := res.Resources()[0][widgetTypeName]
+		Expect(prop).To(BeAssignableToTypeOf(&widgetProperties{}))
+		Expect(prop.CommonProperties().Ensure).To(Equal(model.EnsurePresent))
+
+		resource, err := NewResourceFromProperties(ctx, mgr, prop)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resource.Type()).To(Equal(widgetTypeName))
+
+		event, err := resource.Apply(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(event.Errors).To(BeEmpty())
+		Expect(event.Provider).To(Equal("memory"))
+		Expect(event.Changed).To(BeTrue())
+		Expect(widgets.status("w1").Color).To(Equal("blue"))
+
+		event, err = resource.Apply(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(event.Changed).To(BeFalse())
+		Expect(event.Status.(*widgetState).Color).To(Equal("blue"))
+	})
+
+	It("Should validate custom properties", func(ctx context.Context) {
+		props, err := model.NewResourcePropertiesFromYaml(widgetTypeName, yaml.RawMessage("name: w1"), &templates.Env{})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = NewResourceFromProperties(ctx, mgr, props[0])
+		Expect(err).To(MatchError(ContainSubstring("color is required")))
+	})
+})
@@ -12,35 +12,67 @@ import (
 	"github.com/choria-io/ccm/resources/apply"
 	"github.com/choria-io/ccm/resources/applyresource"
 	archiveresource "github.com/choria-io/ccm/resources/archive"
+	cronresource "github.com/choria-io/ccm/resources/cron"
 	execresource "github.com/choria-io/ccm/resources/exec"
 	fileresource "github.com/choria-io/ccm/resources/file"
+	gitresource "github.com/choria-io/ccm/resources/git"
+	groupresource "github.com/choria-io/ccm/resources/group"
+	hostresource "github.com/choria-io/ccm/resources/host"
+	jsoneditresource "github.com/choria-io/ccm/resources/jsonedit"
 	packageresource "github.com/choria-io/ccm/resources/package"
 	scaffoldresource "github.com/choria-io/ccm/resources/scaffold"
 	serviceresource "github.com/choria-io/ccm/resources/service"
+	sshkeyresource "github.com/choria-io/ccm/resources/sshkey"
+	sudoersresource "github.com/choria-io/ccm/resources/sudoers"
+	yumreporesource "github.com/choria-io/ccm/resources/yumrepo"
 )
 
 func init() {
 	apply.ResourceFactory = NewResourceFromProperties
 }
 
-// NewResourceFromProperties creates a new resource from a properties struct
+// NewResourceFromProperties creates a new resource from a properties struct, properties of custom types are
+// passed to the constructor given to Register
 func NewResourceFromProperties(ctx context.Context, mgr model.Manager, props model.ResourceProperties) (model.Resource, error) {
 	switch rprop := props.(type) {
 	case *model.ApplyResourceProperties:
 		return applyresource.New(ctx, mgr, *rprop)
 	case *model.ArchiveResourceProperties:
 		return archiveresource.New(ctx, mgr, *rprop)
+	case *model.CronResourceProperties:
+		return cronresource.New(ctx, mgr, *rprop)
 	case *model.ExecResourceProperties:
 		return execresource.New(ctx, mgr, *rprop)
 	case *model.FileResourceProperties:
 		return fileresource.New(ctx, mgr, *rprop)
+	case *model.GitResourceProperties:
+		return gitresource.New(ctx, mgr, *rprop)
+	case *model.GroupResourceProperties:
+		return groupresource.New(ctx, mgr, *rprop)
+	case *model.HostResourceProperties:
+		return hostresource.New(ctx, mgr, *rprop)
+	case *model.JsonEditResourceProperties:
+		return jsoneditresource.New(ctx, mgr, *rprop)
 	case *model.PackageResourceProperties:
 		return packageresource.New(ctx, mgr, *rprop)
 	case *model.ScaffoldResourceProperties:
 		return scaffoldresource.New(ctx, mgr, *rprop)
 	case *model.ServiceResourceProperties:
 		return serviceresource.New(ctx, mgr, *rprop)
-	default:
+	case *model.SSHAuthorizedKeyResourceProperties:
+		return sshkeyresource.New(ctx, mgr, *rprop)
+	case *model.SudoersResourceProperties:
+		return sudoersresource.New(ctx, mgr, *rprop)
+	case *model.YumRepoResourceProperties:
+		return yumreporesource.New(ctx, mgr, *rprop)
+	case nil:
 		return nil, fmt.Errorf("unsupported resource property type %T", rprop)
+	default:
+		ctor, ok := customConstructor(rprop.CommonProperties().Type)
+		if !ok {
+			return nil, fmt.Errorf("unsupported resource property type %T", rprop)
+		}
+
+		return ctor(ctx, mgr, rprop)
 	}
 }
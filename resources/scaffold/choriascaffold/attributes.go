@@ -0,0 +1,165 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package choriascaffold
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/goccy/go-yaml"
+
+	iu "github.com/choria-io/ccm/internal/util"
+	"github.com/choria-io/ccm/model"
+)
+
+// fileAttributes is the desired ownership and mode of a scaffolded file, empty values are not managed
+type fileAttributes struct {
+	Owner string `yaml:"owner,omitempty"`
+	Group string `yaml:"group,omitempty"`
+	Mode  string `yaml:"mode,omitempty"`
+}
+
+func (a fileAttributes) managed() bool {
+	return a.Owner != "" || a.Group != "" || a.Mode != ""
+}
+
+// fileMeta holds per-file attribute overrides read from the source meta file keyed by slash separated relative path
+type fileMeta map[string]fileAttributes
+
+// loadFileMeta reads the meta file from the root of source, a missing file results in no overrides
+func loadFileMeta(source string) (fileMeta, error) {
+	metaFile := filepath.Join(source, model.ScaffoldMetaFileName)
+	if !iu.FileExists(metaFile) {
+		return nil, nil
+	}
+
+	raw, err := os.ReadFile(metaFile)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := fileMeta{}
+	err = yaml.Unmarshal(raw, &parsed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", model.ScaffoldMetaFileName, err)
+	}
+
+	meta := fileMeta{}
+	for path, attrs := range parsed {
+		if attrs.Mode != "" {
+			_, err = strconv.ParseUint(attrs.Mode, 8, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid mode for %s in %s: %w", path, model.ScaffoldMetaFileName, err)
+			}
+		}
+
+		meta[filepath.ToSlash(filepath.Clean(path))] = attrs
+	}
+
+	return meta, nil
+}
+
+// attributes merges the per-file overrides for path over the defaults set on the resource
+func (m fileMeta) attributes(prop *model.ScaffoldResourceProperties, path string) fileAttributes {
+	attrs := fileAttributes{Owner: prop.Owner, Group: prop.Group, Mode: prop.FileMode}
+
+	override, ok := m[path]
+	if !ok {
+		return attrs
+	}
+
+	if override.Owner != "" {
+		attrs.Owner = override.Owner
+	}
+	if override.Group != "" {
+		attrs.Group = override.Group
+	}
+	if override.Mode != "" {
+		attrs.Mode = override.Mode
+	}
+
+	return attrs
+}
+
+// attributesDrifted reports whether the file at path differs from the desired attributes
+func attributesDrifted(path string, attrs fileAttributes) (bool, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+
+	owner, group, mode, err := iu.GetFileOwner(stat)
+	if err != nil {
+		return false, err
+	}
+
+	if attrs.Owner != "" && !iu.UserIDMatches(attrs.Owner, owner) {
+		return true, nil
+	}
+
+	if attrs.Group != "" && !iu.GroupIDMatches(attrs.Group, group) {
+		return true, nil
+	}
+
+	if attrs.Mode != "" {
+		desired, err := strconv.ParseUint(attrs.Mode, 8, 32)
+		if err != nil {
+			return false, err
+		}
+		actual, err := strconv.ParseUint(mode, 8, 32)
+		if err != nil {
+			return false, err
+		}
+		if desired != actual {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// applyAttributes sets the desired ownership and mode on the file at path
+func applyAttributes(path string, attrs fileAttributes) error {
+	uid, gid := -1, -1
+	var err error
+
+	if attrs.Owner != "" {
+		uid, err = iu.LookupUserID(attrs.Owner)
+		if err != nil {
+			return err
+		}
+	}
+
+	if attrs.Group != "" {
+		gid, err = iu.LookupGroupID(attrs.Group)
+		if err != nil {
+			return err
+		}
+	}
+
+	if uid != -1 || gid != -1 {
+		err = os.Chown(path, uid, gid)
+		if err != nil {
+			return fmt.Errorf("could not set ownership of %s: %w", path, err)
+		}
+	}
+
+	if attrs.Mode != "" {
+		mode, err := strconv.ParseUint(attrs.Mode, 8, 32)
+		if err != nil {
+			return err
+		}
+
+		err = os.Chmod(path, fs.FileMode(mode))
+		if err != nil {
+			return fmt.Errorf("could not set mode of %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
@@ -7,8 +7,12 @@ package choriascaffold
 import (
 	"context"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	iu "github.com/choria-io/ccm/internal/util"
 	"github.com/choria-io/ccm/model"
@@ -141,44 +145,35 @@ func (p *Provider) render(_ context.Context, env *templates.Env, prop *model.Sca
 		Metadata:            metadata,
 	}
 
-	var s *scaffold.Scaffold
-	var err error
-
-	cfg := scaffold.Config{
-		TargetDirectory:      prop.Name,
-		SourceDirectory:      prop.Source,
-		MergeTargetDirectory: true,
-		Post:                 prop.Post,
-		SkipEmpty:            prop.SkipEmpty,
-		CustomLeftDelimiter:  prop.LeftDelimiter,
-		CustomRightDelimiter: prop.RightDelimiter,
-	}
-
-	switch prop.Engine {
-	case model.ScaffoldEngineGo:
-		s, err = scaffold.New(cfg, env.GoFunctions())
-	case model.ScaffoldEngineJet:
-		s, err = scaffold.NewJet(cfg, env.JetFunctions())
-	default:
-		return nil, fmt.Errorf("unknown scaffold engine %s", prop.Engine)
-	}
+	meta, err := loadFileMeta(prop.Source)
 	if err != nil {
 		return nil, err
 	}
 
-	s.Logger(&logger{p.log})
+	filter := newFileFilter(prop)
 
 	var result []scaffold.ManagedFile
-	if noop {
-		result, err = s.RenderNoop(env.JetVariables())
+
+	if prop.Engine == model.ScaffoldEngineRaw {
+		result, err = p.copyRaw(prop, filter, noop)
 	} else {
-		result, err = s.Render(env.JetVariables())
+		result, err = p.renderTemplates(env, prop, filter, noop)
 	}
 	if err != nil {
 		p.log.Error("Failed to render scaffold", "error", err)
 		return nil, err
 	}
 
+	// files in the target that are not managed are never reported, which also prevents them being purged
+	result = slices.DeleteFunc(result, func(f scaffold.ManagedFile) bool {
+		return !filter.managed(f.Path)
+	})
+
+	err = p.manageAttributes(prop, meta, result, noop)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, f := range result {
 		switch f.Action {
 		case scaffold.FileActionEqual:
@@ -203,3 +198,257 @@ func (p *Provider) render(_ context.Context, env *templates.Env, prop *model.Sca
 	}
 	return state, nil
 }
+
+// manageAttributes reports files with drifted ownership or mode as updated and, unless noop, sets the desired
+// ownership and mode on all written or drifted files
+func (p *Provider) manageAttributes(prop *model.ScaffoldResourceProperties, meta fileMeta, result []scaffold.ManagedFile, noop bool) error {
+	for i, f := range result {
+		if f.Action == scaffold.FileActionRemove {
+			continue
+		}
+
+		attrs := meta.attributes(prop, f.Path)
+		if !attrs.managed() {
+			continue
+		}
+
+		path := filepath.Join(prop.Name, f.Path)
+
+		if f.Action == scaffold.FileActionEqual {
+			drifted, err := attributesDrifted(path, attrs)
+			if err != nil {
+				return err
+			}
+			if !drifted {
+				continue
+			}
+
+			p.log.Debug("File ownership or mode differs", "file", path)
+			result[i].Action = scaffold.FileActionUpdate
+		}
+
+		if noop {
+			continue
+		}
+
+		err := applyAttributes(path, attrs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renderTemplates renders the source using the go or jet template engines
+func (p *Provider) renderTemplates(env *templates.Env, prop *model.ScaffoldResourceProperties, filter *fileFilter, noop bool) ([]scaffold.ManagedFile, error) {
+	var s *scaffold.Scaffold
+	var err error
+
+	source, cleanup, err := filteredSource(prop.Source, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	cfg := scaffold.Config{
+		TargetDirectory:      prop.Name,
+		SourceDirectory:      source,
+		MergeTargetDirectory: true,
+		Post:                 prop.Post,
+		SkipEmpty:            prop.SkipEmpty,
+		CustomLeftDelimiter:  prop.LeftDelimiter,
+		CustomRightDelimiter: prop.RightDelimiter,
+	}
+
+	switch prop.Engine {
+	case model.ScaffoldEngineGo:
+		s, err = scaffold.New(cfg, env.GoFunctions())
+	case model.ScaffoldEngineJet:
+		s, err = scaffold.NewJet(cfg, env.JetFunctions())
+	default:
+		return nil, fmt.Errorf("unknown scaffold engine %s", prop.Engine)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.Logger(&logger{p.log})
+
+	err = env.LoadAllData()
+	if err != nil {
+		return nil, err
+	}
+
+	if noop {
+		return s.RenderNoop(env.JetVariables())
+	}
+
+	return s.Render(env.JetVariables())
+}
+
+// copyRaw copies the source files to the target without rendering them, files are compared by checksum and only
+// added or updated files are written. Like rendered scaffolds, files in the target not in the source are reported
+// for removal.
+func (p *Provider) copyRaw(prop *model.ScaffoldResourceProperties, filter *fileFilter, noop bool) ([]scaffold.ManagedFile, error) {
+	var result []scaffold.ManagedFile
+	copied := map[string]struct{}{}
+
+	err := filepath.WalkDir(prop.Source, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path == prop.Source {
+			return nil
+		}
+
+		rel, err := filepath.Rel(prop.Source, path)
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if filter.excluded(filepath.ToSlash(rel)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if rel == model.ScaffoldMetaFileName || !filter.managed(filepath.ToSlash(rel)) {
+			return nil
+		}
+
+		if !d.Type().IsRegular() {
+			return fmt.Errorf("invalid file in source: %v", d.Name())
+		}
+
+		if prop.SkipEmpty {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			if info.Size() == 0 {
+				return nil
+			}
+		}
+
+		relSlash := filepath.ToSlash(rel)
+		copied[relSlash] = struct{}{}
+
+		action, err := rawFileAction(path, filepath.Join(prop.Name, rel))
+		if err != nil {
+			return err
+		}
+
+		result = append(result, scaffold.ManagedFile{Path: relSlash, Action: action})
+
+		if noop || action == scaffold.FileActionEqual {
+			return nil
+		}
+
+		p.log.Debug("Copying file", "file", rel)
+
+		return copyRawFile(path, filepath.Join(prop.Name, rel))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if iu.IsDirectory(prop.Name) {
+		err = filepath.WalkDir(prop.Name, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+
+			rel, err := filepath.Rel(prop.Name, path)
+			if err != nil {
+				return err
+			}
+
+			if _, ok := copied[filepath.ToSlash(rel)]; !ok {
+				result = append(result, scaffold.ManagedFile{Path: filepath.ToSlash(rel), Action: scaffold.FileActionRemove})
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	slices.SortFunc(result, func(a, b scaffold.ManagedFile) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return result, nil
+}
+
+// rawFileAction compares the source and target file by checksum
+func rawFileAction(source string, target string) (scaffold.FileAction, error) {
+	if !iu.FileExists(target) {
+		return scaffold.FileActionAdd, nil
+	}
+
+	sourceSum, err := iu.Sha256HashFile(source)
+	if err != nil {
+		return "", err
+	}
+
+	targetSum, err := iu.Sha256HashFile(target)
+	if err != nil {
+		return "", err
+	}
+
+	if sourceSum == targetSum {
+		return scaffold.FileActionEqual, nil
+	}
+
+	return scaffold.FileActionUpdate, nil
+}
+
+// copyRawFile atomically replaces target with a copy of source keeping the mode of the source
+func copyRawFile(source string, target string) error {
+	err := os.MkdirAll(filepath.Dir(target), 0755)
+	if err != nil {
+		return err
+	}
+
+	sf, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer sf.Close()
+
+	stat, err := sf.Stat()
+	if err != nil {
+		return err
+	}
+
+	tf, err := os.CreateTemp(filepath.Dir(target), fmt.Sprintf(".%s.*", filepath.Base(target)))
+	if err != nil {
+		return err
+	}
+	defer tf.Close()
+	defer os.Remove(tf.Name())
+
+	_, err = io.Copy(tf, sf)
+	if err != nil {
+		return err
+	}
+
+	err = tf.Chmod(stat.Mode().Perm())
+	if err != nil {
+		return fmt.Errorf("could not set mode on temporary file: %w", err)
+	}
+
+	err = tf.Close()
+	if err != nil {
+		return fmt.Errorf("could not close temporary file: %w", err)
+	}
+
+	return os.Rename(tf.Name(), target)
+}
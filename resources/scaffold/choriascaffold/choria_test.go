@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/user"
 	"path/filepath"
 	"testing"
 
@@ -596,6 +597,248 @@ var _ = Describe("Choria Scaffold Provider", func() {
 			Expect(state.Metadata.Stable).To(ContainElement(filepath.Join(targetDir, "config.txt")))
 			Expect(state.Metadata.Changed).To(BeEmpty())
 		})
+
+		Context("with the raw engine", func() {
+			var prop *model.ScaffoldResourceProperties
+
+			BeforeEach(func() {
+				prop = &model.ScaffoldResourceProperties{
+					CommonResourceProperties: model.CommonResourceProperties{
+						Name:   targetDir,
+						Ensure: model.EnsurePresent,
+					},
+					Source: sourceDir,
+					Engine: model.ScaffoldEngineRaw,
+				}
+
+				Expect(os.MkdirAll(filepath.Join(sourceDir, "templates"), 0755)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(sourceDir, "templates", "page.html"), []byte("<p>{{ not_a_template }}</p>\n"), 0644)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(sourceDir, "run.sh"), []byte("#!/bin/sh\necho [[ .x ]]\n"), 0755)).To(Succeed())
+			})
+
+			It("Should copy files verbatim", func(ctx context.Context) {
+				state, err := provider.Scaffold(ctx, env, prop, false)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(state.Metadata.Changed).To(Equal([]string{filepath.Join(targetDir, "run.sh"), filepath.Join(targetDir, "templates", "page.html")}))
+
+				content, err := os.ReadFile(filepath.Join(targetDir, "templates", "page.html"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(content)).To(Equal("<p>{{ not_a_template }}</p>\n"))
+
+				content, err = os.ReadFile(filepath.Join(targetDir, "run.sh"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(content)).To(Equal("#!/bin/sh\necho [[ .x ]]\n"))
+
+				stat, err := os.Stat(filepath.Join(targetDir, "run.sh"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stat.Mode().Perm()).To(Equal(os.FileMode(0755)))
+			})
+
+			It("Should compare files by checksum", func(ctx context.Context) {
+				_, err := provider.Scaffold(ctx, env, prop, false)
+				Expect(err).ToNot(HaveOccurred())
+
+				state, err := provider.Status(ctx, env, prop)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(state.Metadata.Changed).To(BeEmpty())
+				Expect(state.Metadata.Stable).To(HaveLen(2))
+
+				Expect(os.WriteFile(filepath.Join(targetDir, "run.sh"), []byte("edited"), 0755)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(targetDir, "local.txt"), []byte("local"), 0644)).To(Succeed())
+
+				state, err = provider.Status(ctx, env, prop)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(state.Metadata.Changed).To(Equal([]string{filepath.Join(targetDir, "run.sh")}))
+				Expect(state.Metadata.Stable).To(Equal([]string{filepath.Join(targetDir, "templates", "page.html")}))
+				Expect(state.Metadata.Purged).To(Equal([]string{filepath.Join(targetDir, "local.txt")}))
+
+				content, err := os.ReadFile(filepath.Join(targetDir, "run.sh"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(content)).To(Equal("edited"))
+			})
+
+			It("Should not write files in noop mode", func(ctx context.Context) {
+				state, err := provider.Scaffold(ctx, env, prop, true)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(state.Metadata.Changed).To(HaveLen(2))
+				Expect(filepath.Join(targetDir, "run.sh")).ToNot(BeAnExistingFile())
+			})
+
+			It("Should purge files not in the source", func(ctx context.Context) {
+				prop.Purge = true
+				Expect(os.WriteFile(filepath.Join(targetDir, "local.txt"), []byte("local"), 0644)).To(Succeed())
+
+				_, err := provider.Scaffold(ctx, env, prop, false)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(filepath.Join(targetDir, "local.txt")).ToNot(BeAnExistingFile())
+			})
+		})
+
+		Context("with file ownership and mode", func() {
+			var prop *model.ScaffoldResourceProperties
+
+			BeforeEach(func() {
+				current, err := user.Current()
+				Expect(err).ToNot(HaveOccurred())
+
+				prop = &model.ScaffoldResourceProperties{
+					CommonResourceProperties: model.CommonResourceProperties{
+						Name:   targetDir,
+						Ensure: model.EnsurePresent,
+					},
+					Source:         sourceDir,
+					Engine:         model.ScaffoldEngineJet,
+					LeftDelimiter:  "[[",
+					RightDelimiter: "]]",
+					Owner:          current.Uid,
+					FileMode:       "0640",
+				}
+
+				Expect(os.MkdirAll(filepath.Join(sourceDir, "bin"), 0755)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(sourceDir, "config.txt"), []byte("host: [[ facts.hostname ]]\n"), 0644)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(sourceDir, "bin", "run.sh"), []byte("#!/bin/sh\n"), 0644)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(sourceDir, model.ScaffoldMetaFileName), []byte("bin/run.sh:\n  mode: \"0750\"\n"), 0644)).To(Succeed())
+			})
+
+			It("Should set the requested mode on written files", func(ctx context.Context) {
+				state, err := provider.Scaffold(ctx, env, prop, false)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(state.Metadata.Changed).To(Equal([]string{filepath.Join(targetDir, "bin", "run.sh"), filepath.Join(targetDir, "config.txt")}))
+
+				stat, err := os.Stat(filepath.Join(targetDir, "config.txt"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stat.Mode().Perm()).To(Equal(os.FileMode(0640)))
+
+				stat, err = os.Stat(filepath.Join(targetDir, "bin", "run.sh"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stat.Mode().Perm()).To(Equal(os.FileMode(0750)))
+
+				Expect(filepath.Join(targetDir, model.ScaffoldMetaFileName)).ToNot(BeAnExistingFile())
+			})
+
+			It("Should report and correct mode drift", func(ctx context.Context) {
+				_, err := provider.Scaffold(ctx, env, prop, false)
+				Expect(err).ToNot(HaveOccurred())
+
+				state, err := provider.Status(ctx, env, prop)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(state.Metadata.Changed).To(BeEmpty())
+				Expect(state.Metadata.Stable).To(HaveLen(2))
+
+				Expect(os.Chmod(filepath.Join(targetDir, "config.txt"), 0600)).To(Succeed())
+
+				state, err = provider.Status(ctx, env, prop)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(state.Metadata.Changed).To(Equal([]string{filepath.Join(targetDir, "config.txt")}))
+
+				stat, err := os.Stat(filepath.Join(targetDir, "config.txt"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stat.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+				_, err = provider.Scaffold(ctx, env, prop, false)
+				Expect(err).ToNot(HaveOccurred())
+
+				stat, err = os.Stat(filepath.Join(targetDir, "config.txt"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stat.Mode().Perm()).To(Equal(os.FileMode(0640)))
+			})
+
+			It("Should copy raw files with the requested mode", func(ctx context.Context) {
+				prop.Engine = model.ScaffoldEngineRaw
+				prop.LeftDelimiter = ""
+				prop.RightDelimiter = ""
+
+				_, err := provider.Scaffold(ctx, env, prop, false)
+				Expect(err).ToNot(HaveOccurred())
+
+				stat, err := os.Stat(filepath.Join(targetDir, "bin", "run.sh"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stat.Mode().Perm()).To(Equal(os.FileMode(0750)))
+				Expect(filepath.Join(targetDir, model.ScaffoldMetaFileName)).ToNot(BeAnExistingFile())
+			})
+
+			It("Should fail for an invalid meta file", func(ctx context.Context) {
+				Expect(os.WriteFile(filepath.Join(sourceDir, model.ScaffoldMetaFileName), []byte("config.txt:\n  mode: \"rw\"\n"), 0644)).To(Succeed())
+
+				_, err := provider.Status(ctx, env, prop)
+				Expect(err).To(MatchError(ContainSubstring("invalid mode for config.txt")))
+			})
+		})
+
+		Context("with include and exclude filters", func() {
+			var prop *model.ScaffoldResourceProperties
+
+			BeforeEach(func() {
+				prop = &model.ScaffoldResourceProperties{
+					CommonResourceProperties: model.CommonResourceProperties{
+						Name:   targetDir,
+						Ensure: model.EnsurePresent,
+					},
+					Source:         sourceDir,
+					Engine:         model.ScaffoldEngineJet,
+					LeftDelimiter:  "[[",
+					RightDelimiter: "]]",
+					Purge:          true,
+					Exclude:        []string{".git", "*.bak"},
+				}
+
+				Expect(os.MkdirAll(filepath.Join(sourceDir, ".git"), 0755)).To(Succeed())
+				Expect(os.MkdirAll(filepath.Join(sourceDir, "conf"), 0755)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(sourceDir, ".git", "config"), []byte("[[ broken"), 0644)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(sourceDir, "conf", "app.conf"), []byte("host: [[ facts.hostname ]]\n"), 0644)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(sourceDir, "conf", "app.conf.bak"), []byte("old"), 0644)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(sourceDir, "README.md"), []byte("readme"), 0644)).To(Succeed())
+
+				Expect(os.MkdirAll(filepath.Join(targetDir, ".git"), 0755)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(targetDir, ".git", "HEAD"), []byte("ref"), 0644)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(targetDir, "local.bak"), []byte("local"), 0644)).To(Succeed())
+			})
+
+			It("Should never write, report or purge excluded files", func(ctx context.Context) {
+				state, err := provider.Status(ctx, env, prop)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(state.Metadata.Changed).To(Equal([]string{filepath.Join(targetDir, "README.md"), filepath.Join(targetDir, "conf", "app.conf")}))
+				Expect(state.Metadata.Purged).To(BeEmpty())
+
+				state, err = provider.Scaffold(ctx, env, prop, false)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(state.Metadata.Purged).To(BeEmpty())
+
+				Expect(filepath.Join(targetDir, "conf", "app.conf")).To(BeAnExistingFile())
+				Expect(filepath.Join(targetDir, ".git", "config")).ToNot(BeAnExistingFile())
+				Expect(filepath.Join(targetDir, "conf", "app.conf.bak")).ToNot(BeAnExistingFile())
+				Expect(filepath.Join(targetDir, ".git", "HEAD")).To(BeAnExistingFile())
+				Expect(filepath.Join(targetDir, "local.bak")).To(BeAnExistingFile())
+
+				state, err = provider.Status(ctx, env, prop)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(state.Metadata.Changed).To(BeEmpty())
+				Expect(state.Metadata.Stable).To(HaveLen(2))
+			})
+
+			It("Should only manage included files", func(ctx context.Context) {
+				prop.Include = []string{"conf"}
+
+				state, err := provider.Scaffold(ctx, env, prop, false)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(state.Metadata.Changed).To(Equal([]string{filepath.Join(targetDir, "conf", "app.conf")}))
+				Expect(state.Metadata.Purged).To(BeEmpty())
+				Expect(filepath.Join(targetDir, "README.md")).ToNot(BeAnExistingFile())
+			})
+
+			It("Should filter raw copies", func(ctx context.Context) {
+				prop.Engine = model.ScaffoldEngineRaw
+				prop.LeftDelimiter = ""
+				prop.RightDelimiter = ""
+
+				state, err := provider.Scaffold(ctx, env, prop, false)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(state.Metadata.Changed).To(Equal([]string{filepath.Join(targetDir, "README.md"), filepath.Join(targetDir, "conf", "app.conf")}))
+				Expect(state.Metadata.Purged).To(BeEmpty())
+				Expect(filepath.Join(targetDir, ".git", "config")).ToNot(BeAnExistingFile())
+				Expect(filepath.Join(targetDir, ".git", "HEAD")).To(BeAnExistingFile())
+			})
+		})
 	})
 
 	Describe("Remove", func() {
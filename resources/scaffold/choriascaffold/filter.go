@@ -0,0 +1,141 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package choriascaffold
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	iu "github.com/choria-io/ccm/internal/util"
+	"github.com/choria-io/ccm/model"
+)
+
+// fileFilter selects the files managed by a scaffold using the include and exclude globs
+type fileFilter struct {
+	include []string
+	exclude []string
+}
+
+func newFileFilter(prop *model.ScaffoldResourceProperties) *fileFilter {
+	return &fileFilter{include: prop.Include, exclude: prop.Exclude}
+}
+
+func (f *fileFilter) empty() bool {
+	return len(f.include) == 0 && len(f.exclude) == 0
+}
+
+// excluded reports whether the slash separated relative path, or any of its parent directories, matches an exclude
+func (f *fileFilter) excluded(rel string) bool {
+	return matchAnyGlob(f.exclude, rel)
+}
+
+// managed reports whether the slash separated relative path is not excluded and, when includes are set, is included
+func (f *fileFilter) managed(rel string) bool {
+	if f.excluded(rel) {
+		return false
+	}
+
+	if len(f.include) == 0 {
+		return true
+	}
+
+	return matchAnyGlob(f.include, rel)
+}
+
+// matchAnyGlob matches patterns against the full path and every parent directory, patterns without a slash
+// are also matched against each individual path element
+func matchAnyGlob(patterns []string, rel string) bool {
+	elements := strings.Split(rel, "/")
+
+	for _, pattern := range patterns {
+		for i := range elements {
+			candidate := strings.Join(elements[:i+1], "/")
+			if ok, _ := path.Match(pattern, candidate); ok {
+				return true
+			}
+
+			if !strings.Contains(pattern, "/") {
+				if ok, _ := path.Match(pattern, elements[i]); ok {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// insidePartials reports whether the slash separated relative path is within a template partials directory
+func insidePartials(rel string) bool {
+	return slices.Contains(strings.Split(rel, "/"), "_partials")
+}
+
+// filteredSource returns a source directory without the meta file and without files the filter does not manage,
+// partials are only subject to excludes. When nothing needs filtering the source is used as is. The returned
+// function removes any temporary copy and must always be called.
+func filteredSource(source string, filter *fileFilter) (string, func(), error) {
+	if filter.empty() && !iu.FileExists(filepath.Join(source, model.ScaffoldMetaFileName)) {
+		return source, func() {}, nil
+	}
+
+	td, err := os.MkdirTemp("", "ccm-scaffold-source-")
+	if err != nil {
+		return "", func() {}, err
+	}
+	cleanup := func() { os.RemoveAll(td) }
+
+	err = filepath.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path == source {
+			return nil
+		}
+
+		rel, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		relSlash := filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if filter.excluded(relSlash) {
+				return filepath.SkipDir
+			}
+
+			return os.MkdirAll(filepath.Join(td, rel), 0700)
+		}
+
+		if relSlash == model.ScaffoldMetaFileName {
+			return nil
+		}
+
+		if insidePartials(relSlash) {
+			if filter.excluded(relSlash) {
+				return nil
+			}
+		} else if !filter.managed(relSlash) {
+			return nil
+		}
+
+		if !d.Type().IsRegular() {
+			return fmt.Errorf("invalid file in source: %v", d.Name())
+		}
+
+		return copyRawFile(path, filepath.Join(td, rel))
+	})
+	if err != nil {
+		cleanup()
+		return "", func() {}, err
+	}
+
+	return td, cleanup, nil
+}
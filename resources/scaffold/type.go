@@ -101,6 +101,7 @@ func (t *Type) ApplyResource(ctx context.Context) (model.ResourceState, error) {
 
 	if len(properties.Data) > 0 {
 		env.Data = properties.Data
+		env.DataFunc = nil
 	}
 
 	initialStatus, err := p.Status(ctx, env, t.prop)
@@ -127,6 +128,7 @@ func (t *Type) ApplyResource(ctx context.Context) (model.ResourceState, error) {
 		changed := affected > 0
 
 		t.FinalizeState(initialStatus, true, noopMessage, changed, false, false)
+		t.ClassifyChange(initialStatus, initialStatus.Metadata.TargetExists)
 
 		return initialStatus, nil
 	}
@@ -158,6 +160,7 @@ func (t *Type) ApplyResource(ctx context.Context) (model.ResourceState, error) {
 	}
 
 	t.FinalizeState(finalStatus, false, "", true, true, false)
+	t.ClassifyChange(finalStatus, initialStatus.Metadata.TargetExists)
 
 	return finalStatus, nil
 }
@@ -248,6 +251,7 @@ func (t *Type) validate() error {
 		t.prop.Engine = model.ScaffoldEngineJet
 	}
 
+	// the raw engine does not render templates so it has no delimiters
 	if t.prop.Engine == model.ScaffoldEngineGo {
 		if t.prop.LeftDelimiter == "" {
 			t.prop.LeftDelimiter = "{{"
@@ -292,7 +296,7 @@ func (t *Type) selectProviderUnlocked() error {
 		return err
 	}
 
-	selected, err := registry.FindSuitableProvider(model.ScaffoldTypeName, t.prop.Provider, t.Facts, t.prop, t.log, runner)
+	selected, err := registry.FindSuitableProvider(model.ScaffoldTypeName, t.prop.Provider, t.Facts, t.prop, t.log, runner, t.mgr)
 	if err != nil {
 		return err
 	}
@@ -209,6 +209,33 @@ var _ = Describe("Scaffold Type", func() {
 			Expect(scaffold.prop.RightDelimiter).To(Equal("}}"))
 		})
 
+		It("Should not set delimiters when engine is raw", func(ctx context.Context) {
+			scaffold, err := New(ctx, mgr, model.ScaffoldResourceProperties{
+				CommonResourceProperties: model.CommonResourceProperties{
+					Name:   "/opt/app/scaffold",
+					Ensure: model.EnsurePresent,
+				},
+				Source: "https://example.com/scaffold.tar.gz",
+				Engine: model.ScaffoldEngineRaw,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(scaffold.prop.LeftDelimiter).To(BeEmpty())
+			Expect(scaffold.prop.RightDelimiter).To(BeEmpty())
+		})
+
+		It("Should reject delimiters when engine is raw", func(ctx context.Context) {
+			_, err := New(ctx, mgr, model.ScaffoldResourceProperties{
+				CommonResourceProperties: model.CommonResourceProperties{
+					Name:   "/opt/app/scaffold",
+					Ensure: model.EnsurePresent,
+				},
+				Source:        "https://example.com/scaffold.tar.gz",
+				Engine:        model.ScaffoldEngineRaw,
+				LeftDelimiter: "<%",
+			})
+			Expect(err).To(MatchError(ContainSubstring("delimiters cannot be used with the \"raw\" engine")))
+		})
+
 		It("Should preserve custom delimiters", func(ctx context.Context) {
 			scaffold, err := New(ctx, mgr, model.ScaffoldResourceProperties{
 				CommonResourceProperties: model.CommonResourceProperties{
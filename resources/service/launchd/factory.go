@@ -0,0 +1,44 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package launchd
+
+import (
+	"runtime"
+
+	"github.com/choria-io/ccm/internal/registry"
+	iu "github.com/choria-io/ccm/internal/util"
+	"github.com/choria-io/ccm/model"
+)
+
+// Register registers this provider with the registry
+func Register() {
+	registry.MustRegister(&factory{})
+}
+
+// goos is the operating system launchd is used on, a variable so tests can simulate macOS
+var goos = runtime.GOOS
+
+type factory struct{}
+
+func (p *factory) TypeName() string { return "service" }
+func (p *factory) Name() string     { return ProviderName }
+func (p *factory) New(log model.Logger, runner model.CommandRunner) (model.Provider, error) {
+	return NewLaunchdProvider(log, runner)
+}
+func (p *factory) IsManageable(_ map[string]any, _ model.ResourceProperties) (bool, int, error) {
+	if goos != "darwin" {
+		return false, 0, nil
+	}
+
+	_, found, err := iu.ExecutableInPath("launchctl")
+	if err != nil {
+		return false, 0, err
+	}
+	if !found {
+		return false, 0, nil
+	}
+
+	return true, 1, nil
+}
@@ -0,0 +1,253 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package launchd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/choria-io/ccm/model"
+)
+
+const (
+	ProviderName = "launchd"
+
+	// domain is the launchd domain system daemons are managed in
+	domain = "system"
+
+	// exitNotFound is the exit code launchctl uses when a service is not loaded
+	exitNotFound = 113
+)
+
+// plistDirs are searched in order for the property list of a service that is not loaded
+var plistDirs = []string{"/Library/LaunchDaemons", "/System/Library/LaunchDaemons"}
+
+type Provider struct {
+	log    model.Logger
+	runner model.CommandRunner
+}
+
+// NewLaunchdProvider creates a new launchd service provider
+func NewLaunchdProvider(log model.Logger, runner model.CommandRunner) (*Provider, error) {
+	return &Provider{log: log, runner: runner}, nil
+}
+
+// We ensure that any user of this provider in the same process will not call launchctl concurrently
+func (p *Provider) execute(ctx context.Context, cmd string, args ...string) (stdout []byte, stderr []byte, exitCode int, err error) {
+	model.ServiceGlobalLock.Lock()
+	defer model.ServiceGlobalLock.Unlock()
+
+	return p.runner.Execute(ctx, cmd, args...)
+}
+
+// launchctl runs launchctl and fails when it exits non zero
+func (p *Provider) launchctl(ctx context.Context, args ...string) error {
+	stdout, stderr, exitCode, err := p.execute(ctx, "launchctl", args...)
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		return fmt.Errorf("launchctl %s failed with exit code %d: %s", args[0], exitCode, bytes.TrimSpace(append(stdout, stderr...)))
+	}
+
+	return nil
+}
+
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+func (p *Provider) Enable(ctx context.Context, service string) error {
+	return p.launchctl(ctx, "enable", target(service))
+}
+
+func (p *Provider) Disable(ctx context.Context, service string) error {
+	return p.launchctl(ctx, "disable", target(service))
+}
+
+// Start loads the service from its property list, a service that is already loaded is kicked. Launchd refuses to
+// load disabled services so a disabled service is enabled first.
+func (p *Provider) Start(ctx context.Context, service string) error {
+	loaded, _, err := p.isRunning(ctx, service)
+	if err != nil {
+		return err
+	}
+
+	if loaded {
+		return p.launchctl(ctx, "kickstart", target(service))
+	}
+
+	plist, err := plistPath(service)
+	if err != nil {
+		return err
+	}
+
+	enabled, err := p.isEnabled(ctx, service)
+	if err != nil {
+		return err
+	}
+
+	if !enabled {
+		p.log.Info("Enabling disabled service to allow it to be loaded", "service", service)
+		err = p.Enable(ctx, service)
+		if err != nil {
+			return err
+		}
+	}
+
+	return p.launchctl(ctx, "bootstrap", domain, plist)
+}
+
+// Stop unloads the service
+func (p *Provider) Stop(ctx context.Context, service string) error {
+	return p.launchctl(ctx, "bootout", target(service))
+}
+
+// Restart kills and restarts a loaded service, a service that is not loaded is started
+func (p *Provider) Restart(ctx context.Context, service string) error {
+	loaded, _, err := p.isRunning(ctx, service)
+	if err != nil {
+		return err
+	}
+
+	if !loaded {
+		return p.Start(ctx, service)
+	}
+
+	return p.launchctl(ctx, "kickstart", "-k", target(service))
+}
+
+func (p *Provider) Status(ctx context.Context, service string) (*model.ServiceState, error) {
+	loaded, isRunning, err := p.isRunning(ctx, service)
+	if err != nil {
+		return nil, err
+	}
+
+	if !loaded {
+		_, err = plistPath(service)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	isEnabled, err := p.isEnabled(ctx, service)
+	if err != nil {
+		return nil, err
+	}
+
+	ensure := model.ServiceEnsureStopped
+	if isRunning {
+		ensure = model.ServiceEnsureRunning
+	}
+
+	return &model.ServiceState{
+		CommonResourceState: model.NewCommonResourceState(model.ResourceStatusServiceProtocol, model.ServiceTypeName, service, ensure),
+		Metadata: &model.ServiceMetadata{
+			Name:     service,
+			Provider: ProviderName,
+			Enabled:  isEnabled,
+			Running:  isRunning,
+		},
+	}, nil
+}
+
+// isEnabled determines if the service is enabled using the launchd override database, services that are not
+// listed are enabled
+func (p *Provider) isEnabled(ctx context.Context, service string) (bool, error) {
+	stdout, stderr, exitCode, err := p.execute(ctx, "launchctl", "print-disabled", domain)
+	if err != nil {
+		return false, err
+	}
+	if exitCode != 0 {
+		return false, fmt.Errorf("launchctl print-disabled failed with exit code %d: %s", exitCode, bytes.TrimSpace(stderr))
+	}
+
+	label := fmt.Sprintf("%q", service)
+	scanner := bufio.NewScanner(bytes.NewReader(stdout))
+	for scanner.Scan() {
+		name, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=>")
+		if !ok || strings.TrimSpace(name) != label {
+			continue
+		}
+
+		// older releases show true for disabled services
+		switch strings.TrimSpace(value) {
+		case "enabled", "false":
+			return true, nil
+		case "disabled", "true":
+			return false, nil
+		default:
+			return false, fmt.Errorf("invalid launchctl print-disabled output: %s", scanner.Text())
+		}
+	}
+
+	return true, scanner.Err()
+}
+
+// isRunning determines if the service is loaded and running using the state launchd reports for it
+func (p *Provider) isRunning(ctx context.Context, service string) (loaded bool, running bool, err error) {
+	stdout, stderr, exitCode, err := p.execute(ctx, "launchctl", "print", target(service))
+	if err != nil {
+		return false, false, err
+	}
+
+	switch exitCode {
+	case 0:
+	case exitNotFound:
+		return false, false, nil
+	default:
+		return false, false, fmt.Errorf("launchctl print failed with exit code %d: %s", exitCode, bytes.TrimSpace(stderr))
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(stdout))
+	for scanner.Scan() {
+		state, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "state = ")
+		if !ok {
+			continue
+		}
+
+		switch state {
+		case "running":
+			return true, true, nil
+		case "not running", "waiting", "exited", "spawn scheduled", "xpcproxy":
+			return true, false, nil
+		default:
+			return false, false, fmt.Errorf("invalid launchctl print output: state %s", state)
+		}
+	}
+	if scanner.Err() != nil {
+		return false, false, scanner.Err()
+	}
+
+	return false, false, fmt.Errorf("invalid launchctl print output: no state found")
+}
+
+// target is the launchd service target for service
+func target(service string) string {
+	return domain + "/" + service
+}
+
+// plistPath finds the property list of a system daemon
+func plistPath(service string) (string, error) {
+	for _, dir := range plistDirs {
+		path := filepath.Join(dir, service+".plist")
+
+		_, err := os.Stat(path)
+		if err == nil {
+			return path, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+	}
+
+	return "", fmt.Errorf("service %s not found", service)
+}
@@ -0,0 +1,310 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package launchd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/model/modelmocks"
+)
+
+func TestServiceResource(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Resources/Service/Launchd")
+}
+
+const label = "com.example.app"
+
+func fixture(file string) []byte {
+	stdout, err := os.ReadFile(filepath.Join("testdata/launchd", file))
+	Expect(err).ToNot(HaveOccurred())
+	return stdout
+}
+
+var _ = Describe("Launchd Provider", func() {
+	var (
+		mockctl  *gomock.Controller
+		logger   *modelmocks.MockLogger
+		runner   *modelmocks.MockCommandRunner
+		err      error
+		provider *Provider
+		plist    string
+	)
+
+	BeforeEach(func() {
+		mockctl = gomock.NewController(GinkgoT())
+		logger = modelmocks.NewMockLogger(mockctl)
+		runner = modelmocks.NewMockCommandRunner(mockctl)
+
+		logger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+		dir := GinkgoT().TempDir()
+		plist = filepath.Join(dir, label+".plist")
+		Expect(os.WriteFile(plist, []byte("<plist/>"), 0644)).To(Succeed())
+
+		origDirs := plistDirs
+		plistDirs = []string{filepath.Join(dir, "missing"), dir}
+		DeferCleanup(func() { plistDirs = origDirs })
+
+		provider, err = NewLaunchdProvider(logger, runner)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	expectPrint := func(file string, exitCode int) {
+		runner.EXPECT().Execute(gomock.Any(), "launchctl", "print", "system/"+label).Times(1).DoAndReturn(func(ctx context.Context, cmd string, args ...string) ([]byte, []byte, int, error) {
+			if exitCode == exitNotFound {
+				return nil, []byte(fmt.Sprintf("Bad request.\nCould not find service %q in domain for system\n", label)), exitCode, nil
+			}
+			return fixture(file), nil, exitCode, nil
+		})
+	}
+
+	expectPrintDisabled := func(file string) {
+		runner.EXPECT().Execute(gomock.Any(), "launchctl", "print-disabled", "system").Times(1).Return(fixture(file), nil, 0, nil)
+	}
+
+	Describe("Name", func() {
+		It("Should return the provider name", func() {
+			Expect(provider.Name()).To(Equal("launchd"))
+		})
+	})
+
+	Describe("isEnabled", func() {
+		DescribeTable("launchctl print-disabled output parsing",
+			func(fixtureFile string, expectedEnabled bool, expectError bool) {
+				expectPrintDisabled(fixtureFile)
+
+				enabled, err := provider.isEnabled(context.Background(), label)
+				if expectError {
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("invalid launchctl print-disabled output"))
+					Expect(enabled).To(BeFalse())
+				} else {
+					Expect(err).ToNot(HaveOccurred())
+					Expect(enabled).To(Equal(expectedEnabled))
+				}
+			},
+			Entry("enabled", "print-disabled-enabled.txt", true, false),
+			Entry("disabled", "print-disabled-disabled.txt", false, false),
+			Entry("not listed", "print-disabled-unlisted.txt", true, false),
+			Entry("legacy enabled", "print-disabled-legacy-enabled.txt", true, false),
+			Entry("legacy disabled", "print-disabled-legacy-disabled.txt", false, false),
+			Entry("invalid output", "print-disabled-invalid.txt", false, true),
+		)
+
+		It("Should fail when launchctl fails", func() {
+			runner.EXPECT().Execute(gomock.Any(), "launchctl", "print-disabled", "system").Return(nil, []byte("Operation not permitted"), 1, nil)
+
+			_, err := provider.isEnabled(context.Background(), label)
+			Expect(err).To(MatchError(ContainSubstring("launchctl print-disabled failed with exit code 1: Operation not permitted")))
+		})
+	})
+
+	Describe("isRunning", func() {
+		DescribeTable("launchctl print output parsing",
+			func(fixtureFile string, exitCode int, expectedLoaded bool, expectedRunning bool, expectError bool) {
+				expectPrint(fixtureFile, exitCode)
+
+				loaded, running, err := provider.isRunning(context.Background(), label)
+				if expectError {
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("invalid launchctl print output"))
+				} else {
+					Expect(err).ToNot(HaveOccurred())
+				}
+				Expect(loaded).To(Equal(expectedLoaded))
+				Expect(running).To(Equal(expectedRunning))
+			},
+			Entry("running", "print-running.txt", 0, true, true, false),
+			Entry("not running", "print-not-running.txt", 0, true, false, false),
+			Entry("spawn scheduled", "print-spawn-scheduled.txt", 0, true, false, false),
+			Entry("not loaded", "", exitNotFound, false, false, false),
+			Entry("invalid state", "print-invalid.txt", 0, false, false, true),
+		)
+
+		It("Should fail on other launchctl failures", func() {
+			runner.EXPECT().Execute(gomock.Any(), "launchctl", "print", "system/"+label).Return(nil, []byte("Unrecognized target specifier."), 64, nil)
+
+			_, _, err := provider.isRunning(context.Background(), label)
+			Expect(err).To(MatchError(ContainSubstring("launchctl print failed with exit code 64")))
+		})
+	})
+
+	Describe("Status", func() {
+		It("Should report running and enabled service correctly", func() {
+			expectPrint("print-running.txt", 0)
+			expectPrintDisabled("print-disabled-enabled.txt")
+
+			status, err := provider.Status(context.Background(), label)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(status.Ensure).To(Equal(model.ServiceEnsureRunning))
+			Expect(status.Metadata.Name).To(Equal(label))
+			Expect(status.Metadata.Provider).To(Equal("launchd"))
+			Expect(status.Metadata.Running).To(BeTrue())
+			Expect(status.Metadata.Enabled).To(BeTrue())
+		})
+
+		It("Should report an unloaded and disabled service as stopped", func() {
+			expectPrint("", exitNotFound)
+			expectPrintDisabled("print-disabled-disabled.txt")
+
+			status, err := provider.Status(context.Background(), label)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(status.Ensure).To(Equal(model.ServiceEnsureStopped))
+			Expect(status.Metadata.Running).To(BeFalse())
+			Expect(status.Metadata.Enabled).To(BeFalse())
+		})
+
+		It("Should report a loaded service that is not running as stopped", func() {
+			expectPrint("print-not-running.txt", 0)
+			expectPrintDisabled("print-disabled-unlisted.txt")
+
+			status, err := provider.Status(context.Background(), label)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(status.Ensure).To(Equal(model.ServiceEnsureStopped))
+			Expect(status.Metadata.Enabled).To(BeTrue())
+		})
+
+		It("Should fail for unknown services", func() {
+			Expect(os.Remove(plist)).To(Succeed())
+			expectPrint("", exitNotFound)
+
+			_, err := provider.Status(context.Background(), label)
+			Expect(err).To(MatchError("service com.example.app not found"))
+		})
+	})
+
+	Describe("Enable", func() {
+		It("Should call launchctl enable", func() {
+			runner.EXPECT().Execute(gomock.Any(), "launchctl", "enable", "system/"+label).Times(1).Return(nil, nil, 0, nil)
+			Expect(provider.Enable(context.Background(), label)).To(Succeed())
+		})
+
+		It("Should fail when launchctl fails", func() {
+			runner.EXPECT().Execute(gomock.Any(), "launchctl", "enable", "system/"+label).Times(1).Return(nil, []byte("Operation not permitted"), 1, nil)
+			Expect(provider.Enable(context.Background(), label)).To(MatchError(ContainSubstring("launchctl enable failed with exit code 1")))
+		})
+	})
+
+	Describe("Disable", func() {
+		It("Should call launchctl disable", func() {
+			runner.EXPECT().Execute(gomock.Any(), "launchctl", "disable", "system/"+label).Times(1).Return(nil, nil, 0, nil)
+			Expect(provider.Disable(context.Background(), label)).To(Succeed())
+		})
+	})
+
+	Describe("Start", func() {
+		It("Should bootstrap an unloaded service", func() {
+			expectPrint("", exitNotFound)
+			expectPrintDisabled("print-disabled-enabled.txt")
+			runner.EXPECT().Execute(gomock.Any(), "launchctl", "bootstrap", "system", plist).Times(1).Return(nil, nil, 0, nil)
+
+			Expect(provider.Start(context.Background(), label)).To(Succeed())
+		})
+
+		It("Should enable a disabled service before bootstrapping it", func() {
+			expectPrint("", exitNotFound)
+			expectPrintDisabled("print-disabled-disabled.txt")
+			gomock.InOrder(
+				runner.EXPECT().Execute(gomock.Any(), "launchctl", "enable", "system/"+label).Times(1).Return(nil, nil, 0, nil),
+				runner.EXPECT().Execute(gomock.Any(), "launchctl", "bootstrap", "system", plist).Times(1).Return(nil, nil, 0, nil),
+			)
+
+			Expect(provider.Start(context.Background(), label)).To(Succeed())
+		})
+
+		It("Should kickstart a loaded service", func() {
+			expectPrint("print-not-running.txt", 0)
+			runner.EXPECT().Execute(gomock.Any(), "launchctl", "kickstart", "system/"+label).Times(1).Return(nil, nil, 0, nil)
+
+			Expect(provider.Start(context.Background(), label)).To(Succeed())
+		})
+
+		It("Should fail for unknown services", func() {
+			Expect(os.Remove(plist)).To(Succeed())
+			expectPrint("", exitNotFound)
+
+			Expect(provider.Start(context.Background(), label)).To(MatchError("service com.example.app not found"))
+		})
+
+		It("Should fail when bootstrap fails", func() {
+			expectPrint("", exitNotFound)
+			expectPrintDisabled("print-disabled-enabled.txt")
+			runner.EXPECT().Execute(gomock.Any(), "launchctl", "bootstrap", "system", plist).Times(1).Return(nil, []byte("Bootstrap failed: 5: Input/output error"), 5, nil)
+
+			Expect(provider.Start(context.Background(), label)).To(MatchError(ContainSubstring("launchctl bootstrap failed with exit code 5: Bootstrap failed: 5: Input/output error")))
+		})
+	})
+
+	Describe("Stop", func() {
+		It("Should call launchctl bootout", func() {
+			runner.EXPECT().Execute(gomock.Any(), "launchctl", "bootout", "system/"+label).Times(1).Return(nil, nil, 0, nil)
+			Expect(provider.Stop(context.Background(), label)).To(Succeed())
+		})
+
+		It("Should propagate errors from launchctl", func() {
+			runner.EXPECT().Execute(gomock.Any(), "launchctl", "bootout", "system/"+label).Times(1).Return(nil, nil, 0, fmt.Errorf("launchctl failed"))
+			Expect(provider.Stop(context.Background(), label)).To(MatchError("launchctl failed"))
+		})
+	})
+
+	Describe("Restart", func() {
+		It("Should kill and restart a loaded service", func() {
+			expectPrint("print-running.txt", 0)
+			runner.EXPECT().Execute(gomock.Any(), "launchctl", "kickstart", "-k", "system/"+label).Times(1).Return(nil, nil, 0, nil)
+
+			Expect(provider.Restart(context.Background(), label)).To(Succeed())
+		})
+
+		It("Should start a service that is not loaded", func() {
+			expectPrint("", exitNotFound)
+			expectPrint("", exitNotFound)
+			expectPrintDisabled("print-disabled-unlisted.txt")
+			runner.EXPECT().Execute(gomock.Any(), "launchctl", "bootstrap", "system", plist).Times(1).Return(nil, nil, 0, nil)
+
+			Expect(provider.Restart(context.Background(), label)).To(Succeed())
+		})
+	})
+})
+
+var _ = Describe("Launchd Factory", func() {
+	var f *factory
+
+	BeforeEach(func() {
+		f = &factory{}
+		origGoos := goos
+		DeferCleanup(func() { goos = origGoos })
+	})
+
+	It("Should only be manageable on darwin with launchctl", func() {
+		dir := GinkgoT().TempDir()
+		GinkgoT().Setenv("PATH", dir)
+
+		goos = "linux"
+		manageable, _, err := f.IsManageable(nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manageable).To(BeFalse())
+
+		goos = "darwin"
+		manageable, _, err = f.IsManageable(nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manageable).To(BeFalse())
+
+		Expect(os.WriteFile(filepath.Join(dir, "launchctl"), []byte("#!/bin/sh\n"), 0755)).To(Succeed())
+		manageable, priority, err := f.IsManageable(nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manageable).To(BeTrue())
+		Expect(priority).To(Equal(1))
+	})
+})
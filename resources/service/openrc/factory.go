@@ -0,0 +1,45 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package openrc
+
+import (
+	"github.com/choria-io/ccm/internal/registry"
+	iu "github.com/choria-io/ccm/internal/util"
+	"github.com/choria-io/ccm/model"
+)
+
+// Register registers this provider with the registry
+func Register() {
+	registry.MustRegister(&factory{})
+}
+
+// priority is lower than systemd so hosts that have both installed prefer systemd
+const priority = 2
+
+// openrcPath is the OpenRC init binary, a variable so tests can simulate OpenRC hosts
+var openrcPath = "/sbin/openrc"
+
+type factory struct{}
+
+func (p *factory) TypeName() string { return "service" }
+func (p *factory) Name() string     { return ProviderName }
+func (p *factory) New(log model.Logger, runner model.CommandRunner) (model.Provider, error) {
+	return NewOpenRCProvider(log, runner)
+}
+func (p *factory) IsManageable(_ map[string]any, _ model.ResourceProperties) (bool, int, error) {
+	if iu.FileExists(openrcPath) {
+		return true, priority, nil
+	}
+
+	_, found, err := iu.ExecutableInPath("rc-service")
+	if err != nil {
+		return false, 0, err
+	}
+	if !found {
+		return false, 0, nil
+	}
+
+	return true, priority, nil
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package openrc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/choria-io/ccm/model"
+)
+
+const (
+	ProviderName = "openrc"
+
+	// defaultRunlevel is the runlevel services are added to when enabled
+	defaultRunlevel = "default"
+)
+
+type Provider struct {
+	log    model.Logger
+	runner model.CommandRunner
+}
+
+// NewOpenRCProvider creates a new OpenRC service provider
+func NewOpenRCProvider(log model.Logger, runner model.CommandRunner) (*Provider, error) {
+	return &Provider{log: log, runner: runner}, nil
+}
+
+// We ensure that any user of this provider in the same process will not call OpenRC concurrently
+func (p *Provider) execute(ctx context.Context, cmd string, args ...string) (stdout []byte, stderr []byte, exitCode int, err error) {
+	model.ServiceGlobalLock.Lock()
+	defer model.ServiceGlobalLock.Unlock()
+
+	return p.runner.Execute(ctx, cmd, args...)
+}
+
+// run runs cmd and fails when it exits non zero
+func (p *Provider) run(ctx context.Context, cmd string, args ...string) error {
+	stdout, stderr, exitCode, err := p.execute(ctx, cmd, args...)
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		return fmt.Errorf("%s %s failed with exit code %d: %s", cmd, strings.Join(args, " "), exitCode, bytes.TrimSpace(append(stdout, stderr...)))
+	}
+
+	return nil
+}
+
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+// Enable adds the service to the default runlevel
+func (p *Provider) Enable(ctx context.Context, service string) error {
+	return p.run(ctx, "rc-update", "add", service, defaultRunlevel)
+}
+
+// Disable removes the service from all runlevels it is in
+func (p *Provider) Disable(ctx context.Context, service string) error {
+	runlevels, err := p.runlevels(ctx, service)
+	if err != nil {
+		return err
+	}
+
+	if len(runlevels) == 0 {
+		return nil
+	}
+
+	return p.run(ctx, "rc-update", append([]string{"del", service}, runlevels...)...)
+}
+
+func (p *Provider) Start(ctx context.Context, service string) error {
+	return p.run(ctx, "rc-service", service, "start")
+}
+
+func (p *Provider) Stop(ctx context.Context, service string) error {
+	return p.run(ctx, "rc-service", service, "stop")
+}
+
+func (p *Provider) Restart(ctx context.Context, service string) error {
+	return p.run(ctx, "rc-service", service, "restart")
+}
+
+func (p *Provider) Status(ctx context.Context, service string) (*model.ServiceState, error) {
+	isStarted, err := p.isStarted(ctx, service)
+	if err != nil {
+		return nil, err
+	}
+
+	runlevels, err := p.runlevels(ctx, service)
+	if err != nil {
+		return nil, err
+	}
+
+	ensure := model.ServiceEnsureStopped
+	if isStarted {
+		ensure = model.ServiceEnsureRunning
+	}
+
+	return &model.ServiceState{
+		CommonResourceState: model.NewCommonResourceState(model.ResourceStatusServiceProtocol, model.ServiceTypeName, service, ensure),
+		Metadata: &model.ServiceMetadata{
+			Name:     service,
+			Provider: ProviderName,
+			Enabled:  len(runlevels) > 0,
+			Running:  isStarted,
+		},
+	}, nil
+}
+
+// isStarted parses the status reported by rc-service, the exit code differs per status so only the output is used
+func (p *Provider) isStarted(ctx context.Context, service string) (bool, error) {
+	stdout, stderr, _, err := p.execute(ctx, "rc-service", service, "status")
+	if err != nil {
+		return false, err
+	}
+
+	if bytes.Contains(stderr, []byte("does not exist")) {
+		return false, fmt.Errorf("service %s not found", service)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(stdout))
+	for scanner.Scan() {
+		_, status, ok := strings.Cut(scanner.Text(), "status: ")
+		if !ok {
+			continue
+		}
+
+		switch strings.TrimSpace(status) {
+		case "started":
+			return true, nil
+		case "stopped", "crashed", "inactive", "starting", "stopping":
+			return false, nil
+		default:
+			return false, fmt.Errorf("invalid rc-service status output: %s", strings.TrimSpace(scanner.Text()))
+		}
+	}
+	if scanner.Err() != nil {
+		return false, scanner.Err()
+	}
+
+	return false, fmt.Errorf("invalid rc-service status output: %s", bytes.TrimSpace(append(stdout, stderr...)))
+}
+
+// runlevels are the runlevels the service is added to according to rc-update show, empty when the service is not
+// enabled
+func (p *Provider) runlevels(ctx context.Context, service string) ([]string, error) {
+	stdout, stderr, exitCode, err := p.execute(ctx, "rc-update", "show")
+	if err != nil {
+		return nil, err
+	}
+	if exitCode != 0 {
+		return nil, fmt.Errorf("rc-update show failed with exit code %d: %s", exitCode, bytes.TrimSpace(stderr))
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(stdout))
+	for scanner.Scan() {
+		name, levels, ok := strings.Cut(scanner.Text(), "|")
+		if !ok || strings.TrimSpace(name) != service {
+			continue
+		}
+
+		return strings.Fields(levels), nil
+	}
+
+	return nil, scanner.Err()
+}
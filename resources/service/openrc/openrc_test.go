@@ -0,0 +1,228 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package openrc
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/model/modelmocks"
+)
+
+func TestServiceResource(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Resources/Service/OpenRC")
+}
+
+func fixture(file string) []byte {
+	stdout, err := os.ReadFile(filepath.Join("testdata/openrc", file))
+	Expect(err).ToNot(HaveOccurred())
+	return stdout
+}
+
+var _ = Describe("OpenRC Provider", func() {
+	var (
+		mockctl  *gomock.Controller
+		logger   *modelmocks.MockLogger
+		runner   *modelmocks.MockCommandRunner
+		err      error
+		provider *Provider
+	)
+
+	BeforeEach(func() {
+		mockctl = gomock.NewController(GinkgoT())
+		logger = modelmocks.NewMockLogger(mockctl)
+		runner = modelmocks.NewMockCommandRunner(mockctl)
+
+		logger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+		provider, err = NewOpenRCProvider(logger, runner)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	expectStatus := func(file string, exitCode int) {
+		runner.EXPECT().Execute(gomock.Any(), "rc-service", "nginx", "status").Times(1).Return(fixture(file), nil, exitCode, nil)
+	}
+
+	expectShow := func(file string) {
+		runner.EXPECT().Execute(gomock.Any(), "rc-update", "show").Times(1).Return(fixture(file), nil, 0, nil)
+	}
+
+	Describe("Name", func() {
+		It("Should return the provider name", func() {
+			Expect(provider.Name()).To(Equal("openrc"))
+		})
+	})
+
+	Describe("isStarted", func() {
+		DescribeTable("rc-service status output parsing",
+			func(fixtureFile string, exitCode int, expectedStarted bool, expectError bool) {
+				expectStatus(fixtureFile, exitCode)
+
+				started, err := provider.isStarted(context.Background(), "nginx")
+				if expectError {
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("invalid rc-service status output"))
+					Expect(started).To(BeFalse())
+				} else {
+					Expect(err).ToNot(HaveOccurred())
+					Expect(started).To(Equal(expectedStarted))
+				}
+			},
+			Entry("started", "status-started.txt", 0, true, false),
+			Entry("stopped", "status-stopped.txt", 3, false, false),
+			Entry("crashed", "status-crashed.txt", 32, false, false),
+			Entry("inactive", "status-inactive.txt", 16, false, false),
+			Entry("starting", "status-starting.txt", 8, false, false),
+			Entry("invalid output", "status-invalid.txt", 0, false, true),
+		)
+
+		It("Should fail for unknown services", func() {
+			runner.EXPECT().Execute(gomock.Any(), "rc-service", "nginx", "status").Return(nil, []byte(" * rc-service: service `nginx' does not exist\n"), 1, nil)
+
+			_, err := provider.isStarted(context.Background(), "nginx")
+			Expect(err).To(MatchError("service nginx not found"))
+		})
+	})
+
+	Describe("runlevels", func() {
+		DescribeTable("rc-update show output parsing",
+			func(fixtureFile string, service string, expected []string) {
+				expectShow(fixtureFile)
+
+				runlevels, err := provider.runlevels(context.Background(), service)
+				Expect(err).ToNot(HaveOccurred())
+				if expected == nil {
+					Expect(runlevels).To(BeEmpty())
+				} else {
+					Expect(runlevels).To(Equal(expected))
+				}
+			},
+			Entry("enabled", "show-enabled.txt", "nginx", []string{"default"}),
+			Entry("boot runlevel", "show-enabled.txt", "hostname", []string{"boot"}),
+			Entry("multiple runlevels", "show-multiple.txt", "nginx", []string{"boot", "default"}),
+			Entry("not in a runlevel", "show-disabled.txt", "nginx", nil),
+			Entry("not listed", "show-enabled.txt", "httpd", nil),
+		)
+	})
+
+	Describe("Status", func() {
+		It("Should report running and enabled service correctly", func() {
+			expectStatus("status-started.txt", 0)
+			expectShow("show-enabled.txt")
+
+			status, err := provider.Status(context.Background(), "nginx")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(status.Ensure).To(Equal(model.ServiceEnsureRunning))
+			Expect(status.Metadata.Name).To(Equal("nginx"))
+			Expect(status.Metadata.Provider).To(Equal("openrc"))
+			Expect(status.Metadata.Running).To(BeTrue())
+			Expect(status.Metadata.Enabled).To(BeTrue())
+		})
+
+		It("Should report stopped and disabled service correctly", func() {
+			expectStatus("status-stopped.txt", 3)
+			expectShow("show-disabled.txt")
+
+			status, err := provider.Status(context.Background(), "nginx")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(status.Ensure).To(Equal(model.ServiceEnsureStopped))
+			Expect(status.Metadata.Running).To(BeFalse())
+			Expect(status.Metadata.Enabled).To(BeFalse())
+		})
+
+		It("Should report crashed service as stopped", func() {
+			expectStatus("status-crashed.txt", 32)
+			expectShow("show-enabled.txt")
+
+			status, err := provider.Status(context.Background(), "nginx")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(status.Ensure).To(Equal(model.ServiceEnsureStopped))
+			Expect(status.Metadata.Running).To(BeFalse())
+			Expect(status.Metadata.Enabled).To(BeTrue())
+		})
+	})
+
+	Describe("Enable", func() {
+		It("Should add the service to the default runlevel", func() {
+			runner.EXPECT().Execute(gomock.Any(), "rc-update", "add", "nginx", "default").Times(1).Return(nil, nil, 0, nil)
+			Expect(provider.Enable(context.Background(), "nginx")).To(Succeed())
+		})
+
+		It("Should fail when rc-update fails", func() {
+			runner.EXPECT().Execute(gomock.Any(), "rc-update", "add", "nginx", "default").Times(1).Return(nil, []byte(" * rc-update: service `nginx' does not exist"), 1, nil)
+			Expect(provider.Enable(context.Background(), "nginx")).To(MatchError(ContainSubstring("rc-update add nginx default failed with exit code 1")))
+		})
+	})
+
+	Describe("Disable", func() {
+		It("Should remove the service from all its runlevels", func() {
+			expectShow("show-multiple.txt")
+			runner.EXPECT().Execute(gomock.Any(), "rc-update", "del", "nginx", "boot", "default").Times(1).Return(nil, nil, 0, nil)
+			Expect(provider.Disable(context.Background(), "nginx")).To(Succeed())
+		})
+
+		It("Should do nothing when the service is not in a runlevel", func() {
+			expectShow("show-disabled.txt")
+			Expect(provider.Disable(context.Background(), "nginx")).To(Succeed())
+		})
+	})
+
+	DescribeTable("Start, Stop and Restart",
+		func(action string, call func(context.Context, string) error) {
+			runner.EXPECT().Execute(gomock.Any(), "rc-service", "nginx", action).Times(1).Return(nil, nil, 0, nil)
+			Expect(call(context.Background(), "nginx")).To(Succeed())
+
+			runner.EXPECT().Execute(gomock.Any(), "rc-service", "nginx", action).Times(1).Return([]byte(" * ERROR: nginx failed to "+action), nil, 1, nil)
+			Expect(call(context.Background(), "nginx")).To(MatchError(ContainSubstring(fmt.Sprintf("rc-service nginx %s failed with exit code 1: * ERROR: nginx failed to %s", action, action))))
+
+			runner.EXPECT().Execute(gomock.Any(), "rc-service", "nginx", action).Times(1).Return(nil, nil, 0, fmt.Errorf("rc-service failed"))
+			Expect(call(context.Background(), "nginx")).To(MatchError("rc-service failed"))
+		},
+		Entry("start", "start", func(ctx context.Context, s string) error { return provider.Start(ctx, s) }),
+		Entry("stop", "stop", func(ctx context.Context, s string) error { return provider.Stop(ctx, s) }),
+		Entry("restart", "restart", func(ctx context.Context, s string) error { return provider.Restart(ctx, s) }),
+	)
+})
+
+var _ = Describe("OpenRC Factory", func() {
+	var f *factory
+
+	BeforeEach(func() {
+		f = &factory{}
+		origPath := openrcPath
+		DeferCleanup(func() { openrcPath = origPath })
+	})
+
+	It("Should detect OpenRC hosts", func() {
+		dir := GinkgoT().TempDir()
+		GinkgoT().Setenv("PATH", dir)
+		openrcPath = filepath.Join(dir, "openrc")
+
+		manageable, _, err := f.IsManageable(nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manageable).To(BeFalse())
+
+		Expect(os.WriteFile(filepath.Join(dir, "rc-service"), []byte("#!/bin/sh\n"), 0755)).To(Succeed())
+		manageable, priority, err := f.IsManageable(nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manageable).To(BeTrue())
+		Expect(priority).To(Equal(2))
+
+		Expect(os.Remove(filepath.Join(dir, "rc-service"))).To(Succeed())
+		Expect(os.WriteFile(openrcPath, []byte("#!/bin/sh\n"), 0755)).To(Succeed())
+		manageable, _, err = f.IsManageable(nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manageable).To(BeTrue())
+	})
+})
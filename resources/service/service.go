@@ -8,11 +8,17 @@ import (
 	"context"
 
 	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/resources/service/launchd"
+	"github.com/choria-io/ccm/resources/service/openrc"
 	"github.com/choria-io/ccm/resources/service/systemd"
+	"github.com/choria-io/ccm/resources/service/windows"
 )
 
 func init() {
 	systemd.Register()
+	launchd.Register()
+	openrc.Register()
+	windows.Register()
 }
 
 type ServiceProvider interface {
@@ -8,12 +8,15 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
 
+	"github.com/choria-io/ccm/internal/cmdrunner"
 	"github.com/choria-io/ccm/model"
 	"github.com/choria-io/ccm/model/modelmocks"
 )
@@ -378,4 +381,29 @@ var _ = Describe("Systemd Provider", func() {
 			Expect(err).To(HaveOccurred())
 		})
 	})
+
+	Describe("Locale", func() {
+		It("Should run systemctl under the C locale", func() {
+			dir := GinkgoT().TempDir()
+			envFile := filepath.Join(dir, "env")
+			script := fmt.Sprintf("#!/bin/sh\nenv > %s\necho active\n", envFile)
+			Expect(os.WriteFile(filepath.Join(dir, "systemctl"), []byte(script), 0700)).To(Succeed())
+			GinkgoT().Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+			GinkgoT().Setenv("LC_ALL", "de_DE.UTF-8")
+
+			logger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+			cmdRunner, err := cmdrunner.NewCommandRunner(logger)
+			Expect(err).ToNot(HaveOccurred())
+			provider, err = NewSystemdProvider(logger, cmdRunner)
+			Expect(err).ToNot(HaveOccurred())
+
+			active, err := provider.isActive(context.Background(), "nginx")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(active).To(BeTrue())
+
+			env, err := os.ReadFile(envFile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(strings.Split(string(env), "\n")).To(ContainElements("LC_ALL=C", "LANG=C"))
+		})
+	})
 })
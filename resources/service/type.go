@@ -7,6 +7,7 @@ package serviceresource
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sync"
 
 	"github.com/choria-io/ccm/internal/registry"
@@ -26,6 +27,8 @@ type Type struct {
 }
 
 var _ model.Resource = (*Type)(nil)
+var _ base.ProviderFallback = (*Type)(nil)
+var _ base.StatusReporter = (*Type)(nil)
 
 // New creates a new service resource with the given properties
 func New(ctx context.Context, mgr model.Manager, properties model.ServiceResourceProperties) (*Type, error) {
@@ -113,6 +116,18 @@ func (t *Type) ApplyResource(ctx context.Context) (model.ResourceState, error) {
 		}
 	}
 
+	if shouldRefreshViaSubscribe && properties.RestartLimit > 0 {
+		failures, err := t.consecutiveFailures()
+		if err != nil {
+			return nil, err
+		}
+
+		if failures >= properties.RestartLimit {
+			t.UserLogger.Warn("Suppressing restart of flapping service", "subscribe", refreshResource, "failures", failures, "limit", properties.RestartLimit)
+			return nil, fmt.Errorf("%w: %d consecutive failures", model.ErrRestartSuppressed, failures)
+		}
+	}
+
 	switch {
 	case shouldRefreshViaSubscribe:
 		t.log.Info("Refreshing via subscribe", "subscribe", refreshResource)
@@ -223,10 +238,35 @@ func (t *Type) ApplyResource(ctx context.Context) (model.ResourceState, error) {
 		changed = true
 	}
 	t.FinalizeState(finalStatus, noop, noopMessage, changed, !refreshState, shouldRefreshViaSubscribe)
+	// services always exist when they can be managed so any change to them corrects drift
+	t.ClassifyChange(finalStatus, true)
 
 	return finalStatus, nil
 }
 
+// consecutiveFailures counts the failed events recorded in the session for this service since it was last
+// applied successfully, skipped events are ignored
+func (t *Type) consecutiveFailures() (int, error) {
+	events, err := t.mgr.ResourceEvents(model.ServiceTypeName, t.prop.Name)
+	if err != nil {
+		return 0, err
+	}
+
+	var failures int
+	for _, event := range slices.Backward(events) {
+		switch {
+		case event.Failed:
+			failures++
+		case event.Skipped:
+			continue
+		default:
+			return failures, nil
+		}
+	}
+
+	return failures, nil
+}
+
 // isDesiredState reports whether state matches properties. The second return is
 // a human-readable reason describing the mismatch when stable is false, suitable
 // for inclusion in error messages.
@@ -260,6 +300,16 @@ func (t *Type) Info(ctx context.Context) (any, error) {
 	return t.provider.(ServiceProvider).Status(ctx, t.prop.Name)
 }
 
+// CurrentState reports the current state of the resource without making any changes
+func (t *Type) CurrentState(ctx context.Context) (model.ResourceState, error) {
+	state, err := t.provider.(ServiceProvider).Status(ctx, t.prop.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
 func (t *Type) providerUnlocked() string {
 	if t.provider == nil {
 		return ""
@@ -286,7 +336,7 @@ func (t *Type) selectProviderUnlocked() error {
 		return err
 	}
 
-	selected, err := registry.FindSuitableProvider(model.ServiceTypeName, t.prop.Provider, t.Facts, t.prop, t.log, runner)
+	selected, err := registry.FindSuitableProvider(model.ServiceTypeName, t.prop.Provider, t.Facts, t.prop, t.log, runner, t.mgr)
 	if err != nil {
 		return err
 	}
@@ -301,6 +351,27 @@ func (t *Type) selectProviderUnlocked() error {
 	return nil
 }
 
+// SelectAlternateProvider replaces the selected provider with the most suitable provider not listed in exclude
+func (t *Type) SelectAlternateProvider(exclude []string) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	runner, err := t.mgr.NewRunner()
+	if err != nil {
+		return "", err
+	}
+
+	selected, err := registry.FindAlternateProvider(model.ServiceTypeName, exclude, t.Facts, t.prop, t.log, runner, t.mgr)
+	if err != nil {
+		return "", err
+	}
+
+	t.log.Debug("Selected alternate provider", "provider", selected.Name())
+	t.provider = selected
+
+	return t.providerUnlocked(), nil
+}
+
 func (t *Type) SelectProvider() (string, error) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
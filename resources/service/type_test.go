@@ -182,6 +182,59 @@ var _ = Describe("Service Type", func() {
 				Expect(event.Errors).To(ContainElement("status failed"))
 			})
 
+			Context("when the provider executable is missing", func() {
+				missing := fmt.Errorf("%w: systemctl", model.ErrExecutableNotFound)
+
+				It("Should report a friendly error when the provider was requested", func(ctx context.Context) {
+					provider.EXPECT().Status(gomock.Any(), "nginx").Return(nil, missing)
+
+					event, err := svc.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(event.Failed).To(BeTrue())
+					Expect(event.Errors).To(Equal([]string{"service#nginx: provider is not manageable: mock provider could not run: executable not found: systemctl"}))
+				})
+
+				It("Should fall back to an alternate provider", func(ctx context.Context) {
+					alternate := NewMockServiceProvider(mockctl)
+					alternate.EXPECT().Name().Return("alternate").AnyTimes()
+
+					primaryFactory := modelmocks.NewMockProviderFactory(mockctl)
+					primaryFactory.EXPECT().Name().Return("mock").AnyTimes()
+					primaryFactory.EXPECT().TypeName().Return(model.ServiceTypeName).AnyTimes()
+					primaryFactory.EXPECT().IsManageable(facts, gomock.Any()).Return(true, 1, nil).AnyTimes()
+					primaryFactory.EXPECT().New(gomock.Any(), gomock.Any()).Return(provider, nil).AnyTimes()
+
+					alternateFactory := modelmocks.NewMockProviderFactory(mockctl)
+					alternateFactory.EXPECT().Name().Return("alternate").AnyTimes()
+					alternateFactory.EXPECT().TypeName().Return(model.ServiceTypeName).AnyTimes()
+					alternateFactory.EXPECT().IsManageable(facts, gomock.Any()).Return(true, 5, nil).AnyTimes()
+					alternateFactory.EXPECT().New(gomock.Any(), gomock.Any()).Return(alternate, nil).AnyTimes()
+
+					registry.Clear()
+					registry.MustRegister(primaryFactory)
+					registry.MustRegister(alternateFactory)
+
+					properties.Provider = ""
+					svc, err = New(ctx, mgr, *properties)
+					Expect(err).ToNot(HaveOccurred())
+
+					running := &model.ServiceState{
+						CommonResourceState: model.CommonResourceState{Ensure: model.ServiceEnsureRunning},
+						Metadata:            &model.ServiceMetadata{Running: true},
+					}
+
+					provider.EXPECT().Status(gomock.Any(), "nginx").Return(nil, missing)
+					alternate.EXPECT().Status(gomock.Any(), "nginx").Return(running, nil)
+
+					event, err := svc.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(event.Errors).To(BeEmpty())
+					Expect(event.Failed).To(BeFalse())
+					Expect(event.Provider).To(Equal("alternate"))
+					Expect(svc.Provider()).To(Equal("alternate"))
+				})
+			})
+
 			Context("when ensure is running", func() {
 				BeforeEach(func() {
 					svc.prop.Ensure = model.ServiceEnsureRunning
@@ -449,6 +502,45 @@ var _ = Describe("Service Type", func() {
 					Expect(event.Errors).To(ContainElement("restart failed"))
 				})
 
+				It("Should restart when notified by a changed resource", func(ctx context.Context) {
+					file := &model.FileResourceProperties{
+						CommonResourceProperties: model.CommonResourceProperties{
+							Type:   model.FileTypeName,
+							Name:   "/etc/nginx/nginx.conf",
+							Ensure: model.EnsurePresent,
+							Notify: []string{"service#nginx"},
+						},
+					}
+					notified := &model.ServiceResourceProperties{
+						CommonResourceProperties: model.CommonResourceProperties{
+							Type:   model.ServiceTypeName,
+							Name:   "nginx",
+							Ensure: model.ServiceEnsureRunning,
+						},
+					}
+
+					err := model.ResolveNotifications([]map[string]model.ResourceProperties{{model.FileTypeName: file}, {model.ServiceTypeName: notified}})
+					Expect(err).ToNot(HaveOccurred())
+
+					svc, err = New(ctx, mgr, *notified)
+					Expect(err).ToNot(HaveOccurred())
+
+					state := &model.ServiceState{
+						CommonResourceState: model.CommonResourceState{Name: "nginx", Ensure: model.ServiceEnsureRunning},
+						Metadata:            &model.ServiceMetadata{Name: "nginx", Running: true},
+					}
+
+					mgr.EXPECT().ShouldRefresh("file", "/etc/nginx/nginx.conf").Return(true, nil)
+					provider.EXPECT().Status(gomock.Any(), "nginx").Return(state, nil)
+					provider.EXPECT().Restart(gomock.Any(), "nginx").Return(nil)
+					provider.EXPECT().Status(gomock.Any(), "nginx").Return(state, nil)
+
+					result, err := svc.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.Changed).To(BeTrue())
+					Expect(result.Refreshed).To(BeTrue())
+				})
+
 				It("Should fail if ShouldRefresh fails", func(ctx context.Context) {
 					state := &model.ServiceState{
 						CommonResourceState: model.CommonResourceState{Name: "nginx", Ensure: model.ServiceEnsureRunning},
@@ -464,6 +556,69 @@ var _ = Describe("Service Type", func() {
 				})
 			})
 
+			Context("when a restart limit is set", func() {
+				var (
+					state  *model.ServiceState
+					events []model.TransactionEvent
+				)
+
+				BeforeEach(func(ctx context.Context) {
+					properties.Subscribe = []string{"package#nginx"}
+					properties.RestartLimit = 2
+					svc, err = New(ctx, mgr, *properties)
+					Expect(err).ToNot(HaveOccurred())
+
+					state = &model.ServiceState{
+						CommonResourceState: model.CommonResourceState{Name: "nginx", Ensure: model.ServiceEnsureRunning},
+						Metadata:            &model.ServiceMetadata{Name: "nginx", Running: true},
+					}
+					events = nil
+
+					logger.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+					mgr.EXPECT().ShouldRefresh("package", "nginx").Return(true, nil).AnyTimes()
+					mgr.EXPECT().ResourceEvents(model.ServiceTypeName, "nginx").DoAndReturn(func(_ string, _ string) ([]model.TransactionEvent, error) {
+						return events, nil
+					}).AnyTimes()
+					provider.EXPECT().Status(gomock.Any(), "nginx").Return(state, nil).AnyTimes()
+				})
+
+				It("Should stop restarting after the limit of failed restarts", func(ctx context.Context) {
+					provider.EXPECT().Restart(gomock.Any(), "nginx").Return(fmt.Errorf("restart failed")).Times(2)
+
+					for range 2 {
+						event, err := svc.Apply(ctx)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(event.Errors).To(ContainElement("restart failed"))
+						events = append(events, *event)
+					}
+
+					for range 2 {
+						event, err := svc.Apply(ctx)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(event.Failed).To(BeTrue())
+						Expect(event.Errors).To(ContainElement(HavePrefix("restart suppressed (flapping)")))
+						events = append(events, *event)
+					}
+				})
+
+				It("Should reset the count after a successful apply", func(ctx context.Context) {
+					events = []model.TransactionEvent{
+						{Failed: true},
+						{Failed: true},
+						{Changed: true},
+						{Skipped: true},
+						{Failed: true},
+					}
+
+					provider.EXPECT().Restart(gomock.Any(), "nginx").Return(nil)
+
+					event, err := svc.Apply(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(event.Failed).To(BeFalse())
+					Expect(event.Refreshed).To(BeTrue())
+				})
+			})
+
 			Context("when multiple subscriptions are set", func() {
 				BeforeEach(func(ctx context.Context) {
 					properties.Subscribe = []string{"package#nginx", "file#/etc/nginx/nginx.conf"}
@@ -568,7 +723,7 @@ var _ = Describe("Service Type", func() {
 					}
 
 					provider.EXPECT().Status(gomock.Any(), "nginx").Return(state, nil)
-					runner.EXPECT().Execute(gomock.Any(), "/usr/lib/nagios/plugins/check_http", "-H", "localhost").
+					runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/lib/nagios/plugins/check_http", Args: []string{"-H", "localhost"}}).
 						Return([]byte("HTTP OK"), []byte{}, 0, nil)
 
 					result, err := svc.Apply(ctx)
@@ -587,7 +742,7 @@ var _ = Describe("Service Type", func() {
 					}
 
 					provider.EXPECT().Status(gomock.Any(), "nginx").Return(state, nil)
-					runner.EXPECT().Execute(gomock.Any(), "/usr/lib/nagios/plugins/check_http", "-H", "localhost").
+					runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/lib/nagios/plugins/check_http", Args: []string{"-H", "localhost"}}).
 						Return([]byte("HTTP WARNING - slow response"), []byte{}, 1, nil)
 
 					result, err := svc.Apply(ctx)
@@ -608,7 +763,7 @@ var _ = Describe("Service Type", func() {
 					}
 
 					provider.EXPECT().Status(gomock.Any(), "nginx").Return(state, nil)
-					runner.EXPECT().Execute(gomock.Any(), "/usr/lib/nagios/plugins/check_http", "-H", "localhost").
+					runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/lib/nagios/plugins/check_http", Args: []string{"-H", "localhost"}}).
 						Return([]byte("HTTP CRITICAL - connection refused"), []byte{}, 2, nil)
 
 					result, err := svc.Apply(ctx)
@@ -629,7 +784,7 @@ var _ = Describe("Service Type", func() {
 					}
 
 					provider.EXPECT().Status(gomock.Any(), "nginx").Return(state, nil)
-					runner.EXPECT().Execute(gomock.Any(), "/usr/lib/nagios/plugins/check_http", "-H", "localhost").
+					runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/lib/nagios/plugins/check_http", Args: []string{"-H", "localhost"}}).
 						Return(nil, nil, 0, fmt.Errorf("command not found"))
 
 					result, err := svc.Apply(ctx)
@@ -656,6 +811,53 @@ var _ = Describe("Service Type", func() {
 			})
 		})
 
+		Describe("Healthcheck", func() {
+			var stopped *model.ServiceState
+
+			BeforeEach(func() {
+				factory.EXPECT().IsManageable(facts, gomock.Any()).Return(true, 1, nil).AnyTimes()
+				svc.prop.Ensure = model.ServiceEnsureRunning
+				svc.prop.Enable = boolPtr(true)
+				svc.prop.HealthChecks = []model.CommonHealthCheck{{
+					Command: "/usr/lib/nagios/plugins/check_http -H localhost",
+				}}
+				stopped = &model.ServiceState{
+					CommonResourceState: model.CommonResourceState{Name: "nginx", Ensure: model.ServiceEnsureStopped},
+					Metadata:            &model.ServiceMetadata{Name: "nginx", Running: false, Enabled: false},
+				}
+			})
+
+			It("Should record critical results without starting or enabling the service", func(ctx context.Context) {
+				// no Start or Enable expectations, the mock fails the test when they are called
+				provider.EXPECT().Status(gomock.Any(), "nginx").Return(stopped, nil)
+				runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/lib/nagios/plugins/check_http", Args: []string{"-H", "localhost"}}).
+					Return([]byte("HTTP CRITICAL - connection refused"), []byte{}, 2, nil)
+
+				result, err := svc.Healthcheck(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.HealthCheckOnly).To(BeTrue())
+				Expect(result.Changed).To(BeFalse())
+				Expect(result.Failed).To(BeTrue())
+				Expect(result.FinalEnsure).To(Equal(model.ServiceEnsureStopped))
+				Expect(result.HealthChecks).To(HaveLen(1))
+				Expect(result.HealthChecks[0].Status).To(Equal(model.HealthCheckCritical))
+			})
+
+			It("Should record passing results without changing the service", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), "nginx").Return(stopped, nil)
+				runner.EXPECT().ExecuteWithOptions(gomock.Any(), model.ExtendedExecOptions{Command: "/usr/lib/nagios/plugins/check_http", Args: []string{"-H", "localhost"}}).
+					Return([]byte("HTTP OK"), []byte{}, 0, nil)
+
+				result, err := svc.Healthcheck(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.HealthCheckOnly).To(BeTrue())
+				Expect(result.Changed).To(BeFalse())
+				Expect(result.Failed).To(BeFalse())
+				Expect(result.HealthChecks).To(HaveLen(1))
+				Expect(result.HealthChecks[0].Status).To(Equal(model.HealthCheckOK))
+			})
+		})
+
 		Describe("Apply in noop mode", func() {
 			var noopMgr *modelmocks.MockManager
 			var noopSvc *Type
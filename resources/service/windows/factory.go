@@ -0,0 +1,31 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package windows
+
+import (
+	"runtime"
+
+	"github.com/choria-io/ccm/internal/registry"
+	"github.com/choria-io/ccm/model"
+)
+
+// Register registers this provider with the registry
+func Register() {
+	registry.MustRegister(&factory{})
+}
+
+// goos is the operating system the service manager is used on, a variable so tests can simulate Windows
+var goos = runtime.GOOS
+
+type factory struct{}
+
+func (p *factory) TypeName() string { return "service" }
+func (p *factory) Name() string     { return ProviderName }
+func (p *factory) New(log model.Logger, runner model.CommandRunner) (model.Provider, error) {
+	return NewWindowsProvider(log, runner)
+}
+func (p *factory) IsManageable(_ map[string]any, _ model.ResourceProperties) (bool, int, error) {
+	return goos == "windows", 1, nil
+}
@@ -0,0 +1,224 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package windows
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/choria-io/ccm/model"
+)
+
+const (
+	ProviderName = "windows"
+
+	// scCommand is the service control manager command line
+	scCommand = "sc.exe"
+
+	// exitServiceDoesNotExist is the error sc.exe exits with for unknown services
+	exitServiceDoesNotExist = 1060
+)
+
+var (
+	// pollInterval is how often the service state is checked while waiting for it to start or stop
+	pollInterval = time.Second
+
+	// stateTimeout is how long to wait for a service to start or stop
+	stateTimeout = time.Minute
+)
+
+type Provider struct {
+	log    model.Logger
+	runner model.CommandRunner
+}
+
+// NewWindowsProvider creates a new Windows service provider
+func NewWindowsProvider(log model.Logger, runner model.CommandRunner) (*Provider, error) {
+	return &Provider{log: log, runner: runner}, nil
+}
+
+// We ensure that any user of this provider in the same process will not call the service manager concurrently
+func (p *Provider) execute(ctx context.Context, args ...string) (stdout []byte, stderr []byte, exitCode int, err error) {
+	model.ServiceGlobalLock.Lock()
+	defer model.ServiceGlobalLock.Unlock()
+
+	return p.runner.Execute(ctx, scCommand, args...)
+}
+
+// sc runs sc.exe and fails when it exits non zero
+func (p *Provider) sc(ctx context.Context, service string, args ...string) ([]byte, error) {
+	stdout, stderr, exitCode, err := p.execute(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	switch exitCode {
+	case 0:
+		return stdout, nil
+	case exitServiceDoesNotExist:
+		return nil, fmt.Errorf("service %s not found", service)
+	default:
+		return nil, fmt.Errorf("sc %s failed with exit code %d: %s", args[0], exitCode, bytes.TrimSpace(append(stdout, stderr...)))
+	}
+}
+
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+// Enable sets the service to start automatically at boot
+func (p *Provider) Enable(ctx context.Context, service string) error {
+	_, err := p.sc(ctx, service, "config", service, "start=", "auto")
+	return err
+}
+
+// Disable sets the service to only start on demand
+func (p *Provider) Disable(ctx context.Context, service string) error {
+	_, err := p.sc(ctx, service, "config", service, "start=", "demand")
+	return err
+}
+
+// Start starts the service and waits for it to be running
+func (p *Provider) Start(ctx context.Context, service string) error {
+	_, err := p.sc(ctx, service, "start", service)
+	if err != nil {
+		return err
+	}
+
+	return p.waitForState(ctx, service, "RUNNING")
+}
+
+// Stop stops the service and waits for it to be stopped
+func (p *Provider) Stop(ctx context.Context, service string) error {
+	_, err := p.sc(ctx, service, "stop", service)
+	if err != nil {
+		return err
+	}
+
+	return p.waitForState(ctx, service, "STOPPED")
+}
+
+// Restart stops and starts the service, sc.exe has no restart command
+func (p *Provider) Restart(ctx context.Context, service string) error {
+	err := p.Stop(ctx, service)
+	if err != nil {
+		return err
+	}
+
+	return p.Start(ctx, service)
+}
+
+func (p *Provider) Status(ctx context.Context, service string) (*model.ServiceState, error) {
+	state, err := p.state(ctx, service)
+	if err != nil {
+		return nil, err
+	}
+
+	isEnabled, err := p.isEnabled(ctx, service)
+	if err != nil {
+		return nil, err
+	}
+
+	isRunning := state == "RUNNING"
+
+	ensure := model.ServiceEnsureStopped
+	if isRunning {
+		ensure = model.ServiceEnsureRunning
+	}
+
+	return &model.ServiceState{
+		CommonResourceState: model.NewCommonResourceState(model.ResourceStatusServiceProtocol, model.ServiceTypeName, service, ensure),
+		Metadata: &model.ServiceMetadata{
+			Name:     service,
+			Provider: ProviderName,
+			Enabled:  isEnabled,
+			Running:  isRunning,
+		},
+	}, nil
+}
+
+// waitForState polls the service until it reaches state, sc.exe returns once the service manager accepted the request
+func (p *Provider) waitForState(ctx context.Context, service string, state string) error {
+	timeout, cancel := context.WithTimeout(ctx, stateTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		current, err := p.state(ctx, service)
+		if err != nil {
+			return err
+		}
+		if current == state {
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-timeout.Done():
+			return fmt.Errorf("service %s did not reach state %s, last state %s: %w", service, state, current, timeout.Err())
+		}
+	}
+}
+
+// state is the current state reported by sc query, for example RUNNING or STOP_PENDING
+func (p *Provider) state(ctx context.Context, service string) (string, error) {
+	stdout, err := p.sc(ctx, service, "query", service)
+	if err != nil {
+		return "", err
+	}
+
+	fields := field(stdout, "STATE")
+	if len(fields) < 2 {
+		return "", fmt.Errorf("invalid sc query output: %s", bytes.TrimSpace(stdout))
+	}
+
+	switch fields[1] {
+	case "STOPPED", "START_PENDING", "STOP_PENDING", "RUNNING", "CONTINUE_PENDING", "PAUSE_PENDING", "PAUSED":
+		return fields[1], nil
+	default:
+		return "", fmt.Errorf("invalid sc query output: unknown state %s", fields[1])
+	}
+}
+
+// isEnabled determines if the service starts at boot using the START_TYPE reported by sc qc
+func (p *Provider) isEnabled(ctx context.Context, service string) (bool, error) {
+	stdout, err := p.sc(ctx, service, "qc", service)
+	if err != nil {
+		return false, err
+	}
+
+	fields := field(stdout, "START_TYPE")
+	if len(fields) < 2 {
+		return false, fmt.Errorf("invalid sc qc output: %s", bytes.TrimSpace(stdout))
+	}
+
+	switch fields[1] {
+	case "AUTO_START", "BOOT_START", "SYSTEM_START":
+		return true, nil
+	case "DEMAND_START", "DISABLED":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid sc qc output: unknown start type %s", fields[1])
+	}
+}
+
+// field finds a "NAME : value" line in sc.exe output and returns the fields of its value
+func field(output []byte, name string) []string {
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if ok && strings.TrimSpace(key) == name {
+			return strings.Fields(value)
+		}
+	}
+
+	return nil
+}
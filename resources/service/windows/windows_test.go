@@ -0,0 +1,260 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package windows
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/model/modelmocks"
+)
+
+func TestServiceResource(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Resources/Service/Windows")
+}
+
+func fixture(file string) []byte {
+	stdout, err := os.ReadFile(filepath.Join("testdata", "sc", file))
+	Expect(err).ToNot(HaveOccurred())
+	return stdout
+}
+
+var _ = Describe("Windows Provider", func() {
+	var (
+		mockctl  *gomock.Controller
+		logger   *modelmocks.MockLogger
+		runner   *modelmocks.MockCommandRunner
+		err      error
+		provider *Provider
+	)
+
+	BeforeEach(func() {
+		mockctl = gomock.NewController(GinkgoT())
+		logger = modelmocks.NewMockLogger(mockctl)
+		runner = modelmocks.NewMockCommandRunner(mockctl)
+
+		logger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+		origInterval, origTimeout := pollInterval, stateTimeout
+		pollInterval = time.Millisecond
+		stateTimeout = 100 * time.Millisecond
+		DeferCleanup(func() { pollInterval, stateTimeout = origInterval, origTimeout })
+
+		provider, err = NewWindowsProvider(logger, runner)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	expectQuery := func(file string) *gomock.Call {
+		return runner.EXPECT().Execute(gomock.Any(), "sc.exe", "query", "W3SVC").Times(1).Return(fixture(file), nil, 0, nil)
+	}
+
+	expectQc := func(file string) *gomock.Call {
+		return runner.EXPECT().Execute(gomock.Any(), "sc.exe", "qc", "W3SVC").Times(1).Return(fixture(file), nil, 0, nil)
+	}
+
+	Describe("Name", func() {
+		It("Should return the provider name", func() {
+			Expect(provider.Name()).To(Equal("windows"))
+		})
+	})
+
+	Describe("isEnabled", func() {
+		DescribeTable("sc qc output parsing",
+			func(fixtureFile string, expectedEnabled bool, expectError bool) {
+				expectQc(fixtureFile)
+
+				enabled, err := provider.isEnabled(context.Background(), "W3SVC")
+				if expectError {
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("invalid sc qc output"))
+					Expect(enabled).To(BeFalse())
+				} else {
+					Expect(err).ToNot(HaveOccurred())
+					Expect(enabled).To(Equal(expectedEnabled))
+				}
+			},
+			Entry("auto", "qc-auto.txt", true, false),
+			Entry("delayed auto", "qc-auto-delayed.txt", true, false),
+			Entry("boot", "qc-boot.txt", true, false),
+			Entry("system", "qc-system.txt", true, false),
+			Entry("demand", "qc-demand.txt", false, false),
+			Entry("disabled", "qc-disabled.txt", false, false),
+			Entry("invalid output", "qc-invalid.txt", false, true),
+		)
+	})
+
+	Describe("state", func() {
+		DescribeTable("sc query output parsing",
+			func(fixtureFile string, expectedState string, expectError bool) {
+				expectQuery(fixtureFile)
+
+				state, err := provider.state(context.Background(), "W3SVC")
+				if expectError {
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("invalid sc query output"))
+				} else {
+					Expect(err).ToNot(HaveOccurred())
+					Expect(state).To(Equal(expectedState))
+				}
+			},
+			Entry("running", "query-running.txt", "RUNNING", false),
+			Entry("stopped", "query-stopped.txt", "STOPPED", false),
+			Entry("start pending", "query-start-pending.txt", "START_PENDING", false),
+			Entry("stop pending", "query-stop-pending.txt", "STOP_PENDING", false),
+			Entry("paused", "query-paused.txt", "PAUSED", false),
+			Entry("invalid output", "query-invalid.txt", "", true),
+		)
+
+		It("Should fail for unknown services", func() {
+			runner.EXPECT().Execute(gomock.Any(), "sc.exe", "query", "W3SVC").Return([]byte("[SC] EnumQueryServicesStatus:OpenService FAILED 1060:\r\n\r\nThe specified service does not exist as an installed service.\r\n"), nil, 1060, nil)
+
+			_, err := provider.state(context.Background(), "W3SVC")
+			Expect(err).To(MatchError("service W3SVC not found"))
+		})
+	})
+
+	Describe("Status", func() {
+		It("Should report running and enabled service correctly", func() {
+			expectQuery("query-running.txt")
+			expectQc("qc-auto.txt")
+
+			status, err := provider.Status(context.Background(), "W3SVC")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(status.Ensure).To(Equal(model.ServiceEnsureRunning))
+			Expect(status.Metadata.Name).To(Equal("W3SVC"))
+			Expect(status.Metadata.Provider).To(Equal("windows"))
+			Expect(status.Metadata.Running).To(BeTrue())
+			Expect(status.Metadata.Enabled).To(BeTrue())
+		})
+
+		It("Should report stopped and disabled service correctly", func() {
+			expectQuery("query-stopped.txt")
+			expectQc("qc-demand.txt")
+
+			status, err := provider.Status(context.Background(), "W3SVC")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(status.Ensure).To(Equal(model.ServiceEnsureStopped))
+			Expect(status.Metadata.Running).To(BeFalse())
+			Expect(status.Metadata.Enabled).To(BeFalse())
+		})
+
+		It("Should report pending services as stopped", func() {
+			expectQuery("query-start-pending.txt")
+			expectQc("qc-auto.txt")
+
+			status, err := provider.Status(context.Background(), "W3SVC")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(status.Ensure).To(Equal(model.ServiceEnsureStopped))
+		})
+	})
+
+	Describe("Enable", func() {
+		It("Should set the start type to auto", func() {
+			runner.EXPECT().Execute(gomock.Any(), "sc.exe", "config", "W3SVC", "start=", "auto").Times(1).Return([]byte("[SC] ChangeServiceConfig SUCCESS"), nil, 0, nil)
+			Expect(provider.Enable(context.Background(), "W3SVC")).To(Succeed())
+		})
+
+		It("Should fail when sc fails", func() {
+			runner.EXPECT().Execute(gomock.Any(), "sc.exe", "config", "W3SVC", "start=", "auto").Times(1).Return([]byte("[SC] OpenService FAILED 5:\r\n\r\nAccess is denied.\r\n"), nil, 5, nil)
+			Expect(provider.Enable(context.Background(), "W3SVC")).To(MatchError(ContainSubstring("sc config failed with exit code 5: [SC] OpenService FAILED 5")))
+		})
+	})
+
+	Describe("Disable", func() {
+		It("Should set the start type to demand", func() {
+			runner.EXPECT().Execute(gomock.Any(), "sc.exe", "config", "W3SVC", "start=", "demand").Times(1).Return([]byte("[SC] ChangeServiceConfig SUCCESS"), nil, 0, nil)
+			Expect(provider.Disable(context.Background(), "W3SVC")).To(Succeed())
+		})
+	})
+
+	Describe("Start", func() {
+		It("Should start the service and wait for it to run", func() {
+			gomock.InOrder(
+				runner.EXPECT().Execute(gomock.Any(), "sc.exe", "start", "W3SVC").Times(1).Return(fixture("query-start-pending.txt"), nil, 0, nil),
+				expectQuery("query-start-pending.txt"),
+				expectQuery("query-running.txt"),
+			)
+
+			Expect(provider.Start(context.Background(), "W3SVC")).To(Succeed())
+		})
+
+		It("Should fail when the service does not start in time", func() {
+			runner.EXPECT().Execute(gomock.Any(), "sc.exe", "start", "W3SVC").Times(1).Return(nil, nil, 0, nil)
+			runner.EXPECT().Execute(gomock.Any(), "sc.exe", "query", "W3SVC").MinTimes(1).Return(fixture("query-start-pending.txt"), nil, 0, nil)
+
+			err := provider.Start(context.Background(), "W3SVC")
+			Expect(err).To(MatchError(ContainSubstring("service W3SVC did not reach state RUNNING, last state START_PENDING")))
+			Expect(err).To(MatchError(context.DeadlineExceeded))
+		})
+
+		It("Should fail for unknown services", func() {
+			runner.EXPECT().Execute(gomock.Any(), "sc.exe", "start", "W3SVC").Times(1).Return([]byte("[SC] StartService: OpenService FAILED 1060"), nil, 1060, nil)
+			Expect(provider.Start(context.Background(), "W3SVC")).To(MatchError("service W3SVC not found"))
+		})
+	})
+
+	Describe("Stop", func() {
+		It("Should stop the service and wait for it to stop", func() {
+			gomock.InOrder(
+				runner.EXPECT().Execute(gomock.Any(), "sc.exe", "stop", "W3SVC").Times(1).Return(fixture("query-stop-pending.txt"), nil, 0, nil),
+				expectQuery("query-stopped.txt"),
+			)
+
+			Expect(provider.Stop(context.Background(), "W3SVC")).To(Succeed())
+		})
+
+		It("Should propagate errors from sc", func() {
+			runner.EXPECT().Execute(gomock.Any(), "sc.exe", "stop", "W3SVC").Times(1).Return(nil, nil, 0, fmt.Errorf("sc failed"))
+			Expect(provider.Stop(context.Background(), "W3SVC")).To(MatchError("sc failed"))
+		})
+	})
+
+	Describe("Restart", func() {
+		It("Should stop and start the service", func() {
+			gomock.InOrder(
+				runner.EXPECT().Execute(gomock.Any(), "sc.exe", "stop", "W3SVC").Times(1).Return(nil, nil, 0, nil),
+				expectQuery("query-stop-pending.txt"),
+				expectQuery("query-stopped.txt"),
+				runner.EXPECT().Execute(gomock.Any(), "sc.exe", "start", "W3SVC").Times(1).Return(nil, nil, 0, nil),
+				expectQuery("query-running.txt"),
+			)
+
+			Expect(provider.Restart(context.Background(), "W3SVC")).To(Succeed())
+		})
+	})
+})
+
+var _ = Describe("Windows Factory", func() {
+	var f *factory
+
+	BeforeEach(func() {
+		f = &factory{}
+		origGoos := goos
+		DeferCleanup(func() { goos = origGoos })
+	})
+
+	It("Should only be manageable on windows", func() {
+		goos = "linux"
+		manageable, _, err := f.IsManageable(nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manageable).To(BeFalse())
+
+		goos = "windows"
+		manageable, priority, err := f.IsManageable(nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manageable).To(BeTrue())
+		Expect(priority).To(Equal(1))
+	})
+})
@@ -0,0 +1,336 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package posix
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/choria-io/ccm/model"
+)
+
+const ProviderName = "posix"
+
+type Provider struct {
+	log        model.Logger
+	runner     model.CommandRunner
+	lookupUser func(string) (*user.User, error)
+}
+
+// NewPosixProvider creates a provider managing keys in the authorized_keys file in the home directory of a user
+func NewPosixProvider(log model.Logger, runner model.CommandRunner) (*Provider, error) {
+	return &Provider{log: log, runner: runner, lookupUser: user.Lookup}, nil
+}
+
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+// authorizedKey is a parsed entry from an authorized_keys file
+type authorizedKey struct {
+	options []string
+	keyType string
+	key     string
+	comment string
+}
+
+// owner is the user owning an authorized_keys file
+type owner struct {
+	dir  string
+	file string
+	uid  int
+	gid  int
+}
+
+func (p *Provider) owner(properties *model.SSHAuthorizedKeyResourceProperties) (*owner, error) {
+	usr, err := p.lookupUser(properties.User)
+	if err != nil {
+		return nil, fmt.Errorf("could not look up user %s: %w", properties.User, err)
+	}
+
+	if usr.HomeDir == "" {
+		return nil, fmt.Errorf("user %s has no home directory", properties.User)
+	}
+
+	uid, err := strconv.Atoi(usr.Uid)
+	if err != nil {
+		return nil, fmt.Errorf("invalid uid %q for user %s", usr.Uid, properties.User)
+	}
+
+	gid, err := strconv.Atoi(usr.Gid)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gid %q for user %s", usr.Gid, properties.User)
+	}
+
+	dir := filepath.Join(usr.HomeDir, ".ssh")
+
+	return &owner{dir: dir, file: filepath.Join(dir, "authorized_keys"), uid: uid, gid: gid}, nil
+}
+
+// Status reports the entry identified by the key body or comment in the authorized_keys file of the user
+func (p *Provider) Status(ctx context.Context, properties *model.SSHAuthorizedKeyResourceProperties) (*model.SSHAuthorizedKeyState, error) {
+	own, err := p.owner(properties)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &model.SSHAuthorizedKeyState{
+		CommonResourceState: model.NewCommonResourceState(model.ResourceStatusSSHAuthorizedKeyProtocol, model.SSHAuthorizedKeyTypeName, properties.Name, model.EnsureAbsent),
+		Metadata: &model.SSHAuthorizedKeyMetadata{
+			Name:     properties.Name,
+			User:     properties.User,
+			File:     own.file,
+			Provider: ProviderName,
+		},
+	}
+
+	lines, err := readLines(own.file)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range lines {
+		key, ok := parseAuthorizedKey(line)
+		if !ok || !matches(key, properties) {
+			continue
+		}
+
+		state.Metadata.Matches++
+		if state.Metadata.Matches == 1 {
+			state.Ensure = model.EnsurePresent
+			state.Metadata.KeyType = key.keyType
+			state.Metadata.Key = key.key
+			state.Metadata.Comment = key.comment
+			state.Metadata.Options = key.options
+		}
+	}
+
+	return state, nil
+}
+
+// Set writes the key in place of the first entry identified as this key, other identified entries are removed and
+// the key is appended when none were found. Unrelated lines are kept as they are.
+func (p *Provider) Set(ctx context.Context, properties *model.SSHAuthorizedKeyResourceProperties) error {
+	own, err := p.owner(properties)
+	if err != nil {
+		return err
+	}
+
+	lines, err := readLines(own.file)
+	if err != nil {
+		return err
+	}
+
+	var result []string
+	written := false
+	for _, line := range lines {
+		key, ok := parseAuthorizedKey(line)
+		if ok && matches(key, properties) {
+			if !written {
+				result = append(result, properties.AuthorizedKeyLine())
+				written = true
+			}
+			continue
+		}
+
+		result = append(result, line)
+	}
+
+	if !written {
+		result = append(result, properties.AuthorizedKeyLine())
+	}
+
+	return p.write(own, result)
+}
+
+// Remove removes every entry identified as this key, a missing file is not an error
+func (p *Provider) Remove(ctx context.Context, properties *model.SSHAuthorizedKeyResourceProperties) error {
+	own, err := p.owner(properties)
+	if err != nil {
+		return err
+	}
+
+	lines, err := readLines(own.file)
+	if err != nil {
+		return err
+	}
+
+	var result []string
+	for _, line := range lines {
+		key, ok := parseAuthorizedKey(line)
+		if ok && matches(key, properties) {
+			continue
+		}
+
+		result = append(result, line)
+	}
+
+	if len(result) == len(lines) {
+		return nil
+	}
+
+	return p.write(own, result)
+}
+
+// write atomically replaces the authorized_keys file, creating the .ssh directory when needed
+func (p *Provider) write(own *owner, lines []string) error {
+	_, err := os.Stat(own.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		err = os.Mkdir(own.dir, 0700)
+		if err != nil {
+			return err
+		}
+
+		err = os.Chown(own.dir, own.uid, own.gid)
+		if err != nil {
+			return fmt.Errorf("could not set owner of %s: %w", own.dir, err)
+		}
+
+		p.log.Debug("Created ssh directory", "dir", own.dir)
+	} else if err != nil {
+		return err
+	}
+
+	tf, err := os.CreateTemp(own.dir, ".authorized_keys.*")
+	if err != nil {
+		return err
+	}
+	defer tf.Close()
+	defer os.Remove(tf.Name())
+
+	var content []byte
+	if len(lines) > 0 {
+		content = []byte(strings.Join(lines, "\n") + "\n")
+	}
+
+	_, err = tf.Write(content)
+	if err != nil {
+		return err
+	}
+
+	err = tf.Chmod(0600)
+	if err != nil {
+		return fmt.Errorf("could not set mode on temporary file: %w", err)
+	}
+
+	err = tf.Chown(own.uid, own.gid)
+	if err != nil {
+		return fmt.Errorf("could not set owner on temporary file: %w", err)
+	}
+
+	err = tf.Sync()
+	if err != nil {
+		return fmt.Errorf("could not sync temporary file: %w", err)
+	}
+
+	err = tf.Close()
+	if err != nil {
+		return fmt.Errorf("could not close temporary file: %w", err)
+	}
+
+	err = os.Rename(tf.Name(), own.file)
+	if err != nil {
+		return fmt.Errorf("could not rename temporary file: %w", err)
+	}
+
+	p.log.Debug("Wrote authorized keys", "file", own.file)
+
+	return nil
+}
+
+// matches determines if key is the key managed by properties, keys are identified by their body or comment
+func matches(key *authorizedKey, properties *model.SSHAuthorizedKeyResourceProperties) bool {
+	if properties.Key != "" && key.key == properties.Key {
+		return true
+	}
+
+	return key.comment != "" && key.comment == properties.KeyComment()
+}
+
+func readLines(file string) ([]string, error) {
+	content, err := os.ReadFile(file)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	content = bytes.TrimSuffix(content, []byte("\n"))
+	if len(content) == 0 {
+		return nil, nil
+	}
+
+	return strings.Split(string(content), "\n"), nil
+}
+
+// parseAuthorizedKey parses a line in the format described in sshd(8), returns false for comments, blank lines and
+// lines that can not be parsed
+func parseAuthorizedKey(line string) (*authorizedKey, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil, false
+	}
+
+	fields := splitQuoted(line, func(r rune) bool { return r == ' ' || r == '\t' })
+
+	key := &authorizedKey{}
+	if !model.IsSSHKeyType(fields[0]) {
+		key.options = splitQuoted(fields[0], func(r rune) bool { return r == ',' })
+		fields = fields[1:]
+	}
+
+	if len(fields) < 2 {
+		return nil, false
+	}
+
+	key.keyType = fields[0]
+	key.key = fields[1]
+	key.comment = strings.Join(fields[2:], " ")
+
+	return key, true
+}
+
+// splitQuoted splits s on runes matching sep that are not inside double quotes, empty fields are dropped
+func splitQuoted(s string, sep func(rune) bool) []string {
+	var (
+		fields  []string
+		current strings.Builder
+		quoted  bool
+		escaped bool
+	)
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\' && quoted:
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+		case !quoted && sep(r):
+			if current.Len() > 0 {
+				fields = append(fields, current.String())
+				current.Reset()
+			}
+			continue
+		}
+
+		current.WriteRune(r)
+	}
+
+	if current.Len() > 0 {
+		fields = append(fields, current.String())
+	}
+
+	return fields
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package posix
+
+import (
+	"context"
+	"os"
+	"os/user"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/model/modelmocks"
+)
+
+func TestPosixProvider(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Resources/SSHAuthorizedKey/Posix")
+}
+
+var _ = Describe("Posix Provider", func() {
+	var (
+		mockctl  *gomock.Controller
+		logger   *modelmocks.MockLogger
+		provider *Provider
+		home     string
+		keysFile string
+	)
+
+	const (
+		aliceKey = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQAlice alice@desktop"
+		otherKey = `command="/usr/bin/backup --dry-run",from="10.0.0.0/8" ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIBackup backup`
+	)
+
+	BeforeEach(func() {
+		mockctl = gomock.NewController(GinkgoT())
+		logger = modelmocks.NewMockLogger(mockctl)
+		logger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+
+		home = GinkgoT().TempDir()
+		keysFile = filepath.Join(home, ".ssh", "authorized_keys")
+
+		current, err := user.Current()
+		Expect(err).ToNot(HaveOccurred())
+
+		provider, err = NewPosixProvider(logger, modelmocks.NewMockCommandRunner(mockctl))
+		Expect(err).ToNot(HaveOccurred())
+		provider.lookupUser = func(name string) (*user.User, error) {
+			Expect(name).To(Equal("bob"))
+			return &user.User{Username: name, Uid: current.Uid, Gid: current.Gid, HomeDir: home}, nil
+		}
+	})
+
+	props := func() *model.SSHAuthorizedKeyResourceProperties {
+		return &model.SSHAuthorizedKeyResourceProperties{
+			CommonResourceProperties: model.CommonResourceProperties{Name: "bob@laptop", Ensure: model.EnsurePresent},
+			User:                     "bob",
+			KeyType:                  "ssh-ed25519",
+			Key:                      "AAAAC3NzaC1lZDI1NTE5AAAAIBob",
+			Options:                  []string{"no-pty", `from="10.0.0.1,10.0.0.2"`},
+		}
+	}
+
+	writeKeys := func(lines string) {
+		Expect(os.MkdirAll(filepath.Dir(keysFile), 0700)).To(Succeed())
+		Expect(os.WriteFile(keysFile, []byte(lines), 0600)).To(Succeed())
+	}
+
+	Describe("Status", func() {
+		It("Should handle missing files", func(ctx context.Context) {
+			state, err := provider.Status(ctx, props())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Ensure).To(Equal(model.EnsureAbsent))
+			Expect(state.Metadata.File).To(Equal(keysFile))
+		})
+
+		It("Should find keys by comment", func(ctx context.Context) {
+			writeKeys("# managed keys\n" + aliceKey + "\nno-pty ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOld bob@laptop\n")
+
+			state, err := provider.Status(ctx, props())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Ensure).To(Equal(model.EnsurePresent))
+			Expect(state.Metadata.Matches).To(Equal(1))
+			Expect(state.Metadata.Key).To(Equal("AAAAC3NzaC1lZDI1NTE5AAAAIOld"))
+			Expect(state.Metadata.Options).To(Equal([]string{"no-pty"}))
+		})
+
+		It("Should find keys by body and count duplicates", func(ctx context.Context) {
+			writeKeys("ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIBob old comment\nssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIBob\n")
+
+			state, err := provider.Status(ctx, props())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Metadata.Matches).To(Equal(2))
+			Expect(state.Metadata.Comment).To(Equal("old comment"))
+		})
+	})
+
+	Describe("Set", func() {
+		It("Should create the directory and file", func(ctx context.Context) {
+			Expect(provider.Set(ctx, props())).To(Succeed())
+
+			stat, err := os.Stat(filepath.Dir(keysFile))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stat.Mode().Perm()).To(Equal(os.FileMode(0700)))
+
+			stat, err = os.Stat(keysFile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stat.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+			Expect(os.ReadFile(keysFile)).To(Equal([]byte(`no-pty,from="10.0.0.1,10.0.0.2" ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIBob bob@laptop` + "\n")))
+
+			state, err := provider.Status(ctx, props())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Metadata.Options).To(Equal(props().Options))
+		})
+
+		It("Should replace the key in place and keep unrelated keys", func(ctx context.Context) {
+			writeKeys("# managed keys\n" + aliceKey + "\nssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOld bob@laptop\n" + otherKey + "\nssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIBob\n")
+
+			Expect(provider.Set(ctx, props())).To(Succeed())
+
+			Expect(os.ReadFile(keysFile)).To(Equal([]byte("# managed keys\n" + aliceKey + "\n" + props().AuthorizedKeyLine() + "\n" + otherKey + "\n")))
+		})
+	})
+
+	Describe("Remove", func() {
+		It("Should only remove the matching key", func(ctx context.Context) {
+			writeKeys(aliceKey + "\nssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIBob bob@laptop\n" + otherKey + "\n")
+
+			Expect(provider.Remove(ctx, props())).To(Succeed())
+			Expect(os.ReadFile(keysFile)).To(Equal([]byte(aliceKey + "\n" + otherKey + "\n")))
+		})
+
+		It("Should handle missing files", func(ctx context.Context) {
+			Expect(provider.Remove(ctx, props())).To(Succeed())
+			Expect(keysFile).ToNot(BeAnExistingFile())
+		})
+	})
+
+	Describe("parseAuthorizedKey", func() {
+		It("Should parse options with quoted spaces and commas", func() {
+			key, ok := parseAuthorizedKey(otherKey)
+			Expect(ok).To(BeTrue())
+			Expect(key.options).To(Equal([]string{`command="/usr/bin/backup --dry-run"`, `from="10.0.0.0/8"`}))
+			Expect(key.keyType).To(Equal("ssh-ed25519"))
+			Expect(key.key).To(Equal("AAAAC3NzaC1lZDI1NTE5AAAAIBackup"))
+			Expect(key.comment).To(Equal("backup"))
+		})
+
+		It("Should skip comments and invalid lines", func() {
+			for _, line := range []string{"", "   ", "# ssh-rsa AAAA x", "ssh-rsa"} {
+				_, ok := parseAuthorizedKey(line)
+				Expect(ok).To(BeFalse(), line)
+			}
+		})
+	})
+})
@@ -0,0 +1,24 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package sshkeyresource
+
+import (
+	"context"
+
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/resources/sshkey/posix"
+)
+
+func init() {
+	posix.Register()
+}
+
+type SSHAuthorizedKeyProvider interface {
+	model.Provider
+
+	Status(ctx context.Context, properties *model.SSHAuthorizedKeyResourceProperties) (*model.SSHAuthorizedKeyState, error)
+	Set(ctx context.Context, properties *model.SSHAuthorizedKeyResourceProperties) error
+	Remove(ctx context.Context, properties *model.SSHAuthorizedKeyResourceProperties) error
+}
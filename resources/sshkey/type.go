@@ -0,0 +1,271 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package sshkeyresource
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/choria-io/ccm/internal/registry"
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/resources/base"
+	"github.com/choria-io/ccm/resources/sshkey/posix"
+)
+
+var _ base.ProviderFallback = (*Type)(nil)
+var _ base.StatusReporter = (*Type)(nil)
+
+type Type struct {
+	*base.Base
+
+	prop     *model.SSHAuthorizedKeyResourceProperties
+	mgr      model.Manager
+	log      model.Logger
+	provider model.Provider
+
+	mu sync.Mutex
+}
+
+var _ model.Resource = (*Type)(nil)
+var _ SSHAuthorizedKeyProvider = (*posix.Provider)(nil)
+
+// New creates a new ssh_authorized_key resource with the given properties
+func New(ctx context.Context, mgr model.Manager, properties model.SSHAuthorizedKeyResourceProperties) (*Type, error) {
+	env, err := mgr.TemplateEnvironment(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = properties.ResolveTemplates(env)
+	if err != nil {
+		return nil, err
+	}
+
+	loggerArgs := []any{"type", model.SSHAuthorizedKeyTypeName, "name", properties.Name}
+	logger, err := mgr.Logger(loggerArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	properties.CommonResourceProperties.Type = model.SSHAuthorizedKeyTypeName
+
+	t := &Type{
+		prop: &properties,
+		mgr:  mgr,
+		log:  logger,
+	}
+	t.Base = &base.Base{
+		Resource:           t,
+		ResourceProperties: &properties,
+		CommonProperties:   properties.CommonResourceProperties,
+		Log:                logger,
+		UserLogger:         mgr.UserLogger().With(loggerArgs...),
+		Manager:            mgr,
+		Facts:              env.Facts,
+		Data:               env.Data,
+	}
+
+	err = t.Base.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %w", t.String(), model.ErrResourceInvalid, err)
+	}
+
+	t.log.Debug("Created resource instance")
+
+	return t, nil
+}
+
+func (t *Type) ApplyResource(ctx context.Context) (model.ResourceState, error) {
+	var (
+		initialStatus *model.SSHAuthorizedKeyState
+		finalStatus   *model.SSHAuthorizedKeyState
+		p             = t.provider.(SSHAuthorizedKeyProvider)
+		properties    = t.prop
+		noop          = t.mgr.NoopMode()
+		noopMessage   string
+		err           error
+	)
+
+	initialStatus, err = p.Status(ctx, properties)
+	if err != nil {
+		return nil, err
+	}
+
+	isStable, _ := t.isDesiredState(properties, initialStatus)
+	if isStable {
+		t.FinalizeState(initialStatus, noop, "", false, true, false)
+		return initialStatus, nil
+	}
+
+	switch {
+	case properties.Ensure == model.EnsureAbsent:
+		if !noop {
+			t.log.Info("Removing authorized key")
+			err = p.Remove(ctx, properties)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			t.log.Info("Skipping remove as noop")
+			noopMessage = "Would have removed authorized key"
+		}
+
+	default:
+		if !noop {
+			t.log.Info("Writing authorized key")
+			err = p.Set(ctx, properties)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			t.log.Info("Skipping write as noop")
+			noopMessage = "Would have added authorized key"
+			if initialStatus.Ensure == model.EnsurePresent {
+				noopMessage = "Would have updated authorized key"
+			}
+		}
+	}
+
+	finalStatus = initialStatus
+	if !noop {
+		finalStatus, err = p.Status(ctx, properties)
+		if err != nil {
+			return nil, err
+		}
+
+		var reason string
+		isStable, reason = t.isDesiredState(properties, finalStatus)
+		if !isStable {
+			return nil, fmt.Errorf("%w: %s: %s", model.ErrDesiredStateFailed, properties.Ensure, reason)
+		}
+	}
+
+	t.FinalizeState(finalStatus, noop, noopMessage, true, isStable, false)
+	t.ClassifyChange(finalStatus, initialStatus.Ensure != model.EnsureAbsent)
+
+	return finalStatus, nil
+}
+
+// isDesiredState reports whether state matches properties by comparing the key type, body, comment and options of
+// the single entry identified as this key. The second return is a human-readable reason describing the mismatch when
+// stable is false, suitable for inclusion in error messages.
+func (t *Type) isDesiredState(properties *model.SSHAuthorizedKeyResourceProperties, state *model.SSHAuthorizedKeyState) (bool, string) {
+	if properties.Ensure == model.EnsureAbsent {
+		if state.Ensure == model.EnsureAbsent {
+			return true, ""
+		}
+		return false, fmt.Sprintf("%d matching keys found", state.Metadata.Matches)
+	}
+
+	switch {
+	case state.Ensure != model.EnsurePresent:
+		return false, "key not found"
+	case state.Metadata.Matches > 1:
+		return false, fmt.Sprintf("%d matching keys found", state.Metadata.Matches)
+	case state.Metadata.KeyType != properties.KeyType || state.Metadata.Key != properties.Key:
+		return false, "key differs"
+	case state.Metadata.Comment != properties.KeyComment():
+		return false, "comment differs"
+	case !slices.Equal(state.Metadata.Options, properties.Options):
+		return false, "options differ"
+	}
+
+	return true, ""
+}
+
+func (t *Type) Info(ctx context.Context) (any, error) {
+	_, err := t.SelectProvider()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", t.String(), err)
+	}
+
+	return t.provider.(SSHAuthorizedKeyProvider).Status(ctx, t.prop)
+}
+
+// CurrentState reports the current state of the resource without making any changes
+func (t *Type) CurrentState(ctx context.Context) (model.ResourceState, error) {
+	state, err := t.provider.(SSHAuthorizedKeyProvider).Status(ctx, t.prop)
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+func (t *Type) providerUnlocked() string {
+	if t.provider == nil {
+		return ""
+	}
+
+	return t.provider.Name()
+}
+
+// Provider returns the name of the selected provider
+func (t *Type) Provider() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.providerUnlocked()
+}
+
+func (t *Type) selectProviderUnlocked() error {
+	if t.provider != nil {
+		return nil
+	}
+
+	runner, err := t.mgr.NewRunner()
+	if err != nil {
+		return err
+	}
+
+	selected, err := registry.FindSuitableProvider(model.SSHAuthorizedKeyTypeName, t.prop.Provider, t.Facts, t.prop, t.log, runner, t.mgr)
+	if err != nil {
+		return err
+	}
+
+	if selected == nil {
+		return model.ErrNoSuitableProvider
+	}
+
+	t.log.Debug("Selected provider", "provider", selected.Name())
+	t.provider = selected
+
+	return nil
+}
+
+// SelectAlternateProvider replaces the selected provider with the most suitable provider not listed in exclude
+func (t *Type) SelectAlternateProvider(exclude []string) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	runner, err := t.mgr.NewRunner()
+	if err != nil {
+		return "", err
+	}
+
+	selected, err := registry.FindAlternateProvider(model.SSHAuthorizedKeyTypeName, exclude, t.Facts, t.prop, t.log, runner, t.mgr)
+	if err != nil {
+		return "", err
+	}
+
+	t.log.Debug("Selected alternate provider", "provider", selected.Name())
+	t.provider = selected
+
+	return t.providerUnlocked(), nil
+}
+
+func (t *Type) SelectProvider() (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	err := t.selectProviderUnlocked()
+	if err != nil {
+		return "", err
+	}
+
+	return t.providerUnlocked(), nil
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package sshkeyresource
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/choria-io/ccm/internal/registry"
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/model/modelmocks"
+)
+
+func TestSSHAuthorizedKeyResource(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Resources/SSHAuthorizedKey")
+}
+
+var _ = Describe("SSHAuthorizedKey Type", func() {
+	var (
+		facts    = make(map[string]any)
+		data     = make(map[string]any)
+		mgr      *modelmocks.MockManager
+		logger   *modelmocks.MockLogger
+		mockctl  *gomock.Controller
+		provider *MockSSHAuthorizedKeyProvider
+	)
+
+	BeforeEach(func() {
+		mockctl = gomock.NewController(GinkgoT())
+		mgr, logger = modelmocks.NewManager(facts, data, false, mockctl)
+		mgr.EXPECT().NewRunner().AnyTimes().Return(modelmocks.NewMockCommandRunner(mockctl), nil)
+		provider = NewMockSSHAuthorizedKeyProvider(mockctl)
+
+		provider.EXPECT().Name().Return("mock").AnyTimes()
+		logger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+		logger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+	})
+
+	Describe("New", func() {
+		It("Should validate properties", func(ctx context.Context) {
+			_, err := New(ctx, mgr, model.SSHAuthorizedKeyResourceProperties{})
+			Expect(err).To(MatchError(model.ErrResourceNameRequired))
+
+			_, err = New(ctx, mgr, model.SSHAuthorizedKeyResourceProperties{
+				CommonResourceProperties: model.CommonResourceProperties{Name: "bob@laptop", Ensure: model.EnsurePresent},
+				User:                     "bob",
+				KeyType:                  "ssh-ed25519",
+			})
+			Expect(err).To(MatchError(ContainSubstring("key is required")))
+		})
+	})
+
+	Context("with a prepared provider", func() {
+		var factory *modelmocks.MockProviderFactory
+		var res *Type
+		var err error
+
+		BeforeEach(func(ctx context.Context) {
+			factory = modelmocks.NewMockProviderFactory(mockctl)
+			factory.EXPECT().Name().Return("test").AnyTimes()
+			factory.EXPECT().TypeName().Return(model.SSHAuthorizedKeyTypeName).AnyTimes()
+			factory.EXPECT().New(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(log model.Logger, runner model.CommandRunner) (model.Provider, error) {
+				return provider, nil
+			})
+			factory.EXPECT().IsManageable(facts, gomock.Any()).Return(true, 1, nil).AnyTimes()
+
+			res, err = New(ctx, mgr, model.SSHAuthorizedKeyResourceProperties{
+				CommonResourceProperties: model.CommonResourceProperties{
+					Name:     "bob@laptop",
+					Ensure:   model.EnsurePresent,
+					Provider: "test",
+				},
+				User:    "bob",
+				KeyType: "ssh-ed25519",
+				Key:     "AAAAC3NzaC1lZDI1NTE5AAAAIBob",
+				Options: []string{"no-pty"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			registry.Clear()
+			registry.MustRegister(factory)
+		})
+
+		state := func(matches int, key string, options ...string) *model.SSHAuthorizedKeyState {
+			s := &model.SSHAuthorizedKeyState{
+				CommonResourceState: model.CommonResourceState{Ensure: model.EnsureAbsent},
+				Metadata:            &model.SSHAuthorizedKeyMetadata{Name: "bob@laptop", User: "bob"},
+			}
+
+			if matches > 0 {
+				s.Ensure = model.EnsurePresent
+				s.Metadata.Matches = matches
+				s.Metadata.KeyType = "ssh-ed25519"
+				s.Metadata.Key = key
+				s.Metadata.Comment = "bob@laptop"
+				s.Metadata.Options = options
+			}
+
+			return s
+		}
+
+		Describe("Apply", func() {
+			It("Should fail if initial status check fails", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("status failed"))
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Errors).To(ContainElement(ContainSubstring("status failed")))
+			})
+
+			It("Should add missing keys", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(0, ""), nil)
+				provider.EXPECT().Set(gomock.Any(), res.prop).Return(nil)
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(1, res.prop.Key, "no-pty"), nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Errors).To(BeEmpty())
+				Expect(event.Changed).To(BeTrue())
+			})
+
+			It("Should update keys with different options", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(1, res.prop.Key), nil)
+				provider.EXPECT().Set(gomock.Any(), res.prop).Return(nil)
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(1, res.prop.Key, "no-pty"), nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Errors).To(BeEmpty())
+				Expect(event.Changed).To(BeTrue())
+			})
+
+			It("Should fail when duplicates remain", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(2, res.prop.Key, "no-pty"), nil)
+				provider.EXPECT().Set(gomock.Any(), res.prop).Return(nil)
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(2, res.prop.Key, "no-pty"), nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Errors).To(ContainElement(ContainSubstring("2 matching keys found")))
+			})
+
+			It("Should not change when the key matches", func(ctx context.Context) {
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(1, res.prop.Key, "no-pty"), nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Changed).To(BeFalse())
+			})
+
+			It("Should remove the key when absent", func(ctx context.Context) {
+				res.prop.Ensure = model.EnsureAbsent
+
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(1, res.prop.Key), nil)
+				provider.EXPECT().Remove(gomock.Any(), res.prop).Return(nil)
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(0, ""), nil)
+
+				event, err := res.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Errors).To(BeEmpty())
+				Expect(event.Changed).To(BeTrue())
+			})
+		})
+
+		Describe("Apply in noop mode", func() {
+			It("Should not add the key", func(ctx context.Context) {
+				noopMgr, _ := modelmocks.NewManager(facts, data, true, mockctl)
+				noopMgr.EXPECT().NewRunner().AnyTimes().Return(modelmocks.NewMockCommandRunner(mockctl), nil)
+				noopRes, err := New(ctx, noopMgr, *res.prop)
+				Expect(err).ToNot(HaveOccurred())
+
+				provider.EXPECT().Status(gomock.Any(), gomock.Any()).Return(state(0, ""), nil)
+
+				event, err := noopRes.Apply(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(event.Changed).To(BeTrue())
+				Expect(event.Noop).To(BeTrue())
+				Expect(event.NoopMessage).To(Equal("Would have added authorized key"))
+			})
+		})
+	})
+})
@@ -0,0 +1,24 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package sudoersresource
+
+import (
+	"context"
+
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/resources/sudoers/visudo"
+)
+
+func init() {
+	visudo.Register()
+}
+
+type SudoersProvider interface {
+	model.Provider
+
+	Set(ctx context.Context, properties *model.SudoersResourceProperties) error
+	Remove(ctx context.Context, properties *model.SudoersResourceProperties) error
+	Status(ctx context.Context, properties *model.SudoersResourceProperties) (*model.SudoersState, error)
+}
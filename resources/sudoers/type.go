@@ -0,0 +1,258 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package sudoersresource
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/choria-io/ccm/internal/registry"
+	iu "github.com/choria-io/ccm/internal/util"
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/resources/base"
+	"github.com/choria-io/ccm/resources/sudoers/visudo"
+)
+
+var _ base.StatusReporter = (*Type)(nil)
+
+type Type struct {
+	*base.Base
+
+	prop     *model.SudoersResourceProperties
+	mgr      model.Manager
+	log      model.Logger
+	provider model.Provider
+
+	mu sync.Mutex
+}
+
+var _ model.Resource = (*Type)(nil)
+var _ SudoersProvider = (*visudo.Provider)(nil)
+
+// New creates a new sudoers resource with the given properties
+func New(ctx context.Context, mgr model.Manager, properties model.SudoersResourceProperties) (*Type, error) {
+	env, err := mgr.TemplateEnvironment(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = properties.ResolveTemplates(env)
+	if err != nil {
+		return nil, err
+	}
+
+	loggerArgs := []any{"type", model.SudoersTypeName, "name", properties.Name}
+	logger, err := mgr.Logger(loggerArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	properties.CommonResourceProperties.Type = model.SudoersTypeName
+
+	t := &Type{
+		prop: &properties,
+		mgr:  mgr,
+		log:  logger,
+	}
+	t.Base = &base.Base{
+		Resource:           t,
+		ResourceProperties: &properties,
+		CommonProperties:   properties.CommonResourceProperties,
+		Log:                logger,
+		UserLogger:         mgr.UserLogger().With(loggerArgs...),
+		Manager:            mgr,
+		Facts:              env.Facts,
+		Data:               env.Data,
+	}
+
+	err = t.Base.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %w", t.String(), model.ErrResourceInvalid, err)
+	}
+
+	t.log.Debug("Created resource instance")
+
+	return t, nil
+}
+
+func (t *Type) ApplyResource(ctx context.Context) (model.ResourceState, error) {
+	var (
+		initialStatus *model.SudoersState
+		finalStatus   *model.SudoersState
+		p             = t.provider.(SudoersProvider)
+		properties    = t.prop
+		noop          = t.mgr.NoopMode()
+		noopMessage   string
+		err           error
+	)
+
+	initialStatus, err = p.Status(ctx, properties)
+	if err != nil {
+		return nil, err
+	}
+
+	isStable, _, err := t.isDesiredState(properties, initialStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	if isStable {
+		t.FinalizeState(initialStatus, noop, "", false, true, false)
+		return initialStatus, nil
+	}
+
+	switch properties.Ensure {
+	case model.EnsureAbsent:
+		if !noop {
+			t.log.Info("Removing sudoers file")
+			err = p.Remove(ctx, properties)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			t.log.Info("Skipping remove as noop")
+			noopMessage = "Would have removed the sudoers file"
+		}
+
+	default:
+		if !noop {
+			t.log.Info("Writing sudoers file")
+			err = p.Set(ctx, properties)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			t.log.Info("Skipping write as noop")
+			noopMessage = "Would have written the sudoers file"
+		}
+	}
+
+	finalStatus = initialStatus
+	if !noop {
+		finalStatus, err = p.Status(ctx, properties)
+		if err != nil {
+			return nil, err
+		}
+
+		var reason string
+		isStable, reason, err = t.isDesiredState(properties, finalStatus)
+		if err != nil {
+			return nil, err
+		}
+		if !isStable {
+			return nil, fmt.Errorf("%w: %s: %s", model.ErrDesiredStateFailed, properties.Ensure, reason)
+		}
+	}
+
+	t.FinalizeState(finalStatus, noop, noopMessage, true, isStable, false)
+	t.ClassifyChange(finalStatus, initialStatus.Ensure != model.EnsureAbsent)
+
+	return finalStatus, nil
+}
+
+// isDesiredState reports whether state matches properties by comparing the checksum
+// of the sudoers file with the rendered content and ensuring the file has the mode sudo
+// requires. The second return is a human-readable reason describing the mismatch when
+// stable is false, suitable for inclusion in error messages.
+func (t *Type) isDesiredState(properties *model.SudoersResourceProperties, state *model.SudoersState) (bool, string, error) {
+	if properties.Ensure == model.EnsureAbsent {
+		if state.Ensure == model.EnsureAbsent {
+			return true, "", nil
+		}
+		return false, fmt.Sprintf("%s still exists", state.Metadata.File), nil
+	}
+
+	if state.Ensure != model.EnsurePresent {
+		return false, fmt.Sprintf("%s does not exist", state.Metadata.File), nil
+	}
+
+	checksum, err := iu.Sha256HashBytes(properties.FileContent())
+	if err != nil {
+		return false, "", err
+	}
+
+	if state.Metadata.Checksum != checksum {
+		t.log.Debug("Sudoers file checksum does not match", "file", state.Metadata.File, "checksum", state.Metadata.Checksum, "expected", checksum)
+		return false, fmt.Sprintf("checksum mismatch in %s", state.Metadata.File), nil
+	}
+
+	if state.Metadata.Mode != model.SudoersFileMode {
+		return false, fmt.Sprintf("mode of %s is %s, expected %s", state.Metadata.File, state.Metadata.Mode, model.SudoersFileMode), nil
+	}
+
+	return true, "", nil
+}
+
+func (t *Type) Info(ctx context.Context) (any, error) {
+	_, err := t.SelectProvider()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", t.String(), err)
+	}
+
+	return t.provider.(SudoersProvider).Status(ctx, t.prop)
+}
+
+// CurrentState reports the current state of the resource without making any changes
+func (t *Type) CurrentState(ctx context.Context) (model.ResourceState, error) {
+	state, err := t.provider.(SudoersProvider).Status(ctx, t.prop)
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+func (t *Type) providerUnlocked() string {
+	if t.provider == nil {
+		return ""
+	}
+
+	return t.provider.Name()
+}
+
+func (t *Type) Provider() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.providerUnlocked()
+}
+
+func (t *Type) selectProviderUnlocked() error {
+	if t.provider != nil {
+		return nil
+	}
+
+	runner, err := t.mgr.NewRunner()
+	if err != nil {
+		return err
+	}
+
+	selected, err := registry.FindSuitableProvider(model.SudoersTypeName, t.prop.Provider, t.Facts, t.prop, t.log, runner, t.mgr)
+	if err != nil {
+		return err
+	}
+
+	if selected == nil {
+		return model.ErrNoSuitableProvider
+	}
+
+	t.log.Debug("Selected provider", "provider", selected.Name())
+	t.provider = selected
+
+	return nil
+}
+
+func (t *Type) SelectProvider() (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	err := t.selectProviderUnlocked()
+	if err != nil {
+		return "", err
+	}
+
+	return t.providerUnlocked(), nil
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package visudo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	iu "github.com/choria-io/ccm/internal/util"
+	"github.com/choria-io/ccm/model"
+)
+
+const (
+	ProviderName = "visudo"
+
+	// DefaultDirectory is the directory sudo includes drop in files from
+	DefaultDirectory = "/etc/sudoers.d"
+)
+
+type Provider struct {
+	log    model.Logger
+	runner model.CommandRunner
+	dir    string
+}
+
+// NewVisudoProvider creates a provider managing sudoers files in dir that are validated using visudo
+func NewVisudoProvider(log model.Logger, runner model.CommandRunner, dir string) (*Provider, error) {
+	return &Provider{log: log, runner: runner, dir: dir}, nil
+}
+
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+func (p *Provider) path(properties *model.SudoersResourceProperties) string {
+	return filepath.Join(p.dir, properties.FileName())
+}
+
+// Status reads the sudoers file and reports its checksum and mode
+func (p *Provider) Status(ctx context.Context, properties *model.SudoersResourceProperties) (*model.SudoersState, error) {
+	file := p.path(properties)
+
+	state := &model.SudoersState{
+		CommonResourceState: model.NewCommonResourceState(model.ResourceStatusSudoersProtocol, model.SudoersTypeName, properties.Name, model.EnsureAbsent),
+		Metadata: &model.SudoersMetadata{
+			Name:     properties.Name,
+			File:     file,
+			Provider: ProviderName,
+		},
+	}
+
+	stat, err := os.Stat(file)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return state, nil
+	case err != nil:
+		return nil, err
+	}
+
+	state.Ensure = model.EnsurePresent
+	state.Metadata.Mode = fmt.Sprintf("%04o", stat.Mode().Perm())
+	state.Metadata.Checksum, err = iu.Sha256HashFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// Set writes the sudoers file, the content is validated using visudo before the file is atomically replaced
+// so invalid content never reaches the live file
+func (p *Provider) Set(ctx context.Context, properties *model.SudoersResourceProperties) error {
+	file := p.path(properties)
+
+	mode, err := strconv.ParseUint(model.SudoersFileMode, 8, 32)
+	if err != nil {
+		return err
+	}
+
+	// sudo skips files with dots in their names so the temporary file is never loaded
+	tf, err := os.CreateTemp(p.dir, fmt.Sprintf(".%s.*", filepath.Base(file)))
+	if err != nil {
+		return err
+	}
+	defer tf.Close()
+	defer os.Remove(tf.Name())
+
+	_, err = tf.Write(properties.FileContent())
+	if err != nil {
+		return err
+	}
+
+	err = tf.Chmod(os.FileMode(mode))
+	if err != nil {
+		return fmt.Errorf("could not set mode on temporary file: %w", err)
+	}
+
+	err = tf.Sync()
+	if err != nil {
+		return fmt.Errorf("could not sync temporary file: %w", err)
+	}
+
+	err = tf.Close()
+	if err != nil {
+		return fmt.Errorf("could not close temporary file: %w", err)
+	}
+
+	stdout, stderr, exitCode, err := p.runner.Execute(ctx, "visudo", "-cf", tf.Name())
+	if err != nil {
+		return fmt.Errorf("could not validate sudoers content: %w", err)
+	}
+	if exitCode != 0 {
+		return fmt.Errorf("sudoers content failed validation: %s", bytes.TrimSpace(append(stdout, stderr...)))
+	}
+
+	err = os.Rename(tf.Name(), file)
+	if err != nil {
+		return fmt.Errorf("could not rename temporary file: %w", err)
+	}
+
+	p.log.Debug("Wrote sudoers file", "file", file)
+
+	return nil
+}
+
+// Remove deletes the sudoers file, a missing file is not an error
+func (p *Provider) Remove(ctx context.Context, properties *model.SudoersResourceProperties) error {
+	file := p.path(properties)
+
+	err := os.Remove(file)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	p.log.Debug("Removed sudoers file", "file", file)
+
+	return nil
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package visudo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	iu "github.com/choria-io/ccm/internal/util"
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/model/modelmocks"
+)
+
+func TestVisudoProvider(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Resources/Sudoers/Visudo")
+}
+
+var _ = Describe("Visudo Provider", func() {
+	var (
+		mockctl  *gomock.Controller
+		logger   *modelmocks.MockLogger
+		runner   *modelmocks.MockCommandRunner
+		provider *Provider
+		tmpDir   string
+		err      error
+	)
+
+	BeforeEach(func() {
+		mockctl = gomock.NewController(GinkgoT())
+		logger = modelmocks.NewMockLogger(mockctl)
+		logger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+		runner = modelmocks.NewMockCommandRunner(mockctl)
+
+		tmpDir = GinkgoT().TempDir()
+
+		provider, err = NewVisudoProvider(logger, runner, tmpDir)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	props := func(name string) *model.SudoersResourceProperties {
+		return &model.SudoersResourceProperties{
+			CommonResourceProperties: model.CommonResourceProperties{Name: name, Ensure: model.EnsurePresent},
+			Rules:                    []model.SudoersRule{{User: "deploy", Commands: []string{"/usr/bin/systemctl restart app"}, NoPasswd: true}},
+		}
+	}
+
+	// expectValidation expects visudo to check a temporary file in tmpDir, holding the expected content
+	expectValidation := func(prop *model.SudoersResourceProperties, exitCode int) {
+		runner.EXPECT().Execute(gomock.Any(), "visudo", "-cf", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, args ...string) ([]byte, []byte, int, error) {
+			Expect(filepath.Dir(args[1])).To(Equal(tmpDir))
+			Expect(filepath.Base(args[1])).To(HavePrefix("." + prop.FileName() + "."))
+			Expect(os.ReadFile(args[1])).To(Equal(prop.FileContent()))
+
+			stat, err := os.Stat(args[1])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stat.Mode().Perm()).To(Equal(os.FileMode(0440)))
+
+			if exitCode != 0 {
+				return nil, []byte(args[1] + ":2:1: syntax error\n"), exitCode, nil
+			}
+
+			return []byte(args[1] + ": parsed OK\n"), nil, 0, nil
+		})
+	}
+
+	Describe("Status", func() {
+		It("Should handle missing files", func(ctx context.Context) {
+			state, err := provider.Status(ctx, props("deploy"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Ensure).To(Equal(model.EnsureAbsent))
+			Expect(state.Metadata.File).To(Equal(filepath.Join(tmpDir, "deploy")))
+			Expect(state.Metadata.Provider).To(Equal(ProviderName))
+		})
+
+		It("Should report the checksum and mode", func(ctx context.Context) {
+			file := filepath.Join(tmpDir, "deploy")
+			Expect(os.WriteFile(file, []byte("deploy ALL=(ALL) ALL\n"), 0600)).To(Succeed())
+
+			checksum, err := iu.Sha256HashBytes([]byte("deploy ALL=(ALL) ALL\n"))
+			Expect(err).ToNot(HaveOccurred())
+
+			state, err := provider.Status(ctx, props("deploy"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Ensure).To(Equal(model.EnsurePresent))
+			Expect(state.Metadata.Mode).To(Equal("0600"))
+			Expect(state.Metadata.Checksum).To(Equal(checksum))
+		})
+	})
+
+	Describe("Set", func() {
+		It("Should write validated content with the sudoers mode", func(ctx context.Context) {
+			prop := props("app.deploy")
+			expectValidation(prop, 0)
+
+			Expect(provider.Set(ctx, prop)).To(Succeed())
+
+			file := filepath.Join(tmpDir, "app_deploy")
+			Expect(os.ReadFile(file)).To(Equal(prop.FileContent()))
+
+			state, err := provider.Status(ctx, prop)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Metadata.Mode).To(Equal(model.SudoersFileMode))
+
+			entries, err := os.ReadDir(tmpDir)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+		})
+
+		It("Should never touch the live file when validation fails", func(ctx context.Context) {
+			file := filepath.Join(tmpDir, "deploy")
+			Expect(os.WriteFile(file, []byte("deploy ALL=(ALL) ALL\n"), 0440)).To(Succeed())
+
+			prop := props("deploy")
+			prop.Rules = nil
+			prop.Content = "deploy ALL=(ALL"
+			expectValidation(prop, 1)
+
+			err := provider.Set(ctx, prop)
+			Expect(err).To(MatchError(ContainSubstring("sudoers content failed validation")))
+			Expect(err).To(MatchError(ContainSubstring("syntax error")))
+
+			Expect(os.ReadFile(file)).To(Equal([]byte("deploy ALL=(ALL) ALL\n")))
+
+			entries, err := os.ReadDir(tmpDir)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+		})
+
+		It("Should not create the file when validation fails", func(ctx context.Context) {
+			prop := props("deploy")
+			expectValidation(prop, 1)
+
+			Expect(provider.Set(ctx, prop)).To(MatchError(ContainSubstring("failed validation")))
+
+			entries, err := os.ReadDir(tmpDir)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(BeEmpty())
+		})
+	})
+
+	Describe("Remove", func() {
+		It("Should remove the file and ignore missing files", func(ctx context.Context) {
+			file := filepath.Join(tmpDir, "deploy")
+			Expect(os.WriteFile(file, []byte("deploy ALL=(ALL) ALL\n"), 0440)).To(Succeed())
+
+			Expect(provider.Remove(ctx, props("deploy"))).To(Succeed())
+			Expect(iu.FileExists(file)).To(BeFalse())
+			Expect(provider.Remove(ctx, props("deploy"))).To(Succeed())
+		})
+	})
+})
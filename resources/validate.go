@@ -0,0 +1,129 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/resources/apply"
+	"github.com/choria-io/ccm/templates"
+)
+
+// ValidationError is a problem found while validating a resource
+type ValidationError struct {
+	// Type is the resource type, empty when not known
+	Type string `json:"type,omitempty"`
+	// Name is the resource name, empty when the properties could not be parsed
+	Name string `json:"name,omitempty"`
+	// Field is the property holding the invalid value, empty when the problem is not tied to a single property
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e *ValidationError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidateResource validates the properties of a single resource, as parsed from YAML, using the same checks
+// as loading a manifest. Resources are created against a manager that cannot make changes, no provider is
+// selected and nothing on the system is inspected or changed.
+//
+// An error is only returned when validation could not be performed, problems with the resource are returned
+// as validation errors
+func ValidateResource(ctx context.Context, typeName string, properties map[string]any, env *templates.Env, log model.Logger) ([]*ValidationError, error) {
+	if env == nil {
+		env = &templates.Env{}
+	}
+
+	raw, err := yaml.Marshal(properties)
+	if err != nil {
+		return nil, err
+	}
+
+	props, err := model.NewResourcePropertiesFromYaml(typeName, raw, env)
+	if err != nil {
+		return []*ValidationError{{Type: typeName, Message: err.Error()}}, nil
+	}
+
+	mgr := &validationManager{env: env, log: log}
+
+	var errs []*ValidationError
+	for _, prop := range props {
+		name := prop.CommonProperties().Name
+
+		err = apply.ValidateResourceSchema(prop)
+		if err != nil {
+			var verr *jsonschema.ValidationError
+			if !errors.As(err, &verr) {
+				return nil, err
+			}
+
+			for _, unit := range verr.BasicOutput().Errors {
+				if unit.Error == nil {
+					continue
+				}
+
+				errs = append(errs, &ValidationError{
+					Type:    typeName,
+					Name:    name,
+					Field:   strings.ReplaceAll(strings.TrimPrefix(unit.InstanceLocation, "/"), "/", "."),
+					Message: unit.Error.String(),
+				})
+			}
+
+			continue
+		}
+
+		err = prop.Validate()
+		if err != nil {
+			errs = append(errs, &ValidationError{Type: typeName, Name: name, Message: err.Error()})
+			continue
+		}
+
+		// creating the resource performs the type specific validation, providers are only selected when applying
+		_, err = NewResourceFromProperties(ctx, mgr, prop)
+		if err != nil {
+			errs = append(errs, &ValidationError{Type: typeName, Name: name, Message: err.Error()})
+		}
+	}
+
+	return errs, nil
+}
+
+// validationManager supports creating resources for validation, it embeds a nil manager so any attempt
+// to use facilities beyond those needed to create a resource fails rather than affecting the system
+type validationManager struct {
+	model.Manager
+
+	env *templates.Env
+	log model.Logger
+}
+
+func (m *validationManager) TemplateEnvironment(_ context.Context) (*templates.Env, error) {
+	return m.env, nil
+}
+
+func (m *validationManager) Logger(args ...any) (model.Logger, error) {
+	if len(args)%2 != 0 {
+		return nil, fmt.Errorf("invalid logger arguments, must be key value pairs")
+	}
+
+	return m.log.With(args...), nil
+}
+
+func (m *validationManager) UserLogger() model.Logger { return m.log }
+func (m *validationManager) WorkingDirectory() string { return m.env.WorkingDir }
+func (m *validationManager) NoopMode() bool           { return true }
@@ -0,0 +1,102 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/model/modelmocks"
+	"github.com/choria-io/ccm/templates"
+)
+
+var _ = Describe("ValidateResource", func() {
+	var (
+		mockctl *gomock.Controller
+		logger  *modelmocks.MockLogger
+		env     *templates.Env
+	)
+
+	BeforeEach(func() {
+		mockctl = gomock.NewController(GinkgoT())
+		logger = modelmocks.NewMockLogger(mockctl)
+		logger.EXPECT().With(gomock.Any()).Return(logger).AnyTimes()
+		logger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+
+		env = &templates.Env{
+			Facts: map[string]any{"pkg": "nginx"},
+			Data:  map[string]any{},
+		}
+	})
+
+	validate := func(ctx context.Context, typeName string, properties map[string]any) []*ValidationError {
+		errs, err := ValidateResource(ctx, typeName, properties, env, logger)
+		Expect(err).ToNot(HaveOccurred())
+
+		return errs
+	}
+
+	It("Should accept valid resources", func(ctx context.Context) {
+		Expect(validate(ctx, model.PackageTypeName, map[string]any{"name": "{{ Facts.pkg }}", "ensure": "present"})).To(BeEmpty())
+		Expect(validate(ctx, model.FileTypeName, map[string]any{"name": "/etc/motd", "ensure": "present", "content": "hello", "owner": "root", "group": "root", "mode": "0644"})).To(BeEmpty())
+		Expect(validate(ctx, model.ServiceTypeName, map[string]any{"name": "nginx", "subscribe": []string{"package#nginx"}})).To(BeEmpty())
+		Expect(validate(ctx, model.ScaffoldTypeName, map[string]any{"name": "/srv/app", "ensure": "present", "source": "templates/app", "engine": "go"})).To(BeEmpty())
+	})
+
+	It("Should fail for unknown resource types", func(ctx context.Context) {
+		errs := validate(ctx, "unknown", map[string]any{"name": "x"})
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Message).To(ContainSubstring("unknown resource type"))
+	})
+
+	DescribeTable("Should report field errors from the schema",
+		func(ctx context.Context, typeName string, properties map[string]any, field string, message string) {
+			errs := validate(ctx, typeName, properties)
+			Expect(errs).To(ContainElement(And(
+				HaveField("Field", field),
+				HaveField("Message", ContainSubstring(message)),
+			)))
+		},
+		Entry("file ensure", model.FileTypeName, map[string]any{"name": "/etc/motd", "ensure": "bogus", "owner": "root", "group": "root", "mode": "0644"}, "ensure", "must be one of"),
+		Entry("file mode", model.FileTypeName, map[string]any{"name": "/etc/motd", "ensure": "present", "owner": "root", "group": "root", "mode": "99999"}, "mode", "does not match pattern"),
+		Entry("service ensure", model.ServiceTypeName, map[string]any{"name": "nginx", "ensure": "restarted"}, "ensure", "must be one of"),
+		Entry("service subscribe", model.ServiceTypeName, map[string]any{"name": "nginx", "subscribe": []string{"nginx"}}, "subscribe.0", "does not match pattern"),
+		Entry("exec subscribe", model.ExecTypeName, map[string]any{"name": "/bin/true", "subscribe": []string{"file"}}, "subscribe.0", "does not match pattern"),
+		Entry("group gid", model.GroupTypeName, map[string]any{"name": "app", "gid": -1}, "gid", "minimum"),
+		Entry("git ensure", model.GitTypeName, map[string]any{"name": "/srv/app", "source": "https://git.example.net/app.git", "ensure": "running"}, "ensure", "must be one of"),
+		Entry("scaffold engine", model.ScaffoldTypeName, map[string]any{"name": "/srv/app", "source": "templates/app", "engine": "erb"}, "engine", "must be one of"),
+	)
+
+	DescribeTable("Should report resource validation errors",
+		func(ctx context.Context, typeName string, properties map[string]any, message string) {
+			errs := validate(ctx, typeName, properties)
+			Expect(errs).To(ContainElement(HaveField("Message", ContainSubstring(message))))
+		},
+		Entry("package without ensure", model.PackageTypeName, map[string]any{"name": "nginx"}, "ensure is required"),
+		Entry("cron schedule range", model.CronTypeName, map[string]any{"name": "backup", "ensure": "present", "command": "/bin/backup", "hour": "25"}, `invalid hour "25"`),
+		Entry("sudoers relative command", model.SudoersTypeName, map[string]any{"name": "deploy", "ensure": "present", "rules": []any{map[string]any{"user": "deploy", "commands": []any{"systemctl"}}}}, "must be fully qualified"),
+		Entry("group invalid name", model.GroupTypeName, map[string]any{"name": "1app", "ensure": "present"}, `invalid group name "1app"`),
+		Entry("ssh_authorized_key without key", model.SSHAuthorizedKeyTypeName, map[string]any{"name": "bob@laptop", "ensure": "present", "user": "bob", "type": "ssh-ed25519"}, "key is required"),
+		Entry("git without source", model.GitTypeName, map[string]any{"name": "/srv/app", "ensure": "latest"}, "source cannot be empty"),
+		Entry("host without ip", model.HostTypeName, map[string]any{"name": "db.example.net", "ensure": "present"}, "ip is required"),
+		Entry("yumrepo without baseurl", model.YumRepoTypeName, map[string]any{"name": "epel", "ensure": "present"}, "baseurl is required"),
+		Entry("jsonedit without path", model.JsonEditTypeName, map[string]any{"name": "/etc/app.json", "ensure": "present"}, "path cannot be empty"),
+		Entry("file relative path", model.FileTypeName, map[string]any{"name": "etc/motd", "ensure": "present", "owner": "root", "group": "root", "mode": "0644"}, "absolute path"),
+		Entry("file mode range", model.FileTypeName, map[string]any{"name": "/etc/motd", "ensure": "present", "owner": "root", "group": "root", "mode": "1777"}, "exceeds maximum value"),
+		Entry("archive types", model.ArchiveTypeName, map[string]any{"name": "/tmp/a.zip", "ensure": "present", "url": "https://example.net/a.tgz", "owner": "root", "group": "root"}, "same archive type"),
+		Entry("archive cleanup", model.ArchiveTypeName, map[string]any{"name": "/tmp/a.tgz", "ensure": "present", "url": "https://example.net/a.tgz", "owner": "root", "group": "root", "cleanup": true}, "cleanup requires extract_parent"),
+	)
+
+	It("Should validate every resource in a block", func(ctx context.Context) {
+		errs := validate(ctx, model.FileTypeName, map[string]any{"name": "/etc/motd", "ensure": "present", "owner": "root", "group": "root", "mode": "999"})
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Name).To(Equal("/etc/motd"))
+		Expect(errs[0].Error()).To(HavePrefix("mode: "))
+	})
+})
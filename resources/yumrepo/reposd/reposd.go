@@ -0,0 +1,183 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package reposd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/choria-io/ccm/model"
+)
+
+const (
+	ProviderName = "reposd"
+
+	// DefaultDirectory is where dnf and yum read repository files from
+	DefaultDirectory = "/etc/yum.repos.d"
+)
+
+type Provider struct {
+	log model.Logger
+	dir string
+}
+
+// NewReposDProvider creates a provider managing repositories as files in dir
+func NewReposDProvider(log model.Logger, dir string) (*Provider, error) {
+	return &Provider{log: log, dir: dir}, nil
+}
+
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+func (p *Provider) path(properties *model.YumRepoResourceProperties) string {
+	return filepath.Join(p.dir, properties.FileName())
+}
+
+// Status parses the file holding the repository and reports the fields of its section
+func (p *Provider) Status(ctx context.Context, properties *model.YumRepoResourceProperties) (*model.YumRepoState, error) {
+	file := p.path(properties)
+
+	state := &model.YumRepoState{
+		CommonResourceState: model.NewCommonResourceState(model.ResourceStatusYumRepoProtocol, model.YumRepoTypeName, properties.Name, model.EnsureAbsent),
+		Metadata: &model.YumRepoMetadata{
+			Name:     properties.Name,
+			File:     file,
+			Provider: ProviderName,
+		},
+	}
+
+	raw, err := os.ReadFile(file)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return state, nil
+	case err != nil:
+		return nil, err
+	}
+
+	state.Ensure = model.EnsurePresent
+
+	section, found := parseSection(string(raw), properties.Name)
+	if !found {
+		return state, nil
+	}
+
+	state.Metadata.BaseURL = section["baseurl"]
+	state.Metadata.Description = section["name"]
+	state.Metadata.GPGKey = section["gpgkey"]
+	state.Metadata.Enabled = true
+	if enabled, ok := section["enabled"]; ok {
+		state.Metadata.Enabled = parseBool(enabled)
+	}
+
+	return state, nil
+}
+
+// Set writes the file holding the repository, the file is replaced atomically so the package manager never reads a
+// partial repository
+func (p *Provider) Set(ctx context.Context, properties *model.YumRepoResourceProperties) error {
+	file := p.path(properties)
+
+	// dnf only reads files ending in .repo so the temporary file is never loaded
+	tf, err := os.CreateTemp(p.dir, fmt.Sprintf(".%s.*", filepath.Base(file)))
+	if err != nil {
+		return err
+	}
+	defer tf.Close()
+	defer os.Remove(tf.Name())
+
+	_, err = tf.Write(properties.FileContent())
+	if err != nil {
+		return err
+	}
+
+	err = tf.Chmod(0644)
+	if err != nil {
+		return fmt.Errorf("could not set mode on temporary file: %w", err)
+	}
+
+	err = tf.Sync()
+	if err != nil {
+		return fmt.Errorf("could not sync temporary file: %w", err)
+	}
+
+	err = tf.Close()
+	if err != nil {
+		return fmt.Errorf("could not close temporary file: %w", err)
+	}
+
+	err = os.Rename(tf.Name(), file)
+	if err != nil {
+		return fmt.Errorf("could not rename temporary file: %w", err)
+	}
+
+	p.log.Debug("Wrote repository file", "file", file)
+
+	return nil
+}
+
+// Remove deletes the file holding the repository, a missing file is not an error
+func (p *Provider) Remove(ctx context.Context, properties *model.YumRepoResourceProperties) error {
+	file := p.path(properties)
+
+	err := os.Remove(file)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	p.log.Debug("Removed repository file", "file", file)
+
+	return nil
+}
+
+// parseSection parses the ini style repository file and returns the keys of the section named id
+func parseSection(content string, id string) (map[string]string, bool) {
+	var (
+		section map[string]string
+		current string
+	)
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			current = strings.TrimSpace(line[1 : len(line)-1])
+			if current == id && section == nil {
+				section = map[string]string{}
+			}
+			continue
+		}
+
+		if current != id || section == nil {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+
+		section[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+	}
+
+	return section, section != nil
+}
+
+// parseBool parses boolean values as accepted in repository files
+func parseBool(v string) bool {
+	switch strings.ToLower(v) {
+	case "1", "yes", "true", "on":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package reposd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/model/modelmocks"
+)
+
+func TestReposDProvider(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Resources/YumRepo/ReposD")
+}
+
+var _ = Describe("ReposD Provider", func() {
+	var (
+		mockctl  *gomock.Controller
+		logger   *modelmocks.MockLogger
+		provider *Provider
+		tmpDir   string
+		err      error
+	)
+
+	BeforeEach(func() {
+		mockctl = gomock.NewController(GinkgoT())
+		logger = modelmocks.NewMockLogger(mockctl)
+		logger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+
+		tmpDir = GinkgoT().TempDir()
+
+		provider, err = NewReposDProvider(logger, tmpDir)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	props := func() *model.YumRepoResourceProperties {
+		return &model.YumRepoResourceProperties{
+			CommonResourceProperties: model.CommonResourceProperties{Name: "epel", Ensure: model.EnsurePresent},
+			BaseURL:                  "https://mirror.example.net/epel/$releasever/$basearch/",
+			Description:              "EPEL $releasever",
+			GPGKey:                   "https://mirror.example.net/RPM-GPG-KEY-EPEL",
+		}
+	}
+
+	Describe("Status", func() {
+		It("Should handle missing files", func(ctx context.Context) {
+			state, err := provider.Status(ctx, props())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Ensure).To(Equal(model.EnsureAbsent))
+			Expect(state.Metadata.File).To(Equal(filepath.Join(tmpDir, "epel.repo")))
+		})
+
+		It("Should parse the section of the repository", func(ctx context.Context) {
+			Expect(os.WriteFile(filepath.Join(tmpDir, "epel.repo"), []byte(`[epel-debug]
+name=EPEL debug
+baseurl=https://mirror.example.net/debug/
+
+[epel]
+name = EPEL 9
+baseurl = https://mirror.example.net/epel/9/
+# enabled=1
+enabled = 0
+gpgcheck=1
+gpgkey=https://mirror.example.net/RPM-GPG-KEY-EPEL
+`), 0644)).To(Succeed())
+
+			state, err := provider.Status(ctx, props())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Ensure).To(Equal(model.EnsurePresent))
+			Expect(state.Metadata.Description).To(Equal("EPEL 9"))
+			Expect(state.Metadata.BaseURL).To(Equal("https://mirror.example.net/epel/9/"))
+			Expect(state.Metadata.Enabled).To(BeFalse())
+			Expect(state.Metadata.GPGKey).To(Equal("https://mirror.example.net/RPM-GPG-KEY-EPEL"))
+		})
+
+		It("Should default to enabled", func(ctx context.Context) {
+			Expect(os.WriteFile(filepath.Join(tmpDir, "epel.repo"), []byte("[epel]\nbaseurl=https://mirror.example.net/\n"), 0644)).To(Succeed())
+
+			state, err := provider.Status(ctx, props())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Metadata.Enabled).To(BeTrue())
+		})
+
+		It("Should report files without the section as present with no fields", func(ctx context.Context) {
+			Expect(os.WriteFile(filepath.Join(tmpDir, "epel.repo"), []byte("[other]\nbaseurl=https://mirror.example.net/\n"), 0644)).To(Succeed())
+
+			state, err := provider.Status(ctx, props())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Ensure).To(Equal(model.EnsurePresent))
+			Expect(state.Metadata.BaseURL).To(BeEmpty())
+		})
+	})
+
+	Describe("Set", func() {
+		It("Should write the repository file", func(ctx context.Context) {
+			Expect(provider.Set(ctx, props())).To(Succeed())
+
+			file := filepath.Join(tmpDir, "epel.repo")
+			content, err := os.ReadFile(file)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal(model.YumRepoFileHeader + `
+[epel]
+name=EPEL $releasever
+baseurl=https://mirror.example.net/epel/$releasever/$basearch/
+enabled=1
+gpgkey=https://mirror.example.net/RPM-GPG-KEY-EPEL
+`))
+
+			stat, err := os.Stat(file)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stat.Mode().Perm()).To(Equal(os.FileMode(0644)))
+
+			entries, err := os.ReadDir(tmpDir)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+		})
+
+		It("Should be idempotent", func(ctx context.Context) {
+			p := props()
+			enabled := false
+			p.Enabled = &enabled
+
+			Expect(provider.Set(ctx, p)).To(Succeed())
+
+			state, err := provider.Status(ctx, p)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Ensure).To(Equal(model.EnsurePresent))
+			Expect(state.Metadata.BaseURL).To(Equal(p.BaseURL))
+			Expect(state.Metadata.Description).To(Equal(p.RepoDescription()))
+			Expect(state.Metadata.Enabled).To(BeFalse())
+			Expect(state.Metadata.GPGKey).To(Equal(p.GPGKey))
+
+			first, err := os.ReadFile(state.Metadata.File)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(provider.Set(ctx, p)).To(Succeed())
+			second, err := os.ReadFile(state.Metadata.File)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(second).To(Equal(first))
+		})
+	})
+
+	Describe("Remove", func() {
+		It("Should remove the file", func(ctx context.Context) {
+			Expect(provider.Set(ctx, props())).To(Succeed())
+			Expect(provider.Remove(ctx, props())).To(Succeed())
+
+			state, err := provider.Status(ctx, props())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.Ensure).To(Equal(model.EnsureAbsent))
+		})
+
+		It("Should handle missing files", func(ctx context.Context) {
+			Expect(provider.Remove(ctx, props())).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,24 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package yumreporesource
+
+import (
+	"context"
+
+	"github.com/choria-io/ccm/model"
+	"github.com/choria-io/ccm/resources/yumrepo/reposd"
+)
+
+func init() {
+	reposd.Register()
+}
+
+type YumRepoProvider interface {
+	model.Provider
+
+	Status(ctx context.Context, properties *model.YumRepoResourceProperties) (*model.YumRepoState, error)
+	Set(ctx context.Context, properties *model.YumRepoResourceProperties) error
+	Remove(ctx context.Context, properties *model.YumRepoResourceProperties) error
+}
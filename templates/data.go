@@ -0,0 +1,119 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package templates
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// dataReferenceRegex matches data references in expressions, the key is captured from data.key,
+	// data["key"] and lookup paths like "data.key.child"
+	dataReferenceRegex = regexp.MustCompile(`\b[Dd]ata\b(?:\.([A-Za-z0-9_-]+)|\[\s*["'\x60]([^"'\x60]+)["'\x60]\s*\])?`)
+)
+
+// referencedDataKeys finds the top level data keys referenced in query, all is true when data is referenced in
+// a way that does not identify a key, like passing data to a function or using a wildcard lookup path
+func referencedDataKeys(query string) (keys []string, all bool) {
+	for _, m := range dataReferenceRegex.FindAllStringSubmatchIndex(query, -1) {
+		// facts.data or similar refers to something other than the data section
+		if m[0] > 0 && query[m[0]-1] == '.' {
+			continue
+		}
+
+		var key string
+		switch {
+		case m[2] >= 0:
+			key = query[m[2]:m[3]]
+		case m[4] >= 0:
+			key = query[m[4]:m[5]]
+		default:
+			return nil, true
+		}
+
+		// a wildcard or a path continuing with a pattern may match many keys
+		if m[1] < len(query) && strings.ContainsAny(query[m[1]:m[1]+1], "*?#|@") {
+			return nil, true
+		}
+
+		keys = append(keys, key)
+
+		// in expressions data.a-b is a subtraction rather than the key a-b so both are loaded
+		if ident, _, ok := strings.Cut(key, "-"); ok && m[2] >= 0 && ident != "" {
+			keys = append(keys, ident)
+		}
+	}
+
+	return keys, false
+}
+
+// LoadAllData loads all data using DataFunc, used before the data is accessed directly rather than through expressions
+func (e *Env) LoadAllData() error {
+	return e.loadData(nil, true)
+}
+
+// loadReferencedData loads the data keys referenced in query using DataFunc
+func (e *Env) loadReferencedData(query string) error {
+	if e.DataFunc == nil {
+		return nil
+	}
+
+	keys, all := referencedDataKeys(query)
+
+	return e.loadData(keys, all)
+}
+
+// loadData loads keys, or all data, using DataFunc into Data, keys already loaded are not loaded again
+func (e *Env) loadData(keys []string, all bool) error {
+	if e.DataFunc == nil {
+		return nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.dataLoadedAll {
+		return nil
+	}
+
+	var missing []string
+	if !all {
+		for _, key := range keys {
+			if !e.dataLoaded[key] {
+				missing = append(missing, key)
+			}
+		}
+
+		if len(missing) == 0 {
+			return nil
+		}
+	}
+
+	loaded, err := e.DataFunc(missing...)
+	if err != nil {
+		return err
+	}
+
+	if e.Data == nil {
+		e.Data = map[string]any{}
+	}
+	if e.dataLoaded == nil {
+		e.dataLoaded = map[string]bool{}
+	}
+
+	for k, v := range loaded {
+		e.Data[k] = v
+	}
+	for _, key := range missing {
+		e.dataLoaded[key] = true
+	}
+	e.dataLoadedAll = all
+
+	// the lookup cache is based on the data so has to be rebuilt
+	e.envJSON = nil
+
+	return nil
+}
@@ -18,10 +18,21 @@ func ExpandMapValues(value map[string]any, env *Env) (map[string]any, error) {
 }
 
 // ExpandValuesRecursively walks a data structure and replaces {{ expression }} placeholders in all string values.
-// Maps and slices are recursively processed, while other types are returned unchanged.
+// Maps and slices are recursively processed, while other types are returned unchanged. Encrypted strings are
+// decrypted using the environment DecryptFunc.
 func ExpandValuesRecursively(value any, env *Env) (any, error) {
 	switch typed := value.(type) {
 	case string:
+		if env.DecryptFunc != nil {
+			plain, decrypted, err := env.DecryptFunc(typed)
+			if err != nil {
+				return nil, err
+			}
+			if decrypted {
+				return plain, nil
+			}
+		}
+
 		return ResolveTemplateTyped(typed, env)
 	case map[string]any:
 		result := make(map[string]any, len(typed))
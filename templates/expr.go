@@ -11,6 +11,11 @@ import (
 )
 
 func ExprParse(query string, env *Env, opts ...expr.Option) (any, error) {
+	err := env.loadReferencedData(query)
+	if err != nil {
+		return nil, err
+	}
+
 	o := []expr.Option{
 		expr.Env(env),
 		expr.Function("lookup", env.lookup),
@@ -24,6 +29,7 @@ func ExprParse(query string, env *Env, opts ...expr.Option) (any, error) {
 			expr.Function("jet", env.jet),
 			expr.Function("registrations", env.registrations),
 			expr.Function("kvGet", env.kvGet),
+			expr.Function("secret", env.secret),
 		)
 	}
 
@@ -16,6 +16,7 @@ func (e *Env) GoFunctions() template.FuncMap {
 		"file":          e.goReadFile,
 		"registrations": e.registrations,
 		"kvGet":         e.kvGet,
+		"secret":        e.goSecret,
 		"jet":           e.goJet,
 	}
 }
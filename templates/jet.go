@@ -32,6 +32,7 @@ func (e *Env) JetFunctions() map[string]jet.Func {
 		"file":          e.jetReadFile(),
 		"registrations": e.jetRegistrations(),
 		"kvGet":         e.jetKVGet(),
+		"secret":        e.jetSecret(),
 		"template":      e.jetTemplate(),
 	}
 }
@@ -76,6 +77,11 @@ func (e *Env) jet(params ...any) (any, error) {
 		body = f.(string)
 	}
 
+	err := e.LoadAllData()
+	if err != nil {
+		return nil, err
+	}
+
 	set := jet.NewSet(jet.NewInMemLoader(), jet.WithDelims(lpat, rpat), jet.WithSafeWriter(func(w io.Writer, b []byte) {
 		w.Write(b)
 	}))
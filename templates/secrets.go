@@ -0,0 +1,93 @@
+// Copyright (c) 2026, R.I. Pienaar and the Choria Project contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package templates
+
+import (
+	"fmt"
+	"reflect"
+	"sync/atomic"
+
+	"github.com/CloudyKit/jet/v6"
+)
+
+// SecretProvider retrieves secrets for the secret() template function from an external store like Vault
+type SecretProvider interface {
+	// Secret returns the value stored at path, an error should be returned when the secret does not exist
+	Secret(path string) (string, error)
+}
+
+// secretUse records if secret() returned a value, shared by an environment and its copies
+type secretUse struct {
+	used atomic.Bool
+}
+
+// SecretsUsed reports if secret() returned a value while rendering templates in this environment or in any copy
+// made using WithDefaultData, values rendered from such environments should not be shown to users
+func (e *Env) SecretsUsed() bool {
+	return e.secretUse().used.Load()
+}
+
+func (e *Env) secretUse() *secretUse {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.secrets == nil {
+		e.secrets = &secretUse{}
+	}
+
+	return e.secrets
+}
+
+func (e *Env) secret(params ...any) (any, error) {
+	if len(params) != 1 {
+		return nil, fmt.Errorf("secret requires 1 string argument: path")
+	}
+
+	path, ok := params[0].(string)
+	if !ok || path == "" {
+		return nil, fmt.Errorf("secret requires a non empty string argument")
+	}
+
+	// never render an empty value in place of a secret that could not be looked up
+	if e.SecretProvider == nil {
+		return nil, fmt.Errorf("secret function not available: no secret provider configured")
+	}
+
+	val, err := e.SecretProvider.Secret(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not look up secret %q: %w", path, err)
+	}
+
+	e.secretUse().used.Store(true)
+
+	return val, nil
+}
+
+func (e *Env) goSecret(path string) (string, error) {
+	res, err := e.secret(path)
+	if err != nil {
+		return "", err
+	}
+
+	return res.(string), nil
+}
+
+func (e *Env) jetSecret() jet.Func {
+	return func(a jet.Arguments) reflect.Value {
+		a.RequireNumOfArguments("secret", 1, 1)
+
+		var path string
+		if err := a.ParseInto(&path); err != nil {
+			a.Panicf("secret: argument must be a string: %v", err)
+		}
+
+		val, err := e.secret(path)
+		if err != nil {
+			a.Panicf("secret: %v", err)
+		}
+
+		return reflect.ValueOf(val)
+	}
+}
@@ -9,11 +9,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 
 	"github.com/tidwall/gjson"
+
+	iu "github.com/choria-io/ccm/internal/util"
 )
 
 // templateMatch represents a found template expression with its position in the source string
@@ -199,6 +203,9 @@ type Env struct {
 	// KVGetFunc retrieves the value of a key from a NATS KV bucket, requires a NATS context to be configured
 	KVGetFunc func(bucket, key string) (string, error) `json:"-" yaml:"-"`
 
+	// SecretProvider retrieves secrets for the secret() function, secret() fails when it is not set
+	SecretProvider SecretProvider `json:"-" yaml:"-"`
+
 	// DefaultOnMissing when true causes lookup() to return "" instead of an error
 	// when a key is missing and no default is provided
 	DefaultOnMissing bool `json:"-" yaml:"-"`
@@ -207,8 +214,67 @@ type Env struct {
 	// excluding file I/O and template functions
 	RestrictFunctions bool `json:"-" yaml:"-"`
 
-	envJSON json.RawMessage
-	mu      sync.Mutex
+	// DataFunc loads data on demand, when set the data keys referenced by an expression are loaded into Data
+	// before it is evaluated. Called without keys it should return all data.
+	DataFunc func(keys ...string) (map[string]any, error) `json:"-" yaml:"-"`
+
+	// Hierarchy is the data layers of a hiera hierarchy lowest priority first, when set lookup() finds data
+	// keys in every layer and combines them using its merge option
+	Hierarchy []map[string]any `json:"-" yaml:"-"`
+
+	// DecryptFunc decrypts encrypted string values before placeholders are expanded, it reports if the value
+	// was decrypted and decrypted values are used as is
+	DecryptFunc func(value string) (string, bool, error) `json:"-" yaml:"-"`
+
+	envJSON       json.RawMessage
+	hierarchyJSON []json.RawMessage
+	dataLoaded    map[string]bool
+	dataLoadedAll bool
+	secrets       *secretUse
+	mu            sync.Mutex
+}
+
+// WithDefaultData returns a copy of the environment where Data is merged over defaults,
+// values in Data always take precedence over the defaults
+func (e *Env) WithDefaultData(defaults map[string]any) *Env {
+	var dataFunc func(keys ...string) (map[string]any, error)
+	if e.DataFunc != nil {
+		dataFunc = func(keys ...string) (map[string]any, error) {
+			data, err := e.DataFunc(keys...)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(keys) == 0 {
+				return iu.MergeDefaults(defaults, data), nil
+			}
+
+			keyDefaults := map[string]any{}
+			for _, key := range keys {
+				if v, ok := defaults[key]; ok {
+					keyDefaults[key] = v
+				}
+			}
+
+			return iu.MergeDefaults(keyDefaults, data), nil
+		}
+	}
+
+	return &Env{
+		Facts:             e.Facts,
+		Data:              iu.MergeDefaults(defaults, e.Data),
+		Environ:           e.Environ,
+		WorkingDir:        e.WorkingDir,
+		RegistrationsFunc: e.RegistrationsFunc,
+		KVGetFunc:         e.KVGetFunc,
+		SecretProvider:    e.SecretProvider,
+		DefaultOnMissing:  e.DefaultOnMissing,
+		RestrictFunctions: e.RestrictFunctions,
+		DataFunc:          dataFunc,
+		Hierarchy:         e.Hierarchy,
+		DecryptFunc:       e.DecryptFunc,
+		secrets:           e.secretUse(),
+	}
 }
 
 func (e *Env) readFile(params ...any) (any, error) {
@@ -308,7 +374,7 @@ func (e *Env) kvGet(params ...any) (any, error) {
 
 func (e *Env) lookup(params ...any) (any, error) {
 	var key string
-	var defaultValue any
+	var opts lookupOptions
 	var ok bool
 
 	if len(params) == 0 || len(params) > 2 {
@@ -321,42 +387,183 @@ func (e *Env) lookup(params ...any) (any, error) {
 	}
 
 	if len(params) == 2 {
-		defaultValue = params[1]
-	} else {
-		defaultValue = nil
+		var err error
+		opts, err = parseLookupOptions(params[1])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := e.loadReferencedData(strconv.Quote(key))
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := e.lookupValues(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(values) == 0 {
+		if opts.defaultValue == nil {
+			if e.DefaultOnMissing {
+				return "", nil
+			}
+			return "", fmt.Errorf("missing key '%s' in environment", key)
+		}
+		return opts.defaultValue, nil
+	}
+
+	return mergeLookupValues(values, opts.merge), nil
+}
+
+// lookupOptions are the options lookup() accepts as its second argument
+type lookupOptions struct {
+	defaultValue any
+	merge        string
+}
+
+// parseLookupOptions parses the second argument to lookup(), a map with only default and merge keys holds
+// options while any other value is the default value
+func parseLookupOptions(param any) (lookupOptions, error) {
+	opts, ok := param.(map[string]any)
+	if !ok || len(opts) == 0 {
+		return lookupOptions{defaultValue: param}, nil
+	}
+
+	for k := range opts {
+		if k != "default" && k != "merge" {
+			return lookupOptions{defaultValue: param}, nil
+		}
+	}
+
+	res := lookupOptions{defaultValue: opts["default"]}
+
+	merge, ok := opts["merge"]
+	if ok {
+		res.merge, ok = merge.(string)
+		if !ok {
+			return res, fmt.Errorf("lookup merge option must be a string")
+		}
 	}
 
+	switch res.merge {
+	case "", "first", "unique", "deep":
+	default:
+		return res, fmt.Errorf("unsupported lookup merge mode: %s", res.merge)
+	}
+
+	return res, nil
+}
+
+// lookupValues finds the values of key, data keys are looked up in every hierarchy layer when a hierarchy is set
+// with values returned lowest priority first, other keys and data keys absent from the hierarchy are looked up
+// in the environment
+func (e *Env) lookupValues(key string) ([]any, error) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
+	var values []any
+
+	if dataKey, ok := strings.CutPrefix(key, "data."); ok && len(e.Hierarchy) > 0 {
+		if e.hierarchyJSON == nil {
+			for _, layer := range e.Hierarchy {
+				j, err := json.Marshal(layer)
+				if err != nil {
+					return nil, err
+				}
+				e.hierarchyJSON = append(e.hierarchyJSON, j)
+			}
+		}
+
+		for _, layer := range e.hierarchyJSON {
+			res := gjson.GetBytes(layer, dataKey)
+			if res.Exists() {
+				values = append(values, lookupResultValue(res))
+			}
+		}
+
+		if len(values) > 0 {
+			return values, nil
+		}
+	}
+
 	if e.envJSON == nil {
 		j, err := json.Marshal(e)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 		e.envJSON = j
 	}
 
 	res := gjson.GetBytes(e.envJSON, key)
-	if !res.Exists() {
-		if defaultValue == nil {
-			if e.DefaultOnMissing {
-				return "", nil
-			}
-			return "", fmt.Errorf("missing key '%s' in environment", key)
-		}
-		return defaultValue, nil
+	if res.Exists() {
+		values = append(values, lookupResultValue(res))
 	}
 
+	return values, nil
+}
+
+// lookupResultValue converts a lookup result to a typed value keeping integers as integers
+func lookupResultValue(res gjson.Result) any {
 	if res.Type == gjson.Number {
 		if strings.Contains(res.Raw, ".") {
-			return res.Float(), nil
+			return res.Float()
 		}
 
-		return res.Int(), nil
+		return res.Int()
 	}
 
-	return res.Value(), nil
+	return res.Value()
+}
+
+// mergeLookupValues combines the values found by lookup, lowest priority first. The first mode picks the highest
+// priority value, unique combines all values into a list without duplicates and deep merges values like the
+// deep hierarchy merge mode
+func mergeLookupValues(values []any, merge string) any {
+	switch merge {
+	case "unique":
+		result := []any{}
+		for _, value := range values {
+			items, ok := value.([]any)
+			if !ok {
+				items = []any{value}
+			}
+
+			for _, item := range items {
+				if !slices.ContainsFunc(result, func(v any) bool { return reflect.DeepEqual(v, item) }) {
+					result = append(result, item)
+				}
+			}
+		}
+
+		return result
+
+	case "deep":
+		result := values[0]
+		for _, value := range values[1:] {
+			existingMap, isMap := result.(map[string]any)
+			incomingMap, ok := value.(map[string]any)
+			if isMap && ok {
+				result = iu.DeepMergeMap(existingMap, incomingMap)
+				continue
+			}
+
+			existingSlice, isSlice := result.([]any)
+			incomingSlice, ok := value.([]any)
+			if isSlice && ok {
+				result = append(slices.Clone(existingSlice), incomingSlice...)
+				continue
+			}
+
+			result = value
+		}
+
+		return result
+
+	default:
+		return values[len(values)-1]
+	}
 }
 
 // ResolveTemplateStringMatch resolves {{ expression }} placeholders in a template string and returns the result,
@@ -231,6 +231,75 @@ var _ = Describe("Templates", func() {
 			Expect(err).ToNot(HaveOccurred())
 			Expect(result).To(Equal("second"))
 		})
+
+		It("Should return the default from the options for non-existent keys", func() {
+			result, err := ExprParse("lookup('data.nonexistent', {default: []})", env)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal([]any{}))
+		})
+
+		It("Should treat maps with other keys as the default value", func() {
+			result, err := ExprParse("lookup('data.nonexistent', {default: 1, other: 2})", env)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(map[string]any{"default": 1, "other": 2}))
+		})
+
+		It("Should reject unknown merge modes", func() {
+			_, err := ExprParse("lookup('data.port', {merge: 'bogus'})", env)
+			Expect(err).To(MatchError(ContainSubstring("unsupported lookup merge mode: bogus")))
+		})
+
+		It("Should dedupe a list without a hierarchy", func() {
+			env.Data["items"] = []any{"a", "b", "a"}
+
+			result, err := ExprParse("lookup('data.items', {merge: 'unique'})", env)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal([]any{"a", "b"}))
+		})
+
+		Describe("with a hierarchy", func() {
+			BeforeEach(func() {
+				env.Hierarchy = []map[string]any{
+					{"packages": []any{"zsh", "vim"}, "web": map[string]any{"port": 80, "tls": false}},
+					{"packages": []any{"vim", "nginx"}, "web": map[string]any{"tls": true}},
+					{"packages": "nginx"},
+				}
+			})
+
+			It("Should return the highest priority value by default", func() {
+				result, err := ExprParse("lookup('data.packages')", env)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal("nginx"))
+
+				result, err = ExprParse("lookup('data.web', {merge: 'first'})", env)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(map[string]any{"tls": true}))
+			})
+
+			It("Should combine unique values across the hierarchy", func() {
+				result, err := ExprParse("lookup('data.packages', {default: [], merge: 'unique'})", env)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal([]any{"zsh", "vim", "nginx"}))
+			})
+
+			It("Should deep merge values across the hierarchy", func() {
+				result, err := ExprParse("lookup('data.web', {merge: 'deep'})", env)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(map[string]any{"port": float64(80), "tls": true}))
+			})
+
+			It("Should fall back to the environment for keys not in the hierarchy", func() {
+				result, err := ExprParse("lookup('data.port', {merge: 'unique'})", env)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal([]any{int64(8080)}))
+			})
+
+			It("Should return the default when no layer has the key", func() {
+				result, err := ExprParse("lookup('data.nonexistent', {default: ['x'], merge: 'unique'})", env)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal([]any{"x"}))
+			})
+		})
 	})
 
 	Describe("kvGet function", func() {
@@ -302,6 +371,84 @@ var _ = Describe("Templates", func() {
 		})
 	})
 
+	Describe("secret function", func() {
+		BeforeEach(func() {
+			env.SecretProvider = memorySecrets{"db/password": "s3cr3t"}
+		})
+
+		It("Should return the secret at a path", func() {
+			result, err := ResolveTemplateString("password={{ secret('db/password') }}", env)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal("password=s3cr3t"))
+		})
+
+		It("Should be available inside jet templates", func() {
+			result, err := ResolveTemplateString(`{{ jet('[[ secret("db/password") ]]') }}`, env)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal("s3cr3t"))
+		})
+
+		It("Should be available inside go templates", func() {
+			tmpl, err := template.New("test").Funcs(env.GoFunctions()).Parse(`{{ secret "db/password" }}`)
+			Expect(err).ToNot(HaveOccurred())
+
+			var buf bytes.Buffer
+			err = tmpl.Execute(&buf, env)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(buf.String()).To(Equal("s3cr3t"))
+		})
+
+		It("Should be kept when adding default data", func() {
+			result, err := ResolveTemplateString("{{ secret('db/password') }}", env.WithDefaultData(map[string]any{"x": 1}))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal("s3cr3t"))
+		})
+
+		It("Should record that secrets were used", func() {
+			_, err := ResolveTemplateString("{{ Facts.os }}", env)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(env.SecretsUsed()).To(BeFalse())
+
+			_, err = ResolveTemplateString("{{ secret('db/missing') }}", env)
+			Expect(err).To(HaveOccurred())
+			Expect(env.SecretsUsed()).To(BeFalse())
+
+			defaulted := env.WithDefaultData(map[string]any{"x": 1})
+			_, err = ResolveTemplateString("{{ secret('db/password') }}", defaulted)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(defaulted.SecretsUsed()).To(BeTrue())
+			Expect(env.SecretsUsed()).To(BeTrue())
+		})
+
+		It("Should error rather than render empty when no provider is configured", func() {
+			env.SecretProvider = nil
+
+			result, err := ResolveTemplateString("password={{ secret('db/password') }}", env)
+			Expect(err).To(MatchError(ContainSubstring("no secret provider configured")))
+			Expect(result).To(Equal(""))
+		})
+
+		It("Should propagate errors from the provider", func() {
+			_, err := ResolveTemplateString("{{ secret('db/missing') }}", env)
+			Expect(err).To(MatchError(ContainSubstring(`could not look up secret "db/missing": secret not found`)))
+		})
+
+		It("Should error with wrong arguments", func() {
+			_, err := ExprParse("secret()", env)
+			Expect(err).To(MatchError(ContainSubstring("secret requires 1 string argument")))
+
+			_, err = ExprParse("secret('')", env)
+			Expect(err).To(MatchError(ContainSubstring("secret requires a non empty string argument")))
+		})
+
+		It("Should not be available with restricted functions", func() {
+			env.RestrictFunctions = true
+
+			_, err := ExprParse("secret('db/password')", env)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 	Describe("Thread safety", func() {
 		It("Should handle concurrent ResolveTemplateString calls", func() {
 			done := make(chan bool)
@@ -903,4 +1050,108 @@ var _ = Describe("Templates", func() {
 			Expect(m["os"]).To(Equal("linux"))
 		})
 	})
+
+	Describe("DataFunc", func() {
+		var (
+			data   map[string]any
+			loaded [][]string
+			lazy   *Env
+		)
+
+		BeforeEach(func() {
+			data = map[string]any{
+				"app_name": "myapp",
+				"web":      map[string]any{"port": 8080},
+				"user":     "app",
+			}
+			loaded = nil
+
+			lazy = &Env{
+				Facts: map[string]any{"data": map[string]any{"x": 1}},
+				DataFunc: func(keys ...string) (map[string]any, error) {
+					loaded = append(loaded, keys)
+					if len(keys) == 0 {
+						return data, nil
+					}
+
+					res := map[string]any{}
+					for _, key := range keys {
+						if v, ok := data[key]; ok {
+							res[key] = v
+						}
+					}
+
+					return res, nil
+				},
+			}
+		})
+
+		It("Should only load referenced keys", func() {
+			res, err := ResolveTemplateString("{{ Data.app_name }}:{{ lookup('data.web.port') }}:{{ Facts.data.x }}", lazy)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal("myapp:8080:1"))
+			Expect(loaded).To(Equal([][]string{{"app_name"}, {"web"}}))
+			Expect(lazy.Data).ToNot(HaveKey("user"))
+		})
+
+		It("Should load keys only once", func() {
+			for range 2 {
+				res, err := ResolveTemplateTyped(`{{ Data["web"].port }}`, lazy)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res).To(Equal(8080))
+			}
+			Expect(loaded).To(Equal([][]string{{"web"}}))
+		})
+
+		It("Should load all data for unidentified references", func() {
+			res, err := ResolveTemplateTyped("{{ len(Data) }}", lazy)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal(3))
+			Expect(loaded).To(Equal([][]string{nil}))
+
+			_, err = ResolveTemplateTyped("{{ Data.user }}", lazy)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(loaded).To(HaveLen(1))
+		})
+
+		It("Should honor defaults", func() {
+			withDefaults := lazy.WithDefaultData(map[string]any{"user": "nobody", "group": "staff"})
+
+			res, err := ResolveTemplateString("{{ Data.user }}:{{ Data.group }}", withDefaults)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal("app:staff"))
+		})
+
+		It("Should find referenced keys", func() {
+			for query, expected := range map[string][]string{
+				"Data.a + data.b":           {"a", "b"},
+				`Data["a-b"]`:               {"a-b"},
+				`lookup("data.a.b", 1)`:     {"a"},
+				"Data.a-1":                  {"a-1", "a"},
+				"Facts.data.a + Facts.user": nil,
+				"metadata.a":                nil,
+			} {
+				keys, all := referencedDataKeys(query)
+				Expect(all).To(BeFalse(), query)
+				Expect(keys).To(Equal(expected), query)
+			}
+
+			for _, query := range []string{"Data", "keys(data)", `lookup("data")`, `lookup("data.a*")`, `lookup("data.#")`} {
+				_, all := referencedDataKeys(query)
+				Expect(all).To(BeTrue(), query)
+			}
+		})
+	})
 })
+
+// memorySecrets is an in-memory secret provider for tests
+type memorySecrets map[string]string
+
+func (m memorySecrets) Secret(path string) (string, error) {
+	val, ok := m[path]
+	if !ok {
+		return "", fmt.Errorf("secret not found")
+	}
+
+	return val, nil
+}